@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/accounts"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/progress"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var accountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "Manage the client account registry",
+	Long: `Map friendly client names to a config profile and org ID in
+~/.asa-cli/accounts.yaml, then select a client on any command with --account:
+
+  asa-cli accounts add acme --profile agency --org-id 123456
+  asa-cli --account acme reports campaigns --start-date 2024-01-01 --end-date 2024-01-31`,
+}
+
+var accountsAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Register a client account",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAccountsAdd,
+}
+
+var accountsRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a client account",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAccountsRemove,
+}
+
+var accountsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List client accounts, optionally with their spend",
+	Long: `List registered client accounts. With --with-spend, fetch a campaign report
+for every account and show its spend, installs, and CPI over --range.
+Accounts that fail (e.g. expired credentials) are listed with the error.`,
+	RunE: runAccountsList,
+}
+
+var (
+	accountName       string
+	accountProfile    string
+	accountOrgID      string
+	accountsWithSpend bool
+	accountsRange     string
+)
+
+func init() {
+	accountsAddCmd.Flags().StringVar(&accountProfile, "profile", "", "Config profile holding the credentials (default profile if empty)")
+	accountsAddCmd.Flags().StringVar(&accountOrgID, "org-id", "", "Organization ID (required)")
+	accountsAddCmd.MarkFlagRequired("org-id")
+
+	accountsListCmd.Flags().BoolVar(&accountsWithSpend, "with-spend", false, "Fetch spend for every account")
+	accountsListCmd.Flags().StringVar(&accountsRange, "range", "mtd", "Date range for --with-spend: "+daterange.Help)
+
+	accountsCmd.AddCommand(accountsAddCmd, accountsRemoveCmd, accountsListCmd)
+	rootCmd.AddCommand(accountsCmd)
+}
+
+// applyAccount points the profile and org ID at the --account client, unless
+// --profile or --org-id were given explicitly.
+func applyAccount(cmd *cobra.Command) error {
+	reg, err := accounts.Load()
+	if err != nil {
+		return err
+	}
+	a, err := reg.Get(accountName)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("profile") {
+		profileName = a.Profile
+	}
+	if !cmd.Flags().Changed("org-id") {
+		globalOrgID = a.OrgID
+	}
+	return nil
+}
+
+func runAccountsAdd(cmd *cobra.Command, args []string) error {
+	reg, err := accounts.Load()
+	if err != nil {
+		return err
+	}
+	if err := reg.Set(accounts.Account{Name: args[0], Profile: accountProfile, OrgID: accountOrgID}); err != nil {
+		return err
+	}
+	fmt.Printf("Account '%s' registered. Use it with: asa-cli --account %s <command>\n", args[0], args[0])
+	return nil
+}
+
+func runAccountsRemove(cmd *cobra.Command, args []string) error {
+	reg, err := accounts.Load()
+	if err != nil {
+		return err
+	}
+	if _, err := reg.Get(args[0]); err != nil {
+		return err
+	}
+	if err := reg.Remove(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("Account '%s' removed.\n", args[0])
+	return nil
+}
+
+type accountRow struct {
+	Name     string             `json:"name"`
+	Profile  string             `json:"profile"`
+	OrgID    string             `json:"orgId"`
+	Totals   *aggregate.Metrics `json:"totals,omitempty"`
+	Error    string             `json:"error,omitempty"`
+	Spend    string             `json:"-"`
+	Installs string             `json:"-"`
+	CPI      string             `json:"-"`
+}
+
+func runAccountsList(cmd *cobra.Command, args []string) error {
+	reg, err := accounts.Load()
+	if err != nil {
+		return err
+	}
+
+	var rng daterange.Range
+	if accountsWithSpend {
+		if rng, err = daterange.Parse(accountsRange, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	list := reg.List()
+	rows := make([]accountRow, 0, len(list))
+	bar := progress.New("Fetching spend", len(list))
+	for _, a := range list {
+		row := accountRow{Name: a.Name, Profile: a.Profile, OrgID: a.OrgID}
+		if row.Profile == "" {
+			row.Profile = "default"
+		}
+		if accountsWithSpend {
+			if m, err := accountSpend(a, rng); err != nil {
+				row.Error, _, _ = strings.Cut(err.Error(), "\n")
+			} else {
+				row.Totals = m
+				row.Spend = fmt.Sprintf("%.2f %s", m.Spend, m.Currency)
+				row.Installs = fmt.Sprint(m.Installs)
+				row.CPI = fmt.Sprintf("%.2f", m.CPI())
+			}
+			bar.Add(1)
+		}
+		rows = append(rows, row)
+	}
+	bar.Done()
+
+	columns := []output.Column{
+		{Header: "NAME", Field: "Name"},
+		{Header: "PROFILE", Field: "Profile"},
+		{Header: "ORG ID", Field: "OrgID"},
+	}
+	if accountsWithSpend {
+		columns = append(columns,
+			output.Column{Header: "SPEND", Field: "Spend"},
+			output.Column{Header: "INSTALLS", Field: "Installs"},
+			output.Column{Header: "CPI", Field: "CPI"},
+			output.Column{Header: "ERROR", Field: "Error"},
+		)
+	}
+	output.Print(getFormat(), rows, columns)
+	return nil
+}
+
+// accountSpend totals an account's campaign report over rng, temporarily
+// switching the active profile and org.
+func accountSpend(a accounts.Account, rng daterange.Range) (*aggregate.Metrics, error) {
+	prevProfile, prevOrg := profileName, globalOrgID
+	profileName, globalOrgID = a.Profile, a.OrgID
+	config.SetProfile(profileName)
+	defer func() {
+		profileName, globalOrgID = prevProfile, prevOrg
+		config.SetProfile(profileName)
+	}()
+
+	client, err := newAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := services.NewReportingService(client).GetCampaignReport(newRangeReportRequest(rng, 1000))
+	if err != nil {
+		return nil, fmt.Errorf("getting campaign report: %w", err)
+	}
+	m := aggregate.Total(aggregate.ByEntity(resp, "campaignId", "campaignName"))
+	return &m, nil
+}
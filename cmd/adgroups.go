@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
 	"github.com/trebuhs/asa-cli/internal/models"
 	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/refs"
 	"github.com/trebuhs/asa-cli/internal/services"
 )
 
@@ -47,6 +50,19 @@ var adgroupsUpdateCmd = &cobra.Command{
 	RunE:  runAdGroupsUpdate,
 }
 
+var adgroupsSetBiddingCmd = &cobra.Command{
+	Use:   "set-bidding <id>",
+	Short: "Show or change an ad group's default bid and CPA goal",
+	Long: `Show an ad group's bidding settings (default bid, CPA goal, pricing model) and
+optionally change the default bid and CPA goal. With no flags the current values
+are displayed. Bids are validated against min_bid and max_bid from config.
+
+The pricing model is fixed when the ad group is created (see adgroups create
+--pricing-model) and is shown for reference.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdGroupsSetBidding,
+}
+
 var adgroupsDeleteCmd = &cobra.Command{
 	Use:   "delete <id>",
 	Short: "Delete an ad group",
@@ -56,46 +72,46 @@ var adgroupsDeleteCmd = &cobra.Command{
 
 var (
 	agCampaignID int64
-	agLimit      int
-	agOffset     int
+	agPage       pager
 	agFilters    []string
 	agSorts      []string
 	agAll        bool
 	agName       string
 	agBid        string
 	agCpaGoal    string
-	agStatus     string
+	agStatus     models.Status
 	agAutoKW     string
 	agStartTime  string
 	agEndTime    string
+	agPricing    models.PricingModel
 )
 
 func init() {
 	// Common campaign-id flag
-	for _, cmd := range []*cobra.Command{adgroupsListCmd, adgroupsGetCmd, adgroupsFindCmd, adgroupsCreateCmd, adgroupsUpdateCmd, adgroupsDeleteCmd} {
+	for _, cmd := range []*cobra.Command{adgroupsListCmd, adgroupsGetCmd, adgroupsFindCmd, adgroupsCreateCmd, adgroupsUpdateCmd, adgroupsSetBiddingCmd, adgroupsDeleteCmd} {
 		cmd.Flags().Int64Var(&agCampaignID, "campaign-id", 0, "Campaign ID (required)")
 		cmd.MarkFlagRequired("campaign-id")
 	}
 
 	// list
-	adgroupsListCmd.Flags().IntVar(&agLimit, "limit", 20, "Number of results")
-	adgroupsListCmd.Flags().IntVar(&agOffset, "offset", 0, "Results offset")
+	agPage.register(adgroupsListCmd)
 
 	// find
 	adgroupsFindCmd.Flags().StringSliceVar(&agFilters, "filter", nil, `Filter conditions`)
 	adgroupsFindCmd.Flags().StringSliceVar(&agSorts, "sort", nil, `Sort order`)
-	adgroupsFindCmd.Flags().IntVar(&agLimit, "limit", 20, "Number of results")
-	adgroupsFindCmd.Flags().IntVar(&agOffset, "offset", 0, "Results offset")
+	agPage.register(adgroupsFindCmd)
 	adgroupsFindCmd.Flags().BoolVar(&agAll, "all", false, "Fetch all pages")
 
 	// create
 	adgroupsCreateCmd.Flags().StringVar(&agName, "name", "", "Ad group name (required)")
 	adgroupsCreateCmd.Flags().StringVar(&agBid, "default-bid", "", "Default bid amount (e.g. 1.50)")
 	adgroupsCreateCmd.Flags().StringVar(&agCpaGoal, "cpa-goal", "", "CPA goal amount")
-	adgroupsCreateCmd.Flags().StringVar(&agStatus, "status", "ENABLED", "Status")
+	enumVar(adgroupsCreateCmd.Flags(), &agStatus, "status", models.StatusEnabled, models.ParseStatus, "Status (ENABLED/PAUSED)")
 	adgroupsCreateCmd.Flags().StringVar(&agAutoKW, "auto-keywords", "false", "Automated keywords opt-in (true/false)")
 	adgroupsCreateCmd.Flags().StringVar(&agStartTime, "start-time", "", "Start time (ISO 8601)")
 	adgroupsCreateCmd.Flags().StringVar(&agEndTime, "end-time", "", "End time (ISO 8601)")
+	enumVar(adgroupsCreateCmd.Flags(), &agPricing, "pricing-model", models.PricingCPC, models.ParsePricingModel, "Pricing model: CPC or CPM")
+	adgroupsCreateCmd.Flags().StringVar(&externalID, "external-id", "", externalIDUsage)
 	adgroupsCreateCmd.MarkFlagRequired("name")
 	adgroupsCreateCmd.MarkFlagRequired("default-bid")
 
@@ -103,12 +119,19 @@ func init() {
 	adgroupsUpdateCmd.Flags().StringVar(&agName, "name", "", "Ad group name")
 	adgroupsUpdateCmd.Flags().StringVar(&agBid, "default-bid", "", "Default bid amount")
 	adgroupsUpdateCmd.Flags().StringVar(&agCpaGoal, "cpa-goal", "", "CPA goal amount")
-	adgroupsUpdateCmd.Flags().StringVar(&agStatus, "status", "", "Status (ENABLED/PAUSED)")
+	enumVar(adgroupsUpdateCmd.Flags(), &agStatus, "status", "", models.ParseStatus, "Status (ENABLED/PAUSED)")
 	adgroupsUpdateCmd.Flags().StringVar(&agAutoKW, "auto-keywords", "", "Automated keywords (true/false)")
 	adgroupsUpdateCmd.Flags().StringVar(&agStartTime, "start-time", "", "Start time")
 	adgroupsUpdateCmd.Flags().StringVar(&agEndTime, "end-time", "", "End time")
+	registerPatchFlags(adgroupsUpdateCmd)
+
+	// set-bidding
+	adgroupsSetBiddingCmd.Flags().StringVar(&agBid, "default-bid", "", "Default bid amount (e.g. 1.50)")
+	adgroupsSetBiddingCmd.Flags().StringVar(&agCpaGoal, "cpa-goal", "", "CPA goal amount (e.g. 3.00)")
 
-	adgroupsCmd.AddCommand(adgroupsListCmd, adgroupsGetCmd, adgroupsFindCmd, adgroupsCreateCmd, adgroupsUpdateCmd, adgroupsDeleteCmd)
+	addIgnoreMissingFlag(adgroupsDeleteCmd)
+
+	adgroupsCmd.AddCommand(adgroupsListCmd, adgroupsGetCmd, adgroupsFindCmd, adgroupsCreateCmd, adgroupsUpdateCmd, adgroupsSetBiddingCmd, adgroupsDeleteCmd)
 	rootCmd.AddCommand(adgroupsCmd)
 }
 
@@ -121,19 +144,31 @@ var adgroupColumns = []output.Column{
 	{Header: "CPA GOAL", Field: "CpaGoal", Width: 12},
 }
 
+var adgroupBiddingColumns = []output.Column{
+	{Header: "ID", Field: "ID", Width: 12},
+	{Header: "NAME", Field: "Name", Width: 25},
+	{Header: "PRICING", Field: "PricingModel", Width: 8},
+	{Header: "DEFAULT BID", Field: "DefaultBidAmount", Width: 15},
+	{Header: "CPA GOAL", Field: "CpaGoal", Width: 12},
+}
+
 func runAdGroupsList(cmd *cobra.Command, args []string) error {
 	client, err := newAPIClient()
 	if err != nil {
 		return err
 	}
 
+	limit, offset, err := agPage.resolve()
+	if err != nil {
+		return err
+	}
 	svc := services.NewAdGroupService(client)
-	adgroups, _, err := svc.List(agCampaignID, agLimit, agOffset)
+	adgroups, page, err := svc.List(agCampaignID, limit, offset)
 	if err != nil {
 		return fmt.Errorf("listing ad groups: %w", err)
 	}
 
-	output.Print(getFormat(), adgroups, adgroupColumns)
+	agPage.print(adgroups, page, adgroupColumns)
 	return nil
 }
 
@@ -164,7 +199,10 @@ func runAdGroupsFind(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	selector := models.NewSelector(agLimit, agOffset)
+	selector, err := agPage.selector()
+	if err != nil {
+		return err
+	}
 	selector.Conditions = parseFilters(agFilters)
 	selector.OrderBy = parseSorts(agSorts)
 
@@ -175,13 +213,13 @@ func runAdGroupsFind(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("finding ad groups: %w", err)
 		}
-		output.Print(getFormat(), adgroups, adgroupColumns)
+		agPage.print(adgroups, nil, adgroupColumns)
 	} else {
-		adgroups, _, err := svc.Find(agCampaignID, selector)
+		adgroups, page, err := svc.Find(agCampaignID, selector)
 		if err != nil {
 			return fmt.Errorf("finding ad groups: %w", err)
 		}
-		output.Print(getFormat(), adgroups, adgroupColumns)
+		agPage.print(adgroups, page, adgroupColumns)
 	}
 	return nil
 }
@@ -200,6 +238,12 @@ func runAdGroupsCreate(cmd *cobra.Command, args []string) error {
 	if err := checkBidLimit(agBid); err != nil {
 		return err
 	}
+	if err := checkMinBid(agBid); err != nil {
+		return err
+	}
+	if err := checkAdGroupName(agName); err != nil {
+		return err
+	}
 
 	autoKW := agAutoKW == "true"
 	adgroup := &models.AdGroup{
@@ -207,7 +251,7 @@ func runAdGroupsCreate(cmd *cobra.Command, args []string) error {
 		Status:                 agStatus,
 		DefaultBidAmount:       &models.Money{Amount: agBid, Currency: currency},
 		AutomatedKeywordsOptIn: autoKW,
-		PricingModel:           "CPC",
+		PricingModel:           agPricing,
 	}
 
 	if agCpaGoal != "" {
@@ -221,10 +265,47 @@ func runAdGroupsCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewAdGroupService(client)
+
+	store, ref, err := findExternalRef(refs.KindAdGroup)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		_, err := svc.Get(ref.CampaignID, ref.ID)
+		switch {
+		case err == nil:
+			fmt.Fprintf(os.Stderr, "External ID %s is ad group %d; updating it instead of creating a new one.\n", externalID, ref.ID)
+			update := &models.AdGroupUpdate{
+				Name:             agName,
+				DefaultBidAmount: adgroup.DefaultBidAmount,
+				CpaGoal:          adgroup.CpaGoal,
+				EndTime:          adgroup.EndTime,
+			}
+			if cmd.Flags().Changed("status") {
+				update.Status = agStatus
+			}
+			updated, err := svc.Update(ref.CampaignID, ref.ID, update)
+			if err != nil {
+				return fmt.Errorf("updating ad group: %w", err)
+			}
+			output.Print(getFormat(), updated, adgroupColumns)
+			return nil
+		case api.IsNotFound(err):
+			if err := forgetExternalRef(store, refs.KindAdGroup); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("getting ad group %d for external ID %s: %w", ref.ID, externalID, err)
+		}
+	}
+
 	created, err := svc.Create(agCampaignID, adgroup)
 	if err != nil {
 		return fmt.Errorf("creating ad group: %w", err)
 	}
+	if err := recordExternalRef(store, refs.Ref{Kind: refs.KindAdGroup, ID: created.ID, CampaignID: agCampaignID}); err != nil {
+		return err
+	}
 
 	output.Print(getFormat(), created, adgroupColumns)
 	return nil
@@ -235,6 +316,9 @@ func runAdGroupsUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("invalid ad group ID: %s", args[0])
 	}
+	if err := checkPatchFlags(cmd, "name", "default-bid", "cpa-goal", "status", "auto-keywords", "start-time", "end-time"); err != nil {
+		return err
+	}
 
 	client, err := newAPIClient()
 	if err != nil {
@@ -244,7 +328,17 @@ func runAdGroupsUpdate(cmd *cobra.Command, args []string) error {
 	update := &models.AdGroupUpdate{}
 	hasUpdate := false
 
+	if cmd.Flags().Changed("file") {
+		if update, err = patchAdGroup(client, agCampaignID, id); err != nil {
+			return err
+		}
+		hasUpdate = true
+	}
+
 	if cmd.Flags().Changed("name") {
+		if err := checkAdGroupName(agName); err != nil {
+			return err
+		}
 		update.Name = agName
 		hasUpdate = true
 	}
@@ -284,19 +378,104 @@ func runAdGroupsUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	if !hasUpdate {
-		return fmt.Errorf("no update flags provided")
+		return fmt.Errorf("no update flags provided (or pass --file)")
 	}
 
 	svc := services.NewAdGroupService(client)
-	updated, err := svc.Update(agCampaignID, id, update)
+	updated, changes, err := svc.UpdateIfChanged(agCampaignID, id, update)
 	if err != nil {
 		return fmt.Errorf("updating ad group: %w", err)
 	}
+	if len(changes) == 0 {
+		fmt.Fprintf(os.Stderr, "Ad group %d already matches the update; skipped.\n", id)
+	}
+	printChanges(fmt.Sprintf("ad group %d", id), changes)
 
-	output.Print(getFormat(), updated, adgroupColumns)
+	output.Print(getFormat(), withChanges(updated, changes), adgroupColumns)
 	return nil
 }
 
+func runAdGroupsSetBidding(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ad group ID: %s", args[0])
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	svc := services.NewAdGroupService(client)
+	current, err := svc.Get(agCampaignID, id)
+	if err != nil {
+		return fmt.Errorf("getting ad group: %w", err)
+	}
+
+	bidChanged := cmd.Flags().Changed("default-bid")
+	cpaChanged := cmd.Flags().Changed("cpa-goal")
+	if !bidChanged && !cpaChanged {
+		output.Print(getFormat(), current, adgroupBiddingColumns)
+		return nil
+	}
+
+	update := &models.AdGroupUpdate{}
+	currency := ""
+	if current.DefaultBidAmount != nil {
+		currency = current.DefaultBidAmount.Currency
+	}
+	if currency == "" {
+		if currency, err = resolveOrgCurrency(client); err != nil {
+			return err
+		}
+	}
+
+	if bidChanged {
+		if err := checkMinBid(agBid); err != nil {
+			return err
+		}
+		if err := checkBidLimit(agBid); err != nil {
+			return err
+		}
+		update.DefaultBidAmount = &models.Money{Amount: agBid, Currency: currency}
+	}
+	if cpaChanged {
+		cpa, err := strconv.ParseFloat(agCpaGoal, 64)
+		if err != nil || cpa <= 0 {
+			return fmt.Errorf("invalid CPA goal: %s", agCpaGoal)
+		}
+		update.CpaGoal = &models.Money{Amount: agCpaGoal, Currency: currency}
+	}
+
+	if getFormat() == output.FormatTable {
+		fmt.Printf("Current: default bid %s, CPA goal %s, pricing model %s\n",
+			moneyString(current.DefaultBidAmount), moneyString(current.CpaGoal), current.PricingModel)
+	}
+
+	if update.Unchanged(current) {
+		fmt.Fprintf(os.Stderr, "Ad group %d already has this bidding; skipped.\n", id)
+		output.Print(getFormat(), current, adgroupBiddingColumns)
+		return nil
+	}
+	changes := models.Diff(current, update)
+	updated, err := svc.Update(agCampaignID, id, update)
+	if err != nil {
+		return fmt.Errorf("updating ad group bidding: %w", err)
+	}
+	printChanges(fmt.Sprintf("ad group %d", id), changes)
+
+	output.Print(getFormat(), withChanges(updated, changes), adgroupBiddingColumns)
+	return nil
+}
+
+// moneyString formats an optional Money value for display.
+func moneyString(m *models.Money) string {
+	if m == nil || m.Amount == "" {
+		return "(none)"
+	}
+	return m.Amount + " " + m.Currency
+}
+
 func runAdGroupsDelete(cmd *cobra.Command, args []string) error {
 	id, err := strconv.ParseInt(args[0], 10, 64)
 	if err != nil {
@@ -310,6 +489,10 @@ func runAdGroupsDelete(cmd *cobra.Command, args []string) error {
 
 	svc := services.NewAdGroupService(client)
 	if err := svc.Delete(agCampaignID, id); err != nil {
+		if ignoreMissing && services.IsMissing(err) {
+			fmt.Printf("Ad group %d not found; skipped (already deleted).\n", id)
+			return nil
+		}
 		return fmt.Errorf("deleting ad group: %w", err)
 	}
 
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/changeset"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var adgroupsAdjustBidsCmd = &cobra.Command{
+	Use:   "adjust-bids",
+	Short: "Scale the default bids of many ad groups at once",
+	Long: `Multiply the default bid of every ad group matching --filter, across all
+campaigns or within --campaign-id. New bids are rounded to --round-to (up, down,
+or to the nearest step), then kept within --min and --max.
+
+Without --apply the changes are only listed. Every new bid is checked against
+the configured bid limits before any change. Applied changes are recorded as
+a change set, with a rollback change set that restores the previous bids:
+
+  asa-cli changeset apply adjust-bids-20240601-120000-rollback
+
+Example:
+  asa-cli adgroups adjust-bids --filter status=ENABLED --multiply 1.10 --min 0.50 --max 5.00
+  asa-cli adgroups adjust-bids --campaign-id 123 --multiply 0.9 --round-to 0.05 --rounding down --apply`,
+	Args: cobra.NoArgs,
+	RunE: runAdGroupsAdjustBids,
+}
+
+var (
+	adjFilters  []string
+	adjMultiply float64
+	adjMin      float64
+	adjMax      float64
+	adjRoundTo  float64
+	adjRounding string
+	adjApply    bool
+)
+
+func init() {
+	adgroupsAdjustBidsCmd.Flags().Int64Var(&agCampaignID, "campaign-id", 0, "Only ad groups in this campaign (default: every campaign)")
+	adgroupsAdjustBidsCmd.Flags().StringSliceVar(&adjFilters, "filter", nil, `Ad group filter conditions (e.g. "status=ENABLED")`)
+	adgroupsAdjustBidsCmd.Flags().Float64Var(&adjMultiply, "multiply", 0, "Multiply default bids by this (e.g. 1.10 for +10%) (required)")
+	adgroupsAdjustBidsCmd.Flags().Float64Var(&adjMin, "min", 0, "Lowest new default bid")
+	adgroupsAdjustBidsCmd.Flags().Float64Var(&adjMax, "max", 0, "Highest new default bid")
+	adgroupsAdjustBidsCmd.Flags().Float64Var(&adjRoundTo, "round-to", 0.01, "Round new bids to a multiple of this")
+	adgroupsAdjustBidsCmd.Flags().StringVar(&adjRounding, "rounding", "nearest", "Rounding direction: nearest, up, or down")
+	adgroupsAdjustBidsCmd.Flags().BoolVar(&adjApply, "apply", false, "Update the default bids (default: only list the changes)")
+	adgroupsAdjustBidsCmd.MarkFlagRequired("multiply")
+
+	adgroupsCmd.AddCommand(adgroupsAdjustBidsCmd)
+}
+
+// DefaultBidAdjustment is one ad group's default bid change.
+type DefaultBidAdjustment struct {
+	CampaignID int64   `json:"campaignId"`
+	AdGroupID  int64   `json:"adGroupId"`
+	Name       string  `json:"name"`
+	Currency   string  `json:"currency"`
+	Current    float64 `json:"current"`
+	New        float64 `json:"new"`
+	Change     string  `json:"change"`
+	Result     string  `json:"result"` // planned, updated, unchanged, failed
+	Error      string  `json:"error,omitempty"`
+
+	ResultText string `json:"-"`
+}
+
+func runAdGroupsAdjustBids(cmd *cobra.Command, args []string) error {
+	if adjMultiply <= 0 {
+		return fmt.Errorf("--multiply must be positive")
+	}
+	if adjRoundTo <= 0 {
+		return fmt.Errorf("--round-to must be positive")
+	}
+	if adjMin < 0 || adjMax < 0 || (adjMax > 0 && adjMin > adjMax) {
+		return fmt.Errorf("--min and --max must be positive, with --min at most --max")
+	}
+	switch adjRounding {
+	case "nearest", "up", "down":
+	default:
+		return fmt.Errorf("invalid --rounding %q (expected nearest, up, or down)", adjRounding)
+	}
+	if len(parseFilters(adjFilters)) != len(adjFilters) {
+		return fmt.Errorf("invalid --filter: expected field<op>value, e.g. \"status=ENABLED\"")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewAdGroupService(client)
+	selector := models.NewSelector(maxPageSize, 0)
+	selector.Conditions = parseFilters(adjFilters)
+	var adGroups []models.AdGroup
+	if agCampaignID != 0 {
+		adGroups, err = svc.FindAll(agCampaignID, selector)
+	} else {
+		adGroups, err = svc.FindAllInOrg(selector)
+	}
+	if err != nil {
+		return fmt.Errorf("finding ad groups: %w", err)
+	}
+
+	var adjustments []DefaultBidAdjustment
+	noBid := 0
+	for _, ag := range adGroups {
+		if ag.DefaultBidAmount == nil {
+			noBid++
+			continue
+		}
+		campaignID := ag.CampaignID
+		if campaignID == 0 {
+			campaignID = agCampaignID
+		}
+		a := DefaultBidAdjustment{CampaignID: campaignID, AdGroupID: ag.ID, Name: ag.Name, Currency: ag.DefaultBidAmount.Currency,
+			Current: aggregate.Amount(*ag.DefaultBidAmount)}
+		a.New = adjustBid(a.Current)
+		a.Change = pctChange(a.New, a.Current)
+		a.Result, a.ResultText = "planned", "planned"
+		if a.New == a.Current {
+			a.Result, a.ResultText = "unchanged", "unchanged"
+		}
+		adjustments = append(adjustments, a)
+	}
+	if noBid > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d ad group(s) without a default bid.\n", noBid)
+	}
+	if len(adjustments) == 0 {
+		return fmt.Errorf("no ad groups match")
+	}
+
+	columns := []output.Column{
+		{Header: "CAMPAIGN", Field: "CampaignID"},
+		{Header: "AD GROUP", Field: "AdGroupID"},
+		{Header: "NAME", Field: "Name"},
+		{Header: "CURRENT", Field: "Current"},
+		{Header: "NEW", Field: "New"},
+		{Header: "CHANGE", Field: "Change"},
+		{Header: "RESULT", Field: "ResultText"},
+	}
+	if !adjApply {
+		output.Print(getFormat(), adjustments, columns)
+		fmt.Fprintf(os.Stderr, "Dry run: %d default bid(s) would change. Re-run with --apply to update them.\n", countResult(adjustments, "planned"))
+		return nil
+	}
+
+	// Check every bid against the limits before changing any.
+	for _, a := range adjustments {
+		if a.Result != "planned" {
+			continue
+		}
+		if err := checkScaledBid(formatBid(a.New)); err != nil {
+			return fmt.Errorf("ad group %d: %w", a.AdGroupID, err)
+		}
+	}
+
+	record, err := changeset.New("adjust-bids-" + time.Now().Format("20060102-150405"))
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for i := range adjustments {
+		a := &adjustments[i]
+		if a.Result != "planned" {
+			continue
+		}
+		id := strconv.FormatInt(a.AdGroupID, 10)
+		scope := "--campaign-id=" + strconv.FormatInt(a.CampaignID, 10)
+		record.Add([]string{"adgroups", "update", id, scope, "--default-bid=" + formatBid(a.New)})
+		item := &record.Items[len(record.Items)-1]
+
+		update := &models.AdGroupUpdate{DefaultBidAmount: &models.Money{Amount: formatBid(a.New), Currency: a.Currency}}
+		if _, err := svc.Update(a.CampaignID, a.AdGroupID, update); err != nil {
+			a.Result, a.Error = "failed", err.Error()
+			a.ResultText = "failed: " + err.Error()
+			item.Status, item.Error = changeset.ItemFailed, err.Error()
+			failed++
+			continue
+		}
+		a.Result, a.ResultText = "updated", "updated"
+		item.Status = changeset.ItemApplied
+		item.Rollback = [][]string{{"adgroups", "update", id, scope, "--default-bid=" + formatBid(a.Current)}}
+	}
+	output.Print(getFormat(), adjustments, columns)
+
+	updated := countResult(adjustments, "updated")
+	summary := fmt.Sprintf("Updated default bids of %d ad group(s)", updated)
+	if n := countResult(adjustments, "unchanged"); n > 0 {
+		summary += fmt.Sprintf("; skipped %d already at the new bid", n)
+	}
+	fmt.Fprintln(os.Stderr, summary+".")
+
+	if len(record.Items) > 0 {
+		now := time.Now().UTC()
+		record.Status, record.AppliedAt = changeset.StatusApplied, &now
+		if failed > 0 {
+			record.Status = changeset.StatusFailed
+		}
+		if err := changeset.Save(profileName, record); err != nil {
+			return err
+		}
+		rollback, err := writeRollbackChangeset(record)
+		if err != nil {
+			return err
+		}
+		if rollback != "" {
+			fmt.Fprintf(os.Stderr, "Recorded as change set %s. To undo: asa-cli changeset apply %s\n", record.Name, rollback)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d ad group(s) could not be updated", failed)
+	}
+	return nil
+}
+
+// adjustBid scales a default bid by --multiply, rounds it to --round-to in
+// the --rounding direction, and keeps it within --min and --max.
+func adjustBid(current float64) float64 {
+	steps := current * adjMultiply / adjRoundTo
+	// Ignore float noise, so 1.10 * 1.5 doesn't round up past 1.65.
+	const eps = 1e-9
+	switch adjRounding {
+	case "up":
+		steps = math.Ceil(steps - eps)
+	case "down":
+		steps = math.Floor(steps + eps)
+	default:
+		steps = math.Round(steps)
+	}
+	v := roundCents(steps * adjRoundTo)
+	if adjMin > 0 && v < adjMin {
+		v = adjMin
+	}
+	if adjMax > 0 && v > adjMax {
+		v = adjMax
+	}
+	return v
+}
+
+func formatBid(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func countResult(adjustments []DefaultBidAdjustment, result string) int {
+	n := 0
+	for _, a := range adjustments {
+		if a.Result == result {
+			n++
+		}
+	}
+	return n
+}
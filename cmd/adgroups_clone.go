@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var adgroupsCloneCmd = &cobra.Command{
+	Use:   "clone <id>",
+	Short: "Copy an ad group within or across campaigns",
+	Long: `Create a copy of an ad group with its bidding, targeting, and ads (creatives),
+optionally with its keywords and negative keywords. --bid-multiplier scales the
+default bid and every keyword bid; scaled bids are checked against min_bid and
+max_bid before anything is created. On an api_version v4 profile ads are not
+copied.
+
+The copy goes into --to-campaign, or the source campaign if omitted. Targeting
+must be valid for the destination campaign's countries.
+
+Example:
+  asa-cli adgroups clone 456 --campaign-id 123 --to-campaign 789 --name "Exact - JP" \
+    --with-keywords --with-negatives --bid-multiplier 0.8`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdGroupsClone,
+}
+
+var (
+	cloneToCampaign    int64
+	cloneName          string
+	cloneStatus        models.Status
+	cloneKeywords      bool
+	cloneNegatives     bool
+	cloneBidMultiplier float64
+)
+
+func init() {
+	adgroupsCloneCmd.Flags().Int64Var(&agCampaignID, "campaign-id", 0, "Source campaign ID (required)")
+	adgroupsCloneCmd.Flags().Int64Var(&cloneToCampaign, "to-campaign", 0, "Destination campaign ID (default: the source campaign)")
+	adgroupsCloneCmd.Flags().StringVar(&cloneName, "name", "", `Name of the copy (default: "<name> (copy)")`)
+	enumVar(adgroupsCloneCmd.Flags(), &cloneStatus, "status", "", models.ParseStatus, "Status of the copy (default: the source's)")
+	adgroupsCloneCmd.Flags().BoolVar(&cloneKeywords, "with-keywords", false, "Copy targeting keywords")
+	adgroupsCloneCmd.Flags().BoolVar(&cloneNegatives, "with-negatives", false, "Copy ad group negative keywords")
+	adgroupsCloneCmd.Flags().Float64Var(&cloneBidMultiplier, "bid-multiplier", 1, "Scale the default bid and keyword bids")
+	adgroupsCloneCmd.MarkFlagRequired("campaign-id")
+
+	adgroupsCmd.AddCommand(adgroupsCloneCmd)
+}
+
+func runAdGroupsClone(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ad group ID: %s", args[0])
+	}
+	if cloneBidMultiplier <= 0 {
+		return fmt.Errorf("--bid-multiplier must be positive")
+	}
+	target := cloneToCampaign
+	if target == 0 {
+		target = agCampaignID
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	agSvc := services.NewAdGroupService(client)
+	kwSvc := services.NewKeywordService(client)
+
+	src, err := agSvc.Get(agCampaignID, id)
+	if err != nil {
+		return fmt.Errorf("getting ad group: %w", err)
+	}
+
+	name := cloneName
+	if name == "" {
+		name = src.Name + " (copy)"
+	}
+	if err := checkAdGroupName(name); err != nil {
+		return err
+	}
+	status := cloneStatus
+	if status == "" {
+		status = src.Status
+	}
+	clone := &models.AdGroup{
+		Name:                   name,
+		Status:                 status,
+		DefaultBidAmount:       scaleBid(src.DefaultBidAmount, cloneBidMultiplier),
+		CpaGoal:                src.CpaGoal,
+		AutomatedKeywordsOptIn: src.AutomatedKeywordsOptIn,
+		EndTime:                src.EndTime,
+		TargetingDimensions:    src.TargetingDimensions,
+		PricingModel:           src.PricingModel,
+	}
+	// Apple rejects start times in the past.
+	if start, err := time.Parse("2006-01-02T15:04:05.000", src.StartTime); err == nil && start.After(time.Now()) {
+		clone.StartTime = src.StartTime
+	}
+
+	var keywords []models.Keyword
+	if cloneKeywords {
+		found, err := kwSvc.FindAll(agCampaignID, id, models.NewSelector(1000, 0))
+		if err != nil {
+			return fmt.Errorf("finding keywords: %w", err)
+		}
+		for _, kw := range found {
+			if kw.Deleted {
+				continue
+			}
+			keywords = append(keywords, models.Keyword{
+				Text:      kw.Text,
+				MatchType: kw.MatchType,
+				Status:    kw.Status,
+				BidAmount: scaleBid(kw.BidAmount, cloneBidMultiplier),
+			})
+		}
+	}
+	var negatives []models.NegativeKeyword
+	if cloneNegatives {
+		found, err := kwSvc.FindAllAdGroupNegativeKeywords(agCampaignID, id, models.NewSelector(1000, 0))
+		if err != nil {
+			return fmt.Errorf("finding negative keywords: %w", err)
+		}
+		for _, kw := range found {
+			if !kw.Deleted {
+				negatives = append(negatives, models.NegativeKeyword{Text: kw.Text, MatchType: kw.MatchType})
+			}
+		}
+	}
+	adSvc := services.NewAdService(client)
+	ads, err := adSvc.FindAll(agCampaignID, id, models.NewSelector(1000, 0))
+	if api.IsVersionError(err) {
+		// v4 orgs have creative sets, not ads; the copy gets none.
+		warnf("%v; ads are not copied.", err)
+		ads, err = nil, nil
+	}
+	if err != nil {
+		return fmt.Errorf("finding ads: %w", err)
+	}
+
+	// Check scaled bids before creating anything.
+	if clone.DefaultBidAmount != nil {
+		if err := checkScaledBid(clone.DefaultBidAmount.Amount); err != nil {
+			return fmt.Errorf("default bid: %w", err)
+		}
+	}
+	for _, kw := range keywords {
+		if kw.BidAmount == nil {
+			continue
+		}
+		if err := checkScaledBid(kw.BidAmount.Amount); err != nil {
+			return fmt.Errorf("keyword %q: %w", kw.Text, err)
+		}
+	}
+
+	created, err := agSvc.Create(target, clone)
+	if err != nil {
+		return fmt.Errorf("creating ad group: %w", err)
+	}
+
+	copiedAds := 0
+	for _, ad := range ads {
+		if ad.Deleted {
+			continue
+		}
+		if _, err := adSvc.Create(target, created.ID, &models.Ad{CreativeID: ad.CreativeID, Name: ad.Name, Status: ad.Status}); err != nil {
+			return fmt.Errorf("ad group %d created, but copying ad %q failed: %w", created.ID, ad.Name, err)
+		}
+		copiedAds++
+	}
+	for i := 0; i < len(keywords); i += 1000 {
+		chunk := keywords[i:min(i+1000, len(keywords))]
+		if _, err := kwSvc.Create(target, created.ID, chunk); err != nil {
+			return fmt.Errorf("ad group %d created, but copying keywords failed after %d of %d: %w", created.ID, i, len(keywords), err)
+		}
+	}
+	for i := 0; i < len(negatives); i += 1000 {
+		chunk := negatives[i:min(i+1000, len(negatives))]
+		if _, err := kwSvc.CreateAdGroupNegativeKeywords(target, created.ID, chunk); err != nil {
+			return fmt.Errorf("ad group %d created, but copying negative keywords failed after %d of %d: %w", created.ID, i, len(negatives), err)
+		}
+	}
+
+	output.Print(getFormat(), created, adgroupColumns)
+	fmt.Fprintf(os.Stderr, "Cloned ad group %d into campaign %d: %d ad(s), %d keyword(s), %d negative keyword(s).\n",
+		id, target, copiedAds, len(keywords), len(negatives))
+	return nil
+}
+
+// scaleBid multiplies a bid, rounding to cents.
+func scaleBid(m *models.Money, multiplier float64) *models.Money {
+	if m == nil {
+		return nil
+	}
+	if multiplier == 1 {
+		return &models.Money{Amount: m.Amount, Currency: m.Currency}
+	}
+	v := roundCents(aggregate.Amount(*m) * multiplier)
+	return &models.Money{Amount: strconv.FormatFloat(v, 'f', 2, 64), Currency: m.Currency}
+}
+
+func checkScaledBid(amount string) error {
+	if err := checkBidLimit(amount); err != nil {
+		return err
+	}
+	return checkMinBid(amount)
+}
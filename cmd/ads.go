@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/approvals"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/rotation"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var adsCmd = &cobra.Command{
+	Use:   "ads",
+	Short: "Manage ads (default and custom product page creatives)",
+}
+
+var adsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ads in an ad group",
+	RunE:  runAdsList,
+}
+
+var adsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Enable the ads a rotation schedule picks for today",
+	Long: `Enable the ads that --schedule picks for today and pause the other ads it
+mentions. Ads the schedule doesn't mention are left alone. Ads are enabled
+before others are paused, so the ad group is never without an ad.
+
+The schedule is a YAML file with dated windows, a weekly cycle, and a
+default. Windows win over the cycle; the default applies when neither covers
+the day:
+
+  windows:                 # e.g. seasonal creatives
+    - from: 2026-11-20
+      to: 2026-12-31
+      ads: [4001]
+  cycle:                   # one ad per slot, in turn
+    start: 2026-01-05
+    weeks: 1
+    ads: [4002, 4003]
+  default: [4002]
+
+Run it daily from cron, or keep it running with --watch. Every change is
+appended to ~/.asa-cli/rotation.jsonl with the operator and the slot that
+caused it; see: asa-cli ads history.
+
+Example:
+  asa-cli ads rotate --campaign-id 123 --adgroup-id 456 --schedule rotation.yaml --dry-run`,
+	RunE: runAdsRotate,
+}
+
+var adsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the ad status changes made by rotations",
+	RunE:  runAdsHistory,
+}
+
+var (
+	adsCampaignID  int64
+	adsAdGroupID   int64
+	adsSchedule    string
+	adsDryRun      bool
+	adsWatch       bool
+	adsInterval    time.Duration
+	adsHistoryLast int
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{adsListCmd, adsRotateCmd} {
+		cmd.Flags().Int64Var(&adsCampaignID, "campaign-id", 0, "Campaign ID (required)")
+		cmd.Flags().Int64Var(&adsAdGroupID, "adgroup-id", 0, "Ad group ID (required)")
+		cmd.MarkFlagRequired("campaign-id")
+		cmd.MarkFlagRequired("adgroup-id")
+	}
+	adsRotateCmd.Flags().StringVar(&adsSchedule, "schedule", "", "Rotation schedule YAML file (required)")
+	adsRotateCmd.Flags().BoolVar(&adsDryRun, "dry-run", false, "Print the changes without making them")
+	adsRotateCmd.Flags().BoolVar(&adsWatch, "watch", false, "Keep running and re-check every --interval")
+	adsRotateCmd.Flags().DurationVar(&adsInterval, "interval", time.Hour, "Time between checks with --watch")
+	adsRotateCmd.MarkFlagRequired("schedule")
+	adsHistoryCmd.Flags().IntVar(&adsHistoryLast, "last", 50, "Show at most this many of the latest changes (0 for all)")
+
+	adsCmd.AddCommand(adsListCmd, adsRotateCmd, adsHistoryCmd)
+	rootCmd.AddCommand(adsCmd)
+
+	markMutating(func() bool { return !adsDryRun }, adsRotateCmd)
+}
+
+var adColumns = []output.Column{
+	{Header: "ID", Field: "ID"},
+	{Header: "NAME", Field: "Name"},
+	{Header: "CREATIVE ID", Field: "CreativeID"},
+	{Header: "TYPE", Field: "CreativeType"},
+	{Header: "STATUS", Field: "Status"},
+	{Header: "SERVING", Field: "ServingStatus"},
+}
+
+func runAdsList(cmd *cobra.Command, args []string) error {
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	ads, err := liveAds(client)
+	if err != nil {
+		return err
+	}
+	output.Print(getFormat(), ads, adColumns)
+	return nil
+}
+
+// liveAds returns the ad group's ads that are not deleted.
+func liveAds(client *api.Client) ([]models.Ad, error) {
+	all, err := services.NewAdService(client).FindAll(adsCampaignID, adsAdGroupID, models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return nil, fmt.Errorf("finding ads: %w", err)
+	}
+	ads := []models.Ad{}
+	for _, ad := range all {
+		if !ad.Deleted {
+			ads = append(ads, ad)
+		}
+	}
+	return ads, nil
+}
+
+func runAdsRotate(cmd *cobra.Command, args []string) error {
+	schedule, err := rotation.Load(adsSchedule)
+	if err != nil {
+		return err
+	}
+	if adsWatch && adsInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	if !adsWatch {
+		return rotateAds(client, schedule, time.Now())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "Rotating ads in ad group %d by %s, checking every %s. Press Ctrl-C to stop.\n", adsAdGroupID, adsSchedule, adsInterval)
+	for {
+		if err := rotateAds(client.WithContext(ctx), schedule, time.Now()); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "%s rotation failed: %v\n", time.Now().Format("2006-01-02 15:04"), err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(adsInterval):
+		}
+	}
+}
+
+// rotateAds brings the managed ads in line with the schedule for day and
+// logs each change.
+func rotateAds(client *api.Client, schedule *rotation.Schedule, day time.Time) error {
+	ads, err := liveAds(client)
+	if err != nil {
+		return err
+	}
+	active, slot := schedule.Active(day)
+	byID := map[int64]models.Ad{}
+	for _, ad := range ads {
+		byID[ad.ID] = ad
+	}
+	for _, id := range active {
+		if _, ok := byID[id]; !ok {
+			return fmt.Errorf("ad %d (%s) is not in ad group %d", id, slot, adsAdGroupID)
+		}
+	}
+
+	// Enable first, then pause, so the ad group always has an ad running.
+	var changes []rotation.Change
+	for _, pass := range []models.Status{models.StatusEnabled, models.StatusPaused} {
+		for _, id := range schedule.Managed() {
+			ad, ok := byID[id]
+			if !ok {
+				continue
+			}
+			want := models.StatusPaused
+			if slices.Contains(active, id) {
+				want = models.StatusEnabled
+			}
+			if want != pass || ad.Status == want {
+				continue
+			}
+			changes = append(changes, rotation.Change{
+				Time:       time.Now().UTC(),
+				Operator:   approvals.Operator(),
+				Profile:    profileName,
+				CampaignID: adsCampaignID,
+				AdGroupID:  adsAdGroupID,
+				AdID:       id,
+				AdName:     ad.Name,
+				From:       string(ad.Status),
+				To:         string(want),
+				Slot:       slot,
+				Schedule:   filepath.Base(adsSchedule),
+			})
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(os.Stderr, "%s ad group %d already matches %s.\n", day.Format("2006-01-02 15:04"), adsAdGroupID, slot)
+		return nil
+	}
+	if adsDryRun {
+		printRotation(changes)
+		fmt.Fprintf(os.Stderr, "Dry run: %d ad(s) would change for %s.\n", len(changes), slot)
+		return nil
+	}
+
+	svc := services.NewAdService(client)
+	var runErr error
+	for i := range changes {
+		c := &changes[i]
+		if _, err := svc.Update(adsCampaignID, adsAdGroupID, c.AdID, &models.AdUpdate{Status: models.Status(c.To)}); err != nil {
+			c.Error = err.Error()
+			runErr = fmt.Errorf("setting ad %d to %s: %w", c.AdID, c.To, err)
+			changes = changes[:i+1]
+			break
+		}
+	}
+	if err := rotation.Append(changes); err != nil {
+		warnf("%v", err)
+	}
+	printRotation(changes)
+	return runErr
+}
+
+type rotationRow struct {
+	rotation.Change
+	When   string `json:"-"`
+	Status string `json:"-"`
+}
+
+func printRotation(changes []rotation.Change) {
+	rows := []rotationRow{}
+	for _, c := range changes {
+		status := c.From + " -> " + c.To
+		if c.Error != "" {
+			status += " (failed: " + c.Error + ")"
+		}
+		rows = append(rows, rotationRow{Change: c, When: c.Time.Local().Format("2006-01-02 15:04"), Status: status})
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "TIME", Field: "When"},
+		{Header: "AD GROUP", Field: "AdGroupID"},
+		{Header: "AD", Field: "AdID"},
+		{Header: "NAME", Field: "AdName"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "SLOT", Field: "Slot"},
+		{Header: "BY", Field: "Operator"},
+	})
+}
+
+func runAdsHistory(cmd *cobra.Command, args []string) error {
+	changes, err := rotation.LoadLog()
+	if err != nil {
+		return err
+	}
+	if adsHistoryLast > 0 && len(changes) > adsHistoryLast {
+		changes = changes[len(changes)-adsHistoryLast:]
+	}
+	printRotation(changes)
+	return nil
+}
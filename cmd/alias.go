@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage command aliases",
+	Long: `Define shortcuts for long command lines, similar to git aliases. Aliases are
+stored in config.yaml under "aliases:" and expanded before the command line is
+parsed; any extra arguments are appended to the expansion.
+
+Example:
+  asa-cli alias set top-keywords 'reports keywords --start-date 2026-01-01 --end-date 2026-01-31 --limit 50'
+  asa-cli top-keywords --campaign-id 123`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <command>",
+	Short: "Create or replace an alias",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runAliasSet,
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	RunE:  runAliasList,
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an alias",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAliasRemove,
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd, aliasListCmd, aliasRemoveCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
+
+type aliasRow struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+func runAliasSet(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(args[0])
+	if strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid alias name: %q", args[0])
+	}
+	if sub, _, err := rootCmd.Find([]string{name}); err == nil && sub != rootCmd {
+		return fmt.Errorf("%q is a built-in command and cannot be aliased", name)
+	}
+
+	expansion := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(args[1]), "asa-cli "))
+	parts, err := splitCommandLine(expansion)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("alias command cannot be empty")
+	}
+
+	if err := config.SetAlias(name, expansion); err != nil {
+		return fmt.Errorf("saving alias: %w", err)
+	}
+	fmt.Printf("Alias '%s' set: %s\n", name, expansion)
+	return nil
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	aliases, err := config.Aliases()
+	if err != nil {
+		return fmt.Errorf("loading aliases: %w", err)
+	}
+
+	rows := []aliasRow{}
+	for name, expansion := range aliases {
+		rows = append(rows, aliasRow{Name: name, Command: expansion})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "ALIAS", Field: "Name"},
+		{Header: "COMMAND", Field: "Command"},
+	})
+	return nil
+}
+
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(args[0])
+	aliases, err := config.Aliases()
+	if err != nil {
+		return fmt.Errorf("loading aliases: %w", err)
+	}
+	if _, ok := aliases[name]; !ok {
+		return fmt.Errorf("alias %q not found", name)
+	}
+	if err := config.RemoveAlias(name); err != nil {
+		return fmt.Errorf("removing alias: %w", err)
+	}
+	fmt.Printf("Alias '%s' removed.\n", name)
+	return nil
+}
+
+// expandAliases replaces the first command word in args with its alias
+// expansion, if it names an alias rather than a built-in command. Global
+// flags before the command word are preserved.
+func expandAliases(args []string) []string {
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") {
+		name := strings.TrimLeft(args[i], "-")
+		if strings.Contains(name, "=") {
+			i++
+			continue
+		}
+		var f = rootCmd.PersistentFlags().Lookup(name)
+		if f == nil && len(name) == 1 {
+			f = rootCmd.PersistentFlags().ShorthandLookup(name)
+		}
+		if f != nil && f.NoOptDefVal == "" {
+			i++ // skip the flag's value
+		}
+		i++
+	}
+	if i >= len(args) {
+		return args
+	}
+	if sub, _, err := rootCmd.Find([]string{args[i]}); err == nil && sub != rootCmd {
+		return args
+	}
+
+	aliases, err := config.Aliases()
+	if err != nil {
+		return args
+	}
+	expansion, ok := aliases[strings.ToLower(args[i])]
+	if !ok {
+		return args
+	}
+	parts, err := splitCommandLine(expansion)
+	if err != nil || len(parts) == 0 {
+		return args
+	}
+
+	out := append([]string(nil), args[:i]...)
+	out = append(out, parts...)
+	return append(out, args[i+1:]...)
+}
@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyses built on top of reports",
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+}
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzeCannibalizationCmd = &cobra.Command{
+	Use:   "cannibalization",
+	Short: "Find search terms broad keywords serve that exact keywords target",
+	Long: `Match the search terms a campaign's BROAD keywords served against its live
+EXACT keywords, and total the spend that went to broad for terms an exact
+keyword already targets.
+
+For each term the fix is an EXACT negative in the broad keyword's ad group,
+which sends the traffic to the exact keyword. No negative is suggested where
+it would block the exact keyword too (both in one ad group), where the exact
+keyword is paused, or where the ad group already has the negative. --apply
+adds the suggested negatives.
+
+Example:
+  asa-cli analyze cannibalization --campaign-id 123 --range last-30d
+  asa-cli analyze cannibalization --campaign-id 123 --min-spend 5 --apply`,
+	RunE: runAnalyzeCannibalization,
+}
+
+var (
+	cannibalCampaignID int64
+	cannibalRange      string
+	cannibalMinSpend   float64
+	cannibalApply      bool
+)
+
+func init() {
+	analyzeCannibalizationCmd.Flags().Int64Var(&cannibalCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	analyzeCannibalizationCmd.Flags().StringVar(&cannibalRange, "range", "last-30d", "Date range: "+daterange.Help)
+	analyzeCannibalizationCmd.Flags().Float64Var(&cannibalMinSpend, "min-spend", 0, "Only report terms with at least this much broad spend")
+	analyzeCannibalizationCmd.Flags().BoolVar(&cannibalApply, "apply", false, "Add the suggested negatives")
+	analyzeCannibalizationCmd.MarkFlagRequired("campaign-id")
+
+	analyzeCmd.AddCommand(analyzeCannibalizationCmd)
+}
+
+// Cannibalization is a search term a broad keyword served in one ad group
+// while an exact keyword targets it.
+type Cannibalization struct {
+	Term           string            `json:"term"`
+	BroadAdGroupID int64             `json:"broadAdGroupId"`
+	BroadAdGroup   string            `json:"broadAdGroup"`
+	BroadKeywords  []string          `json:"broadKeywords"`
+	ExactKeywordID int64             `json:"exactKeywordId"`
+	ExactAdGroupID int64             `json:"exactAdGroupId"`
+	Broad          aggregate.Metrics `json:"broad"`
+	Exact          aggregate.Metrics `json:"exact"`
+	// Negative is the suggested ad group negative, or nil when Note says
+	// why there is none.
+	Negative   *models.NegativeKeyword `json:"negative,omitempty"`
+	NegativeID int64                   `json:"negativeId,omitempty"`
+	Note       string                  `json:"note,omitempty"`
+
+	KeywordsText string `json:"-"`
+	SpendText    string `json:"-"`
+	InstallsText string `json:"-"`
+	CPIText      string `json:"-"`
+	ExactCPIText string `json:"-"`
+	Action       string `json:"-"`
+}
+
+func runAnalyzeCannibalization(cmd *cobra.Command, args []string) error {
+	rng, err := daterange.Parse(cannibalRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewKeywordService(client)
+	keywords, err := svc.FindAllInCampaign(cannibalCampaignID, models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return fmt.Errorf("listing keywords: %w", err)
+	}
+	negatives, err := svc.FindAllAdGroupNegativeKeywordsInCampaign(cannibalCampaignID, models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("listing ad group negative keywords: %w", err)
+	}
+
+	report := &models.ReportingDataResponse{}
+	err = services.NewReportingService(client).EachPage(cannibalCampaignID, "searchterms", newRangeReportRequest(rng, 1000), func(page *models.ReportingDataResponse) error {
+		report.Row = append(report.Row, page.Row...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("getting search terms report: %w", err)
+	}
+
+	found := findCannibalization(report, keywords, negatives)
+	var leaked aggregate.Metrics
+	for _, c := range found {
+		leaked.Merge(c.Broad)
+	}
+	if len(found) == 0 && getFormat() == output.FormatTable {
+		fmt.Printf("No broad keywords served terms targeted as exact in campaign %d (%s).\n", cannibalCampaignID, rng)
+		return nil
+	}
+
+	failed := 0
+	if cannibalApply {
+		failed = addCannibalizationNegatives(svc, found)
+	}
+
+	output.Print(getFormat(), found, []output.Column{
+		{Header: "TERM", Field: "Term"},
+		{Header: "BROAD AD GROUP", Field: "BroadAdGroup"},
+		{Header: "BROAD KEYWORDS", Field: "KeywordsText"},
+		{Header: "BROAD SPEND", Field: "SpendText"},
+		{Header: "BROAD INSTALLS", Field: "InstallsText"},
+		{Header: "BROAD CPI", Field: "CPIText"},
+		{Header: "EXACT CPI", Field: "ExactCPIText"},
+		{Header: "NEGATIVE", Field: "Action"},
+	})
+	if getFormat() == output.FormatTable {
+		fmt.Printf("\n%d term(s), %.2f broad spend on terms exact keywords target (%s)\n", len(found), leaked.Spend, rng)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d negative(s) could not be added", failed)
+	}
+	if !cannibalApply {
+		suggested := 0
+		for _, c := range found {
+			if c.Negative != nil {
+				suggested++
+			}
+		}
+		if suggested > 0 {
+			fmt.Fprintf(os.Stderr, "Re-run with --apply to add %d negative(s).\n", suggested)
+		}
+	}
+	return nil
+}
+
+// findCannibalization totals, per term and broad ad group, the search-term
+// rows served by live BROAD keywords whose text a live EXACT keyword
+// targets, and suggests the negative that would stop them. Rows with the
+// most broad spend come first.
+func findCannibalization(resp *models.ReportingDataResponse, keywords []models.Keyword, negatives []models.NegativeKeyword) []Cannibalization {
+	byID := map[int64]models.Keyword{}
+	exact := map[string]models.Keyword{}
+	for _, k := range keywords {
+		if k.Deleted {
+			continue
+		}
+		byID[k.ID] = k
+		if k.MatchType != models.MatchExact {
+			continue
+		}
+		// Prefer an active exact keyword when a text is targeted twice.
+		term := aggregate.NormalizeTerm(k.Text)
+		if prev, ok := exact[term]; !ok || prev.Status != models.KeywordActive && k.Status == models.KeywordActive {
+			exact[term] = k
+		}
+	}
+	negated := map[string]bool{}
+	for _, n := range negatives {
+		if !n.Deleted && n.MatchType == models.MatchExact {
+			negated[fmt.Sprintf("%d|%s", n.AdGroupID, aggregate.NormalizeTerm(n.Text))] = true
+		}
+	}
+
+	type key struct {
+		term string
+		ag   int64
+	}
+	index := map[key]int{}
+	exactMetrics := map[string]aggregate.Metrics{}
+	found := []Cannibalization{}
+	for _, row := range resp.Row {
+		term := aggregate.NormalizeTerm(aggregate.MetaString(row.Metadata, "searchTermText"))
+		kw, ok := byID[aggregate.MetaInt64(row.Metadata, "keywordId")]
+		if term == "" || !ok {
+			continue
+		}
+		target, targeted := exact[term]
+		if !targeted {
+			continue
+		}
+		if kw.ID == target.ID {
+			m := exactMetrics[term]
+			m.Add(row.Total)
+			exactMetrics[term] = m
+			continue
+		}
+		if kw.MatchType != models.MatchBroad {
+			continue
+		}
+
+		k := key{term, kw.AdGroupID}
+		i, ok := index[k]
+		if !ok {
+			i = len(found)
+			index[k] = i
+			found = append(found, Cannibalization{
+				Term:           term,
+				BroadAdGroupID: kw.AdGroupID,
+				BroadAdGroup:   aggregate.MetaString(row.Metadata, "adGroupName"),
+				ExactKeywordID: target.ID,
+				ExactAdGroupID: target.AdGroupID,
+			})
+		}
+		c := &found[i]
+		c.Broad.Add(row.Total)
+		if !containsString(c.BroadKeywords, kw.Text) {
+			c.BroadKeywords = append(c.BroadKeywords, kw.Text)
+		}
+	}
+
+	out := []Cannibalization{}
+	for _, c := range found {
+		if c.Broad.Spend < cannibalMinSpend || c.Broad.Impressions == 0 {
+			continue
+		}
+		c.Exact = exactMetrics[c.Term]
+		switch {
+		case c.ExactAdGroupID == c.BroadAdGroupID:
+			c.Note = "exact keyword in the same ad group; move it to its own"
+		case exact[c.Term].Status != models.KeywordActive:
+			c.Note = "exact keyword paused"
+		case negated[fmt.Sprintf("%d|%s", c.BroadAdGroupID, c.Term)]:
+			c.Note = "already negated"
+		default:
+			c.Negative = &models.NegativeKeyword{CampaignID: cannibalCampaignID, AdGroupID: c.BroadAdGroupID, Text: c.Term, MatchType: models.MatchExact}
+		}
+		if c.BroadAdGroup == "" {
+			c.BroadAdGroup = fmt.Sprintf("%d", c.BroadAdGroupID)
+		}
+		sort.Strings(c.BroadKeywords)
+		c.Broad.Spend = roundCents(c.Broad.Spend)
+		c.Exact.Spend = roundCents(c.Exact.Spend)
+		c.KeywordsText = strings.Join(c.BroadKeywords, ", ")
+		c.SpendText = fmt.Sprintf("%.2f", c.Broad.Spend)
+		c.InstallsText = fmt.Sprintf("%d", c.Broad.Installs)
+		c.CPIText = cpiText(c.Broad)
+		c.ExactCPIText = cpiText(c.Exact)
+		c.Action = c.Note
+		if c.Negative != nil {
+			c.Action = fmt.Sprintf("add %q EXACT", c.Term)
+		}
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Broad.Spend > out[j].Broad.Spend })
+	return out
+}
+
+func cpiText(m aggregate.Metrics) string {
+	if m.Installs == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", m.CPI())
+}
+
+// addCannibalizationNegatives adds the suggested negatives, one request per
+// ad group, and returns how many could not be added.
+func addCannibalizationNegatives(svc *services.KeywordService, found []Cannibalization) int {
+	byAdGroup := map[int64][]int{}
+	var adGroups []int64
+	for i, c := range found {
+		if c.Negative == nil {
+			continue
+		}
+		if _, ok := byAdGroup[c.BroadAdGroupID]; !ok {
+			adGroups = append(adGroups, c.BroadAdGroupID)
+		}
+		byAdGroup[c.BroadAdGroupID] = append(byAdGroup[c.BroadAdGroupID], i)
+	}
+
+	failed := 0
+	for _, ag := range adGroups {
+		rows := byAdGroup[ag]
+		negatives := make([]models.NegativeKeyword, len(rows))
+		for i, r := range rows {
+			negatives[i] = models.NegativeKeyword{Text: found[r].Term, MatchType: models.MatchExact}
+		}
+		results, err := svc.CreateAdGroupNegativeKeywordsEach(cannibalCampaignID, ag, negatives)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Adding negatives to ad group %d: %v\n", ag, err)
+			for _, r := range rows {
+				found[r].Action = "failed: " + err.Error()
+			}
+			failed += len(rows)
+			continue
+		}
+		for _, res := range results {
+			c := &found[rows[res.Index]]
+			if !res.OK() {
+				c.Action = "failed: " + res.Error
+				failed++
+				continue
+			}
+			c.NegativeID = res.Item.ID
+			c.Action = "added"
+		}
+	}
+	return failed
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/forecast"
+	"github.com/trebuhs/asa-cli/internal/intraday"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzeForecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Project a campaign's daily spend and installs for budget planning",
+	Long: `Fit a day-of-week seasonal model to a campaign's daily spend and installs over
+--history and project them --horizon days ahead, with 80% confidence bands.
+
+Days in the local warehouse (hourly history kept by 'summary --project-eod')
+are taken from it; the rest of the history comes from a daily report.
+
+This is an ESTIMATE from a simple model: a linear trend times a weekday
+factor, fitted separately to spend and installs. Budget, bid, and targeting
+changes, auction dynamics, and events outside the history are not modeled, so
+re-run it after changing the campaign.
+
+Example:
+  asa-cli analyze forecast --campaign-id 123 --horizon 30d
+  asa-cli analyze forecast --campaign-id 123 --horizon 4w --csv forecast.csv`,
+	RunE: runAnalyzeForecast,
+}
+
+var (
+	forecastCampaignID int64
+	forecastHorizon    string
+	forecastHistory    string
+	forecastCSV        string
+)
+
+// forecastMaxDays bounds the history and horizon: Apple serves daily reports
+// for up to 90 days per request, and projections further out mean little.
+const forecastMaxDays = 90
+
+func init() {
+	analyzeForecastCmd.Flags().Int64Var(&forecastCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	analyzeForecastCmd.Flags().StringVar(&forecastHorizon, "horizon", "30d", "How far ahead to project (e.g. 30d, 4w)")
+	analyzeForecastCmd.Flags().StringVar(&forecastHistory, "history", "8w", "History to fit the model to (at least 14d)")
+	analyzeForecastCmd.Flags().StringVar(&forecastCSV, "csv", "", "Also write the projection to this CSV file")
+	analyzeForecastCmd.MarkFlagRequired("campaign-id")
+
+	analyzeCmd.AddCommand(analyzeForecastCmd)
+}
+
+// ForecastDay is one projected day, or the horizon's total.
+type ForecastDay struct {
+	Date         string  `json:"date"`
+	Weekday      string  `json:"weekday,omitempty"`
+	Spend        float64 `json:"spend"`
+	SpendLow     float64 `json:"spendLow"`
+	SpendHigh    float64 `json:"spendHigh"`
+	Installs     float64 `json:"installs"`
+	InstallsLow  float64 `json:"installsLow"`
+	InstallsHigh float64 `json:"installsHigh"`
+}
+
+// Forecast is the output of analyze forecast.
+type Forecast struct {
+	Estimate     bool          `json:"estimate"`
+	CampaignID   int64         `json:"campaignId"`
+	CampaignName string        `json:"campaignName,omitempty"`
+	Currency     string        `json:"currency,omitempty"`
+	History      string        `json:"history"`
+	Confidence   float64       `json:"confidence"`
+	Days         []ForecastDay `json:"days"`
+	Total        ForecastDay   `json:"total"`
+}
+
+func runAnalyzeForecast(cmd *cobra.Command, args []string) error {
+	horizon, err := forecastDays("--horizon", forecastHorizon, 1)
+	if err != nil {
+		return err
+	}
+	historyDays, err := forecastDays("--history", forecastHistory, forecast.MinHistory)
+	if err != nil {
+		return err
+	}
+	rng, err := daterange.Parse(fmt.Sprintf("last-%dd", historyDays), time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	spend := make([]float64, historyDays)
+	installs := make([]float64, historyDays)
+	store, err := intraday.Load(profileName)
+	if err != nil {
+		return err
+	}
+	var missing []int
+	for i := range spend {
+		s, n, ok := store.DayTotal(forecastCampaignID, rng.Start.AddDate(0, 0, i))
+		if !ok {
+			missing = append(missing, i)
+			continue
+		}
+		spend[i], installs[i] = s, float64(n)
+	}
+
+	f := Forecast{Estimate: true, CampaignID: forecastCampaignID, History: rng.String(), Confidence: 0.8}
+	if len(missing) > 0 {
+		from := rng.Start.AddDate(0, 0, missing[0])
+		to := rng.Start.AddDate(0, 0, missing[len(missing)-1])
+		req := newRangeReportRequest(daterange.Range{Start: from, End: to}, 1)
+		req.Granularity = models.GranularityDaily
+		req.Selector.Conditions = []models.Condition{
+			{Field: "campaignId", Operator: "IN", Values: []string{strconv.FormatInt(forecastCampaignID, 10)}},
+		}
+		resp, err := services.NewReportingService(client).GetCampaignReport(req)
+		if err != nil {
+			return fmt.Errorf("getting daily campaign report: %w", err)
+		}
+		fetched := map[string]aggregate.Metrics{}
+		for _, row := range resp.Row {
+			f.CampaignName = aggregate.MetaString(row.Metadata, "campaignName")
+			for _, g := range row.Granularity {
+				m := fetched[g.Date]
+				m.Add(g.Metrics)
+				fetched[g.Date] = m
+				if f.Currency == "" {
+					f.Currency = m.Currency
+				}
+			}
+		}
+		for _, i := range missing {
+			m := fetched[rng.Start.AddDate(0, 0, i).Format(daterange.DateFormat)]
+			spend[i], installs[i] = m.Spend, float64(m.Installs)
+		}
+	}
+
+	if f.Currency == "" {
+		f.Currency, _ = resolveOrgCurrency(client)
+	}
+
+	// Fit from the campaign's first day with spend, so days before it ran
+	// don't drag the level down
+	first := 0
+	for first < len(spend) && spend[first] == 0 {
+		first++
+	}
+	if historyDays-first < forecast.MinHistory {
+		return fmt.Errorf("campaign %d has %d day(s) with data in %s; a forecast needs at least %d", forecastCampaignID, historyDays-first, rng, forecast.MinHistory)
+	}
+	start := rng.Start.AddDate(0, 0, first)
+	spendModel, err := forecast.Fit(spend[first:], start)
+	if err != nil {
+		return err
+	}
+	installsModel, err := forecast.Fit(installs[first:], start)
+	if err != nil {
+		return err
+	}
+
+	spendEst := spendModel.Project(horizon, forecast.Z80)
+	installsEst := installsModel.Project(horizon, forecast.Z80)
+	for i := range spendEst {
+		f.Days = append(f.Days, forecastDay(spendEst[i], installsEst[i]))
+	}
+	f.Total = forecastDay(forecast.Sum(spendEst), forecast.Sum(installsEst))
+	f.Total.Date = "TOTAL"
+
+	if forecastCSV != "" {
+		if err := writeForecastCSV(forecastCSV, f); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", forecastCSV)
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, f, nil)
+		return nil
+	}
+	name := f.CampaignName
+	if name == "" {
+		name = strconv.FormatInt(forecastCampaignID, 10)
+	}
+	fmt.Fprintf(os.Stderr, "ESTIMATE for campaign %s: next %d day(s), fitted to %s (%s), 80%% bands\n",
+		name, horizon, rng, f.Currency)
+	output.Print(getFormat(), append(f.Days, f.Total), []output.Column{
+		{Header: "DATE", Field: "Date"},
+		{Header: "DAY", Field: "Weekday"},
+		{Header: "SPEND", Field: "Spend"},
+		{Header: "SPEND LOW", Field: "SpendLow"},
+		{Header: "SPEND HIGH", Field: "SpendHigh"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "INSTALLS LOW", Field: "InstallsLow"},
+		{Header: "INSTALLS HIGH", Field: "InstallsHigh"},
+	})
+	fmt.Fprintln(os.Stderr, "These figures are model estimates for planning, not guarantees; see 'asa-cli analyze forecast --help'.")
+	return nil
+}
+
+// forecastDays parses a --horizon or --history length such as 30d or 4w.
+func forecastDays(flag, value string, min int) (int, error) {
+	age, err := daterange.ParseAge(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", flag, err)
+	}
+	days := int(age / (24 * time.Hour))
+	if days < min || days > forecastMaxDays {
+		return 0, fmt.Errorf("%s must be between %d and %d days", flag, min, forecastMaxDays)
+	}
+	return days, nil
+}
+
+func forecastDay(spend, installs forecast.Estimate) ForecastDay {
+	d := ForecastDay{
+		Spend:        roundCents(spend.Value),
+		SpendLow:     roundCents(spend.Low),
+		SpendHigh:    roundCents(spend.High),
+		Installs:     math.Round(installs.Value*10) / 10,
+		InstallsLow:  math.Round(installs.Low*10) / 10,
+		InstallsHigh: math.Round(installs.High*10) / 10,
+	}
+	if !spend.Date.IsZero() {
+		d.Date = spend.Date.Format(daterange.DateFormat)
+		d.Weekday = spend.Date.Weekday().String()[:3]
+	}
+	return d
+}
+
+func writeForecastCSV(path string, f Forecast) error {
+	file, err := os.Create(explainPath(path))
+	if err != nil {
+		return fmt.Errorf("creating CSV: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"date", "weekday", "spend", "spendLow", "spendHigh", "installs", "installsLow", "installsHigh", "currency", "estimate"})
+	for _, d := range append(f.Days, f.Total) {
+		w.Write([]string{
+			d.Date, d.Weekday,
+			fmt.Sprintf("%g", d.Spend), fmt.Sprintf("%g", d.SpendLow), fmt.Sprintf("%g", d.SpendHigh),
+			fmt.Sprintf("%g", d.Installs), fmt.Sprintf("%g", d.InstallsLow), fmt.Sprintf("%g", d.InstallsHigh),
+			f.Currency, "true",
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
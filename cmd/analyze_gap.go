@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzeKeywordGapCmd = &cobra.Command{
+	Use:   "keyword-gap",
+	Short: "Find converting search terms not targeted as exact keywords",
+	Long: `Compare a campaign's search-term report with its targeting keywords and list
+the terms that convert but are not yet targeted as EXACT match in any ad group.
+
+HEADROOM is an ESTIMATE of the extra installs (and spend, at the term's
+observed CPI) per 30 days if exact targeting lifts the term's impressions by
+--lift, assuming its tap-through and conversion rates hold.
+
+With --csv the gaps are written as a keyword import file (text,matchType,bid)
+with the term's average CPT as the bid, ready for:
+  asa-cli keywords import --campaign-id N --adgroup-id M --file gaps.csv
+
+Example:
+  asa-cli analyze keyword-gap --campaign-id 123 --min-installs 3 --csv gaps.csv`,
+	RunE: runAnalyzeKeywordGap,
+}
+
+var (
+	gapCampaignID  int64
+	gapRange       string
+	gapMinInstalls int64
+	gapMaxCPI      float64
+	gapLift        float64
+	gapCSV         string
+)
+
+func init() {
+	analyzeKeywordGapCmd.Flags().Int64Var(&gapCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	analyzeKeywordGapCmd.Flags().StringVar(&gapRange, "range", "last-30d", "Date range: "+daterange.Help)
+	analyzeKeywordGapCmd.Flags().Int64Var(&gapMinInstalls, "min-installs", 1, "Only report terms with at least this many installs")
+	analyzeKeywordGapCmd.Flags().Float64Var(&gapMaxCPI, "max-cpi", 0, "Only report terms with a CPI at or below this")
+	analyzeKeywordGapCmd.Flags().Float64Var(&gapLift, "lift", 0.25, "Assumed impression lift from exact targeting, for HEADROOM")
+	analyzeKeywordGapCmd.Flags().StringVar(&gapCSV, "csv", "", "Also write the gaps to this keyword import CSV")
+	analyzeKeywordGapCmd.MarkFlagRequired("campaign-id")
+
+	analyzeCmd.AddCommand(analyzeKeywordGapCmd)
+}
+
+// KeywordGap is a converting search term with no exact-match keyword.
+type KeywordGap struct {
+	Term             string  `json:"term"`
+	SuggestedBid     float64 `json:"suggestedBid"`
+	HeadroomInstalls float64 `json:"headroomInstalls"`
+	HeadroomSpend    float64 `json:"headroomSpend"`
+	aggregate.Metrics
+
+	CPIText  string `json:"-"`
+	Bid      string `json:"-"`
+	Headroom string `json:"-"`
+}
+
+func runAnalyzeKeywordGap(cmd *cobra.Command, args []string) error {
+	if gapLift < 0 {
+		return fmt.Errorf("--lift must not be negative")
+	}
+	rng, err := daterange.Parse(gapRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := services.NewReportingService(client).GetSearchTermReport(gapCampaignID, newRangeReportRequest(rng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting search terms report: %w", err)
+	}
+	keywords, err := services.NewKeywordService(client).FindAllInCampaign(gapCampaignID, models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return fmt.Errorf("listing keywords: %w", err)
+	}
+
+	gaps := findKeywordGaps(aggregate.BySearchTerm(resp), exactKeywordSet(keywords), rng.Days())
+	if len(gaps) == 0 && getFormat() == output.FormatTable {
+		fmt.Printf("No untargeted converting search terms in campaign %d (%s).\n", gapCampaignID, rng)
+		return nil
+	}
+
+	if gapCSV != "" {
+		if err := writeKeywordGapCSV(gapCSV, gaps); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d keywords to %s\n", len(gaps), gapCSV)
+	}
+
+	output.Print(getFormat(), gaps, []output.Column{
+		{Header: "TERM", Field: "Term"},
+		{Header: "IMPRESSIONS", Field: "Impressions"},
+		{Header: "TAPS", Field: "Taps"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "CPI", Field: "CPIText"},
+		{Header: "BID", Field: "Bid"},
+		{Header: "HEADROOM (30D)", Field: "Headroom"},
+	})
+	return nil
+}
+
+// exactKeywordSet returns the normalized texts of the live EXACT keywords.
+func exactKeywordSet(keywords []models.Keyword) map[string]bool {
+	set := map[string]bool{}
+	for _, k := range keywords {
+		if k.Deleted || k.MatchType != models.MatchExact {
+			continue
+		}
+		set[aggregate.NormalizeTerm(k.Text)] = true
+	}
+	return set
+}
+
+func findKeywordGaps(terms []aggregate.SearchTerm, exact map[string]bool, days int) []KeywordGap {
+	gaps := []KeywordGap{}
+	for _, t := range terms {
+		if exact[t.Term] || t.Installs < gapMinInstalls || t.Installs == 0 {
+			continue
+		}
+		if gapMaxCPI > 0 && t.CPI() > gapMaxCPI {
+			continue
+		}
+		g := KeywordGap{Term: t.Term, Metrics: t.Metrics, SuggestedBid: roundCents(t.CPT())}
+		if days > 0 {
+			g.HeadroomInstalls = float64(t.Installs) / float64(days) * 30 * gapLift
+			g.HeadroomSpend = roundCents(g.HeadroomInstalls * t.CPI())
+		}
+		g.CPIText = fmt.Sprintf("%.2f", t.CPI())
+		g.Bid = fmt.Sprintf("%.2f", g.SuggestedBid)
+		g.Headroom = fmt.Sprintf("+%.0f installs / %.2f", g.HeadroomInstalls, g.HeadroomSpend)
+		gaps = append(gaps, g)
+	}
+
+	// Most installs first, then cheapest.
+	sort.SliceStable(gaps, func(i, j int) bool {
+		if gaps[i].Installs != gaps[j].Installs {
+			return gaps[i].Installs > gaps[j].Installs
+		}
+		return gaps[i].CPI() < gaps[j].CPI()
+	})
+	return gaps
+}
+
+// writeKeywordGapCSV writes gaps in the format keywords import reads.
+func writeKeywordGapCSV(path string, gaps []KeywordGap) error {
+	f, err := os.Create(explainPath(path))
+	if err != nil {
+		return fmt.Errorf("creating CSV: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"text", "matchType", "bid"})
+	for _, g := range gaps {
+		w.Write([]string{g.Term, "EXACT", fmt.Sprintf("%.2f", g.SuggestedBid)})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/negeffect"
+	"github.com/trebuhs/asa-cli/internal/neglists"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzeNegativesCmd = &cobra.Command{
+	Use:   "negatives",
+	Short: "Estimate the traffic each negative keyword blocks",
+	Long: `Estimate what a campaign's negative keywords block. Apple reports nothing
+for a blocked term, so the estimate comes from history: search terms a
+negative matches that served before it was added and stopped serving
+afterwards were most likely blocked by it. Their daily average before is
+projected over the days after.
+
+A negative is added no later than its last modification, or than the last
+time a negative keyword list synced it to the campaign, whichever is
+earlier. Only negatives added inside --range, with at least a day on either
+side, can be estimated.
+
+A negative is flagged as possibly over-broad when the terms it stopped had
+installs, or when it is a broad match negative that stopped at least
+--min-terms terms besides its own text.
+
+Example:
+  asa-cli analyze negatives --campaign-id 123 --range last-30d
+  asa-cli analyze negatives --campaign-id 123 --over-broad-only -o json`,
+	RunE: runAnalyzeNegatives,
+}
+
+var (
+	negEffectCampaignID    int64
+	negEffectRange         string
+	negEffectMinTerms      int
+	negEffectOverBroadOnly bool
+	negEffectLimit         int
+)
+
+func init() {
+	analyzeNegativesCmd.Flags().Int64Var(&negEffectCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	analyzeNegativesCmd.Flags().StringVar(&negEffectRange, "range", "last-30d", "Date range: "+daterange.Help)
+	analyzeNegativesCmd.Flags().IntVar(&negEffectMinTerms, "min-terms", 3, "Flag broad negatives that stopped this many other terms (0 to disable)")
+	analyzeNegativesCmd.Flags().BoolVar(&negEffectOverBroadOnly, "over-broad-only", false, "Only report negatives flagged as possibly over-broad")
+	analyzeNegativesCmd.Flags().IntVar(&negEffectLimit, "limit", 50, "Maximum negatives to report (0 for all)")
+	analyzeNegativesCmd.MarkFlagRequired("campaign-id")
+
+	analyzeCmd.AddCommand(analyzeNegativesCmd)
+}
+
+// NegativeEffect is a negative keyword's estimated effect, as reported by
+// analyze negatives.
+type NegativeEffect struct {
+	negeffect.Effect
+	IsEstimate bool `json:"isEstimate"`
+
+	Level        string `json:"-"`
+	AddedText    string `json:"-"`
+	StoppedText  string `json:"-"`
+	ServingText  string `json:"-"`
+	EstImpText   string `json:"-"`
+	EstSpendText string `json:"-"`
+}
+
+func runAnalyzeNegatives(cmd *cobra.Command, args []string) error {
+	if negEffectMinTerms < 0 {
+		return fmt.Errorf("--min-terms must not be negative")
+	}
+	rng, err := daterange.Parse(negEffectRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	kw := services.NewKeywordService(client)
+	campaignNegs, err := kw.FindAllCampaignNegativeKeywords(negEffectCampaignID, models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("fetching campaign negative keywords: %w", err)
+	}
+	adGroupNegs, err := kw.FindAllAdGroupNegativeKeywordsInCampaign(negEffectCampaignID, models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("fetching ad group negative keywords: %w", err)
+	}
+	negatives := negativesAdded(append(campaignNegs, adGroupNegs...), negEffectCampaignID)
+
+	req := newRangeReportRequest(rng, 1000)
+	req.Granularity = models.GranularityDaily
+	report := &models.ReportingDataResponse{}
+	err = services.NewReportingService(client).EachPage(negEffectCampaignID, "searchterms", req, func(page *models.ReportingDataResponse) error {
+		report.Row = append(report.Row, page.Row...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("getting daily search terms report: %w", err)
+	}
+	terms := negeffect.Terms(report)
+
+	effects, skipped := negeffect.Analyze(negatives, terms, rng, negeffect.Options{MinTerms: negEffectMinTerms})
+	rows := []NegativeEffect{}
+	for _, e := range effects {
+		if negEffectOverBroadOnly && !e.OverBroad {
+			continue
+		}
+		rows = append(rows, negativeEffectRow(e))
+	}
+	if negEffectLimit > 0 && len(rows) > negEffectLimit {
+		rows = rows[:negEffectLimit]
+	}
+	if len(rows) == 0 && getFormat() == output.FormatTable {
+		fmt.Printf("No negatives to report in campaign %d (%s, %d added outside the range).\n", negEffectCampaignID, rng, len(skipped))
+		return nil
+	}
+
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "NEGATIVE", Field: "Text"},
+		{Header: "MATCH", Field: "MatchType"},
+		{Header: "LEVEL", Field: "Level"},
+		{Header: "ADDED", Field: "AddedText"},
+		{Header: "STOPPED", Field: "StoppedText"},
+		{Header: "STILL SERVING", Field: "ServingText"},
+		{Header: "EST. IMPRESSIONS", Field: "EstImpText"},
+		{Header: "EST. SPEND", Field: "EstSpendText"},
+		{Header: "FLAG", Field: "Reason"},
+	})
+	if getFormat() == output.FormatTable {
+		fmt.Printf("\nEstimates over the days after each negative was added (%s). %d negatives added outside the range were skipped.\n", rng, len(skipped))
+	}
+	return nil
+}
+
+// negativesAdded drops deleted negatives and dates the rest by the earlier of
+// their modification time and their last sync from a negative keyword list.
+func negativesAdded(keywords []models.NegativeKeyword, campaignID int64) []negeffect.Negative {
+	synced := map[string]time.Time{}
+	if store, err := neglists.Load(profileName); err == nil {
+		for _, l := range store.All() {
+			app, ok := l.Applied[campaignID]
+			if !ok {
+				continue
+			}
+			for _, t := range app.Terms {
+				key := strings.ToUpper(l.MatchType) + "|" + strings.ToLower(t)
+				if at, ok := synced[key]; !ok || app.AppliedAt.Before(at) {
+					synced[key] = app.AppliedAt
+				}
+			}
+		}
+	}
+
+	var out []negeffect.Negative
+	for _, k := range keywords {
+		if k.Deleted || strings.EqualFold(string(k.Status), "PAUSED") {
+			continue
+		}
+		added, _ := daterange.ParseAPITime(k.ModificationTime)
+		if at, ok := synced[strings.ToUpper(string(k.MatchType))+"|"+strings.ToLower(k.Text)]; ok && !at.IsZero() && (added.IsZero() || at.Before(added)) {
+			added = at
+		}
+		if added.IsZero() {
+			continue
+		}
+		out = append(out, negeffect.Negative{ID: k.ID, AdGroupID: k.AdGroupID, Text: k.Text, MatchType: k.MatchType, Added: added})
+	}
+	return out
+}
+
+func negativeEffectRow(e negeffect.Effect) NegativeEffect {
+	e.Estimate.Spend = roundCents(e.Estimate.Spend)
+	e.Before.Spend = roundCents(e.Before.Spend)
+	r := NegativeEffect{
+		Effect:       e,
+		IsEstimate:   true,
+		Level:        "campaign",
+		AddedText:    e.Added.Local().Format(daterange.DateFormat),
+		StoppedText:  fmt.Sprintf("%d/%d", len(e.Stopped), e.Matched),
+		ServingText:  fmt.Sprintf("%d", len(e.StillServing)),
+		EstImpText:   fmt.Sprintf("~%d", e.Estimate.Impressions),
+		EstSpendText: fmt.Sprintf("~%.2f", e.Estimate.Spend),
+	}
+	if e.AdGroupID != 0 {
+		r.Level = fmt.Sprintf("ad group %d", e.AdGroupID)
+	}
+	return r
+}
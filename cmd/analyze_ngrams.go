@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/ngram"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzeNgramsCmd = &cobra.Command{
+	Use:   "ngrams",
+	Short: "Total search-term spend and installs per 1-3 word n-gram",
+	Long: `Split a campaign's search terms into 1, 2, and 3 word n-grams and total their
+spend and installs, to find tokens that waste spend across many terms. Each
+term counts once toward every n-gram it contains; common filler words are
+left out of 1-grams.
+
+An n-gram is flagged as a negative keyword candidate when it spent at least
+--min-spend without an install, or its CPI is above --max-cpi (by default
+twice the campaign's CPI over the range).
+
+Example:
+  asa-cli analyze ngrams --campaign-id 123 --range last-30d
+  asa-cli analyze ngrams --campaign-id 123 --waste-only -o json`,
+	RunE: runAnalyzeNgrams,
+}
+
+var (
+	ngramCampaignID int64
+	ngramRange      string
+	ngramMaxN       int
+	ngramMinTerms   int
+	ngramMinSpend   float64
+	ngramMaxCPI     float64
+	ngramWasteOnly  bool
+	ngramLimit      int
+)
+
+func init() {
+	analyzeNgramsCmd.Flags().Int64Var(&ngramCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	analyzeNgramsCmd.Flags().StringVar(&ngramRange, "range", "last-30d", "Date range: "+daterange.Help)
+	analyzeNgramsCmd.Flags().IntVar(&ngramMaxN, "n", ngram.MaxN, "Longest n-gram, in words (1-3)")
+	analyzeNgramsCmd.Flags().IntVar(&ngramMinTerms, "min-terms", 2, "Only report n-grams found in at least this many terms")
+	analyzeNgramsCmd.Flags().Float64Var(&ngramMinSpend, "min-spend", 5, "Flag n-grams with no installs once they spent this much")
+	analyzeNgramsCmd.Flags().Float64Var(&ngramMaxCPI, "max-cpi", 0, "Flag n-grams with a CPI above this (default: 2x the campaign's CPI)")
+	analyzeNgramsCmd.Flags().BoolVar(&ngramWasteOnly, "waste-only", false, "Only report flagged n-grams")
+	analyzeNgramsCmd.Flags().IntVar(&ngramLimit, "limit", 50, "Maximum n-grams to report (0 for all)")
+	analyzeNgramsCmd.MarkFlagRequired("campaign-id")
+
+	analyzeCmd.AddCommand(analyzeNgramsCmd)
+}
+
+// NgramStat is an n-gram's search-term totals and whether it wastes spend.
+type NgramStat struct {
+	ngram.Gram
+	CPIValue float64 `json:"cpi"`
+	Waste    bool    `json:"waste"`
+	Reason   string  `json:"reason,omitempty"`
+
+	SpendText string `json:"-"`
+	CPIText   string `json:"-"`
+	CRText    string `json:"-"`
+}
+
+func runAnalyzeNgrams(cmd *cobra.Command, args []string) error {
+	if ngramMaxN < 1 || ngramMaxN > ngram.MaxN {
+		return fmt.Errorf("--n must be between 1 and %d", ngram.MaxN)
+	}
+	rng, err := daterange.Parse(ngramRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	resp, err := services.NewReportingService(client).GetSearchTermReport(ngramCampaignID, newRangeReportRequest(rng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting search terms report: %w", err)
+	}
+
+	terms := aggregate.BySearchTerm(resp)
+	var total aggregate.Metrics
+	for _, t := range terms {
+		total.Merge(t.Metrics)
+	}
+	maxCPI := ngramMaxCPI
+	if maxCPI == 0 {
+		maxCPI = 2 * total.CPI()
+	}
+
+	stats := ngramStats(ngram.Count(terms, ngramMaxN), maxCPI)
+	if ngramLimit > 0 && len(stats) > ngramLimit {
+		stats = stats[:ngramLimit]
+	}
+	if len(stats) == 0 && getFormat() == output.FormatTable {
+		fmt.Printf("No n-grams to report in campaign %d (%s, %d search terms).\n", ngramCampaignID, rng, len(terms))
+		return nil
+	}
+
+	output.Print(getFormat(), stats, []output.Column{
+		{Header: "NGRAM", Field: "Text"},
+		{Header: "TERMS", Field: "Terms"},
+		{Header: "IMPRESSIONS", Field: "Impressions"},
+		{Header: "TAPS", Field: "Taps"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "SPEND", Field: "SpendText"},
+		{Header: "CPI", Field: "CPIText"},
+		{Header: "CR", Field: "CRText"},
+		{Header: "FLAG", Field: "Reason"},
+	})
+	if getFormat() == output.FormatTable {
+		fmt.Printf("\n%d search terms, %.2f spend, CPI %.2f (%s)\n", len(terms), total.Spend, total.CPI(), rng)
+	}
+	return nil
+}
+
+// ngramStats filters grams by --min-terms and --waste-only and flags the
+// wasteful ones.
+func ngramStats(grams []ngram.Gram, maxCPI float64) []NgramStat {
+	stats := []NgramStat{}
+	for _, g := range grams {
+		if g.Terms < ngramMinTerms {
+			continue
+		}
+		s := NgramStat{Gram: g, CPIValue: roundCents(g.CPI())}
+		switch {
+		case g.Installs == 0 && g.Spend >= ngramMinSpend && g.Spend > 0:
+			s.Waste, s.Reason = true, "no installs"
+		case g.Installs > 0 && maxCPI > 0 && g.CPI() > maxCPI:
+			s.Waste, s.Reason = true, fmt.Sprintf("CPI > %.2f", maxCPI)
+		}
+		if ngramWasteOnly && !s.Waste {
+			continue
+		}
+		s.SpendText = fmt.Sprintf("%.2f", g.Spend)
+		s.CPIText = fmt.Sprintf("%.2f", g.CPI())
+		if g.Installs == 0 {
+			s.CPIText = "-"
+		}
+		s.CRText = fmt.Sprintf("%.2f%%", g.ConversionRate()*100)
+		stats = append(stats, s)
+	}
+	return stats
+}
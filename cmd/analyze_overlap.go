@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/progress"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzeTermOverlapCmd = &cobra.Command{
+	Use:   "term-overlap",
+	Short: "Find search terms triggering in more than one campaign",
+	Long: `Pull search-term reports for several campaigns and list the terms that
+triggered in more than one of them (self-competition), with the spend split
+between campaigns.
+
+For each overlapping term the campaign with the most installs (then lowest
+CPI) is kept, and exact-match campaign negatives are suggested for the others
+so the traffic is isolated in one place.`,
+	RunE: runAnalyzeTermOverlap,
+}
+
+var (
+	overlapCampaigns string
+	overlapRange     string
+	overlapMinSpend  float64
+	overlapTag       string
+)
+
+func init() {
+	analyzeTermOverlapCmd.Flags().StringVar(&overlapCampaigns, "campaigns", "", "Comma-separated campaign IDs (at least two)")
+	analyzeTermOverlapCmd.Flags().StringVar(&overlapTag, "tag", "", "Compare the campaigns with this local tag")
+	analyzeTermOverlapCmd.Flags().StringVar(&overlapRange, "range", "last-30d", "Date range: "+daterange.Help)
+	analyzeTermOverlapCmd.Flags().Float64Var(&overlapMinSpend, "min-spend", 0, "Only report terms with at least this much combined spend")
+	analyzeTermOverlapCmd.MarkFlagsOneRequired("campaigns", "tag")
+	analyzeTermOverlapCmd.MarkFlagsMutuallyExclusive("campaigns", "tag")
+
+	analyzeCmd.AddCommand(analyzeTermOverlapCmd)
+}
+
+// TermOverlap is a search term seen in more than one campaign.
+type TermOverlap struct {
+	Term       string              `json:"term"`
+	TotalSpend float64             `json:"totalSpend"`
+	Campaigns  []TermCampaignSplit `json:"campaigns"`
+	KeepIn     int64               `json:"keepIn"`
+	NegateIn   []int64             `json:"negateIn"`
+
+	Split  string `json:"-"`
+	Negate string `json:"-"`
+	Spend  string `json:"-"`
+}
+
+// TermCampaignSplit is one campaign's share of an overlapping term.
+type TermCampaignSplit struct {
+	CampaignID int64   `json:"campaignId"`
+	Share      float64 `json:"share"`
+	aggregate.Metrics
+}
+
+func runAnalyzeTermOverlap(cmd *cobra.Command, args []string) error {
+	var ids []int64
+	var err error
+	if overlapTag != "" {
+		ids, err = campaignIDsForTag(overlapTag)
+	} else {
+		ids, err = parseIDList(overlapCampaigns)
+	}
+	if err != nil {
+		return err
+	}
+	if len(ids) < 2 {
+		return fmt.Errorf("term-overlap needs at least two campaigns")
+	}
+
+	rng, err := daterange.Parse(overlapRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	svc := services.NewReportingService(client)
+	byTerm := map[string][]TermCampaignSplit{}
+	bar := progress.New("Pulling search terms", len(ids))
+	for _, id := range ids {
+		resp, err := svc.GetSearchTermReport(id, newRangeReportRequest(rng, 1000))
+		if err != nil {
+			bar.Done()
+			return fmt.Errorf("getting search terms report for campaign %d: %w", id, err)
+		}
+		for _, t := range aggregate.BySearchTerm(resp) {
+			byTerm[t.Term] = append(byTerm[t.Term], TermCampaignSplit{CampaignID: id, Metrics: t.Metrics})
+		}
+		bar.Add(1)
+	}
+	bar.Done()
+
+	overlaps := findTermOverlaps(byTerm, overlapMinSpend)
+	if len(overlaps) == 0 && getFormat() == output.FormatTable {
+		fmt.Printf("No search terms triggered in more than one campaign (%s).\n", rng)
+		return nil
+	}
+
+	output.Print(getFormat(), overlaps, []output.Column{
+		{Header: "TERM", Field: "Term"},
+		{Header: "SPEND", Field: "Spend"},
+		{Header: "SPLIT (CAMPAIGN: SPEND SHARE)", Field: "Split"},
+		{Header: "KEEP IN", Field: "KeepIn"},
+		{Header: "NEGATE IN", Field: "Negate"},
+	})
+	return nil
+}
+
+func findTermOverlaps(byTerm map[string][]TermCampaignSplit, minSpend float64) []TermOverlap {
+	overlaps := []TermOverlap{}
+	for term, splits := range byTerm {
+		if len(splits) < 2 {
+			continue
+		}
+		var total float64
+		for _, s := range splits {
+			total += s.Spend
+		}
+		if total < minSpend {
+			continue
+		}
+		for i := range splits {
+			if total > 0 {
+				splits[i].Share = splits[i].Spend / total
+			}
+		}
+
+		// Keep the term where it converts best: most installs, then lowest CPI.
+		sort.SliceStable(splits, func(i, j int) bool {
+			if splits[i].Installs != splits[j].Installs {
+				return splits[i].Installs > splits[j].Installs
+			}
+			return splits[i].CPI() < splits[j].CPI()
+		})
+
+		o := TermOverlap{Term: term, TotalSpend: total, Campaigns: splits, KeepIn: splits[0].CampaignID}
+		var parts, negate []string
+		for _, s := range splits {
+			parts = append(parts, fmt.Sprintf("%d: %.2f (%.0f%%)", s.CampaignID, s.Spend, s.Share*100))
+		}
+		for _, s := range splits[1:] {
+			o.NegateIn = append(o.NegateIn, s.CampaignID)
+			negate = append(negate, fmt.Sprintf("%d", s.CampaignID))
+		}
+		o.Split = strings.Join(parts, " | ")
+		o.Negate = strings.Join(negate, ",")
+		o.Spend = fmt.Sprintf("%.2f", total)
+		overlaps = append(overlaps, o)
+	}
+	sort.Slice(overlaps, func(i, j int) bool { return overlaps[i].TotalSpend > overlaps[j].TotalSpend })
+	return overlaps
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/intraday"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzePacingCmd = &cobra.Command{
+	Use:   "pacing",
+	Short: "Chart a campaign's spend through the day against even pacing",
+	Long: `Pull a campaign's hourly spend (in the org's time zone) and show how it
+accumulated through the day against an even pace of the daily budget, one
+twenty-fourth per hour. Days where the budget was spent before the last hour are
+flagged with the hour it ran out.
+
+--date takes a day or a range of up to 30 days within the last 30 days, Apple's
+limit for hourly data. Each day is fetched separately; over several days the
+curve shows the average day.
+
+Example:
+  asa-cli analyze pacing --campaign-id 123
+  asa-cli analyze pacing --campaign-id 123 --date last-7d`,
+	RunE: runAnalyzePacing,
+}
+
+var (
+	pacingCampaignID int64
+	pacingDate       string
+)
+
+// pacingMaxDays is how far back Apple serves hourly reports.
+const pacingMaxDays = 30
+
+// exhaustedShare of the daily budget counts as the budget having run out.
+const exhaustedShare = 0.98
+
+func init() {
+	analyzePacingCmd.Flags().Int64Var(&pacingCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	analyzePacingCmd.Flags().StringVar(&pacingDate, "date", "yesterday", "Day or range: "+daterange.Help)
+	analyzePacingCmd.MarkFlagRequired("campaign-id")
+
+	analyzeCmd.AddCommand(analyzePacingCmd)
+}
+
+// HourPacing is the spend in one hour of the (average) day.
+type HourPacing struct {
+	Hour       string  `json:"hour"`
+	Spend      float64 `json:"spend"`
+	Cumulative float64 `json:"cumulative"`
+	EvenPace   float64 `json:"evenPace"`
+	// OfBudget is Cumulative as a share of the daily budget.
+	OfBudget float64 `json:"ofBudget,omitempty"`
+
+	OfBudgetText string `json:"-"`
+}
+
+// DayPacing is one day's total and when its budget ran out, if it did.
+type DayPacing struct {
+	Date      string  `json:"date"`
+	Spend     float64 `json:"spend"`
+	Exhausted string  `json:"exhaustedAt,omitempty"`
+}
+
+// PacingReport is the output of analyze pacing.
+type PacingReport struct {
+	CampaignID   int64        `json:"campaignId"`
+	CampaignName string       `json:"campaignName"`
+	Period       string       `json:"period"`
+	DailyBudget  float64      `json:"dailyBudget,omitempty"`
+	Currency     string       `json:"currency,omitempty"`
+	Hours        []HourPacing `json:"hours"`
+	Days         []DayPacing  `json:"days"`
+}
+
+func runAnalyzePacing(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	rng, err := daterange.Parse(pacingDate, now)
+	if err != nil {
+		return err
+	}
+	if rng.Start.Before(now.AddDate(0, 0, -pacingMaxDays)) {
+		return fmt.Errorf("hourly data is only available for the last %d days", pacingMaxDays)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	warnOrgTimeZone(client)
+	c, err := services.NewCampaignService(client).Get(pacingCampaignID)
+	if err != nil {
+		return fmt.Errorf("getting campaign %d: %w", pacingCampaignID, err)
+	}
+	report := &PacingReport{CampaignID: c.ID, CampaignName: c.Name, Period: rng.String(), Days: []DayPacing{}}
+	if c.DailyBudgetAmount != nil {
+		report.DailyBudget = aggregate.Amount(*c.DailyBudgetAmount)
+		report.Currency = c.DailyBudgetAmount.Currency
+	}
+
+	reports := services.NewReportingService(client)
+	var hourly [24]float64
+	for d := rng.Start; !d.After(rng.End); d = d.AddDate(0, 0, 1) {
+		spend, err := hourlySpend(reports, d)
+		if err != nil {
+			return err
+		}
+		day := DayPacing{Date: d.Format(daterange.DateFormat)}
+		for h, v := range spend {
+			hourly[h] += v
+			day.Spend += v
+			if report.DailyBudget > 0 && day.Exhausted == "" && h < 23 && day.Spend >= exhaustedShare*report.DailyBudget {
+				day.Exhausted = fmt.Sprintf("%02d:00", h)
+			}
+		}
+		day.Spend = roundCents(day.Spend)
+		report.Days = append(report.Days, day)
+	}
+
+	days := float64(len(report.Days))
+	var total float64
+	for _, v := range hourly {
+		total += v / days
+	}
+	pace := report.DailyBudget
+	if pace == 0 {
+		pace = total
+	}
+	cumulative := 0.0
+	for h, v := range hourly {
+		cumulative += v / days
+		row := HourPacing{
+			Hour:       fmt.Sprintf("%02d:00", h),
+			Spend:      roundCents(v / days),
+			Cumulative: roundCents(cumulative),
+			EvenPace:   roundCents(pace * float64(h+1) / 24),
+		}
+		if report.DailyBudget > 0 {
+			row.OfBudget = cumulative / report.DailyBudget
+			row.OfBudgetText = fmt.Sprintf("%.0f%%", row.OfBudget*100)
+		}
+		report.Hours = append(report.Hours, row)
+	}
+
+	if getFormat() != output.FormatTable {
+		output.Print(getFormat(), report, nil)
+		return nil
+	}
+	printPacing(report)
+	return nil
+}
+
+// hourlySpend fetches one day's spend per hour for the campaign.
+func hourlySpend(reports *services.ReportingService, day time.Time) ([24]float64, error) {
+	days, _, err := hourlyByCampaign(reports, day)
+	if err != nil {
+		return [24]float64{}, err
+	}
+	if d := days[pacingCampaignID]; d != nil {
+		return d.Spend, nil
+	}
+	return [24]float64{}, nil
+}
+
+// hourlyByCampaign fetches one day's spend and installs per hour for every
+// campaign, and the last hour with data (-1 if none).
+func hourlyByCampaign(reports *services.ReportingService, day time.Time) (map[int64]*intraday.Day, int, error) {
+	date := day.Format(daterange.DateFormat)
+	req := &models.ReportRequest{
+		StartTime:   date,
+		EndTime:     date,
+		Granularity: "HOURLY",
+		TimeZone:    "ORTZ",
+		Selector: &models.Selector{
+			Pagination: models.SelectorPagination{Limit: maxPageSize},
+		},
+	}
+	resp, err := reports.GetCampaignReport(req)
+	if err != nil {
+		return nil, -1, fmt.Errorf("getting hourly report for %s: %w", date, err)
+	}
+	days := map[int64]*intraday.Day{}
+	last := -1
+	for _, row := range resp.Row {
+		id := aggregate.MetaInt64(row.Metadata, "campaignId")
+		for _, g := range row.Granularity {
+			h, ok := reportHour(g.Date)
+			if !ok || g.Metrics == nil {
+				continue
+			}
+			d := days[id]
+			if d == nil {
+				d = &intraday.Day{}
+				days[id] = d
+			}
+			d.Spend[h] += aggregate.Amount(g.Metrics.LocalSpend)
+			d.Installs[h] += g.Metrics.TotalInstalls
+			last = max(last, h)
+		}
+	}
+	return days, last, nil
+}
+
+// reportHour reads the hour from an hourly bucket's date, e.g.
+// "2024-01-31 13:00" or "2024-01-31T13:00:00".
+func reportHour(date string) (int, bool) {
+	i := strings.IndexAny(date, " T")
+	if i < 0 || len(date) < i+3 {
+		return 0, false
+	}
+	h, err := strconv.Atoi(date[i+1 : i+3])
+	if err != nil || h < 0 || h > 23 {
+		return 0, false
+	}
+	return h, true
+}
+
+func printPacing(r *PacingReport) {
+	budget := "no daily budget; even pace is of the day's spend"
+	if r.DailyBudget > 0 {
+		budget = fmt.Sprintf("daily budget %.2f %s", r.DailyBudget, r.Currency)
+	}
+	day := "day"
+	if len(r.Days) > 1 {
+		day = fmt.Sprintf("average of %d days", len(r.Days))
+	}
+	fmt.Printf("%s (%d), %s, %s — %s\n\n", r.CampaignName, r.CampaignID, r.Period, day, budget)
+
+	output.Print(output.FormatTable, r.Hours, []output.Column{
+		{Header: "HOUR", Field: "Hour"},
+		{Header: "SPEND", Field: "Spend"},
+		{Header: "CUMULATIVE", Field: "Cumulative"},
+		{Header: "EVEN PACE", Field: "EvenPace"},
+		{Header: "OF BUDGET", Field: "OfBudgetText"},
+	})
+
+	labels := make([]string, len(r.Hours))
+	values := make([]float64, len(r.Hours))
+	targets := make([]float64, len(r.Hours))
+	for i, h := range r.Hours {
+		labels[i], values[i], targets[i] = h.Hour, h.Cumulative, h.EvenPace
+	}
+	fmt.Println("\nCumulative spend (the mark is the even pace)")
+	fmt.Print(output.TargetBarChart(labels, values, targets, 40))
+
+	for _, d := range r.Days {
+		if d.Exhausted != "" {
+			warnf("%s budget ran out during %s (spent %.2f).", d.Date, d.Exhausted, d.Spend)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+	"github.com/trebuhs/asa-cli/internal/simulate"
+)
+
+var analyzeSimulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Estimate the impact of a keyword bid change",
+	Long: `Estimate daily impressions, taps, and spend for a keyword at a new bid, from
+its performance over the last --days days and Apple's bid recommendation.
+
+The history is read from a live keyword report each run, not from the local
+warehouse: Apple's bid recommendation only comes with live reports.
+
+This is an ESTIMATE from a simple model, not a forecast: it assumes the
+tap-through rate holds, impressions follow a power law in the bid ratio, and
+the average CPT moves with the square root of the bid ratio. The low, mid, and
+high rows use increasingly responsive impression volume. Auction dynamics,
+competitors, and seasonality are not modeled.
+
+Example:
+  asa-cli analyze simulate --campaign-id 123 --keyword-id 456 --bid 2.00`,
+	RunE: runAnalyzeSimulate,
+}
+
+var (
+	simCampaignID int64
+	simKeywordID  int64
+	simBid        float64
+	simDays       int
+)
+
+func init() {
+	analyzeSimulateCmd.Flags().Int64Var(&simCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	analyzeSimulateCmd.Flags().Int64Var(&simKeywordID, "keyword-id", 0, "Keyword ID (required)")
+	analyzeSimulateCmd.Flags().Float64Var(&simBid, "bid", 0, "Bid to simulate (required)")
+	analyzeSimulateCmd.Flags().IntVar(&simDays, "days", 30, "Days of history to model from")
+	analyzeSimulateCmd.MarkFlagRequired("campaign-id")
+	analyzeSimulateCmd.MarkFlagRequired("keyword-id")
+	analyzeSimulateCmd.MarkFlagRequired("bid")
+
+	analyzeCmd.AddCommand(analyzeSimulateCmd)
+}
+
+type simulationRow struct {
+	Scenario    string  `json:"scenario"`
+	Bid         float64 `json:"bid"`
+	Impressions float64 `json:"impressionsPerDay"`
+	Taps        float64 `json:"tapsPerDay"`
+	Spend       float64 `json:"spendPerDay"`
+	CPT         float64 `json:"avgCPT"`
+	TapsChange  string  `json:"-"`
+	SpendChange string  `json:"-"`
+}
+
+func runAnalyzeSimulate(cmd *cobra.Command, args []string) error {
+	if simDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+	if err := checkBidLimit(strconv.FormatFloat(simBid, 'f', 2, 64)); err != nil {
+		return err
+	}
+	rng, err := daterange.Parse(fmt.Sprintf("last-%dd", simDays), time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	req := newRangeReportRequest(rng, 1)
+	req.Selector.Conditions = []models.Condition{{
+		Field:    "keywordId",
+		Operator: "IN",
+		Values:   []string{strconv.FormatInt(simKeywordID, 10)},
+	}}
+	resp, err := services.NewReportingService(client).GetKeywordReport(simCampaignID, req)
+	if err != nil {
+		return fmt.Errorf("getting keyword report: %w", err)
+	}
+	if len(resp.Row) == 0 {
+		return fmt.Errorf("keyword %d has no data in campaign %d over %s", simKeywordID, simCampaignID, rng)
+	}
+	row := resp.Row[0]
+	adGroupID := aggregate.MetaInt64(row.Metadata, "adGroupId")
+
+	kw, err := services.NewKeywordService(client).Get(simCampaignID, adGroupID, simKeywordID)
+	if err != nil {
+		return fmt.Errorf("getting keyword: %w", err)
+	}
+	if kw.BidAmount == nil {
+		return fmt.Errorf("keyword %d has no bid of its own; simulate the ad group default bid instead", simKeywordID)
+	}
+
+	m := aggregate.RowTotals(row)
+	days := float64(simDays)
+	base := simulate.Baseline{
+		Bid:         aggregate.Amount(*kw.BidAmount),
+		Impressions: float64(m.Impressions) / days,
+		Taps:        float64(m.Taps) / days,
+		Spend:       m.Spend / days,
+	}
+	if row.Insights != nil && row.Insights.BidRecommendation != nil && row.Insights.BidRecommendation.SuggestedBidAmount != nil {
+		base.SuggestedBid = aggregate.Amount(*row.Insights.BidRecommendation.SuggestedBidAmount)
+	}
+
+	outcomes, err := simulate.Bid(base, simBid)
+	if err != nil {
+		return err
+	}
+
+	rows := []simulationRow{{
+		Scenario:    "current (observed)",
+		Bid:         base.Bid,
+		Impressions: roundCents(base.Impressions),
+		Taps:        roundCents(base.Taps),
+		Spend:       roundCents(base.Spend),
+		CPT:         roundCents(base.CPT()),
+	}}
+	for _, o := range outcomes {
+		rows = append(rows, simulationRow{
+			Scenario:    "estimate (" + o.Label + ")",
+			Bid:         simBid,
+			Impressions: roundCents(o.Impressions),
+			Taps:        roundCents(o.Taps),
+			Spend:       roundCents(o.Spend),
+			CPT:         roundCents(o.CPT),
+			TapsChange:  pctChange(o.Taps, base.Taps),
+			SpendChange: pctChange(o.Spend, base.Spend),
+		})
+	}
+
+	fmt.Fprintf(os.Stderr, "ESTIMATE for keyword %q (%d), daily averages over %s (%s)\n",
+		aggregate.MetaString(row.Metadata, "keyword"), simKeywordID, rng, m.Currency)
+	if base.SuggestedBid > 0 {
+		fmt.Fprintf(os.Stderr, "Apple suggested bid: %.2f\n", base.SuggestedBid)
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "SCENARIO", Field: "Scenario"},
+		{Header: "BID", Field: "Bid"},
+		{Header: "IMPRESSIONS/DAY", Field: "Impressions"},
+		{Header: "TAPS/DAY", Field: "Taps"},
+		{Header: "SPEND/DAY", Field: "Spend"},
+		{Header: "AVG CPT", Field: "CPT"},
+		{Header: "TAPS CHANGE", Field: "TapsChange"},
+		{Header: "SPEND CHANGE", Field: "SpendChange"},
+	})
+	fmt.Fprintln(os.Stderr, "These figures are model estimates, not guarantees; see 'asa-cli analyze simulate --help'.")
+	return nil
+}
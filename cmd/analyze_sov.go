@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var analyzeSOVCmd = &cobra.Command{
+	Use:   "sov",
+	Short: "Impression share trend for a search term",
+	Long: `Generate an impression share (custom) report and show the low/high impression
+share trend per country for a search term, with a sparkline per country.
+
+Impression share reports are generated asynchronously by Apple; this command
+waits for the report to complete (see --timeout).`,
+	RunE: runAnalyzeSOV,
+}
+
+var (
+	sovKeyword     string
+	sovRange       string
+	sovCountries   string
+	sovGranularity models.Granularity
+	sovCSV         string
+	sovTimeout     time.Duration
+)
+
+func init() {
+	analyzeSOVCmd.Flags().StringVar(&sovKeyword, "keyword", "", "Search term to analyze (required)")
+	analyzeSOVCmd.Flags().StringVar(&sovRange, "range", "last-30d", "Date range: "+daterange.Help)
+	analyzeSOVCmd.Flags().StringVar(&sovCountries, "countries", "", "Comma-separated country codes to include (default: all)")
+	enumVar(analyzeSOVCmd.Flags(), &sovGranularity, "granularity", models.GranularityDaily, models.ParseGranularity, "Granularity: DAILY or WEEKLY")
+	analyzeSOVCmd.Flags().StringVar(&sovCSV, "csv", "", "Also write the trend rows to this CSV file")
+	analyzeSOVCmd.Flags().DurationVar(&sovTimeout, "timeout", 5*time.Minute, "How long to wait for the report")
+	analyzeSOVCmd.MarkFlagRequired("keyword")
+
+	analyzeCmd.AddCommand(analyzeSOVCmd)
+}
+
+// sovTrend is the impression share series for one country.
+type sovTrend struct {
+	Country    string                      `json:"countryOrRegion"`
+	Points     []models.ImpressionShareRow `json:"points"`
+	LowTrend   string                      `json:"-"`
+	HighTrend  string                      `json:"-"`
+	LatestLow  string                      `json:"-"`
+	LatestHigh string                      `json:"-"`
+}
+
+func runAnalyzeSOV(cmd *cobra.Command, args []string) error {
+	rng, err := daterange.Parse(sovRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	req := &models.CustomReportRequest{
+		Name:        fmt.Sprintf("asa-cli sov %s %s", sovKeyword, time.Now().Format("20060102150405")),
+		StartTime:   rng.StartDate(),
+		EndTime:     rng.EndDate(),
+		Granularity: sovGranularity,
+	}
+	if sovCountries != "" {
+		sel := models.NewSelector(1000, 0)
+		sel.Conditions = []models.Condition{{
+			Field:    "countryOrRegion",
+			Operator: "IN",
+			Values:   strings.Split(strings.ToUpper(sovCountries), ","),
+		}}
+		req.Selector = &sel
+	}
+
+	svc := services.NewCustomReportService(client)
+	report, err := svc.Create(req)
+	if err != nil {
+		return fmt.Errorf("creating impression share report: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Waiting for impression share report %d...\n", report.ID)
+
+	report, err = svc.WaitForCompletion(report.ID, 5*time.Second, sovTimeout)
+	if err != nil {
+		return err
+	}
+	rows, err := svc.Download(report)
+	if err != nil {
+		return err
+	}
+
+	trends := buildSOVTrends(rows, sovKeyword)
+	if len(trends) == 0 {
+		return fmt.Errorf("no impression share data for %q in %s", sovKeyword, rng)
+	}
+
+	if sovCSV != "" {
+		if err := writeSOVCSV(sovCSV, trends); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", sovCSV)
+	}
+
+	output.Print(getFormat(), trends, []output.Column{
+		{Header: "COUNTRY", Field: "Country"},
+		{Header: "LOW IS", Field: "LatestLow"},
+		{Header: "HIGH IS", Field: "LatestHigh"},
+		{Header: "LOW TREND", Field: "LowTrend"},
+		{Header: "HIGH TREND", Field: "HighTrend"},
+	})
+	return nil
+}
+
+func buildSOVTrends(rows []models.ImpressionShareRow, keyword string) []sovTrend {
+	byCountry := map[string][]models.ImpressionShareRow{}
+	for _, row := range rows {
+		if !strings.EqualFold(strings.TrimSpace(row.SearchTerm), strings.TrimSpace(keyword)) {
+			continue
+		}
+		byCountry[row.CountryOrRegion] = append(byCountry[row.CountryOrRegion], row)
+	}
+
+	var trends []sovTrend
+	for country, points := range byCountry {
+		sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+		var low, high []float64
+		for _, p := range points {
+			low = append(low, p.LowImpressionShare)
+			high = append(high, p.HighImpressionShare)
+		}
+		last := points[len(points)-1]
+		trends = append(trends, sovTrend{
+			Country:    country,
+			Points:     points,
+			LowTrend:   output.Sparkline(low),
+			HighTrend:  output.Sparkline(high),
+			LatestLow:  fmt.Sprintf("%.0f%%", last.LowImpressionShare*100),
+			LatestHigh: fmt.Sprintf("%.0f%%", last.HighImpressionShare*100),
+		})
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].Country < trends[j].Country })
+	return trends
+}
+
+func writeSOVCSV(path string, trends []sovTrend) error {
+	f, err := os.Create(explainPath(path))
+	if err != nil {
+		return fmt.Errorf("creating CSV: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"date", "countryOrRegion", "searchTerm", "lowImpressionShare", "highImpressionShare", "rank"})
+	for _, t := range trends {
+		for _, p := range t.Points {
+			w.Write([]string{
+				p.Date, p.CountryOrRegion, p.SearchTerm,
+				fmt.Sprintf("%g", p.LowImpressionShare), fmt.Sprintf("%g", p.HighImpressionShare), p.Rank,
+			})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// apiVersionAnnotation holds the API version a command needs.
+const apiVersionAnnotation = "asa-cli/api-version"
+
+func init() {
+	needsAPIVersion(api.V5, adsCmd)
+}
+
+// needsAPIVersion marks cmds, and their subcommands, as needing version.
+func needsAPIVersion(version string, cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[apiVersionAnnotation] = version
+	}
+}
+
+// warnAPIVersion warns when cmd needs a later API version than the profile's
+// api_version.
+func warnAPIVersion(cmd *cobra.Command) {
+	need := ""
+	for c := cmd; c != nil && need == ""; c = c.Parent() {
+		need = c.Annotations[apiVersionAnnotation]
+	}
+	if need == "" {
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	have, err := api.ParseVersion(cfg.APIVersion)
+	if err != nil || have >= need {
+		return
+	}
+	warnf("%s needs API %s; this profile uses %s, so it will fail.", cmd.CommandPath(), need, have)
+}
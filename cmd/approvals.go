@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/trebuhs/asa-cli/internal/approvals"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var approvalsCmd = &cobra.Command{
+	Use:   "approvals",
+	Short: "Review and apply proposed changes",
+	Long: `With --require-approval (or require_approval: true in config.yaml, top level
+or per profile), commands that change campaigns, ad groups, keywords, or
+budgets write a proposal instead of running. Another operator lists the
+proposals and applies or rejects them, which runs the recorded command as if
+it had been typed.
+
+Operators are identified by $ASA_OPERATOR or the login name, and a proposal
+can't be applied by the operator who made it (use --force to override).
+Proposals are kept in ~/.asa-cli/approvals; set approvals_dir in config.yaml
+to share them, e.g. through a synced folder.`,
+}
+
+var approvalsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List proposals",
+	RunE:  runApprovalsList,
+}
+
+var approvalsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a proposal",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runApprovalsShow,
+}
+
+var approvalsApplyCmd = &cobra.Command{
+	Use:   "apply <id>",
+	Short: "Run a pending (or previously failed) proposal",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runApprovalsApply,
+}
+
+var approvalsRejectCmd = &cobra.Command{
+	Use:   "reject <id>",
+	Short: "Reject a pending proposal",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runApprovalsReject,
+}
+
+var (
+	requireApproval bool
+	approvalsAll    bool
+	approvalsReason string
+
+	// applyingApproval is set while a proposal runs, so it isn't proposed again.
+	applyingApproval bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&requireApproval, "require-approval", false, "Write mutating commands as proposals for another operator instead of running them")
+
+	approvalsListCmd.Flags().BoolVar(&approvalsAll, "all", false, "Include applied and rejected proposals")
+	approvalsRejectCmd.Flags().StringVar(&approvalsReason, "reason", "", "Why the proposal was rejected")
+
+	approvalsCmd.AddCommand(approvalsListCmd, approvalsShowCmd, approvalsApplyCmd, approvalsRejectCmd)
+	rootCmd.AddCommand(approvalsCmd)
+
+	markMutating(nil,
+		campaignsCreateCmd, campaignsUpdateCmd, campaignsDeleteCmd, campaignsAddCountriesCmd, campaignsRestoreGeoCmd,
+		adgroupsCreateCmd, adgroupsUpdateCmd, adgroupsDeleteCmd, adgroupsSetBiddingCmd, adgroupsCloneCmd,
+		campaignsEditCmd, adgroupsEditCmd, kwCreateCmd, kwUpdateCmd, kwEditCmd,
+		nkCampaignCreateCmd, nkCampaignDeleteCmd, nkAdGroupCreateCmd, nkAdGroupDeleteCmd,
+	)
+	markMutating(func() bool { return !kwDelDryRun }, kwDeleteCmd)
+	markMutating(func() bool { return !kwImportDryRun }, kwImportCmd)
+	markMutating(func() bool { return !kwLocalizeDryRun }, kwLocalizeCmd)
+	markMutating(func() bool { return optApply }, optimizeBudgetsCmd)
+	markMutating(func() bool { return !convDryRun }, kwConvertMatchCmd)
+	markMutating(func() bool { return bidGapApply }, kwBidGapCmd)
+	markMutating(func() bool { return adjApply }, adgroupsAdjustBidsCmd)
+	markMutating(func() bool { return !importDryRun && importOut == "" }, importCmd)
+}
+
+// writesWhen holds the when funcs passed to markMutating, for other checks
+// that need to know whether a run changes anything.
+var writesWhen = map[*cobra.Command]func() bool{}
+
+// markMutating has cmds write a proposal instead of running when approval is
+// required. when, if not nil, reports whether a given run changes anything.
+func markMutating(when func() bool, cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		if when != nil {
+			writesWhen[c] = when
+		}
+		run := c.RunE
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			if approvalRequired() && (when == nil || when()) {
+				return propose(cmd, args)
+			}
+			return run(cmd, args)
+		}
+	}
+}
+
+func approvalRequired() bool {
+	if applyingApproval {
+		return false
+	}
+	if requireApproval {
+		return true
+	}
+	cfg, err := config.Load()
+	return err == nil && cfg.RequireApproval
+}
+
+func approvalsDir() approvals.Dir {
+	dir := ""
+	if cfg, err := config.Load(); err == nil {
+		dir = cfg.ApprovalsDir
+	}
+	return approvals.Open(dir)
+}
+
+// propose records the command line of cmd as a pending proposal.
+func propose(cmd *cobra.Command, args []string) error {
+	p, err := approvalsDir().New(commandLine(cmd, args), profileName)
+	if err != nil {
+		return err
+	}
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, p, nil)
+		return nil
+	}
+	fmt.Printf("Proposed %s\n", p.ID)
+	fmt.Fprintf(os.Stderr, "Approval required: nothing was changed. Another operator can run:\n  asa-cli approvals apply %s\n", p.ID)
+	return nil
+}
+
+// commandLine rebuilds the arguments of a run: the command path, every flag
+// that was set, and the positional arguments.
+func commandLine(cmd *cobra.Command, args []string) []string {
+	line := strings.Fields(cmd.CommandPath())[1:]
+	visit := func(f *pflag.Flag) {
+		// Visit also sees flags set by earlier runs in this process (batch,
+		// shell) that restoreFlags has since reset.
+		if !f.Changed || f.Name == "require-approval" {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				line = append(line, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		line = append(line, "--"+f.Name+"="+f.Value.String())
+	}
+	cmd.Flags().Visit(visit) // includes the inherited flags parsed with it
+	return append(line, args...)
+}
+
+type approvalRow struct {
+	approvals.Proposal
+	When string `json:"-"`
+}
+
+func runApprovalsList(cmd *cobra.Command, args []string) error {
+	list, err := approvalsDir().List()
+	if err != nil {
+		return err
+	}
+	rows := []approvalRow{}
+	for _, p := range list {
+		if !approvalsAll && !p.Open() {
+			continue
+		}
+		rows = append(rows, approvalRow{Proposal: p, When: p.ProposedAt.Local().Format("2006-01-02 15:04")})
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "ID", Field: "ID"},
+		{Header: "PROPOSED", Field: "When"},
+		{Header: "BY", Field: "ProposedBy"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "COMMAND", Field: "Command"},
+	})
+	return nil
+}
+
+func runApprovalsShow(cmd *cobra.Command, args []string) error {
+	p, err := approvalsDir().Get(args[0])
+	if err != nil {
+		return err
+	}
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, p, nil)
+		return nil
+	}
+	fmt.Printf("Proposal:  %s\n", p.ID)
+	fmt.Printf("Command:   asa-cli %s\n", p.Command)
+	fmt.Printf("Proposed:  %s by %s\n", p.ProposedAt.Local().Format("2006-01-02 15:04"), p.ProposedBy)
+	fmt.Printf("Status:    %s\n", p.Status)
+	if p.ReviewedAt != nil {
+		fmt.Printf("Reviewed:  %s by %s\n", p.ReviewedAt.Local().Format("2006-01-02 15:04"), p.ReviewedBy)
+	}
+	if p.Note != "" {
+		fmt.Printf("Note:      %s\n", p.Note)
+	}
+	return nil
+}
+
+// openProposal loads a proposal that can still be applied or rejected: one
+// that is pending, or whose earlier apply failed.
+func openProposal(dir approvals.Dir, id string) (*approvals.Proposal, error) {
+	p, err := dir.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Open() {
+		return nil, fmt.Errorf("proposal %s is already %s", p.ID, p.Status)
+	}
+	return p, nil
+}
+
+func runApprovalsApply(cmd *cobra.Command, args []string) error {
+	dir := approvalsDir()
+	p, err := openProposal(dir, args[0])
+	if err != nil {
+		return err
+	}
+	if p.ProposedBy == approvals.Operator() && !forceFlag {
+		return fmt.Errorf("proposal %s was made by %s; another operator must apply it (use --force to override)", p.ID, p.ProposedBy)
+	}
+
+	fmt.Fprintf(os.Stderr, "Applying %s: asa-cli %s\n", p.ID, p.Command)
+	runErr := runProposal(p.Args)
+	if runErr != nil {
+		p.Review(approvals.StatusFailed, runErr.Error())
+	} else {
+		p.Review(approvals.StatusApplied, "")
+	}
+	if err := dir.Save(p); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("applying proposal %s: %w", p.ID, runErr)
+	}
+	return nil
+}
+
+// runProposal runs recorded arguments in this process, as batch does. The
+// applier's own --force is not passed on: the command runs with the safety
+// checks it was proposed with.
+func runProposal(args []string) error {
+	baseline := snapshotFlags(rootCmd)
+	defer restoreFlags(baseline)
+
+	clean := flagSnapshot{}
+	for f, st := range baseline {
+		if f.Name == "force" {
+			st = flagState{value: "false"}
+		}
+		clean[f] = st
+	}
+	restoreFlags(clean)
+
+	applyingApproval = true
+	defer func() { applyingApproval = false }()
+	rootCmd.SetArgs(args)
+	return rootCmd.Execute()
+}
+
+func runApprovalsReject(cmd *cobra.Command, args []string) error {
+	dir := approvalsDir()
+	p, err := openProposal(dir, args[0])
+	if err != nil {
+		return err
+	}
+	p.Review(approvals.StatusRejected, approvalsReason)
+	if err := dir.Save(p); err != nil {
+		return err
+	}
+	fmt.Printf("Rejected %s\n", p.ID)
+	return nil
+}
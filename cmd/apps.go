@@ -21,15 +21,13 @@ var appsSearchCmd = &cobra.Command{
 
 var (
 	appQuery    string
-	appLimit    int
-	appOffset   int
+	appPage     pager
 	appOwnedOnly bool
 )
 
 func init() {
 	appsSearchCmd.Flags().StringVar(&appQuery, "query", "", "Search query (required)")
-	appsSearchCmd.Flags().IntVar(&appLimit, "limit", 20, "Number of results")
-	appsSearchCmd.Flags().IntVar(&appOffset, "offset", 0, "Results offset")
+	appPage.register(appsSearchCmd)
 	appsSearchCmd.Flags().BoolVar(&appOwnedOnly, "owned", false, "Return only owned apps")
 	appsSearchCmd.MarkFlagRequired("query")
 
@@ -43,13 +41,17 @@ func runAppsSearch(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	limit, offset, err := appPage.resolve()
+	if err != nil {
+		return err
+	}
 	svc := services.NewAppService(client)
-	apps, _, err := svc.Search(appQuery, appLimit, appOffset, appOwnedOnly)
+	apps, page, err := svc.Search(appQuery, limit, offset, appOwnedOnly)
 	if err != nil {
 		return fmt.Errorf("searching apps: %w", err)
 	}
 
-	output.Print(getFormat(), apps, []output.Column{
+	appPage.print(apps, page, []output.Column{
 		{Header: "ADAM ID", Field: "AdamID", Width: 12},
 		{Header: "APP NAME", Field: "AppName", Width: 30},
 		{Header: "DEVELOPER", Field: "DeveloperName", Width: 25},
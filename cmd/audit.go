@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/naming"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check the account against configured policies",
+}
+
+var auditNamingCmd = &cobra.Command{
+	Use:   "naming",
+	Short: "List campaigns and ad groups violating the naming policy",
+	Long: `Check every campaign and ad group name against the naming policy in
+config.yaml and list the ones that do not match. Exits non-zero if any
+violations are found, so it can run in CI.
+
+Example policy:
+  naming:
+    campaign: "{country}_{type}_{app}"
+    adgroup: "^(Exact|Broad|Discovery) - .+$"
+    tokens:
+      type: [Brand, Generic, Competitor]`,
+	RunE: runAuditNaming,
+}
+
+func init() {
+	auditCmd.AddCommand(auditNamingCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+// namingRules compiles the configured campaign and ad group rules; either is
+// nil when no pattern is set.
+func namingRules() (campaign, adgroup *naming.Rule, err error) {
+	policy, err := config.Naming()
+	if err != nil {
+		return nil, nil, err
+	}
+	if policy.Campaign != "" {
+		if campaign, err = naming.Compile(policy.Campaign, policy.Tokens); err != nil {
+			return nil, nil, err
+		}
+	}
+	if policy.AdGroup != "" {
+		if adgroup, err = naming.Compile(policy.AdGroup, policy.Tokens); err != nil {
+			return nil, nil, err
+		}
+	}
+	return campaign, adgroup, nil
+}
+
+// checkCampaignName validates a new campaign name against the naming policy.
+// Skipped with --force.
+func checkCampaignName(name string) error {
+	if forceFlag {
+		return nil
+	}
+	rule, _, err := namingRules()
+	if err != nil || rule == nil {
+		return err
+	}
+	if err := rule.Check(name); err != nil {
+		return fmt.Errorf("%w; use --force to override", err)
+	}
+	return nil
+}
+
+// checkAdGroupName validates a new ad group name against the naming policy.
+// Skipped with --force.
+func checkAdGroupName(name string) error {
+	if forceFlag {
+		return nil
+	}
+	_, rule, err := namingRules()
+	if err != nil || rule == nil {
+		return err
+	}
+	if err := rule.Check(name); err != nil {
+		return fmt.Errorf("%w; use --force to override", err)
+	}
+	return nil
+}
+
+type namingViolation struct {
+	Kind       string `json:"kind"`
+	ID         int64  `json:"id"`
+	CampaignID int64  `json:"campaignId,omitempty"`
+	Name       string `json:"name"`
+	Problem    string `json:"problem"`
+}
+
+func runAuditNaming(cmd *cobra.Command, args []string) error {
+	campaignRule, adgroupRule, err := namingRules()
+	if err != nil {
+		return err
+	}
+	if campaignRule == nil && adgroupRule == nil {
+		return fmt.Errorf("no naming policy configured; add a naming: section to %s/config.yaml", config.ConfigDir())
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	campaigns, err := services.NewCampaignService(client).FindAll(models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("listing campaigns: %w", err)
+	}
+
+	violations := []namingViolation{}
+	adgroups := services.NewAdGroupService(client)
+	for _, c := range campaigns {
+		if campaignRule != nil {
+			if err := campaignRule.Check(c.Name); err != nil {
+				violations = append(violations, namingViolation{Kind: "campaign", ID: c.ID, Name: c.Name, Problem: err.Error()})
+			}
+		}
+		if adgroupRule == nil {
+			continue
+		}
+		groups, err := adgroups.FindAll(c.ID, models.NewSelector(1000, 0))
+		if err != nil {
+			return fmt.Errorf("listing ad groups for campaign %d: %w", c.ID, err)
+		}
+		for _, g := range groups {
+			if err := adgroupRule.Check(g.Name); err != nil {
+				violations = append(violations, namingViolation{Kind: "adgroup", ID: g.ID, CampaignID: c.ID, Name: g.Name, Problem: err.Error()})
+			}
+		}
+	}
+
+	if len(violations) == 0 && getFormat() == output.FormatTable {
+		fmt.Println("All campaign and ad group names match the naming policy.")
+		return nil
+	}
+
+	output.Print(getFormat(), violations, []output.Column{
+		{Header: "KIND", Field: "Kind"},
+		{Header: "ID", Field: "ID"},
+		{Header: "CAMPAIGN ID", Field: "CampaignID"},
+		{Header: "NAME", Field: "Name"},
+	})
+	if len(violations) > 0 {
+		return fmt.Errorf("%d name(s) violate the naming policy", len(violations))
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/credentials"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect authentication setup and refresh tokens",
+}
+
+var authInspectKeyCmd = &cobra.Command{
+	Use:   "inspect-key",
+	Short: "Print the public key and fingerprint of the private key",
+	Long: `Print the public part of the configured private key and its SHA-256
+fingerprint, for comparison with the public key uploaded in the Search Ads UI
+(Account Settings > API). A mismatch is the usual cause of invalid_client
+errors after a key rotation.
+
+With --public, compare against a public key PEM file directly.`,
+	RunE: runAuthInspectKey,
+}
+
+var authRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Exchange a new access token now",
+	Long: `Exchange a new access token for the profile and cache it, whatever the cached
+token's expiry. Useful before a long interactive session, or to check that the
+credentials still work.
+
+To refresh automatically in the background instead, set in config.yaml:
+
+  auth:
+    prewarm: true
+    prewarm_window: 10m`,
+	Args: cobra.NoArgs,
+	RunE: runAuthRefresh,
+}
+
+var (
+	inspectKeyPath    string
+	inspectPublicPath string
+)
+
+func init() {
+	authInspectKeyCmd.Flags().StringVar(&inspectKeyPath, "key", "", "Private key file (default: the profile's private_key_path)")
+	authInspectKeyCmd.Flags().StringVar(&inspectPublicPath, "public", "", "Public key PEM to compare against")
+
+	authCmd.AddCommand(authInspectKeyCmd, authRefreshCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+// defaultPrewarmWindow is how close to expiry a prewarmed token is refreshed
+// when auth.prewarm_window is not set.
+const defaultPrewarmWindow = 10 * time.Minute
+
+// newTokenProvider returns the profile's token provider, refreshing in the
+// background when auth.prewarm is set and warning when a rejected key fails
+// over to the next one.
+func newTokenProvider(cfg *config.Config) *auth.TokenProvider {
+	tp := auth.NewTokenProvider(cfg)
+	tp.OnFailover = warnKeyFailover
+	ac, err := config.Auth()
+	if err != nil || !ac.Prewarm {
+		return tp
+	}
+	tp.PrewarmWindow = ac.PrewarmWindow
+	if tp.PrewarmWindow <= 0 {
+		tp.PrewarmWindow = defaultPrewarmWindow
+	}
+	tp.Prewarm()
+	return tp
+}
+
+func warnKeyFailover(rejected, next string) {
+	warnf("key %s was rejected (invalid_client); trying key %s", rejected, next)
+}
+
+func runAuthRefresh(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := credentials.Resolve(cfg); err != nil {
+		return err
+	}
+	if err := auth.ValidateConfig(cfg); err != nil {
+		return err
+	}
+	tp := auth.NewTokenProvider(cfg)
+	tp.OnFailover = warnKeyFailover
+	token, err := tp.Refresh()
+	if err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, map[string]interface{}{"expiresAt": token.ExpiresAt, "keyId": tp.ActiveKeyID()}, nil)
+		return nil
+	}
+	fmt.Printf("Access token refreshed with key %s; valid until %s.\n", tp.ActiveKeyID(), token.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+type keyInfo struct {
+	KeyID       string `json:"keyId,omitempty"`
+	Source      string `json:"source"`
+	Fingerprint string `json:"fingerprint"`
+	PublicKey   string `json:"publicKey"`
+	Matches     *bool  `json:"matchesPublicKey,omitempty"`
+}
+
+func runAuthInspectKey(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if inspectKeyPath != "" {
+		cfg.PrivateKeyPath = config.ExpandPath(inspectKeyPath)
+		cfg.PrivateKey = ""
+		cfg.CredentialSource = ""
+	}
+	if err := credentials.Resolve(cfg); err != nil {
+		return err
+	}
+	if cfg.PrivateKey == "" && cfg.PrivateKeyPath == "" {
+		return fmt.Errorf("no private key configured; pass --key or run 'asa-cli configure'")
+	}
+
+	key, err := auth.LoadPrivateKey(cfg)
+	if err != nil {
+		return err
+	}
+	info := keyInfo{KeyID: cfg.KeyID, Source: cfg.PrivateKeyPath}
+	if cfg.PrivateKey != "" {
+		info.Source = cfg.CredentialSource
+	}
+	if info.PublicKey, err = auth.PublicKeyPEM(&key.PublicKey); err != nil {
+		return err
+	}
+	if info.Fingerprint, err = auth.Fingerprint(&key.PublicKey); err != nil {
+		return err
+	}
+
+	if inspectPublicPath != "" {
+		data, err := os.ReadFile(config.ExpandPath(inspectPublicPath))
+		if err != nil {
+			return fmt.Errorf("reading public key: %w", err)
+		}
+		pub, err := auth.ParsePublicKey(data)
+		if err != nil {
+			return err
+		}
+		matches := key.PublicKey.Equal(pub)
+		info.Matches = &matches
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, info, nil)
+	} else {
+		fmt.Printf("Source:      %s\n", info.Source)
+		if info.KeyID != "" {
+			fmt.Printf("Key ID:      %s\n", info.KeyID)
+		}
+		fmt.Printf("Fingerprint: %s\n\n", info.Fingerprint)
+		fmt.Print(info.PublicKey)
+	}
+
+	if info.Matches != nil {
+		if !*info.Matches {
+			return fmt.Errorf("private key does not match %s; upload the printed public key in the Search Ads UI and update key_id", inspectPublicPath)
+		}
+		fmt.Fprintf(os.Stderr, "\nPrivate key matches %s.\n", inspectPublicPath)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var authKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the profile's API keys",
+	Long: `Manage the key pairs a profile can authenticate with. The primary key
+(key_id and private_key_path) is tried first; when Apple rejects a key with
+invalid_client the next one is tried, so a new key can be added before the old
+one is revoked in the Search Ads UI and removed once rotation is done.
+
+Example:
+  asa-cli auth keys add --key-id NEWKEY --private-key-path ~/.asa-cli/keys/new.pem
+  asa-cli auth keys list
+  asa-cli auth keys remove OLDKEY`,
+}
+
+var authKeysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the profile's keys in the order they are tried",
+	Args:  cobra.NoArgs,
+	RunE:  runAuthKeysList,
+}
+
+var authKeysAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a key pair to the profile",
+	Args:  cobra.NoArgs,
+	RunE:  runAuthKeysAdd,
+}
+
+var authKeysRemoveCmd = &cobra.Command{
+	Use:   "remove <key-id>",
+	Short: "Remove a key pair from the profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAuthKeysRemove,
+}
+
+var (
+	keysAddKeyID   string
+	keysAddPath    string
+	keysAddPrimary bool
+)
+
+func init() {
+	authKeysAddCmd.Flags().StringVar(&keysAddKeyID, "key-id", "", "API Key ID (required)")
+	authKeysAddCmd.Flags().StringVar(&keysAddPath, "private-key-path", "", "Path to the private key file (required)")
+	authKeysAddCmd.Flags().BoolVar(&keysAddPrimary, "primary", false, "Try this key first")
+	authKeysAddCmd.MarkFlagRequired("key-id")
+	authKeysAddCmd.MarkFlagRequired("private-key-path")
+
+	authKeysCmd.AddCommand(authKeysListCmd, authKeysAddCmd, authKeysRemoveCmd)
+	authCmd.AddCommand(authKeysCmd)
+}
+
+// KeyEntry is a configured key pair as listed by `auth keys list`.
+type KeyEntry struct {
+	KeyID          string `json:"keyId"`
+	Role           string `json:"role"`
+	PrivateKeyPath string `json:"privateKeyPath,omitempty"`
+	Fingerprint    string `json:"fingerprint,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+func runAuthKeysList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	entries := []KeyEntry{}
+	for i, pair := range cfg.KeyPairs() {
+		e := KeyEntry{KeyID: pair.KeyID, Role: "fallback", PrivateKeyPath: pair.PrivateKeyPath}
+		if i == 0 {
+			e.Role = "primary"
+		}
+		if i == 0 && cfg.CredentialSource != "" {
+			e.PrivateKeyPath = cfg.CredentialSource
+		} else if fp, err := keyFingerprint(pair.PrivateKeyPath); err != nil {
+			e.Error = err.Error()
+		} else {
+			e.Fingerprint = fp
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 && getFormat() == output.FormatTable {
+		fmt.Println("No keys configured. Run 'asa-cli configure' or 'asa-cli auth keys add'.")
+		return nil
+	}
+
+	output.Print(getFormat(), entries, []output.Column{
+		{Header: "KEY ID", Field: "KeyID"},
+		{Header: "ROLE", Field: "Role"},
+		{Header: "PRIVATE KEY", Field: "PrivateKeyPath"},
+		{Header: "FINGERPRINT", Field: "Fingerprint"},
+		{Header: "ERROR", Field: "Error"},
+	})
+	return nil
+}
+
+// keyFingerprint loads a private key file and returns its public key's
+// fingerprint.
+func keyFingerprint(path string) (string, error) {
+	key, err := auth.LoadPrivateKey(&config.Config{PrivateKeyPath: path})
+	if err != nil {
+		return "", err
+	}
+	return auth.Fingerprint(&key.PublicKey)
+}
+
+func runAuthKeysAdd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	pair := config.KeyPair{KeyID: keysAddKeyID, PrivateKeyPath: config.ExpandPath(keysAddPath)}
+	if _, err := keyFingerprint(pair.PrivateKeyPath); err != nil {
+		return err
+	}
+
+	pairs := cfg.KeyPairs()
+	for _, p := range pairs {
+		if p.KeyID == pair.KeyID {
+			return fmt.Errorf("key %s is already configured; remove it first to change its private key", pair.KeyID)
+		}
+	}
+	if keysAddPrimary {
+		pairs = append([]config.KeyPair{pair}, pairs...)
+	} else {
+		pairs = append(pairs, pair)
+	}
+	if err := config.SaveKeyPairs(pairs); err != nil {
+		return err
+	}
+
+	role := "fallback"
+	if pairs[0] == pair {
+		role = "primary"
+	}
+	fmt.Fprintf(os.Stderr, "Added key %s as %s (%d key(s) configured).\n", pair.KeyID, role, len(pairs))
+	return nil
+}
+
+func runAuthKeysRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	pairs := cfg.KeyPairs()
+	kept := []config.KeyPair{}
+	for _, p := range pairs {
+		if p.KeyID != args[0] {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == len(pairs) {
+		return fmt.Errorf("key %s is not configured", args[0])
+	}
+	if len(kept) == 0 {
+		return fmt.Errorf("cannot remove the only key; add its replacement first")
+	}
+	if err := config.SaveKeyPairs(kept); err != nil {
+		return err
+	}
+
+	if pairs[0].KeyID == args[0] {
+		fmt.Fprintf(os.Stderr, "Removed key %s; %s is now the primary key.\n", args[0], kept[0].KeyID)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Removed key %s.\n", args[0])
+	return nil
+}
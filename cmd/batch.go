@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/progress"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run a sequence of commands in one process",
+	Long: `Run asa-cli commands from a file (or stdin), one per line, within a single
+process. Commands share the access token and API client, which is much faster
+than invoking the binary repeatedly.
+
+Blank lines and lines starting with # are ignored. A leading "asa-cli" on a
+line is optional. A consolidated JSON report is printed when all commands finish.
+
+Example commands.txt:
+  campaigns list --page-size 5
+  asa-cli adgroups list --campaign-id 123
+  keywords update --campaign-id 123 --adgroup-id 456 --id 789 --bid 1.20`,
+	RunE: runBatch,
+}
+
+var (
+	batchFile        string
+	batchStopOnError bool
+)
+
+func init() {
+	batchCmd.Flags().StringVarP(&batchFile, "file", "f", "-", "Command file (- for stdin)")
+	batchCmd.Flags().BoolVar(&batchStopOnError, "stop-on-error", false, "Stop at the first failing command")
+	rootCmd.AddCommand(batchCmd)
+}
+
+// BatchResult is the outcome of a single command in a batch run.
+type BatchResult struct {
+	Line       int             `json:"line"`
+	Command    string          `json:"command"`
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+	Output     json.RawMessage `json:"output,omitempty"`
+	DurationMs int64           `json:"durationMs"`
+}
+
+// BatchReport is the consolidated result of a batch run.
+type BatchReport struct {
+	Total     int           `json:"total"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Results   []BatchResult `json:"results"`
+}
+
+type batchLine struct {
+	num  int
+	text string
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	var in io.Reader = os.Stdin
+	if batchFile != "-" {
+		f, err := os.Open(batchFile)
+		if err != nil {
+			return fmt.Errorf("opening batch file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	lines, err := readBatchLines(in)
+	if err != nil {
+		return err
+	}
+
+	// Inner commands must not inherit the batch command's own flags, so
+	// remember the flag state as it is now and restore it before each line.
+	baseline := snapshotFlags(rootCmd)
+	stopOnError := batchStopOnError
+
+	report := BatchReport{Total: len(lines)}
+	bar := progress.New("Running batch", len(lines))
+	for i, line := range lines {
+		result := runBatchLine(line, baseline)
+		report.Results = append(report.Results, result)
+		bar.Add(1)
+		if result.Success {
+			report.Succeeded++
+			continue
+		}
+		report.Failed++
+		if stopOnError {
+			report.Skipped = len(lines) - i - 1
+			break
+		}
+	}
+
+	bar.Done()
+	restoreFlags(baseline)
+
+	output.Print(output.FormatJSON, report, nil)
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d command(s) failed", report.Failed, report.Total)
+	}
+	return nil
+}
+
+func readBatchLines(r io.Reader) ([]batchLine, error) {
+	var lines []batchLine
+	scanner := bufio.NewScanner(r)
+	num := 0
+	for scanner.Scan() {
+		num++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		lines = append(lines, batchLine{num: num, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading batch commands: %w", err)
+	}
+	return lines, nil
+}
+
+func runBatchLine(line batchLine, baseline flagSnapshot) BatchResult {
+	result := BatchResult{Line: line.num, Command: line.text}
+	start := time.Now()
+
+	args, err := splitCommandLine(line.text)
+	if err == nil && len(args) > 0 && args[0] == "asa-cli" {
+		args = args[1:]
+	}
+	if err == nil {
+		args, err = expandWebLinks(expandAliases(args))
+	}
+	if err == nil && len(args) == 0 {
+		err = fmt.Errorf("empty command")
+	}
+	if err == nil && (args[0] == "batch" || args[0] == "shell") {
+		err = fmt.Errorf("%q cannot be nested in a batch", args[0])
+	}
+
+	var out []byte
+	if err == nil {
+		restoreFlags(baseline)
+		out, err = captureStdout(func() error {
+			rootCmd.SetArgs(args)
+			return rootCmd.Execute()
+		})
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.Success = err == nil
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if trimmed := bytes.TrimSpace(out); len(trimmed) > 0 {
+		if json.Valid(trimmed) {
+			result.Output = trimmed
+		} else {
+			result.Output, _ = json.Marshal(string(trimmed))
+		}
+	}
+	return result
+}
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote.
+func captureStdout(fn func() error) ([]byte, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("capturing output: %w", err)
+	}
+
+	orig := os.Stdout
+	os.Stdout = w
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&buf, r)
+		close(done)
+	}()
+
+	runErr := fn()
+
+	w.Close()
+	<-done
+	r.Close()
+	os.Stdout = orig
+	return buf.Bytes(), runErr
+}
+
+// flagSnapshot records the value and changed state of every flag in the
+// command tree so it can be restored between in-process executions.
+type flagSnapshot map[*pflag.Flag]flagState
+
+type flagState struct {
+	value   string
+	slice   []string
+	changed bool
+}
+
+func snapshotFlags(root *cobra.Command) flagSnapshot {
+	snap := flagSnapshot{}
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		visit := func(f *pflag.Flag) {
+			if _, ok := snap[f]; ok {
+				return
+			}
+			st := flagState{value: f.Value.String(), changed: f.Changed}
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				st.slice = append([]string(nil), sv.GetSlice()...)
+			}
+			snap[f] = st
+		}
+		c.PersistentFlags().VisitAll(visit)
+		c.Flags().VisitAll(visit)
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+	return snap
+}
+
+func restoreFlags(snap flagSnapshot) {
+	for f, st := range snap {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(st.slice)
+		} else {
+			f.Value.Set(st.value)
+		}
+		f.Changed = st.changed
+	}
+}
+
+// splitCommandLine splits a command line into arguments, honoring single
+// quotes, double quotes and backslash escapes.
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				cur.WriteRune(runes[i])
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			cur.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in: %s", line)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
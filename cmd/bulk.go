@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/executor"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+// BulkItemResult is one item of a bulk create that the API partly accepted.
+type BulkItemResult struct {
+	Item      int    `json:"item"`
+	ID        int64  `json:"id,omitempty"`
+	Text      string `json:"text"`
+	MatchType string `json:"matchType"`
+	Result    string `json:"result"` // created or failed
+	Error     string `json:"error,omitempty"`
+
+	ResultText string `json:"-"`
+}
+
+var bulkItemColumns = []output.Column{
+	{Header: "#", Field: "Item"},
+	{Header: "ID", Field: "ID"},
+	{Header: "TEXT", Field: "Text"},
+	{Header: "MATCH TYPE", Field: "MatchType"},
+	{Header: "RESULT", Field: "ResultText"},
+}
+
+// keywordBulkItems converts targeting keyword results to table rows.
+func keywordBulkItems(results []services.BulkResult[models.Keyword]) []BulkItemResult {
+	rows := make([]BulkItemResult, len(results))
+	for i, r := range results {
+		rows[i] = bulkItem(r.Index, r.Item.ID, r.Item.Text, string(r.Item.MatchType), r.Error)
+	}
+	return rows
+}
+
+// negativeBulkItems converts negative keyword results to table rows.
+func negativeBulkItems(results []services.BulkResult[models.NegativeKeyword]) []BulkItemResult {
+	rows := make([]BulkItemResult, len(results))
+	for i, r := range results {
+		rows[i] = bulkItem(r.Index, r.Item.ID, r.Item.Text, string(r.Item.MatchType), r.Error)
+	}
+	return rows
+}
+
+func bulkItem(index int, id int64, text, matchType, reason string) BulkItemResult {
+	row := BulkItemResult{Item: index + 1, ID: id, Text: text, MatchType: matchType, Result: "created", ResultText: "created"}
+	if reason != "" {
+		row.ID = 0
+		row.Result, row.Error = "failed", reason
+		row.ResultText = "failed: " + reason
+	}
+	return row
+}
+
+// printBulkItems prints a partly accepted bulk create and returns an error
+// counting the failures.
+func printBulkItems(rows []BulkItemResult, what string) error {
+	failed := 0
+	for _, r := range rows {
+		if r.Result == "failed" {
+			failed++
+		}
+	}
+	output.Print(getFormat(), rows, bulkItemColumns)
+	fmt.Fprintf(os.Stderr, "Created %d of %d %s.\n", len(rows)-failed, len(rows), what)
+	return fmt.Errorf("%d of %d %s failed", failed, len(rows), what)
+}
+
+var bulkConcurrency int
+
+// addConcurrencyFlag adds --concurrency to a bulk command.
+func addConcurrencyFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&bulkConcurrency, "concurrency", 4, "Requests in flight at once (lowered automatically when rate limited)")
+}
+
+// newExecutor returns the work queue of a bulk command, with batches of at
+// most maxBatch items.
+func newExecutor(label string, maxBatch int, stopOnError bool) *executor.Executor {
+	return executor.New(executor.Options{
+		Label:       label,
+		Workers:     bulkConcurrency,
+		MaxBatch:    maxBatch,
+		StopOnError: stopOnError,
+	})
+}
+
+// printThroughput reports a bulk run's throughput on stderr.
+func printThroughput(stats executor.Stats) {
+	fmt.Fprintf(os.Stderr, "Throughput: %s\n", stats)
+}
+
+var ignoreMissing bool
+
+// addIgnoreMissingFlag adds --ignore-missing to a delete command.
+func addIgnoreMissingFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&ignoreMissing, "ignore-missing", false, "Succeed for entities that are already deleted, so retries are safe")
+}
+
+// printDeleted reports a delete of n items by ID, listing the ones skipped
+// because they were already deleted.
+func printDeleted(n int, what string, skipped []int64) {
+	msg := fmt.Sprintf("Deleted %d %s", n-len(skipped), what)
+	if len(skipped) > 0 {
+		ids := make([]string, len(skipped))
+		for i, id := range skipped {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		msg += fmt.Sprintf("; skipped %d already deleted: %s", len(skipped), strings.Join(ids, ", "))
+	}
+	fmt.Println(msg + ".")
+}
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
 	"github.com/trebuhs/asa-cli/internal/models"
 	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/refs"
 	"github.com/trebuhs/asa-cli/internal/services"
 )
 
@@ -56,8 +59,7 @@ var campaignsDeleteCmd = &cobra.Command{
 }
 
 var (
-	campLimit     int
-	campOffset    int
+	campPage      pager
 	campFilters   []string
 	campSorts     []string
 	campAll       bool
@@ -66,20 +68,21 @@ var (
 	campDaily     string
 	campCountries string
 	campAppID     int64
-	campStatus    string
+	campStatus    models.Status
+	campTag       string
 )
 
 func init() {
 	// list
-	campaignsListCmd.Flags().IntVar(&campLimit, "limit", 20, "Number of results")
-	campaignsListCmd.Flags().IntVar(&campOffset, "offset", 0, "Results offset")
+	campPage.register(campaignsListCmd)
+	campaignsListCmd.Flags().StringVar(&campTag, "tag", "", "Only campaigns with this local tag (fetches all pages)")
 
 	// find
 	campaignsFindCmd.Flags().StringSliceVar(&campFilters, "filter", nil, `Filter conditions (e.g. "status=ENABLED", "name~MyApp")`)
 	campaignsFindCmd.Flags().StringSliceVar(&campSorts, "sort", nil, `Sort order (e.g. "name:asc", "id:desc")`)
-	campaignsFindCmd.Flags().IntVar(&campLimit, "limit", 20, "Number of results")
-	campaignsFindCmd.Flags().IntVar(&campOffset, "offset", 0, "Results offset")
+	campPage.register(campaignsFindCmd)
 	campaignsFindCmd.Flags().BoolVar(&campAll, "all", false, "Fetch all pages")
+	campaignsFindCmd.Flags().StringVar(&campTag, "tag", "", "Only campaigns with this local tag")
 
 	// create
 	campaignsCreateCmd.Flags().StringVar(&campName, "name", "", "Campaign name (required)")
@@ -87,7 +90,8 @@ func init() {
 	campaignsCreateCmd.Flags().StringVar(&campDaily, "daily-budget", "", "Daily budget (e.g. 50.00)")
 	campaignsCreateCmd.Flags().StringVar(&campCountries, "countries", "", "Comma-separated country codes (e.g. US,GB)")
 	campaignsCreateCmd.Flags().Int64Var(&campAppID, "app-id", 0, "App Adam ID (required)")
-	campaignsCreateCmd.Flags().StringVar(&campStatus, "status", "ENABLED", "Campaign status")
+	enumVar(campaignsCreateCmd.Flags(), &campStatus, "status", models.StatusEnabled, models.ParseStatus, "Campaign status (ENABLED/PAUSED)")
+	campaignsCreateCmd.Flags().StringVar(&externalID, "external-id", "", externalIDUsage)
 	campaignsCreateCmd.MarkFlagRequired("name")
 	campaignsCreateCmd.MarkFlagRequired("app-id")
 	campaignsCreateCmd.MarkFlagRequired("countries")
@@ -98,7 +102,10 @@ func init() {
 	campaignsUpdateCmd.Flags().StringVar(&campName, "name", "", "Campaign name")
 	campaignsUpdateCmd.Flags().StringVar(&campBudget, "budget", "", "Total budget")
 	campaignsUpdateCmd.Flags().StringVar(&campDaily, "daily-budget", "", "Daily budget")
-	campaignsUpdateCmd.Flags().StringVar(&campStatus, "status", "", "Campaign status (ENABLED/PAUSED)")
+	enumVar(campaignsUpdateCmd.Flags(), &campStatus, "status", "", models.ParseStatus, "Campaign status (ENABLED/PAUSED)")
+	registerPatchFlags(campaignsUpdateCmd)
+
+	addIgnoreMissingFlag(campaignsDeleteCmd)
 
 	campaignsCmd.AddCommand(campaignsListCmd, campaignsGetCmd, campaignsFindCmd, campaignsCreateCmd, campaignsUpdateCmd, campaignsDeleteCmd)
 	rootCmd.AddCommand(campaignsCmd)
@@ -121,12 +128,28 @@ func runCampaignsList(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewCampaignService(client)
-	campaigns, _, err := svc.List(campLimit, campOffset)
+	if campTag != "" {
+		campaigns, err := svc.FindAll(models.NewSelector(1000, 0))
+		if err != nil {
+			return fmt.Errorf("listing campaigns: %w", err)
+		}
+		if campaigns, err = filterCampaignsByTag(campaigns, campTag); err != nil {
+			return err
+		}
+		campPage.print(campaigns, nil, campaignColumns)
+		return nil
+	}
+
+	limit, offset, err := campPage.resolve()
+	if err != nil {
+		return err
+	}
+	campaigns, page, err := svc.List(limit, offset)
 	if err != nil {
 		return fmt.Errorf("listing campaigns: %w", err)
 	}
 
-	output.Print(getFormat(), campaigns, campaignColumns)
+	campPage.print(campaigns, page, campaignColumns)
 	return nil
 }
 
@@ -157,25 +180,32 @@ func runCampaignsFind(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	selector := models.NewSelector(campLimit, campOffset)
+	selector, err := campPage.selector()
+	if err != nil {
+		return err
+	}
 	selector.Conditions = parseFilters(campFilters)
 	selector.OrderBy = parseSorts(campSorts)
 
 	svc := services.NewCampaignService(client)
 
+	var campaigns []models.Campaign
+	var page *models.PageDetail
 	if campAll {
-		campaigns, err := svc.FindAll(selector)
-		if err != nil {
-			return fmt.Errorf("finding campaigns: %w", err)
-		}
-		output.Print(getFormat(), campaigns, campaignColumns)
+		campaigns, err = svc.FindAll(selector)
 	} else {
-		campaigns, _, err := svc.Find(selector)
-		if err != nil {
-			return fmt.Errorf("finding campaigns: %w", err)
+		campaigns, page, err = svc.Find(selector)
+	}
+	if err != nil {
+		return fmt.Errorf("finding campaigns: %w", err)
+	}
+	if campTag != "" {
+		if campaigns, err = filterCampaignsByTag(campaigns, campTag); err != nil {
+			return err
 		}
-		output.Print(getFormat(), campaigns, campaignColumns)
+		page = nil
 	}
+	campPage.print(campaigns, page, campaignColumns)
 	return nil
 }
 
@@ -193,6 +223,43 @@ func runCampaignsCreate(cmd *cobra.Command, args []string) error {
 	if err := checkBudgetLimit(campDaily); err != nil {
 		return err
 	}
+	if err := checkCampaignName(campName); err != nil {
+		return err
+	}
+
+	svc := services.NewCampaignService(client)
+
+	store, ref, err := findExternalRef(refs.KindCampaign)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		_, err := svc.Get(ref.ID)
+		switch {
+		case err == nil:
+			fmt.Fprintf(os.Stderr, "External ID %s is campaign %d; updating it instead of creating a new one.\n", externalID, ref.ID)
+			update := &models.CampaignUpdate{
+				Name:              campName,
+				BudgetAmount:      &models.Money{Amount: campBudget, Currency: currency},
+				DailyBudgetAmount: &models.Money{Amount: campDaily, Currency: currency},
+			}
+			if cmd.Flags().Changed("status") {
+				update.Status = campStatus
+			}
+			updated, err := svc.Update(ref.ID, update)
+			if err != nil {
+				return fmt.Errorf("updating campaign: %w", err)
+			}
+			output.Print(getFormat(), updated, campaignColumns)
+			return nil
+		case api.IsNotFound(err):
+			if err := forgetExternalRef(store, refs.KindCampaign); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("getting campaign %d for external ID %s: %w", ref.ID, externalID, err)
+		}
+	}
 
 	campaign := &models.Campaign{
 		Name:               campName,
@@ -201,16 +268,18 @@ func runCampaignsCreate(cmd *cobra.Command, args []string) error {
 		CountriesOrRegions: strings.Split(campCountries, ","),
 		BudgetAmount:       &models.Money{Amount: campBudget, Currency: currency},
 		DailyBudgetAmount:  &models.Money{Amount: campDaily, Currency: currency},
-		AdChannelType:      "SEARCH",
-		SupplySources:      []string{"APPSTORE_SEARCH_RESULTS"},
-		BillingEvent:       "TAPS",
+		AdChannelType:      models.ChannelSearch,
+		SupplySources:      []models.SupplySource{models.SupplySearchResults},
+		BillingEvent:       models.BillingTaps,
 	}
 
-	svc := services.NewCampaignService(client)
 	created, err := svc.Create(campaign)
 	if err != nil {
 		return fmt.Errorf("creating campaign: %w", err)
 	}
+	if err := recordExternalRef(store, refs.Ref{Kind: refs.KindCampaign, ID: created.ID}); err != nil {
+		return err
+	}
 
 	output.Print(getFormat(), created, campaignColumns)
 	return nil
@@ -221,6 +290,9 @@ func runCampaignsUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("invalid campaign ID: %s", args[0])
 	}
+	if err := checkPatchFlags(cmd, "name", "budget", "daily-budget", "status"); err != nil {
+		return err
+	}
 
 	client, err := newAPIClient()
 	if err != nil {
@@ -230,7 +302,17 @@ func runCampaignsUpdate(cmd *cobra.Command, args []string) error {
 	update := &models.CampaignUpdate{}
 	hasUpdate := false
 
+	if cmd.Flags().Changed("file") {
+		if update, err = patchCampaign(client, id); err != nil {
+			return err
+		}
+		hasUpdate = true
+	}
+
 	if cmd.Flags().Changed("name") {
+		if err := checkCampaignName(campName); err != nil {
+			return err
+		}
 		update.Name = campName
 		hasUpdate = true
 	}
@@ -257,16 +339,20 @@ func runCampaignsUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	if !hasUpdate {
-		return fmt.Errorf("no update flags provided")
+		return fmt.Errorf("no update flags provided (or pass --file)")
 	}
 
 	svc := services.NewCampaignService(client)
-	updated, err := svc.Update(id, update)
+	updated, changes, err := svc.UpdateIfChanged(id, update)
 	if err != nil {
 		return fmt.Errorf("updating campaign: %w", err)
 	}
+	if len(changes) == 0 {
+		fmt.Fprintf(os.Stderr, "Campaign %d already matches the update; skipped.\n", id)
+	}
+	printChanges(fmt.Sprintf("campaign %d", id), changes)
 
-	output.Print(getFormat(), updated, campaignColumns)
+	output.Print(getFormat(), withChanges(updated, changes), campaignColumns)
 	return nil
 }
 
@@ -283,6 +369,10 @@ func runCampaignsDelete(cmd *cobra.Command, args []string) error {
 
 	svc := services.NewCampaignService(client)
 	if err := svc.Delete(id); err != nil {
+		if ignoreMissing && services.IsMissing(err) {
+			fmt.Printf("Campaign %d not found; skipped (already deleted).\n", id)
+			return nil
+		}
 		return fmt.Errorf("deleting campaign: %w", err)
 	}
 
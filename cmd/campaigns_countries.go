@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/progress"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var campaignsAddCountriesCmd = &cobra.Command{
+	Use:   "add-countries <id>",
+	Short: "Add countries or regions to a campaign",
+	Long: `Add storefronts to a campaign's countriesOrRegions.
+
+Apple refuses to change a campaign's countries while any of its ad groups have
+geo targeting (country, admin area, or locality), unless the change clears that
+targeting. In that case this command lists the affected ad groups and asks for
+confirmation (or --yes), clears the targeting as part of the update, and then
+restores each ad group's geo targeting. The targeting is saved to a file under
+~/.asa-cli/geo-snapshots/ first, so it can be re-applied with
+"campaigns restore-geo" if the run is interrupted. With --copy-geo-from, ad
+groups whose country targeting includes that country also get the new
+countries.
+
+Keywords are not localized for the new storefronts; use "keywords localize"
+for that.
+
+Example:
+  asa-cli campaigns add-countries 123 --countries JP,KR --copy-geo-from US`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCampaignsAddCountries,
+}
+
+var campaignsRestoreGeoCmd = &cobra.Command{
+	Use:   "restore-geo <file>",
+	Short: "Re-apply ad group geo targeting saved by add-countries",
+	Long: `Set each ad group in a geo snapshot written by "campaigns add-countries" back
+to the targeting it had, e.g. after a run was interrupted between clearing and
+restoring it.
+
+Example:
+  asa-cli campaigns restore-geo ~/.asa-cli/geo-snapshots/123-20260102-150405.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCampaignsRestoreGeo,
+}
+
+var (
+	addCountries    string
+	addCopyGeoFrom  string
+	addCountriesYes bool
+)
+
+func init() {
+	campaignsAddCountriesCmd.Flags().StringVar(&addCountries, "countries", "", "Comma-separated country codes to add (required)")
+	campaignsAddCountriesCmd.Flags().StringVar(&addCopyGeoFrom, "copy-geo-from", "", "Extend ad group country targeting of this country to the new ones")
+	campaignsAddCountriesCmd.Flags().BoolVar(&addCountriesYes, "yes", false, "Don't ask before clearing and restoring geo targeting")
+	campaignsAddCountriesCmd.MarkFlagRequired("countries")
+
+	campaignsCmd.AddCommand(campaignsAddCountriesCmd)
+	campaignsCmd.AddCommand(campaignsRestoreGeoCmd)
+}
+
+// geoSnapshot is an ad group's geo targeting, saved before it is cleared.
+type geoSnapshot struct {
+	AdGroupID int64                       `json:"adGroupId"`
+	Name      string                      `json:"name"`
+	Targeting *models.TargetingDimensions `json:"targetingDimensions"`
+}
+
+// geoSnapshotFile is the file add-countries writes before clearing geo
+// targeting.
+type geoSnapshotFile struct {
+	CampaignID int64         `json:"campaignId"`
+	Countries  []string      `json:"countriesOrRegions"`
+	SavedAt    time.Time     `json:"savedAt"`
+	AdGroups   []geoSnapshot `json:"adGroups"`
+}
+
+// saveGeoSnapshots writes the ad groups' geo targeting under the config
+// directory and returns the file's path. suffix tells apart the files of one
+// run.
+func saveGeoSnapshots(campaign *models.Campaign, snapshots []geoSnapshot, suffix string) (string, error) {
+	now := time.Now()
+	path := filepath.Join(config.ConfigDir(), "geo-snapshots", fmt.Sprintf("%d-%s%s.json", campaign.ID, now.Format("20060102-150405"), suffix))
+	err := config.SaveJSON(path, "geo snapshot", geoSnapshotFile{
+		CampaignID: campaign.ID,
+		Countries:  campaign.CountriesOrRegions,
+		SavedAt:    now.UTC(),
+		AdGroups:   snapshots,
+	})
+	return path, err
+}
+
+func runCampaignsAddCountries(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid campaign ID: %s", args[0])
+	}
+	source := strings.ToUpper(strings.TrimSpace(addCopyGeoFrom))
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewCampaignService(client)
+	campaign, err := svc.Get(id)
+	if err != nil {
+		return fmt.Errorf("getting campaign: %w", err)
+	}
+
+	countries := append([]string(nil), campaign.CountriesOrRegions...)
+	var added []string
+	for _, c := range splitList(strings.ToUpper(addCountries)) {
+		if !containsString(countries, c) {
+			countries = append(countries, c)
+			added = append(added, c)
+		}
+	}
+	if len(added) == 0 {
+		fmt.Printf("Campaign %d already serves %s.\n", id, strings.ToUpper(addCountries))
+		return nil
+	}
+	if source != "" && !containsString(campaign.CountriesOrRegions, source) {
+		return fmt.Errorf("--copy-geo-from %s is not one of the campaign's countries (%s)", source, strings.Join(campaign.CountriesOrRegions, ","))
+	}
+
+	adGroups, err := services.NewAdGroupService(client).FindAll(id, models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("finding ad groups: %w", err)
+	}
+	var snapshots []geoSnapshot
+	var snapshotPath string
+	for _, ag := range adGroups {
+		if geo := geoTargeting(ag.TargetingDimensions); geo != nil {
+			snapshots = append(snapshots, geoSnapshot{AdGroupID: ag.ID, Name: ag.Name, Targeting: geo})
+		}
+	}
+
+	if len(snapshots) > 0 {
+		fmt.Fprintf(os.Stderr, "%d ad group(s) have geo targeting that Apple clears when countries change:\n", len(snapshots))
+		for _, s := range snapshots {
+			fmt.Fprintf(os.Stderr, "  %d %s\n", s.AdGroupID, s.Name)
+		}
+		fmt.Fprintln(os.Stderr, "Their targeting will be restored after the update.")
+		if !addCountriesYes && !confirm("Clear and restore geo targeting?") {
+			return fmt.Errorf("aborted; no changes made")
+		}
+		path, err := saveGeoSnapshots(campaign, snapshots, "")
+		if err != nil {
+			return fmt.Errorf("%w; no changes made", err)
+		}
+		fmt.Fprintf(os.Stderr, "Saved their geo targeting to %s\n", path)
+		snapshotPath = path
+	}
+
+	updated, err := svc.UpdateCountries(id, countries, len(snapshots) > 0)
+	if err != nil {
+		return fmt.Errorf("updating campaign: %w", err)
+	}
+
+	agSvc := services.NewAdGroupService(client)
+	var failed []geoSnapshot
+	bar := progress.New("Restoring geo targeting", len(snapshots))
+	for _, s := range snapshots {
+		targeting := s.Targeting
+		if source != "" {
+			targeting = extendCountryTargeting(targeting, source, added)
+		}
+		if _, err := agSvc.Update(id, s.AdGroupID, &models.AdGroupUpdate{TargetingDimensions: targeting}); err != nil {
+			fmt.Fprintf(os.Stderr, "Restoring geo targeting of ad group %d: %v\n", s.AdGroupID, err)
+			s.Targeting = targeting
+			failed = append(failed, s)
+		}
+		bar.Add(1)
+	}
+	bar.Done()
+
+	output.Print(getFormat(), updated, campaignColumns)
+	if len(failed) > 0 {
+		path, err := saveGeoSnapshots(campaign, failed, "-unrestored")
+		if err != nil {
+			path = snapshotPath
+		}
+		fmt.Fprintf(os.Stderr, "Geo targeting of %d ad group(s) was not restored; re-apply it with:\n  asa-cli campaigns restore-geo %s\n", len(failed), path)
+		return fmt.Errorf("restoring geo targeting failed for %d ad group(s)", len(failed))
+	}
+	return nil
+}
+
+func runCampaignsRestoreGeo(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(config.ExpandPath(args[0]))
+	if err != nil {
+		return fmt.Errorf("reading geo snapshot: %w", err)
+	}
+	var snap geoSnapshotFile
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("parsing geo snapshot %s: %w", args[0], err)
+	}
+	if snap.CampaignID == 0 || len(snap.AdGroups) == 0 {
+		return fmt.Errorf("%s has no ad group targeting to restore", args[0])
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	agSvc := services.NewAdGroupService(client)
+	restored := []geoSnapshot{}
+	failed := 0
+	bar := progress.New("Restoring geo targeting", len(snap.AdGroups))
+	for _, s := range snap.AdGroups {
+		if _, err := agSvc.Update(snap.CampaignID, s.AdGroupID, &models.AdGroupUpdate{TargetingDimensions: s.Targeting}); err != nil {
+			fmt.Fprintf(os.Stderr, "Restoring geo targeting of ad group %d: %v\n", s.AdGroupID, err)
+			failed++
+		} else {
+			restored = append(restored, s)
+		}
+		bar.Add(1)
+	}
+	bar.Done()
+
+	output.Print(getFormat(), restored, []output.Column{
+		{Header: "AD GROUP ID", Field: "AdGroupID"},
+		{Header: "NAME", Field: "Name"},
+	})
+	if failed > 0 {
+		return fmt.Errorf("restoring geo targeting failed for %d ad group(s)", failed)
+	}
+	return nil
+}
+
+// geoTargeting returns the country, admin area, and locality dimensions of t,
+// or nil if none are set.
+func geoTargeting(t *models.TargetingDimensions) *models.TargetingDimensions {
+	if t == nil || (t.Country == nil && t.AdminArea == nil && t.Locality == nil) {
+		return nil
+	}
+	return &models.TargetingDimensions{Country: t.Country, AdminArea: t.AdminArea, Locality: t.Locality}
+}
+
+// extendCountryTargeting adds countries to t's included countries when they
+// include source.
+func extendCountryTargeting(t *models.TargetingDimensions, source string, countries []string) *models.TargetingDimensions {
+	if t.Country == nil {
+		return t
+	}
+	included := false
+	for _, v := range t.Country.Included {
+		if s, ok := v.(string); ok && strings.EqualFold(s, source) {
+			included = true
+		}
+	}
+	if !included {
+		return t
+	}
+	out := *t
+	country := *t.Country
+	country.Included = append([]interface{}(nil), t.Country.Included...)
+	for _, c := range countries {
+		country.Included = append(country.Included, c)
+	}
+	out.Country = &country
+	return &out
+}
+
+// confirm asks a yes/no question on stderr. Without a terminal it answers no.
+func confirm(question string) bool {
+	if fd := os.Stdin.Fd(); !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd) {
+		fmt.Fprintln(os.Stderr, "Not a terminal; pass --yes to confirm.")
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,371 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/changeset"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var changesetCmd = &cobra.Command{
+	Use:   "changeset",
+	Short: "Stage several edits and apply them together",
+	Long: `Stage edits into a change set, then apply them together with one summary.
+
+Before each edit runs, the state it changes is recorded so it can be undone.
+If an edit fails, the edits already applied are rolled back in reverse order.
+A successful apply writes a <name>-rollback change set that undoes the whole
+set when applied.
+
+Only reversible commands can be staged:
+  ` + strings.Join(stageableCommands(), "\n  ") + `
+
+Example:
+  asa-cli changeset begin q3-shift
+  asa-cli changeset add campaigns update 123 --daily-budget 80
+  asa-cli changeset add keywords update --campaign-id 123 --adgroup-id 456 --id 789 --status PAUSED
+  asa-cli changeset add negative-keywords campaign-create --campaign-id 123 --text "free"
+  asa-cli changeset apply`,
+}
+
+var changesetBeginCmd = &cobra.Command{
+	Use:   "begin <name>",
+	Short: "Start a change set and make it current",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runChangesetBegin,
+}
+
+var changesetAddCmd = &cobra.Command{
+	Use:                "add <command> [flags]",
+	Short:              "Stage a command in the current change set",
+	Args:               cobra.MinimumNArgs(1),
+	DisableFlagParsing: true,
+	RunE:               runChangesetAdd,
+}
+
+var changesetShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a change set's items (default: the current one)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runChangesetShow,
+}
+
+var changesetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List change sets",
+	RunE:  runChangesetList,
+}
+
+var changesetApplyCmd = &cobra.Command{
+	Use:   "apply [name]",
+	Short: "Apply a change set (default: the current one)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runChangesetApply,
+}
+
+var changesetDiscardCmd = &cobra.Command{
+	Use:   "discard [name]",
+	Short: "Delete a change set (default: the current one)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runChangesetDiscard,
+}
+
+var csNoRollback bool
+
+func init() {
+	changesetApplyCmd.Flags().BoolVar(&csNoRollback, "no-rollback", false, "Keep the edits already applied when one fails")
+
+	changesetCmd.AddCommand(changesetBeginCmd, changesetAddCmd, changesetShowCmd, changesetListCmd, changesetApplyCmd, changesetDiscardCmd)
+	rootCmd.AddCommand(changesetCmd)
+
+	// In approval mode the whole change set is one proposal.
+	markMutating(nil, changesetApplyCmd)
+}
+
+type changesetItemRow struct {
+	Num      int    `json:"-"`
+	Status   string `json:"-"`
+	Command  string `json:"-"`
+	Rollback string `json:"-"`
+}
+
+type changesetRow struct {
+	changeset.Changeset
+	Current string `json:"-"`
+	Count   int    `json:"-"`
+	Created string `json:"-"`
+}
+
+// changesetName returns args[0], or the current change set.
+func changesetName(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	name := changeset.Current(profileName)
+	if name == "" {
+		return "", fmt.Errorf("no current change set; start one with changeset begin <name>")
+	}
+	return name, nil
+}
+
+func runChangesetBegin(cmd *cobra.Command, args []string) error {
+	if changeset.Exists(profileName, args[0]) {
+		return fmt.Errorf("change set %q already exists", changeset.Normalize(args[0]))
+	}
+	c, err := changeset.New(args[0])
+	if err != nil {
+		return err
+	}
+	if err := changeset.Save(profileName, c); err != nil {
+		return err
+	}
+	if err := changeset.SetCurrent(profileName, c.Name); err != nil {
+		return err
+	}
+	fmt.Printf("Started change set %s\n", c.Name)
+	return nil
+}
+
+func runChangesetAdd(cmd *cobra.Command, args []string) error {
+	// Flag parsing is off so the staged command keeps its flags; handle
+	// help here instead.
+	if args[0] == "-h" || args[0] == "--help" {
+		return cmd.Help()
+	}
+	name, err := changesetName(nil)
+	if err != nil {
+		return err
+	}
+	c, err := changeset.Load(profileName, name)
+	if err != nil {
+		return err
+	}
+	if c.Status != changeset.StatusOpen {
+		return fmt.Errorf("change set %s is already %s", c.Name, c.Status)
+	}
+	staged, err := stageCommand(args)
+	if err != nil {
+		return err
+	}
+	c.Add(staged)
+	if err := changeset.Save(profileName, c); err != nil {
+		return err
+	}
+	fmt.Printf("Staged #%d in %s: %s\n", len(c.Items), c.Name, strings.Join(staged, " "))
+	return nil
+}
+
+func runChangesetShow(cmd *cobra.Command, args []string) error {
+	name, err := changesetName(args)
+	if err != nil {
+		return err
+	}
+	c, err := changeset.Load(profileName, name)
+	if err != nil {
+		return err
+	}
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, c, nil)
+		return nil
+	}
+	printChangesetItems(c)
+	return nil
+}
+
+func printChangesetItems(c *changeset.Changeset) {
+	rows := []changesetItemRow{}
+	for i, it := range c.Items {
+		var undo []string
+		for _, line := range it.Rollback {
+			undo = append(undo, strings.Join(line, " "))
+		}
+		status := it.Status
+		if it.Error != "" {
+			status += ": " + it.Error
+		}
+		rows = append(rows, changesetItemRow{Num: i + 1, Status: status, Command: it.Command, Rollback: strings.Join(undo, "; ")})
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "#", Field: "Num"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "COMMAND", Field: "Command"},
+		{Header: "ROLLBACK", Field: "Rollback"},
+	})
+}
+
+func runChangesetList(cmd *cobra.Command, args []string) error {
+	list, err := changeset.List(profileName)
+	if err != nil {
+		return err
+	}
+	current := changeset.Current(profileName)
+	rows := []changesetRow{}
+	for _, c := range list {
+		row := changesetRow{Changeset: c, Count: len(c.Items), Created: c.CreatedAt.Local().Format("2006-01-02 15:04")}
+		if c.Name == current {
+			row.Current = "*"
+		}
+		rows = append(rows, row)
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "", Field: "Current"},
+		{Header: "NAME", Field: "Name"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "ITEMS", Field: "Count"},
+		{Header: "CREATED", Field: "Created"},
+	})
+	return nil
+}
+
+func runChangesetDiscard(cmd *cobra.Command, args []string) error {
+	name, err := changesetName(args)
+	if err != nil {
+		return err
+	}
+	if err := changeset.Delete(profileName, name); err != nil {
+		return err
+	}
+	fmt.Printf("Discarded change set %s\n", changeset.Normalize(name))
+	return nil
+}
+
+func runChangesetApply(cmd *cobra.Command, args []string) error {
+	name, err := changesetName(args)
+	if err != nil {
+		return err
+	}
+	c, err := changeset.Load(profileName, name)
+	if err != nil {
+		return err
+	}
+	if c.Status != changeset.StatusOpen {
+		return fmt.Errorf("change set %s is already %s", c.Name, c.Status)
+	}
+	if len(c.Items) == 0 {
+		return fmt.Errorf("change set %s is empty", c.Name)
+	}
+
+	failed := -1
+	for i := range c.Items {
+		it := &c.Items[i]
+		fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", i+1, len(c.Items), it.Command)
+		undo, err := prepareRollback(it.Args)
+		if err == nil {
+			var out []byte
+			out, err = captureStdout(func() error {
+				return runProposal(append(append([]string(nil), it.Args...), "--output=json", "--envelope=false"))
+			})
+			if err == nil {
+				it.Rollback, err = undo(out)
+				if err != nil {
+					err = fmt.Errorf("applied, but can't be rolled back: %w", err)
+				}
+			}
+		}
+		if err != nil {
+			it.Status, it.Error = changeset.ItemFailed, err.Error()
+			failed = i
+			break
+		}
+		it.Status = changeset.ItemApplied
+	}
+
+	now := time.Now().UTC()
+	c.AppliedAt = &now
+	c.Status = changeset.StatusApplied
+	if failed >= 0 {
+		for i := failed + 1; i < len(c.Items); i++ {
+			c.Items[i].Status = changeset.ItemSkipped
+		}
+		c.Status = changeset.StatusFailed
+		if !csNoRollback && failed > 0 {
+			c.Status = changeset.StatusRolledBack
+			if !rollBackItems(c.Items[:failed]) {
+				c.Status = changeset.StatusFailed
+			}
+		}
+	}
+	if changeset.Current(profileName) == c.Name {
+		changeset.SetCurrent(profileName, "")
+	}
+	if err := changeset.Save(profileName, c); err != nil {
+		return err
+	}
+
+	rollback, err := writeRollbackChangeset(c)
+	if err != nil {
+		return err
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, c, nil)
+	} else {
+		printChangesetItems(c)
+	}
+	if rollback != "" {
+		fmt.Fprintf(os.Stderr, "To undo: asa-cli changeset apply %s\n", rollback)
+	}
+	if failed >= 0 {
+		return fmt.Errorf("change set %s %s: item %d failed: %s", c.Name, c.Status, failed+1, c.Items[failed].Error)
+	}
+	fmt.Fprintf(os.Stderr, "Applied %d change(s) from %s.\n", len(c.Items), c.Name)
+	return nil
+}
+
+// rollBackItems undoes applied items, last first, and reports whether every
+// rollback command succeeded.
+func rollBackItems(items []changeset.Item) bool {
+	ok := true
+	for i := len(items) - 1; i >= 0; i-- {
+		it := &items[i]
+		if it.Status != changeset.ItemApplied {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Rolling back: %s\n", it.Command)
+		var failed []string
+		for _, line := range it.Rollback {
+			if _, err := captureStdout(func() error { return runProposal(line) }); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", strings.Join(line, " "), err))
+			}
+		}
+		if len(failed) > 0 {
+			it.Error = "rollback failed: " + strings.Join(failed, "; ")
+			ok = false
+			continue
+		}
+		it.Status = changeset.ItemRolledBack
+	}
+	return ok
+}
+
+// writeRollbackChangeset stores the rollback commands of c's applied items,
+// last first, as a new open change set, and returns its name ("" if there is
+// nothing to undo).
+func writeRollbackChangeset(c *changeset.Changeset) (string, error) {
+	var lines [][]string
+	for i := len(c.Items) - 1; i >= 0; i-- {
+		if c.Items[i].Status == changeset.ItemApplied {
+			lines = append(lines, c.Items[i].Rollback...)
+		}
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	name := c.Name + "-rollback"
+	for n := 2; changeset.Exists(profileName, name); n++ {
+		name = fmt.Sprintf("%s-rollback-%d", c.Name, n)
+	}
+	rb, err := changeset.New(name)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range lines {
+		rb.Add(line)
+	}
+	return rb.Name, changeset.Save(profileName, rb)
+}
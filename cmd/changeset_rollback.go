@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/accounts"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+// undoFunc returns the command lines that undo a command, given what it
+// printed as JSON.
+type undoFunc func(out []byte) ([][]string, error)
+
+// rollbackPreparers record, before a command runs, what is needed to undo
+// it. Only these commands can be staged in a change set.
+var rollbackPreparers = map[string]func(client *api.Client, s staged) (undoFunc, error){
+	"campaigns update":                  undoCampaignUpdate,
+	"adgroups update":                   undoAdGroupUpdate,
+	"adgroups set-bidding":              undoAdGroupUpdate,
+	"keywords update":                   undoKeywordUpdate,
+	"keywords create":                   undoCreate("keywords delete", "campaign-id", "adgroup-id"),
+	"keywords delete":                   undoKeywordDelete,
+	"negative-keywords campaign-create": undoCreate("negative-keywords campaign-delete", "campaign-id"),
+	"negative-keywords adgroup-create":  undoCreate("negative-keywords adgroup-delete", "campaign-id", "adgroup-id"),
+	"negative-keywords campaign-delete": undoNegativeDelete,
+	"negative-keywords adgroup-delete":  undoNegativeDelete,
+}
+
+func stageableCommands() []string {
+	var paths []string
+	for p := range rollbackPreparers {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// staged is a canonical command line split into its command path, flag
+// values, and positional arguments.
+type staged struct {
+	path  string
+	flags map[string][]string
+	args  []string
+}
+
+func (s staged) flag(name string) string {
+	if v := s.flags[name]; len(v) > 0 {
+		return v[len(v)-1]
+	}
+	return ""
+}
+
+func (s staged) int64Flag(name string) int64 {
+	v, _ := strconv.ParseInt(s.flag(name), 10, 64)
+	return v
+}
+
+func (s staged) has(name string) bool {
+	_, ok := s.flags[name]
+	return ok
+}
+
+// parseStaged splits a line produced by commandLine.
+func parseStaged(line []string) (staged, error) {
+	cmd, _, err := rootCmd.Find(line)
+	if err != nil {
+		return staged{}, err
+	}
+	depth := len(strings.Fields(cmd.CommandPath())) - 1
+	s := staged{path: strings.Join(line[:depth], " "), flags: map[string][]string{}}
+	for _, a := range line[depth:] {
+		if name, value, ok := strings.Cut(strings.TrimPrefix(a, "--"), "="); ok && strings.HasPrefix(a, "--") {
+			s.flags[name] = append(s.flags[name], value)
+			continue
+		}
+		s.args = append(s.args, a)
+	}
+	return s, nil
+}
+
+// stageCommand validates a command for a change set and returns it in
+// canonical form, with every flag as --name=value.
+func stageCommand(raw []string) ([]string, error) {
+	if len(raw) > 0 && raw[0] == "asa-cli" {
+		raw = raw[1:]
+	}
+	raw = expandAliases(raw)
+	cmd, rest, err := rootCmd.Find(raw)
+	if err != nil {
+		return nil, err
+	}
+	path := strings.Join(strings.Fields(cmd.CommandPath())[1:], " ")
+	if _, ok := rollbackPreparers[path]; !ok {
+		return nil, fmt.Errorf("%q can't be staged; change sets take: %s", path, strings.Join(stageableCommands(), ", "))
+	}
+
+	baseline := snapshotFlags(rootCmd)
+	defer restoreFlags(baseline)
+	if err := cmd.ParseFlags(rest); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cmd.ValidateRequiredFlags(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cmd.ValidateArgs(cmd.Flags().Args()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return commandLine(cmd, cmd.Flags().Args()), nil
+}
+
+// prepareRollback records the state a staged command is about to change,
+// reading it from the account the command will run in.
+func prepareRollback(line []string) (undoFunc, error) {
+	s, err := parseStaged(line)
+	if err != nil {
+		return nil, err
+	}
+	prepare, ok := rollbackPreparers[s.path]
+	if !ok {
+		return nil, fmt.Errorf("%q can't be rolled back", s.path)
+	}
+	client, err := stagedClient(s)
+	if err != nil {
+		return nil, err
+	}
+	undo, err := prepare(client, s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Undo in the same account the command ran in.
+	var scope []string
+	for _, name := range []string{"profile", "account", "org-id"} {
+		if s.has(name) {
+			scope = append(scope, "--"+name+"="+s.flag(name))
+		}
+	}
+	return func(out []byte) ([][]string, error) {
+		lines, err := undo(out)
+		for i := range lines {
+			lines[i] = append(lines[i], scope...)
+		}
+		return lines, err
+	}, nil
+}
+
+// stagedClient returns a client for the profile and org a staged command
+// runs in: its own --account, --profile, and --org-id over the current ones.
+func stagedClient(s staged) (*api.Client, error) {
+	prevProfile, prevOrg := profileName, globalOrgID
+	defer func() {
+		profileName, globalOrgID = prevProfile, prevOrg
+		config.SetProfile(profileName)
+	}()
+	if s.has("account") {
+		reg, err := accounts.Load()
+		if err != nil {
+			return nil, err
+		}
+		a, err := reg.Get(s.flag("account"))
+		if err != nil {
+			return nil, err
+		}
+		profileName, globalOrgID = a.Profile, a.OrgID
+	}
+	if s.has("profile") {
+		profileName = s.flag("profile")
+	}
+	if s.has("org-id") {
+		globalOrgID = s.flag("org-id")
+	}
+	config.SetProfile(profileName)
+	return newAPIClient()
+}
+
+// fixed returns an undoFunc that ignores the command's output.
+func fixed(lines ...[]string) undoFunc {
+	return func([]byte) ([][]string, error) { return lines, nil }
+}
+
+func moneyAmount(m *models.Money) string {
+	if m == nil {
+		return ""
+	}
+	return m.Amount
+}
+
+// restoreLine builds "<path> <id> --flag=old..." for the flags s changes.
+// Flags whose old value is unknown (empty) can't be restored.
+func restoreLine(path, id string, s staged, old map[string]string, keep ...string) ([]string, error) {
+	line := append(strings.Fields(path), id)
+	for _, name := range keep {
+		line = append(line, "--"+name+"="+s.flag(name))
+	}
+	var names []string
+	for name := range s.flags {
+		if _, tracked := old[name]; tracked {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if old[name] == "" {
+			return nil, fmt.Errorf("--%s had no previous value to restore", name)
+		}
+		line = append(line, "--"+name+"="+old[name])
+	}
+	return line, nil
+}
+
+func undoCampaignUpdate(client *api.Client, s staged) (undoFunc, error) {
+	if s.has("file") {
+		return nil, fmt.Errorf("--file updates can't be rolled back; use flags")
+	}
+	id, err := strconv.ParseInt(s.args[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid campaign ID: %s", s.args[0])
+	}
+	c, err := services.NewCampaignService(client).Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("getting campaign %d: %w", id, err)
+	}
+	line, err := restoreLine("campaigns update", s.args[0], s, map[string]string{
+		"name":         c.Name,
+		"budget":       moneyAmount(c.BudgetAmount),
+		"daily-budget": moneyAmount(c.DailyBudgetAmount),
+		"status":       string(c.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fixed(line), nil
+}
+
+func undoAdGroupUpdate(client *api.Client, s staged) (undoFunc, error) {
+	if s.has("file") {
+		return nil, fmt.Errorf("--file updates can't be rolled back; use flags")
+	}
+	id, err := strconv.ParseInt(s.args[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ad group ID: %s", s.args[0])
+	}
+	ag, err := services.NewAdGroupService(client).Get(s.int64Flag("campaign-id"), id)
+	if err != nil {
+		return nil, fmt.Errorf("getting ad group %d: %w", id, err)
+	}
+	line, err := restoreLine("adgroups update", s.args[0], s, map[string]string{
+		"name":          ag.Name,
+		"default-bid":   moneyAmount(ag.DefaultBidAmount),
+		"cpa-goal":      moneyAmount(ag.CpaGoal),
+		"status":        string(ag.Status),
+		"auto-keywords": strconv.FormatBool(ag.AutomatedKeywordsOptIn),
+		"start-time":    ag.StartTime,
+		"end-time":      ag.EndTime,
+	}, "campaign-id")
+	if err != nil {
+		return nil, err
+	}
+	return fixed(line), nil
+}
+
+func undoKeywordUpdate(client *api.Client, s staged) (undoFunc, error) {
+	kw, err := services.NewKeywordService(client).Get(s.int64Flag("campaign-id"), s.int64Flag("adgroup-id"), s.int64Flag("id"))
+	if err != nil {
+		return nil, fmt.Errorf("getting keyword %s: %w", s.flag("id"), err)
+	}
+	line := []string{"keywords", "update",
+		"--campaign-id=" + s.flag("campaign-id"), "--adgroup-id=" + s.flag("adgroup-id"), "--id=" + s.flag("id")}
+	if s.has("status") {
+		line = append(line, "--status="+string(kw.Status))
+	}
+	if s.has("bid") {
+		if kw.BidAmount == nil {
+			return nil, fmt.Errorf("--bid had no previous value to restore")
+		}
+		line = append(line, "--bid="+kw.BidAmount.Amount)
+	}
+	return fixed(line), nil
+}
+
+// undoCreate deletes what a create command printed, passing on the given
+// scope flags.
+func undoCreate(deletePath string, scope ...string) func(*api.Client, staged) (undoFunc, error) {
+	return func(client *api.Client, s staged) (undoFunc, error) {
+		return func(out []byte) ([][]string, error) {
+			ids, err := createdIDs(out)
+			if err != nil {
+				return nil, err
+			}
+			if len(ids) == 0 {
+				return nil, nil
+			}
+			parts := make([]string, len(ids))
+			for i, id := range ids {
+				parts[i] = strconv.FormatInt(id, 10)
+			}
+			line := append(strings.Fields(deletePath), strings.Join(parts, ","))
+			for _, name := range scope {
+				line = append(line, "--"+name+"="+s.flag(name))
+			}
+			return [][]string{line}, nil
+		}, nil
+	}
+}
+
+// createdIDs reads the IDs from a create command's JSON output, a list or a
+// single entity.
+func createdIDs(out []byte) ([]int64, error) {
+	type entity struct {
+		ID int64 `json:"id"`
+	}
+	var list []entity
+	if err := json.Unmarshal(out, &list); err != nil {
+		var one entity
+		if err := json.Unmarshal(out, &one); err != nil {
+			return nil, fmt.Errorf("reading created IDs: %w", err)
+		}
+		list = []entity{one}
+	}
+	var ids []int64
+	for _, e := range list {
+		if e.ID != 0 {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids, nil
+}
+
+func undoKeywordDelete(client *api.Client, s staged) (undoFunc, error) {
+	ids, err := parseIDList(s.args[0])
+	if err != nil {
+		return nil, err
+	}
+	svc := services.NewKeywordService(client)
+	type group struct{ matchType, bid string }
+	texts := map[group][]string{}
+	var order []group
+	for _, id := range ids {
+		kw, err := svc.Get(s.int64Flag("campaign-id"), s.int64Flag("adgroup-id"), id)
+		if err != nil {
+			return nil, fmt.Errorf("getting keyword %d: %w", id, err)
+		}
+		g := group{string(kw.MatchType), moneyAmount(kw.BidAmount)}
+		if _, seen := texts[g]; !seen {
+			order = append(order, g)
+		}
+		texts[g] = append(texts[g], kw.Text)
+	}
+
+	var lines [][]string
+	for _, g := range order {
+		line := []string{"keywords", "create", "--campaign-id=" + s.flag("campaign-id"), "--adgroup-id=" + s.flag("adgroup-id"), "--match-type=" + g.matchType}
+		if g.bid != "" {
+			line = append(line, "--bid="+g.bid)
+		}
+		for _, t := range texts[g] {
+			line = append(line, "--text="+t)
+		}
+		lines = append(lines, line)
+	}
+	return fixed(lines...), nil
+}
+
+func undoNegativeDelete(client *api.Client, s staged) (undoFunc, error) {
+	ids, err := parseIDList(s.args[0])
+	if err != nil {
+		return nil, err
+	}
+	svc := services.NewKeywordService(client)
+	adGroup := s.path == "negative-keywords adgroup-delete"
+	texts := map[models.MatchType][]string{}
+	var order []models.MatchType
+	for _, id := range ids {
+		var kw *models.NegativeKeyword
+		if adGroup {
+			kw, err = svc.GetAdGroupNegativeKeyword(s.int64Flag("campaign-id"), s.int64Flag("adgroup-id"), id)
+		} else {
+			kw, err = svc.GetCampaignNegativeKeyword(s.int64Flag("campaign-id"), id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting negative keyword %d: %w", id, err)
+		}
+		if _, seen := texts[kw.MatchType]; !seen {
+			order = append(order, kw.MatchType)
+		}
+		texts[kw.MatchType] = append(texts[kw.MatchType], kw.Text)
+	}
+
+	var lines [][]string
+	for _, mt := range order {
+		line := []string{"negative-keywords", "campaign-create", "--campaign-id=" + s.flag("campaign-id")}
+		if adGroup {
+			line = []string{"negative-keywords", "adgroup-create", "--campaign-id=" + s.flag("campaign-id"), "--adgroup-id=" + s.flag("adgroup-id")}
+		}
+		line = append(line, "--match-type="+string(mt))
+		for _, t := range texts[mt] {
+			line = append(line, "--text="+t)
+		}
+		lines = append(lines, line)
+	}
+	return fixed(lines...), nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/chaos"
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// faults holds --fail-rate and --inject-429. They are hidden: they exist to
+// test scripts' retry and resume handling, not for everyday use.
+var faults chaos.Transport
+
+func init() {
+	rootCmd.PersistentFlags().Float64Var(&faults.FailRate, "fail-rate", 0, "Fail this share of API requests (0-1) with a 500, 503, or dropped connection, without sending them")
+	rootCmd.PersistentFlags().Float64Var(&faults.RateLimitRate, "inject-429", 0, "Answer this share of API requests (0-1) with a 429 rate limit, without sending them")
+	rootCmd.PersistentFlags().MarkHidden("fail-rate")
+	rootCmd.PersistentFlags().MarkHidden("inject-429")
+}
+
+// checkFaults refuses --fail-rate and --inject-429 unless nothing the
+// command sends can change the account: the requests that aren't failed go
+// to Apple, so a write would be applied while its neighbours fail.
+func checkFaults(cmd *cobra.Command) error {
+	if !faults.Enabled() || readOnly || explainFlag {
+		return nil
+	}
+	if f := cmd.Flags().Lookup("dry-run"); f != nil && f.Value.String() == "true" {
+		return nil
+	}
+	// Commands that only write with --apply (or whose markMutating when func
+	// says this run won't write) are as safe as a dry run.
+	if f := cmd.Flags().Lookup("apply"); f != nil && f.Value.String() == "false" {
+		return nil
+	}
+	if when, ok := writesWhen[cmd]; ok && !when() {
+		return nil
+	}
+	if cfg, err := config.Load(); err == nil && cfg.ReadOnly {
+		return nil
+	}
+	return fmt.Errorf("--fail-rate and --inject-429 require --read-only, --dry-run, or --explain: requests they don't fail are sent to Apple")
+}
+
+// injectFaults wraps rt so that it fails requests at the --fail-rate and
+// --inject-429 rates. Without them it returns rt unchanged.
+func injectFaults(rt http.RoundTripper) http.RoundTripper {
+	if !faults.Enabled() {
+		return rt
+	}
+	t := faults
+	t.Base = rt
+	return &t
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/progress"
+)
+
+var compareProfilesCmd = &cobra.Command{
+	Use:   "compare-profiles",
+	Short: "Run a read command under several profiles side by side",
+	Long: `Run the same command once per profile, in one process and with --read-only,
+then show the results side by side: one row per profile with its org, and for
+reports the spend, installs, and CPI of all rows. Other commands show how many
+rows each profile returned. -o json includes each profile's full output.
+
+Example:
+  asa-cli compare-profiles --profiles clientA,clientB --command "reports campaigns --start-date 2024-06-01 --end-date 2024-06-07"`,
+	Args: cobra.NoArgs,
+	RunE: runCompareProfiles,
+}
+
+var (
+	compareProfiles []string
+	compareCommand  string
+)
+
+func init() {
+	compareProfilesCmd.Flags().StringSliceVar(&compareProfiles, "profiles", nil, "Comma-separated profile names (required)")
+	compareProfilesCmd.Flags().StringVar(&compareCommand, "command", "", `Command to run under each profile, e.g. "campaigns list --filter status=ENABLED" (required)`)
+	compareProfilesCmd.MarkFlagRequired("profiles")
+	compareProfilesCmd.MarkFlagRequired("command")
+	rootCmd.AddCommand(compareProfilesCmd)
+}
+
+// ProfileResult is one profile's result in a compare-profiles run.
+type ProfileResult struct {
+	Profile string             `json:"profile"`
+	OrgID   string             `json:"orgId,omitempty"`
+	Rows    int                `json:"rows"`
+	Totals  *aggregate.Metrics `json:"totals,omitempty"`
+	Output  json.RawMessage    `json:"output,omitempty"`
+	Error   string             `json:"error,omitempty"`
+
+	Spend    string `json:"-"`
+	Installs string `json:"-"`
+	CPI      string `json:"-"`
+}
+
+func runCompareProfiles(cmd *cobra.Command, args []string) error {
+	inner, err := splitCommandLine(compareCommand)
+	if err != nil {
+		return fmt.Errorf("invalid --command: %w", err)
+	}
+	if len(inner) > 0 && inner[0] == "asa-cli" {
+		inner = inner[1:]
+	}
+	inner = expandAliases(inner)
+	if len(inner) == 0 {
+		return fmt.Errorf("--command is empty")
+	}
+	switch inner[0] {
+	case "compare-profiles", "batch", "shell":
+		return fmt.Errorf("%q cannot be run by compare-profiles", inner[0])
+	}
+
+	// Each run gets the flags as they are now, so one profile's flags never
+	// leak into the next.
+	baseline := snapshotFlags(rootCmd)
+	prevProfile := profileName
+
+	results := make([]ProfileResult, 0, len(compareProfiles))
+	failed := 0
+	bar := progress.New("Running under each profile", len(compareProfiles))
+	for _, p := range compareProfiles {
+		r := runUnderProfile(p, inner, baseline)
+		if r.Error != "" {
+			failed++
+		}
+		results = append(results, r)
+		bar.Add(1)
+	}
+	bar.Done()
+	restoreFlags(baseline)
+	profileName = prevProfile
+	config.SetProfile(profileName)
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, results, nil)
+	} else {
+		columns := []output.Column{
+			{Header: "PROFILE", Field: "Profile"},
+			{Header: "ORG ID", Field: "OrgID"},
+			{Header: "ROWS", Field: "Rows"},
+		}
+		for _, r := range results {
+			if r.Totals != nil {
+				columns = append(columns,
+					output.Column{Header: "SPEND", Field: "Spend"},
+					output.Column{Header: "INSTALLS", Field: "Installs"},
+					output.Column{Header: "CPI", Field: "CPI"},
+				)
+				break
+			}
+		}
+		columns = append(columns, output.Column{Header: "ERROR", Field: "Error"})
+		output.Print(getFormat(), results, columns)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d profile(s) failed", failed, len(compareProfiles))
+	}
+	return nil
+}
+
+// runUnderProfile runs args as a read-only command under profile and
+// summarizes its JSON output.
+func runUnderProfile(profile string, args []string, baseline flagSnapshot) ProfileResult {
+	r := ProfileResult{Profile: profile}
+	restoreFlags(baseline)
+	run := append(append([]string(nil), args...), "--profile", profile, "--output", "json", "--envelope", "--read-only")
+	out, err := captureStdout(func() error {
+		rootCmd.SetArgs(run)
+		return rootCmd.Execute()
+	})
+	r.OrgID = globalOrgID
+	if r.OrgID == "" {
+		if cfg, err := config.Load(); err == nil {
+			r.OrgID = cfg.OrgID
+		}
+	}
+	if err != nil {
+		r.Error, _, _ = strings.Cut(err.Error(), "\n")
+		return r
+	}
+
+	var env struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &env); err != nil || env.Data == nil {
+		r.Output, _ = json.Marshal(string(bytes.TrimSpace(out)))
+		return r
+	}
+	out = env.Data
+	r.Output = out
+
+	var report models.ReportingDataResponse
+	if json.Unmarshal(out, &report) == nil && report.Row != nil {
+		var m aggregate.Metrics
+		for _, row := range report.Row {
+			m.Merge(aggregate.RowTotals(row))
+		}
+		r.Rows, r.Totals = len(report.Row), &m
+		r.Spend = fmt.Sprintf("%.2f %s", m.Spend, m.Currency)
+		r.Installs = fmt.Sprint(m.Installs)
+		r.CPI = fmt.Sprintf("%.2f", m.CPI())
+		return r
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(out, &list) == nil {
+		r.Rows = len(list)
+	} else {
+		r.Rows = 1
+	}
+	return r
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -20,7 +19,7 @@ Credential Setup:
   1. Sign in at https://ads.apple.com
   2. Go to Settings > API tab
   3. Create an API user (or use existing)
-  4. Generate a key pair and upload the public key
+  4. Generate a key pair (asa-cli configure generate-key) and upload the public key
   5. Note the Client ID, Team ID, Key ID from the API settings page
   6. Run: asa-cli configure --client-id "..." --team-id "..." --key-id "..." --private-key-path "/path/to/key.pem"
   7. Verify with: asa-cli whoami
@@ -58,7 +57,10 @@ func runConfigure(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("required flags: --client-id, --team-id, --key-id, --private-key-path\nOptional: --org-id (auto-detected for single-org accounts)")
 	}
 
-	cfgPrivateKeyPath = expandPath(cfgPrivateKeyPath)
+	cfgPrivateKeyPath = config.ExpandPath(cfgPrivateKeyPath)
+	if cfgOrgID == "" {
+		cfgOrgID = configuredOrgID()
+	}
 
 	// Validate key file exists
 	if _, err := os.Stat(cfgPrivateKeyPath); os.IsNotExist(err) {
@@ -99,7 +101,10 @@ func runInteractiveConfigure() error {
 	teamID := prompt(reader, "Team ID")
 	keyID := prompt(reader, "Key ID")
 	orgID := promptOptional(reader, "Org ID (press Enter to skip — auto-detected for single-org accounts)")
-	privateKeyPath := expandPath(prompt(reader, "Private Key Path (.pem or .p8 file)"))
+	privateKeyPath := config.ExpandPath(prompt(reader, "Private Key Path (.pem or .p8 file)"))
+	if orgID == "" {
+		orgID = configuredOrgID()
+	}
 
 	// Validate key file
 	if _, err := os.Stat(privateKeyPath); os.IsNotExist(err) {
@@ -127,15 +132,6 @@ func runInteractiveConfigure() error {
 	return nil
 }
 
-func expandPath(path string) string {
-	if strings.HasPrefix(path, "~/") {
-		if home, err := os.UserHomeDir(); err == nil {
-			return filepath.Join(home, path[2:])
-		}
-	}
-	return path
-}
-
 func prompt(reader *bufio.Reader, label string) string {
 	for {
 		fmt.Printf("%s: ", label)
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"go.yaml.in/yaml/v3"
+)
+
+var configureExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export config.yaml as a shareable bundle",
+	Long: `Print config.yaml (profiles, org IDs, defaults, aliases, presets, policies) as
+YAML. With --no-secrets, each user's credentials (client_id, team_id, key_id,
+private_key_path, google_service_account_path) are left out so the bundle can
+be handed to teammates, who import it and add their own key.
+
+Example:
+  asa-cli configure export --no-secrets --out team.yaml`,
+	RunE: runConfigureExport,
+}
+
+var configureImportCmd = &cobra.Command{
+	Use:   "import <bundle.yaml>",
+	Short: "Merge a shared config bundle into config.yaml",
+	Long: `Merge a bundle produced by "configure export" into config.yaml. Bundle values
+replace local ones, but credentials already configured locally are kept.
+Profiles that still lack credentials are listed afterwards.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigureImport,
+}
+
+var (
+	exportNoSecrets bool
+	exportOut       string
+)
+
+func init() {
+	configureExportCmd.Flags().BoolVar(&exportNoSecrets, "no-secrets", false, "Leave out per-user credentials")
+	configureExportCmd.Flags().StringVar(&exportOut, "out", "", "Write to a file instead of stdout")
+
+	configureCmd.AddCommand(configureExportCmd, configureImportCmd)
+}
+
+func runConfigureExport(cmd *cobra.Command, args []string) error {
+	doc, err := config.Export(exportNoSecrets)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("encoding bundle: %w", err)
+	}
+	if exportOut == "" {
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := os.WriteFile(explainPath(exportOut), data, 0600); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Config exported to %s", exportOut)
+	if !exportNoSecrets {
+		fmt.Fprint(os.Stderr, " (includes credentials; use --no-secrets to share)")
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
+
+func runConfigureImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+	bundle := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	if err := config.Import(bundle); err != nil {
+		return fmt.Errorf("importing bundle: %w", err)
+	}
+	fmt.Printf("Imported %s into %s/config.yaml.\n", args[0], config.ConfigDir())
+
+	doc, err := config.Export(false)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	if v, _ := doc["org_id"].(string); v != "" && !hasCredentials(doc) {
+		missing = append(missing, "default")
+	}
+	profiles, _ := doc["profiles"].(map[string]interface{})
+	for name, p := range profiles {
+		if p, ok := p.(map[string]interface{}); ok && !hasCredentials(p) {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		flag := ""
+		if name != "default" {
+			flag = " -p " + name
+		}
+		fmt.Printf("Profile '%s' needs credentials: asa-cli configure%s --client-id ... --team-id ... --key-id ... --private-key-path ...\n", name, flag)
+	}
+	return nil
+}
+
+// hasCredentials reports whether a config section sets every key needed to
+// authenticate, or a credential source to fetch them from.
+func hasCredentials(section map[string]interface{}) bool {
+	if v, _ := section["credential_source"].(string); v != "" {
+		return true
+	}
+	for _, k := range []string{"client_id", "team_id", "key_id", "private_key_path"} {
+		if v, _ := section[k].(string); v == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// configuredOrgID returns the org ID stored in config.yaml for the active
+// profile, so re-running configure to add a key keeps an imported org ID.
+func configuredOrgID() string {
+	doc, err := config.Export(false)
+	if err != nil {
+		return ""
+	}
+	if profileName != "" && profileName != "default" {
+		profiles, _ := doc["profiles"].(map[string]interface{})
+		doc, _ = profiles[profileName].(map[string]interface{})
+	}
+	orgID, _ := doc["org_id"].(string)
+	return orgID
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+var configureGenerateKeyCmd = &cobra.Command{
+	Use:   "generate-key",
+	Short: "Create an ES256 key pair for API access",
+	Long: `Create a P-256 key pair, store the private key in ~/.asa-cli/keys/<name>.pem
+(mode 0600), and print the public key to paste into the Search Ads UI
+(Account Settings > API). With --copy, the public key is also copied to the
+clipboard.
+
+Example:
+  asa-cli configure generate-key --copy`,
+	RunE: runConfigureGenerateKey,
+}
+
+var (
+	genKeyName  string
+	genKeyForce bool
+	genKeyCopy  bool
+)
+
+func init() {
+	configureGenerateKeyCmd.Flags().StringVar(&genKeyName, "name", "", "Key file name (default: the profile name)")
+	configureGenerateKeyCmd.Flags().BoolVar(&genKeyForce, "force", false, "Overwrite an existing key file")
+	configureGenerateKeyCmd.Flags().BoolVar(&genKeyCopy, "copy", false, "Copy the public key to the clipboard")
+
+	configureCmd.AddCommand(configureGenerateKeyCmd)
+}
+
+func runConfigureGenerateKey(cmd *cobra.Command, args []string) error {
+	name := genKeyName
+	if name == "" {
+		name = profileName
+	}
+	if name == "" {
+		name = "default"
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("--name must be a file name, not a path")
+	}
+
+	dir := filepath.Join(config.ConfigDir(), "keys")
+	path := filepath.Join(dir, name+".pem")
+	if _, err := os.Stat(path); err == nil && !genKeyForce {
+		return fmt.Errorf("%s already exists; pass --force to replace it (the uploaded public key will stop working)", path)
+	}
+
+	key, privPEM, err := auth.GenerateKey()
+	if err != nil {
+		return err
+	}
+	pubPEM, err := auth.PublicKeyPEM(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	path = explainPath(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("cannot create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, privPEM, 0600); err != nil {
+		return fmt.Errorf("writing private key: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("securing private key: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Private key written to %s\n\n", path)
+	fmt.Fprintln(os.Stderr, "Paste this public key in Search Ads > Account Settings > API:")
+	fmt.Print(pubPEM)
+
+	if genKeyCopy {
+		if err := copyToClipboard(pubPEM); err != nil {
+			fmt.Fprintf(os.Stderr, "\nCould not copy to clipboard: %v\n", err)
+		} else {
+			fmt.Fprintln(os.Stderr, "\nPublic key copied to clipboard.")
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "\nThen note the Client ID, Team ID, and Key ID and run:\n")
+	fmt.Fprintf(os.Stderr, "  asa-cli configure --client-id ... --team-id ... --key-id ... --private-key-path %s\n", path)
+	return nil
+}
+
+// copyToClipboard pipes text to the platform's clipboard tool.
+func copyToClipboard(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		candidates = [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return fmt.Errorf("no clipboard tool found")
+}
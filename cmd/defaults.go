@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// applyConfigDefaults sets flags the user didn't pass from the `defaults:`
+// section of config.yaml. The most specific command scope wins, so for
+// "reports keywords" defaults.reports.keywords.limit overrides
+// defaults.reports.limit, which overrides defaults.limit.
+func applyConfigDefaults(cmd *cobra.Command) error {
+	defaults, err := config.Defaults()
+	if err != nil {
+		return err
+	}
+	if len(defaults) == 0 {
+		return nil
+	}
+
+	scopes := []map[string]interface{}{defaults}
+	scope := defaults
+	for _, name := range strings.Fields(cmd.CommandPath())[1:] {
+		next, ok := scope[name].(map[string]interface{})
+		if !ok {
+			break
+		}
+		scopes = append(scopes, next)
+		scope = next
+	}
+
+	values := map[string]interface{}{}
+	for _, s := range scopes {
+		for key, val := range s {
+			if _, isScope := val.(map[string]interface{}); !isScope {
+				values[key] = val
+			}
+		}
+	}
+	for key, val := range values {
+		f := cmd.Flags().Lookup(key)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := setFlagDefault(f, val); err != nil {
+			return fmt.Errorf("invalid config default for --%s: %w", key, err)
+		}
+		// Required flags are validated by their changed state.
+		if _, required := f.Annotations[cobra.BashCompOneRequiredFlag]; required {
+			f.Changed = true
+		}
+	}
+	return nil
+}
+
+func setFlagDefault(f *pflag.Flag, val interface{}) error {
+	if list, ok := val.([]interface{}); ok {
+		var items []string
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			return sv.Replace(items)
+		}
+		return f.Value.Set(strings.Join(items, ","))
+	}
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		return sv.Replace(strings.Split(fmt.Sprint(val), ","))
+	}
+	return f.Value.Set(fmt.Sprint(val))
+}
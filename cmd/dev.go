@@ -0,0 +1,107 @@
+//go:build devtools
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/modelgen"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var devCmd = &cobra.Command{
+	Use:    "dev",
+	Short:  "Tools for working on asa-cli itself",
+	Hidden: true,
+}
+
+var devRegenModelsCmd = &cobra.Command{
+	Use:   "regen-models",
+	Short: "Refresh the generated API models from Apple's OpenAPI spec",
+	Long: `Compare the structs in internal/models/generated.go with an OpenAPI spec of the
+Apple Search Ads API and list the differences; with --write, rewrite the file.
+
+Each generated struct names its schema in its doc comment. Existing fields keep
+their Go name, type and comments, fields new to the spec are appended, and
+fields the spec no longer has are dropped. When the spec's type for a field
+differs from the struct's, the struct's is kept and the difference listed.
+Hand-written types and helpers live in the package's other files and are never
+touched.
+
+Only built with -tags devtools, e.g.:
+  go run -tags devtools . dev regen-models --spec openapi.json
+  go run -tags devtools . dev regen-models --spec openapi.json --add Budget=BudgetOrder --write`,
+	RunE: runDevRegenModels,
+}
+
+var (
+	regenSpec      string
+	regenModelsDir string
+	regenAdd       []string
+	regenWrite     bool
+)
+
+func init() {
+	devRegenModelsCmd.Flags().StringVar(&regenSpec, "spec", "", "OpenAPI spec (JSON) (required)")
+	devRegenModelsCmd.Flags().StringVar(&regenModelsDir, "models-dir", filepath.Join("internal", "models"), "Models package directory")
+	devRegenModelsCmd.Flags().StringSliceVar(&regenAdd, "add", nil, "Generate a new struct, as Type=Schema")
+	devRegenModelsCmd.Flags().BoolVar(&regenWrite, "write", false, "Rewrite the generated file")
+	devRegenModelsCmd.MarkFlagRequired("spec")
+
+	devCmd.AddCommand(devRegenModelsCmd)
+	rootCmd.AddCommand(devCmd)
+}
+
+var modelChangeColumns = []output.Column{
+	{Header: "TYPE", Field: "Type"},
+	{Header: "FIELD", Field: "Field"},
+	{Header: "CHANGE", Field: "Kind"},
+	{Header: "DETAIL", Field: "Detail"},
+}
+
+func runDevRegenModels(cmd *cobra.Command, args []string) error {
+	add := map[string]string{}
+	for _, a := range regenAdd {
+		name, schema, ok := strings.Cut(a, "=")
+		if !ok {
+			schema = name
+		}
+		if name == "" || schema == "" {
+			return fmt.Errorf("invalid --add %q (want Type=Schema)", a)
+		}
+		add[name] = schema
+	}
+
+	spec, err := modelgen.Load(regenSpec)
+	if err != nil {
+		return err
+	}
+	models, err := modelgen.LoadModels(regenModelsDir)
+	if err != nil {
+		return err
+	}
+	structs, changes, err := modelgen.Regenerate(spec, models, add)
+	if err != nil {
+		return err
+	}
+
+	generated := filepath.Join(regenModelsDir, modelgen.GeneratedFile)
+	if len(changes) == 0 {
+		fmt.Fprintf(os.Stderr, "%s matches the spec.\n", generated)
+		return nil
+	}
+	output.Print(getFormat(), changes, modelChangeColumns)
+	if !regenWrite {
+		fmt.Fprintf(os.Stderr, "%d difference(s); re-run with --write to update %s.\n", len(changes), generated)
+		return nil
+	}
+	if err := models.Write(structs); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s. Run go build ./... to catch uses of removed fields.\n", generated)
+	return nil
+}
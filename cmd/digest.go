@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/digest"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Write a Markdown or HTML digest of the period's performance",
+	Long: `Combine the summary, top movers, budget utilization, serving issues and new
+search-term opportunities for a period into one document, ready to paste into
+chat or send by email.
+
+The digest is written as HTML when --out ends in .html, as Markdown otherwise,
+and to stdout as Markdown without --out. Budget utilization is each enabled
+campaign's spend over its daily budget for every day of the period.
+Opportunities are converting search terms in enabled campaigns with spend that
+are not yet targeted as EXACT keywords (see: asa-cli analyze keyword-gap).
+
+Example:
+  asa-cli digest --range last-week --out digest.md
+  asa-cli digest --out digest.html --email weekly-digest`,
+	RunE: runDigest,
+}
+
+var (
+	digRange       string
+	digOut         string
+	digTop         int
+	digMinInstalls int64
+	digEmail       string
+)
+
+func init() {
+	digestCmd.Flags().StringVar(&digRange, "range", "last-week", "Date range: "+daterange.Help)
+	digestCmd.Flags().StringVar(&digOut, "out", "", "Output path, .md or .html (default: Markdown to stdout)")
+	digestCmd.Flags().IntVar(&digTop, "top", 5, "Number of campaigns, movers and opportunities to include")
+	digestCmd.Flags().Int64Var(&digMinInstalls, "min-installs", 2, "Only list opportunities with at least this many installs")
+	digestCmd.Flags().StringVar(&digEmail, "email", "", "Email the digest to this notifications.email job's recipients (requires --out)")
+
+	rootCmd.AddCommand(digestCmd)
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	if digEmail != "" && digOut == "" {
+		return fmt.Errorf("--email requires --out")
+	}
+	rng, err := daterange.Parse(digRange, time.Now())
+	if err != nil {
+		return err
+	}
+	prevRng := rng.Previous()
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	acl, err := resolveOrgACL(client)
+	if err != nil {
+		return fmt.Errorf("fetching org: %w", err)
+	}
+	campaigns, err := services.NewCampaignService(client).FindAll(models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return fmt.Errorf("listing campaigns: %w", err)
+	}
+
+	reports := services.NewReportingService(client)
+	cur, err := reports.GetCampaignReport(newRangeReportRequest(rng, maxPageSize))
+	if err != nil {
+		return fmt.Errorf("getting campaign report: %w", err)
+	}
+	prev, err := reports.GetCampaignReport(newRangeReportRequest(prevRng, maxPageSize))
+	if err != nil {
+		return fmt.Errorf("getting previous period report: %w", err)
+	}
+	curEntities := aggregate.ByEntity(cur, "campaignId", "campaignName")
+	prevEntities := aggregate.ByEntity(prev, "campaignId", "campaignName")
+	spend := map[int64]float64{}
+	for _, e := range curEntities {
+		spend[e.ID] = e.Spend
+	}
+
+	d := &digest.Digest{
+		OrgName:       acl.OrgName,
+		Currency:      acl.Currency,
+		Period:        rng.String(),
+		Previous:      prevRng.String(),
+		Totals:        aggregate.Total(curEntities),
+		PrevTotals:    aggregate.Total(prevEntities),
+		TopCampaigns:  aggregate.TopBySpend(curEntities, digTop),
+		Movers:        aggregate.Movers(curEntities, prevEntities, digTop),
+		Budgets:       []digest.Budget{},
+		ServingIssues: []digest.ServingIssue{},
+		Opportunities: []digest.Opportunity{},
+	}
+
+	for _, c := range campaigns {
+		if c.Status != "ENABLED" {
+			continue
+		}
+		if c.ServingStatus != "" && c.ServingStatus != "RUNNING" {
+			d.ServingIssues = append(d.ServingIssues, digest.ServingIssue{
+				ID:            c.ID,
+				Name:          c.Name,
+				ServingStatus: string(c.ServingStatus),
+				Reasons:       c.ServingStateReasons,
+			})
+		}
+		if c.DailyBudgetAmount != nil {
+			if daily := aggregate.Amount(*c.DailyBudgetAmount); daily > 0 {
+				d.Budgets = append(d.Budgets, digest.Budget{
+					ID:          c.ID,
+					Name:        c.Name,
+					DailyBudget: daily,
+					Spend:       roundCents(spend[c.ID]),
+					Utilization: spend[c.ID] / (daily * float64(rng.Days())),
+				})
+			}
+		}
+		if spend[c.ID] > 0 {
+			opps, err := digestOpportunities(client, c, rng)
+			if err != nil {
+				warnf("skipping opportunities for campaign %d: %v", c.ID, err)
+				continue
+			}
+			d.Opportunities = append(d.Opportunities, opps...)
+		}
+	}
+
+	sort.SliceStable(d.Budgets, func(i, j int) bool { return d.Budgets[i].Utilization > d.Budgets[j].Utilization })
+	sort.SliceStable(d.Opportunities, func(i, j int) bool {
+		if d.Opportunities[i].Installs != d.Opportunities[j].Installs {
+			return d.Opportunities[i].Installs > d.Opportunities[j].Installs
+		}
+		return d.Opportunities[i].CPI < d.Opportunities[j].CPI
+	})
+	if digTop > 0 && len(d.Opportunities) > digTop {
+		d.Opportunities = d.Opportunities[:digTop]
+	}
+
+	if getFormat() == output.FormatJSON && digOut == "" {
+		output.Print(output.FormatJSON, d, nil)
+		return nil
+	}
+	if digOut == "" {
+		return digest.WriteMarkdown(os.Stdout, d)
+	}
+
+	f, err := os.Create(explainPath(digOut))
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", digOut, err)
+	}
+	write := digest.WriteMarkdown
+	if digest.IsHTML(digOut) {
+		write = digest.WriteHTML
+	}
+	if err := write(f, d); err != nil {
+		f.Close()
+		return fmt.Errorf("writing digest: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing digest: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Digest for %s written to %s\n", rng, digOut)
+	if digEmail != "" {
+		return emailFiles(digEmail, []string{digOut})
+	}
+	return nil
+}
+
+// digestOpportunities lists a campaign's converting search terms that have no
+// exact keyword.
+func digestOpportunities(client *api.Client, c models.Campaign, rng daterange.Range) ([]digest.Opportunity, error) {
+	resp, err := services.NewReportingService(client).GetSearchTermReport(c.ID, newRangeReportRequest(rng, maxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("getting search terms report: %w", err)
+	}
+	keywords, err := services.NewKeywordService(client).FindAllInCampaign(c.ID, models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return nil, fmt.Errorf("listing keywords: %w", err)
+	}
+	var opps []digest.Opportunity
+	for _, g := range findKeywordGaps(aggregate.BySearchTerm(resp), exactKeywordSet(keywords), rng.Days()) {
+		if g.Installs < digMinInstalls {
+			continue
+		}
+		opps = append(opps, digest.Opportunity{
+			CampaignID:   c.ID,
+			CampaignName: c.Name,
+			Term:         g.Term,
+			Installs:     g.Installs,
+			CPI:          roundCents(g.CPI()),
+			SuggestedBid: g.SuggestedBid,
+		})
+	}
+	return opps, nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/credentials"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose configuration, keys, clock, and API access",
+	Long: `Check the profile's setup step by step: the config, each private key, the
+local clock against appleid.apple.com, the token exchange, and access to the
+API. A clock that has drifted more than a minute (common on resumed VMs) makes
+Apple reject otherwise valid credentials with invalid_client or invalid_grant.
+
+Exits non-zero when a check fails.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// DoctorCheck is the result of one doctor check.
+type DoctorCheck struct {
+	Check  string `json:"check"`
+	Status string `json:"status"` // ok, warn, fail, or skip
+	Detail string `json:"detail,omitempty"`
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checks := doctorChecks()
+
+	output.Print(getFormat(), checks, []output.Column{
+		{Header: "CHECK", Field: "Check"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "DETAIL", Field: "Detail"},
+	})
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == "fail" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// doctorChecks runs the checks in order; checks that depend on a failed one
+// are skipped.
+func doctorChecks() []DoctorCheck {
+	var checks []DoctorCheck
+	add := func(check, status, detail string) {
+		checks = append(checks, DoctorCheck{Check: check, Status: status, Detail: detail})
+	}
+
+	cfg, err := config.Load()
+	if err == nil {
+		err = credentials.Resolve(cfg)
+	}
+	if err == nil {
+		err = auth.ValidateConfig(cfg)
+	}
+	if err != nil {
+		add("config", "fail", err.Error())
+	} else {
+		profile := profileName
+		if profile == "" {
+			profile = "default"
+		}
+		add("config", "ok", "profile "+profile)
+	}
+
+	if cfg != nil {
+		for i, pair := range cfg.KeyPairs() {
+			name := "key " + pair.KeyID
+			if i == 0 && cfg.PrivateKey != "" {
+				add(name, "ok", "from "+cfg.CredentialSource)
+				continue
+			}
+			fp, err := keyFingerprint(pair.PrivateKeyPath)
+			switch {
+			case err != nil:
+				add(name, "fail", err.Error())
+			case auth.KeyPermissionWarning(pair.PrivateKeyPath) != "":
+				add(name, "warn", auth.KeyPermissionWarning(pair.PrivateKeyPath))
+			default:
+				add(name, "ok", fp)
+			}
+		}
+	}
+
+	clockOK := true
+	if skew, err := auth.ClockSkew(); err != nil {
+		add("clock", "warn", err.Error())
+	} else if auth.Skewed(skew) {
+		clockOK = false
+		add("clock", "fail", auth.DescribeSkew(skew)+"; sync it (e.g. enable NTP)")
+	} else {
+		add("clock", "ok", fmt.Sprintf("within %s of appleid.apple.com", auth.MaxClockSkew))
+	}
+
+	if len(checks) == 0 || checks[0].Status != "ok" {
+		add("token", "skip", "config check failed")
+		add("api", "skip", "config check failed")
+		return checks
+	}
+	tp := newTokenProvider(cfg)
+	if _, err := tp.GetToken(); err != nil {
+		// The first line; hints follow on the next ones
+		detail := strings.SplitN(err.Error(), "\n", 2)[0]
+		if !clockOK {
+			detail = "rejected; see the clock check"
+		}
+		add("token", "fail", detail)
+		add("api", "skip", "no access token")
+		return checks
+	}
+	add("token", "ok", "key "+tp.ActiveKeyID())
+
+	client, err := newAPIClientNoOrg()
+	if err != nil {
+		add("api", "fail", err.Error())
+		return checks
+	}
+	acls, err := services.NewACLService(client).GetACLs()
+	if err != nil {
+		add("api", "fail", err.Error())
+		return checks
+	}
+	add("api", "ok", fmt.Sprintf("%d organization(s) accessible", len(acls)))
+	return checks
+}
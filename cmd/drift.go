@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/drift"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Inspect API schema drift found with --strict-decode",
+	Long: `Run any command with --strict-decode to compare Apple's responses with the
+CLI's models. Fields in a response that the models don't have ("unknown") and
+fields the models expect that a response lacks ("missing"), and enum values
+the models don't list ("value") are printed to stderr and appended to
+~/.asa-cli/drift.jsonl.
+
+Unknown fields are dropped from table, TSV, and export output until the models
+learn them; missing fields come out as zero values. Unknown enum values are
+kept and shown as Apple sent them.`,
+}
+
+var driftReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize the logged drift findings",
+	RunE:  runDriftReport,
+}
+
+var driftClear bool
+
+// driftRecorder is shared by every API client; --strict-decode enables it.
+var driftRecorder = &drift.Recorder{}
+
+func init() {
+	driftReportCmd.Flags().BoolVar(&driftClear, "clear", false, "Delete the drift log after printing it")
+
+	driftCmd.AddCommand(driftReportCmd)
+	rootCmd.AddCommand(driftCmd)
+}
+
+// reportDrift logs and prints the findings of the finished command.
+func reportDrift(cmd *cobra.Command) {
+	findings := driftRecorder.Take()
+	if len(findings) == 0 {
+		return
+	}
+	name := ""
+	if cmd != nil {
+		name = strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+	}
+	for i := range findings {
+		findings[i].Command = name
+	}
+	if err := drift.Append(findings); err != nil {
+		warnf("%v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Schema drift: %d field(s) differ from the models (logged to %s):\n", len(findings), drift.Path())
+	for _, s := range drift.Summarize(findings) {
+		fmt.Fprintf(os.Stderr, "  %-7s %s %s (%s)\n", s.Kind, s.Endpoint, s.Field, s.Model)
+	}
+}
+
+type driftRow struct {
+	drift.Summary
+	Last string `json:"-"`
+}
+
+func runDriftReport(cmd *cobra.Command, args []string) error {
+	findings, err := drift.Load()
+	if err != nil {
+		return err
+	}
+	rows := []driftRow{}
+	for _, s := range drift.Summarize(findings) {
+		rows = append(rows, driftRow{Summary: s, Last: s.LastSeen.Local().Format("2006-01-02 15:04")})
+	}
+	if len(rows) == 0 && getFormat() == output.FormatTable {
+		fmt.Println("No drift logged. Run commands with --strict-decode to check responses.")
+	} else {
+		output.Print(getFormat(), rows, []output.Column{
+			{Header: "KIND", Field: "Kind"},
+			{Header: "ENDPOINT", Field: "Endpoint"},
+			{Header: "FIELD", Field: "Field"},
+			{Header: "MODEL", Field: "Model"},
+			{Header: "SEEN", Field: "Count"},
+			{Header: "LAST SEEN", Field: "Last"},
+		})
+	}
+	if driftClear {
+		if err := os.Remove(drift.Path()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("clearing drift log: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+	"go.yaml.in/yaml/v3"
+)
+
+const editLong = `Open the %s's editable fields as YAML in $VISUAL or $EDITOR (default: %s).
+On save, the changed fields and the update payload are shown and applied once
+confirmed (or with --yes). Saving the file unchanged, or emptying it, cancels.
+A file that doesn't parse or fails a safety check can be edited again.`
+
+var campaignsEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit a campaign in $EDITOR",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCampaignsEdit,
+}
+
+var adgroupsEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit an ad group in $EDITOR",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAdGroupsEdit,
+}
+
+var kwEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit a keyword in $EDITOR",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runKWEdit,
+}
+
+var editYes bool
+
+func init() {
+	campaignsEditCmd.Long = fmt.Sprintf(editLong, "campaign", defaultEditor())
+	adgroupsEditCmd.Long = fmt.Sprintf(editLong, "ad group", defaultEditor())
+	kwEditCmd.Long = fmt.Sprintf(editLong, "keyword", defaultEditor())
+
+	adgroupsEditCmd.Flags().Int64Var(&agCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	adgroupsEditCmd.MarkFlagRequired("campaign-id")
+	kwEditCmd.Flags().Int64Var(&kwCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	kwEditCmd.Flags().Int64Var(&kwAdGroupID, "adgroup-id", 0, "Ad group ID (required)")
+	kwEditCmd.MarkFlagRequired("campaign-id")
+	kwEditCmd.MarkFlagRequired("adgroup-id")
+	for _, cmd := range []*cobra.Command{campaignsEditCmd, adgroupsEditCmd, kwEditCmd} {
+		cmd.Flags().BoolVar(&editYes, "yes", false, "Apply the edit without asking")
+	}
+
+	campaignsCmd.AddCommand(campaignsEditCmd)
+	adgroupsCmd.AddCommand(adgroupsEditCmd)
+	keywordsCmd.AddCommand(kwEditCmd)
+}
+
+// campaignDoc is the editable part of a campaign. Countries are left out:
+// changing them can clear ad group geo targeting (see campaigns add-countries).
+type campaignDoc struct {
+	Name        string        `yaml:"name"`
+	Status      models.Status `yaml:"status"`
+	Budget      string        `yaml:"budget"`
+	DailyBudget string        `yaml:"daily_budget"`
+}
+
+type adGroupDoc struct {
+	Name         string        `yaml:"name"`
+	Status       models.Status `yaml:"status"`
+	DefaultBid   string        `yaml:"default_bid"`
+	CpaGoal      string        `yaml:"cpa_goal"`
+	AutoKeywords bool          `yaml:"auto_keywords"`
+	StartTime    string        `yaml:"start_time"`
+	EndTime      string        `yaml:"end_time"`
+}
+
+type keywordDoc struct {
+	Status models.KeywordStatus `yaml:"status"`
+	Bid    string               `yaml:"bid"`
+}
+
+// EditChange is a field changed in the editor.
+type EditChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+func runCampaignsEdit(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid campaign ID: %s", args[0])
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewCampaignService(client)
+	c, err := svc.Get(id)
+	if err != nil {
+		return fmt.Errorf("getting campaign: %w", err)
+	}
+
+	orig := campaignDoc{Name: c.Name, Status: c.Status, Budget: moneyAmount(c.BudgetAmount), DailyBudget: moneyAmount(c.DailyBudgetAmount)}
+	header := fmt.Sprintf("Campaign %d (%s, serving %s, countries %s)", c.ID, c.AdChannelType, c.ServingStatus, strings.Join(c.CountriesOrRegions, ","))
+	var edited campaignDoc
+	changes, err := editEntity(header, orig, &edited, func() error {
+		if edited.Status, err = models.ParseStatus(string(edited.Status)); err != nil {
+			return err
+		}
+		if edited.Name != orig.Name {
+			if err := checkCampaignName(edited.Name); err != nil {
+				return err
+			}
+		}
+		if edited.DailyBudget != orig.DailyBudget {
+			return checkBudgetLimit(edited.DailyBudget)
+		}
+		return nil
+	})
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	currency, err := entityCurrency(client, c.BudgetAmount, c.DailyBudgetAmount)
+	if err != nil {
+		return err
+	}
+	update := &models.CampaignUpdate{}
+	if edited.Name != orig.Name {
+		update.Name = edited.Name
+	}
+	if edited.Status != orig.Status {
+		update.Status = edited.Status
+	}
+	if edited.Budget != orig.Budget {
+		update.BudgetAmount = &models.Money{Amount: edited.Budget, Currency: currency}
+	}
+	if edited.DailyBudget != orig.DailyBudget {
+		update.DailyBudgetAmount = &models.Money{Amount: edited.DailyBudget, Currency: currency}
+	}
+	if !confirmEdit(changes, update) {
+		return fmt.Errorf("aborted; no changes made")
+	}
+
+	updated, err := svc.Update(id, update)
+	if err != nil {
+		return fmt.Errorf("updating campaign: %w", err)
+	}
+	output.Print(getFormat(), updated, campaignColumns)
+	return nil
+}
+
+func runAdGroupsEdit(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ad group ID: %s", args[0])
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewAdGroupService(client)
+	ag, err := svc.Get(agCampaignID, id)
+	if err != nil {
+		return fmt.Errorf("getting ad group: %w", err)
+	}
+
+	orig := adGroupDoc{
+		Name:         ag.Name,
+		Status:       ag.Status,
+		DefaultBid:   moneyAmount(ag.DefaultBidAmount),
+		CpaGoal:      moneyAmount(ag.CpaGoal),
+		AutoKeywords: ag.AutomatedKeywordsOptIn,
+		StartTime:    ag.StartTime,
+		EndTime:      ag.EndTime,
+	}
+	header := fmt.Sprintf("Ad group %d in campaign %d (%s, serving %s)", ag.ID, ag.CampaignID, ag.PricingModel, ag.ServingStatus)
+	var edited adGroupDoc
+	changes, err := editEntity(header, orig, &edited, func() error {
+		if edited.Status, err = models.ParseStatus(string(edited.Status)); err != nil {
+			return err
+		}
+		if edited.Name != orig.Name {
+			if err := checkAdGroupName(edited.Name); err != nil {
+				return err
+			}
+		}
+		if edited.DefaultBid != orig.DefaultBid {
+			return checkBidLimit(edited.DefaultBid)
+		}
+		return nil
+	})
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	currency, err := entityCurrency(client, ag.DefaultBidAmount, ag.CpaGoal)
+	if err != nil {
+		return err
+	}
+	update := &models.AdGroupUpdate{}
+	if edited.Name != orig.Name {
+		update.Name = edited.Name
+	}
+	if edited.Status != orig.Status {
+		update.Status = edited.Status
+	}
+	if edited.DefaultBid != orig.DefaultBid {
+		update.DefaultBidAmount = &models.Money{Amount: edited.DefaultBid, Currency: currency}
+	}
+	if edited.CpaGoal != orig.CpaGoal {
+		update.CpaGoal = &models.Money{Amount: edited.CpaGoal, Currency: currency}
+	}
+	if edited.AutoKeywords != orig.AutoKeywords {
+		update.AutomatedKeywordsOptIn = &edited.AutoKeywords
+	}
+	if edited.StartTime != orig.StartTime {
+		update.StartTime = edited.StartTime
+	}
+	if edited.EndTime != orig.EndTime {
+		update.EndTime = edited.EndTime
+	}
+	if !confirmEdit(changes, update) {
+		return fmt.Errorf("aborted; no changes made")
+	}
+
+	updated, err := svc.Update(agCampaignID, id, update)
+	if err != nil {
+		return fmt.Errorf("updating ad group: %w", err)
+	}
+	output.Print(getFormat(), updated, adgroupColumns)
+	return nil
+}
+
+func runKWEdit(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid keyword ID: %s", args[0])
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewKeywordService(client)
+	kw, err := svc.Get(kwCampaignID, kwAdGroupID, id)
+	if err != nil {
+		return fmt.Errorf("getting keyword: %w", err)
+	}
+
+	orig := keywordDoc{Status: kw.Status, Bid: moneyAmount(kw.BidAmount)}
+	header := fmt.Sprintf("Keyword %d %q (%s) in ad group %d", kw.ID, kw.Text, kw.MatchType, kw.AdGroupID)
+	var edited keywordDoc
+	changes, err := editEntity(header, orig, &edited, func() error {
+		if edited.Status, err = models.ParseKeywordStatus(string(edited.Status)); err != nil {
+			return err
+		}
+		if edited.Bid != orig.Bid {
+			return checkBidLimit(edited.Bid)
+		}
+		return nil
+	})
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	update := models.KeywordUpdate{ID: id}
+	if edited.Status != orig.Status {
+		update.Status = edited.Status
+	}
+	if edited.Bid != orig.Bid {
+		currency, err := entityCurrency(client, kw.BidAmount)
+		if err != nil {
+			return err
+		}
+		update.BidAmount = &models.Money{Amount: edited.Bid, Currency: currency}
+	}
+	if !confirmEdit(changes, update) {
+		return fmt.Errorf("aborted; no changes made")
+	}
+
+	updated, err := svc.Update(kwCampaignID, kwAdGroupID, []models.KeywordUpdate{update})
+	if err != nil {
+		return fmt.Errorf("updating keyword: %w", err)
+	}
+	output.Print(getFormat(), updated, keywordColumns)
+	return nil
+}
+
+// entityCurrency returns the currency of the entity's first set amount, or
+// the org's when it has none.
+func entityCurrency(client *api.Client, amounts ...*models.Money) (string, error) {
+	for _, m := range amounts {
+		if m != nil && m.Currency != "" {
+			return m.Currency, nil
+		}
+	}
+	return resolveOrgCurrency(client)
+}
+
+// editEntity opens orig as YAML in the editor, decodes the saved file into
+// edited, and returns the changed fields. A file that fails to parse or
+// validate can be edited again. No changes means the edit was cancelled.
+func editEntity(header string, orig, edited interface{}, validate func() error) ([]EditChange, error) {
+	body, err := yaml.Marshal(orig)
+	if err != nil {
+		return nil, fmt.Errorf("encoding YAML: %w", err)
+	}
+	text := commentLines(header+"\nLines starting with # are ignored. Save unchanged or empty to cancel.") + string(body)
+
+	for {
+		saved, err := runEditor(text)
+		if err != nil {
+			return nil, err
+		}
+		content := stripComments(saved)
+		if strings.TrimSpace(content) == "" || content == string(body) {
+			fmt.Fprintln(os.Stderr, "Edit cancelled; no changes made.")
+			return nil, nil
+		}
+
+		reflect.ValueOf(edited).Elem().Set(reflect.ValueOf(orig))
+		err = decodeStrict(content, edited)
+		if err == nil {
+			err = validate()
+		}
+		if err == nil {
+			changes := diffDocs(orig, reflect.ValueOf(edited).Elem().Interface())
+			if len(changes) == 0 {
+				fmt.Fprintln(os.Stderr, "Edit cancelled; no changes made.")
+			}
+			return changes, nil
+		}
+
+		if fd := os.Stdin.Fd(); !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd) {
+			return nil, fmt.Errorf("%w; no changes made", err)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if !confirm("Edit again?") {
+			return nil, fmt.Errorf("aborted; no changes made")
+		}
+		text = commentLines("Error: "+err.Error()+"\n"+header) + content
+	}
+}
+
+// decodeStrict decodes YAML, rejecting unknown fields.
+func decodeStrict(content string, out interface{}) error {
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("parsing YAML: %w", err)
+	}
+	return nil
+}
+
+// diffDocs compares two docs of the same struct type field by field.
+func diffDocs(orig, edited interface{}) []EditChange {
+	a, b := reflect.ValueOf(orig), reflect.ValueOf(edited)
+	var changes []EditChange
+	for i := 0; i < a.NumField(); i++ {
+		old, cur := fmt.Sprint(a.Field(i).Interface()), fmt.Sprint(b.Field(i).Interface())
+		if old != cur {
+			name := strings.Split(a.Type().Field(i).Tag.Get("yaml"), ",")[0]
+			changes = append(changes, EditChange{Field: name, Old: old, New: cur})
+		}
+	}
+	return changes
+}
+
+// confirmEdit shows the changes and the update payload, and asks before
+// applying them unless --yes is set.
+func confirmEdit(changes []EditChange, payload interface{}) bool {
+	fmt.Fprintln(os.Stderr, "Changes:")
+	for _, c := range changes {
+		fmt.Fprintf(os.Stderr, "  %s: %q → %q\n", c.Field, c.Old, c.New)
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err == nil {
+		fmt.Fprintf(os.Stderr, "Update payload:\n%s\n", data)
+	}
+	return editYes || confirm("Apply?")
+}
+
+func commentLines(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString("# " + line + "\n")
+	}
+	return b.String()
+}
+
+func stripComments(text string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+func defaultEditor() string {
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// runEditor writes text to a temporary YAML file, opens it in the user's
+// editor, and returns what was saved.
+func runEditor(text string) (string, error) {
+	f, err := os.CreateTemp("", "asa-cli-edit-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", fmt.Errorf("writing temp file: %w", err)
+	}
+	f.Close()
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor()
+	}
+	// Editors are often set with arguments, e.g. "code --wait"
+	args := append(strings.Fields(editor), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running editor %s: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading edited file: %w", err)
+	}
+	return string(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))), nil
+}
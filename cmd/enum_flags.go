@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// enumValue is a flag that accepts only an enum's values, in any case, so a
+// typo fails when the flag is parsed rather than at the API.
+type enumValue[T ~string] struct {
+	p     *T
+	parse func(string) (T, error)
+}
+
+func (v *enumValue[T]) Set(s string) error {
+	parsed, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	*v.p = parsed
+	return nil
+}
+
+func (v *enumValue[T]) String() string { return string(*v.p) }
+
+func (v *enumValue[T]) Type() string { return "string" }
+
+// enumVar defines an enum flag with a default value.
+func enumVar[T ~string](fs *pflag.FlagSet, p *T, name string, value T, parse func(string) (T, error), usage string) {
+	*p = value
+	fs.Var(&enumValue[T]{p: p, parse: parse}, name, usage)
+}
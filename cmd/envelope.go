@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+// envelopeFlag wraps JSON output in {data, pagination, request, warnings}.
+var envelopeFlag bool
+
+// callLog records every API request, for the request summary of envelopes.
+var callLog api.CallLog
+
+var (
+	warningsMu sync.Mutex
+	// runWarnings holds the warnings printed since the outermost command
+	// started.
+	runWarnings []string
+	// runMarks holds, per command being run, where its calls and warnings
+	// start. Commands run by batch, shell, and the like nest.
+	runMarks []runMark
+)
+
+type runMark struct{ calls, warnings int }
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&envelopeFlag, "envelope", true, "Wrap JSON output in {data, pagination, request, warnings}")
+	cobra.OnInitialize(beginRun)
+	cobra.OnFinalize(endRun)
+	output.EnvelopeMeta = envelopeMeta
+}
+
+func beginRun() {
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+	runMarks = append(runMarks, runMark{calls: callLog.Len(), warnings: len(runWarnings)})
+}
+
+func endRun() {
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+	if len(runMarks) > 0 {
+		runMarks = runMarks[:len(runMarks)-1]
+	}
+	if len(runMarks) == 0 {
+		callLog.Reset()
+		runWarnings = nil
+	}
+}
+
+// warnf prints a warning to stderr and keeps it for the JSON envelope.
+func warnf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+	runWarnings = append(runWarnings, msg)
+}
+
+// envelopeMeta summarizes the calls and warnings of the command being run.
+func envelopeMeta() (output.RequestMeta, []string) {
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+	var mark runMark
+	if len(runMarks) > 0 {
+		mark = runMarks[len(runMarks)-1]
+	}
+	s := callLog.Summary(mark.calls)
+	meta := output.RequestMeta{
+		Endpoint:   s.Endpoint,
+		Calls:      s.Calls,
+		DurationMS: s.Duration.Milliseconds(),
+		Retries:    s.Retries,
+	}
+	return meta, append([]string{}, runWarnings[mark.warnings:]...)
+}
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/progress"
+)
+
+// --explain rehearses a command instead of running it: API requests are
+// recorded and answered with empty data, nothing reaches the network, output
+// is discarded, and local state and files go to a throwaway directory.
+
+var explainFlag bool
+
+// explaining is set while a command is being rehearsed.
+var explaining *explainRun
+
+// maxExplainCalls stops a rehearsal that keeps calling, e.g. a loop that
+// waits for data the empty responses never provide.
+const maxExplainCalls = 200
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&explainFlag, "explain", false, "Print the API calls a command would make, and the profile and org in effect, without running it")
+
+	for cmd, r := range map[*cobra.Command]explainRefusal{
+		serveCmd:          {nil, "it starts a server"},
+		serveAPICmd:       {nil, "it starts a server"},
+		shellCmd:          {nil, "it is interactive; use --explain on the commands inside it"},
+		batchCmd:          {nil, "it runs other commands; use --explain on them one at a time"},
+		changesetApplyCmd: {nil, "it runs the staged commands; use --explain on them one at a time"},
+		approvalsApplyCmd: {nil, "it runs the proposed command; use --explain on it"},
+		configureCmd:      {nil, "it only changes local configuration"},
+		campaignsEditCmd:  {nil, "it opens an editor; use --explain on campaigns update"},
+		adgroupsEditCmd:   {nil, "it opens an editor; use --explain on adgroups update"},
+		kwEditCmd:         {nil, "it opens an editor; use --explain on keywords update"},
+		guardCmd:          {func() bool { return !guardOnce }, "it runs until stopped; add --once"},
+		adsRotateCmd:      {func() bool { return adsWatch }, "it runs until stopped; drop --watch"},
+	} {
+		explainRefusals[cmd] = r
+	}
+}
+
+// ExplainCall is one API request a command would make.
+type ExplainCall struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	Params   string `json:"params,omitempty"`
+	Write    bool   `json:"write"`
+
+	Kind       string `json:"-"`
+	ParamsText string `json:"-"`
+}
+
+// Explanation is what --explain prints.
+type Explanation struct {
+	Command    string        `json:"command"`
+	Profile    string        `json:"profile"`
+	ConfigFile string        `json:"configFile"`
+	OrgID      string        `json:"orgId"`
+	OrgSource  string        `json:"orgSource"`
+	Modes      []string      `json:"modes,omitempty"`
+	Calls      []ExplainCall `json:"calls"`
+	Reads      int           `json:"reads"`
+	Writes     int           `json:"writes"`
+	Skipped    []string      `json:"skipped,omitempty"`
+	Stopped    string        `json:"stopped,omitempty"`
+}
+
+type explainRun struct {
+	Explanation
+	sandbox string
+}
+
+// explainRefusal is why a command can't be rehearsed. when, if not nil,
+// reports whether this run is the refused kind.
+type explainRefusal struct {
+	when   func() bool
+	reason string
+}
+
+var explainRefusals = map[*cobra.Command]explainRefusal{}
+
+var explainWrap sync.Once
+
+// wrapExplain has every command under cmd run as a rehearsal when --explain
+// is given.
+func wrapExplain(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		wrapExplain(c)
+	}
+	run := cmd.RunE
+	if run == nil {
+		return
+	}
+	cmd.RunE = func(c *cobra.Command, args []string) error {
+		if !explainFlag {
+			return run(c, args)
+		}
+		finish, err := startExplain(c, args)
+		if err != nil {
+			return err
+		}
+		return finish(run(c, args))
+	}
+}
+
+// startExplain sets up the rehearsal of cmd. The returned function prints the
+// explanation and undoes the setup.
+func startExplain(cmd *cobra.Command, args []string) (func(runErr error) error, error) {
+	for c := cmd; c != nil; c = c.Parent() {
+		if r, ok := explainRefusals[c]; ok && (r.when == nil || r.when()) {
+			return nil, fmt.Errorf("%s can't be explained: %s", cmd.CommandPath(), r.reason)
+		}
+	}
+
+	run := &explainRun{}
+	run.Command = explainCommandLine(cmd, args)
+	run.Profile = profileName
+	if run.Profile == "" {
+		run.Profile = "default"
+	}
+	run.ConfigFile = filepath.Join(config.ConfigDir(), "config.yaml")
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	switch {
+	case globalOrgID != "":
+		run.OrgID, run.OrgSource = globalOrgID, "--org-id"
+	case cfg.OrgID != "":
+		run.OrgID, run.OrgSource = cfg.OrgID, "config"
+	default:
+		run.OrgID, run.OrgSource = "", "detected from /acls when the command runs"
+		if acls, ok := cachedACLs(cfg); ok && len(acls) == 1 {
+			run.OrgID, run.OrgSource = strconv.FormatInt(acls[0].OrgID, 10), "detected from the cached /acls response"
+		}
+	}
+	if readOnly || cfg.ReadOnly {
+		run.Modes = append(run.Modes, "read-only")
+	}
+	if approvalRequired() {
+		run.Modes = append(run.Modes, "approval required (mutating commands write a proposal)")
+	}
+	if forceFlag {
+		run.Modes = append(run.Modes, "budget and bid checks skipped (--force)")
+	}
+
+	sandbox, err := os.MkdirTemp("", "asa-cli-explain-")
+	if err != nil {
+		return nil, fmt.Errorf("creating explain sandbox: %w", err)
+	}
+	run.sandbox = sandbox
+	if err := seedExplainSandbox(config.ConfigDir(), sandbox); err != nil {
+		os.RemoveAll(sandbox)
+		return nil, err
+	}
+	wd, _ := os.Getwd()
+	home, hadHome := os.LookupEnv("HOME")
+	prevDir := config.SetDir(sandbox)
+	os.Chdir(sandbox)
+	os.Setenv("HOME", sandbox)
+	devNull, _ := os.Open(os.DevNull)
+	devNullW, _ := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	stdin, stdout, stderr := os.Stdin, os.Stdout, os.Stderr
+	os.Stdin, os.Stdout, os.Stderr = devNull, devNullW, devNullW
+	prevProgress := progress.Enabled
+	progress.Enabled = false
+	explaining = run
+
+	return func(runErr error) error {
+		explaining = nil
+		progress.Enabled = prevProgress
+		os.Stdin, os.Stdout, os.Stderr = stdin, stdout, stderr
+		devNull.Close()
+		devNullW.Close()
+		if hadHome {
+			os.Setenv("HOME", home)
+		}
+		os.Chdir(wd)
+		config.SetDir(prevDir)
+		os.RemoveAll(sandbox)
+
+		if runErr != nil {
+			run.Stopped = runErr.Error()
+		}
+		printExplanation(&run.Explanation)
+		return nil
+	}, nil
+}
+
+// explainCommandLine rebuilds the command line from the flags that were set.
+func explainCommandLine(cmd *cobra.Command, args []string) string {
+	parts := append([]string{cmd.CommandPath()}, args...)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name == "explain" {
+			return
+		}
+		if f.Value.Type() == "bool" {
+			parts = append(parts, "--"+f.Name)
+			return
+		}
+		value := f.Value.String()
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			value = strings.Join(sv.GetSlice(), ",")
+		}
+		if value == "" || strings.ContainsAny(value, " \t'\"") {
+			value = strconv.Quote(value)
+		}
+		parts = append(parts, "--"+f.Name+" "+value)
+	})
+	return strings.Join(parts, " ")
+}
+
+// seedExplainSandbox copies the config and local stores (YAML and JSON files)
+// so the rehearsal sees the same settings, tags, portfolios and so on.
+func seedExplainSandbox(from, to string) error {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.Type().IsRegular() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(from, e.Name()))
+		if err != nil {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(to, e.Name()), data, 0600); err != nil {
+			return fmt.Errorf("seeding explain sandbox: %w", err)
+		}
+	}
+	return nil
+}
+
+// explainClient returns a client that records requests instead of sending
+// them. withOrg adds the org lookup a real client makes when none is set.
+func explainClient(withOrg bool) *api.Client {
+	if withOrg && explaining.OrgID == "" && len(explaining.Calls) == 0 {
+		explaining.record("GET", "/acls", "(to pick the org)")
+	}
+	client := api.NewClient(&http.Client{Transport: callLog.Transport(injectFaults(explainTransport{}))})
+	client.Drift = driftRecorder
+	if cfg, err := config.Load(); err == nil {
+		if version, err := api.ParseVersion(cfg.APIVersion); err == nil {
+			client.Version = version
+		}
+	}
+	return client
+}
+
+// explainTransport answers every request with an empty result.
+type explainTransport struct{}
+
+func (explainTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	run := explaining
+	if run == nil {
+		return nil, fmt.Errorf("explain: request outside a rehearsal")
+	}
+	var body string
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		body = compactJSON(data)
+	}
+	endpoint := req.URL.Path
+	if rest, ok := strings.CutPrefix(endpoint, "/api/"); ok {
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			endpoint = rest[i:]
+		}
+	}
+	params := body
+	if req.URL.RawQuery != "" {
+		params = strings.TrimSpace(req.URL.RawQuery + " " + body)
+	}
+	if n := len(run.Calls); n > 0 {
+		last := run.Calls[n-1]
+		if last.Method == req.Method && last.Endpoint == endpoint && last.Params == params {
+			return nil, fmt.Errorf("the command polls %s %s until it is ready; a rehearsal can't wait for that", req.Method, endpoint)
+		}
+	}
+	if len(run.Calls) >= maxExplainCalls {
+		return nil, fmt.Errorf("more than %d API calls", maxExplainCalls)
+	}
+	run.record(req.Method, endpoint, params)
+
+	// Commands that look up the org get a stand-in for it.
+	reply := `{"data":null}`
+	if endpoint == "/acls" {
+		orgID, _ := strconv.ParseInt(run.OrgID, 10, 64)
+		reply = fmt.Sprintf(`{"data":[{"orgId":%d,"orgName":"(rehearsal)","currency":"USD"}]}`, orgID)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(reply)),
+		Request:    req,
+	}, nil
+}
+
+func (r *explainRun) record(method, endpoint, params string) {
+	call := ExplainCall{Method: method, Endpoint: endpoint, Params: params, Write: !auth.IsRead(method, endpoint)}
+	call.Kind, call.ParamsText = "read", params
+	if r := []rune(params); len(r) > 100 {
+		call.ParamsText = string(r[:99]) + "…"
+	}
+	if call.Write {
+		call.Kind = "write"
+		r.Writes++
+	} else {
+		r.Reads++
+	}
+	r.Calls = append(r.Calls, call)
+}
+
+// explainSkip notes a side effect outside the API that a rehearsal leaves
+// out, such as sending email. It reports whether the caller should skip it.
+func explainSkip(what string) bool {
+	if explaining == nil {
+		return false
+	}
+	explaining.Skipped = append(explaining.Skipped, what)
+	return true
+}
+
+// explainPath keeps files a rehearsal writes inside its sandbox.
+func explainPath(path string) string {
+	if explaining == nil {
+		return path
+	}
+	return filepath.Join(explaining.sandbox, filepath.Base(path))
+}
+
+func compactJSON(data []byte) string {
+	var buf bytes.Buffer
+	if json.Compact(&buf, data) != nil {
+		return string(data)
+	}
+	return buf.String()
+}
+
+func printExplanation(e *Explanation) {
+	if e.Calls == nil {
+		e.Calls = []ExplainCall{}
+	}
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, e, nil)
+		return
+	}
+	org := e.OrgID
+	if org == "" {
+		org = "(none)"
+	}
+	fmt.Printf("Command:  %s\n", e.Command)
+	fmt.Printf("Profile:  %s (%s)\n", e.Profile, e.ConfigFile)
+	fmt.Printf("Org:      %s, %s\n", org, e.OrgSource)
+	if len(e.Modes) > 0 {
+		fmt.Printf("Modes:    %s\n", strings.Join(e.Modes, "; "))
+	}
+	fmt.Printf("API calls: %d (%d read, %d write)\n\n", len(e.Calls), e.Reads, e.Writes)
+	if len(e.Calls) > 0 {
+		output.Print(output.FormatTable, e.Calls, []output.Column{
+			{Header: "METHOD", Field: "Method"},
+			{Header: "ENDPOINT", Field: "Endpoint"},
+			{Header: "KIND", Field: "Kind"},
+			{Header: "PARAMETERS", Field: "ParamsText"},
+		})
+		fmt.Println()
+	}
+	for _, s := range e.Skipped {
+		fmt.Printf("Not done: %s\n", s)
+	}
+	if e.Stopped != "" {
+		fmt.Printf("The rehearsal stopped early: %s\n", e.Stopped)
+	}
+	fmt.Println("Responses were empty in the rehearsal, so calls that depend on returned data")
+	fmt.Println("(one per campaign, further pages) are not listed. A real run makes at least")
+	fmt.Println("these calls.")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/refs"
+)
+
+// externalID is the --external-id flag shared by create commands.
+var externalID string
+
+const externalIDUsage = "Your own reference ID; re-running with the same ID updates the entity created before instead of creating a duplicate"
+
+// externalRefOrg scopes external IDs to the org: the --org-id flag or the
+// configured org (empty when the org is auto-detected).
+func externalRefOrg() string {
+	if globalOrgID != "" {
+		return globalOrgID
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.OrgID
+}
+
+// findExternalRef looks up --external-id in the mapping store. It returns a
+// nil store when no external ID was given, and a nil ref when the ID has not
+// been used yet.
+func findExternalRef(kind string) (*refs.Store, *refs.Ref, error) {
+	if externalID == "" {
+		return nil, nil, nil
+	}
+	store, err := refs.Load(profileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	ref, ok := store.Lookup(kind, externalRefOrg(), externalID)
+	if !ok {
+		return store, nil, nil
+	}
+	return store, &ref, nil
+}
+
+// recordExternalRef saves the mapping for a newly created entity.
+func recordExternalRef(store *refs.Store, ref refs.Ref) error {
+	if store == nil {
+		return nil
+	}
+	ref.OrgID = externalRefOrg()
+	ref.ExternalID = externalID
+	if err := store.Put(ref); err != nil {
+		return fmt.Errorf("recording external ID %s: %w", externalID, err)
+	}
+	return nil
+}
+
+// forgetExternalRef drops a mapping whose entity no longer exists.
+func forgetExternalRef(store *refs.Store, kind string) error {
+	return store.Delete(kind, externalRefOrg(), externalID)
+}
@@ -21,16 +21,14 @@ var geoSearchCmd = &cobra.Command{
 
 var (
 	geoQuery       string
-	geoLimit       int
-	geoOffset      int
+	geoPage        pager
 	geoEntity      string
 	geoCountryCode string
 )
 
 func init() {
 	geoSearchCmd.Flags().StringVar(&geoQuery, "query", "", "Search query (required)")
-	geoSearchCmd.Flags().IntVar(&geoLimit, "limit", 20, "Number of results")
-	geoSearchCmd.Flags().IntVar(&geoOffset, "offset", 0, "Results offset")
+	geoPage.register(geoSearchCmd)
 	geoSearchCmd.Flags().StringVar(&geoEntity, "entity", "", "Entity type filter")
 	geoSearchCmd.Flags().StringVar(&geoCountryCode, "country-code", "", "Country code filter")
 	geoSearchCmd.MarkFlagRequired("query")
@@ -45,13 +43,17 @@ func runGeoSearch(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	limit, offset, err := geoPage.resolve()
+	if err != nil {
+		return err
+	}
 	svc := services.NewAppService(client)
-	geos, _, err := svc.SearchGeo(geoQuery, geoLimit, geoOffset, geoEntity, geoCountryCode)
+	geos, page, err := svc.SearchGeo(geoQuery, limit, offset, geoEntity, geoCountryCode)
 	if err != nil {
 		return fmt.Errorf("searching geo locations: %w", err)
 	}
 
-	output.Print(getFormat(), geos, []output.Column{
+	geoPage.print(geos, page, []output.Column{
 		{Header: "ID", Field: "ID", Width: 10},
 		{Header: "ENTITY", Field: "Entity", Width: 15},
 		{Header: "NAME", Field: "DisplayName", Width: 30},
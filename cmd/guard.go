@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/guard"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var guardCmd = &cobra.Command{
+	Use:   "guard",
+	Short: "Pause a campaign when its spend today passes a cap",
+	Long: `Poll a campaign's spend for the current day every --interval and act once it
+reaches --daily-cap: pause the campaign (--action pause) or only report it
+(--action warn). With --resume-next-day, a campaign the guard paused is
+enabled again on the first check of the next day.
+
+What the guard did is kept in ~/.asa-cli/guard.json, so a restarted guard
+neither pauses a campaign twice nor forgets to re-enable it. Use --once to
+check a single time, e.g. from cron.
+
+Apple's reporting lags by up to a few hours, so the cap is a brake, not an
+exact limit: leave headroom below the amount you can't exceed. Days follow
+the org's time zone for spend and this machine's clock for the date, so run
+the guard with TZ set to the org's time zone.
+
+Example:
+  asa-cli guard --campaign-id 123 --daily-cap 300 --action pause --interval 10m --resume-next-day`,
+	RunE: runGuard,
+}
+
+var (
+	guardCampaignID    int64
+	guardDailyCap      float64
+	guardAction        string
+	guardInterval      time.Duration
+	guardResumeNextDay bool
+	guardOnce          bool
+)
+
+func init() {
+	guardCmd.Flags().Int64Var(&guardCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	guardCmd.Flags().Float64Var(&guardDailyCap, "daily-cap", 0, "Spend for the day at which to act (required)")
+	guardCmd.Flags().StringVar(&guardAction, "action", "pause", "What to do at the cap: pause or warn")
+	guardCmd.Flags().DurationVar(&guardInterval, "interval", 10*time.Minute, "Time between checks")
+	guardCmd.Flags().BoolVar(&guardResumeNextDay, "resume-next-day", false, "Re-enable a campaign the guard paused on the next day")
+	guardCmd.Flags().BoolVar(&guardOnce, "once", false, "Check once and exit")
+	guardCmd.MarkFlagRequired("campaign-id")
+	guardCmd.MarkFlagRequired("daily-cap")
+
+	rootCmd.AddCommand(guardCmd)
+}
+
+// GuardCheck is the outcome of one check.
+type GuardCheck struct {
+	Time       time.Time     `json:"time"`
+	CampaignID int64         `json:"campaignId"`
+	Name       string        `json:"name"`
+	Status     models.Status `json:"status"`
+	Spend      float64       `json:"spend"`
+	Cap        float64       `json:"cap"`
+	Currency   string        `json:"currency,omitempty"`
+	Action     string        `json:"action,omitempty"` // paused, warned, resumed
+	Note       string        `json:"note,omitempty"`
+}
+
+func runGuard(cmd *cobra.Command, args []string) error {
+	if guardAction != "pause" && guardAction != "warn" {
+		return fmt.Errorf("invalid --action %q (expected pause or warn)", guardAction)
+	}
+	if guardDailyCap <= 0 {
+		return fmt.Errorf("--daily-cap must be positive")
+	}
+	if guardInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	warnOrgTimeZone(client)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if !guardOnce {
+		fmt.Fprintf(os.Stderr, "Guarding campaign %d at %.2f per day, checking every %s. Press Ctrl-C to stop.\n", guardCampaignID, guardDailyCap, guardInterval)
+	}
+	for {
+		check, err := runGuardCheck(client.WithContext(ctx), time.Now())
+		if err != nil {
+			if guardOnce || ctx.Err() != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%s check failed: %v\n", time.Now().Format("2006-01-02 15:04"), err)
+		} else {
+			printGuardCheck(check)
+		}
+		if guardOnce {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(guardInterval):
+		}
+	}
+}
+
+// runGuardCheck compares the campaign's spend today with the cap, acts on it,
+// and records what it did.
+func runGuardCheck(client *api.Client, now time.Time) (*GuardCheck, error) {
+	store, err := guard.Load(profileName)
+	if err != nil {
+		return nil, err
+	}
+	campaigns := services.NewCampaignService(client)
+	c, err := campaigns.Get(guardCampaignID)
+	if err != nil {
+		return nil, fmt.Errorf("getting campaign %d: %w", guardCampaignID, err)
+	}
+	check := &GuardCheck{Time: now, CampaignID: c.ID, Name: c.Name, Status: c.Status, Cap: guardDailyCap}
+
+	rng, _ := daterange.Parse("today", now)
+	today := rng.StartDate()
+	trip := store.Get(guardCampaignID)
+	if trip != nil && trip.Date != today {
+		if guardResumeNextDay && trip.PausedAt != nil && c.Status == models.StatusPaused {
+			if _, err := campaigns.Update(c.ID, &models.CampaignUpdate{Status: models.StatusEnabled}); err != nil {
+				return nil, fmt.Errorf("re-enabling campaign %d: %w", c.ID, err)
+			}
+			c.Status = models.StatusEnabled
+			check.Status, check.Action = c.Status, "resumed"
+			check.Note = "paused by the guard on " + trip.Date
+		}
+		store.Clear(guardCampaignID)
+		if err := store.Save(); err != nil {
+			return nil, err
+		}
+		trip = nil
+	}
+
+	req := newRangeReportRequest(rng, maxPageSize)
+	req.TimeZone = "ORTZ"
+	resp, err := services.NewReportingService(client).GetCampaignReport(req)
+	if err != nil {
+		return nil, fmt.Errorf("getting campaign report: %w", err)
+	}
+	for _, e := range aggregate.ByEntity(resp, "campaignId", "campaignName") {
+		if e.ID == guardCampaignID {
+			check.Spend, check.Currency = roundCents(e.Spend), e.Currency
+		}
+	}
+
+	if check.Spend < guardDailyCap || trip != nil {
+		return check, nil
+	}
+	trip = &guard.Trip{CampaignID: c.ID, Date: today, Spend: check.Spend, Cap: guardDailyCap, Action: guardAction, TrippedAt: now.UTC()}
+	switch {
+	case guardAction == "warn":
+		check.Action = "warned"
+	case c.Status != models.StatusEnabled:
+		check.Note = "cap reached; campaign is already " + string(c.Status)
+	default:
+		if _, err := campaigns.Update(c.ID, &models.CampaignUpdate{Status: models.StatusPaused}); err != nil {
+			return nil, fmt.Errorf("pausing campaign %d: %w", c.ID, err)
+		}
+		pausedAt := now.UTC()
+		trip.PausedAt = &pausedAt
+		check.Status, check.Action = models.StatusPaused, "paused"
+	}
+	store.Set(trip)
+	if err := store.Save(); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+func printGuardCheck(c *GuardCheck) {
+	if getFormat() == output.FormatJSON {
+		data, _ := json.Marshal(c)
+		fmt.Println(string(data))
+		return
+	}
+	line := fmt.Sprintf("%s campaign %d (%s) %s: spent %.2f of %.2f %s", c.Time.Format("2006-01-02 15:04"), c.CampaignID, c.Name, c.Status, c.Spend, c.Cap, c.Currency)
+	switch c.Action {
+	case "paused":
+		line += " - CAP REACHED, campaign paused"
+	case "warned":
+		line += " - CAP REACHED"
+	case "resumed":
+		line += " - campaign re-enabled"
+	}
+	if c.Note != "" {
+		line += " (" + c.Note + ")"
+	}
+	fmt.Println(line)
+}
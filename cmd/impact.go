@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/relindex"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact <keyword|adgroup|campaign> <id>",
+	Short: "Show what pausing or deleting an entity would affect",
+	Long: `Trace an entity's relations before changing it: its parents, and for a keyword,
+the campaign's other keywords with the same text (in any ad group or match
+type) and the negative keywords that match it; for an ad group or campaign,
+the keywords and negatives under it.
+
+Relations are read from a local index of the campaign, rebuilt when it is
+older than an hour or with --refresh.
+
+Example:
+  asa-cli impact keyword 789
+  asa-cli impact adgroup 456 --refresh`,
+	Args: cobra.ExactArgs(2),
+	RunE: runImpact,
+}
+
+var (
+	impactCampaignID int64
+	impactRefresh    bool
+)
+
+// impactTypes are the entity types impact traces.
+var impactTypes = []string{"keyword", "adgroup", "campaign"}
+
+func init() {
+	impactCmd.Flags().Int64Var(&impactCampaignID, "campaign-id", 0, "Campaign of the keyword or ad group (default: search)")
+	impactCmd.Flags().BoolVar(&impactRefresh, "refresh", false, "Rebuild the campaign's relation index from the API")
+
+	rootCmd.AddCommand(impactCmd)
+}
+
+// Impact is an entity and what changing it would affect.
+type Impact struct {
+	Type       string           `json:"type"`
+	ID         int64            `json:"id"`
+	CampaignID int64            `json:"campaignId"`
+	IndexedAt  time.Time        `json:"indexedAt"`
+	Relations  []ImpactRelation `json:"relations"`
+}
+
+// ImpactRelation is one entity related to the traced one.
+type ImpactRelation struct {
+	Relation  string `json:"relation"`
+	Kind      string `json:"kind"`
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	MatchType string `json:"matchType,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+var impactColumns = []output.Column{
+	{Header: "RELATION", Field: "Relation"},
+	{Header: "KIND", Field: "Kind"},
+	{Header: "ID", Field: "ID"},
+	{Header: "NAME", Field: "Name"},
+	{Header: "MATCH TYPE", Field: "MatchType"},
+	{Header: "STATUS", Field: "Status"},
+}
+
+func runImpact(cmd *cobra.Command, args []string) error {
+	kind := strings.ToLower(args[0])
+	if !containsString(impactTypes, kind) {
+		return fmt.Errorf("unknown type %q (expected %s)", args[0], strings.Join(impactTypes, ", "))
+	}
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %s", args[1])
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	ix, err := relindex.Load(profileName)
+	if err != nil {
+		return err
+	}
+
+	campaignID, err := impactCampaign(client, ix, kind, id)
+	if err != nil {
+		return err
+	}
+	c, err := indexedCampaign(client, ix, campaignID)
+	if err != nil {
+		return err
+	}
+
+	impact := &Impact{Type: kind, ID: id, CampaignID: c.ID, IndexedAt: c.IndexedAt}
+	add := func(relation string, nodes ...relindex.Node) {
+		for _, n := range nodes {
+			impact.Relations = append(impact.Relations, ImpactRelation{
+				Relation: relation, Kind: n.Kind, ID: n.ID, Name: n.Name, MatchType: n.MatchType, Status: n.Status,
+			})
+		}
+	}
+	campaign := relindex.Node{Kind: relindex.KindCampaign, ID: c.ID, Name: c.Name, Status: c.Status}
+
+	var summary string
+	switch kind {
+	case "keyword":
+		kw, ok := c.Keyword(id)
+		if !ok {
+			return fmt.Errorf("no keyword with ID %d in campaign %d", id, c.ID)
+		}
+		ag, _ := c.AdGroup(kw.AdGroupID)
+		siblings, blocking := c.Siblings(kw), c.Blocking(kw)
+		add("target", kw)
+		add("parent", ag, campaign)
+		add("sibling", siblings...)
+		add("negative match", blocking...)
+		summary = fmt.Sprintf("%d keyword(s) share this text; %d negative keyword(s) match it", len(siblings), len(blocking))
+	case "adgroup":
+		ag, ok := c.AdGroup(id)
+		if !ok {
+			return fmt.Errorf("no ad group with ID %d in campaign %d", id, c.ID)
+		}
+		keywords, negatives := c.InAdGroup(id)
+		add("target", ag)
+		add("parent", campaign)
+		add("child", keywords...)
+		add("child", negatives...)
+		summary = fmt.Sprintf("%d keyword(s) and %d negative keyword(s) are in this ad group", len(keywords), len(negatives))
+	case "campaign":
+		negatives := c.CampaignNegatives()
+		add("target", campaign)
+		add("child", c.AdGroups...)
+		add("child", negatives...)
+		summary = fmt.Sprintf("%d ad group(s), %d keyword(s), and %d negative keyword(s) are in this campaign",
+			len(c.AdGroups), len(c.Keywords), len(c.Negatives))
+	}
+
+	if getFormat() != output.FormatTable {
+		output.Print(getFormat(), impact, nil)
+		return nil
+	}
+	output.Print(output.FormatTable, impact.Relations, impactColumns)
+	fmt.Printf("\n%s (indexed %s)\n", summary, c.IndexedAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+// impactCampaign returns the campaign holding the entity: --campaign-id, the
+// index, or a search of the org.
+func impactCampaign(client *api.Client, ix *relindex.Index, kind string, id int64) (int64, error) {
+	if kind == "campaign" {
+		return id, nil
+	}
+	if impactCampaignID != 0 {
+		return impactCampaignID, nil
+	}
+
+	byID := models.NewSelector(1, 0)
+	byID.Conditions = []models.Condition{{Field: "id", Operator: "EQUALS", Values: []string{strconv.FormatInt(id, 10)}}}
+	if kind == "adgroup" {
+		found, _, err := services.NewAdGroupService(client).FindInOrg(byID)
+		if err != nil {
+			return 0, fmt.Errorf("searching ad groups: %w", err)
+		}
+		if len(found) == 0 {
+			return 0, fmt.Errorf("no ad group with ID %d", id)
+		}
+		return found[0].CampaignID, nil
+	}
+
+	if c, ok := ix.FindKeyword(id); ok {
+		return c.ID, nil
+	}
+	kw, err := findKeywordByID(client, 0, byID)
+	if err != nil {
+		return 0, err
+	}
+	if kw == nil {
+		return 0, fmt.Errorf("no keyword with ID %d", id)
+	}
+	return kw.CampaignID, nil
+}
+
+// indexedCampaign returns a campaign's relations from the index, rebuilding
+// and saving them when they are stale or --refresh is set.
+func indexedCampaign(client *api.Client, ix *relindex.Index, campaignID int64) (*relindex.Campaign, error) {
+	if !impactRefresh {
+		if c, ok := ix.Fresh(campaignID, relindex.DefaultTTL); ok {
+			return c, nil
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Indexing campaign %d...\n", campaignID)
+	c, err := buildCampaignIndex(client, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ix.Put(c)
+	if err := ix.Save(); err != nil && verbose {
+		warnf("%v", err)
+	}
+	return c, nil
+}
+
+// buildCampaignIndex fetches a campaign's ad groups, keywords, and negative
+// keywords.
+func buildCampaignIndex(client *api.Client, campaignID int64) (*relindex.Campaign, error) {
+	camp, err := services.NewCampaignService(client).Get(campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("getting campaign %d: %w", campaignID, err)
+	}
+	c := &relindex.Campaign{ID: camp.ID, Name: camp.Name, Status: string(camp.Status), IndexedAt: time.Now()}
+	all := models.NewSelector(maxPageSize, 0)
+
+	adGroups, err := services.NewAdGroupService(client).FindAll(campaignID, all)
+	if err != nil {
+		return nil, fmt.Errorf("listing ad groups: %w", err)
+	}
+	for _, ag := range adGroups {
+		c.AdGroups = append(c.AdGroups, relindex.Node{Kind: relindex.KindAdGroup, ID: ag.ID, Name: ag.Name, Status: string(ag.Status)})
+	}
+
+	svc := services.NewKeywordService(client)
+	keywords, err := svc.FindAllInCampaign(campaignID, all)
+	if err != nil {
+		return nil, fmt.Errorf("listing keywords: %w", err)
+	}
+	for _, k := range keywords {
+		if k.Deleted {
+			continue
+		}
+		node := relindex.Node{
+			Kind: relindex.KindKeyword, ID: k.ID, AdGroupID: k.AdGroupID, Name: k.Text, MatchType: string(k.MatchType), Status: string(k.Status),
+		}
+		if k.BidAmount != nil {
+			node.Bid, node.Currency = k.BidAmount.Amount, k.BidAmount.Currency
+		}
+		c.Keywords = append(c.Keywords, node)
+	}
+
+	negatives, err := svc.FindAllCampaignNegativeKeywords(campaignID, all)
+	if err != nil {
+		return nil, fmt.Errorf("listing campaign negative keywords: %w", err)
+	}
+	for _, n := range negatives {
+		if !n.Deleted {
+			c.Negatives = append(c.Negatives, relindex.Node{
+				Kind: relindex.KindCampaignNegative, ID: n.ID, Name: n.Text, MatchType: string(n.MatchType), Status: string(n.Status),
+			})
+		}
+	}
+	negatives, err = svc.FindAllAdGroupNegativeKeywordsInCampaign(campaignID, all)
+	if err != nil {
+		return nil, fmt.Errorf("listing ad group negative keywords: %w", err)
+	}
+	for _, n := range negatives {
+		if !n.Deleted {
+			c.Negatives = append(c.Negatives, relindex.Node{
+				Kind: relindex.KindAdGroupNegative, ID: n.ID, AdGroupID: n.AdGroupID, Name: n.Text, MatchType: string(n.MatchType), Status: string(n.Status),
+			})
+		}
+	}
+	return c, nil
+}
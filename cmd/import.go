@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/importspec"
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create or update campaigns, ad groups, and keywords from a spec or a bulk sheet",
+	Long: `Import campaigns with their ad groups, keywords, and negative keywords from an
+import spec (YAML) or from another tool's bulk sheet, translated to a spec:
+
+  spec          an import spec (see the README for its fields)
+  searchads-ui  a keyword or bulk sheet downloaded from the Search Ads web UI
+  google-ads    a Google Ads Editor export (CSV or tab-separated UTF-16)
+
+Campaigns are matched by name, ad groups by name within their campaign, and
+keywords by text and match type. Missing ones are created; existing ones are
+updated where the spec's status, budget, or bids differ. Nothing is deleted.
+Creating a campaign needs an app ID, countries, and a daily budget, and
+creating an ad group a default bid; --app-id, --countries, and --default-bid
+fill in what the sheet lacks.
+
+Use --out to write the translated spec for review instead of importing, and
+--dry-run to list the changes without making them.
+
+Example:
+  asa-cli import --format google-ads editor-export.csv --out spec.yaml
+  asa-cli import --format spec spec.yaml --app-id 123456789 --countries US --dry-run
+  asa-cli import --format searchads-ui export.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+var (
+	importFormat     string
+	importOut        string
+	importDryRun     bool
+	importAppID      int64
+	importCountries  string
+	importDefaultBid string
+)
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "spec", "Input format: spec, searchads-ui, or google-ads")
+	importCmd.Flags().StringVar(&importOut, "out", "", "Write the translated spec to this file instead of importing")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "List the changes without making them")
+	importCmd.Flags().Int64Var(&importAppID, "app-id", 0, "App Adam ID for new campaigns without one")
+	importCmd.Flags().StringVar(&importCountries, "countries", "", "Comma-separated country codes for new campaigns without any")
+	importCmd.Flags().StringVar(&importDefaultBid, "default-bid", "", "Default bid for new ad groups without one")
+	rootCmd.AddCommand(importCmd)
+}
+
+// ImportAction is one entity an import creates, updates, or leaves alone.
+type ImportAction struct {
+	Entity   string `json:"entity"` // campaign, adgroup, keyword, campaign-negative, or adgroup-negative
+	Campaign string `json:"campaign"`
+	AdGroup  string `json:"adGroup,omitempty"`
+	Name     string `json:"name"`
+	ID       int64  `json:"id,omitempty"`
+	Action   string `json:"action"` // create, update, or unchanged
+	Detail   string `json:"detail,omitempty"`
+	Result   string `json:"result"` // planned, done, unchanged, or failed
+	Error    string `json:"error,omitempty"`
+
+	ResultText string `json:"-"`
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	spec, err := loadImportSpec(args[0])
+	if err != nil {
+		return err
+	}
+	if importOut != "" {
+		if err := spec.Write(explainPath(importOut)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d campaign(s) to %s. Import it with: asa-cli import --format spec %s\n", len(spec.Campaigns), importOut, importOut)
+		return nil
+	}
+
+	for i := range spec.Campaigns {
+		c := &spec.Campaigns[i]
+		if c.AppID == 0 {
+			c.AppID = importAppID
+		}
+		if len(c.Countries) == 0 && importCountries != "" {
+			c.Countries = strings.Split(strings.ToUpper(importCountries), ",")
+		}
+		for j := range c.AdGroups {
+			if c.AdGroups[j].DefaultBid == "" {
+				c.AdGroups[j].DefaultBid = importDefaultBid
+			}
+		}
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	currency, err := resolveOrgCurrency(client)
+	if err != nil {
+		return err
+	}
+	campaigns, err := services.NewCampaignService(client).FindAll(models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return fmt.Errorf("listing campaigns: %w", err)
+	}
+	live := map[string]*models.Campaign{}
+	for i := range campaigns {
+		live[campaigns[i].Name] = &campaigns[i]
+	}
+
+	im := &importer{
+		campaigns: services.NewCampaignService(client),
+		adGroups:  services.NewAdGroupService(client),
+		keywords:  services.NewKeywordService(client),
+		currency:  currency,
+	}
+	for _, c := range spec.Campaigns {
+		im.campaign(c, live[c.Name])
+	}
+
+	output.Print(getFormat(), im.actions, []output.Column{
+		{Header: "ENTITY", Field: "Entity"},
+		{Header: "CAMPAIGN", Field: "Campaign"},
+		{Header: "AD GROUP", Field: "AdGroup"},
+		{Header: "NAME", Field: "Name"},
+		{Header: "ACTION", Field: "Action"},
+		{Header: "DETAIL", Field: "Detail"},
+		{Header: "RESULT", Field: "ResultText"},
+	})
+
+	counts := map[string]int{}
+	failed := 0
+	for _, a := range im.actions {
+		if a.Result == "failed" {
+			failed++
+			continue
+		}
+		counts[a.Action]++
+	}
+	if importDryRun {
+		fmt.Fprintf(os.Stderr, "Dry run: %d to create, %d to update, %d unchanged. Re-run without --dry-run to import.\n", counts["create"], counts["update"], counts["unchanged"])
+	} else {
+		fmt.Fprintf(os.Stderr, "Created %d, updated %d, left %d unchanged.\n", counts["create"], counts["update"], counts["unchanged"])
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d change(s) failed", failed, len(im.actions))
+	}
+	return nil
+}
+
+// loadImportSpec reads a spec, or translates a bulk sheet and prints what
+// the translation left out.
+func loadImportSpec(path string) (*importspec.Spec, error) {
+	if importFormat == "spec" {
+		return importspec.Load(path)
+	}
+	var translate func(f *os.File) (*importspec.Spec, importspec.Warnings, error)
+	switch importFormat {
+	case "searchads-ui":
+		translate = func(f *os.File) (*importspec.Spec, importspec.Warnings, error) { return importspec.FromSearchAdsUI(f) }
+	case "google-ads":
+		translate = func(f *os.File) (*importspec.Spec, importspec.Warnings, error) { return importspec.FromGoogleAds(f) }
+	default:
+		return nil, fmt.Errorf("invalid --format %q (expected spec, searchads-ui, or google-ads)", importFormat)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	defer f.Close()
+	spec, warnings, err := translate(f)
+	for _, w := range warnings {
+		warnf("%s", w)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("translating %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// importer applies a spec, recording an ImportAction per entity. With
+// --dry-run it only reads.
+type importer struct {
+	campaigns *services.CampaignService
+	adGroups  *services.AdGroupService
+	keywords  *services.KeywordService
+	currency  string
+	actions   []ImportAction
+}
+
+func (im *importer) add(a ImportAction) *ImportAction {
+	switch {
+	case a.Action == "unchanged":
+		a.Result = "unchanged"
+	case importDryRun:
+		a.Result = "planned"
+	default:
+		a.Result = "done"
+	}
+	a.ResultText = a.Result
+	im.actions = append(im.actions, a)
+	return &im.actions[len(im.actions)-1]
+}
+
+func (im *importer) fail(a *ImportAction, err error) {
+	a.Result, a.Error = "failed", err.Error()
+	a.ResultText = "failed: " + a.Error
+}
+
+func (im *importer) money(amount string) *models.Money {
+	if amount == "" {
+		return nil
+	}
+	return &models.Money{Amount: amount, Currency: im.currency}
+}
+
+// campaign creates or updates a campaign, then its ad groups and negative
+// keywords. Nothing below a campaign that couldn't be created is imported.
+func (im *importer) campaign(c importspec.Campaign, live *models.Campaign) {
+	a := ImportAction{Entity: "campaign", Campaign: c.Name, Name: c.Name}
+	var id int64
+	if live == nil {
+		a.Action = "create"
+		a.Detail = fmt.Sprintf("app %d, %s, daily budget %s", c.AppID, strings.Join(c.Countries, ","), c.DailyBudget)
+		act := im.add(a)
+		var err error
+		switch {
+		case c.AppID == 0 || len(c.Countries) == 0 || c.DailyBudget == "":
+			err = fmt.Errorf("needs app_id, countries, and daily_budget to create (see --app-id and --countries)")
+		default:
+			if err = checkCampaignName(c.Name); err == nil {
+				err = checkBudgetLimit(c.DailyBudget)
+			}
+		}
+		if err == nil && !importDryRun {
+			status := c.Status
+			if status == "" {
+				status = models.StatusEnabled
+			}
+			var created *models.Campaign
+			created, err = im.campaigns.Create(&models.Campaign{
+				Name:               c.Name,
+				AdamID:             c.AppID,
+				Status:             status,
+				CountriesOrRegions: c.Countries,
+				DailyBudgetAmount:  im.money(c.DailyBudget),
+				AdChannelType:      models.ChannelSearch,
+				SupplySources:      []models.SupplySource{models.SupplySearchResults},
+				BillingEvent:       models.BillingTaps,
+			})
+			if err == nil {
+				id, act.ID = created.ID, created.ID
+			}
+		}
+		if err != nil {
+			im.fail(act, err)
+			return
+		}
+	} else {
+		id, a.ID = live.ID, live.ID
+		update := &models.CampaignUpdate{Status: c.Status, DailyBudgetAmount: im.money(c.DailyBudget)}
+		a.Action = "unchanged"
+		if !update.Unchanged(live) {
+			a.Action, a.Detail = "update", changeDetail("status", string(live.Status), string(c.Status), "daily budget", moneyAmount(live.DailyBudgetAmount), c.DailyBudget)
+		}
+		act := im.add(a)
+		if a.Action == "update" {
+			err := checkBudgetLimit(c.DailyBudget)
+			if err == nil && !importDryRun {
+				_, err = im.campaigns.Update(id, update)
+			}
+			if err != nil {
+				im.fail(act, err)
+			}
+		}
+	}
+
+	liveAdGroups := map[string]*models.AdGroup{}
+	var liveNegatives []models.NegativeKeyword
+	if live != nil {
+		adGroups, err := im.adGroups.FindAll(id, models.NewSelector(maxPageSize, 0))
+		if err != nil {
+			im.fail(im.add(ImportAction{Entity: "adgroup", Campaign: c.Name, Action: "create"}), fmt.Errorf("listing ad groups: %w", err))
+			return
+		}
+		for i := range adGroups {
+			liveAdGroups[adGroups[i].Name] = &adGroups[i]
+		}
+		if len(c.Negatives) > 0 {
+			if liveNegatives, err = im.keywords.FindAllCampaignNegativeKeywords(id, models.NewSelector(maxPageSize, 0)); err != nil {
+				im.fail(im.add(ImportAction{Entity: "campaign-negative", Campaign: c.Name, Action: "create"}), fmt.Errorf("listing negative keywords: %w", err))
+				return
+			}
+		}
+	}
+	for _, ag := range c.AdGroups {
+		im.adGroup(id, c.Name, ag, liveAdGroups[ag.Name])
+	}
+	im.negatives(ImportAction{Entity: "campaign-negative", Campaign: c.Name}, c.Negatives, liveNegatives, func(create []models.NegativeKeyword) ([]services.BulkResult[models.NegativeKeyword], error) {
+		return im.keywords.CreateCampaignNegativeKeywordsEach(id, create)
+	})
+}
+
+// adGroup creates or updates an ad group, then its keywords and negative
+// keywords. campaignID is 0 for a campaign not created yet (in a dry run).
+func (im *importer) adGroup(campaignID int64, campaign string, ag importspec.AdGroup, live *models.AdGroup) {
+	a := ImportAction{Entity: "adgroup", Campaign: campaign, AdGroup: ag.Name, Name: ag.Name}
+	var id int64
+	if live == nil {
+		a.Action, a.Detail = "create", "default bid "+ag.DefaultBid
+		act := im.add(a)
+		err := checkAdGroupName(ag.Name)
+		if err == nil && ag.DefaultBid == "" {
+			err = fmt.Errorf("needs default_bid to create (see --default-bid)")
+		}
+		if err == nil {
+			err = checkScaledBid(ag.DefaultBid)
+		}
+		if err == nil && !importDryRun {
+			status := ag.Status
+			if status == "" {
+				status = models.StatusEnabled
+			}
+			var created *models.AdGroup
+			created, err = im.adGroups.Create(campaignID, &models.AdGroup{
+				Name:             ag.Name,
+				Status:           status,
+				DefaultBidAmount: im.money(ag.DefaultBid),
+				PricingModel:     models.PricingCPC,
+			})
+			if err == nil {
+				id, act.ID = created.ID, created.ID
+			}
+		}
+		if err != nil {
+			im.fail(act, err)
+			return
+		}
+	} else {
+		id, a.ID = live.ID, live.ID
+		update := &models.AdGroupUpdate{Status: ag.Status, DefaultBidAmount: im.money(ag.DefaultBid)}
+		a.Action = "unchanged"
+		if !update.Unchanged(live) {
+			a.Action, a.Detail = "update", changeDetail("status", string(live.Status), string(ag.Status), "default bid", moneyAmount(live.DefaultBidAmount), ag.DefaultBid)
+		}
+		act := im.add(a)
+		if a.Action == "update" {
+			var err error
+			if ag.DefaultBid != "" {
+				err = checkScaledBid(ag.DefaultBid)
+			}
+			if err == nil && !importDryRun {
+				_, err = im.adGroups.Update(campaignID, id, update)
+			}
+			if err != nil {
+				im.fail(act, err)
+			}
+		}
+	}
+
+	var liveKeywords []models.Keyword
+	var liveNegatives []models.NegativeKeyword
+	if live != nil {
+		var err error
+		if liveKeywords, err = im.keywords.FindAll(campaignID, id, models.NewSelector(maxPageSize, 0)); err != nil {
+			im.fail(im.add(ImportAction{Entity: "keyword", Campaign: campaign, AdGroup: ag.Name, Action: "create"}), fmt.Errorf("listing keywords: %w", err))
+			return
+		}
+		if len(ag.Negatives) > 0 {
+			if liveNegatives, err = im.keywords.FindAllAdGroupNegativeKeywords(campaignID, id, models.NewSelector(maxPageSize, 0)); err != nil {
+				im.fail(im.add(ImportAction{Entity: "adgroup-negative", Campaign: campaign, AdGroup: ag.Name, Action: "create"}), fmt.Errorf("listing negative keywords: %w", err))
+				return
+			}
+		}
+	}
+	im.targetingKeywords(campaignID, id, campaign, ag, liveKeywords)
+	im.negatives(ImportAction{Entity: "adgroup-negative", Campaign: campaign, AdGroup: ag.Name}, ag.Negatives, liveNegatives, func(create []models.NegativeKeyword) ([]services.BulkResult[models.NegativeKeyword], error) {
+		return im.keywords.CreateAdGroupNegativeKeywordsEach(campaignID, id, create)
+	})
+}
+
+// targetingKeywords creates an ad group's missing keywords in one bulk
+// request and updates the bids and statuses of existing ones in another.
+func (im *importer) targetingKeywords(campaignID, adGroupID int64, campaign string, ag importspec.AdGroup, live []models.Keyword) {
+	byKey := map[string]*models.Keyword{}
+	for i := range live {
+		if !live[i].Deleted {
+			byKey[kwplan.Key(live[i].Text, string(live[i].MatchType))] = &live[i]
+		}
+	}
+
+	var create []models.Keyword
+	var createActs []int
+	var updates []models.KeywordUpdate
+	var updateActs []int
+	for _, k := range ag.Keywords {
+		a := ImportAction{Entity: "keyword", Campaign: campaign, AdGroup: ag.Name, Name: k.Text + " (" + string(k.MatchType) + ")"}
+		var err error
+		if k.Bid != "" {
+			err = checkBidLimit(k.Bid)
+		}
+		existing := byKey[kwplan.Key(k.Text, string(k.MatchType))]
+		if existing == nil {
+			a.Action = "create"
+			if k.Bid != "" {
+				a.Detail = "bid " + k.Bid
+			}
+			act := im.add(a)
+			if err != nil {
+				im.fail(act, err)
+				continue
+			}
+			create = append(create, models.Keyword{Text: k.Text, MatchType: k.MatchType, Status: k.Status, BidAmount: im.money(k.Bid)})
+			createActs = append(createActs, len(im.actions)-1)
+			continue
+		}
+		a.ID = existing.ID
+		update := models.KeywordUpdate{ID: existing.ID, Status: k.Status, BidAmount: im.money(k.Bid)}
+		a.Action = "unchanged"
+		if !update.Unchanged(existing) {
+			a.Action, a.Detail = "update", changeDetail("status", string(existing.Status), string(k.Status), "bid", moneyAmount(existing.BidAmount), k.Bid)
+		}
+		act := im.add(a)
+		if a.Action != "update" {
+			continue
+		}
+		if err != nil {
+			im.fail(act, err)
+			continue
+		}
+		updates = append(updates, update)
+		updateActs = append(updateActs, len(im.actions)-1)
+	}
+	if importDryRun || adGroupID == 0 {
+		return
+	}
+
+	if len(create) > 0 {
+		results, err := im.keywords.CreateEach(campaignID, adGroupID, create)
+		for i, idx := range createActs {
+			switch {
+			case err != nil:
+				im.fail(&im.actions[idx], err)
+			case i < len(results) && !results[i].OK():
+				im.fail(&im.actions[idx], fmt.Errorf("%s", results[i].Error))
+			case i < len(results):
+				im.actions[idx].ID = results[i].Item.ID
+			}
+		}
+	}
+	if len(updates) > 0 {
+		if _, err := im.keywords.Update(campaignID, adGroupID, updates); err != nil {
+			for _, idx := range updateActs {
+				im.fail(&im.actions[idx], err)
+			}
+		}
+	}
+}
+
+// negatives creates the negative keywords of a campaign or ad group that it
+// doesn't have yet, with create.
+func (im *importer) negatives(base ImportAction, keywords []importspec.Keyword, live []models.NegativeKeyword, create func([]models.NegativeKeyword) ([]services.BulkResult[models.NegativeKeyword], error)) {
+	have := map[string]int64{}
+	for _, n := range live {
+		if !n.Deleted {
+			have[kwplan.Key(n.Text, string(n.MatchType))] = n.ID
+		}
+	}
+	var missing []models.NegativeKeyword
+	var acts []int
+	for _, k := range keywords {
+		a := base
+		a.Name = k.Text + " (" + string(k.MatchType) + ")"
+		if id, ok := have[kwplan.Key(k.Text, string(k.MatchType))]; ok {
+			a.ID, a.Action = id, "unchanged"
+			im.add(a)
+			continue
+		}
+		a.Action = "create"
+		im.add(a)
+		missing = append(missing, models.NegativeKeyword{Text: k.Text, MatchType: k.MatchType})
+		acts = append(acts, len(im.actions)-1)
+	}
+	if importDryRun || len(missing) == 0 {
+		return
+	}
+	results, err := create(missing)
+	for i, idx := range acts {
+		switch {
+		case err != nil:
+			im.fail(&im.actions[idx], err)
+		case i < len(results) && !results[i].OK():
+			im.fail(&im.actions[idx], fmt.Errorf("%s", results[i].Error))
+		case i < len(results):
+			im.actions[idx].ID = results[i].Item.ID
+		}
+	}
+}
+
+// changeDetail describes the fields that change, as pairs of name, old
+// value, and new value; empty new values are left as they are.
+func changeDetail(fields ...string) string {
+	var parts []string
+	for i := 0; i+2 < len(fields); i += 3 {
+		name, from, to := fields[i], fields[i+1], fields[i+2]
+		if to != "" && to != from {
+			parts = append(parts, fmt.Sprintf("%s %s → %s", name, from, to))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
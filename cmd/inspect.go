@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get <campaign|adgroup|keyword|ad> <id>",
+	Short: "Get a campaign, ad group, keyword, or ad by ID alone",
+	Long: `Get an entity by its ID without knowing its campaign or ad group. Ad groups and
+ads are found with org-wide searches; keywords are searched campaign by
+campaign, which --campaign-id skips.
+
+Example:
+  asa-cli get adgroup 456
+  asa-cli get keyword 789 --campaign-id 123`,
+	Args: cobra.ExactArgs(2),
+	RunE: runGet,
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <id>",
+	Short: "Find what an ID refers to and print it with its parents",
+	Long: `Probe the campaign, ad group, ad, and keyword endpoints, in that order, for an
+ID of unknown type (e.g. from an MMP postback), and print the entity with its
+parent chain. Keywords are probed last since that searches every campaign;
+pass --campaign-id to search only one.
+
+Example:
+  asa-cli inspect 456`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspect,
+}
+
+var inspectCampaignID int64
+
+// inspectTypes are the entity types, in probing order.
+var inspectTypes = []string{"campaign", "adgroup", "ad", "keyword"}
+
+func init() {
+	getCmd.Flags().Int64Var(&inspectCampaignID, "campaign-id", 0, "Campaign to search (keywords only; default: every campaign)")
+	inspectCmd.Flags().Int64Var(&inspectCampaignID, "campaign-id", 0, "Campaign to search for keywords (default: every campaign)")
+
+	rootCmd.AddCommand(getCmd, inspectCmd)
+}
+
+// Inspection is an entity found by ID, with its parents.
+type Inspection struct {
+	Type     string           `json:"type"`
+	ID       int64            `json:"id"`
+	Chain    []InspectionLink `json:"chain"`
+	Campaign *models.Campaign `json:"campaign,omitempty"`
+	AdGroup  *models.AdGroup  `json:"adGroup,omitempty"`
+	Keyword  *models.Keyword  `json:"keyword,omitempty"`
+	Ad       *models.Ad       `json:"ad,omitempty"`
+}
+
+// InspectionLink is one level of an entity's parent chain.
+type InspectionLink struct {
+	Level  string `json:"level"`
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func runGet(cmd *cobra.Command, args []string) error {
+	kind := strings.ToLower(args[0])
+	if !containsString(inspectTypes, kind) {
+		return fmt.Errorf("unknown type %q (expected %s)", args[0], strings.Join(inspectTypes, ", "))
+	}
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %s", args[1])
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	in, err := locate(client, kind, id)
+	if err != nil {
+		return err
+	}
+	if in == nil {
+		return fmt.Errorf("no %s with ID %d", kind, id)
+	}
+
+	switch kind {
+	case "campaign":
+		output.Print(getFormat(), in.Campaign, campaignColumns)
+	case "adgroup":
+		output.Print(getFormat(), in.AdGroup, adgroupColumns)
+	case "keyword":
+		output.Print(getFormat(), in.Keyword, keywordColumns)
+	case "ad":
+		output.Print(getFormat(), in.Ad, adColumns)
+	}
+	return nil
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %s", args[0])
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	for _, kind := range inspectTypes {
+		in, err := locate(client, kind, id)
+		if api.IsVersionError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if in == nil {
+			continue
+		}
+		if getFormat() != output.FormatTable {
+			output.Print(getFormat(), in, nil)
+			return nil
+		}
+		fmt.Printf("%d is %s %s\n\n", id, article(kind), kind)
+		output.Print(output.FormatTable, in.Chain, []output.Column{
+			{Header: "LEVEL", Field: "Level"},
+			{Header: "ID", Field: "ID"},
+			{Header: "NAME", Field: "Name"},
+			{Header: "STATUS", Field: "Status"},
+		})
+		return nil
+	}
+	return fmt.Errorf("no campaign, ad group, ad, or keyword with ID %d in this org", id)
+}
+
+func article(kind string) string {
+	if kind == "adgroup" || kind == "ad" {
+		return "an"
+	}
+	return "a"
+}
+
+// locate finds an entity of kind by ID and its parents. It returns nil when
+// there is none.
+func locate(client *api.Client, kind string, id int64) (*Inspection, error) {
+	in := &Inspection{Type: kind, ID: id}
+	byID := models.NewSelector(1, 0)
+	byID.Conditions = []models.Condition{{Field: "id", Operator: "EQUALS", Values: []string{strconv.FormatInt(id, 10)}}}
+
+	var campaignID, adGroupID int64
+	switch kind {
+	case "campaign":
+		campaignID = id
+	case "adgroup":
+		found, _, err := services.NewAdGroupService(client).FindInOrg(byID)
+		if err != nil {
+			return nil, fmt.Errorf("searching ad groups: %w", err)
+		}
+		if len(found) == 0 {
+			return nil, nil
+		}
+		in.AdGroup = &found[0]
+		campaignID = found[0].CampaignID
+	case "ad":
+		found, _, err := services.NewAdService(client).FindInOrg(byID)
+		if err != nil {
+			return nil, fmt.Errorf("searching ads: %w", err)
+		}
+		if len(found) == 0 {
+			return nil, nil
+		}
+		in.Ad = &found[0]
+		campaignID, adGroupID = found[0].CampaignID, found[0].AdGroupID
+	case "keyword":
+		kw, err := findKeywordByID(client, inspectCampaignID, byID)
+		if err != nil || kw == nil {
+			return nil, err
+		}
+		in.Keyword = kw
+		campaignID, adGroupID = kw.CampaignID, kw.AdGroupID
+	}
+
+	c, err := services.NewCampaignService(client).Get(campaignID)
+	if err != nil {
+		if kind == "campaign" && api.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting campaign %d: %w", campaignID, err)
+	}
+	in.Campaign = c
+	in.Chain = append(in.Chain, InspectionLink{Level: "campaign", ID: c.ID, Name: c.Name, Status: string(c.Status)})
+
+	if in.AdGroup == nil && adGroupID != 0 {
+		ag, err := services.NewAdGroupService(client).Get(campaignID, adGroupID)
+		if err != nil {
+			return nil, fmt.Errorf("getting ad group %d: %w", adGroupID, err)
+		}
+		in.AdGroup = ag
+	}
+	if in.AdGroup != nil {
+		in.Chain = append(in.Chain, InspectionLink{Level: "adgroup", ID: in.AdGroup.ID, Name: in.AdGroup.Name, Status: string(in.AdGroup.Status)})
+	}
+	if in.Keyword != nil {
+		in.Chain = append(in.Chain, InspectionLink{Level: "keyword", ID: in.Keyword.ID, Name: in.Keyword.Text + " (" + string(in.Keyword.MatchType) + ")", Status: string(in.Keyword.Status)})
+	}
+	if in.Ad != nil {
+		in.Chain = append(in.Chain, InspectionLink{Level: "ad", ID: in.Ad.ID, Name: in.Ad.Name, Status: string(in.Ad.Status)})
+	}
+	return in, nil
+}
+
+// findKeywordByID searches campaignID, or every campaign when it is 0, for a
+// targeting keyword.
+func findKeywordByID(client *api.Client, campaignID int64, byID models.Selector) (*models.Keyword, error) {
+	ids := []int64{campaignID}
+	if campaignID == 0 {
+		campaigns, err := services.NewCampaignService(client).FindAll(models.NewSelector(maxPageSize, 0))
+		if err != nil {
+			return nil, fmt.Errorf("listing campaigns: %w", err)
+		}
+		ids = ids[:0]
+		for _, c := range campaigns {
+			ids = append(ids, c.ID)
+		}
+		fmt.Fprintf(os.Stderr, "Searching %d campaign(s) for keyword...\n", len(ids))
+	}
+	svc := services.NewKeywordService(client)
+	for _, id := range ids {
+		found, _, err := svc.FindInCampaign(id, byID)
+		if err != nil {
+			return nil, fmt.Errorf("searching keywords in campaign %d: %w", id, err)
+		}
+		if len(found) > 0 {
+			kw := found[0]
+			if kw.CampaignID == 0 {
+				kw.CampaignID = id
+			}
+			return &kw, nil
+		}
+	}
+	return nil, nil
+}
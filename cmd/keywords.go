@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
 	"github.com/trebuhs/asa-cli/internal/models"
 	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/refs"
 	"github.com/trebuhs/asa-cli/internal/services"
 )
 
@@ -48,25 +51,42 @@ var kwUpdateCmd = &cobra.Command{
 }
 
 var kwDeleteCmd = &cobra.Command{
-	Use:   "delete <id,...>",
+	Use:   "delete [<id,...>]",
 	Short: "Delete targeting keywords",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runKWDelete,
+	Long: `Delete keywords by ID, or every keyword matching --filter and --older-than.
+
+Matching deletes list what they will delete and ask you to type the number of
+keywords to confirm. In scripts, pass --yes with --max-items, which fails
+instead of deleting more than that many. Without --adgroup-id, matches are
+searched across the whole campaign. Keywords are deleted in batches per ad
+group and each keyword's result is reported.
+
+--older-than compares with the keyword's last modification time.
+
+Example:
+  asa-cli keywords delete 111,222 --campaign-id 123 --adgroup-id 456
+  asa-cli keywords delete --campaign-id 123 --filter status=PAUSED --older-than 90d
+  asa-cli keywords delete --campaign-id 123 --filter status=PAUSED --yes --max-items 200`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runKWDelete,
 }
 
 var (
 	kwCampaignID int64
 	kwAdGroupID  int64
-	kwLimit      int
-	kwOffset     int
+	kwPage       pager
 	kwFilters    []string
 	kwSorts      []string
 	kwAll        bool
 	kwTexts      []string
-	kwMatchType  string
+	kwMatchType  models.MatchType
 	kwBid        string
-	kwStatus     string
+	kwStatus     models.KeywordStatus
 	kwID         int64
+
+	kwFilterStatus    models.KeywordStatus
+	kwFilterMatchType models.MatchType
+	kwTextContains    string
 )
 
 func init() {
@@ -75,29 +95,38 @@ func init() {
 		cmd.Flags().Int64Var(&kwCampaignID, "campaign-id", 0, "Campaign ID (required)")
 		cmd.Flags().Int64Var(&kwAdGroupID, "adgroup-id", 0, "Ad group ID (required)")
 		cmd.MarkFlagRequired("campaign-id")
-		cmd.MarkFlagRequired("adgroup-id")
+		if cmd != kwListCmd && cmd != kwFindCmd && cmd != kwDeleteCmd {
+			cmd.MarkFlagRequired("adgroup-id")
+		}
+	}
+
+	// Server-side filters; without --adgroup-id they search the whole campaign.
+	for _, cmd := range []*cobra.Command{kwListCmd, kwFindCmd} {
+		cmd.Flags().Lookup("adgroup-id").Usage = "Ad group ID (omit to search all ad groups in the campaign)"
+		enumVar(cmd.Flags(), &kwFilterStatus, "status", "", models.ParseKeywordStatus, "Only keywords with this status (ACTIVE/PAUSED)")
+		enumVar(cmd.Flags(), &kwFilterMatchType, "match-type", "", models.ParseMatchType, "Only keywords with this match type (BROAD/EXACT)")
+		cmd.Flags().StringVar(&kwTextContains, "text-contains", "", "Only keywords whose text contains this")
 	}
 
 	// list
-	kwListCmd.Flags().IntVar(&kwLimit, "limit", 20, "Number of results")
-	kwListCmd.Flags().IntVar(&kwOffset, "offset", 0, "Results offset")
+	kwPage.register(kwListCmd)
 
 	// find
 	kwFindCmd.Flags().StringSliceVar(&kwFilters, "filter", nil, "Filter conditions")
 	kwFindCmd.Flags().StringSliceVar(&kwSorts, "sort", nil, "Sort order")
-	kwFindCmd.Flags().IntVar(&kwLimit, "limit", 20, "Number of results")
-	kwFindCmd.Flags().IntVar(&kwOffset, "offset", 0, "Results offset")
+	kwPage.register(kwFindCmd)
 	kwFindCmd.Flags().BoolVar(&kwAll, "all", false, "Fetch all pages")
 
 	// create
 	kwCreateCmd.Flags().StringSliceVar(&kwTexts, "text", nil, "Keyword text(s) — repeatable for bulk")
-	kwCreateCmd.Flags().StringVar(&kwMatchType, "match-type", "BROAD", "Match type: BROAD or EXACT")
+	enumVar(kwCreateCmd.Flags(), &kwMatchType, "match-type", models.MatchBroad, models.ParseMatchType, "Match type: BROAD or EXACT")
 	kwCreateCmd.Flags().StringVar(&kwBid, "bid", "", "Bid amount (e.g. 1.50)")
+	kwCreateCmd.Flags().StringVar(&externalID, "external-id", "", externalIDUsage+" (single --text only)")
 	kwCreateCmd.MarkFlagRequired("text")
 
 	// update
 	kwUpdateCmd.Flags().Int64Var(&kwID, "id", 0, "Keyword ID to update (required)")
-	kwUpdateCmd.Flags().StringVar(&kwStatus, "status", "", "Status (ACTIVE/PAUSED)")
+	enumVar(kwUpdateCmd.Flags(), &kwStatus, "status", "", models.ParseKeywordStatus, "Status (ACTIVE/PAUSED)")
 	kwUpdateCmd.Flags().StringVar(&kwBid, "bid", "", "Bid amount")
 	kwUpdateCmd.MarkFlagRequired("id")
 
@@ -119,16 +148,61 @@ func runKWList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	limit, offset, err := kwPage.resolve()
+	if err != nil {
+		return err
+	}
+
 	svc := services.NewKeywordService(client)
-	keywords, _, err := svc.List(kwCampaignID, kwAdGroupID, kwLimit, kwOffset)
+	filter := keywordFilter()
+	if filter.IsZero() && kwAdGroupID != 0 {
+		keywords, page, err := svc.List(kwCampaignID, kwAdGroupID, limit, offset)
+		if err != nil {
+			return fmt.Errorf("listing keywords: %w", err)
+		}
+		kwPage.print(keywords, page, keywordColumns)
+		return nil
+	}
+
+	keywords, page, err := findKeywords(svc, filter.Apply(models.NewSelector(limit, offset)), false)
 	if err != nil {
 		return fmt.Errorf("listing keywords: %w", err)
 	}
-
-	output.Print(getFormat(), keywords, keywordColumns)
+	kwPage.print(keywords, page, keywordListColumns())
 	return nil
 }
 
+func keywordFilter() services.KeywordFilter {
+	return services.KeywordFilter{Status: kwFilterStatus, MatchType: kwFilterMatchType, TextContains: kwTextContains}
+}
+
+// findKeywords runs a find in the ad group, or in the whole campaign when no
+// ad group is given. With all, every page is fetched and the page detail is
+// nil.
+func findKeywords(svc *services.KeywordService, selector models.Selector, all bool) ([]models.Keyword, *models.PageDetail, error) {
+	var keywords []models.Keyword
+	var err error
+	switch {
+	case kwAdGroupID == 0 && all:
+		keywords, err = svc.FindAllInCampaign(kwCampaignID, selector)
+	case kwAdGroupID == 0:
+		return svc.FindInCampaign(kwCampaignID, selector)
+	case all:
+		keywords, err = svc.FindAll(kwCampaignID, kwAdGroupID, selector)
+	default:
+		return svc.Find(kwCampaignID, kwAdGroupID, selector)
+	}
+	return keywords, nil, err
+}
+
+// keywordListColumns adds the ad group to campaign-wide results.
+func keywordListColumns() []output.Column {
+	if kwAdGroupID != 0 {
+		return keywordColumns
+	}
+	return append([]output.Column{{Header: "AD GROUP", Field: "AdGroupID", Width: 12}}, keywordColumns...)
+}
+
 func runKWGet(cmd *cobra.Command, args []string) error {
 	id, err := strconv.ParseInt(args[0], 10, 64)
 	if err != nil {
@@ -156,25 +230,20 @@ func runKWFind(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	selector := models.NewSelector(kwLimit, kwOffset)
+	selector, err := kwPage.selector()
+	if err != nil {
+		return err
+	}
 	selector.Conditions = parseFilters(kwFilters)
 	selector.OrderBy = parseSorts(kwSorts)
+	selector = keywordFilter().Apply(selector)
 
 	svc := services.NewKeywordService(client)
-
-	if kwAll {
-		keywords, err := svc.FindAll(kwCampaignID, kwAdGroupID, selector)
-		if err != nil {
-			return fmt.Errorf("finding keywords: %w", err)
-		}
-		output.Print(getFormat(), keywords, keywordColumns)
-	} else {
-		keywords, _, err := svc.Find(kwCampaignID, kwAdGroupID, selector)
-		if err != nil {
-			return fmt.Errorf("finding keywords: %w", err)
-		}
-		output.Print(getFormat(), keywords, keywordColumns)
+	keywords, page, err := findKeywords(svc, selector, kwAll)
+	if err != nil {
+		return fmt.Errorf("finding keywords: %w", err)
 	}
+	kwPage.print(keywords, page, keywordListColumns())
 	return nil
 }
 
@@ -208,10 +277,51 @@ func runKWCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewKeywordService(client)
-	created, err := svc.Create(kwCampaignID, kwAdGroupID, keywords)
+
+	if externalID != "" && len(keywords) != 1 {
+		return fmt.Errorf("--external-id needs exactly one --text")
+	}
+	store, ref, err := findExternalRef(refs.KindKeyword)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		existing, err := svc.Get(ref.CampaignID, ref.AdGroupID, ref.ID)
+		switch {
+		case err == nil && keywords[0].BidAmount == nil:
+			fmt.Fprintf(os.Stderr, "External ID %s is keyword %d; nothing to update.\n", externalID, ref.ID)
+			output.Print(getFormat(), existing, keywordColumns)
+			return nil
+		case err == nil:
+			fmt.Fprintf(os.Stderr, "External ID %s is keyword %d; updating it instead of creating a new one.\n", externalID, ref.ID)
+			updated, err := svc.Update(ref.CampaignID, ref.AdGroupID, []models.KeywordUpdate{{ID: ref.ID, BidAmount: keywords[0].BidAmount}})
+			if err != nil {
+				return fmt.Errorf("updating keyword: %w", err)
+			}
+			output.Print(getFormat(), updated, keywordColumns)
+			return nil
+		case api.IsNotFound(err):
+			if err := forgetExternalRef(store, refs.KindKeyword); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("getting keyword %d for external ID %s: %w", ref.ID, externalID, err)
+		}
+	}
+
+	results, err := svc.CreateEach(kwCampaignID, kwAdGroupID, keywords)
 	if err != nil {
 		return fmt.Errorf("creating keywords: %w", err)
 	}
+	if services.FailedCount(results) > 0 {
+		return printBulkItems(keywordBulkItems(results), "keyword(s)")
+	}
+	created := services.Succeeded(results)
+	if len(created) == 1 {
+		if err := recordExternalRef(store, refs.Ref{Kind: refs.KindKeyword, ID: created[0].ID, CampaignID: kwCampaignID, AdGroupID: kwAdGroupID}); err != nil {
+			return err
+		}
+	}
 
 	output.Print(getFormat(), created, keywordColumns)
 	return nil
@@ -239,16 +349,38 @@ func runKWUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewKeywordService(client)
-	updated, err := svc.Update(kwCampaignID, kwAdGroupID, []models.KeywordUpdate{update})
+	updated, unchanged, changes, err := svc.UpdateChanged(kwCampaignID, kwAdGroupID, []models.KeywordUpdate{update})
 	if err != nil {
 		return fmt.Errorf("updating keyword: %w", err)
 	}
+	if len(unchanged) > 0 {
+		fmt.Fprintf(os.Stderr, "Keyword %d already matches the update; skipped.\n", kwID)
+		updated = unchanged
+	}
+	printChanges(fmt.Sprintf("keyword %d", kwID), changes[kwID])
 
-	output.Print(getFormat(), updated, keywordColumns)
+	result := make([]interface{}, len(updated))
+	for i, k := range updated {
+		result[i] = withChanges(k, changes[k.ID])
+	}
+	if getFormat() != output.FormatJSON {
+		output.Print(getFormat(), updated, keywordColumns)
+		return nil
+	}
+	output.Print(getFormat(), result, keywordColumns)
 	return nil
 }
 
 func runKWDelete(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return runKWDeleteMatching(cmd)
+	}
+	if kwAdGroupID == 0 {
+		return fmt.Errorf("--adgroup-id is required when deleting by ID")
+	}
+	if len(kwDelFilters) > 0 || kwDelOlderThan != "" {
+		return fmt.Errorf("pass keyword IDs or --filter/--older-than, not both")
+	}
 	client, err := newAPIClient()
 	if err != nil {
 		return err
@@ -264,10 +396,16 @@ func runKWDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewKeywordService(client)
-	if err := svc.Delete(kwCampaignID, kwAdGroupID, ids); err != nil {
+	var skipped []int64
+	if ignoreMissing {
+		skipped, err = svc.DeleteExisting(kwCampaignID, kwAdGroupID, ids)
+	} else {
+		err = svc.Delete(kwCampaignID, kwAdGroupID, ids)
+	}
+	if err != nil {
 		return fmt.Errorf("deleting keywords: %w", err)
 	}
 
-	fmt.Printf("Deleted %d keyword(s).\n", len(ids))
+	printDeleted(len(ids), "keyword(s)", skipped)
 	return nil
 }
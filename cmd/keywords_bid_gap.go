@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var kwBidGapCmd = &cobra.Command{
+	Use:   "bid-gap",
+	Short: "List keywords bidding below Apple's suggested range",
+	Long: `Compare each live keyword's bid with the bid recommendation in the keyword
+report over --range, and list the keywords whose bid is below the bottom of the
+suggested range, with the gap.
+
+--apply raises each listed keyword's bid to the bottom of its suggested range.
+Every new bid is checked against the configured bid limits before any change.
+Keywords bidding the ad group default have no bid of their own and are skipped.
+
+Example:
+  asa-cli keywords bid-gap --campaign-id 123
+  asa-cli keywords bid-gap --campaign-id 123 --adgroup-id 456 --apply`,
+	RunE: runKWBidGap,
+}
+
+var (
+	bidGapRange string
+	bidGapApply bool
+)
+
+func init() {
+	kwBidGapCmd.Flags().Int64Var(&kwCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	kwBidGapCmd.Flags().Int64Var(&kwAdGroupID, "adgroup-id", 0, "Only keywords in this ad group")
+	kwBidGapCmd.Flags().StringVar(&bidGapRange, "range", "last-7d", "Report period for the recommendations: "+daterange.Help)
+	kwBidGapCmd.Flags().BoolVar(&bidGapApply, "apply", false, "Raise the listed bids to the bottom of the suggested range")
+	kwBidGapCmd.MarkFlagRequired("campaign-id")
+
+	keywordsCmd.AddCommand(kwBidGapCmd)
+}
+
+// KeywordBidGap is a keyword bidding below its suggested range.
+type KeywordBidGap struct {
+	ID           int64            `json:"id"`
+	AdGroupID    int64            `json:"adGroupId"`
+	Text         string           `json:"text"`
+	MatchType    models.MatchType `json:"matchType"`
+	Bid          float64          `json:"bid"`
+	SuggestedMin float64          `json:"suggestedMin"`
+	SuggestedMax float64          `json:"suggestedMax"`
+	Gap          float64          `json:"gap"`
+	Result       string           `json:"result,omitempty"`
+}
+
+var keywordBidGapColumns = []output.Column{
+	{Header: "AD GROUP", Field: "AdGroupID"},
+	{Header: "ID", Field: "ID"},
+	{Header: "TEXT", Field: "Text"},
+	{Header: "MATCH TYPE", Field: "MatchType"},
+	{Header: "BID", Field: "Bid"},
+	{Header: "SUGGESTED MIN", Field: "SuggestedMin"},
+	{Header: "SUGGESTED MAX", Field: "SuggestedMax"},
+	{Header: "GAP", Field: "Gap"},
+	{Header: "RESULT", Field: "Result"},
+}
+
+func runKWBidGap(cmd *cobra.Command, args []string) error {
+	rng, err := daterange.Parse(bidGapRange, time.Now())
+	if err != nil {
+		return err
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := services.NewReportingService(client).GetKeywordReport(kwCampaignID, newRangeReportRequest(rng, maxPageSize))
+	if err != nil {
+		return fmt.Errorf("getting keyword report: %w", err)
+	}
+	svc := services.NewKeywordService(client)
+	keywords, err := svc.FindAllInCampaign(kwCampaignID, models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return fmt.Errorf("listing keywords: %w", err)
+	}
+
+	gaps, noBid := findBidGaps(keywords, resp)
+	if noBid > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d keyword(s) bidding the ad group default.\n", noBid)
+	}
+	if len(gaps) == 0 {
+		fmt.Fprintf(os.Stderr, "No keywords bid below their suggested range (%s).\n", rng)
+		return nil
+	}
+	if !bidGapApply {
+		output.Print(getFormat(), gaps, keywordBidGapColumns)
+		fmt.Fprintf(os.Stderr, "%d keyword(s) below the suggested range. Re-run with --apply to raise them to the suggested minimum.\n", len(gaps))
+		return nil
+	}
+
+	for _, g := range gaps {
+		if err := checkBidLimit(strconv.FormatFloat(g.SuggestedMin, 'f', 2, 64)); err != nil {
+			return fmt.Errorf("keyword %d: %w", g.ID, err)
+		}
+	}
+	currency, err := resolveOrgCurrency(client)
+	if err != nil {
+		return err
+	}
+	failed := raiseBidGaps(svc, gaps, currency)
+	output.Print(getFormat(), gaps, keywordBidGapColumns)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d bid(s) could not be raised", failed, len(gaps))
+	}
+	fmt.Fprintf(os.Stderr, "Raised %d bid(s) to the suggested minimum.\n", len(gaps))
+	return nil
+}
+
+// findBidGaps returns the live keywords whose own bid is below the bottom of
+// their suggested range, biggest gap first, and how many live keywords had no
+// bid of their own.
+func findBidGaps(keywords []models.Keyword, resp *models.ReportingDataResponse) ([]KeywordBidGap, int) {
+	type bidRange struct{ min, max float64 }
+	suggested := map[int64]bidRange{}
+	for _, row := range resp.Row {
+		if row.Insights == nil || row.Insights.BidRecommendation == nil {
+			continue
+		}
+		rec := row.Insights.BidRecommendation
+		var r bidRange
+		if rec.SuggestedBidAmount != nil {
+			r.min = aggregate.Amount(*rec.SuggestedBidAmount)
+			r.max = r.min
+		}
+		if rec.BidMin != nil {
+			r.min = aggregate.Amount(*rec.BidMin)
+		}
+		if rec.BidMax != nil {
+			r.max = aggregate.Amount(*rec.BidMax)
+		}
+		if r.min > 0 {
+			suggested[aggregate.MetaInt64(row.Metadata, "keywordId")] = r
+		}
+	}
+
+	gaps := []KeywordBidGap{}
+	noBid := 0
+	for _, k := range keywords {
+		if k.Deleted || (kwAdGroupID != 0 && k.AdGroupID != kwAdGroupID) {
+			continue
+		}
+		if k.BidAmount == nil {
+			noBid++
+			continue
+		}
+		r, ok := suggested[k.ID]
+		bid := aggregate.Amount(*k.BidAmount)
+		if !ok || bid >= r.min {
+			continue
+		}
+		gaps = append(gaps, KeywordBidGap{
+			ID:           k.ID,
+			AdGroupID:    k.AdGroupID,
+			Text:         k.Text,
+			MatchType:    k.MatchType,
+			Bid:          bid,
+			SuggestedMin: r.min,
+			SuggestedMax: r.max,
+			Gap:          roundCents(r.min - bid),
+		})
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].Gap > gaps[j].Gap })
+	return gaps, noBid
+}
+
+// raiseBidGaps sets each keyword's bid to its suggested minimum, one update
+// per ad group, records the results, and returns how many failed.
+func raiseBidGaps(svc *services.KeywordService, gaps []KeywordBidGap, currency string) int {
+	byAdGroup := map[int64][]int{}
+	var adGroups []int64
+	for i, g := range gaps {
+		if _, ok := byAdGroup[g.AdGroupID]; !ok {
+			adGroups = append(adGroups, g.AdGroupID)
+		}
+		byAdGroup[g.AdGroupID] = append(byAdGroup[g.AdGroupID], i)
+	}
+	failed := 0
+	for _, adGroupID := range adGroups {
+		var updates []models.KeywordUpdate
+		for _, i := range byAdGroup[adGroupID] {
+			updates = append(updates, models.KeywordUpdate{ID: gaps[i].ID, BidAmount: &models.Money{Amount: strconv.FormatFloat(gaps[i].SuggestedMin, 'f', 2, 64), Currency: currency}})
+		}
+		_, err := svc.Update(kwCampaignID, adGroupID, updates)
+		for _, i := range byAdGroup[adGroupID] {
+			if err != nil {
+				gaps[i].Result = "failed: " + err.Error()
+				failed++
+			} else {
+				gaps[i].Result = "raised to " + strconv.FormatFloat(gaps[i].SuggestedMin, 'f', 2, 64)
+			}
+		}
+	}
+	return failed
+}
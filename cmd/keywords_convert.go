@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var kwConvertMatchCmd = &cobra.Command{
+	Use:   "convert-match",
+	Short: "Copy converting keywords to another match type",
+	Long: `Graduate keywords from discovery to performance: every live --from keyword in
+the campaign with at least --min-installs installs over --range gets a --to
+copy with the same bid (or --bid).
+
+Copies go to the source keyword's own ad group, or to --target-adgroup-id.
+--pause-source pauses the originals once their copies exist. --add-negatives
+adds the text as a --to negative in the source ad group, so the discovery ad
+group stops competing with the new keyword; it needs --target-adgroup-id.
+
+Keywords whose text already exists with the --to match type in the target ad
+group are skipped. Use --dry-run to see the plan without changing anything.
+
+Example:
+  asa-cli keywords convert-match --from BROAD --to EXACT --campaign-id 123 \
+    --min-installs 3 --target-adgroup-id 456 --pause-source --add-negatives`,
+	RunE: runKWConvertMatch,
+}
+
+var (
+	convFrom          models.MatchType
+	convTo            models.MatchType
+	convRange         string
+	convMinInstalls   int64
+	convMaxCPI        float64
+	convTargetAdGroup int64
+	convBid           string
+	convPauseSource   bool
+	convAddNegatives  bool
+	convDryRun        bool
+)
+
+func init() {
+	kwConvertMatchCmd.Flags().Int64Var(&kwCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	enumVar(kwConvertMatchCmd.Flags(), &convFrom, "from", models.MatchBroad, models.ParseMatchType, "Match type to convert from: BROAD or EXACT")
+	enumVar(kwConvertMatchCmd.Flags(), &convTo, "to", models.MatchExact, models.ParseMatchType, "Match type to create: BROAD or EXACT")
+	kwConvertMatchCmd.Flags().StringVar(&convRange, "range", "last-30d", "Date range: "+daterange.Help)
+	kwConvertMatchCmd.Flags().Int64Var(&convMinInstalls, "min-installs", 1, "Only convert keywords with at least this many installs")
+	kwConvertMatchCmd.Flags().Float64Var(&convMaxCPI, "max-cpi", 0, "Only convert keywords with a CPI at or below this")
+	kwConvertMatchCmd.Flags().Int64Var(&convTargetAdGroup, "target-adgroup-id", 0, "Ad group for the copies (default: the source keyword's ad group)")
+	kwConvertMatchCmd.Flags().StringVar(&convBid, "bid", "", "Bid for the copies (default: the source keyword's bid)")
+	kwConvertMatchCmd.Flags().BoolVar(&convPauseSource, "pause-source", false, "Pause the source keywords after copying")
+	kwConvertMatchCmd.Flags().BoolVar(&convAddNegatives, "add-negatives", false, "Add the texts as --to negatives in the source ad groups")
+	kwConvertMatchCmd.Flags().BoolVar(&convDryRun, "dry-run", false, "Print the plan without changing anything")
+	kwConvertMatchCmd.MarkFlagRequired("campaign-id")
+
+	keywordsCmd.AddCommand(kwConvertMatchCmd)
+}
+
+// MatchConversion is a keyword copied to another match type.
+type MatchConversion struct {
+	SourceID        int64   `json:"sourceId"`
+	SourceAdGroupID int64   `json:"sourceAdGroupId"`
+	Text            string  `json:"text"`
+	TargetAdGroupID int64   `json:"targetAdGroupId"`
+	Bid             string  `json:"bid,omitempty"`
+	NewID           int64   `json:"newId,omitempty"`
+	NegativeID      int64   `json:"negativeId,omitempty"`
+	SourcePaused    bool    `json:"sourcePaused,omitempty"`
+	Installs        int64   `json:"installs"`
+	CPI             float64 `json:"cpi"`
+
+	CPIText string `json:"-"`
+	Result  string `json:"-"`
+}
+
+func runKWConvertMatch(cmd *cobra.Command, args []string) error {
+	if convFrom == convTo {
+		return fmt.Errorf("--from and --to are both %s", convFrom)
+	}
+	if convAddNegatives && convTargetAdGroup == 0 {
+		return fmt.Errorf("--add-negatives needs --target-adgroup-id; a negative in the same ad group would block the new keyword")
+	}
+	rng, err := daterange.Parse(convRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewKeywordService(client)
+
+	resp, err := services.NewReportingService(client).GetKeywordReport(kwCampaignID, newRangeReportRequest(rng, maxPageSize))
+	if err != nil {
+		return fmt.Errorf("getting keyword report: %w", err)
+	}
+	keywords, err := svc.FindAllInCampaign(kwCampaignID, models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return fmt.Errorf("listing keywords: %w", err)
+	}
+
+	plan := planMatchConversions(keywords, aggregate.ByEntity(resp, "keywordId", "keyword"))
+	if len(plan) == 0 {
+		if getFormat() == output.FormatTable {
+			fmt.Printf("No %s keywords in campaign %d qualify for conversion (%s).\n", convFrom, kwCampaignID, rng)
+		}
+		return nil
+	}
+	// Check every bid against the limits before creating any keyword.
+	for _, c := range plan {
+		if c.Bid == "" {
+			continue
+		}
+		if err := checkBidLimit(c.Bid); err != nil {
+			return fmt.Errorf("keyword %d: %w", c.SourceID, err)
+		}
+	}
+
+	if !convDryRun {
+		err = applyMatchConversions(client, svc, plan)
+	}
+	printMatchConversions(plan)
+	if convDryRun {
+		fmt.Fprintf(os.Stderr, "Dry run: %d keyword(s) would be converted from %s to %s. Re-run without --dry-run to apply.\n", len(plan), convFrom, convTo)
+	}
+	return err
+}
+
+// planMatchConversions picks the live --from keywords that meet the install
+// and CPI thresholds and have no --to copy in their target ad group yet.
+func planMatchConversions(keywords []models.Keyword, stats []aggregate.Entity) []MatchConversion {
+	byID := map[int64]aggregate.Metrics{}
+	for _, e := range stats {
+		byID[e.ID] = e.Metrics
+	}
+	existing := map[string]bool{}
+	key := func(adGroupID int64, text string) string {
+		return fmt.Sprintf("%d|%s", adGroupID, aggregate.NormalizeTerm(text))
+	}
+	for _, k := range keywords {
+		if !k.Deleted && k.MatchType == convTo {
+			existing[key(k.AdGroupID, k.Text)] = true
+		}
+	}
+
+	plan := []MatchConversion{}
+	for _, k := range keywords {
+		if k.Deleted || k.MatchType != convFrom {
+			continue
+		}
+		m := byID[k.ID]
+		if m.Installs == 0 || m.Installs < convMinInstalls {
+			continue
+		}
+		if convMaxCPI > 0 && m.CPI() > convMaxCPI {
+			continue
+		}
+		target := k.AdGroupID
+		if convTargetAdGroup != 0 {
+			target = convTargetAdGroup
+		}
+		if existing[key(target, k.Text)] {
+			continue
+		}
+		existing[key(target, k.Text)] = true
+
+		c := MatchConversion{
+			SourceID:        k.ID,
+			SourceAdGroupID: k.AdGroupID,
+			Text:            k.Text,
+			TargetAdGroupID: target,
+			Bid:             convBid,
+			Installs:        m.Installs,
+			CPI:             roundCents(m.CPI()),
+			CPIText:         fmt.Sprintf("%.2f", m.CPI()),
+			Result:          "planned",
+		}
+		if c.Bid == "" && k.BidAmount != nil {
+			c.Bid = k.BidAmount.Amount
+		}
+		plan = append(plan, c)
+	}
+
+	// Most installs first, then cheapest.
+	sort.SliceStable(plan, func(i, j int) bool {
+		if plan[i].Installs != plan[j].Installs {
+			return plan[i].Installs > plan[j].Installs
+		}
+		return plan[i].CPI < plan[j].CPI
+	})
+	return plan
+}
+
+// applyMatchConversions creates the copies per target ad group, then pauses
+// sources and adds negatives for the copies that were created. It stops at the
+// first failed request; plan records what was done.
+func applyMatchConversions(client *api.Client, svc *services.KeywordService, plan []MatchConversion) error {
+	currency, err := resolveOrgCurrency(client)
+	if err != nil {
+		return err
+	}
+
+	byTarget := map[int64][]int{}
+	var targets []int64
+	for i, c := range plan {
+		if _, ok := byTarget[c.TargetAdGroupID]; !ok {
+			targets = append(targets, c.TargetAdGroupID)
+		}
+		byTarget[c.TargetAdGroupID] = append(byTarget[c.TargetAdGroupID], i)
+	}
+	for _, target := range targets {
+		var create []models.Keyword
+		for _, i := range byTarget[target] {
+			kw := models.Keyword{Text: plan[i].Text, MatchType: convTo}
+			if plan[i].Bid != "" {
+				kw.BidAmount = &models.Money{Amount: plan[i].Bid, Currency: currency}
+			}
+			create = append(create, kw)
+		}
+		created, err := svc.Create(kwCampaignID, target, create)
+		if err != nil {
+			return fmt.Errorf("creating %s keywords in ad group %d: %w", convTo, target, err)
+		}
+		for _, k := range created {
+			for _, i := range byTarget[target] {
+				if plan[i].NewID == 0 && aggregate.NormalizeTerm(plan[i].Text) == aggregate.NormalizeTerm(k.Text) {
+					plan[i].NewID = k.ID
+					plan[i].Result = "created"
+					break
+				}
+			}
+		}
+	}
+
+	bySource := map[int64][]int{}
+	var sources []int64
+	for i, c := range plan {
+		if c.NewID == 0 {
+			continue
+		}
+		if _, ok := bySource[c.SourceAdGroupID]; !ok {
+			sources = append(sources, c.SourceAdGroupID)
+		}
+		bySource[c.SourceAdGroupID] = append(bySource[c.SourceAdGroupID], i)
+	}
+	for _, source := range sources {
+		if convPauseSource {
+			var updates []models.KeywordUpdate
+			for _, i := range bySource[source] {
+				updates = append(updates, models.KeywordUpdate{ID: plan[i].SourceID, Status: "PAUSED"})
+			}
+			if _, err := svc.Update(kwCampaignID, source, updates); err != nil {
+				return fmt.Errorf("pausing source keywords in ad group %d: %w", source, err)
+			}
+			for _, i := range bySource[source] {
+				plan[i].SourcePaused = true
+			}
+		}
+		if convAddNegatives {
+			var negatives []models.NegativeKeyword
+			for _, i := range bySource[source] {
+				negatives = append(negatives, models.NegativeKeyword{Text: plan[i].Text, MatchType: convTo})
+			}
+			created, err := svc.CreateAdGroupNegativeKeywords(kwCampaignID, source, negatives)
+			if err != nil {
+				return fmt.Errorf("adding negatives to ad group %d: %w", source, err)
+			}
+			for _, n := range created {
+				for _, i := range bySource[source] {
+					if plan[i].NegativeID == 0 && aggregate.NormalizeTerm(plan[i].Text) == aggregate.NormalizeTerm(n.Text) {
+						plan[i].NegativeID = n.ID
+						break
+					}
+				}
+			}
+		}
+	}
+	for i := range plan {
+		if plan[i].NewID == 0 {
+			continue
+		}
+		var done []string
+		if plan[i].SourcePaused {
+			done = append(done, "source paused")
+		}
+		if plan[i].NegativeID != 0 {
+			done = append(done, "negative added")
+		}
+		if len(done) > 0 {
+			plan[i].Result += ", " + strings.Join(done, ", ")
+		}
+	}
+	return nil
+}
+
+func printMatchConversions(plan []MatchConversion) {
+	output.Print(getFormat(), plan, []output.Column{
+		{Header: "SOURCE ID", Field: "SourceID"},
+		{Header: "AD GROUP", Field: "SourceAdGroupID"},
+		{Header: "TEXT", Field: "Text"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "CPI", Field: "CPIText"},
+		{Header: "BID", Field: "Bid"},
+		{Header: "TARGET AD GROUP", Field: "TargetAdGroupID"},
+		{Header: "NEW ID", Field: "NewID"},
+		{Header: "RESULT", Field: "Result"},
+	})
+}
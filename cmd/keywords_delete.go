@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/executor"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var (
+	kwDelFilters   []string
+	kwDelOlderThan string
+	kwDelYes       bool
+	kwDelMaxItems  int
+	kwDelBatchSize int
+	kwDelDryRun    bool
+)
+
+func init() {
+	kwDeleteCmd.Flags().Lookup("adgroup-id").Usage = "Ad group ID (required with IDs; omit to match across the campaign)"
+	kwDeleteCmd.Flags().StringSliceVar(&kwDelFilters, "filter", nil, "Delete keywords matching these conditions (e.g. status=PAUSED)")
+	kwDeleteCmd.Flags().StringVar(&kwDelOlderThan, "older-than", "", "Only keywords last modified longer ago than this (e.g. 90d)")
+	kwDeleteCmd.Flags().BoolVar(&kwDelYes, "yes", false, "Delete matches without asking (requires --max-items)")
+	kwDeleteCmd.Flags().IntVar(&kwDelMaxItems, "max-items", 0, "With --yes, fail if more than this many keywords match")
+	kwDeleteCmd.Flags().IntVar(&kwDelBatchSize, "batch-size", 100, "Most keywords per delete request")
+	addConcurrencyFlag(kwDeleteCmd)
+	addIgnoreMissingFlag(kwDeleteCmd)
+	kwDeleteCmd.Flags().BoolVar(&kwDelDryRun, "dry-run", false, "List the matching keywords without deleting them")
+}
+
+// KeywordDeletion is the result of deleting one keyword.
+type KeywordDeletion struct {
+	ID               int64                `json:"id"`
+	AdGroupID        int64                `json:"adGroupId"`
+	Text             string               `json:"text"`
+	MatchType        models.MatchType     `json:"matchType"`
+	Status           models.KeywordStatus `json:"status"`
+	ModificationTime string               `json:"modificationTime,omitempty"`
+	Result           string               `json:"result"` // matched, deleted, skipped, failed
+	Error            string               `json:"error,omitempty"`
+
+	ResultText string `json:"-"`
+}
+
+var keywordDeletionColumns = []output.Column{
+	{Header: "AD GROUP", Field: "AdGroupID"},
+	{Header: "ID", Field: "ID"},
+	{Header: "TEXT", Field: "Text"},
+	{Header: "MATCH TYPE", Field: "MatchType"},
+	{Header: "STATUS", Field: "Status"},
+	{Header: "MODIFIED", Field: "ModificationTime"},
+	{Header: "RESULT", Field: "ResultText"},
+}
+
+// runKWDeleteMatching deletes the keywords matching --filter and --older-than
+// after confirmation.
+func runKWDeleteMatching(cmd *cobra.Command) error {
+	if len(kwDelFilters) == 0 && kwDelOlderThan == "" {
+		return fmt.Errorf("pass keyword IDs, or --filter and/or --older-than to select keywords")
+	}
+	if kwDelBatchSize <= 0 {
+		return fmt.Errorf("--batch-size must be positive")
+	}
+	if kwDelYes && kwDelMaxItems <= 0 {
+		return fmt.Errorf("--yes requires --max-items")
+	}
+	var cutoff time.Time
+	if kwDelOlderThan != "" {
+		age, err := daterange.ParseAge(kwDelOlderThan)
+		if err != nil {
+			return err
+		}
+		cutoff = time.Now().Add(-age)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewKeywordService(client)
+	selector := models.NewSelector(maxPageSize, 0)
+	selector.Conditions = parseFilters(kwDelFilters)
+	keywords, _, err := findKeywords(svc, selector, true)
+	if err != nil {
+		return fmt.Errorf("finding keywords: %w", err)
+	}
+
+	var matches []KeywordDeletion
+	undated := 0
+	for _, k := range keywords {
+		if k.Deleted {
+			continue
+		}
+		if !cutoff.IsZero() {
+			modified, err := daterange.ParseAPITime(k.ModificationTime)
+			if err != nil {
+				undated++
+				continue
+			}
+			if !modified.Before(cutoff) {
+				continue
+			}
+		}
+		adGroupID := k.AdGroupID
+		if adGroupID == 0 {
+			adGroupID = kwAdGroupID
+		}
+		matches = append(matches, KeywordDeletion{
+			ID:               k.ID,
+			AdGroupID:        adGroupID,
+			Text:             k.Text,
+			MatchType:        k.MatchType,
+			Status:           k.Status,
+			ModificationTime: k.ModificationTime,
+			Result:           "matched",
+			ResultText:       "matched",
+		})
+	}
+	if undated > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d keyword(s) without a readable modification time.\n", undated)
+	}
+	if len(matches) == 0 {
+		fmt.Fprintln(os.Stderr, "No keywords match.")
+		return nil
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].AdGroupID < matches[j].AdGroupID })
+
+	if kwDelDryRun {
+		output.Print(getFormat(), matches, keywordDeletionColumns)
+		fmt.Fprintf(os.Stderr, "Dry run: %d keyword(s) would be deleted.\n", len(matches))
+		return nil
+	}
+	if kwDelYes {
+		if len(matches) > kwDelMaxItems {
+			return fmt.Errorf("%d keywords match, more than --max-items %d; nothing deleted", len(matches), kwDelMaxItems)
+		}
+	} else {
+		output.Print(output.FormatTable, matches, keywordDeletionColumns)
+		if !confirmCount(len(matches), "keyword(s)") {
+			return fmt.Errorf("aborted; nothing deleted")
+		}
+	}
+
+	failed, skipped := deleteKeywordBatches(svc, matches)
+	output.Print(getFormat(), matches, keywordDeletionColumns)
+	summary := fmt.Sprintf("Deleted %d of %d keyword(s)", len(matches)-failed-skipped, len(matches))
+	if skipped > 0 {
+		summary += fmt.Sprintf("; skipped %d already deleted", skipped)
+	}
+	fmt.Fprintln(os.Stderr, summary+".")
+	if failed > 0 {
+		return fmt.Errorf("%d keyword(s) could not be deleted", failed)
+	}
+	return nil
+}
+
+// deleteKeywordBatches deletes matches per ad group in concurrent batches of
+// up to --batch-size, records each keyword's result, and returns how many
+// failed and, with --ignore-missing, how many were already deleted. A failed
+// batch doesn't stop the others.
+func deleteKeywordBatches(svc *services.KeywordService, matches []KeywordDeletion) (failed, skipped int) {
+	exec := newExecutor("Deleting keywords", kwDelBatchSize, false)
+	for start := 0; start < len(matches); {
+		end := start
+		for end < len(matches) && matches[end].AdGroupID == matches[start].AdGroupID {
+			end++
+		}
+		adGroupID := matches[start].AdGroupID
+		executor.Batches(exec, executor.Write, matches[start:end], func(batch []KeywordDeletion) error {
+			ids := make([]int64, len(batch))
+			for i, m := range batch {
+				ids[i] = m.ID
+			}
+			if !ignoreMissing {
+				return svc.Delete(kwCampaignID, adGroupID, ids)
+			}
+			missing, err := svc.DeleteExisting(kwCampaignID, adGroupID, ids)
+			for _, id := range missing {
+				for i := range batch {
+					if batch[i].ID == id {
+						batch[i].Result, batch[i].ResultText = "skipped", "skipped: already deleted"
+					}
+				}
+			}
+			return err
+		}, func(_ int, batch []KeywordDeletion, err error) {
+			for i := range batch {
+				switch {
+				case err != nil:
+					batch[i].Result, batch[i].Error = "failed", err.Error()
+					batch[i].ResultText = "failed: " + err.Error()
+				case batch[i].Result != "skipped":
+					batch[i].Result, batch[i].ResultText = "deleted", "deleted"
+				}
+			}
+		})
+		start = end
+	}
+	stats, _ := exec.Wait()
+	printThroughput(stats)
+	for _, m := range matches {
+		if m.Result == "skipped" {
+			skipped++
+		}
+	}
+	return stats.Failed, skipped
+}
+
+// confirmCount asks the user to type n to go ahead with an action on n items.
+func confirmCount(n int, items string) bool {
+	if fd := os.Stdin.Fd(); !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd) {
+		fmt.Fprintln(os.Stderr, "Not a terminal; pass --yes with --max-items to confirm.")
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "This deletes %d %s. Type %d to confirm: ", n, items, n)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer) == strconv.Itoa(n)
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/relindex"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var kwGrepCmd = &cobra.Command{
+	Use:   "grep <text>",
+	Short: "Search keyword and negative keyword text across all campaigns",
+	Long: `Find every targeting keyword and negative keyword whose text contains <text>,
+in every campaign, and show where each one lives with its status and bid.
+Matching ignores case and extra spaces; --exact matches the whole text.
+
+Campaigns in the local relation index (built by impact, at most an hour old)
+are searched without API calls; the others are searched with the API's find
+endpoints. --refresh searches them all through the API.
+
+Example:
+  asa-cli keywords grep photo
+  asa-cli keywords grep "photo editor" --exact --campaign-filter status=ENABLED`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKWGrep,
+}
+
+var (
+	grepExact           bool
+	grepNoNegatives     bool
+	grepRefresh         bool
+	grepCampaignFilters []string
+)
+
+func init() {
+	kwGrepCmd.Flags().BoolVar(&grepExact, "exact", false, "Match the whole keyword text, not part of it")
+	kwGrepCmd.Flags().BoolVar(&grepNoNegatives, "no-negatives", false, "Search targeting keywords only")
+	kwGrepCmd.Flags().BoolVar(&grepRefresh, "refresh", false, "Search through the API even where the local index is fresh")
+	kwGrepCmd.Flags().StringSliceVar(&grepCampaignFilters, "campaign-filter", nil, `Only campaigns matching these conditions (e.g. "name~US")`)
+
+	keywordsCmd.AddCommand(kwGrepCmd)
+}
+
+// KeywordMatch is a keyword or negative keyword whose text matched a grep.
+type KeywordMatch struct {
+	CampaignID   int64         `json:"campaignId"`
+	CampaignName string        `json:"campaignName"`
+	AdGroupID    int64         `json:"adGroupId,omitempty"`
+	AdGroupName  string        `json:"adGroupName,omitempty"`
+	Kind         string        `json:"kind"` // keyword, campaign-negative, or adgroup-negative
+	ID           int64         `json:"id"`
+	Text         string        `json:"text"`
+	MatchType    string        `json:"matchType"`
+	Status       string        `json:"status"`
+	Bid          *models.Money `json:"bid,omitempty"`
+}
+
+var keywordMatchColumns = []output.Column{
+	{Header: "CAMPAIGN", Field: "CampaignName"},
+	{Header: "AD GROUP", Field: "AdGroupName"},
+	{Header: "KIND", Field: "Kind"},
+	{Header: "ID", Field: "ID"},
+	{Header: "TEXT", Field: "Text"},
+	{Header: "MATCH TYPE", Field: "MatchType"},
+	{Header: "STATUS", Field: "Status"},
+	{Header: "BID", Field: "Bid"},
+}
+
+func runKWGrep(cmd *cobra.Command, args []string) error {
+	term := relindex.Normalize(args[0])
+	if term == "" {
+		return fmt.Errorf("search text is empty")
+	}
+	if len(parseFilters(grepCampaignFilters)) != len(grepCampaignFilters) {
+		return fmt.Errorf("invalid --campaign-filter: expected field<op>value, e.g. \"name~US\"")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	selector := models.NewSelector(maxPageSize, 0)
+	selector.Conditions = parseFilters(grepCampaignFilters)
+	campaigns, err := services.NewCampaignService(client).FindAll(selector)
+	if err != nil {
+		return fmt.Errorf("finding campaigns: %w", err)
+	}
+	ix, err := relindex.Load(profileName)
+	if err != nil {
+		return err
+	}
+
+	matches := []KeywordMatch{}
+	indexed, inCampaigns := 0, 0
+	for _, c := range campaigns {
+		var found []KeywordMatch
+		if ixc, ok := ix.Fresh(c.ID, relindex.DefaultTTL); ok && !grepRefresh {
+			found = grepIndexed(ixc, term)
+			indexed++
+		} else {
+			found, err = grepCampaign(client, c, term)
+			if err != nil {
+				return err
+			}
+		}
+		for i := range found {
+			found[i].CampaignID, found[i].CampaignName = c.ID, c.Name
+		}
+		if len(found) > 0 {
+			inCampaigns++
+		}
+		matches = append(matches, found...)
+	}
+
+	kindOrder := map[string]int{relindex.KindKeyword: 0, relindex.KindAdGroupNegative: 1, relindex.KindCampaignNegative: 2}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.CampaignName != b.CampaignName {
+			return a.CampaignName < b.CampaignName
+		}
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.AdGroupName != b.AdGroupName {
+			return a.AdGroupName < b.AdGroupName
+		}
+		return a.Text < b.Text
+	})
+
+	output.Print(getFormat(), matches, keywordMatchColumns)
+	summary := fmt.Sprintf("%d match(es) in %d of %d campaign(s)", len(matches), inCampaigns, len(campaigns))
+	if indexed > 0 {
+		summary += fmt.Sprintf("; %d searched in the local index (--refresh to refetch)", indexed)
+	}
+	fmt.Fprintln(os.Stderr, summary+".")
+	return nil
+}
+
+// grepMatches reports whether keyword text matches the normalized search term.
+func grepMatches(text, term string) bool {
+	text = relindex.Normalize(text)
+	if grepExact {
+		return text == term
+	}
+	return strings.Contains(text, term)
+}
+
+// grepIndexed searches an indexed campaign.
+func grepIndexed(c *relindex.Campaign, term string) []KeywordMatch {
+	var out []KeywordMatch
+	add := func(n relindex.Node) {
+		if !grepMatches(n.Name, term) {
+			return
+		}
+		m := KeywordMatch{AdGroupID: n.AdGroupID, Kind: n.Kind, ID: n.ID, Text: n.Name, MatchType: n.MatchType, Status: n.Status}
+		if ag, ok := c.AdGroup(n.AdGroupID); ok {
+			m.AdGroupName = ag.Name
+		}
+		if n.Bid != "" {
+			m.Bid = &models.Money{Amount: n.Bid, Currency: n.Currency}
+		}
+		out = append(out, m)
+	}
+	for _, k := range c.Keywords {
+		add(k)
+	}
+	if !grepNoNegatives {
+		for _, n := range c.Negatives {
+			add(n)
+		}
+	}
+	return out
+}
+
+// grepCampaign searches a campaign with the find endpoints, narrowed on the
+// server to texts containing term.
+func grepCampaign(client *api.Client, c models.Campaign, term string) ([]KeywordMatch, error) {
+	svc := services.NewKeywordService(client)
+	selector := services.KeywordFilter{TextContains: term}.Apply(models.NewSelector(maxPageSize, 0))
+
+	var out []KeywordMatch
+	keywords, err := svc.FindAllInCampaign(c.ID, selector)
+	if err != nil {
+		return nil, fmt.Errorf("searching keywords of campaign %d: %w", c.ID, err)
+	}
+	for _, k := range keywords {
+		if !k.Deleted && grepMatches(k.Text, term) {
+			out = append(out, KeywordMatch{AdGroupID: k.AdGroupID, Kind: relindex.KindKeyword, ID: k.ID, Text: k.Text,
+				MatchType: string(k.MatchType), Status: string(k.Status), Bid: k.BidAmount})
+		}
+	}
+
+	if !grepNoNegatives {
+		negatives, err := svc.FindAllCampaignNegativeKeywords(c.ID, selector)
+		if err != nil {
+			return nil, fmt.Errorf("searching negative keywords of campaign %d: %w", c.ID, err)
+		}
+		adGroupNegatives, err := svc.FindAllAdGroupNegativeKeywordsInCampaign(c.ID, selector)
+		if err != nil {
+			return nil, fmt.Errorf("searching ad group negative keywords of campaign %d: %w", c.ID, err)
+		}
+		add := func(kind string, negatives []models.NegativeKeyword) {
+			for _, n := range negatives {
+				if !n.Deleted && grepMatches(n.Text, term) {
+					out = append(out, KeywordMatch{AdGroupID: n.AdGroupID, Kind: kind, ID: n.ID, Text: n.Text,
+						MatchType: string(n.MatchType), Status: string(n.Status)})
+				}
+			}
+		}
+		add(relindex.KindCampaignNegative, negatives)
+		add(relindex.KindAdGroupNegative, adGroupNegatives)
+	}
+
+	if err := nameAdGroups(client, c.ID, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// nameAdGroups fills in the ad group names of matches, fetching the
+// campaign's ad groups only if a match is in one.
+func nameAdGroups(client *api.Client, campaignID int64, matches []KeywordMatch) error {
+	need := false
+	for _, m := range matches {
+		if m.AdGroupID != 0 {
+			need = true
+		}
+	}
+	if !need {
+		return nil
+	}
+	adGroups, err := services.NewAdGroupService(client).FindAll(campaignID, models.NewSelector(maxPageSize, 0))
+	if err != nil {
+		return fmt.Errorf("listing ad groups of campaign %d: %w", campaignID, err)
+	}
+	names := map[int64]string{}
+	for _, ag := range adGroups {
+		names[ag.ID] = ag.Name
+	}
+	for i := range matches {
+		matches[i].AdGroupName = names[matches[i].AdGroupID]
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/checkpoint"
+	"github.com/trebuhs/asa-cli/internal/executor"
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var kwImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Bulk-create keywords from a CSV file",
+	Long: `Create targeting keywords from a CSV file with columns text, matchType, bid
+(matchType and bid are optional; a header row is allowed). Keywords are sent
+in concurrent chunks of up to --chunk-size, backing off when rate limited, and
+each created keyword is recorded in a checkpoint file.
+
+Before anything is created, each row is normalized (lowercased, whitespace
+collapsed) and checked: rows repeating an earlier row, rows matching a live
+keyword in the ad group with the same match type, and rows failing the length
+or character rules are skipped. The resulting report is printed to stderr;
+use --dry-run to see the full per-row report without importing.
+
+If the import stops part-way (a crash, network error, or rate limiting),
+re-run the same command with --resume to continue with the keywords not yet
+created instead of starting over.
+
+When the API rejects some keywords of a chunk and creates the rest, the import
+carries on and ends with a table of every keyword's result and the reason for
+each failure. Fix the failed rows and import the file again; keywords that
+now exist are skipped.`,
+	RunE: runKWImport,
+}
+
+var (
+	kwImportFile      string
+	kwImportChunkSize int
+	kwImportResume    bool
+	kwImportDryRun    bool
+)
+
+func init() {
+	kwImportCmd.Flags().Int64Var(&kwCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	kwImportCmd.Flags().Int64Var(&kwAdGroupID, "adgroup-id", 0, "Ad group ID (required)")
+	kwImportCmd.Flags().StringVarP(&kwImportFile, "file", "f", "", "CSV file to import (required)")
+	enumVar(kwImportCmd.Flags(), &kwMatchType, "match-type", models.MatchBroad, models.ParseMatchType, "Match type for rows without one")
+	kwImportCmd.Flags().StringVar(&kwBid, "bid", "", "Bid for rows without one")
+	kwImportCmd.Flags().IntVar(&kwImportChunkSize, "chunk-size", 100, "Most keywords per API request")
+	addConcurrencyFlag(kwImportCmd)
+	kwImportCmd.Flags().BoolVar(&kwImportResume, "resume", false, "Continue an interrupted import from its checkpoint")
+	kwImportCmd.Flags().BoolVar(&kwImportDryRun, "dry-run", false, "Print the import report without creating keywords")
+	kwImportCmd.MarkFlagRequired("campaign-id")
+	kwImportCmd.MarkFlagRequired("adgroup-id")
+	kwImportCmd.MarkFlagRequired("file")
+
+	keywordsCmd.AddCommand(kwImportCmd)
+}
+
+func runKWImport(cmd *cobra.Command, args []string) error {
+	if kwImportChunkSize <= 0 {
+		return fmt.Errorf("--chunk-size must be positive")
+	}
+
+	data, err := os.ReadFile(kwImportFile)
+	if err != nil {
+		return fmt.Errorf("reading import file: %w", err)
+	}
+	rows, err := parseKeywordCSV(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no keywords in %s", kwImportFile)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	currency, err := resolveOrgCurrency(client)
+	if err != nil {
+		return err
+	}
+
+	svc := services.NewKeywordService(client)
+	live, err := svc.FindAll(kwCampaignID, kwAdGroupID, models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("listing existing keywords: %w", err)
+	}
+	existing := map[string]bool{}
+	for _, kw := range live {
+		if !kw.Deleted {
+			existing[kwplan.Key(kw.Text, string(kw.MatchType))] = true
+		}
+	}
+
+	planned := kwplan.Plan(rows, existing)
+	for i, p := range planned {
+		if p.Status == kwplan.StatusAdd && p.Bid != "" {
+			if err := checkBidLimit(p.Bid); err != nil {
+				planned[i].Status, planned[i].Reason = kwplan.StatusInvalid, err.Error()
+			}
+		}
+	}
+
+	if kwImportDryRun {
+		output.Print(getFormat(), planned, []output.Column{
+			{Header: "LINE", Field: "Line"},
+			{Header: "TEXT", Field: "Text"},
+			{Header: "MATCH TYPE", Field: "MatchType"},
+			{Header: "BID", Field: "Bid"},
+			{Header: "STATUS", Field: "Status"},
+			{Header: "REASON", Field: "Reason"},
+		})
+		printImportSummary(planned)
+		return nil
+	}
+	printImportSummary(planned)
+
+	var keywords []models.Keyword
+	for _, p := range planned {
+		if p.Status != kwplan.StatusAdd {
+			fmt.Fprintf(os.Stderr, "  line %d: %q %s — %s: %s\n", p.Line, p.Text, p.MatchType, p.Status, p.Reason)
+			continue
+		}
+		kw := models.Keyword{Text: p.Text, MatchType: models.MatchType(p.MatchType)}
+		if p.Bid != "" {
+			kw.BidAmount = &models.Money{Amount: p.Bid, Currency: currency}
+		}
+		keywords = append(keywords, kw)
+	}
+	if len(keywords) == 0 {
+		fmt.Fprintln(os.Stderr, "Nothing to import.")
+		return nil
+	}
+
+	opKey := checkpoint.Key(profileName, globalOrgID, strconv.FormatInt(kwCampaignID, 10), strconv.FormatInt(kwAdGroupID, 10), string(data))
+	cp, err := checkpoint.Open("keywords-import", opKey, kwImportResume)
+	if err != nil {
+		return err
+	}
+
+	// Keywords from an earlier, interrupted run come from the checkpoint;
+	// the rest are created in batches.
+	outcomes := make([]services.BulkResult[models.Keyword], len(keywords))
+	var pending []int
+	for i, kw := range keywords {
+		item := keywordItemKey(kw)
+		if !cp.IsDone(item) {
+			pending = append(pending, i)
+			continue
+		}
+		if err := cp.Result(item, &outcomes[i].Item); err != nil {
+			return err
+		}
+	}
+
+	exec := newExecutor("Importing keywords", kwImportChunkSize, true)
+	executor.Batches(exec, executor.Write, pending, func(batch []int) error {
+		chunk := make([]models.Keyword, len(batch))
+		for i, k := range batch {
+			chunk[i] = keywords[k]
+		}
+		chunkResults, err := svc.CreateEach(kwCampaignID, kwAdGroupID, chunk)
+		if err != nil {
+			return err
+		}
+		created := map[string]interface{}{}
+		for _, r := range chunkResults {
+			outcomes[batch[r.Index]] = r
+			if r.OK() {
+				created[keywordItemKey(chunk[r.Index])] = r.Item
+			}
+		}
+		return cp.MarkDoneMany(created)
+	}, nil)
+	stats, err := exec.Wait()
+	printThroughput(stats)
+	if err != nil {
+		imported := 0
+		for _, o := range outcomes {
+			if o.OK() && o.Item.ID != 0 {
+				imported++
+			}
+		}
+		return fmt.Errorf("creating keywords: %w; %d of %d keyword(s) imported, re-run with --resume to continue", err, imported, len(keywords))
+	}
+
+	var all []models.Keyword
+	var results []BulkItemResult
+	failed := 0
+	for i, o := range outcomes {
+		results = append(results, bulkItem(i, o.Item.ID, o.Item.Text, string(o.Item.MatchType), o.Error))
+		if o.OK() {
+			all = append(all, o.Item)
+		} else {
+			failed++
+		}
+	}
+
+	if err := cp.Remove(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return printBulkItems(results, "keyword(s)")
+	}
+	output.Print(getFormat(), all, keywordColumns)
+	return nil
+}
+
+// parseKeywordCSV reads text,matchType,bid rows, filling blanks from
+// --match-type and --bid. A first row starting with "text" is a header.
+// Values are checked later by the import plan.
+func parseKeywordCSV(r io.Reader) ([]kwplan.Candidate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []kwplan.Candidate
+	for line := 1; ; line++ {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing import file: %w", err)
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(rec[0]), "text") {
+			continue
+		}
+
+		row := kwplan.Candidate{Line: line, Text: strings.TrimSpace(rec[0]), MatchType: string(kwMatchType), Bid: kwBid}
+		if row.Text == "" {
+			continue
+		}
+		if len(rec) > 1 && strings.TrimSpace(rec[1]) != "" {
+			row.MatchType = strings.TrimSpace(rec[1])
+		}
+		if len(rec) > 2 && strings.TrimSpace(rec[2]) != "" {
+			row.Bid = strings.TrimSpace(rec[2])
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// printImportSummary prints the counts of an import plan to stderr.
+func printImportSummary(planned []kwplan.Planned) {
+	c := kwplan.Count(planned)
+	fmt.Fprintf(os.Stderr, "Import report: %d to add, %d duplicate, %d already in ad group, %d invalid\n",
+		c[kwplan.StatusAdd], c[kwplan.StatusDuplicate], c[kwplan.StatusExisting], c[kwplan.StatusInvalid])
+}
+
+// keywordItemKey is the idempotency key of a keyword: the same keyword always
+// produces the same key, so a resumed run recognizes keywords it already
+// created however the batches were sized.
+func keywordItemKey(kw models.Keyword) string {
+	bid := ""
+	if kw.BidAmount != nil {
+		bid = kw.BidAmount.Amount
+	}
+	return checkpoint.Key(strings.ToLower(kw.Text) + "|" + string(kw.MatchType) + "|" + bid)
+}
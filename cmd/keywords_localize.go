@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/executor"
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+	"github.com/trebuhs/asa-cli/internal/localize"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var kwLocalizeCmd = &cobra.Command{
+	Use:   "localize",
+	Short: "Create translated keyword sets for each storefront of a campaign",
+	Long: `Create the keywords of a base CSV file (text, matchType, bid, as for import)
+in every language searched in the campaign's storefronts, from a YAML map of
+translations:
+
+  keywords:
+    photo editor:
+      de: [fotobearbeitung, foto editor]
+      fr: éditeur photo
+      es-MX: editor de fotos   # only in MX, instead of "es"
+  storefronts:                 # optional, replaces the built-in languages
+    CH: [de, fr]
+
+Each storefront gets the base keyword where --base-language is searched and
+its translations into the storefront's other languages; keywords without a
+translation for a language are reported. Translations keep the base row's
+match type and bid.
+
+With --per-country-adgroups, each storefront gets its own ad group, a copy of
+the --adgroup-id ad group's bid, CPA goal, and targeting limited to the
+country, named by --adgroup-name. An ad group of that name is reused, so the
+command can be re-run as translations are added. Ads are not copied; add
+them, or create the ad groups with adgroups clone first. Without it, every
+variant goes into the --adgroup-id ad group.
+
+Keywords already in an ad group, and repeats, are skipped. Use --dry-run to
+see the plan without creating anything.
+
+Example:
+  asa-cli keywords localize --campaign-id 123 --adgroup-id 456 \
+    --file base_keywords.csv --map translations.yaml --per-country-adgroups --dry-run`,
+	RunE: runKWLocalize,
+}
+
+var (
+	kwLocalizeFile       string
+	kwLocalizeMap        string
+	kwLocalizeCountries  []string
+	kwLocalizeBaseLang   string
+	kwLocalizePerCountry bool
+	kwLocalizeAGName     string
+	kwLocalizeDryRun     bool
+)
+
+func init() {
+	kwLocalizeCmd.Flags().Int64Var(&kwCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	kwLocalizeCmd.Flags().Int64Var(&kwAdGroupID, "adgroup-id", 0, "Ad group to add to, or to copy with --per-country-adgroups (required)")
+	kwLocalizeCmd.Flags().StringVarP(&kwLocalizeFile, "file", "f", "", "CSV file of base keywords (required)")
+	kwLocalizeCmd.Flags().StringVar(&kwLocalizeMap, "map", "", "YAML file of translations (required)")
+	kwLocalizeCmd.Flags().StringSliceVar(&kwLocalizeCountries, "countries", nil, "Storefronts to localize for (default: the campaign's)")
+	kwLocalizeCmd.Flags().StringVar(&kwLocalizeBaseLang, "base-language", "en", "Language of the base keywords")
+	kwLocalizeCmd.Flags().BoolVar(&kwLocalizePerCountry, "per-country-adgroups", false, "Put each storefront's keywords in its own ad group")
+	kwLocalizeCmd.Flags().StringVar(&kwLocalizeAGName, "adgroup-name", "{adgroup} - {country}", "Name of per-country ad groups; {adgroup} and {country} are replaced")
+	enumVar(kwLocalizeCmd.Flags(), &kwMatchType, "match-type", models.MatchBroad, models.ParseMatchType, "Match type for rows without one")
+	kwLocalizeCmd.Flags().StringVar(&kwBid, "bid", "", "Bid for rows without one")
+	addConcurrencyFlag(kwLocalizeCmd)
+	kwLocalizeCmd.Flags().BoolVar(&kwLocalizeDryRun, "dry-run", false, "Print the plan without creating ad groups or keywords")
+	kwLocalizeCmd.MarkFlagRequired("campaign-id")
+	kwLocalizeCmd.MarkFlagRequired("adgroup-id")
+	kwLocalizeCmd.MarkFlagRequired("file")
+	kwLocalizeCmd.MarkFlagRequired("map")
+
+	keywordsCmd.AddCommand(kwLocalizeCmd)
+}
+
+// LocalizedKeyword is a planned keyword variant for a storefront.
+type LocalizedKeyword struct {
+	Country   string `json:"country"`
+	AdGroup   string `json:"adGroup"`
+	AdGroupID int64  `json:"adGroupId,omitempty"`
+	Language  string `json:"language"`
+	kwplan.Planned
+}
+
+// localizeTarget is an ad group and the storefronts whose keywords go in it.
+type localizeTarget struct {
+	name      string
+	id        int64
+	countries []string
+	keywords  []models.Keyword
+	rows      []int
+}
+
+func runKWLocalize(cmd *cobra.Command, args []string) error {
+	if !strings.Contains(kwLocalizeAGName, "{country}") && kwLocalizePerCountry {
+		return fmt.Errorf("--adgroup-name must contain {country}")
+	}
+	data, err := os.ReadFile(kwLocalizeFile)
+	if err != nil {
+		return fmt.Errorf("reading keyword file: %w", err)
+	}
+	base, err := parseKeywordCSV(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if len(base) == 0 {
+		return fmt.Errorf("no keywords in %s", kwLocalizeFile)
+	}
+	tr, err := localize.Load(kwLocalizeMap)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	currency, err := resolveOrgCurrency(client)
+	if err != nil {
+		return err
+	}
+	campaign, err := services.NewCampaignService(client).Get(kwCampaignID)
+	if err != nil {
+		return fmt.Errorf("getting campaign: %w", err)
+	}
+	countries, err := localizeCountries(campaign.CountriesOrRegions, tr)
+	if err != nil {
+		return err
+	}
+	agSvc := services.NewAdGroupService(client)
+	kwSvc := services.NewKeywordService(client)
+	template, err := agSvc.Get(kwCampaignID, kwAdGroupID)
+	if err != nil {
+		return fmt.Errorf("getting ad group: %w", err)
+	}
+
+	// One target per storefront, or the one ad group for all of them.
+	var targets []*localizeTarget
+	if kwLocalizePerCountry {
+		existing, err := agSvc.FindAll(kwCampaignID, models.NewSelector(1000, 0))
+		if err != nil {
+			return fmt.Errorf("listing ad groups: %w", err)
+		}
+		byName := map[string]int64{}
+		for _, ag := range existing {
+			byName[strings.ToLower(ag.Name)] = ag.ID
+		}
+		for _, c := range countries {
+			name := strings.NewReplacer("{adgroup}", template.Name, "{country}", c).Replace(kwLocalizeAGName)
+			targets = append(targets, &localizeTarget{name: name, id: byName[strings.ToLower(name)], countries: []string{c}})
+		}
+	} else {
+		targets = []*localizeTarget{{name: template.Name, id: template.ID, countries: countries}}
+	}
+
+	var rows []LocalizedKeyword
+	missing := map[string]int{}
+	for _, t := range targets {
+		live := map[string]bool{}
+		if t.id != 0 {
+			found, err := kwSvc.FindAll(kwCampaignID, t.id, models.NewSelector(1000, 0))
+			if err != nil {
+				return fmt.Errorf("listing keywords of ad group %d: %w", t.id, err)
+			}
+			for _, kw := range found {
+				if !kw.Deleted {
+					live[kwplan.Key(kw.Text, string(kw.MatchType))] = true
+				}
+			}
+		}
+
+		var candidates []kwplan.Candidate
+		var meta []LocalizedKeyword
+		for _, c := range t.countries {
+			for _, b := range base {
+				variants, langs := tr.Variants(b.Text, c, kwLocalizeBaseLang)
+				for _, l := range langs {
+					missing[c+" "+l]++
+				}
+				for _, v := range variants {
+					candidates = append(candidates, kwplan.Candidate{Line: b.Line, Text: v.Text, MatchType: b.MatchType, Bid: b.Bid})
+					meta = append(meta, LocalizedKeyword{Country: c, AdGroup: t.name, AdGroupID: t.id, Language: v.Language})
+				}
+			}
+		}
+		for i, p := range kwplan.Plan(candidates, live) {
+			if p.Status == kwplan.StatusAdd && p.Bid != "" {
+				if err := checkBidLimit(p.Bid); err != nil {
+					p.Status, p.Reason = kwplan.StatusInvalid, err.Error()
+				}
+			}
+			row := meta[i]
+			row.Planned = p
+			if p.Status == kwplan.StatusAdd {
+				kw := models.Keyword{Text: p.Text, MatchType: models.MatchType(p.MatchType)}
+				if p.Bid != "" {
+					kw.BidAmount = &models.Money{Amount: p.Bid, Currency: currency}
+				}
+				t.keywords = append(t.keywords, kw)
+				t.rows = append(t.rows, len(rows))
+			}
+			rows = append(rows, row)
+		}
+	}
+	warnMissingTranslations(missing, len(base))
+
+	columns := []output.Column{
+		{Header: "COUNTRY", Field: "Country"},
+		{Header: "AD GROUP", Field: "AdGroup"},
+		{Header: "LANGUAGE", Field: "Language"},
+		{Header: "TEXT", Field: "Text"},
+		{Header: "MATCH TYPE", Field: "MatchType"},
+		{Header: "BID", Field: "Bid"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "REASON", Field: "Reason"},
+	}
+	if kwLocalizeDryRun {
+		output.Print(getFormat(), rows, columns)
+		printLocalizeSummary(rows, targets)
+		return nil
+	}
+	printLocalizeSummary(rows, targets)
+
+	for _, t := range targets {
+		if t.id == 0 && len(t.keywords) > 0 {
+			if err := checkAdGroupName(t.name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range targets {
+		if len(t.keywords) == 0 {
+			continue
+		}
+		if t.id == 0 {
+			created, err := agSvc.Create(kwCampaignID, localizedAdGroup(template, t.name, t.countries[0]))
+			if err != nil {
+				return fmt.Errorf("creating ad group %q: %w", t.name, err)
+			}
+			t.id = created.ID
+			fmt.Fprintf(os.Stderr, "Created ad group %q (%d).\n", t.name, t.id)
+		}
+
+		exec := newExecutor(fmt.Sprintf("Adding keywords to %s", t.name), 100, true)
+		indexes := make([]int, len(t.keywords))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		executor.Batches(exec, executor.Write, indexes, func(batch []int) error {
+			chunk := make([]models.Keyword, len(batch))
+			for i, k := range batch {
+				chunk[i] = t.keywords[k]
+			}
+			results, err := kwSvc.CreateEach(kwCampaignID, t.id, chunk)
+			if err != nil {
+				return err
+			}
+			for _, r := range results {
+				row := &rows[t.rows[batch[r.Index]]]
+				row.AdGroupID = t.id
+				row.Status, row.Reason = "created", ""
+				if !r.OK() {
+					row.Status, row.Reason = "failed", r.Error
+				}
+			}
+			return nil
+		}, nil)
+		stats, err := exec.Wait()
+		printThroughput(stats)
+		if err != nil {
+			return fmt.Errorf("creating keywords in ad group %q: %w", t.name, err)
+		}
+	}
+
+	output.Print(getFormat(), rows, columns)
+	failed := 0
+	for _, r := range rows {
+		if r.Status == "failed" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d keyword(s) failed", failed)
+	}
+	return nil
+}
+
+// localizeCountries returns the storefronts to localize for: --countries,
+// which must be served by the campaign, or the campaign's. Each needs known
+// languages.
+func localizeCountries(served []string, tr *localize.Map) ([]string, error) {
+	countries := served
+	if len(kwLocalizeCountries) > 0 {
+		countries = nil
+		for _, c := range kwLocalizeCountries {
+			c = strings.ToUpper(strings.TrimSpace(c))
+			if !containsString(served, c) {
+				return nil, fmt.Errorf("campaign %d doesn't serve %s (it serves %s)", kwCampaignID, c, strings.Join(served, ", "))
+			}
+			countries = append(countries, c)
+		}
+	}
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("campaign %d has no storefronts", kwCampaignID)
+	}
+	for _, c := range countries {
+		if len(tr.Languages(c)) == 0 {
+			return nil, fmt.Errorf("no languages known for storefront %s; list them under storefronts: in %s", c, kwLocalizeMap)
+		}
+	}
+	return countries, nil
+}
+
+// localizedAdGroup copies template's bidding and targeting into a new ad
+// group serving only country.
+func localizedAdGroup(template *models.AdGroup, name, country string) *models.AdGroup {
+	targeting := models.TargetingDimensions{}
+	if template.TargetingDimensions != nil {
+		targeting = *template.TargetingDimensions
+	}
+	targeting.Country = &models.TargetingDimension{Included: []interface{}{country}}
+	// Admin areas and localities belong to the template's countries.
+	targeting.AdminArea, targeting.Locality = nil, nil
+	return &models.AdGroup{
+		Name:                   name,
+		Status:                 template.Status,
+		DefaultBidAmount:       template.DefaultBidAmount,
+		CpaGoal:                template.CpaGoal,
+		AutomatedKeywordsOptIn: template.AutomatedKeywordsOptIn,
+		PricingModel:           template.PricingModel,
+		TargetingDimensions:    &targeting,
+	}
+}
+
+// warnMissingTranslations reports, per storefront language, how many base
+// keywords have no translation.
+func warnMissingTranslations(missing map[string]int, total int) {
+	keys := make([]string, 0, len(missing))
+	for k := range missing {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		country, lang, _ := strings.Cut(k, " ")
+		warnf("%d of %d keyword(s) have no %s translation for %s", missing[k], total, lang, country)
+	}
+}
+
+// printLocalizeSummary prints the counts of a localize plan to stderr.
+func printLocalizeSummary(rows []LocalizedKeyword, targets []*localizeTarget) {
+	planned := make([]kwplan.Planned, len(rows))
+	for i, r := range rows {
+		planned[i] = r.Planned
+	}
+	newGroups := 0
+	for _, t := range targets {
+		if t.id == 0 && len(t.keywords) > 0 {
+			newGroups++
+		}
+	}
+	c := kwplan.Count(planned)
+	fmt.Fprintf(os.Stderr, "Localize plan: %d ad group(s) to create, %d keyword(s) to add, %d duplicate, %d already in ad group, %d invalid\n",
+		newGroups, c[kwplan.StatusAdd], c[kwplan.StatusDuplicate], c[kwplan.StatusExisting], c[kwplan.StatusInvalid])
+}
@@ -0,0 +1,546 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/neglists"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var negListsCmd = &cobra.Command{
+	Use:   "neg-lists",
+	Short: "Reusable negative keyword lists synced to campaigns",
+	Long: `A negative keyword list is a named set of terms kept locally per profile,
+optionally scoped to storefronts with --countries. Applying it adds its terms
+as campaign negative keywords to every matching campaign.
+
+Each apply is recorded, so applying again syncs the list: terms added to it
+are created and terms dropped from it are deleted from the campaigns it was
+applied to. Negative keywords the list didn't create are never deleted.
+status reports campaigns that drifted from their lists.`,
+}
+
+var negListsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a list from a file of terms (one per line)",
+	Example: `  asa-cli neg-lists create brand-protect --file terms.txt
+  asa-cli neg-lists create free-seekers --file free.txt --match-type BROAD --countries US,GB
+  asa-cli neg-lists create brand-protect --file terms.txt --replace`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNegListsCreate,
+}
+
+var negListsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List negative keyword lists",
+	RunE:  runNegListsList,
+}
+
+var negListsShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a list's terms and the campaigns it was applied to",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNegListsShow,
+}
+
+var negListsApplyCmd = &cobra.Command{
+	Use:   "apply <name>",
+	Short: "Sync a list's terms to campaigns as campaign negative keywords",
+	Long: `Sync a list to campaigns: create its terms missing from each campaign, and
+delete the terms dropped from the list since it was last applied there.
+Campaigns outside the list's --countries are skipped.
+
+Select campaigns with --campaigns=IDS, or --campaigns alone for every
+campaign, narrowed with --filter.`,
+	Example: `  asa-cli neg-lists apply brand-protect --campaigns --filter name~Discovery
+  asa-cli neg-lists apply brand-protect --campaigns=123,456 --dry-run`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 && nlCampaigns == "all" {
+			return fmt.Errorf("pass campaign IDs as --campaigns=%s", strings.Join(args[1:], ","))
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runNegListsApply,
+}
+
+var negListsStatusCmd = &cobra.Command{
+	Use:   "status [name...]",
+	Short: "Report campaigns that drifted from the lists applied to them",
+	RunE:  runNegListsStatus,
+}
+
+var negListsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a list (campaign negative keywords are not changed)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNegListsDelete,
+}
+
+var (
+	nlFile      string
+	nlMatchType models.MatchType
+	nlCountries string
+	nlReplace   bool
+	nlCampaigns string
+	nlFilters   []string
+	nlDryRun    bool
+)
+
+func init() {
+	negListsCreateCmd.Flags().StringVarP(&nlFile, "file", "f", "", `File of terms, one per line ("-" for stdin; # starts a comment)`)
+	enumVar(negListsCreateCmd.Flags(), &nlMatchType, "match-type", models.MatchExact, models.ParseMatchType, "Match type: BROAD or EXACT")
+	negListsCreateCmd.Flags().StringVar(&nlCountries, "countries", "", "Only apply to campaigns serving these storefronts (e.g. US,GB)")
+	negListsCreateCmd.Flags().BoolVar(&nlReplace, "replace", false, "Replace an existing list's terms; the next apply syncs the change")
+	negListsCreateCmd.MarkFlagRequired("file")
+
+	negListsApplyCmd.Flags().StringVar(&nlCampaigns, "campaigns", "", "Campaign IDs as --campaigns=1,2, or --campaigns alone for every campaign matching --filter")
+	negListsApplyCmd.Flags().Lookup("campaigns").NoOptDefVal = "all"
+	negListsApplyCmd.Flags().StringSliceVar(&nlFilters, "filter", nil, `Campaign filter conditions (e.g. "name~Discovery")`)
+	negListsApplyCmd.Flags().BoolVar(&nlDryRun, "dry-run", false, "Show what would change without changing it")
+	negListsApplyCmd.MarkFlagRequired("campaigns")
+
+	markMutating(func() bool { return !nlDryRun }, negListsApplyCmd)
+
+	negListsCmd.AddCommand(negListsCreateCmd, negListsListCmd, negListsShowCmd, negListsApplyCmd, negListsStatusCmd, negListsDeleteCmd)
+	rootCmd.AddCommand(negListsCmd)
+}
+
+type negListRow struct {
+	Name      string `json:"name"`
+	MatchType string `json:"matchType"`
+	Countries string `json:"countries"`
+	Terms     int    `json:"terms"`
+	Campaigns int    `json:"campaigns"`
+	Updated   string `json:"updated"`
+}
+
+// NegListSync is the result of syncing a list to one campaign.
+type NegListSync struct {
+	CampaignID   int64    `json:"campaignId"`
+	CampaignName string   `json:"campaignName"`
+	Added        []string `json:"added"`
+	Removed      []string `json:"removed"`
+	Unchanged    int      `json:"unchanged"`
+	Result       string   `json:"result"` // planned, synced, skipped, failed
+	Error        string   `json:"error,omitempty"`
+
+	AddedCount   int    `json:"-"`
+	RemovedCount int    `json:"-"`
+	ResultText   string `json:"-"`
+}
+
+// NegListDrift is how one campaign differs from a list applied to it.
+type NegListDrift struct {
+	List         string    `json:"list"`
+	CampaignID   int64     `json:"campaignId"`
+	CampaignName string    `json:"campaignName"`
+	AppliedAt    time.Time `json:"appliedAt"`
+	neglists.Drift
+	Status string `json:"status"` // in sync, drifted, campaign not found
+
+	Summary string `json:"-"`
+}
+
+func runNegListsCreate(cmd *cobra.Command, args []string) error {
+	store, err := neglists.Load(profileName)
+	if err != nil {
+		return err
+	}
+	existing, err := store.Get(args[0])
+	if err == nil && !nlReplace {
+		return fmt.Errorf("list %q already exists; pass --replace to replace its terms", existing.Name)
+	}
+
+	var in io.Reader = os.Stdin
+	if nlFile != "-" {
+		f, err := os.Open(nlFile)
+		if err != nil {
+			return fmt.Errorf("reading terms: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	terms, err := neglists.ReadTerms(in)
+	if err != nil {
+		return err
+	}
+	if len(terms) == 0 {
+		return fmt.Errorf("%s holds no terms", nlFile)
+	}
+
+	l := neglists.List{Name: args[0], MatchType: string(nlMatchType), Terms: terms, Updated: time.Now().UTC(), Applied: existing.Applied}
+	if nlCountries != "" {
+		for _, c := range strings.Split(nlCountries, ",") {
+			l.Countries = append(l.Countries, strings.ToUpper(strings.TrimSpace(c)))
+		}
+	}
+	if existing.Name != "" && !strings.EqualFold(existing.MatchType, l.MatchType) && len(existing.Applied) > 0 {
+		return fmt.Errorf("list %q was applied with match type %s; create a new list to change it", existing.Name, existing.MatchType)
+	}
+	if err := store.Put(l); err != nil {
+		return err
+	}
+
+	verb := "Created"
+	if existing.Name != "" {
+		verb = "Replaced"
+	}
+	fmt.Printf("%s list %s: %d %s term(s)\n", verb, neglists.Normalize(l.Name), len(terms), l.MatchType)
+	if len(l.Applied) > 0 {
+		fmt.Fprintf(os.Stderr, "Applied to %d campaign(s); run neg-lists apply to sync them.\n", len(l.Applied))
+	}
+	return nil
+}
+
+func runNegListsList(cmd *cobra.Command, args []string) error {
+	store, err := neglists.Load(profileName)
+	if err != nil {
+		return err
+	}
+	rows := []negListRow{}
+	for _, l := range store.All() {
+		rows = append(rows, negListRow{
+			Name:      l.Name,
+			MatchType: l.MatchType,
+			Countries: strings.Join(l.Countries, ","),
+			Terms:     len(l.Terms),
+			Campaigns: len(l.Applied),
+			Updated:   l.Updated.Local().Format("2006-01-02 15:04"),
+		})
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "NAME", Field: "Name"},
+		{Header: "MATCH TYPE", Field: "MatchType"},
+		{Header: "COUNTRIES", Field: "Countries"},
+		{Header: "TERMS", Field: "Terms"},
+		{Header: "CAMPAIGNS", Field: "Campaigns"},
+		{Header: "UPDATED", Field: "Updated"},
+	})
+	return nil
+}
+
+func runNegListsShow(cmd *cobra.Command, args []string) error {
+	store, err := neglists.Load(profileName)
+	if err != nil {
+		return err
+	}
+	l, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, l, nil)
+		return nil
+	}
+
+	type termRow struct{ Term string }
+	rows := make([]termRow, len(l.Terms))
+	for i, t := range l.Terms {
+		rows[i] = termRow{t}
+	}
+	output.Print(getFormat(), rows, []output.Column{{Header: "TERM", Field: "Term"}})
+	if getFormat() == output.FormatTable {
+		scope := "all storefronts"
+		if len(l.Countries) > 0 {
+			scope = strings.Join(l.Countries, ", ")
+		}
+		fmt.Fprintf(os.Stderr, "\n%s: %d %s term(s) for %s, applied to %d campaign(s)\n", l.Name, len(l.Terms), l.MatchType, scope, len(l.Applied))
+	}
+	return nil
+}
+
+func runNegListsDelete(cmd *cobra.Command, args []string) error {
+	store, err := neglists.Load(profileName)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted list %s\n", neglists.Normalize(args[0]))
+	return nil
+}
+
+func runNegListsApply(cmd *cobra.Command, args []string) error {
+	store, err := neglists.Load(profileName)
+	if err != nil {
+		return err
+	}
+	l, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if len(parseFilters(nlFilters)) != len(nlFilters) {
+		return fmt.Errorf("invalid --filter: expected field<op>value, e.g. \"name~Discovery\"")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	selector := models.NewSelector(1000, 0)
+	selector.Conditions = parseFilters(nlFilters)
+	if nlCampaigns != "all" {
+		ids, err := parseIDList(nlCampaigns)
+		if err != nil {
+			return err
+		}
+		values := make([]string, len(ids))
+		for i, id := range ids {
+			values[i] = strconv.FormatInt(id, 10)
+		}
+		selector.Conditions = append(selector.Conditions, models.Condition{Field: "id", Operator: "IN", Values: values})
+	}
+	campaigns, err := services.NewCampaignService(client).FindAll(selector)
+	if err != nil {
+		return fmt.Errorf("finding campaigns: %w", err)
+	}
+	if len(campaigns) == 0 {
+		return fmt.Errorf("no campaigns match")
+	}
+
+	svc := services.NewKeywordService(client)
+	if l.Applied == nil {
+		l.Applied = map[int64]neglists.Application{}
+	}
+	var results []NegListSync
+	failed, skipped := 0, 0
+	for _, c := range campaigns {
+		r := NegListSync{CampaignID: c.ID, CampaignName: c.Name, Added: []string{}, Removed: []string{}}
+		if !l.Covers(c.CountriesOrRegions) {
+			r.Result, r.ResultText = "skipped", "skipped: serves "+strings.Join(c.CountriesOrRegions, ",")
+			results = append(results, r)
+			skipped++
+			continue
+		}
+		live, err := liveNegatives(svc, c.ID, l.MatchType)
+		if err != nil {
+			return fmt.Errorf("listing negative keywords of campaign %d: %w", c.ID, err)
+		}
+		plan := l.Plan(c.ID, termSet(live), store.Kept(l.Name, c.ID, l.MatchType))
+		r.Unchanged = len(plan.Present)
+		if nlDryRun {
+			r.Added, r.Removed = append(r.Added, plan.Add...), append(r.Removed, plan.Remove...)
+			r.Result, r.ResultText = "planned", "planned"
+			results = append(results, r.counted())
+			continue
+		}
+
+		applied, err := syncNegList(svc, c.ID, l.MatchType, plan, live, &r)
+		if applied != nil {
+			l.Applied[c.ID] = neglists.Application{CampaignName: c.Name, Terms: applied, AppliedAt: time.Now().UTC()}
+		}
+		if err != nil {
+			r.Result, r.Error = "failed", err.Error()
+			r.ResultText = "failed: " + err.Error()
+			failed++
+		} else {
+			r.Result, r.ResultText = "synced", "synced"
+		}
+		results = append(results, r.counted())
+	}
+
+	if !nlDryRun {
+		if err := store.Put(l); err != nil {
+			return err
+		}
+	}
+	output.Print(getFormat(), results, []output.Column{
+		{Header: "CAMPAIGN", Field: "CampaignID"},
+		{Header: "NAME", Field: "CampaignName"},
+		{Header: "ADD", Field: "AddedCount"},
+		{Header: "REMOVE", Field: "RemovedCount"},
+		{Header: "UNCHANGED", Field: "Unchanged"},
+		{Header: "RESULT", Field: "ResultText"},
+	})
+
+	added, removed := 0, 0
+	for _, r := range results {
+		added += len(r.Added)
+		removed += len(r.Removed)
+	}
+	summary := fmt.Sprintf("%s to %d campaign(s): %d added, %d removed", l.Name, len(results)-skipped, added, removed)
+	if skipped > 0 {
+		summary += fmt.Sprintf("; skipped %d outside %s", skipped, strings.Join(l.Countries, ","))
+	}
+	if nlDryRun {
+		fmt.Fprintf(os.Stderr, "Dry run: would sync %s.\n", summary)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Synced %s.\n", summary)
+	if failed > 0 {
+		return fmt.Errorf("%d campaign(s) failed to sync", failed)
+	}
+	return nil
+}
+
+func (r NegListSync) counted() NegListSync {
+	r.AddedCount, r.RemovedCount = len(r.Added), len(r.Removed)
+	return r
+}
+
+// syncNegList applies a sync plan to a campaign and records the outcome in r.
+// It returns the list terms the campaign now has, which become the recorded
+// application, or nil if nothing could be changed.
+func syncNegList(svc *services.KeywordService, campaignID int64, matchType string, plan neglists.Plan, live map[string]int64, r *NegListSync) ([]string, error) {
+	terms := append([]string{}, plan.Present...)
+	var errs []string
+	if len(plan.Add) > 0 {
+		keywords := make([]models.NegativeKeyword, len(plan.Add))
+		for i, t := range plan.Add {
+			keywords[i] = models.NegativeKeyword{Text: t, MatchType: models.MatchType(matchType)}
+		}
+		results, err := svc.CreateCampaignNegativeKeywordsEach(campaignID, keywords)
+		if err != nil {
+			return nil, fmt.Errorf("creating negative keywords: %w", err)
+		}
+		for i, res := range results {
+			if res.OK() {
+				r.Added = append(r.Added, plan.Add[i])
+				terms = append(terms, plan.Add[i])
+			} else {
+				errs = append(errs, fmt.Sprintf("%q: %s", plan.Add[i], res.Error))
+			}
+		}
+	}
+	if len(plan.Remove) > 0 {
+		ids := make([]int64, len(plan.Remove))
+		for i, t := range plan.Remove {
+			ids[i] = live[t]
+		}
+		if _, err := svc.DeleteCampaignNegativeKeywordsExisting(campaignID, ids); err != nil {
+			// Keep the terms recorded so the next apply retries the delete.
+			terms = append(terms, plan.Remove...)
+			errs = append(errs, fmt.Sprintf("deleting negative keywords: %v", err))
+		} else {
+			r.Removed = append(r.Removed, plan.Remove...)
+		}
+	}
+	if len(errs) > 0 {
+		return terms, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return terms, nil
+}
+
+// liveNegatives returns a campaign's negative keywords of matchType by
+// normalized text, with their IDs.
+func liveNegatives(svc *services.KeywordService, campaignID int64, matchType string) (map[string]int64, error) {
+	keywords, err := svc.FindAllCampaignNegativeKeywords(campaignID, models.NewSelector(1000, 0))
+	if err != nil {
+		return nil, err
+	}
+	live := map[string]int64{}
+	for _, k := range keywords {
+		if !k.Deleted && strings.EqualFold(string(k.MatchType), matchType) {
+			live[kwplan.Normalize(k.Text)] = k.ID
+		}
+	}
+	return live, nil
+}
+
+func termSet(live map[string]int64) map[string]bool {
+	set := make(map[string]bool, len(live))
+	for t := range live {
+		set[t] = true
+	}
+	return set
+}
+
+func runNegListsStatus(cmd *cobra.Command, args []string) error {
+	store, err := neglists.Load(profileName)
+	if err != nil {
+		return err
+	}
+	var lists []neglists.List
+	if len(args) == 0 {
+		lists = store.All()
+	} else {
+		for _, name := range args {
+			l, err := store.Get(name)
+			if err != nil {
+				return err
+			}
+			lists = append(lists, l)
+		}
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewKeywordService(client)
+	rows := []NegListDrift{}
+	drifted := 0
+	for _, l := range lists {
+		for _, id := range sortedCampaignIDs(l.Applied) {
+			app := l.Applied[id]
+			row := NegListDrift{List: l.Name, CampaignID: id, CampaignName: app.CampaignName, AppliedAt: app.AppliedAt}
+			live, err := liveNegatives(svc, id, l.MatchType)
+			switch {
+			case api.IsNotFound(err):
+				row.Status, row.Summary = "campaign not found", "campaign not found"
+			case err != nil:
+				return fmt.Errorf("listing negative keywords of campaign %d: %w", id, err)
+			default:
+				row.Drift = l.Drift(id, termSet(live))
+				row.Status, row.Summary = "in sync", "in sync"
+				if !row.InSync() {
+					row.Status = "drifted"
+					row.Summary = driftSummary(row.Drift)
+					drifted++
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(os.Stderr, "No list has been applied yet; run neg-lists apply.")
+		return nil
+	}
+
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "LIST", Field: "List"},
+		{Header: "CAMPAIGN", Field: "CampaignID"},
+		{Header: "NAME", Field: "CampaignName"},
+		{Header: "STATUS", Field: "Summary"},
+	})
+	if drifted > 0 {
+		fmt.Fprintf(os.Stderr, "%d campaign(s) drifted; run neg-lists apply to sync them.\n", drifted)
+	}
+	return nil
+}
+
+// driftSummary describes drift in a few words, e.g. "2 missing, 1 pending".
+func driftSummary(d neglists.Drift) string {
+	var parts []string
+	if n := len(d.Missing); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d missing (%s)", n, strings.Join(d.Missing, ", ")))
+	}
+	if n := len(d.Pending); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d not yet applied", n))
+	}
+	if n := len(d.Stale); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d dropped from the list", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedCampaignIDs(applied map[int64]neglists.Application) []int64 {
+	ids := make([]int64, 0, len(applied))
+	for id := range applied {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -73,12 +73,15 @@ var nkAdGroupDeleteCmd = &cobra.Command{
 var (
 	nkCampaignID int64
 	nkAdGroupID  int64
-	nkLimit      int
-	nkOffset     int
+	nkPage       pager
 	nkTexts      []string
-	nkMatchType  string
+	nkMatchType  models.MatchType
 	nkFilters    []string
 	nkSorts      []string
+
+	nkFilterStatus    models.KeywordStatus
+	nkFilterMatchType models.MatchType
+	nkTextContains    string
 )
 
 func init() {
@@ -88,37 +91,48 @@ func init() {
 		cmd.MarkFlagRequired("campaign-id")
 	}
 
-	nkCampaignListCmd.Flags().IntVar(&nkLimit, "limit", 20, "Number of results")
-	nkCampaignListCmd.Flags().IntVar(&nkOffset, "offset", 0, "Results offset")
+	nkPage.register(nkCampaignListCmd)
 
 	nkCampaignCreateCmd.Flags().StringSliceVar(&nkTexts, "text", nil, "Keyword text(s)")
-	nkCampaignCreateCmd.Flags().StringVar(&nkMatchType, "match-type", "EXACT", "Match type: BROAD or EXACT")
+	enumVar(nkCampaignCreateCmd.Flags(), &nkMatchType, "match-type", models.MatchExact, models.ParseMatchType, "Match type: BROAD or EXACT")
 	nkCampaignCreateCmd.MarkFlagRequired("text")
 
 	nkCampaignFindCmd.Flags().StringSliceVar(&nkFilters, "filter", nil, "Filter conditions")
 	nkCampaignFindCmd.Flags().StringSliceVar(&nkSorts, "sort", nil, "Sort order")
-	nkCampaignFindCmd.Flags().IntVar(&nkLimit, "limit", 20, "Number of results")
-	nkCampaignFindCmd.Flags().IntVar(&nkOffset, "offset", 0, "Results offset")
+	nkPage.register(nkCampaignFindCmd)
 
 	// Ad group-level commands
 	for _, cmd := range []*cobra.Command{nkAdGroupListCmd, nkAdGroupCreateCmd, nkAdGroupFindCmd, nkAdGroupDeleteCmd} {
 		cmd.Flags().Int64Var(&nkCampaignID, "campaign-id", 0, "Campaign ID (required)")
 		cmd.Flags().Int64Var(&nkAdGroupID, "adgroup-id", 0, "Ad group ID (required)")
 		cmd.MarkFlagRequired("campaign-id")
-		cmd.MarkFlagRequired("adgroup-id")
+		if cmd != nkAdGroupListCmd && cmd != nkAdGroupFindCmd {
+			cmd.MarkFlagRequired("adgroup-id")
+		}
+	}
+	for _, cmd := range []*cobra.Command{nkAdGroupListCmd, nkAdGroupFindCmd} {
+		cmd.Flags().Lookup("adgroup-id").Usage = "Ad group ID (omit to search all ad groups in the campaign)"
+	}
+
+	// Server-side filters
+	for _, cmd := range []*cobra.Command{nkCampaignListCmd, nkCampaignFindCmd, nkAdGroupListCmd, nkAdGroupFindCmd} {
+		enumVar(cmd.Flags(), &nkFilterStatus, "status", "", models.ParseKeywordStatus, "Only keywords with this status (ACTIVE/PAUSED)")
+		enumVar(cmd.Flags(), &nkFilterMatchType, "match-type", "", models.ParseMatchType, "Only keywords with this match type (BROAD/EXACT)")
+		cmd.Flags().StringVar(&nkTextContains, "text-contains", "", "Only keywords whose text contains this")
 	}
 
-	nkAdGroupListCmd.Flags().IntVar(&nkLimit, "limit", 20, "Number of results")
-	nkAdGroupListCmd.Flags().IntVar(&nkOffset, "offset", 0, "Results offset")
+	nkPage.register(nkAdGroupListCmd)
 
 	nkAdGroupCreateCmd.Flags().StringSliceVar(&nkTexts, "text", nil, "Keyword text(s)")
-	nkAdGroupCreateCmd.Flags().StringVar(&nkMatchType, "match-type", "EXACT", "Match type: BROAD or EXACT")
+	enumVar(nkAdGroupCreateCmd.Flags(), &nkMatchType, "match-type", models.MatchExact, models.ParseMatchType, "Match type: BROAD or EXACT")
 	nkAdGroupCreateCmd.MarkFlagRequired("text")
 
 	nkAdGroupFindCmd.Flags().StringSliceVar(&nkFilters, "filter", nil, "Filter conditions")
 	nkAdGroupFindCmd.Flags().StringSliceVar(&nkSorts, "sort", nil, "Sort order")
-	nkAdGroupFindCmd.Flags().IntVar(&nkLimit, "limit", 20, "Number of results")
-	nkAdGroupFindCmd.Flags().IntVar(&nkOffset, "offset", 0, "Results offset")
+	nkPage.register(nkAdGroupFindCmd)
+
+	addIgnoreMissingFlag(nkCampaignDeleteCmd)
+	addIgnoreMissingFlag(nkAdGroupDeleteCmd)
 
 	negKeywordsCmd.AddCommand(
 		nkCampaignListCmd, nkCampaignCreateCmd, nkCampaignFindCmd, nkCampaignDeleteCmd,
@@ -142,13 +156,24 @@ func runNKCampaignList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	limit, offset, err := nkPage.resolve()
+	if err != nil {
+		return err
+	}
+
 	svc := services.NewKeywordService(client)
-	keywords, _, err := svc.ListCampaignNegativeKeywords(nkCampaignID, nkLimit, nkOffset)
+	var keywords []models.NegativeKeyword
+	var page *models.PageDetail
+	if filter := negKeywordFilter(); filter.IsZero() {
+		keywords, page, err = svc.ListCampaignNegativeKeywords(nkCampaignID, limit, offset)
+	} else {
+		keywords, page, err = svc.FindCampaignNegativeKeywords(nkCampaignID, filter.Apply(models.NewSelector(limit, offset)))
+	}
 	if err != nil {
 		return fmt.Errorf("listing negative keywords: %w", err)
 	}
 
-	output.Print(getFormat(), keywords, negKeywordColumns)
+	nkPage.print(keywords, page, negKeywordColumns)
 	return nil
 }
 
@@ -167,12 +192,15 @@ func runNKCampaignCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewKeywordService(client)
-	created, err := svc.CreateCampaignNegativeKeywords(nkCampaignID, keywords)
+	results, err := svc.CreateCampaignNegativeKeywordsEach(nkCampaignID, keywords)
 	if err != nil {
 		return fmt.Errorf("creating negative keywords: %w", err)
 	}
+	if services.FailedCount(results) > 0 {
+		return printBulkItems(negativeBulkItems(results), "negative keyword(s)")
+	}
 
-	output.Print(getFormat(), created, negKeywordColumns)
+	output.Print(getFormat(), services.Succeeded(results), negKeywordColumns)
 	return nil
 }
 
@@ -182,17 +210,21 @@ func runNKCampaignFind(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	selector := models.NewSelector(nkLimit, nkOffset)
+	selector, err := nkPage.selector()
+	if err != nil {
+		return err
+	}
 	selector.Conditions = parseFilters(nkFilters)
 	selector.OrderBy = parseSorts(nkSorts)
+	selector = negKeywordFilter().Apply(selector)
 
 	svc := services.NewKeywordService(client)
-	keywords, _, err := svc.FindCampaignNegativeKeywords(nkCampaignID, selector)
+	keywords, page, err := svc.FindCampaignNegativeKeywords(nkCampaignID, selector)
 	if err != nil {
 		return fmt.Errorf("finding negative keywords: %w", err)
 	}
 
-	output.Print(getFormat(), keywords, negKeywordColumns)
+	nkPage.print(keywords, page, negKeywordColumns)
 	return nil
 }
 
@@ -208,11 +240,17 @@ func runNKCampaignDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewKeywordService(client)
-	if err := svc.DeleteCampaignNegativeKeywords(nkCampaignID, ids); err != nil {
+	var skipped []int64
+	if ignoreMissing {
+		skipped, err = svc.DeleteCampaignNegativeKeywordsExisting(nkCampaignID, ids)
+	} else {
+		err = svc.DeleteCampaignNegativeKeywords(nkCampaignID, ids)
+	}
+	if err != nil {
 		return fmt.Errorf("deleting negative keywords: %w", err)
 	}
 
-	fmt.Printf("Deleted %d negative keyword(s).\n", len(ids))
+	printDeleted(len(ids), "negative keyword(s)", skipped)
 	return nil
 }
 
@@ -224,13 +262,24 @@ func runNKAdGroupList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	limit, offset, err := nkPage.resolve()
+	if err != nil {
+		return err
+	}
+
 	svc := services.NewKeywordService(client)
-	keywords, _, err := svc.ListAdGroupNegativeKeywords(nkCampaignID, nkAdGroupID, nkLimit, nkOffset)
+	var keywords []models.NegativeKeyword
+	var page *models.PageDetail
+	if filter := negKeywordFilter(); filter.IsZero() && nkAdGroupID != 0 {
+		keywords, page, err = svc.ListAdGroupNegativeKeywords(nkCampaignID, nkAdGroupID, limit, offset)
+	} else {
+		keywords, page, err = findAdGroupNegativeKeywords(svc, filter.Apply(models.NewSelector(limit, offset)))
+	}
 	if err != nil {
 		return fmt.Errorf("listing negative keywords: %w", err)
 	}
 
-	output.Print(getFormat(), keywords, negKeywordColumns)
+	nkPage.print(keywords, page, adGroupNegKeywordColumns())
 	return nil
 }
 
@@ -249,12 +298,15 @@ func runNKAdGroupCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewKeywordService(client)
-	created, err := svc.CreateAdGroupNegativeKeywords(nkCampaignID, nkAdGroupID, keywords)
+	results, err := svc.CreateAdGroupNegativeKeywordsEach(nkCampaignID, nkAdGroupID, keywords)
 	if err != nil {
 		return fmt.Errorf("creating negative keywords: %w", err)
 	}
+	if services.FailedCount(results) > 0 {
+		return printBulkItems(negativeBulkItems(results), "negative keyword(s)")
+	}
 
-	output.Print(getFormat(), created, negKeywordColumns)
+	output.Print(getFormat(), services.Succeeded(results), negKeywordColumns)
 	return nil
 }
 
@@ -264,17 +316,21 @@ func runNKAdGroupFind(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	selector := models.NewSelector(nkLimit, nkOffset)
+	selector, err := nkPage.selector()
+	if err != nil {
+		return err
+	}
 	selector.Conditions = parseFilters(nkFilters)
 	selector.OrderBy = parseSorts(nkSorts)
+	selector = negKeywordFilter().Apply(selector)
 
 	svc := services.NewKeywordService(client)
-	keywords, _, err := svc.FindAdGroupNegativeKeywords(nkCampaignID, nkAdGroupID, selector)
+	keywords, page, err := findAdGroupNegativeKeywords(svc, selector)
 	if err != nil {
 		return fmt.Errorf("finding negative keywords: %w", err)
 	}
 
-	output.Print(getFormat(), keywords, negKeywordColumns)
+	nkPage.print(keywords, page, adGroupNegKeywordColumns())
 	return nil
 }
 
@@ -290,14 +346,41 @@ func runNKAdGroupDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewKeywordService(client)
-	if err := svc.DeleteAdGroupNegativeKeywords(nkCampaignID, nkAdGroupID, ids); err != nil {
+	var skipped []int64
+	if ignoreMissing {
+		skipped, err = svc.DeleteAdGroupNegativeKeywordsExisting(nkCampaignID, nkAdGroupID, ids)
+	} else {
+		err = svc.DeleteAdGroupNegativeKeywords(nkCampaignID, nkAdGroupID, ids)
+	}
+	if err != nil {
 		return fmt.Errorf("deleting negative keywords: %w", err)
 	}
 
-	fmt.Printf("Deleted %d negative keyword(s).\n", len(ids))
+	printDeleted(len(ids), "negative keyword(s)", skipped)
 	return nil
 }
 
+func negKeywordFilter() services.KeywordFilter {
+	return services.KeywordFilter{Status: nkFilterStatus, MatchType: nkFilterMatchType, TextContains: nkTextContains}
+}
+
+// findAdGroupNegativeKeywords searches the ad group, or every ad group in the
+// campaign when none is given.
+func findAdGroupNegativeKeywords(svc *services.KeywordService, selector models.Selector) ([]models.NegativeKeyword, *models.PageDetail, error) {
+	if nkAdGroupID == 0 {
+		return svc.FindAdGroupNegativeKeywordsInCampaign(nkCampaignID, selector)
+	}
+	return svc.FindAdGroupNegativeKeywords(nkCampaignID, nkAdGroupID, selector)
+}
+
+// adGroupNegKeywordColumns adds the ad group to campaign-wide results.
+func adGroupNegKeywordColumns() []output.Column {
+	if nkAdGroupID != 0 {
+		return negKeywordColumns
+	}
+	return append([]output.Column{{Header: "AD GROUP", Field: "AdGroupID", Width: 12}}, negKeywordColumns...)
+}
+
 func parseIDList(s string) ([]int64, error) {
 	var ids []int64
 	for _, part := range strings.Split(s, ",") {
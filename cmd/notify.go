@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/notify"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Deliver generated reports",
+}
+
+var notifyEmailCmd = &cobra.Command{
+	Use:   "email <job> <file>...",
+	Short: "Email files to a configured job's recipients",
+	Long: `Email one or more files as attachments to the recipients of a job configured
+under notifications.email.jobs in config.yaml. Chain it after a report command
+in cron to deliver scheduled reports:
+
+  0 7 * * 1  asa-cli reports brief --out /tmp/brief.pdf && asa-cli notify email weekly-brief /tmp/brief.pdf
+
+Subject and body are Go templates with {{.Job}}, {{.Profile}}, {{.Date}}, and
+{{.Files}}; a job's own subject/body override the section defaults.
+
+Example config:
+  notifications:
+    email:
+      host: smtp.example.com
+      port: 587
+      username: reports@example.com
+      password_env: ASA_SMTP_PASSWORD
+      from: reports@example.com
+      subject: "Apple Search Ads: {{.Job}} ({{.Date}})"
+      jobs:
+        weekly-brief:
+          to: [client@example.com]
+          cc: [team@example.com]`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runNotifyEmail,
+}
+
+var notifyDryRun bool
+
+func init() {
+	notifyEmailCmd.Flags().BoolVar(&notifyDryRun, "dry-run", false, "Print the encoded message instead of sending it")
+
+	notifyCmd.AddCommand(notifyEmailCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
+
+func runNotifyEmail(cmd *cobra.Command, args []string) error {
+	return emailFiles(args[0], args[1:])
+}
+
+// emailFiles sends files to an email job's recipients, or prints the message
+// with --dry-run.
+func emailFiles(job string, files []string) error {
+	if explainSkip(fmt.Sprintf("email %d file(s) to job '%s'", len(files), job)) {
+		return nil
+	}
+	cfg, err := config.Email()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("attachment: %w", err)
+		}
+	}
+	msg, err := notify.NewMessage(cfg, job, profileName, files)
+	if err != nil {
+		return err
+	}
+
+	if notifyDryRun {
+		data, err := msg.Bytes()
+		if err != nil {
+			return err
+		}
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := notify.Send(cfg, msg); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Emailed %d file(s) to %d recipient(s) for job '%s'.\n", len(files), len(msg.To)+len(msg.Cc), job)
+	return nil
+}
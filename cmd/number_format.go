@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var (
+	localeFlag      string
+	csvCurrencyMode string
+	dateFormat      string
+)
+
+// setupNumberFormat sets how tables show numbers from the formatting:
+// section, with --locale overriding its locale.
+func setupNumberFormat() error {
+	fc, err := config.Formatting()
+	if err != nil {
+		fc = &config.FormattingConfig{} // don't block on config errors
+	}
+	locale := fc.Locale
+	if localeFlag != "" {
+		locale = localeFlag
+	}
+
+	var nf output.NumberFormat
+	if locale != "" {
+		if nf, err = output.Locale(locale); err != nil {
+			return err
+		}
+	}
+	if fc.ThousandsSeparator != nil {
+		nf.Thousands = *fc.ThousandsSeparator
+	}
+	if fc.DecimalSeparator != "" {
+		nf.Decimal = fc.DecimalSeparator
+	}
+	if nf.Thousands != "" && nf.Thousands == nf.Decimal {
+		return fmt.Errorf("formatting: thousands and decimal separators are both %q", nf.Decimal)
+	}
+	if fc.Currency != "" {
+		c := strings.ToLower(fc.Currency)
+		if c != "code" && c != "before" && c != "after" {
+			return fmt.Errorf("formatting: invalid currency placement %q (expected code, before, or after)", fc.Currency)
+		}
+		nf.Currency = c
+	}
+	for name, places := range fc.Decimals {
+		if places < 0 || places > 10 {
+			return fmt.Errorf("formatting: decimals for %s must be between 0 and 10", name)
+		}
+	}
+	nf.Decimals = fc.Decimals
+	output.Numbers = nf
+	return nil
+}
+
+// setupCSVFormat sets how csv output writes money and report dates from
+// --csv-currency-mode and --date-format.
+func setupCSVFormat() error {
+	mode := strings.ToLower(csvCurrencyMode)
+	switch mode {
+	case output.CurrencySplit, output.CurrencySymbol, output.CurrencyPlain:
+	default:
+		return fmt.Errorf("invalid --csv-currency-mode %q (expected split, symbol, or plain)", csvCurrencyMode)
+	}
+
+	layout := dateFormat
+	if preset, ok := output.DateLayouts[strings.ToLower(layout)]; ok {
+		layout = preset
+	} else if layout != "" {
+		// A layout without any date or time element formats as itself
+		ref := time.Date(1999, 12, 31, 23, 59, 58, 0, time.UTC)
+		if ref.Format(layout) == layout {
+			return fmt.Errorf("invalid --date-format %q (expected iso, us, eu, or a Go layout such as 02.01.2006)", dateFormat)
+		}
+	}
+	output.CSV = output.CSVOptions{Currency: mode, DateLayout: layout}
+	return nil
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/changeset"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/optimize"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var optimizeCmd = &cobra.Command{
+	Use:   "optimize",
+	Short: "Propose changes from recent performance",
+}
+
+var optimizeBudgetsCmd = &cobra.Command{
+	Use:   "budgets",
+	Short: "Split a daily budget pool across campaigns",
+	Long: `Propose new daily budgets that split --pool across the selected campaigns,
+weighted by their performance over the last --days days:
+
+  proportional-to-installs  share by installs
+  proportional-to-spend     share by spend
+  inverse-cpi               share by 1/CPI (cheaper installs earn more)
+  equal                     the same budget for every campaign
+
+Every campaign gets at least --min-budget; with the default of 0, a campaign
+with no weight is proposed no budget, which --apply refuses. Nothing changes
+unless --apply is given; without it the proposal is only printed. Applied
+budgets are recorded as a change set, with a rollback change set that
+restores the previous budgets:
+
+  asa-cli changeset apply optimize-budgets-20240601-120000-rollback
+
+Example:
+  asa-cli optimize budgets --pool 1000 --strategy proportional-to-installs --campaign-filter status=ENABLED`,
+	RunE: runOptimizeBudgets,
+}
+
+var (
+	optPool      float64
+	optStrategy  string
+	optFilters   []string
+	optDays      int
+	optMinBudget float64
+	optApply     bool
+	optPortfolio string
+)
+
+func init() {
+	optimizeBudgetsCmd.Flags().Float64Var(&optPool, "pool", 0, "Total daily budget to split (required)")
+	optimizeBudgetsCmd.Flags().StringVar(&optStrategy, "strategy", optimize.StrategyInstalls, "Allocation strategy: "+strings.Join(optimize.Strategies, ", "))
+	optimizeBudgetsCmd.Flags().StringSliceVar(&optFilters, "campaign-filter", []string{"status=ENABLED"}, `Campaign filter conditions (e.g. "status=ENABLED", "name~US")`)
+	optimizeBudgetsCmd.Flags().StringVar(&optPortfolio, "portfolio", "", "Only campaigns in this portfolio (combined with --campaign-filter)")
+	optimizeBudgetsCmd.Flags().IntVar(&optDays, "days", 14, "Days of performance to weigh")
+	optimizeBudgetsCmd.Flags().Float64Var(&optMinBudget, "min-budget", 0, "Minimum daily budget per campaign")
+	optimizeBudgetsCmd.Flags().BoolVar(&optApply, "apply", false, "Update the campaigns' daily budgets")
+	optimizeBudgetsCmd.MarkFlagRequired("pool")
+
+	optimizeCmd.AddCommand(optimizeBudgetsCmd)
+	rootCmd.AddCommand(optimizeCmd)
+}
+
+type budgetProposal struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Spend    float64 `json:"spend"`
+	Installs int64   `json:"installs"`
+	CPI      float64 `json:"cpi"`
+	Current  float64 `json:"currentDailyBudget"`
+	Proposed float64 `json:"proposedDailyBudget"`
+	Change   string  `json:"-"`
+}
+
+func runOptimizeBudgets(cmd *cobra.Command, args []string) error {
+	if optPool <= 0 {
+		return fmt.Errorf("--pool must be positive")
+	}
+	if _, err := optimize.Weight(optStrategy, aggregate.Metrics{}); err != nil {
+		return fmt.Errorf("%w (expected %s)", err, strings.Join(optimize.Strategies, ", "))
+	}
+	if optDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+	rng, err := daterange.Parse(fmt.Sprintf("last-%dd", optDays), time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	selector := models.NewSelector(1000, 0)
+	selector.Conditions = parseFilters(optFilters)
+	campaigns, err := services.NewCampaignService(client).FindAll(selector)
+	if err != nil {
+		return fmt.Errorf("finding campaigns: %w", err)
+	}
+	if optPortfolio != "" {
+		ids, err := portfolioCampaignIDs(client, optPortfolio)
+		if err != nil {
+			return err
+		}
+		var kept []models.Campaign
+		for _, c := range campaigns {
+			if ids[c.ID] {
+				kept = append(kept, c)
+			}
+		}
+		campaigns = kept
+	}
+	if len(campaigns) == 0 {
+		return fmt.Errorf("no campaigns match the filter")
+	}
+
+	report, err := services.NewReportingService(client).GetCampaignReport(newRangeReportRequest(rng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting campaign report: %w", err)
+	}
+	metrics := map[int64]aggregate.Metrics{}
+	for _, e := range aggregate.ByEntity(report, "campaignId", "campaignName") {
+		metrics[e.ID] = e.Metrics
+	}
+
+	weights := make([]float64, len(campaigns))
+	for i, c := range campaigns {
+		weights[i], _ = optimize.Weight(optStrategy, metrics[c.ID])
+	}
+	budgets := optimize.Allocate(optPool, weights, optMinBudget)
+
+	proposals := make([]budgetProposal, len(campaigns))
+	for i, c := range campaigns {
+		m := metrics[c.ID]
+		p := budgetProposal{ID: c.ID, Name: c.Name, Spend: roundCents(m.Spend), Installs: m.Installs, CPI: roundCents(m.CPI()), Proposed: budgets[i]}
+		if c.DailyBudgetAmount != nil {
+			p.Current = aggregate.Amount(*c.DailyBudgetAmount)
+		}
+		p.Change = pctChange(p.Proposed, p.Current)
+		proposals[i] = p
+	}
+
+	output.Print(getFormat(), proposals, []output.Column{
+		{Header: "ID", Field: "ID"},
+		{Header: "NAME", Field: "Name"},
+		{Header: fmt.Sprintf("SPEND (%dD)", optDays), Field: "Spend"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "CPI", Field: "CPI"},
+		{Header: "CURRENT DAILY", Field: "Current"},
+		{Header: "PROPOSED DAILY", Field: "Proposed"},
+		{Header: "CHANGE", Field: "Change"},
+	})
+
+	if !optApply {
+		fmt.Fprintf(os.Stderr, "Dry run: %s over %s. Re-run with --apply to update daily budgets.\n", optStrategy, rng)
+		return nil
+	}
+
+	// Check every budget against the limits before changing any.
+	for _, p := range proposals {
+		if p.Proposed <= 0 {
+			return fmt.Errorf("campaign %d: proposed daily budget %.2f is not positive; raise --min-budget or narrow --campaign-filter", p.ID, p.Proposed)
+		}
+		if err := checkBudgetLimit(strconv.FormatFloat(p.Proposed, 'f', 2, 64)); err != nil {
+			return fmt.Errorf("campaign %d: %w", p.ID, err)
+		}
+	}
+	currency, err := resolveOrgCurrency(client)
+	if err != nil {
+		return err
+	}
+	record, err := changeset.New("optimize-budgets-" + time.Now().Format("20060102-150405"))
+	if err != nil {
+		return err
+	}
+	svc := services.NewCampaignService(client)
+	updated, unchanged := 0, 0
+	var updateErr error
+	for _, p := range proposals {
+		if p.Proposed == p.Current {
+			unchanged++
+			continue
+		}
+		id := strconv.FormatInt(p.ID, 10)
+		amount := strconv.FormatFloat(p.Proposed, 'f', 2, 64)
+		record.Add([]string{"campaigns", "update", id, "--daily-budget=" + amount})
+		item := &record.Items[len(record.Items)-1]
+
+		update := &models.CampaignUpdate{DailyBudgetAmount: &models.Money{Amount: amount, Currency: currency}}
+		if _, err := svc.Update(p.ID, update); err != nil {
+			item.Status, item.Error = changeset.ItemFailed, err.Error()
+			updateErr = fmt.Errorf("updating campaign %d (%d of %d updated): %w", p.ID, updated, len(proposals), err)
+			break
+		}
+		updated++
+		item.Status = changeset.ItemApplied
+		if p.Current > 0 {
+			item.Rollback = [][]string{{"campaigns", "update", id, "--daily-budget=" + strconv.FormatFloat(p.Current, 'f', 2, 64)}}
+		}
+	}
+	if updateErr == nil {
+		summary := fmt.Sprintf("Updated daily budgets of %d campaign(s)", updated)
+		if unchanged > 0 {
+			summary += fmt.Sprintf("; skipped %d already at the proposed budget", unchanged)
+		}
+		fmt.Fprintln(os.Stderr, summary+".")
+	}
+
+	if len(record.Items) > 0 {
+		now := time.Now().UTC()
+		record.Status, record.AppliedAt = changeset.StatusApplied, &now
+		if updateErr != nil {
+			record.Status = changeset.StatusFailed
+		}
+		if err := changeset.Save(profileName, record); err != nil {
+			return err
+		}
+		rollback, err := writeRollbackChangeset(record)
+		if err != nil {
+			return err
+		}
+		if rollback != "" {
+			fmt.Fprintf(os.Stderr, "Recorded as change set %s. To undo: asa-cli changeset apply %s\n", record.Name, rollback)
+		}
+	}
+	return updateErr
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aclcache"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var orgsCmd = &cobra.Command{
+	Use:   "orgs",
+	Short: "Show organization settings",
+}
+
+var orgsShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show an organization's currency, time zone, and payment model",
+	Long: `Show the settings of an organization from GET /acls: its currency, time zone,
+payment model, parent org, and your roles in it. Without an ID, the org in
+effect (--org-id, org_id in config, or the only one) is shown.
+
+Mismatches with what the CLI assumes are listed too: a profile currency
+(currency in config, the one max_daily_budget, max_bid and min_bid are in)
+other than the org's, and a machine time zone other than the org's, which
+shifts "today" and relative date ranges away from Apple's days.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOrgsShow,
+}
+
+func init() {
+	orgsCmd.AddCommand(orgsShowCmd)
+	rootCmd.AddCommand(orgsCmd)
+}
+
+// OrgDetails is an organization's settings as shown by orgs show.
+type OrgDetails struct {
+	OrgID         int64               `json:"orgId"`
+	OrgName       string              `json:"orgName"`
+	ParentOrgID   *int64              `json:"parentOrgId,omitempty"`
+	Currency      string              `json:"currency"`
+	TimeZone      string              `json:"timeZone,omitempty"`
+	PaymentModel  models.PaymentModel `json:"paymentModel,omitempty"`
+	Roles         []string            `json:"roles"`
+	LocalTimeZone string              `json:"localTimeZone"`
+	Warnings      []string            `json:"warnings"`
+}
+
+func runOrgsShow(cmd *cobra.Command, args []string) error {
+	orgID := globalOrgID
+	if len(args) == 1 {
+		if _, err := strconv.ParseInt(args[0], 10, 64); err != nil {
+			return fmt.Errorf("invalid org ID: %s", args[0])
+		}
+		orgID = args[0]
+	}
+
+	client, err := newAPIClientNoOrg()
+	if err != nil {
+		return err
+	}
+	acls, err := services.NewACLService(client).GetACLs()
+	if err != nil {
+		return fmt.Errorf("fetching ACLs: %w", err)
+	}
+	cfg, _ := config.Load()
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	_ = aclcache.Save(profileName, cfg.ClientID, acls)
+	if orgID == "" {
+		orgID = cfg.OrgID
+	}
+
+	var acl *models.UserACL
+	for i := range acls {
+		if orgID == "" && len(acls) == 1 || strconv.FormatInt(acls[i].OrgID, 10) == orgID {
+			acl = &acls[i]
+			break
+		}
+	}
+	if acl == nil {
+		if orgID == "" {
+			return fmt.Errorf("multiple organizations found; pass an org ID (see asa-cli whoami)")
+		}
+		return fmt.Errorf("organization %s not found among the %d you can access", orgID, len(acls))
+	}
+
+	now := time.Now()
+	zone, _ := now.Zone()
+	d := OrgDetails{
+		OrgID:         acl.OrgID,
+		OrgName:       acl.OrgName,
+		ParentOrgID:   acl.ParentOrgID,
+		Currency:      acl.Currency,
+		TimeZone:      acl.TimeZone,
+		PaymentModel:  acl.PaymentModel,
+		Roles:         acl.RoleNames,
+		LocalTimeZone: fmt.Sprintf("%s (%s)", zone, utcOffset(now)),
+		Warnings:      []string{},
+	}
+	if w := orgCurrencyMismatch(acl, cfg); w != "" {
+		d.Warnings = append(d.Warnings, w)
+	}
+	if w := orgTimeZoneMismatch(acl, now); w != "" {
+		d.Warnings = append(d.Warnings, w)
+	}
+
+	if getFormat() != output.FormatTable {
+		output.Print(getFormat(), d, nil)
+		return nil
+	}
+	parent := "-"
+	if d.ParentOrgID != nil {
+		parent = strconv.FormatInt(*d.ParentOrgID, 10)
+	}
+	fmt.Printf("Organization:   %s (ID: %d)\n", d.OrgName, d.OrgID)
+	fmt.Printf("Parent org:     %s\n", parent)
+	fmt.Printf("Currency:       %s\n", orDash(d.Currency))
+	fmt.Printf("Time zone:      %s\n", orDash(d.TimeZone))
+	fmt.Printf("Payment model:  %s\n", orDash(string(d.PaymentModel)))
+	fmt.Printf("Roles:          %s\n", orDash(strings.Join(d.Roles, ", ")))
+	fmt.Printf("Local time:     %s\n", d.LocalTimeZone)
+	for _, w := range d.Warnings {
+		warnf("%s", w)
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// orgCurrencyMismatch describes a profile currency other than the org's, or
+// returns "".
+func orgCurrencyMismatch(acl *models.UserACL, cfg *config.Config) string {
+	if cfg.Currency == "" || acl.Currency == "" || strings.EqualFold(cfg.Currency, acl.Currency) {
+		return ""
+	}
+	return fmt.Sprintf("org %d bills in %s, but this profile's currency is %s; max_daily_budget, max_bid and min_bid are compared with %s amounts",
+		acl.OrgID, acl.Currency, strings.ToUpper(cfg.Currency), acl.Currency)
+}
+
+// orgTimeZoneMismatch describes a machine time zone whose offset differs
+// from the org's at now, or returns "". Unknown zones are not compared.
+func orgTimeZoneMismatch(acl *models.UserACL, now time.Time) string {
+	if acl.TimeZone == "" {
+		return ""
+	}
+	loc, err := time.LoadLocation(acl.TimeZone)
+	if err != nil {
+		return ""
+	}
+	_, orgOffset := now.In(loc).Zone()
+	zone, localOffset := now.Zone()
+	if orgOffset == localOffset {
+		return ""
+	}
+	return fmt.Sprintf("org %d is on %s (%s) but this machine is on %s (%s); \"today\" and relative date ranges follow the machine's clock while Apple's days follow the org's. Set TZ=%s to align them",
+		acl.OrgID, acl.TimeZone, utcOffset(now.In(loc)), zone, utcOffset(now), acl.TimeZone)
+}
+
+// utcOffset formats t's zone offset as "UTC+02:00".
+func utcOffset(t time.Time) string {
+	return "UTC" + t.Format("-07:00")
+}
+
+// orgWarned records the org warnings already printed in this process.
+var orgWarned = map[string]bool{}
+
+func warnOrgOnce(msg string) {
+	if msg == "" || orgWarned[msg] {
+		return
+	}
+	orgWarned[msg] = true
+	warnf("%s", msg)
+}
+
+// warnOrgTimeZone warns when the machine's days differ from the org's, for
+// commands that work out "today" themselves. Failures to look up the org are
+// left to the command.
+func warnOrgTimeZone(client *api.Client) {
+	acl, err := resolveOrgACL(client)
+	if err != nil {
+		return
+	}
+	warnOrgOnce(orgTimeZoneMismatch(acl, time.Now()))
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var noPager bool
+
+// setupPager picks the pager for long tables, like git: $ASA_PAGER, then
+// formatting.pager, then $PAGER, then less. An empty $ASA_PAGER, "false",
+// "off", or "cat" turns paging off, as does a pager that isn't installed.
+func setupPager() {
+	output.Pager = ""
+	if noPager || getFormat() != output.FormatTable {
+		return
+	}
+	pager, ok := os.LookupEnv("ASA_PAGER")
+	if !ok {
+		if fc, err := config.Formatting(); err == nil && fc.Pager != "" {
+			pager = fc.Pager
+		} else if pager = os.Getenv("PAGER"); pager == "" {
+			pager = "less"
+		}
+	}
+	fields := strings.Fields(pager)
+	if len(fields) == 0 {
+		return
+	}
+	switch strings.ToLower(fields[0]) {
+	case "false", "off", "cat":
+		return
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return
+	}
+	output.Pager = pager
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+// maxPageSize is the most results the API returns per request.
+const maxPageSize = 1000
+
+// pager holds a list command's --page and --page-size. The older --limit and
+// --offset still work but are hidden; when given they take precedence.
+type pager struct {
+	page   int
+	size   int
+	limit  int
+	offset int
+}
+
+// register adds the paging flags to cmd. Commands sharing a pager share the
+// values, like their other flags.
+func (p *pager) register(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&p.page, "page", 1, "Page number, starting at 1")
+	cmd.Flags().IntVar(&p.size, "page-size", 20, fmt.Sprintf("Results per page (max %d)", maxPageSize))
+	cmd.Flags().IntVar(&p.limit, "limit", 0, "Number of results")
+	cmd.Flags().IntVar(&p.offset, "offset", 0, "Results offset")
+	cmd.Flags().MarkDeprecated("limit", "use --page-size")
+	cmd.Flags().MarkDeprecated("offset", "use --page")
+}
+
+// resolve returns the limit and offset to request.
+func (p *pager) resolve() (limit, offset int, err error) {
+	limit = p.size
+	if p.limit > 0 {
+		limit = p.limit
+	}
+	if limit < 1 || limit > maxPageSize {
+		return 0, 0, fmt.Errorf("--page-size must be between 1 and %d", maxPageSize)
+	}
+	if p.page < 1 {
+		return 0, 0, fmt.Errorf("--page must be 1 or more")
+	}
+	offset = (p.page - 1) * limit
+	if p.offset > 0 {
+		offset = p.offset
+	}
+	return limit, offset, nil
+}
+
+// selector returns a find selector for the requested page.
+func (p *pager) selector() (models.Selector, error) {
+	limit, offset, err := p.resolve()
+	if err != nil {
+		return models.Selector{}, err
+	}
+	return models.NewSelector(limit, offset), nil
+}
+
+// print shows one page of results. page may be nil when data holds every
+// result, e.g. after --all.
+func (p *pager) print(data interface{}, page *models.PageDetail, columns []output.Column) {
+	limit, _, _ := p.resolve()
+	output.PrintPage(getFormat(), data, page, limit, columns)
+}
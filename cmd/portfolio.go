@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/portfolios"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var portfolioCmd = &cobra.Command{
+	Use:   "portfolio",
+	Short: "Group campaigns into local portfolios",
+	Long: `A portfolio is a named group of campaigns kept locally per profile. Its
+campaigns are a fixed list of IDs, or chosen each time by a local tag or by
+campaign filter conditions.
+
+Portfolios can be used with --portfolio on reports campaigns, summary, and
+optimize budgets, and reported on together with portfolio report.`,
+}
+
+var portfolioCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a portfolio",
+	Example: `  asa-cli portfolio create brand-us --campaigns 1,2,3
+  asa-cli portfolio create brand --tag brand
+  asa-cli portfolio create us --campaign-filter "name~US" --campaign-filter status=ENABLED`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPortfolioCreate,
+}
+
+var portfolioListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List portfolios",
+	RunE:  runPortfolioList,
+}
+
+var portfolioShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a portfolio's campaigns with their combined budgets and spend",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPortfolioShow,
+}
+
+var portfolioReportCmd = &cobra.Command{
+	Use:   "report [name...]",
+	Short: "Aggregated metrics and budgets per portfolio",
+	RunE:  runPortfolioReport,
+}
+
+var portfolioDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a portfolio (the campaigns are not changed)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPortfolioDelete,
+}
+
+var (
+	pfCampaigns string
+	pfTag       string
+	pfFilters   []string
+	pfRange     string
+)
+
+func init() {
+	portfolioCreateCmd.Flags().StringVar(&pfCampaigns, "campaigns", "", "Comma-separated campaign IDs")
+	portfolioCreateCmd.Flags().StringVar(&pfTag, "tag", "", "Campaigns with this local tag, resolved on use")
+	portfolioCreateCmd.Flags().StringSliceVar(&pfFilters, "campaign-filter", nil, `Campaign filter conditions, resolved on use (e.g. "name~US")`)
+	portfolioCreateCmd.MarkFlagsOneRequired("campaigns", "tag", "campaign-filter")
+	portfolioCreateCmd.MarkFlagsMutuallyExclusive("campaigns", "tag", "campaign-filter")
+
+	for _, cmd := range []*cobra.Command{portfolioShowCmd, portfolioReportCmd} {
+		cmd.Flags().StringVar(&pfRange, "range", "last-7d", "Date range: "+daterange.Help)
+	}
+
+	portfolioCmd.AddCommand(portfolioCreateCmd, portfolioListCmd, portfolioShowCmd, portfolioReportCmd, portfolioDeleteCmd)
+	rootCmd.AddCommand(portfolioCmd)
+}
+
+type portfolioRow struct {
+	Name        string   `json:"name"`
+	CampaignIDs []int64  `json:"campaignIds,omitempty"`
+	Tag         string   `json:"tag,omitempty"`
+	Filters     []string `json:"filters,omitempty"`
+	Source      string   `json:"-"`
+}
+
+// PortfolioCampaign is one campaign of a portfolio with its budgets and totals.
+type PortfolioCampaign struct {
+	ID          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Status      models.Status `json:"status"`
+	DailyBudget float64       `json:"dailyBudget"`
+	Budget      float64       `json:"budget"`
+	aggregate.Metrics
+
+	DailyText  string `json:"-"`
+	BudgetText string `json:"-"`
+	SpendText  string `json:"-"`
+	CPIText    string `json:"-"`
+}
+
+// PortfolioSummary is a portfolio's combined budgets and metrics.
+type PortfolioSummary struct {
+	Name        string              `json:"name"`
+	StartDate   string              `json:"startDate"`
+	EndDate     string              `json:"endDate"`
+	DailyBudget float64             `json:"dailyBudget"`
+	Budget      float64             `json:"budget"`
+	Totals      aggregate.Metrics   `json:"totals"`
+	Campaigns   []PortfolioCampaign `json:"campaigns"`
+
+	Count     int    `json:"-"`
+	DailyText string `json:"-"`
+	SpendText string `json:"-"`
+	CPIText   string `json:"-"`
+	Installs  int64  `json:"-"`
+}
+
+func runPortfolioCreate(cmd *cobra.Command, args []string) error {
+	store, err := portfolios.Load(profileName)
+	if err != nil {
+		return err
+	}
+	if _, err := store.Get(args[0]); err == nil {
+		return fmt.Errorf("portfolio %q already exists; delete it first", portfolios.Normalize(args[0]))
+	}
+
+	p := portfolios.Portfolio{Name: args[0], Tag: strings.TrimSpace(pfTag), Filters: pfFilters}
+	if pfCampaigns != "" {
+		if p.CampaignIDs, err = parseIDList(pfCampaigns); err != nil {
+			return err
+		}
+	}
+	if len(parseFilters(p.Filters)) != len(p.Filters) {
+		return fmt.Errorf("invalid --campaign-filter: expected field<op>value, e.g. \"name~US\"")
+	}
+	if err := store.Put(p); err != nil {
+		return err
+	}
+	fmt.Printf("Created portfolio %s (%s)\n", portfolios.Normalize(p.Name), p.Source())
+	return nil
+}
+
+func runPortfolioList(cmd *cobra.Command, args []string) error {
+	store, err := portfolios.Load(profileName)
+	if err != nil {
+		return err
+	}
+	rows := []portfolioRow{}
+	for _, p := range store.List() {
+		rows = append(rows, portfolioRow{Name: p.Name, CampaignIDs: p.CampaignIDs, Tag: p.Tag, Filters: p.Filters, Source: p.Source()})
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "NAME", Field: "Name"},
+		{Header: "CAMPAIGNS", Field: "Source"},
+	})
+	return nil
+}
+
+func runPortfolioDelete(cmd *cobra.Command, args []string) error {
+	store, err := portfolios.Load(profileName)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted portfolio %s\n", portfolios.Normalize(args[0]))
+	return nil
+}
+
+func runPortfolioShow(cmd *cobra.Command, args []string) error {
+	rng, err := daterange.Parse(pfRange, time.Now())
+	if err != nil {
+		return err
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	campaigns, err := portfolioCampaigns(client, args[0])
+	if err != nil {
+		return err
+	}
+	report, err := services.NewReportingService(client).GetCampaignReport(newRangeReportRequest(rng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting campaign report: %w", err)
+	}
+
+	s := summarizePortfolio(portfolios.Normalize(args[0]), campaigns, campaignMetrics(report), rng)
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, s, nil)
+		return nil
+	}
+
+	output.Print(getFormat(), s.Campaigns, []output.Column{
+		{Header: "ID", Field: "ID"},
+		{Header: "NAME", Field: "Name"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "DAILY BUDGET", Field: "DailyText"},
+		{Header: "BUDGET", Field: "BudgetText"},
+		{Header: "SPEND", Field: "SpendText"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "CPI", Field: "CPIText"},
+	})
+	if getFormat() == output.FormatTable {
+		fmt.Printf("\n%s — %d campaign(s), %s\n", s.Name, len(s.Campaigns), rng)
+		fmt.Printf("  Daily budget: %12.2f\n", s.DailyBudget)
+		fmt.Printf("  Budget:       %12.2f\n", s.Budget)
+		fmt.Printf("  Spend:        %12.2f\n", s.Totals.Spend)
+		fmt.Printf("  Installs:     %12d\n", s.Totals.Installs)
+		fmt.Printf("  CPI:          %12.2f\n", s.Totals.CPI())
+	}
+	return nil
+}
+
+func runPortfolioReport(cmd *cobra.Command, args []string) error {
+	rng, err := daterange.Parse(pfRange, time.Now())
+	if err != nil {
+		return err
+	}
+	store, err := portfolios.Load(profileName)
+	if err != nil {
+		return err
+	}
+	var list []portfolios.Portfolio
+	if len(args) == 0 {
+		list = store.List()
+	} else {
+		for _, name := range args {
+			p, err := store.Get(name)
+			if err != nil {
+				return err
+			}
+			list = append(list, p)
+		}
+	}
+	if len(list) == 0 {
+		return fmt.Errorf("no portfolios; create one with portfolio create")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	all, err := services.NewCampaignService(client).FindAll(models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("listing campaigns: %w", err)
+	}
+	report, err := services.NewReportingService(client).GetCampaignReport(newRangeReportRequest(rng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting campaign report: %w", err)
+	}
+	metrics := campaignMetrics(report)
+
+	summaries := []PortfolioSummary{}
+	for _, p := range list {
+		campaigns, err := resolvePortfolio(client, p, all)
+		if err != nil {
+			return err
+		}
+		summaries = append(summaries, summarizePortfolio(p.Name, campaigns, metrics, rng))
+	}
+
+	output.Print(getFormat(), summaries, []output.Column{
+		{Header: "PORTFOLIO", Field: "Name"},
+		{Header: "CAMPAIGNS", Field: "Count"},
+		{Header: "DAILY BUDGET", Field: "DailyText"},
+		{Header: "SPEND", Field: "SpendText"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "CPI", Field: "CPIText"},
+	})
+	return nil
+}
+
+// campaignMetrics totals a campaign report per campaign ID.
+func campaignMetrics(report *models.ReportingDataResponse) map[int64]aggregate.Metrics {
+	metrics := map[int64]aggregate.Metrics{}
+	for _, e := range aggregate.ByEntity(report, "campaignId", "campaignName") {
+		metrics[e.ID] = e.Metrics
+	}
+	return metrics
+}
+
+func summarizePortfolio(name string, campaigns []models.Campaign, metrics map[int64]aggregate.Metrics, rng daterange.Range) PortfolioSummary {
+	s := PortfolioSummary{Name: name, StartDate: rng.StartDate(), EndDate: rng.EndDate(), Campaigns: []PortfolioCampaign{}}
+	for _, c := range campaigns {
+		pc := PortfolioCampaign{ID: c.ID, Name: c.Name, Status: c.Status, Metrics: metrics[c.ID]}
+		if c.DailyBudgetAmount != nil {
+			pc.DailyBudget = aggregate.Amount(*c.DailyBudgetAmount)
+		}
+		if c.BudgetAmount != nil {
+			pc.Budget = aggregate.Amount(*c.BudgetAmount)
+		}
+		pc.DailyText = fmt.Sprintf("%.2f", pc.DailyBudget)
+		pc.BudgetText = fmt.Sprintf("%.2f", pc.Budget)
+		pc.SpendText = fmt.Sprintf("%.2f", pc.Spend)
+		pc.CPIText = fmt.Sprintf("%.2f", pc.CPI())
+
+		s.DailyBudget += pc.DailyBudget
+		s.Budget += pc.Budget
+		s.Totals.Merge(pc.Metrics)
+		s.Campaigns = append(s.Campaigns, pc)
+	}
+	s.Count = len(s.Campaigns)
+	s.Installs = s.Totals.Installs
+	s.DailyText = fmt.Sprintf("%.2f", s.DailyBudget)
+	s.SpendText = fmt.Sprintf("%.2f", s.Totals.Spend)
+	s.CPIText = fmt.Sprintf("%.2f", s.Totals.CPI())
+	return s
+}
+
+// portfolioCampaigns resolves a portfolio by name to its campaigns.
+func portfolioCampaigns(client *api.Client, name string) ([]models.Campaign, error) {
+	store, err := portfolios.Load(profileName)
+	if err != nil {
+		return nil, err
+	}
+	p, err := store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	all, err := services.NewCampaignService(client).FindAll(models.NewSelector(1000, 0))
+	if err != nil {
+		return nil, fmt.Errorf("listing campaigns: %w", err)
+	}
+	return resolvePortfolio(client, p, all)
+}
+
+// resolvePortfolio picks a portfolio's campaigns out of all, the account's
+// campaigns. Filter portfolios query the API with their conditions instead.
+func resolvePortfolio(client *api.Client, p portfolios.Portfolio, all []models.Campaign) ([]models.Campaign, error) {
+	var campaigns []models.Campaign
+	switch {
+	case p.Tag != "":
+		return filterCampaignsByTag(all, p.Tag)
+	case len(p.Filters) > 0:
+		selector := models.NewSelector(1000, 0)
+		selector.Conditions = parseFilters(p.Filters)
+		found, err := services.NewCampaignService(client).FindAll(selector)
+		if err != nil {
+			return nil, fmt.Errorf("finding campaigns of portfolio %s: %w", p.Name, err)
+		}
+		return found, nil
+	}
+
+	byID := map[int64]models.Campaign{}
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+	for _, id := range p.CampaignIDs {
+		c, ok := byID[id]
+		if !ok {
+			warnf("portfolio %s: campaign %d not found", p.Name, id)
+			continue
+		}
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, nil
+}
+
+// portfolioCampaignIDs resolves a portfolio by name to the set of its
+// campaign IDs.
+func portfolioCampaignIDs(client *api.Client, name string) (map[int64]bool, error) {
+	campaigns, err := portfolioCampaigns(client, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(campaigns) == 0 {
+		return nil, fmt.Errorf("portfolio %q has no campaigns", portfolios.Normalize(name))
+	}
+	ids := map[int64]bool{}
+	for _, c := range campaigns {
+		ids[c.ID] = true
+	}
+	return ids, nil
+}
@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
 	"github.com/trebuhs/asa-cli/internal/models"
 	"github.com/trebuhs/asa-cli/internal/output"
 	"github.com/trebuhs/asa-cli/internal/services"
@@ -44,11 +44,19 @@ var reportsSearchTermsCmd = &cobra.Command{
 var (
 	rptStartDate   string
 	rptEndDate     string
-	rptGranularity string
+	rptGranularity models.Granularity
 	rptGroupBy     string
 	rptCampaignID  int64
+	rptAdGroupID   int64
 	rptLimit       int
 	rptGrandTotals bool
+	rptChart       bool
+	rptChartMetric string
+	rptExport      string
+	rptMetrics     string
+	rptPortfolio   string
+	rptFilters     []string
+	rptSorts       []string
 )
 
 func init() {
@@ -56,14 +64,33 @@ func init() {
 	for _, cmd := range []*cobra.Command{reportsCampaignsCmd, reportsAdGroupsCmd, reportsKeywordsCmd, reportsSearchTermsCmd} {
 		cmd.Flags().StringVar(&rptStartDate, "start-date", "", "Start date (YYYY-MM-DD) (required)")
 		cmd.Flags().StringVar(&rptEndDate, "end-date", "", "End date (YYYY-MM-DD) (required)")
-		cmd.Flags().StringVar(&rptGranularity, "granularity", "", "Granularity: HOURLY, DAILY, WEEKLY, MONTHLY")
+		enumVar(cmd.Flags(), &rptGranularity, "granularity", "", models.ParseGranularity, "Granularity: HOURLY, DAILY, WEEKLY, MONTHLY")
 		cmd.Flags().StringVar(&rptGroupBy, "group-by", "", "Comma-separated group by fields (e.g. countryOrRegion,deviceClass)")
 		cmd.Flags().IntVar(&rptLimit, "limit", 1000, "Result limit")
 		cmd.Flags().BoolVar(&rptGrandTotals, "grand-totals", false, "Include grand totals")
+		cmd.Flags().BoolVar(&rptChart, "chart", false, "Render a terminal chart: over time with --granularity, by group with --group-by, else by entity")
+		cmd.Flags().StringVar(&rptMetrics, "metrics", aggregate.DefaultMetrics, "Comma-separated metrics for table and export output: "+strings.Join(aggregate.MetricNames, ", "))
+		cmd.Flags().StringVar(&rptChartMetric, "chart-metric", "spend", "Metric to chart: "+strings.Join(aggregate.MetricNames, ", "))
+		cmd.Flags().StringVar(&rptExport, "export", "", "Write rows to a destination instead of printing: gsheet://<spreadsheetId>/<tab>[?mode=replace] or postgres://...?table=<name>")
 		cmd.MarkFlagRequired("start-date")
 		cmd.MarkFlagRequired("end-date")
+		addThresholdFlags(cmd)
+		addDimensionFlags(cmd)
+		addReportSelectorFlags(cmd)
+		cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			if _, err := reportMetrics(); err != nil {
+				return err
+			}
+			if err := checkReportFilters(); err != nil {
+				return err
+			}
+			return resolveGroupBy(cmd.Name())
+		}
 	}
 
+	reportsSearchTermsCmd.Flags().Int64Var(&rptAdGroupID, "adgroup-id", 0, "Only search terms of this ad group")
+	reportsCampaignsCmd.Flags().StringVar(&rptPortfolio, "portfolio", "", "Only campaigns in this portfolio; grand totals cover just them")
+
 	// Campaign ID for sub-entity reports
 	for _, cmd := range []*cobra.Command{reportsAdGroupsCmd, reportsKeywordsCmd, reportsSearchTermsCmd} {
 		cmd.Flags().Int64Var(&rptCampaignID, "campaign-id", 0, "Campaign ID (required)")
@@ -74,16 +101,33 @@ func init() {
 	rootCmd.AddCommand(reportsCmd)
 }
 
+// addReportSelectorFlags adds --filter and --sort, sent to the API as the
+// report selector's conditions and order.
+func addReportSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&rptFilters, "filter", nil, `Report filter conditions (e.g. "countryOrRegion=US", "keywordStatus=ACTIVE")`)
+	cmd.Flags().StringSliceVar(&rptSorts, "sort", nil, `Sort order (e.g. "installs:desc"; default "localSpend:desc")`)
+}
+
+func checkReportFilters() error {
+	if len(parseFilters(rptFilters)) != len(rptFilters) {
+		return fmt.Errorf("invalid --filter: expected field<op>value, e.g. \"countryOrRegion=US\"")
+	}
+	return nil
+}
+
 func buildReportRequest() *models.ReportRequest {
+	orderBy := parseSorts(rptSorts)
+	if len(orderBy) == 0 {
+		orderBy = []models.OrderByItem{{Field: "localSpend", SortOrder: "DESCENDING"}}
+	}
 	req := &models.ReportRequest{
 		StartTime:         rptStartDate,
 		EndTime:           rptEndDate,
 		ReturnGrandTotals: rptGrandTotals,
 		ReturnRowTotals:   true,
 		Selector: &models.Selector{
-			OrderBy: []models.OrderByItem{
-				{Field: "localSpend", SortOrder: "DESCENDING"},
-			},
+			Conditions: parseFilters(rptFilters),
+			OrderBy:    orderBy,
 			Pagination: models.SelectorPagination{
 				Offset: 0,
 				Limit:  rptLimit,
@@ -91,67 +135,51 @@ func buildReportRequest() *models.ReportRequest {
 		},
 	}
 
-	if rptGranularity != "" {
-		req.Granularity = strings.ToUpper(rptGranularity)
-	}
+	req.Granularity = rptGranularity
 
-	if rptGroupBy != "" {
-		req.GroupBy = strings.Split(rptGroupBy, ",")
+	if groupBy := splitList(rptGroupBy); len(groupBy) > 0 {
+		req.GroupBy = groupBy
 	}
 
 	return req
 }
 
-func printReport(resp *models.ReportingDataResponse) {
-	if getFormat() == output.FormatJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		enc.Encode(resp)
-		return
+// newRangeReportRequest builds a report request for a date range, sorted by spend.
+func newRangeReportRequest(r daterange.Range, limit int) *models.ReportRequest {
+	return &models.ReportRequest{
+		StartTime:       r.StartDate(),
+		EndTime:         r.EndDate(),
+		ReturnRowTotals: true,
+		Selector: &models.Selector{
+			OrderBy: []models.OrderByItem{
+				{Field: "localSpend", SortOrder: "DESCENDING"},
+			},
+			Pagination: models.SelectorPagination{
+				Offset: 0,
+				Limit:  limit,
+			},
+		},
 	}
+}
 
-	// Table format — print summary
-	if resp == nil || len(resp.Row) == 0 {
-		fmt.Println("No report data.")
-		return
+func printReport(resp *models.ReportingDataResponse, level string) error {
+	resp = filterReport(resp)
+	if rptExport != "" {
+		return exportReport(resp, level)
 	}
-
-	// Print each row
-	for _, row := range resp.Row {
-		if row.Metadata != nil {
-			for k, v := range row.Metadata {
-				fmt.Printf("%s: %v  ", k, v)
-			}
-			fmt.Println()
-		}
-
-		if row.Total != nil {
-			printMetricsRow(row.Total)
-		}
-
-		for _, g := range row.Granularity {
-			fmt.Printf("  Date: %s\n", g.Date)
-			if g.Metrics != nil {
-				printMetricsRow(g.Metrics)
-			}
-		}
-		fmt.Println("---")
+	if rptChart {
+		return printReportChart(resp, level)
 	}
-
-	if resp.GrandTotals != nil && resp.GrandTotals.Total != nil {
-		fmt.Println("\nGRAND TOTALS:")
-		printMetricsRow(resp.GrandTotals.Total)
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, resp, nil)
+		return nil
 	}
-}
 
-func printMetricsRow(m *models.SpendRow) {
-	fmt.Printf("  Impressions: %d | Taps: %d | Installs: %d (tap: %d, view: %d) | NewDL: %d | Redownloads: %d\n",
-		m.Impressions, m.Taps, m.TotalInstalls, m.TapInstalls, m.ViewInstalls, m.TotalNewDownloads, m.TotalRedownloads)
-	fmt.Printf("  TTR: %.4f | InstallRate: %.4f (tap: %.4f) | CPI: %s %s | AvgCPT: %s %s | Spend: %s %s\n",
-		m.TTR, m.TotalInstallRate, m.TapInstallRate,
-		m.TotalAvgCPI.Amount, m.TotalAvgCPI.Currency,
-		m.AvgCPT.Amount, m.AvgCPT.Currency,
-		m.LocalSpend.Amount, m.LocalSpend.Currency)
+	if resp == nil || len(resp.Row) == 0 {
+		fmt.Println("No report data.")
+		return nil
+	}
+	return printReportTable(resp, level)
 }
 
 func runReportCampaigns(cmd *cobra.Command, args []string) error {
@@ -165,9 +193,15 @@ func runReportCampaigns(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("getting campaign report: %w", err)
 	}
+	if rptPortfolio != "" {
+		ids, err := portfolioCampaignIDs(client, rptPortfolio)
+		if err != nil {
+			return err
+		}
+		resp = aggregate.Subset(resp, "campaignId", ids)
+	}
 
-	printReport(resp)
-	return nil
+	return printReport(resp, "campaigns")
 }
 
 func runReportAdGroups(cmd *cobra.Command, args []string) error {
@@ -182,8 +216,7 @@ func runReportAdGroups(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting ad group report: %w", err)
 	}
 
-	printReport(resp)
-	return nil
+	return printReport(resp, "adgroups")
 }
 
 func runReportKeywords(cmd *cobra.Command, args []string) error {
@@ -198,8 +231,7 @@ func runReportKeywords(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting keyword report: %w", err)
 	}
 
-	printReport(resp)
-	return nil
+	return printReport(resp, "keywords")
 }
 
 func runReportSearchTerms(cmd *cobra.Command, args []string) error {
@@ -209,11 +241,15 @@ func runReportSearchTerms(cmd *cobra.Command, args []string) error {
 	}
 
 	svc := services.NewReportingService(client)
-	resp, err := svc.GetSearchTermReport(rptCampaignID, buildReportRequest())
+	var resp *models.ReportingDataResponse
+	if rptAdGroupID != 0 {
+		resp, err = svc.GetAdGroupSearchTermReport(rptCampaignID, rptAdGroupID, buildReportRequest())
+	} else {
+		resp, err = svc.GetSearchTermReport(rptCampaignID, buildReportRequest())
+	}
 	if err != nil {
 		return fmt.Errorf("getting search terms report: %w", err)
 	}
 
-	printReport(resp)
-	return nil
+	return printReport(resp, "search-terms")
 }
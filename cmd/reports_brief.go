@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/brief"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var reportsBriefCmd = &cobra.Command{
+	Use:   "brief",
+	Short: "Generate a one-page PDF performance brief",
+	Long: `Generate a client-facing one-page PDF for a period: headline KPIs with their
+change from the previous period of equal length, a daily spend chart, and the
+top campaigns by spend.
+
+Example:
+  asa-cli reports brief --range last-week --out brief.pdf
+  asa-cli reports brief --out brief.pdf --email weekly-brief`,
+	RunE: runReportsBrief,
+}
+
+var (
+	briefRange string
+	briefOut   string
+	briefTop   int
+	briefEmail string
+)
+
+func init() {
+	reportsBriefCmd.Flags().StringVar(&briefRange, "range", "last-week", "Date range: "+daterange.Help)
+	reportsBriefCmd.Flags().StringVar(&briefOut, "out", "brief.pdf", "Output PDF path")
+	reportsBriefCmd.Flags().IntVar(&briefTop, "top", 10, "Number of top campaigns to include")
+	reportsBriefCmd.Flags().StringVar(&briefEmail, "email", "", "Email the PDF to this notifications.email job's recipients")
+
+	reportsCmd.AddCommand(reportsBriefCmd)
+}
+
+func runReportsBrief(cmd *cobra.Command, args []string) error {
+	rng, err := daterange.Parse(briefRange, time.Now())
+	if err != nil {
+		return err
+	}
+	prevRng := rng.Previous()
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	acl, err := resolveOrgACL(client)
+	if err != nil {
+		return fmt.Errorf("fetching org: %w", err)
+	}
+
+	reports := services.NewReportingService(client)
+	req := newRangeReportRequest(rng, 1000)
+	req.Granularity = "DAILY"
+	cur, err := reports.GetCampaignReport(req)
+	if err != nil {
+		return fmt.Errorf("getting campaign report: %w", err)
+	}
+	prev, err := reports.GetCampaignReport(newRangeReportRequest(prevRng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting previous period report: %w", err)
+	}
+
+	curEntities := aggregate.ByEntity(cur, "campaignId", "campaignName")
+	prevEntities := aggregate.ByEntity(prev, "campaignId", "campaignName")
+	prevSpend := map[int64]float64{}
+	for _, e := range prevEntities {
+		prevSpend[e.ID] = e.Spend
+	}
+
+	b := &brief.Brief{
+		OrgName:    acl.OrgName,
+		Currency:   acl.Currency,
+		Period:     rng.String(),
+		Previous:   prevRng.String(),
+		Totals:     aggregate.Total(curEntities),
+		PrevTotals: aggregate.Total(prevEntities),
+	}
+	for _, e := range aggregate.TopBySpend(curEntities, briefTop) {
+		b.Campaigns = append(b.Campaigns, brief.Campaign{Name: e.Name, Current: e.Metrics, PrevSpend: prevSpend[e.ID]})
+	}
+
+	daily := map[string]float64{}
+	if cur != nil {
+		for _, row := range cur.Row {
+			for _, g := range row.Granularity {
+				var m aggregate.Metrics
+				m.Add(g.Metrics)
+				daily[g.Date] += m.Spend
+			}
+		}
+	}
+	for d := rng.Start; !d.After(rng.End); d = d.AddDate(0, 0, 1) {
+		date := d.Format(daterange.DateFormat)
+		b.Daily = append(b.Daily, brief.Day{Date: date, Spend: daily[date]})
+	}
+
+	f, err := os.Create(explainPath(briefOut))
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", briefOut, err)
+	}
+	if err := brief.Write(f, b); err != nil {
+		f.Close()
+		return fmt.Errorf("writing brief: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing brief: %w", err)
+	}
+	fmt.Printf("Brief for %s written to %s\n", rng, briefOut)
+	if briefEmail != "" {
+		return emailFiles(briefEmail, []string{briefOut})
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+// chartBars caps the number of bars in a report bar chart.
+const chartBars = 20
+
+// printReportChart renders a report as a terminal chart: a line chart over
+// time when rows have granularity buckets, otherwise bars per group-by value
+// or per entity. Ratio metrics are recomputed from the summed counts.
+func printReportChart(resp *models.ReportingDataResponse, level string) error {
+	metric, ok := aggregate.LookupMetric(rptChartMetric)
+	if !ok {
+		return fmt.Errorf("unknown chart metric %q (expected %s)", rptChartMetric, strings.Join(aggregate.MetricNames, ", "))
+	}
+	if resp == nil || len(resp.Row) == 0 {
+		fmt.Println("No report data to chart.")
+		return nil
+	}
+
+	if hasGranularity(resp) {
+		byDate := map[string]*aggregate.Metrics{}
+		for _, row := range resp.Row {
+			for _, g := range row.Granularity {
+				m, ok := byDate[g.Date]
+				if !ok {
+					m = &aggregate.Metrics{}
+					byDate[g.Date] = m
+				}
+				m.Add(g.Metrics)
+			}
+		}
+		dates := make([]string, 0, len(byDate))
+		for d := range byDate {
+			dates = append(dates, d)
+		}
+		sort.Strings(dates)
+		values := make([]float64, len(dates))
+		for i, d := range dates {
+			values[i] = metric.Compute(*byDate[d])
+		}
+		fmt.Printf("%s by date\n\n", metric.Header)
+		fmt.Print(output.LineChart(dates, values, 12))
+		return nil
+	}
+
+	_, nameKey, err := reportLevelKeys(level)
+	if err != nil {
+		return err
+	}
+	groupBy := splitList(rptGroupBy)
+	by := "entity"
+	if len(groupBy) > 0 {
+		var headers []string
+		for _, g := range groupBy {
+			headers = append(headers, strings.ToLower(dimensionHeader(g)))
+		}
+		by = strings.Join(headers, " / ")
+	}
+
+	var labels []string
+	totals := map[string]*aggregate.Metrics{}
+	for _, row := range resp.Row {
+		label := aggregate.MetaString(row.Metadata, nameKey)
+		if len(groupBy) > 0 {
+			var parts []string
+			for _, g := range groupBy {
+				parts = append(parts, dimensionValue(row.Metadata, g))
+			}
+			label = strings.Join(parts, " / ")
+		}
+		m, ok := totals[label]
+		if !ok {
+			m = &aggregate.Metrics{}
+			totals[label] = m
+			labels = append(labels, label)
+		}
+		m.Merge(aggregate.RowTotals(row))
+	}
+
+	values := map[string]float64{}
+	for _, l := range labels {
+		values[l] = metric.Compute(*totals[l])
+	}
+	sort.SliceStable(labels, func(i, j int) bool { return values[labels[i]] > values[labels[j]] })
+	if len(labels) > chartBars {
+		labels = labels[:chartBars]
+	}
+	bars := make([]float64, len(labels))
+	for i, l := range labels {
+		bars[i] = values[l]
+	}
+
+	fmt.Printf("%s by %s\n\n", metric.Header, by)
+	fmt.Print(output.BarChart(labels, bars, 40))
+	return nil
+}
+
+func hasGranularity(resp *models.ReportingDataResponse) bool {
+	for _, row := range resp.Row {
+		if len(row.Granularity) > 0 {
+			return true
+		}
+	}
+	return false
+}
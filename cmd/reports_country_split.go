@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var reportsCountrySplitCmd = &cobra.Command{
+	Use:   "country-split",
+	Short: "Per-country spend and performance of a multi-country campaign",
+	Long: `Break a campaign's spend, installs, and CPI down by storefront, with each
+country's share of the campaign total.
+
+Countries whose spend reaches --min-share of the campaign (and --min-spend, if
+set) are recommended for a dedicated campaign, where their budget and bids can
+be managed without competing with the other storefronts.`,
+	RunE: runReportsCountrySplit,
+}
+
+var (
+	splitCampaignID int64
+	splitRange      string
+	splitMinShare   float64
+	splitMinSpend   float64
+)
+
+func init() {
+	reportsCountrySplitCmd.Flags().Int64Var(&splitCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	reportsCountrySplitCmd.Flags().StringVar(&splitRange, "range", "last-30d", "Date range: "+daterange.Help)
+	reportsCountrySplitCmd.Flags().Float64Var(&splitMinShare, "min-share", 0.25, "Share of campaign spend (0-1) from which to recommend a split")
+	reportsCountrySplitCmd.Flags().Float64Var(&splitMinSpend, "min-spend", 0, "Spend a country must also reach to be recommended for a split")
+	reportsCountrySplitCmd.MarkFlagRequired("campaign-id")
+
+	reportsCmd.AddCommand(reportsCountrySplitCmd)
+}
+
+// CountrySplit is one storefront's slice of a campaign.
+type CountrySplit struct {
+	Country      string  `json:"country"`
+	Spend        float64 `json:"spend"`
+	Currency     string  `json:"currency,omitempty"`
+	SpendShare   float64 `json:"spendShare"`
+	Installs     int64   `json:"installs"`
+	InstallShare float64 `json:"installShare"`
+	CPI          float64 `json:"cpi"`
+	Split        bool    `json:"split"`
+	Reason       string  `json:"reason,omitempty"`
+}
+
+type countrySplitRow struct {
+	Country      string
+	Spend        string
+	Share        string
+	Installs     int64
+	InstallShare string
+	CPI          string
+	Action       string
+}
+
+func runReportsCountrySplit(cmd *cobra.Command, args []string) error {
+	if splitMinShare < 0 || splitMinShare > 1 {
+		return fmt.Errorf("--min-share must be between 0 and 1")
+	}
+	rng, err := daterange.Parse(splitRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	req := newRangeReportRequest(rng, 1000)
+	req.GroupBy = []string{"countryOrRegion"}
+	req.Selector.Conditions = []models.Condition{
+		{Field: "campaignId", Operator: "IN", Values: []string{strconv.FormatInt(splitCampaignID, 10)}},
+	}
+	resp, err := services.NewReportingService(client).GetCampaignReport(req)
+	if err != nil {
+		return fmt.Errorf("getting campaign report: %w", err)
+	}
+
+	countries, total := countrySplits(resp)
+	if len(countries) == 0 {
+		fmt.Fprintf(os.Stderr, "No spend for campaign %d in %s.\n", splitCampaignID, rng)
+		return nil
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, countries, nil)
+		return nil
+	}
+
+	rows := make([]countrySplitRow, 0, len(countries)+1)
+	var candidates []string
+	for _, c := range countries {
+		action := ""
+		if c.Split {
+			action = "split: " + c.Reason
+			candidates = append(candidates, c.Country)
+		}
+		rows = append(rows, countrySplitRow{
+			Country:      c.Country,
+			Spend:        fmt.Sprintf("%.2f", c.Spend),
+			Share:        fmt.Sprintf("%.1f%%", c.SpendShare*100),
+			Installs:     c.Installs,
+			InstallShare: fmt.Sprintf("%.1f%%", c.InstallShare*100),
+			CPI:          fmt.Sprintf("%.2f", c.CPI),
+			Action:       action,
+		})
+	}
+	rows = append(rows, countrySplitRow{
+		Country:      "TOTAL",
+		Spend:        fmt.Sprintf("%.2f", total.Spend),
+		Share:        "100.0%",
+		Installs:     total.Installs,
+		InstallShare: "100.0%",
+		CPI:          fmt.Sprintf("%.2f", total.CPI()),
+	})
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "COUNTRY", Field: "Country"},
+		{Header: "SPEND", Field: "Spend"},
+		{Header: "SHARE", Field: "Share"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "INSTALL SHARE", Field: "InstallShare"},
+		{Header: "CPI", Field: "CPI"},
+		{Header: "RECOMMENDATION", Field: "Action"},
+	})
+
+	switch {
+	case len(countries) == 1:
+		fmt.Fprintf(os.Stderr, "\nAll spend is in %s; nothing to split.\n", countries[0].Country)
+	case len(candidates) > 0:
+		fmt.Fprintf(os.Stderr, "\nConsider dedicated campaigns for: %s\n", strings.Join(candidates, ", "))
+	default:
+		fmt.Fprintf(os.Stderr, "\nNo country reaches %.0f%% of spend; the campaign is balanced.\n", splitMinShare*100)
+	}
+	return nil
+}
+
+// countrySplits totals a country-grouped campaign report per storefront,
+// sorted by spend, and flags the countries worth a dedicated campaign.
+func countrySplits(resp *models.ReportingDataResponse) ([]CountrySplit, aggregate.Metrics) {
+	var total aggregate.Metrics
+	byCountry := map[string]*aggregate.Metrics{}
+	var order []string
+	if resp != nil {
+		for _, row := range resp.Row {
+			country := dimensionValue(row.Metadata, "countryOrRegion")
+			if country == "" {
+				country = "(unknown)"
+			}
+			m, ok := byCountry[country]
+			if !ok {
+				m = &aggregate.Metrics{}
+				byCountry[country] = m
+				order = append(order, country)
+			}
+			rt := aggregate.RowTotals(row)
+			m.Merge(rt)
+			total.Merge(rt)
+		}
+	}
+
+	out := make([]CountrySplit, 0, len(order))
+	for _, country := range order {
+		m := byCountry[country]
+		c := CountrySplit{
+			Country:  country,
+			Spend:    roundCents(m.Spend),
+			Currency: m.Currency,
+			Installs: m.Installs,
+			CPI:      roundCents(m.CPI()),
+		}
+		if total.Spend > 0 {
+			c.SpendShare = m.Spend / total.Spend
+		}
+		if total.Installs > 0 {
+			c.InstallShare = float64(m.Installs) / float64(total.Installs)
+		}
+		if len(order) > 1 && m.Spend > 0 && c.SpendShare >= splitMinShare && m.Spend >= splitMinSpend {
+			c.Split = true
+			c.Reason = fmt.Sprintf("%.0f%% of spend", c.SpendShare*100)
+			if cpi := total.CPI(); cpi > 0 && m.Installs > 0 {
+				c.Reason += fmt.Sprintf(", CPI %.0f%% of campaign", m.CPI()/cpi*100)
+			}
+		}
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Spend > out[j].Spend })
+	return out, total
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// reportDimension is a field reports can be grouped by.
+type reportDimension struct {
+	Field  string // API group-by field
+	Header string // table column header
+	Flag   string // shortcut flag, if any
+}
+
+var reportDimensions = []reportDimension{
+	{Field: "countryOrRegion", Header: "COUNTRY", Flag: "by-country"},
+	{Field: "deviceClass", Header: "DEVICE", Flag: "by-device"},
+	{Field: "ageRange", Header: "AGE", Flag: "by-age"},
+	{Field: "gender", Header: "GENDER", Flag: "by-gender"},
+	{Field: "countryCode", Header: "COUNTRY CODE"},
+	{Field: "adminArea", Header: "REGION"},
+	{Field: "locality", Header: "CITY"},
+}
+
+// reportLevelDimensions lists the group-by fields each report level accepts.
+// Demographics are only reported down to ad groups, and search terms only
+// break down by country.
+var reportLevelDimensions = map[string][]string{
+	"campaigns":    {"countryOrRegion", "deviceClass", "ageRange", "gender", "countryCode", "adminArea", "locality"},
+	"adgroups":     {"countryOrRegion", "deviceClass", "ageRange", "gender", "countryCode", "adminArea", "locality"},
+	"keywords":     {"countryOrRegion", "deviceClass", "countryCode", "adminArea", "locality"},
+	"search-terms": {"countryOrRegion"},
+}
+
+// rptByDimension holds the shortcut flags, keyed by group-by field.
+var rptByDimension = map[string]*bool{}
+
+func addDimensionFlags(cmd *cobra.Command) {
+	for _, d := range reportDimensions {
+		if d.Flag == "" {
+			continue
+		}
+		v, ok := rptByDimension[d.Field]
+		if !ok {
+			v = new(bool)
+			rptByDimension[d.Field] = v
+		}
+		cmd.Flags().BoolVar(v, d.Flag, false, "Break the report down by "+d.Field+" (same as --group-by "+d.Field+")")
+	}
+}
+
+// dimensionHeader returns the table header for a group-by field.
+func dimensionHeader(field string) string {
+	for _, d := range reportDimensions {
+		if d.Field == field {
+			return d.Header
+		}
+	}
+	return strings.ToUpper(field)
+}
+
+// resolveGroupBy folds the --by-* flags into --group-by and checks the
+// combined fields against what the report level accepts.
+func resolveGroupBy(level string) error {
+	fields := splitList(rptGroupBy)
+	flagOf := map[string]string{}
+	for _, f := range fields {
+		flagOf[f] = "--group-by " + f
+	}
+	for _, d := range reportDimensions {
+		if v := rptByDimension[d.Field]; v != nil && *v {
+			if _, ok := flagOf[d.Field]; !ok {
+				fields = append(fields, d.Field)
+				flagOf[d.Field] = "--" + d.Flag
+			}
+		}
+	}
+
+	allowed := reportLevelDimensions[level]
+	for _, f := range fields {
+		if !slices.Contains(allowed, f) {
+			if !slices.ContainsFunc(reportDimensions, func(d reportDimension) bool { return d.Field == f }) {
+				return fmt.Errorf("unknown group-by field %q (expected one of: %s)", f, strings.Join(allowed, ", "))
+			}
+			return fmt.Errorf("%s isn't available for %s reports (allowed: %s)", flagOf[f], level, strings.Join(allowed, ", "))
+		}
+	}
+	if slices.Contains(fields, "countryOrRegion") && slices.Contains(fields, "countryCode") {
+		return fmt.Errorf("group by countryOrRegion or countryCode, not both")
+	}
+	if (slices.Contains(fields, "adminArea") || slices.Contains(fields, "locality")) &&
+		!slices.Contains(fields, "countryOrRegion") && !slices.Contains(fields, "countryCode") {
+		return fmt.Errorf("adminArea and locality need a country dimension too (add --by-country)")
+	}
+
+	rptGroupBy = strings.Join(fields, ",")
+	return nil
+}
+
+// dimensionValue returns a row's value for a group-by field, or "" when the
+// row has none.
+func dimensionValue(meta map[string]interface{}, field string) string {
+	if v, ok := meta[field]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/checkpoint"
+	"github.com/trebuhs/asa-cli/internal/executor"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var reportsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a report for several campaigns to files",
+	Long: `Pull an ad group, keyword, or search term report for each campaign and write
+it to <dir>/<level>-<campaignId>.json. Campaigns are fetched concurrently
+(see --concurrency), backing off when rate limited. Each finished campaign is recorded in a
+checkpoint file; if the export stops part-way, re-run the same command with
+--resume to continue with the remaining campaigns.
+
+--limit caps the rows per campaign. With --all every row is fetched, --limit
+rows at a time, and each page is written straight to the file instead of being
+held in memory, so even 500k-row keyword reports use little memory. Files are
+written as <file>.partial and renamed when complete.`,
+	RunE: runReportsExport,
+}
+
+var (
+	rptExportCampaigns string
+	rptExportLevel     string
+	rptExportDir       string
+	rptExportResume    bool
+	rptExportTag       string
+	rptExportAll       bool
+)
+
+func init() {
+	reportsExportCmd.Flags().StringVar(&rptExportCampaigns, "campaign-ids", "", "Comma-separated campaign IDs")
+	reportsExportCmd.Flags().StringVar(&rptExportTag, "tag", "", "Export campaigns with this local tag")
+	reportsExportCmd.Flags().StringVar(&rptExportLevel, "level", "keywords", "Report level: adgroups, keywords, or search-terms")
+	reportsExportCmd.Flags().StringVar(&rptExportDir, "dir", ".", "Output directory")
+	reportsExportCmd.Flags().BoolVar(&rptExportResume, "resume", false, "Continue an interrupted export from its checkpoint")
+	reportsExportCmd.Flags().StringVar(&rptStartDate, "start-date", "", "Start date (YYYY-MM-DD) (required)")
+	reportsExportCmd.Flags().StringVar(&rptEndDate, "end-date", "", "End date (YYYY-MM-DD) (required)")
+	enumVar(reportsExportCmd.Flags(), &rptGranularity, "granularity", "", models.ParseGranularity, "Granularity: HOURLY, DAILY, WEEKLY, MONTHLY")
+	reportsExportCmd.Flags().StringVar(&rptGroupBy, "group-by", "", "Comma-separated group by fields (e.g. countryOrRegion,deviceClass)")
+	reportsExportCmd.Flags().IntVar(&rptLimit, "limit", 1000, "Result limit per campaign (page size with --all)")
+	reportsExportCmd.Flags().BoolVar(&rptExportAll, "all", false, "Fetch every row page by page, streaming them to the file")
+	addConcurrencyFlag(reportsExportCmd)
+	addThresholdFlags(reportsExportCmd)
+	addDimensionFlags(reportsExportCmd)
+	addReportSelectorFlags(reportsExportCmd)
+	reportsExportCmd.MarkFlagsOneRequired("campaign-ids", "tag")
+	reportsExportCmd.MarkFlagsMutuallyExclusive("campaign-ids", "tag")
+	reportsExportCmd.MarkFlagRequired("start-date")
+	reportsExportCmd.MarkFlagRequired("end-date")
+
+	reportsCmd.AddCommand(reportsExportCmd)
+}
+
+type reportExportRow struct {
+	CampaignID int64  `json:"campaignId"`
+	File       string `json:"file"`
+	Rows       int    `json:"rows"`
+}
+
+func runReportsExport(cmd *cobra.Command, args []string) error {
+	if err := checkReportFilters(); err != nil {
+		return err
+	}
+	var ids []int64
+	var err error
+	if rptExportTag != "" {
+		ids, err = campaignIDsForTag(rptExportTag)
+	} else {
+		ids, err = parseIDList(rptExportCampaigns)
+	}
+	if err != nil {
+		return err
+	}
+
+	var fetch func(s *services.ReportingService, id int64, req *models.ReportRequest) (*models.ReportingDataResponse, error)
+	level := rptExportLevel
+	switch rptExportLevel {
+	case "adgroups":
+		fetch = func(s *services.ReportingService, id int64, req *models.ReportRequest) (*models.ReportingDataResponse, error) {
+			return s.GetAdGroupReport(id, req)
+		}
+	case "keywords":
+		fetch = func(s *services.ReportingService, id int64, req *models.ReportRequest) (*models.ReportingDataResponse, error) {
+			return s.GetKeywordReport(id, req)
+		}
+	case "search-terms":
+		level = "searchterms"
+		fetch = func(s *services.ReportingService, id int64, req *models.ReportRequest) (*models.ReportingDataResponse, error) {
+			return s.GetSearchTermReport(id, req)
+		}
+	default:
+		return fmt.Errorf("invalid --level %q (expected adgroups, keywords, or search-terms)", rptExportLevel)
+	}
+	if err := resolveGroupBy(rptExportLevel); err != nil {
+		return err
+	}
+	if rptExportAll && rptLimit <= 0 {
+		return fmt.Errorf("--limit must be positive with --all")
+	}
+
+	if err := os.MkdirAll(explainPath(rptExportDir), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	dir, err := filepath.Abs(rptExportDir)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	keyParts := []string{profileName, globalOrgID, rptExportLevel, fmt.Sprint(ids), rptStartDate, rptEndDate,
+		string(rptGranularity), rptGroupBy, strconv.Itoa(rptLimit), dir}
+	if rptExportAll {
+		keyParts = append(keyParts, "all")
+	}
+	if !rptThresholds.IsZero() {
+		keyParts = append(keyParts, fmt.Sprint(rptThresholds))
+	}
+	opKey := checkpoint.Key(keyParts...)
+	cp, err := checkpoint.Open("reports-export", opKey, rptExportResume)
+	if err != nil {
+		return err
+	}
+
+	reports := services.NewReportingService(client)
+	rows := make([]*reportExportRow, len(ids))
+	var pending []int
+	for i, id := range ids {
+		item := strconv.FormatInt(id, 10)
+		if !cp.IsDone(item) {
+			pending = append(pending, i)
+			continue
+		}
+		rows[i] = &reportExportRow{}
+		if err := cp.Result(item, rows[i]); err != nil {
+			return err
+		}
+	}
+
+	exec := newExecutor("Exporting reports", 1, true)
+	executor.Batches(exec, executor.Read, pending, func(batch []int) error {
+		id := ids[batch[0]]
+		row := reportExportRow{CampaignID: id, File: filepath.Join(dir, fmt.Sprintf("%s-%d.json", rptExportLevel, id))}
+		if rptExportAll {
+			n, err := streamReport(reports, id, level, explainPath(row.File))
+			if err != nil {
+				return fmt.Errorf("exporting %s report for campaign %d: %w", rptExportLevel, id, err)
+			}
+			row.Rows = n
+		} else {
+			resp, err := fetch(reports, id, buildReportRequest())
+			if err != nil {
+				return fmt.Errorf("getting %s report for campaign %d: %w", rptExportLevel, id, err)
+			}
+			resp = filterReport(resp)
+			if resp != nil {
+				row.Rows = len(resp.Row)
+			}
+			data, err := json.MarshalIndent(resp, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding report: %w", err)
+			}
+			if err := os.WriteFile(explainPath(row.File), data, 0644); err != nil {
+				return fmt.Errorf("writing %s: %w", row.File, err)
+			}
+		}
+		if err := cp.MarkDone(strconv.FormatInt(id, 10), row); err != nil {
+			return err
+		}
+		rows[batch[0]] = &row
+		return nil
+	}, nil)
+	stats, err := exec.Wait()
+	printThroughput(stats)
+
+	var results []reportExportRow
+	for _, row := range rows {
+		if row != nil {
+			results = append(results, *row)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w; %d of %d campaign(s) exported, re-run with --resume to continue", err, len(results), len(ids))
+	}
+
+	if err := cp.Remove(); err != nil {
+		return err
+	}
+
+	output.Print(getFormat(), results, []output.Column{
+		{Header: "CAMPAIGN ID", Field: "CampaignID"},
+		{Header: "ROWS", Field: "Rows"},
+		{Header: "FILE", Field: "File"},
+	})
+	return nil
+}
+
+// streamReport fetches every row of a campaign's report a page at a time and
+// writes each page to path as it arrives, in the same JSON shape as a single
+// report response. Rows below the report thresholds are dropped per page. It
+// writes to path.partial and renames it once complete, and returns the number
+// of rows written.
+func streamReport(reports *services.ReportingService, id int64, level, path string) (int, error) {
+	tmp := path + ".partial"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+	w := bufio.NewWriter(f)
+
+	rows, dropped := 0, 0
+	var totals *models.ReportRow
+	w.WriteString("{\n  \"row\": [")
+	err = reports.EachPage(id, level, buildReportRequest(), func(page *models.ReportingDataResponse) error {
+		if page.GrandTotals != nil {
+			totals = page.GrandTotals
+		}
+		page, n := rptThresholds.Filter(page)
+		dropped += n
+		for _, r := range page.Row {
+			data, err := json.MarshalIndent(r, "    ", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding report row: %w", err)
+			}
+			if rows > 0 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n    ")
+			w.Write(data)
+			rows++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if rows > 0 {
+		w.WriteString("\n  ")
+	}
+	w.WriteString("]")
+	if totals != nil {
+		data, err := json.MarshalIndent(totals, "  ", "  ")
+		if err != nil {
+			return 0, fmt.Errorf("encoding report totals: %w", err)
+		}
+		w.WriteString(",\n  \"grandTotals\": ")
+		w.Write(data)
+	}
+	w.WriteString("\n}")
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, err
+	}
+	if dropped > 0 {
+		fmt.Fprintf(os.Stderr, "Dropped %d row(s) of campaign %d below the report thresholds.\n", dropped, id)
+	}
+	return rows, nil
+}
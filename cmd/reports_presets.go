@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var reportsSavePresetCmd = &cobra.Command{
+	Use:   "save-preset <name>",
+	Short: "Save a report query as a named preset",
+	Long: `Save a report query under a name in config.yaml (report_presets:) so it can be
+re-run with "reports run <name>". Because presets live in the config file,
+they can be shared across a team.
+
+Example:
+  asa-cli reports save-preset weekly-keywords --level keywords --campaign-id 123 \
+    --range last-7d --group-by countryOrRegion --metrics spend,installs,cpi`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReportsSavePreset,
+}
+
+var reportsRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a saved report preset",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runReportsRun,
+}
+
+var reportsPresetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "List saved report presets",
+	RunE:  runReportsPresets,
+}
+
+var reportsDeletePresetCmd = &cobra.Command{
+	Use:   "delete-preset <name>",
+	Short: "Delete a saved report preset",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runReportsDeletePreset,
+}
+
+var (
+	presetLevel       string
+	presetRange       string
+	presetCampaignID  int64
+	presetGroupBy     string
+	presetMetrics     string
+	presetGranularity models.Granularity
+	presetLimit       int
+	runRange          string
+	runCampaignID     int64
+)
+
+func init() {
+	reportsSavePresetCmd.Flags().StringVar(&presetLevel, "level", "campaigns", "Report level: campaigns, adgroups, keywords, or search-terms")
+	reportsSavePresetCmd.Flags().StringVar(&presetRange, "range", "last-7d", "Date range: "+daterange.Help)
+	reportsSavePresetCmd.Flags().Int64Var(&presetCampaignID, "campaign-id", 0, "Campaign ID (required below campaign level)")
+	reportsSavePresetCmd.Flags().StringVar(&presetGroupBy, "group-by", "", "Comma-separated group by fields (e.g. countryOrRegion)")
+	reportsSavePresetCmd.Flags().StringVar(&presetMetrics, "metrics", "impressions,taps,installs,spend,cpi", "Comma-separated metrics: "+strings.Join(aggregate.MetricNames, ", "))
+	enumVar(reportsSavePresetCmd.Flags(), &presetGranularity, "granularity", "", models.ParseGranularity, "Granularity: HOURLY, DAILY, WEEKLY, MONTHLY")
+	reportsSavePresetCmd.Flags().IntVar(&presetLimit, "limit", 1000, "Result limit")
+
+	reportsRunCmd.Flags().StringVar(&runRange, "range", "", "Override the preset's date range")
+	reportsRunCmd.Flags().Int64Var(&runCampaignID, "campaign-id", 0, "Override the preset's campaign ID")
+	addThresholdFlags(reportsRunCmd)
+
+	reportsCmd.AddCommand(reportsSavePresetCmd, reportsRunCmd, reportsPresetsCmd, reportsDeletePresetCmd)
+}
+
+// reportLevelKeys returns the metadata keys identifying rows at a report level.
+func reportLevelKeys(level string) (idKey, nameKey string, err error) {
+	switch level {
+	case "campaigns":
+		return "campaignId", "campaignName", nil
+	case "adgroups":
+		return "adGroupId", "adGroupName", nil
+	case "keywords":
+		return "keywordId", "keyword", nil
+	case "search-terms":
+		return "", "searchTermText", nil
+	}
+	return "", "", fmt.Errorf("invalid level %q (expected campaigns, adgroups, keywords, or search-terms)", level)
+}
+
+func runReportsSavePreset(cmd *cobra.Command, args []string) error {
+	preset := config.ReportPreset{
+		Level:       strings.ToLower(presetLevel),
+		Range:       presetRange,
+		CampaignID:  presetCampaignID,
+		GroupBy:     splitList(presetGroupBy),
+		Metrics:     splitList(strings.ToLower(presetMetrics)),
+		Granularity: string(presetGranularity),
+		Limit:       presetLimit,
+	}
+	if err := validatePreset(preset); err != nil {
+		return err
+	}
+	name := strings.ToLower(args[0])
+	if err := config.SaveReportPreset(name, preset); err != nil {
+		return fmt.Errorf("saving preset: %w", err)
+	}
+	fmt.Printf("Preset '%s' saved. Run it with: asa-cli reports run %s\n", name, name)
+	return nil
+}
+
+func validatePreset(p config.ReportPreset) error {
+	if _, _, err := reportLevelKeys(p.Level); err != nil {
+		return err
+	}
+	if p.Level != "campaigns" && p.CampaignID == 0 {
+		return fmt.Errorf("--campaign-id is required for %s reports", p.Level)
+	}
+	if _, err := daterange.Parse(p.Range, time.Now()); err != nil {
+		return err
+	}
+	if _, err := models.ParseGranularity(p.Granularity); err != nil {
+		return err
+	}
+	_, err := aggregate.ParseMetrics(p.Metrics)
+	return err
+}
+
+func runReportsRun(cmd *cobra.Command, args []string) error {
+	presets, err := config.ReportPresets()
+	if err != nil {
+		return err
+	}
+	preset, ok := presets[strings.ToLower(args[0])]
+	if !ok {
+		return fmt.Errorf("report preset %q not found (see: asa-cli reports presets)", args[0])
+	}
+	if runRange != "" {
+		preset.Range = runRange
+	}
+	if runCampaignID != 0 {
+		preset.CampaignID = runCampaignID
+	}
+	if len(preset.Metrics) == 0 {
+		preset.Metrics = []string{"impressions", "taps", "installs", "spend", "cpi"}
+	}
+	if preset.Limit == 0 {
+		preset.Limit = 1000
+	}
+	if err := validatePreset(preset); err != nil {
+		return fmt.Errorf("preset %q: %w", args[0], err)
+	}
+
+	rng, _ := daterange.Parse(preset.Range, time.Now())
+	req := newRangeReportRequest(rng, preset.Limit)
+	req.GroupBy = preset.GroupBy
+	req.Granularity, _ = models.ParseGranularity(preset.Granularity)
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	svc := services.NewReportingService(client)
+	var resp *models.ReportingDataResponse
+	switch preset.Level {
+	case "campaigns":
+		resp, err = svc.GetCampaignReport(req)
+	case "adgroups":
+		resp, err = svc.GetAdGroupReport(preset.CampaignID, req)
+	case "keywords":
+		resp, err = svc.GetKeywordReport(preset.CampaignID, req)
+	case "search-terms":
+		resp, err = svc.GetSearchTermReport(preset.CampaignID, req)
+	}
+	if err != nil {
+		return fmt.Errorf("getting %s report: %w", preset.Level, err)
+	}
+
+	return printPresetReport(filterReport(resp), preset)
+}
+
+func printPresetReport(resp *models.ReportingDataResponse, preset config.ReportPreset) error {
+	idKey, nameKey, _ := reportLevelKeys(preset.Level)
+	metrics, err := aggregate.ParseMetrics(preset.Metrics)
+	if err != nil {
+		return err
+	}
+
+	var headers []string
+	if idKey != "" {
+		headers = append(headers, "ID")
+	}
+	headers = append(headers, "NAME")
+	if len(preset.GroupBy) > 0 {
+		headers = append(headers, strings.ToUpper(strings.Join(preset.GroupBy, " / ")))
+	}
+	for _, d := range metrics {
+		headers = append(headers, d.Header)
+	}
+
+	var rows [][]string
+	records := []map[string]interface{}{}
+	if resp != nil {
+		for _, r := range resp.Row {
+			m := aggregate.RowTotals(r)
+			name := aggregate.MetaString(r.Metadata, nameKey)
+			rec := map[string]interface{}{"name": name}
+			var cells []string
+			if idKey != "" {
+				id := aggregate.MetaInt64(r.Metadata, idKey)
+				rec["id"] = id
+				cells = append(cells, fmt.Sprint(id))
+			}
+			cells = append(cells, name)
+			var group []string
+			for _, g := range preset.GroupBy {
+				v := fmt.Sprint(r.Metadata[g])
+				rec[g] = v
+				group = append(group, v)
+			}
+			if len(preset.GroupBy) > 0 {
+				cells = append(cells, strings.Join(group, " / "))
+			}
+			for _, d := range metrics {
+				rec[d.Name] = d.Compute(m)
+				cells = append(cells, d.Format(m))
+			}
+			rows = append(rows, cells)
+			records = append(records, rec)
+		}
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, records, nil)
+		return nil
+	}
+	output.PrintGrid(getFormat(), headers, rows)
+	return nil
+}
+
+type presetListRow struct {
+	Name    string              `json:"name"`
+	Preset  config.ReportPreset `json:"preset"`
+	Level   string              `json:"-"`
+	Range   string              `json:"-"`
+	GroupBy string              `json:"-"`
+	Metrics string              `json:"-"`
+}
+
+func runReportsPresets(cmd *cobra.Command, args []string) error {
+	presets, err := config.ReportPresets()
+	if err != nil {
+		return err
+	}
+	rows := []presetListRow{}
+	for name, p := range presets {
+		rows = append(rows, presetListRow{
+			Name:    name,
+			Preset:  p,
+			Level:   p.Level,
+			Range:   p.Range,
+			GroupBy: strings.Join(p.GroupBy, ","),
+			Metrics: strings.Join(p.Metrics, ","),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "NAME", Field: "Name"},
+		{Header: "LEVEL", Field: "Level"},
+		{Header: "RANGE", Field: "Range"},
+		{Header: "GROUP BY", Field: "GroupBy"},
+		{Header: "METRICS", Field: "Metrics"},
+	})
+	return nil
+}
+
+func runReportsDeletePreset(cmd *cobra.Command, args []string) error {
+	presets, err := config.ReportPresets()
+	if err != nil {
+		return err
+	}
+	name := strings.ToLower(args[0])
+	if _, ok := presets[name]; !ok {
+		return fmt.Errorf("report preset %q not found", args[0])
+	}
+	if err := config.RemoveReportPreset(name); err != nil {
+		return fmt.Errorf("deleting preset: %w", err)
+	}
+	fmt.Printf("Preset '%s' deleted.\n", args[0])
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/export"
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// exportReport writes a report to the --export destination.
+func exportReport(resp *models.ReportingDataResponse, level string) error {
+	if explainSkip("export to " + rptExport) {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	table, err := reportTable(resp, level)
+	if err != nil {
+		return err
+	}
+	msg, err := export.Write(rptExport, table, export.Options{GoogleCredentials: cfg.GoogleServiceAccountPath})
+	if err != nil {
+		return fmt.Errorf("exporting report: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, msg+".")
+	return nil
+}
+
+// reportTable flattens a report into one row per entity, group, and date,
+// with a column per selected metric (see --metrics) and a currency column
+// after the first money metric. Rows without granularity buckets are dated by
+// the report's start date. Search term rows use the keyword ID as their
+// entity ID, so the term text is part of their key.
+func reportTable(resp *models.ReportingDataResponse, level string) (*export.Table, error) {
+	idKey, nameKey, err := reportLevelKeys(level)
+	if err != nil {
+		return nil, err
+	}
+	if idKey == "" {
+		idKey = "keywordId"
+	}
+	metrics, err := reportMetrics()
+	if err != nil {
+		return nil, err
+	}
+	groupBy := splitList(rptGroupBy)
+
+	t := &export.Table{
+		Columns: []string{"date", "entity_id", "name"},
+		Key:     append([]string{"date", "entity_id"}, groupBy...),
+	}
+	if level == "search-terms" {
+		t.Key = append(t.Key, "name")
+	}
+	t.Columns = append(t.Columns, groupBy...)
+	currencyAt := -1
+	for i, d := range metrics {
+		t.Columns = append(t.Columns, d.Name)
+		if d.Kind == aggregate.KindMoney && currencyAt < 0 {
+			currencyAt = i
+			t.Columns = append(t.Columns, "currency")
+		}
+	}
+
+	for _, line := range reportLines(resp) {
+		cells := []interface{}{line.Date, aggregate.MetaInt64(line.Row.Metadata, idKey), aggregate.MetaString(line.Row.Metadata, nameKey)}
+		for _, g := range groupBy {
+			cells = append(cells, dimensionValue(line.Row.Metadata, g))
+		}
+		for i, d := range metrics {
+			v := d.Compute(line.Metrics)
+			if d.Kind == aggregate.KindCount {
+				cells = append(cells, int64(v))
+			} else {
+				cells = append(cells, v)
+			}
+			if i == currencyAt {
+				cells = append(cells, line.Metrics.Currency)
+			}
+		}
+		t.Rows = append(t.Rows, cells)
+	}
+	return t, nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+// reportLine is one entity (and group-by value) on one date of a report.
+type reportLine struct {
+	Date    string
+	Row     models.ReportRow
+	Metrics aggregate.Metrics
+}
+
+// reportLines flattens a report into a line per granularity bucket, or a
+// line per row dated by the report's start date when there are no buckets.
+func reportLines(resp *models.ReportingDataResponse) []reportLine {
+	if resp == nil {
+		return nil
+	}
+	var lines []reportLine
+	for _, row := range resp.Row {
+		if len(row.Granularity) == 0 {
+			lines = append(lines, reportLine{Date: rptStartDate, Row: row, Metrics: aggregate.RowTotals(row)})
+			continue
+		}
+		for _, g := range row.Granularity {
+			var m aggregate.Metrics
+			m.Add(g.Metrics)
+			lines = append(lines, reportLine{Date: g.Date, Row: row, Metrics: m})
+		}
+	}
+	return lines
+}
+
+// reportMetrics resolves --metrics.
+func reportMetrics() ([]aggregate.Metric, error) {
+	return aggregate.ParseMetrics(splitList(strings.ToLower(rptMetrics)))
+}
+
+// printReportTable prints a report with leading columns for the group-by
+// dimensions, a date column when the report has granularity, a column per
+// selected metric, and a TOTAL row for --grand-totals.
+func printReportTable(resp *models.ReportingDataResponse, level string) error {
+	idKey, nameKey, err := reportLevelKeys(level)
+	if err != nil {
+		return err
+	}
+	metrics, err := reportMetrics()
+	if err != nil {
+		return err
+	}
+	groupBy := splitList(rptGroupBy)
+	dated := hasGranularity(resp)
+	lines := reportLines(resp)
+
+	currency := ""
+	for _, l := range lines {
+		if c := l.Metrics.Currency; c != "" {
+			if currency != "" && c != currency {
+				currency = ""
+				break
+			}
+			currency = c
+		}
+	}
+
+	var headers []string
+	for _, g := range groupBy {
+		headers = append(headers, dimensionHeader(g))
+	}
+	if dated {
+		headers = append(headers, "DATE")
+	}
+	if idKey != "" {
+		headers = append(headers, "ID")
+	}
+	headers = append(headers, "NAME")
+	for _, d := range metrics {
+		h := d.Header
+		if d.Kind == aggregate.KindMoney && currency != "" {
+			h += " " + currency
+		}
+		headers = append(headers, h)
+	}
+
+	row := func(date, id, name string, meta map[string]interface{}, m aggregate.Metrics) []string {
+		var cells []string
+		for _, g := range groupBy {
+			cells = append(cells, dimensionValue(meta, g))
+		}
+		if dated {
+			cells = append(cells, date)
+		}
+		if idKey != "" {
+			cells = append(cells, id)
+		}
+		cells = append(cells, name)
+		for _, d := range metrics {
+			cells = append(cells, d.Format(m))
+		}
+		return cells
+	}
+
+	var rows [][]string
+	for _, l := range lines {
+		id := ""
+		if idKey != "" {
+			id = fmt.Sprint(aggregate.MetaInt64(l.Row.Metadata, idKey))
+		}
+		rows = append(rows, row(l.Date, id, aggregate.MetaString(l.Row.Metadata, nameKey), l.Row.Metadata, l.Metrics))
+	}
+	if resp.GrandTotals != nil {
+		rows = append(rows, row("", "", "TOTAL", nil, aggregate.RowTotals(*resp.GrandTotals)))
+	}
+	output.PrintGrid(getFormat(), headers, rows)
+	return nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// rptThresholds holds the row thresholds shared by the report commands.
+var rptThresholds aggregate.Thresholds
+
+// addThresholdFlags adds --min-spend, --min-impressions, and --max-cpi.
+func addThresholdFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&rptThresholds.MinSpend, "min-spend", 0, "Drop rows that spent less than this")
+	cmd.Flags().Int64Var(&rptThresholds.MinImpressions, "min-impressions", 0, "Drop rows with fewer impressions than this")
+	cmd.Flags().Float64Var(&rptThresholds.MaxCPI, "max-cpi", 0, "Drop rows with a higher CPI, or with spend but no installs")
+}
+
+// filterReport applies the row thresholds to a fetched report, before it is
+// printed, charted, or exported, and notes on stderr how many rows it dropped.
+func filterReport(resp *models.ReportingDataResponse) *models.ReportingDataResponse {
+	resp, dropped := rptThresholds.Filter(resp)
+	if dropped > 0 {
+		fmt.Fprintf(os.Stderr, "Dropped %d row(s) below the report thresholds.\n", dropped)
+	}
+	return resp
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var reportsTreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Campaign → ad group → keyword rollup in one view",
+	Long: `Fetch the campaign, ad group, and keyword reports for a campaign concurrently
+and nest them: each ad group under the campaign and each keyword under its ad
+group, with every entity's share of its parent's spend.
+
+Table output is an indented tree; JSON output is the nested structure.`,
+	RunE: runReportsTree,
+}
+
+var (
+	treeCampaignID int64
+	treeRange      string
+	treeLimit      int
+)
+
+func init() {
+	reportsTreeCmd.Flags().Int64Var(&treeCampaignID, "campaign-id", 0, "Campaign ID (required)")
+	reportsTreeCmd.Flags().StringVar(&treeRange, "range", "last-7d", "Date range: "+daterange.Help)
+	reportsTreeCmd.Flags().IntVar(&treeLimit, "limit", 1000, "Result limit per level")
+	reportsTreeCmd.MarkFlagRequired("campaign-id")
+
+	reportsCmd.AddCommand(reportsTreeCmd)
+}
+
+type treeRow struct {
+	Name     string
+	ID       int64
+	Spend    string
+	Share    string
+	Installs int64
+	CPI      string
+}
+
+func runReportsTree(cmd *cobra.Command, args []string) error {
+	rng, err := daterange.Parse(treeRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewReportingService(client)
+
+	campaignReq := newRangeReportRequest(rng, treeLimit)
+	campaignReq.Selector.Conditions = []models.Condition{
+		{Field: "campaignId", Operator: "IN", Values: []string{strconv.FormatInt(treeCampaignID, 10)}},
+	}
+
+	var (
+		wg                            sync.WaitGroup
+		campaigns, adgroups, keywords *models.ReportingDataResponse
+		errs                          [3]error
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		campaigns, errs[0] = svc.GetCampaignReport(campaignReq)
+	}()
+	go func() {
+		defer wg.Done()
+		adgroups, errs[1] = svc.GetAdGroupReport(treeCampaignID, newRangeReportRequest(rng, treeLimit))
+	}()
+	go func() {
+		defer wg.Done()
+		keywords, errs[2] = svc.GetKeywordReport(treeCampaignID, newRangeReportRequest(rng, treeLimit))
+	}()
+	wg.Wait()
+
+	for i, level := range []string{"campaign", "ad group", "keyword"} {
+		if errs[i] != nil {
+			return fmt.Errorf("getting %s report: %w", level, errs[i])
+		}
+	}
+
+	tree := aggregate.Tree(treeCampaignID, campaigns, adgroups, keywords)
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, tree, nil)
+		return nil
+	}
+
+	var rows []treeRow
+	flattenTree(tree, 0, &rows)
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "NAME", Field: "Name"},
+		{Header: "ID", Field: "ID"},
+		{Header: "SPEND", Field: "Spend"},
+		{Header: "% OF PARENT", Field: "Share"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "CPI", Field: "CPI"},
+	})
+	return nil
+}
+
+// flattenTree lists a tree depth-first with names indented by level.
+func flattenTree(n *aggregate.Node, depth int, rows *[]treeRow) {
+	name := n.Name
+	if name == "" {
+		name = fmt.Sprintf("(%s %d)", n.Level, n.ID)
+	}
+	name = output.TreePrefix(depth) + name
+	*rows = append(*rows, treeRow{
+		Name:     name,
+		ID:       n.ID,
+		Spend:    fmt.Sprintf("%.2f", n.Spend),
+		Share:    fmt.Sprintf("%.1f%%", n.Share*100),
+		Installs: n.Installs,
+		CPI:      fmt.Sprintf("%.2f", n.CPI()),
+	})
+	for _, c := range n.Children {
+		flattenTree(c, depth+1, rows)
+	}
+}
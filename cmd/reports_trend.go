@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var reportsTrendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Weekly trend of a metric per entity",
+	Long: `Show a weekly time series of one metric for each campaign, ad group, or
+keyword, as a sparkline with the first and last week's values. Useful for
+spotting drift (e.g. creeping CPI) without exporting to a spreadsheet.
+
+The window covers the last --weeks full weeks ending yesterday.`,
+	RunE: runReportsTrend,
+}
+
+var (
+	trendLevel      string
+	trendMetric     string
+	trendWeeks      int
+	trendCampaignID int64
+	trendTop        int
+)
+
+func init() {
+	reportsTrendCmd.Flags().StringVar(&trendLevel, "level", "campaigns", "Report level: campaigns, adgroups, or keywords")
+	reportsTrendCmd.Flags().StringVar(&trendMetric, "metric", "spend", "Metric: "+strings.Join(aggregate.MetricNames, ", "))
+	reportsTrendCmd.Flags().IntVar(&trendWeeks, "weeks", 12, "Number of weeks")
+	reportsTrendCmd.Flags().Int64Var(&trendCampaignID, "campaign-id", 0, "Campaign ID (required for adgroups and keywords)")
+	reportsTrendCmd.Flags().IntVar(&trendTop, "top", 20, "Show the entities with the most spend over the window (0 for all)")
+
+	reportsCmd.AddCommand(reportsTrendCmd)
+}
+
+// TrendPoint is one week of a trend series.
+type TrendPoint struct {
+	Week  string  `json:"week"`
+	Value float64 `json:"value"`
+}
+
+// EntityTrend is a metric's weekly series for one entity.
+type EntityTrend struct {
+	ID     int64        `json:"id"`
+	Name   string       `json:"name"`
+	Metric string       `json:"metric"`
+	Points []TrendPoint `json:"points"`
+
+	Sparkline string `json:"-"`
+	First     string `json:"-"`
+	Last      string `json:"-"`
+	Change    string `json:"-"`
+
+	spend float64
+}
+
+func runReportsTrend(cmd *cobra.Command, args []string) error {
+	metric := strings.ToLower(trendMetric)
+	if _, ok := (aggregate.Metrics{}).Value(metric); !ok {
+		return fmt.Errorf("unknown metric %q (expected %s)", trendMetric, strings.Join(aggregate.MetricNames, ", "))
+	}
+	if trendWeeks <= 0 {
+		return fmt.Errorf("--weeks must be positive")
+	}
+	idKey, nameKey, err := reportLevelKeys(trendLevel)
+	if err != nil || trendLevel == "search-terms" {
+		return fmt.Errorf("invalid level %q (expected campaigns, adgroups, or keywords)", trendLevel)
+	}
+	if trendLevel != "campaigns" && trendCampaignID == 0 {
+		return fmt.Errorf("--campaign-id is required for %s trends", trendLevel)
+	}
+
+	rng, err := daterange.Parse(fmt.Sprintf("last-%dw", trendWeeks), time.Now())
+	if err != nil {
+		return err
+	}
+	req := newRangeReportRequest(rng, 1000)
+	req.Granularity = "WEEKLY"
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	svc := services.NewReportingService(client)
+
+	var resp *models.ReportingDataResponse
+	switch trendLevel {
+	case "campaigns":
+		resp, err = svc.GetCampaignReport(req)
+	case "adgroups":
+		resp, err = svc.GetAdGroupReport(trendCampaignID, req)
+	case "keywords":
+		resp, err = svc.GetKeywordReport(trendCampaignID, req)
+	}
+	if err != nil {
+		return fmt.Errorf("getting %s report: %w", trendLevel, err)
+	}
+
+	trends := buildTrends(resp, idKey, nameKey, metric)
+	if trendTop > 0 && len(trends) > trendTop {
+		trends = trends[:trendTop]
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, trends, nil)
+		return nil
+	}
+	output.Print(getFormat(), trends, []output.Column{
+		{Header: "ID", Field: "ID"},
+		{Header: "NAME", Field: "Name"},
+		{Header: strings.ToUpper(metric) + " (WEEKLY)", Field: "Sparkline"},
+		{Header: "FIRST", Field: "First"},
+		{Header: "LAST", Field: "Last"},
+		{Header: "CHANGE", Field: "Change"},
+	})
+	return nil
+}
+
+// buildTrends turns weekly granularity rows into one series per entity,
+// sorted by spend over the window.
+func buildTrends(resp *models.ReportingDataResponse, idKey, nameKey, metric string) []EntityTrend {
+	trends := []EntityTrend{}
+	if resp == nil {
+		return trends
+	}
+	index := map[int64]int{}
+	for _, row := range resp.Row {
+		id := aggregate.MetaInt64(row.Metadata, idKey)
+		i, ok := index[id]
+		if !ok {
+			i = len(trends)
+			index[id] = i
+			trends = append(trends, EntityTrend{ID: id, Name: aggregate.MetaString(row.Metadata, nameKey), Metric: metric})
+		}
+		for _, g := range row.Granularity {
+			var m aggregate.Metrics
+			m.Add(g.Metrics)
+			v, _ := m.Value(metric)
+			trends[i].Points = append(trends[i].Points, TrendPoint{Week: g.Date, Value: v})
+			trends[i].spend += m.Spend
+		}
+	}
+
+	for i := range trends {
+		t := &trends[i]
+		sort.Slice(t.Points, func(a, b int) bool { return t.Points[a].Week < t.Points[b].Week })
+		values := make([]float64, len(t.Points))
+		for j, p := range t.Points {
+			values[j] = p.Value
+		}
+		t.Sparkline = output.Sparkline(values)
+		if len(values) > 0 {
+			first, last := values[0], values[len(values)-1]
+			t.First = formatMetric(metric, first)
+			t.Last = formatMetric(metric, last)
+			t.Change = pctChange(last, first)
+		}
+	}
+	sort.SliceStable(trends, func(a, b int) bool { return trends[a].spend > trends[b].spend })
+	return trends
+}
+
+// formatMetric formats a metric value for display.
+func formatMetric(metric string, v float64) string {
+	switch metric {
+	case "impressions", "taps", "installs":
+		return fmt.Sprintf("%.0f", v)
+	case "ttr", "cr":
+		return fmt.Sprintf("%.2f%%", v*100)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
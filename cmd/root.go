@@ -12,12 +12,17 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aclcache"
 	"github.com/trebuhs/asa-cli/internal/api"
 	"github.com/trebuhs/asa-cli/internal/auth"
 	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/credentials"
 	"github.com/trebuhs/asa-cli/internal/models"
 	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/progress"
+	"github.com/trebuhs/asa-cli/internal/query"
 	"github.com/trebuhs/asa-cli/internal/services"
+	"github.com/trebuhs/asa-cli/internal/usage"
 )
 
 var (
@@ -27,59 +32,235 @@ var (
 	noColor      bool
 	globalOrgID  string
 	forceFlag    bool
+	quietFlag    bool
+	noProgress   bool
+	quotaLimit   int64
+	enforceQuota bool
+	nullDelim    bool
+	whereExprs   []string
+	clientSorts  []string
+	strictDecode bool
+	readOnly     bool
+	refreshACLs  bool
 )
 
+// apiClients caches authenticated clients by profile, org, and the flags
+// built into their transport, so that commands run repeatedly in one process
+// (e.g. batch) share a token while each line gets the settings it asked for.
+var apiClients = map[string]*api.Client{}
+
+// clientCacheKey is the apiClients key for org ("noorg" for clients without
+// one) under the current flags.
+func clientCacheKey(org string) string {
+	return fmt.Sprintf("%s|%s|ro=%t|v=%t|quota=%d,%t|faults=%g,%g", profileName, org, readOnly, verbose, quotaLimit, enforceQuota, faults.FailRate, faults.RateLimitRate)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "asa-cli",
 	Short: "Apple Search Ads CLI",
 	Long:  "A command-line interface for the Apple Search Ads Campaign Management API v5.",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if accountName != "" {
+			if err := applyAccount(cmd); err != nil {
+				return err
+			}
+		}
+		config.SetProfile(profileName)
+		if err := applySavedContext(cmd); err != nil {
+			return err
+		}
+		if err := applyConfigDefaults(cmd); err != nil {
+			return err
+		}
+		warnAPIVersion(cmd)
+		if !output.PrepareConsole() {
+			color.NoColor = true
+			output.ASCII = true
+		}
 		if noColor {
 			color.NoColor = true
 		}
-		config.SetProfile(profileName)
+		progress.Enabled = !noProgress && progress.IsTerminal()
+		if nullDelim && getFormat() == output.FormatJSON {
+			return fmt.Errorf("-0 cannot be used with JSON output")
+		}
+		output.NullDelimited = nullDelim
+		output.Enveloped = envelopeFlag
+		if err := setupNumberFormat(); err != nil {
+			return err
+		}
+		if err := setupCSVFormat(); err != nil {
+			return err
+		}
+		setupPager()
+		driftRecorder.Enabled = strictDecode
+		if err := faults.Validate(); err != nil {
+			return err
+		}
+		if err := checkFaults(cmd); err != nil {
+			return err
+		}
+		explainWrap.Do(func() { wrapExplain(cmd.Root()) })
+		return setupClientQuery()
+	},
+	// Output that --where or --client-sort couldn't be applied to wasn't
+	// printed; fail the command.
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return output.Err()
 	},
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format: json or table")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format: json, table, tsv, csv, or ids")
+	rootCmd.PersistentFlags().StringVar(&csvCurrencyMode, "csv-currency-mode", output.CurrencySplit, "Money in csv output: split (amount and currency columns), symbol ($12.50), or plain (amount only)")
+	rootCmd.PersistentFlags().StringVar(&dateFormat, "date-format", "", "Report dates in csv output: iso, us, eu, or a Go layout such as 02.01.2006")
+	rootCmd.PersistentFlags().StringVar(&localeFlag, "locale", "", "Number format of tables, e.g. en-US or de-DE (overrides formatting.locale)")
+	rootCmd.PersistentFlags().BoolVarP(&nullDelim, "null", "0", false, "End every field with NUL instead of tabs/newlines (tsv and ids output; implies tsv for tables)")
 	rootCmd.PersistentFlags().StringVarP(&profileName, "profile", "p", "", "Config profile name")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable color output")
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "Print long tables directly instead of through a pager")
 	rootCmd.PersistentFlags().StringVar(&globalOrgID, "org-id", "", "Organization ID (overrides config)")
+	rootCmd.PersistentFlags().StringVar(&accountName, "account", "", "Client account from accounts.yaml (sets profile and org ID)")
 	rootCmd.PersistentFlags().BoolVar(&forceFlag, "force", false, "Skip budget/bid safety checks")
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Disable progress bars on stderr")
+	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Print only entity IDs, one per line")
+	rootCmd.PersistentFlags().Int64Var(&quotaLimit, "quota-limit", 0, "Daily API call budget for the profile (overrides quota_limit)")
+	rootCmd.PersistentFlags().BoolVar(&enforceQuota, "enforce-quota", false, "Refuse API calls once the daily quota is used up")
+	rootCmd.PersistentFlags().StringArrayVar(&whereExprs, "where", nil, "Filter results after fetching, e.g. 'dailyBudgetAmount.amount > 100' (repeatable, ANDed)")
+	rootCmd.PersistentFlags().StringSliceVar(&clientSorts, "client-sort", nil, "Sort results after fetching, e.g. name:asc,dailyBudgetAmount.amount:desc")
+	rootCmd.PersistentFlags().BoolVar(&refreshACLs, "refresh-acls", false, "Fetch the org list from the API instead of the cache")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Refuse every API request that could change the account")
+	rootCmd.PersistentFlags().BoolVar(&strictDecode, "strict-decode", false, "Report API response fields the CLI doesn't know or expected fields that are missing")
 }
 
 func Execute() error {
-	if err := rootCmd.Execute(); err != nil {
+	args, err := expandWebLinks(expandAliases(os.Args[1:]))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	runs, err := expandStdinIDs(args, os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	if len(runs) == 1 {
+		return executeArgs(runs[0])
+	}
+
+	// One run per ID read from stdin: each starts from the same flag state
+	// and a failure doesn't stop the rest.
+	baseline := snapshotFlags(rootCmd)
+	failed := 0
+	for _, args := range runs {
+		restoreFlags(baseline)
+		if executeArgs(args) != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		err := fmt.Errorf("%d of %d run(s) failed", failed, len(runs))
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
 	return nil
 }
 
+// executeArgs runs one command line, records its usage, and prints its error.
+func executeArgs(args []string) error {
+	start := time.Now()
+	usage.Reset()
+	rootCmd.SetArgs(args)
+	cmd, err := rootCmd.ExecuteC()
+	if !explainFlag {
+		recordUsage(cmd, start, err)
+	}
+	reportDrift(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// setupClientQuery parses --where and --client-sort and has output apply
+// them to every printed list.
+func setupClientQuery() error {
+	output.Refine = nil
+	output.Err() // drop a failure left by an earlier run in this process
+	var exprs []*query.Expr
+	for _, w := range whereExprs {
+		e, err := query.Parse(w)
+		if err != nil {
+			return err
+		}
+		exprs = append(exprs, e)
+	}
+	sorts, err := query.ParseSorts(clientSorts)
+	if err != nil {
+		return err
+	}
+	if len(exprs) == 0 && len(sorts) == 0 {
+		return nil
+	}
+	where := query.And(exprs...)
+	output.Refine = func(data interface{}) (interface{}, error) {
+		return query.Apply(data, where, sorts)
+	}
+	return nil
+}
+
 // getFormat returns the output format.
 func getFormat() output.Format {
+	if quietFlag {
+		return output.FormatIDs
+	}
 	switch strings.ToLower(outputFormat) {
 	case "json":
 		return output.FormatJSON
+	case "ids":
+		return output.FormatIDs
+	case "tsv":
+		return output.FormatTSV
+	case "csv":
+		return output.FormatCSV
 	default:
+		if nullDelim {
+			return output.FormatTSV
+		}
 		return output.FormatTable
 	}
 }
 
 // newAPIClient creates an authenticated API client from config.
 func newAPIClient() (*api.Client, error) {
+	if explaining != nil {
+		return explainClient(true), nil
+	}
+	cacheKey := clientCacheKey(globalOrgID)
+	if client, ok := apiClients[cacheKey]; ok {
+		return client, nil
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
 
+	if err := credentials.Resolve(cfg); err != nil {
+		return nil, err
+	}
 	if err := auth.ValidateConfig(cfg); err != nil {
 		return nil, err
 	}
+	version, err := api.ParseVersion(cfg.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	warnKeyPermissions(cfg)
 
 	// Resolve org ID: flag > config > auto-detect
 	orgID := cfg.OrgID
@@ -87,11 +268,12 @@ func newAPIClient() (*api.Client, error) {
 		orgID = globalOrgID
 	}
 
-	tokenProvider := auth.NewTokenProvider(cfg)
+	tokenProvider := newTokenProvider(cfg)
+	quota := quotaFor(cfg)
 
 	// If no org ID configured, auto-resolve from /acls
 	if orgID == "" {
-		resolved, err := resolveOrgID(tokenProvider)
+		resolved, err := resolveOrgID(cfg, tokenProvider, quota)
 		if err != nil {
 			return nil, err
 		}
@@ -99,100 +281,177 @@ func newAPIClient() (*api.Client, error) {
 	}
 
 	transport := &auth.Transport{
-		Token:   tokenProvider,
-		OrgID:   orgID,
-		Verbose: verbose,
+		Token:    tokenProvider,
+		OrgID:    orgID,
+		Verbose:  verbose,
+		Observe:  quota.Observe,
+		Allow:    quota.Allow,
+		ReadOnly: readOnly || cfg.ReadOnly,
 	}
 
 	httpClient := &http.Client{
-		Transport: transport,
+		Transport: callLog.Transport(injectFaults(transport)),
 		Timeout:   30 * time.Second,
 	}
 
 	client := api.NewClient(httpClient)
+	client.Version = version
 	client.Verbose = verbose
+	client.Drift = driftRecorder
+	apiClients[cacheKey] = client
 	return client, nil
 }
 
 // newAPIClientNoOrg creates an authenticated client without requiring an org ID.
 // Used for commands like whoami that don't need X-AP-Context.
 func newAPIClientNoOrg() (*api.Client, error) {
+	if explaining != nil {
+		return explainClient(false), nil
+	}
+	cacheKey := clientCacheKey("noorg")
+	if client, ok := apiClients[cacheKey]; ok {
+		return client, nil
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
 
+	if err := credentials.Resolve(cfg); err != nil {
+		return nil, err
+	}
 	if err := auth.ValidateConfig(cfg); err != nil {
 		return nil, err
 	}
+	version, err := api.ParseVersion(cfg.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	warnKeyPermissions(cfg)
 
-	tokenProvider := auth.NewTokenProvider(cfg)
+	tokenProvider := newTokenProvider(cfg)
+	quota := quotaFor(cfg)
 	transport := &auth.Transport{
-		Token:   tokenProvider,
-		Verbose: verbose,
+		Token:    tokenProvider,
+		Verbose:  verbose,
+		Observe:  quota.Observe,
+		Allow:    quota.Allow,
+		ReadOnly: readOnly || cfg.ReadOnly,
 	}
 
 	httpClient := &http.Client{
-		Transport: transport,
+		Transport: callLog.Transport(injectFaults(transport)),
 		Timeout:   30 * time.Second,
 	}
 
 	client := api.NewClient(httpClient)
+	client.Version = version
 	client.Verbose = verbose
+	client.Drift = driftRecorder
+	apiClients[cacheKey] = client
 	return client, nil
 }
 
-// resolveOrgID fetches /acls and auto-selects the org if there's exactly one.
-func resolveOrgID(tokenProvider *auth.TokenProvider) (string, error) {
+// warnKeyPermissions flags a private key file readable by other users.
+func warnKeyPermissions(cfg *config.Config) {
+	if cfg.PrivateKey != "" {
+		return
+	}
+	if w := auth.KeyPermissionWarning(cfg.PrivateKeyPath); w != "" {
+		warnf("%s", w)
+	}
+}
+
+// resolveOrgID auto-selects the org if the account has exactly one, using
+// the cached /acls response when it is fresh.
+func resolveOrgID(cfg *config.Config, tokenProvider *auth.TokenProvider, quota *usage.Quota) (string, error) {
+	acls, ok := cachedACLs(cfg)
+	if !ok {
+		var err error
+		version, err := api.ParseVersion(cfg.APIVersion)
+		if err != nil {
+			return "", err
+		}
+		acls, err = fetchACLs(tokenProvider, quota, version)
+		if err != nil {
+			return "", err
+		}
+		if err := aclcache.Save(profileName, cfg.ClientID, acls); err != nil && verbose {
+			warnf("%v", err)
+		}
+	}
+
+	switch len(acls) {
+	case 0:
+		return "", fmt.Errorf("no organizations found for this account")
+	case 1:
+		orgID := strconv.FormatInt(acls[0].OrgID, 10)
+		if verbose {
+			fmt.Printf("Auto-selected org: %s (ID: %s)\n", acls[0].OrgName, orgID)
+		}
+		return orgID, nil
+	default:
+		var lines []string
+		for _, acl := range acls {
+			lines = append(lines, fmt.Sprintf("  %s (ID: %d)", acl.OrgName, acl.OrgID))
+		}
+		return "", fmt.Errorf("multiple organizations found. Use --org-id flag or set org_id in config:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+// cachedACLs returns the profile's cached /acls response unless it is stale
+// or --refresh-acls was given.
+func cachedACLs(cfg *config.Config) ([]models.UserACL, bool) {
+	if refreshACLs {
+		return nil, false
+	}
+	ttl := cfg.ACLCacheTTL
+	if ttl <= 0 {
+		ttl = aclcache.DefaultTTL
+	}
+	return aclcache.Load(profileName, cfg.ClientID, ttl)
+}
+
+// fetchACLs gets /acls without an org context.
+func fetchACLs(tokenProvider *auth.TokenProvider, quota *usage.Quota, version string) ([]models.UserACL, error) {
 	transport := &auth.Transport{
 		Token:   tokenProvider,
 		Verbose: verbose,
+		Observe: quota.Observe,
+		Allow:   quota.Allow,
 	}
 	httpClient := &http.Client{
-		Transport: transport,
+		Transport: callLog.Transport(injectFaults(transport)),
 		Timeout:   30 * time.Second,
 	}
 
-	req, err := http.NewRequest("GET", api.BaseURL+"/acls", nil)
+	req, err := http.NewRequest("GET", api.VersionURL(version)+"/acls", nil)
 	if err != nil {
-		return "", fmt.Errorf("creating ACL request: %w", err)
+		return nil, fmt.Errorf("creating ACL request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
+	requestID := api.NewRequestID()
+	req.Header.Set(api.RequestIDHeader, requestID)
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("fetching orgs: %w", err)
+		return nil, fmt.Errorf("fetching orgs (request id %s): %w", requestID, err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("reading org response: %w", err)
+		return nil, fmt.Errorf("reading org response: %w", err)
 	}
 
 	var apiResp struct {
 		Data []models.UserACL `json:"data"`
 	}
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return "", fmt.Errorf("parsing org response: %w", err)
-	}
-
-	switch len(apiResp.Data) {
-	case 0:
-		return "", fmt.Errorf("no organizations found for this account")
-	case 1:
-		orgID := strconv.FormatInt(apiResp.Data[0].OrgID, 10)
-		if verbose {
-			fmt.Printf("Auto-selected org: %s (ID: %s)\n", apiResp.Data[0].OrgName, orgID)
-		}
-		return orgID, nil
-	default:
-		var lines []string
-		for _, acl := range apiResp.Data {
-			lines = append(lines, fmt.Sprintf("  %s (ID: %d)", acl.OrgName, acl.OrgID))
-		}
-		return "", fmt.Errorf("multiple organizations found. Use --org-id flag or set org_id in config:\n%s", strings.Join(lines, "\n"))
+		return nil, fmt.Errorf("parsing org response: %w", err)
 	}
+	return apiResp.Data, nil
 }
 
 // parseFilters parses filter strings like "status=ENABLED" into Conditions.
@@ -276,33 +535,66 @@ func checkBidLimit(amount string) error {
 	return cfg.CheckBid(val)
 }
 
+// checkMinBid validates a bid amount against the configured minimum.
+func checkMinBid(amount string) error {
+	if forceFlag {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	val, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount: %s", amount)
+	}
+	return cfg.CheckMinBid(val)
+}
+
 // resolveOrgCurrency fetches /acls and returns the currency for the given org ID.
 func resolveOrgCurrency(client *api.Client) (string, error) {
-	svc := services.NewACLService(client)
-	acls, err := svc.GetACLs()
+	acl, err := resolveOrgACL(client)
 	if err != nil {
 		return "", fmt.Errorf("fetching org currency: %w", err)
 	}
+	if cfg, _ := config.Load(); cfg != nil {
+		warnOrgOnce(orgCurrencyMismatch(acl, cfg))
+	}
+	return acl.Currency, nil
+}
+
+// resolveOrgACL fetches /acls and returns the entry for the org in effect.
+func resolveOrgACL(client *api.Client) (*models.UserACL, error) {
+	cfg, _ := config.Load()
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	acls, ok := cachedACLs(cfg)
+	if !ok {
+		var err error
+		acls, err = services.NewACLService(client).GetACLs()
+		if err != nil {
+			return nil, err
+		}
+		_ = aclcache.Save(profileName, cfg.ClientID, acls)
+	}
 
 	// Match against the org ID set on the client
 	orgID := globalOrgID
 	if orgID == "" {
-		cfg, _ := config.Load()
-		if cfg != nil {
-			orgID = cfg.OrgID
-		}
+		orgID = cfg.OrgID
 	}
 
-	for _, acl := range acls {
+	for i, acl := range acls {
 		if orgID == "" || strconv.FormatInt(acl.OrgID, 10) == orgID {
-			return acl.Currency, nil
+			return &acls[i], nil
 		}
 	}
 
 	if len(acls) > 0 {
-		return acls[0].Currency, nil
+		return &acls[0], nil
 	}
-	return "", fmt.Errorf("could not resolve org currency: no organizations found")
+	return nil, fmt.Errorf("no organizations found")
 }
 
 // exitWithError prints an error and exits with the given code.
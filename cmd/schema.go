@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/jsonschema"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Print the JSON Schema of an API payload",
+	Long: `Print the JSON Schema (draft 2020-12) of an entity or update payload, for
+editor autocompletion and CI validation of documents passed with --file.
+Without a name, list the available schemas. With --dir, write every schema to
+<dir>/<name>.schema.json.
+
+Example:
+  asa-cli schema campaign-update > campaign-update.schema.json
+  asa-cli schema --dir schemas/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSchema,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return schemaNames(), cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+var schemaDir string
+
+func init() {
+	schemaCmd.Flags().StringVar(&schemaDir, "dir", "", "Write every schema to this directory")
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// schemaDef is a payload a schema can be printed for.
+type schemaDef struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	model       interface{}
+	// patch documents set only the fields they change, so none are required.
+	patch bool
+}
+
+var schemaDefs = []schemaDef{
+	{Name: "campaign", Description: "Campaign, as returned by campaigns get", model: models.Campaign{}},
+	{Name: "campaign-update", Description: "Fields accepted by campaigns update --file", model: models.CampaignUpdate{}, patch: true},
+	{Name: "adgroup", Description: "Ad group, as returned by adgroups get", model: models.AdGroup{}},
+	{Name: "adgroup-update", Description: "Fields accepted by adgroups update --file", model: models.AdGroupUpdate{}, patch: true},
+	{Name: "keyword", Description: "Targeting keyword, as returned by keywords get", model: models.Keyword{}},
+	{Name: "keyword-update", Description: "Keyword update payload (PUT targetingkeywords/bulk item)", model: models.KeywordUpdate{}},
+	{Name: "report-request", Description: "Report request body (POST reports/...)", model: models.ReportRequest{}},
+}
+
+func (d schemaDef) schema() *jsonschema.Schema {
+	s := jsonschema.Generate(d.model, d.Name, d.Description)
+	if d.patch {
+		s.Partial()
+	}
+	return s
+}
+
+func schemaNames() []string {
+	names := make([]string, len(schemaDefs))
+	for i, d := range schemaDefs {
+		names[i] = d.Name
+	}
+	return names
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if schemaDir != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--dir writes every schema; don't pass a name")
+		}
+		return writeSchemas(explainPath(schemaDir))
+	}
+	if len(args) == 0 {
+		output.Print(getFormat(), schemaDefs, []output.Column{
+			{Header: "NAME", Field: "Name"},
+			{Header: "DESCRIPTION", Field: "Description"},
+		})
+		return nil
+	}
+
+	for _, d := range schemaDefs {
+		if d.Name == strings.ToLower(args[0]) {
+			output.PrintDocument(d.schema())
+			return nil
+		}
+	}
+	names := schemaNames()
+	sort.Strings(names)
+	return fmt.Errorf("unknown schema %q (expected %s)", args[0], strings.Join(names, ", "))
+}
+
+func writeSchemas(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	for _, d := range schemaDefs {
+		data, err := json.MarshalIndent(d.schema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding %s schema: %w", d.Name, err)
+		}
+		path := filepath.Join(dir, d.Name+".schema.json")
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d schemas to %s\n", len(schemaDefs), dir)
+	return nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/server"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run local services",
+}
+
+var serveAPICmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve a local authenticated REST API",
+	Long: `Serve a small REST facade over the Apple Search Ads API using this profile's
+credentials, so local dashboards can read ASA data without embedding Apple keys.
+
+Every request (except /healthz) needs "Authorization: Bearer <token>". The token
+comes from --token or ASA_SERVE_TOKEN; if neither is set a random token is
+generated and printed on startup.
+
+Routes:
+  GET  /healthz
+  GET  /v1/campaigns?limit=&offset=
+  GET  /v1/campaigns/{campaignId}
+  GET  /v1/campaigns/{campaignId}/adgroups?limit=&offset=
+  GET  /v1/campaigns/{campaignId}/adgroups/{adGroupId}/keywords?limit=&offset=
+  PUT  /v1/campaigns/{campaignId}/adgroups/{adGroupId}/keywords/bids
+  POST /v1/reports/campaigns
+  POST /v1/reports/campaigns/{campaignId}/{adgroups|keywords|searchterms}
+
+Report routes take a report request body, e.g. {"startTime":"2024-01-01","endTime":"2024-01-31"}.`,
+	RunE: runServeAPI,
+}
+
+var (
+	serveListen string
+	serveToken  string
+)
+
+func init() {
+	serveAPICmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Address to listen on")
+	serveAPICmd.Flags().StringVar(&serveToken, "token", "", "Bearer token clients must send (default: $ASA_SERVE_TOKEN or generated)")
+
+	serveCmd.AddCommand(serveAPICmd)
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServeAPI(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	token := serveToken
+	if token == "" {
+		token = os.Getenv("ASA_SERVE_TOKEN")
+	}
+	if token == "" {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("generating token: %w", err)
+		}
+		token = hex.EncodeToString(buf)
+		fmt.Fprintf(os.Stderr, "Generated API token: %s\n", token)
+	}
+
+	srv := &http.Server{
+		Addr:              serveListen,
+		Handler:           server.New(client, cfg, token).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Fprintf(os.Stderr, "Serving ASA API on http://%s\n", serveListen)
+	return srv.ListenAndServe()
+}
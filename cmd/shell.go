@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/services"
+	"github.com/trebuhs/asa-cli/internal/session"
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive shell",
+	Long: `Start an interactive asa-cli shell with command history and tab completion.
+
+Commands are entered without the "asa-cli" prefix and can be chained with ";".
+The shell keeps a current org, campaign and ad group which are filled in for
+commands that take --org-id, --campaign-id or --adgroup-id. The context is
+saved per profile and restored the next time the shell starts.
+
+Shell commands:
+  use org <id>          Set the current organization
+  use campaign <id>     Set the current campaign (clears the ad group)
+  use adgroup <id>      Set the current ad group
+  context               Show the current context
+  context clear         Clear the current context
+  exit, quit            Leave the shell
+
+Example:
+  asa> use campaign 123; use adgroup 456; keywords list`,
+	Args: cobra.NoArgs,
+	RunE: runShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	ctx, err := session.Load(profileName)
+	if err != nil {
+		return err
+	}
+
+	completer := &shellCompleter{ctx: ctx}
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          shellPrompt(ctx),
+		HistoryFile:     filepath.Join(config.ConfigDir(), "shell_history"),
+		AutoComplete:    completer,
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return fmt.Errorf("starting shell: %w", err)
+	}
+	defer rl.Close()
+
+	baseline := snapshotFlags(rootCmd)
+	defer restoreFlags(baseline)
+
+	fmt.Println(`asa-cli interactive shell. Type "help" for commands, "exit" to quit.`)
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range splitStatements(line) {
+			args, err := splitCommandLine(stmt)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				break
+			}
+			if len(args) == 0 {
+				continue
+			}
+			if args[0] == "asa-cli" {
+				args = args[1:]
+			}
+			args = expandAliases(args)
+			if len(args) > 0 && (args[0] == "exit" || args[0] == "quit") {
+				return nil
+			}
+
+			handled, err := runShellBuiltin(args, ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				break
+			}
+			if handled {
+				rl.SetPrompt(shellPrompt(ctx))
+				continue
+			}
+			if len(args) > 0 && (args[0] == "shell" || args[0] == "batch") {
+				fmt.Fprintf(os.Stderr, "Error: %q is not available inside the shell\n", args[0])
+				break
+			}
+
+			if args, err = expandWebLinks(args); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				break
+			}
+			restoreFlags(baseline)
+			rootCmd.SetArgs(applySessionContext(args, ctx))
+			if err := rootCmd.Execute(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				break
+			}
+		}
+	}
+}
+
+// runShellBuiltin handles shell-only commands. It reports whether args was one.
+func runShellBuiltin(args []string, ctx *session.Context) (bool, error) {
+	switch args[0] {
+	case "use":
+		if len(args) != 3 {
+			return true, fmt.Errorf("usage: use org|campaign|adgroup <id>")
+		}
+		if err := setSessionContext(ctx, args[1], args[2]); err != nil {
+			return true, err
+		}
+		return true, session.Save(profileName, ctx)
+	case "context":
+		if len(args) > 1 && args[1] == "clear" {
+			*ctx = session.Context{}
+			return true, session.Clear(profileName)
+		}
+		printSessionContext(ctx)
+		return true, nil
+	}
+	return false, nil
+}
+
+func printSessionContext(ctx *session.Context) {
+	if ctx.IsEmpty() {
+		fmt.Println("No context set.")
+		return
+	}
+	if ctx.OrgID != "" {
+		fmt.Printf("org:      %s\n", ctx.OrgID)
+	}
+	if ctx.CampaignID != 0 {
+		fmt.Printf("campaign: %d\n", ctx.CampaignID)
+	}
+	if ctx.AdGroupID != 0 {
+		fmt.Printf("adgroup:  %d\n", ctx.AdGroupID)
+	}
+}
+
+func shellPrompt(ctx *session.Context) string {
+	var parts []string
+	if ctx.OrgID != "" {
+		parts = append(parts, "org:"+ctx.OrgID)
+	}
+	if ctx.CampaignID != 0 {
+		parts = append(parts, fmt.Sprintf("campaign:%d", ctx.CampaignID))
+	}
+	if ctx.AdGroupID != 0 {
+		parts = append(parts, fmt.Sprintf("adgroup:%d", ctx.AdGroupID))
+	}
+	if len(parts) == 0 {
+		return "asa> "
+	}
+	return "asa[" + strings.Join(parts, " ") + "]> "
+}
+
+// applySessionContext adds --org-id, --campaign-id and --adgroup-id from the
+// session context when the target command accepts them and they weren't given.
+func applySessionContext(args []string, ctx *session.Context) []string {
+	target, _, err := rootCmd.Find(args)
+	if err != nil || target == nil {
+		return args
+	}
+
+	has := func(name string) bool {
+		for _, a := range args {
+			if a == "--"+name || strings.HasPrefix(a, "--"+name+"=") {
+				return true
+			}
+		}
+		return false
+	}
+
+	out := append([]string(nil), args...)
+	if ctx.OrgID != "" && !has("org-id") {
+		out = append(out, "--org-id", ctx.OrgID)
+	}
+	if ctx.CampaignID != 0 && target.Flags().Lookup("campaign-id") != nil && !has("campaign-id") {
+		out = append(out, "--campaign-id", strconv.FormatInt(ctx.CampaignID, 10))
+	}
+	if ctx.AdGroupID != 0 && target.Flags().Lookup("adgroup-id") != nil && !has("adgroup-id") {
+		out = append(out, "--adgroup-id", strconv.FormatInt(ctx.AdGroupID, 10))
+	}
+	return out
+}
+
+// splitStatements splits a shell line on ";" outside of quotes.
+func splitStatements(line string) []string {
+	var stmts []string
+	var cur strings.Builder
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ';':
+			stmts = append(stmts, cur.String())
+			cur.Reset()
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	return append(stmts, cur.String())
+}
+
+// shellCompleter completes subcommands, flags, and campaign/ad group IDs.
+type shellCompleter struct {
+	ctx       *session.Context
+	campaigns []string
+	adgroups  map[int64][]string
+}
+
+func (c *shellCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	text := string(line[:pos])
+	if i := strings.LastIndex(text, ";"); i >= 0 {
+		text = text[i+1:]
+	}
+
+	fields := strings.Fields(text)
+	partial := ""
+	if len(fields) > 0 && !strings.HasSuffix(text, " ") {
+		partial = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	var out [][]rune
+	for _, cand := range c.candidates(fields, partial) {
+		if strings.HasPrefix(cand, partial) {
+			out = append(out, []rune(cand[len(partial):]+" "))
+		}
+	}
+	return out, len([]rune(partial))
+}
+
+func (c *shellCompleter) candidates(fields []string, partial string) []string {
+	if len(fields) == 0 {
+		names := []string{"use", "context", "exit", "quit"}
+		return append(names, subcommandNames(rootCmd)...)
+	}
+
+	last := fields[len(fields)-1]
+	switch {
+	case fields[0] == "use" && len(fields) == 1:
+		return []string{"org", "campaign", "adgroup"}
+	case fields[0] == "use" && len(fields) == 2 && fields[1] == "campaign", last == "--campaign-id":
+		return c.campaignIDs()
+	case fields[0] == "use" && len(fields) == 2 && fields[1] == "adgroup", last == "--adgroup-id":
+		return c.adGroupIDs(c.ctx.CampaignID)
+	case fields[0] == "context" && len(fields) == 1:
+		return []string{"clear"}
+	}
+
+	cmd := rootCmd
+	for _, f := range fields {
+		if strings.HasPrefix(f, "-") {
+			continue
+		}
+		sub, _, err := cmd.Find([]string{f})
+		if err != nil || sub == cmd {
+			break
+		}
+		cmd = sub
+	}
+
+	if strings.HasPrefix(partial, "-") {
+		var flags []string
+		visit := func(f *pflag.Flag) {
+			if !f.Hidden {
+				flags = append(flags, "--"+f.Name)
+			}
+		}
+		cmd.Flags().VisitAll(visit)
+		cmd.InheritedFlags().VisitAll(visit)
+		sort.Strings(flags)
+		return flags
+	}
+	return subcommandNames(cmd)
+}
+
+func subcommandNames(cmd *cobra.Command) []string {
+	var names []string
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() && sub.Name() != "shell" && sub.Name() != "batch" {
+			names = append(names, sub.Name())
+		}
+	}
+	return names
+}
+
+func (c *shellCompleter) campaignIDs() []string {
+	if c.campaigns != nil {
+		return c.campaigns
+	}
+	c.campaigns = []string{}
+	client, err := c.client()
+	if err != nil {
+		return c.campaigns
+	}
+	campaigns, _, err := services.NewCampaignService(client).List(1000, 0)
+	if err != nil {
+		return c.campaigns
+	}
+	for _, camp := range campaigns {
+		c.campaigns = append(c.campaigns, strconv.FormatInt(camp.ID, 10))
+	}
+	return c.campaigns
+}
+
+func (c *shellCompleter) adGroupIDs(campaignID int64) []string {
+	if campaignID == 0 {
+		return nil
+	}
+	if c.adgroups == nil {
+		c.adgroups = map[int64][]string{}
+	}
+	if ids, ok := c.adgroups[campaignID]; ok {
+		return ids
+	}
+	ids := []string{}
+	c.adgroups[campaignID] = ids
+	client, err := c.client()
+	if err != nil {
+		return ids
+	}
+	adgroups, _, err := services.NewAdGroupService(client).List(campaignID, 1000, 0)
+	if err != nil {
+		return ids
+	}
+	for _, ag := range adgroups {
+		ids = append(ids, strconv.FormatInt(ag.ID, 10))
+	}
+	c.adgroups[campaignID] = ids
+	return ids
+}
+
+// client returns an API client for the shell's current org.
+func (c *shellCompleter) client() (*api.Client, error) {
+	if c.ctx.OrgID != "" && globalOrgID == "" {
+		globalOrgID = c.ctx.OrgID
+		defer func() { globalOrgID = "" }()
+	}
+	return newAPIClient()
+}
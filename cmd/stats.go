@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/usage"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show local command and API usage",
+	Long: `Show per-command invocation counts, API calls, and rate-limit (HTTP 429) hits
+from the local usage log (~/.asa-cli/usage.jsonl). The log never leaves this
+machine; set telemetry.disable_local: true in config.yaml to stop writing it.
+
+Commands run inside "shell" or "batch" are counted under that command.`,
+	RunE: runStats,
+}
+
+var statsTelemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Show or change anonymous usage reporting",
+	Long: `Anonymous usage reporting is off by default. When enabled, each command sends
+its name, OS, architecture, duration, API call and rate-limit counts, success,
+and a random install ID to the configured endpoint. Profile names, IDs,
+arguments, and account data are never sent. DO_NOT_TRACK=1 disables it.
+
+Example:
+  asa-cli stats telemetry --enable --endpoint https://telemetry.example.com/asa-cli`,
+	RunE: runStatsTelemetry,
+}
+
+var (
+	statsDays         int
+	telemetryEnable   bool
+	telemetryDisable  bool
+	telemetryEndpoint string
+)
+
+func init() {
+	statsCmd.Flags().IntVar(&statsDays, "days", 30, "Days of history to include (0 for all)")
+
+	statsTelemetryCmd.Flags().BoolVar(&telemetryEnable, "enable", false, "Turn on anonymous usage reporting")
+	statsTelemetryCmd.Flags().BoolVar(&telemetryDisable, "disable", false, "Turn off anonymous usage reporting")
+	statsTelemetryCmd.Flags().StringVar(&telemetryEndpoint, "endpoint", "", "URL to send reports to")
+
+	statsCmd.AddCommand(statsTelemetryCmd)
+	rootCmd.AddCommand(statsCmd)
+}
+
+type statsRow struct {
+	usage.CommandStats
+	Last string `json:"-"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	var since time.Time
+	if statsDays > 0 {
+		since = time.Now().AddDate(0, 0, -statsDays)
+	}
+	events, err := usage.Load(since)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		fmt.Fprintf(os.Stderr, "No usage recorded yet in %s.\n", usage.Path())
+		return nil
+	}
+
+	stats := usage.ByCommand(events)
+	rows := make([]statsRow, len(stats))
+	var runs int
+	var calls, limited int64
+	for i, s := range stats {
+		rows[i] = statsRow{CommandStats: s, Last: s.LastRun.Local().Format("2006-01-02 15:04")}
+		runs += s.Runs
+		calls += s.APICalls
+		limited += s.RateLimited
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, stats, nil)
+		return nil
+	}
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "COMMAND", Field: "Command"},
+		{Header: "RUNS", Field: "Runs"},
+		{Header: "FAILED", Field: "Failed"},
+		{Header: "API CALLS", Field: "APICalls"},
+		{Header: "RATE LIMITED", Field: "RateLimited"},
+		{Header: "LAST RUN", Field: "Last"},
+	})
+	period := "all time"
+	if statsDays > 0 {
+		period = fmt.Sprintf("last %d days", statsDays)
+	}
+	fmt.Fprintf(os.Stderr, "\n%d runs, %d API calls, %d rate-limited (%s)\n", runs, calls, limited, period)
+	return nil
+}
+
+func runStatsTelemetry(cmd *cobra.Command, args []string) error {
+	if telemetryEnable && telemetryDisable {
+		return fmt.Errorf("--enable and --disable are mutually exclusive")
+	}
+	if telemetryEnable || telemetryDisable {
+		cfg, err := config.Telemetry()
+		if err != nil {
+			return err
+		}
+		if telemetryEnable && telemetryEndpoint == "" && cfg.Endpoint == "" {
+			return fmt.Errorf("--endpoint is required to enable telemetry")
+		}
+		if err := config.SetTelemetry(telemetryEnable, telemetryEndpoint); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.Telemetry()
+	if err != nil {
+		return err
+	}
+	switch {
+	case !cfg.Enabled || cfg.Endpoint == "":
+		fmt.Println("Anonymous usage reporting: off")
+	case usage.DoNotTrack():
+		fmt.Printf("Anonymous usage reporting: on (%s), but suppressed by DO_NOT_TRACK\n", cfg.Endpoint)
+	default:
+		fmt.Printf("Anonymous usage reporting: on (%s)\n", cfg.Endpoint)
+	}
+	if cfg.DisableLocal {
+		fmt.Println("Local usage log: off")
+	} else {
+		fmt.Printf("Local usage log: %s\n", usage.Path())
+	}
+	return nil
+}
+
+// recordUsage logs a finished command locally and, if enabled, reports it.
+// Failures are ignored, apart from a warning when quota counts are lost:
+// usage tracking must never break a command.
+func recordUsage(cmd *cobra.Command, start time.Time, runErr error) {
+	if len(quotas) > 0 {
+		list := make([]*usage.Quota, 0, len(quotas))
+		for _, q := range quotas {
+			list = append(list, q)
+		}
+		if err := usage.SaveQuotas(list); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record API calls for the daily quota: %v\n", err)
+		}
+	}
+	if cmd == nil || cmd == rootCmd || cmd.Name() == "help" || strings.HasPrefix(cmd.Name(), "__complete") {
+		return
+	}
+	cfg, err := config.Telemetry()
+	if err != nil {
+		return
+	}
+	calls, limited := usage.Counts()
+	e := usage.Event{
+		Time:        start.UTC(),
+		Command:     strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		Profile:     profileName,
+		APICalls:    calls,
+		RateLimited: limited,
+		Failed:      runErr != nil,
+		DurationMS:  time.Since(start).Milliseconds(),
+		FailedCalls: usage.FailedCalls(),
+	}
+	if e.Profile == "" {
+		e.Profile = "default"
+	}
+	if !cfg.DisableLocal {
+		_ = usage.Append(e)
+	}
+	if cfg.Enabled && cfg.Endpoint != "" && !usage.DoNotTrack() {
+		_ = usage.Send(cfg.Endpoint, usage.NewReport(e))
+	}
+}
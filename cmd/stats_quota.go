@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/usage"
+)
+
+var statsQuotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Show daily API calls per profile against the quota",
+	Long: `Show each profile's API calls today and over the last 7 days against its daily
+budget. Set the budget with quota_limit in config.yaml (top level or per
+profile) or --quota-limit; commands warn at 80% of it, and refuse further
+calls once it is used up when enforce_quota is set or --enforce-quota given.
+
+Example config:
+  quota_limit: 5000
+  enforce_quota: true`,
+	RunE: runStatsQuota,
+}
+
+func init() {
+	statsCmd.AddCommand(statsQuotaCmd)
+}
+
+// quotas holds this run's quota trackers by profile. Calls are counted in
+// memory and recorded in bulk; the rest are recorded when the command
+// finishes.
+var quotas = map[string]*usage.Quota{}
+
+// quotaFor returns the quota tracker for the active profile.
+func quotaFor(cfg *config.Config) *usage.Quota {
+	profile := profileName
+	if profile == "" {
+		profile = "default"
+	}
+	limit := cfg.QuotaLimit
+	if quotaLimit > 0 {
+		limit = quotaLimit
+	}
+	// A tracker is kept across batch and shell lines so the count carries
+	// over, but each line's --quota-limit and --enforce-quota apply.
+	if q, ok := quotas[profile]; ok {
+		q.Limit, q.Enforce = limit, cfg.EnforceQuota || enforceQuota
+		return q
+	}
+	q := usage.NewQuota(profile, limit, cfg.EnforceQuota || enforceQuota)
+	quotas[profile] = q
+	return q
+}
+
+type quotaRow struct {
+	Profile   string `json:"profile"`
+	Today     int64  `json:"today"`
+	Limit     int64  `json:"limit,omitempty"`
+	Enforced  bool   `json:"enforced"`
+	Last7Days int64  `json:"last7Days"`
+	Used      string `json:"-"`
+}
+
+func runStatsQuota(cmd *cobra.Command, args []string) error {
+	calls, err := usage.LoadDailyCalls()
+	if err != nil {
+		return err
+	}
+
+	// Always list the active profile, even before its first call.
+	active := profileName
+	if active == "" {
+		active = "default"
+	}
+	if _, ok := calls[active]; !ok {
+		calls[active] = map[string]int64{}
+	}
+
+	today := time.Now()
+	var rows []quotaRow
+	for _, profile := range calls.Profiles() {
+		row := quotaRow{Profile: profile, Today: calls[profile][usage.Day(today)]}
+		for i := 0; i < 7; i++ {
+			row.Last7Days += calls[profile][usage.Day(today.AddDate(0, 0, -i))]
+		}
+		row.Limit, row.Enforced = profileQuotaLimit(profile)
+		if profile == active && quotaLimit > 0 {
+			row.Limit = quotaLimit
+		}
+		if profile == active && enforceQuota {
+			row.Enforced = true
+		}
+		if row.Limit > 0 {
+			row.Used = fmt.Sprintf("%.0f%%", float64(row.Today)/float64(row.Limit)*100)
+		}
+		rows = append(rows, row)
+	}
+
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "PROFILE", Field: "Profile"},
+		{Header: "TODAY", Field: "Today"},
+		{Header: "LIMIT", Field: "Limit"},
+		{Header: "USED", Field: "Used"},
+		{Header: "ENFORCED", Field: "Enforced"},
+		{Header: "LAST 7 DAYS", Field: "Last7Days"},
+	})
+	return nil
+}
+
+// profileQuotaLimit reads a profile's configured quota.
+func profileQuotaLimit(profile string) (int64, bool) {
+	config.SetProfile(profile)
+	defer config.SetProfile(profileName)
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, false
+	}
+	return cfg.QuotaLimit, cfg.EnforceQuota
+}
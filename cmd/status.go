@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/credentials"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+	"github.com/trebuhs/asa-cli/internal/usage"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Probe the API's health before running automation",
+	Long: `Probe Apple's token endpoint with a fresh token exchange and the API with a
+cheap request, timing both, then report the rate-limit headroom and the error
+rate of recent runs from the local usage log (~/.asa-cli/usage.jsonl).
+
+Exits non-zero when the API appears degraded: a probe fails or is slower
+than --max-latency, the rate limit is used up, or more than --max-error-rate
+of the API calls in the last --window got a 429 or 5xx response. Use it as a
+pre-flight check:
+
+  asa-cli status -o json > health.json && asa-cli optimize budgets --apply`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+var (
+	statusWindow       string
+	statusMaxLatency   time.Duration
+	statusMaxErrorRate float64
+)
+
+// statusMinCalls is how many recent API calls the error rate needs before it
+// can fail the check; fewer is too little to go on.
+const statusMinCalls = 20
+
+func init() {
+	statusCmd.Flags().StringVar(&statusWindow, "window", "1h", "How far back recent runs count toward the error rate (e.g. 1h, 1d)")
+	statusCmd.Flags().DurationVar(&statusMaxLatency, "max-latency", 3*time.Second, "Probes slower than this count as degraded")
+	statusCmd.Flags().Float64Var(&statusMaxErrorRate, "max-error-rate", 20, "Percent of recent API calls failing with 429 or 5xx that counts as degraded")
+	rootCmd.AddCommand(statusCmd)
+}
+
+// StatusCheck is the result of one status probe.
+type StatusCheck struct {
+	Check     string `json:"check"`
+	Status    string `json:"status"` // ok, warn, fail, or skip
+	LatencyMS int64  `json:"latencyMs,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+
+	Latency string `json:"-"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	window, err := daterange.ParseAge(statusWindow)
+	if err != nil {
+		return fmt.Errorf("invalid --window: %w", err)
+	}
+	checks := statusChecks(window)
+
+	output.Print(getFormat(), checks, []output.Column{
+		{Header: "CHECK", Field: "Check"},
+		{Header: "STATUS", Field: "Status"},
+		{Header: "LATENCY", Field: "Latency"},
+		{Header: "DETAIL", Field: "Detail"},
+	})
+
+	var failed []string
+	for _, c := range checks {
+		if c.Status == "fail" {
+			failed = append(failed, c.Check)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("API degraded: %s", strings.Join(failed, ", "))
+	}
+	fmt.Fprintln(os.Stderr, "API healthy.")
+	return nil
+}
+
+// statusChecks probes the token endpoint and the API, then reads the local
+// usage log. Probes that depend on a failed one are skipped.
+func statusChecks(window time.Duration) []StatusCheck {
+	var checks []StatusCheck
+	add := func(check, status string, latency time.Duration, detail string) {
+		c := StatusCheck{Check: check, Status: status, Detail: detail}
+		if latency > 0 {
+			c.LatencyMS = latency.Milliseconds()
+			c.Latency = fmt.Sprintf("%dms", c.LatencyMS)
+		}
+		checks = append(checks, c)
+	}
+	timed := func(check string, latency time.Duration, detail string) {
+		if latency > statusMaxLatency {
+			add(check, "fail", latency, fmt.Sprintf("%s, slower than %s", detail, statusMaxLatency))
+			return
+		}
+		add(check, "ok", latency, detail)
+	}
+
+	var headers http.Header
+	cfg, err := config.Load()
+	if err == nil {
+		err = credentials.Resolve(cfg)
+	}
+	if err == nil {
+		err = auth.ValidateConfig(cfg)
+	}
+	switch {
+	case err != nil:
+		add("token", "fail", 0, strings.SplitN(err.Error(), "\n", 2)[0]+" (see asa-cli doctor)")
+		add("api", "skip", 0, "no access token")
+	default:
+		start := time.Now()
+		_, err := newTokenProvider(cfg).Refresh()
+		latency := time.Since(start)
+		if err != nil {
+			add("token", "fail", latency, strings.SplitN(err.Error(), "\n", 2)[0])
+			add("api", "skip", 0, "no access token")
+			break
+		}
+		timed("token", latency, "fresh token from appleid.apple.com")
+
+		var probeErr error
+		headers, latency, probeErr = probeAPI()
+		if probeErr != nil {
+			add("api", "fail", latency, probeErr.Error())
+			break
+		}
+		timed("api", latency, "GET /acls")
+	}
+
+	if c, ok := rateLimitCheck(headers, cfg, window); ok {
+		checks = append(checks, c)
+	}
+	checks = append(checks, errorRateCheck(window))
+	return checks
+}
+
+// probeAPI times a GET /acls and returns the response headers, which may
+// carry the rate limit.
+func probeAPI() (http.Header, time.Duration, error) {
+	client, err := newAPIClientNoOrg()
+	if err != nil {
+		return nil, 0, err
+	}
+	rec := &headerRecorder{base: client.HTTP.Transport}
+	probe := *client
+	httpClient := *client.HTTP
+	httpClient.Transport = rec
+	probe.HTTP = &httpClient
+
+	start := time.Now()
+	_, err = services.NewACLService(&probe).GetACLs()
+	return rec.header(), time.Since(start), err
+}
+
+// headerRecorder keeps the headers of the last response it passes on.
+type headerRecorder struct {
+	base http.RoundTripper
+	mu   sync.Mutex
+	last http.Header
+}
+
+func (r *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if resp != nil {
+		r.mu.Lock()
+		r.last = resp.Header
+		r.mu.Unlock()
+	}
+	return resp, err
+}
+
+func (r *headerRecorder) header() http.Header {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// rateLimitHeaders are the remaining/limit header pairs APIs commonly send.
+var rateLimitHeaders = [][2]string{
+	{"X-Rate-Limit-Remaining", "X-Rate-Limit-Limit"},
+	{"X-RateLimit-Remaining", "X-RateLimit-Limit"},
+	{"RateLimit-Remaining", "RateLimit-Limit"},
+}
+
+// rateLimitCheck reports the headroom left under the rate limit the API
+// announced, or else under the profile's daily quota_limit, warning after
+// recent 429s. It reports nothing when neither is known and there were none.
+func rateLimitCheck(h http.Header, cfg *config.Config, window time.Duration) (StatusCheck, bool) {
+	c := StatusCheck{Check: "rate limit", Status: "ok"}
+	for _, names := range rateLimitHeaders {
+		remaining, err := strconv.Atoi(h.Get(names[0]))
+		if err != nil {
+			continue
+		}
+		limit, _ := strconv.Atoi(h.Get(names[1]))
+		c.Detail = fmt.Sprintf("%d request(s) left", remaining)
+		if limit > 0 {
+			c.Detail = fmt.Sprintf("%d of %d request(s) left", remaining, limit)
+		}
+		switch {
+		case remaining == 0:
+			c.Status = "fail"
+		case limit > 0 && remaining*10 < limit:
+			c.Status = "warn"
+		}
+		break
+	}
+	if c.Detail == "" && cfg != nil {
+		q := quotaFor(cfg)
+		if q.Limit > 0 {
+			used := q.Used()
+			c.Detail = fmt.Sprintf("%d of %d daily call(s) used (quota_limit)", used, q.Limit)
+			switch {
+			case used >= q.Limit && q.Enforce:
+				c.Status = "fail"
+			case float64(used) >= 0.8*float64(q.Limit):
+				c.Status = "warn"
+			}
+		}
+	}
+
+	events, _ := usage.Load(time.Now().Add(-window))
+	var limited int64
+	for _, e := range events {
+		limited += e.RateLimited
+	}
+	if limited > 0 {
+		hits := fmt.Sprintf("%d rate-limited response(s) in the last %s", limited, statusWindow)
+		if c.Detail == "" {
+			c.Detail = hits
+		} else {
+			c.Detail += "; " + hits
+		}
+		if c.Status == "ok" {
+			c.Status = "warn"
+		}
+	}
+	return c, c.Detail != ""
+}
+
+// errorRateCheck reports the share of recent API calls that got a 429 or 5xx
+// response, from the local usage log.
+func errorRateCheck(window time.Duration) StatusCheck {
+	c := StatusCheck{Check: "errors", Status: "ok"}
+	events, err := usage.Load(time.Now().Add(-window))
+	if err != nil {
+		c.Status, c.Detail = "skip", err.Error()
+		return c
+	}
+	var calls, degraded int64
+	for _, e := range events {
+		calls += e.APICalls
+		for _, f := range e.FailedCalls {
+			if f.Status == http.StatusTooManyRequests || f.Status >= 500 {
+				degraded++
+			}
+		}
+	}
+	if calls == 0 {
+		c.Status, c.Detail = "skip", fmt.Sprintf("no API calls in the last %s", statusWindow)
+		return c
+	}
+	rate := float64(degraded) / float64(calls) * 100
+	c.Detail = fmt.Sprintf("%d of %d API call(s) in the last %s got a 429 or 5xx (%.1f%%)", degraded, calls, statusWindow, rate)
+	switch {
+	case calls < statusMinCalls:
+		if degraded > 0 {
+			c.Status = "warn"
+		}
+	case rate > statusMaxErrorRate:
+		c.Status = "fail"
+	case rate > statusMaxErrorRate/4:
+		c.Status = "warn"
+	}
+	return c
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// expandStdinIDs replaces a "-" given for an ID argument or ID flag (--id,
+// --ids, --campaign-id, --adgroup-id, ...) with the newline-separated IDs read
+// from in. Where the argument takes a comma-separated list the IDs are joined
+// into it and there is one run; otherwise the command runs once per ID. args is
+// returned as the only run when it reads nothing from stdin.
+func expandStdinIDs(args []string, in io.Reader) ([][]string, error) {
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil || cmd == rootCmd {
+		return [][]string{args}, nil
+	}
+
+	slot, list := -1, false
+	for i, a := range args {
+		var isList bool
+		switch {
+		case a == "-" && i > 0 && flagTakesValue(cmd, args[i-1]):
+			f := lookupFlag(cmd, args[i-1])
+			if !isIDFlag(f) {
+				continue
+			}
+			isList = strings.HasSuffix(f.Name, "ids") && f.Value.Type() != "int64"
+		case a == "-":
+			if !strings.Contains(cmd.Use, "<id") {
+				continue
+			}
+			isList = strings.Contains(cmd.Use, "id,...")
+		case strings.HasPrefix(a, "--") && strings.HasSuffix(a, "=-"):
+			f := lookupFlag(cmd, strings.TrimSuffix(a, "=-"))
+			if !isIDFlag(f) {
+				continue
+			}
+			isList = strings.HasSuffix(f.Name, "ids") && f.Value.Type() != "int64"
+		default:
+			continue
+		}
+		if slot >= 0 {
+			return nil, fmt.Errorf("only one argument can read IDs from stdin")
+		}
+		slot, list = i, isList
+	}
+	if slot < 0 {
+		return [][]string{args}, nil
+	}
+
+	ids, err := readStdinIDs(in)
+	if err != nil {
+		return nil, err
+	}
+	with := func(value string) []string {
+		run := append([]string(nil), args...)
+		if strings.HasSuffix(run[slot], "=-") {
+			value = strings.TrimSuffix(run[slot], "-") + value
+		}
+		run[slot] = value
+		return run
+	}
+	if list {
+		return [][]string{with(strings.Join(ids, ","))}, nil
+	}
+	runs := make([][]string, len(ids))
+	for i, id := range ids {
+		runs[i] = with(id)
+	}
+	return runs, nil
+}
+
+// readStdinIDs reads one ID per line, skipping blank lines and # comments.
+func readStdinIDs(in io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := strconv.ParseInt(line, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid ID on stdin: %s", line)
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading IDs from stdin: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no IDs on stdin")
+	}
+	return ids, nil
+}
+
+// lookupFlag finds the flag named by an argument such as "--campaign-id" or
+// "-p", on the command or its parents.
+func lookupFlag(cmd *cobra.Command, arg string) *pflag.Flag {
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		name := strings.TrimPrefix(arg, "--")
+		if f := cmd.Flags().Lookup(name); f != nil {
+			return f
+		}
+		return cmd.InheritedFlags().Lookup(name)
+	case strings.HasPrefix(arg, "-") && len(arg) == 2:
+		if f := cmd.Flags().ShorthandLookup(arg[1:]); f != nil {
+			return f
+		}
+		return cmd.InheritedFlags().ShorthandLookup(arg[1:])
+	}
+	return nil
+}
+
+// flagTakesValue reports whether arg is a flag whose value is the next
+// argument.
+func flagTakesValue(cmd *cobra.Command, arg string) bool {
+	f := lookupFlag(cmd, arg)
+	return f != nil && f.NoOptDefVal == ""
+}
+
+func isIDFlag(f *pflag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	return f.Name == "id" || f.Name == "ids" || strings.HasSuffix(f.Name, "-id") || strings.HasSuffix(f.Name, "-ids")
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/portfolios"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "One-screen overview of the organization",
+	Long: `Print an overview of the organization for a date range: total spend, installs
+and CPI compared with the previous period, the top campaigns by spend, the
+biggest spend movers, and enabled campaigns that are not serving.
+
+With --range today --project-eod, also project each campaign's end-of-day
+spend and installs from today's hourly data so far and the shape of its recent
+days, and flag campaigns projected to exhaust their daily budget or to spend
+less than --miss-share of it. Hourly history is kept in a local warehouse
+(intraday.json in the config directory), so only missing days are fetched.
+
+Example:
+  asa-cli summary --range today --project-eod`,
+	RunE: runSummary,
+}
+
+var (
+	sumRange      string
+	sumTop        int
+	sumPortfolio  string
+	sumProjectEOD bool
+	sumHistory    int
+	sumMissShare  float64
+)
+
+func init() {
+	summaryCmd.Flags().StringVar(&sumRange, "range", "last-7d", "Date range: "+daterange.Help)
+	summaryCmd.Flags().IntVar(&sumTop, "top", 5, "Number of top campaigns and movers to show")
+	summaryCmd.Flags().StringVar(&sumPortfolio, "portfolio", "", "Summarize only the campaigns in this portfolio")
+	summaryCmd.Flags().BoolVar(&sumProjectEOD, "project-eod", false, "Project end-of-day spend and installs (requires --range today)")
+	summaryCmd.Flags().IntVar(&sumHistory, "history", 7, "Days of hourly history to shape projections (1-29)")
+	summaryCmd.Flags().Float64Var(&sumMissShare, "miss-share", 0.8, "Flag campaigns projected to spend less than this share of their budget")
+	rootCmd.AddCommand(summaryCmd)
+}
+
+// OrgSummary is the JSON form of the summary command.
+type OrgSummary struct {
+	OrgID          int64              `json:"orgId"`
+	OrgName        string             `json:"orgName"`
+	Portfolio      string             `json:"portfolio,omitempty"`
+	Currency       string             `json:"currency"`
+	StartDate      string             `json:"startDate"`
+	EndDate        string             `json:"endDate"`
+	Totals         aggregate.Metrics  `json:"totals"`
+	PreviousTotals aggregate.Metrics  `json:"previousTotals"`
+	CPI            float64            `json:"cpi"`
+	PreviousCPI    float64            `json:"previousCpi"`
+	TopCampaigns   []aggregate.Entity `json:"topCampaigns"`
+	Movers         []aggregate.Mover  `json:"movers"`
+	ServingIssues  []servingIssueRow  `json:"servingIssues"`
+	// ProjectedThrough is the last hour of today's data behind Projections.
+	ProjectedThrough string          `json:"projectedThrough,omitempty"`
+	Projections      []EODProjection `json:"projections,omitempty"`
+}
+
+type summaryCampaignRow struct {
+	ID       int64
+	Name     string
+	Spend    string
+	Installs int64
+	CPI      string
+}
+
+type summaryMoverRow struct {
+	ID        int64
+	Name      string
+	Spend     string
+	PrevSpend string
+	Change    string
+}
+
+type servingIssueRow struct {
+	ID            int64                `json:"id"`
+	Name          string               `json:"name"`
+	ServingStatus models.ServingStatus `json:"servingStatus"`
+	Reasons       []string             `json:"reasons,omitempty"`
+}
+
+func runSummary(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	rng, err := daterange.Parse(sumRange, now)
+	if err != nil {
+		return err
+	}
+	prevRng := rng.Previous()
+	if sumProjectEOD {
+		if today, _ := daterange.Parse("today", now); rng != today {
+			return fmt.Errorf("--project-eod requires --range today")
+		}
+		if sumHistory < 1 || sumHistory >= pacingMaxDays {
+			return fmt.Errorf("--history must be between 1 and %d days", pacingMaxDays-1)
+		}
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	acl, err := resolveOrgACL(client)
+	if err != nil {
+		return fmt.Errorf("fetching org: %w", err)
+	}
+
+	campaigns, err := services.NewCampaignService(client).FindAll(models.NewSelector(1000, 0))
+	if err != nil {
+		return fmt.Errorf("listing campaigns: %w", err)
+	}
+
+	reports := services.NewReportingService(client)
+	cur, err := reports.GetCampaignReport(newRangeReportRequest(rng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting campaign report: %w", err)
+	}
+	prev, err := reports.GetCampaignReport(newRangeReportRequest(prevRng, 1000))
+	if err != nil {
+		return fmt.Errorf("getting previous period report: %w", err)
+	}
+
+	if sumPortfolio != "" {
+		ids, err := portfolioCampaignIDs(client, sumPortfolio)
+		if err != nil {
+			return err
+		}
+		var kept []models.Campaign
+		for _, c := range campaigns {
+			if ids[c.ID] {
+				kept = append(kept, c)
+			}
+		}
+		campaigns = kept
+		cur = aggregate.Subset(cur, "campaignId", ids)
+		prev = aggregate.Subset(prev, "campaignId", ids)
+	}
+
+	curEntities := aggregate.ByEntity(cur, "campaignId", "campaignName")
+	prevEntities := aggregate.ByEntity(prev, "campaignId", "campaignName")
+
+	summary := OrgSummary{
+		OrgID:          acl.OrgID,
+		OrgName:        acl.OrgName,
+		Portfolio:      portfolios.Normalize(sumPortfolio),
+		Currency:       acl.Currency,
+		StartDate:      rng.StartDate(),
+		EndDate:        rng.EndDate(),
+		Totals:         aggregate.Total(curEntities),
+		PreviousTotals: aggregate.Total(prevEntities),
+		TopCampaigns:   aggregate.TopBySpend(curEntities, sumTop),
+		Movers:         aggregate.Movers(curEntities, prevEntities, sumTop),
+		ServingIssues:  []servingIssueRow{},
+	}
+	summary.CPI = summary.Totals.CPI()
+	summary.PreviousCPI = summary.PreviousTotals.CPI()
+
+	for _, c := range campaigns {
+		if c.Status == "ENABLED" && c.ServingStatus != "" && c.ServingStatus != "RUNNING" {
+			summary.ServingIssues = append(summary.ServingIssues, servingIssueRow{
+				ID:            c.ID,
+				Name:          c.Name,
+				ServingStatus: c.ServingStatus,
+				Reasons:       c.ServingStateReasons,
+			})
+		}
+	}
+
+	if sumProjectEOD {
+		warnOrgTimeZone(client)
+		projections, hour, err := projectEOD(client, campaigns, rng.Start)
+		if err != nil {
+			return err
+		}
+		summary.Projections = projections
+		if hour >= 0 {
+			summary.ProjectedThrough = fmt.Sprintf("%02d:00", hour)
+		}
+	}
+
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, summary, nil)
+		return nil
+	}
+
+	printSummary(&summary, rng, prevRng)
+	if sumProjectEOD {
+		printEODProjections(&summary)
+	}
+	return nil
+}
+
+func printSummary(s *OrgSummary, rng, prevRng daterange.Range) {
+	cur := s.Currency
+	if s.Portfolio != "" {
+		fmt.Printf("%s (ID: %d), portfolio %s — %s\n", s.OrgName, s.OrgID, s.Portfolio, rng)
+	} else {
+		fmt.Printf("%s (ID: %d) — %s\n", s.OrgName, s.OrgID, rng)
+	}
+	fmt.Printf("Compared with %s\n\n", prevRng)
+	fmt.Printf("  Spend:     %12.2f %s  %s\n", s.Totals.Spend, cur, pctChange(s.Totals.Spend, s.PreviousTotals.Spend))
+	fmt.Printf("  Installs:  %12d      %s\n", s.Totals.Installs, pctChange(float64(s.Totals.Installs), float64(s.PreviousTotals.Installs)))
+	fmt.Printf("  CPI:       %12.2f %s  %s\n", s.CPI, cur, pctChange(s.CPI, s.PreviousCPI))
+
+	fmt.Printf("\nTOP CAMPAIGNS BY SPEND\n")
+	var top []summaryCampaignRow
+	for _, e := range s.TopCampaigns {
+		top = append(top, summaryCampaignRow{
+			ID:       e.ID,
+			Name:     e.Name,
+			Spend:    fmt.Sprintf("%.2f", e.Spend),
+			Installs: e.Installs,
+			CPI:      fmt.Sprintf("%.2f", e.CPI()),
+		})
+	}
+	output.Print(output.FormatTable, top, []output.Column{
+		{Header: "ID", Field: "ID"},
+		{Header: "NAME", Field: "Name"},
+		{Header: "SPEND", Field: "Spend"},
+		{Header: "INSTALLS", Field: "Installs"},
+		{Header: "CPI", Field: "CPI"},
+	})
+
+	fmt.Printf("\nBIGGEST MOVERS\n")
+	var movers []summaryMoverRow
+	for _, m := range s.Movers {
+		movers = append(movers, summaryMoverRow{
+			ID:        m.ID,
+			Name:      m.Name,
+			Spend:     fmt.Sprintf("%.2f", m.Spend),
+			PrevSpend: fmt.Sprintf("%.2f", m.PrevSpend),
+			Change:    fmt.Sprintf("%+.2f %s", m.Delta, pctChange(m.Spend, m.PrevSpend)),
+		})
+	}
+	output.Print(output.FormatTable, movers, []output.Column{
+		{Header: "ID", Field: "ID"},
+		{Header: "NAME", Field: "Name"},
+		{Header: "SPEND", Field: "Spend"},
+		{Header: "PREVIOUS", Field: "PrevSpend"},
+		{Header: "CHANGE", Field: "Change"},
+	})
+
+	fmt.Printf("\nSERVING ISSUES\n")
+	if len(s.ServingIssues) == 0 {
+		fmt.Println("None — all enabled campaigns are running.")
+		return
+	}
+	for _, issue := range s.ServingIssues {
+		fmt.Printf("  %s (ID: %d) — %s", issue.Name, issue.ID, issue.ServingStatus)
+		if len(issue.Reasons) > 0 {
+			fmt.Printf(": %s", strings.Join(issue.Reasons, ", "))
+		}
+		fmt.Println()
+	}
+}
+
+// pctChange formats the relative change from prev to cur, e.g. "(+12.3%)".
+func pctChange(cur, prev float64) string {
+	if prev == 0 {
+		if cur == 0 {
+			return "(—)"
+		}
+		return "(new)"
+	}
+	return fmt.Sprintf("(%+.1f%%)", (cur-prev)/prev*100)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/intraday"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+// minCurveDays is how many days of a campaign's own history its curve needs;
+// with fewer, the org-wide curve is used.
+const minCurveDays = 3
+
+// EODProjection is a campaign's projected end-of-day spend and installs.
+type EODProjection struct {
+	CampaignID        int64   `json:"campaignId"`
+	CampaignName      string  `json:"campaignName"`
+	SpendSoFar        float64 `json:"spendSoFar"`
+	InstallsSoFar     int64   `json:"installsSoFar"`
+	ProjectedSpend    float64 `json:"projectedSpend"`
+	ProjectedInstalls float64 `json:"projectedInstalls"`
+	DailyBudget       float64 `json:"dailyBudget,omitempty"`
+	// OfBudget is ProjectedSpend as a share of the daily budget.
+	OfBudget float64 `json:"ofBudget,omitempty"`
+	// Flag is "exhausts budget" or "misses budget", if either.
+	Flag        string `json:"flag,omitempty"`
+	ExhaustedAt string `json:"exhaustedAt,omitempty"`
+	// CurveDays is how many days of history shaped the projection; 0 means
+	// even pacing was assumed.
+	CurveDays int `json:"curveDays"`
+
+	SoFarText     string `json:"-"`
+	ProjectedText string `json:"-"`
+	BudgetText    string `json:"-"`
+	InstallsText  string `json:"-"`
+	FlagText      string `json:"-"`
+}
+
+// projectEOD projects today's end-of-day totals for the campaigns that are
+// enabled or spent today, from today's hourly data and the intraday curves of
+// the last --history days. It returns the last hour with data, or -1 when
+// today has none yet.
+func projectEOD(client *api.Client, campaigns []models.Campaign, today time.Time) ([]EODProjection, int, error) {
+	reports := services.NewReportingService(client)
+	store, err := intraday.Load(profileName)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	from, to := today.AddDate(0, 0, -sumHistory), today.AddDate(0, 0, -1)
+	var missing []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !store.Has(d) {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Fetching hourly history for %d day(s)...\n", len(missing))
+		for _, d := range missing {
+			days, _, err := hourlyByCampaign(reports, d)
+			if err != nil {
+				return nil, -1, err
+			}
+			store.Put(d, days)
+		}
+		if err := store.Save(time.Now()); err != nil && verbose {
+			warnf("%v", err)
+		}
+	}
+
+	todays, hour, err := hourlyByCampaign(reports, today)
+	if err != nil {
+		return nil, -1, err
+	}
+	if hour < 0 {
+		return []EODProjection{}, -1, nil
+	}
+
+	orgCurve := store.Curve(0, from, to)
+	projections := []EODProjection{}
+	for _, c := range campaigns {
+		d := todays[c.ID]
+		if d == nil && c.Status != models.StatusEnabled {
+			continue
+		}
+		if d == nil {
+			d = &intraday.Day{}
+		}
+		curve := store.Curve(c.ID, from, to)
+		if curve.Days < minCurveDays {
+			curve = orgCurve
+		}
+
+		p := EODProjection{CampaignID: c.ID, CampaignName: c.Name, CurveDays: curve.Days}
+		p.SpendSoFar, p.InstallsSoFar = d.Total()
+		spend := intraday.Project(p.SpendSoFar, curve.Spend[hour], hour)
+		p.ProjectedSpend = roundCents(spend)
+		p.ProjectedInstalls = intraday.Project(float64(p.InstallsSoFar), curve.Installs[hour], hour)
+		p.SpendSoFar = roundCents(p.SpendSoFar)
+		if c.DailyBudgetAmount != nil {
+			p.DailyBudget = aggregate.Amount(*c.DailyBudgetAmount)
+		}
+		if p.DailyBudget > 0 {
+			p.OfBudget = spend / p.DailyBudget
+			switch {
+			case p.OfBudget >= exhaustedShare:
+				p.Flag = "exhausts budget"
+				p.ExhaustedAt = exhaustionHour(spend, p.DailyBudget, curve, hour)
+			case p.OfBudget < sumMissShare:
+				p.Flag = "misses budget"
+			}
+		}
+
+		p.SoFarText = fmt.Sprintf("%.2f", p.SpendSoFar)
+		p.ProjectedText = fmt.Sprintf("%.2f", p.ProjectedSpend)
+		p.InstallsText = fmt.Sprintf("%d → %.0f", p.InstallsSoFar, p.ProjectedInstalls)
+		if p.DailyBudget > 0 {
+			p.BudgetText = fmt.Sprintf("%.2f (%.0f%%)", p.DailyBudget, p.OfBudget*100)
+		}
+		p.FlagText = p.Flag
+		if p.ExhaustedAt != "" {
+			p.FlagText += " ~" + p.ExhaustedAt
+		}
+		projections = append(projections, p)
+	}
+	sort.SliceStable(projections, func(i, j int) bool {
+		return projections[i].ProjectedSpend > projections[j].ProjectedSpend
+	})
+	return projections, hour, nil
+}
+
+// exhaustionHour returns the hour a day projected to spend total would reach
+// budget, following the curve from hour on.
+func exhaustionHour(total, budget float64, curve intraday.Curve, hour int) string {
+	for h := hour; h < 24; h++ {
+		if total*curve.Spend[h] >= exhaustedShare*budget {
+			return fmt.Sprintf("%02d:00", h)
+		}
+	}
+	return "23:00"
+}
+
+func printEODProjections(s *OrgSummary) {
+	fmt.Printf("\nEND-OF-DAY PROJECTION\n")
+	if s.ProjectedThrough == "" {
+		fmt.Println("No hourly data for today yet.")
+		return
+	}
+	output.Print(output.FormatTable, s.Projections, []output.Column{
+		{Header: "ID", Field: "CampaignID"},
+		{Header: "NAME", Field: "CampaignName"},
+		{Header: "SO FAR", Field: "SoFarText"},
+		{Header: "PROJECTED", Field: "ProjectedText"},
+		{Header: "BUDGET", Field: "BudgetText"},
+		{Header: "INSTALLS", Field: "InstallsText"},
+		{Header: "FLAG", Field: "FlagText"},
+	})
+	fmt.Printf("Data through the %s hour (org time zone); curves from the last %d day(s).\n", s.ProjectedThrough, sumHistory)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/tags"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Label campaigns and ad groups with local tags",
+	Long: `Apple Search Ads has no labels, so the CLI keeps its own: tags are stored
+locally per profile and can be used as a selector with --tag on campaigns
+list/find, reports export, and analyze term-overlap.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <campaign|adgroup> <id> <tag>...",
+	Short: "Add tags to an entity",
+	Args:  cobra.MinimumNArgs(3),
+	RunE:  runTagAdd,
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <campaign|adgroup> <id> <tag>...",
+	Short: "Remove tags from an entity",
+	Args:  cobra.MinimumNArgs(3),
+	RunE:  runTagRemove,
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tagged entities",
+	RunE:  runTagList,
+}
+
+var tagFilter string
+
+func init() {
+	tagListCmd.Flags().StringVar(&tagFilter, "tag", "", "Only entities with this tag")
+
+	tagCmd.AddCommand(tagAddCmd, tagRemoveCmd, tagListCmd)
+	rootCmd.AddCommand(tagCmd)
+}
+
+type tagRow struct {
+	Kind string   `json:"kind"`
+	ID   int64    `json:"id"`
+	Tags []string `json:"tags"`
+
+	TagList string `json:"-"`
+}
+
+func parseTagTarget(args []string) (string, int64, error) {
+	kind := strings.ToLower(args[0])
+	if !tags.ValidKind(kind) {
+		return "", 0, fmt.Errorf("invalid entity kind %q: expected campaign or adgroup", args[0])
+	}
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid %s ID: %s", kind, args[1])
+	}
+	return kind, id, nil
+}
+
+func runTagAdd(cmd *cobra.Command, args []string) error {
+	kind, id, err := parseTagTarget(args)
+	if err != nil {
+		return err
+	}
+	store, err := tags.Load(profileName)
+	if err != nil {
+		return err
+	}
+	if err := store.Add(kind, id, args[2:]...); err != nil {
+		return err
+	}
+	fmt.Printf("%s %d tags: %s\n", kind, id, strings.Join(store.Tags(kind, id), ", "))
+	return nil
+}
+
+func runTagRemove(cmd *cobra.Command, args []string) error {
+	kind, id, err := parseTagTarget(args)
+	if err != nil {
+		return err
+	}
+	store, err := tags.Load(profileName)
+	if err != nil {
+		return err
+	}
+	if err := store.Remove(kind, id, args[2:]...); err != nil {
+		return err
+	}
+	remaining := store.Tags(kind, id)
+	if len(remaining) == 0 {
+		fmt.Printf("%s %d has no tags.\n", kind, id)
+		return nil
+	}
+	fmt.Printf("%s %d tags: %s\n", kind, id, strings.Join(remaining, ", "))
+	return nil
+}
+
+func runTagList(cmd *cobra.Command, args []string) error {
+	store, err := tags.Load(profileName)
+	if err != nil {
+		return err
+	}
+
+	rows := []tagRow{}
+	want := tags.Normalize(tagFilter)
+	for _, e := range store.Entries() {
+		if want != "" && !slices.Contains(e.Tags, want) {
+			continue
+		}
+		rows = append(rows, tagRow{Kind: e.Kind, ID: e.ID, Tags: e.Tags, TagList: strings.Join(e.Tags, ", ")})
+	}
+
+	output.Print(getFormat(), rows, []output.Column{
+		{Header: "KIND", Field: "Kind"},
+		{Header: "ID", Field: "ID"},
+		{Header: "TAGS", Field: "TagList"},
+	})
+	return nil
+}
+
+// campaignIDsForTag returns the campaigns carrying tag.
+func campaignIDsForTag(tag string) ([]int64, error) {
+	store, err := tags.Load(profileName)
+	if err != nil {
+		return nil, err
+	}
+	ids := store.IDs(tags.KindCampaign, tag)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no campaigns tagged %q", tags.Normalize(tag))
+	}
+	return ids, nil
+}
+
+// filterCampaignsByTag keeps the campaigns carrying tag; an empty tag keeps all.
+func filterCampaignsByTag(campaigns []models.Campaign, tag string) ([]models.Campaign, error) {
+	if tag == "" {
+		return campaigns, nil
+	}
+	store, err := tags.Load(profileName)
+	if err != nil {
+		return nil, err
+	}
+	tagged := map[int64]bool{}
+	for _, id := range store.IDs(tags.KindCampaign, tag) {
+		tagged[id] = true
+	}
+	kept := []models.Campaign{}
+	for _, c := range campaigns {
+		if tagged[c.ID] {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/output"
+)
+
+// printChanges lists an update's field changes on stderr, before values in
+// red and after values in green. JSON output carries them instead (see
+// withChanges).
+func printChanges(what string, changes []models.FieldChange) {
+	if len(changes) == 0 || getFormat() == output.FormatJSON {
+		return
+	}
+	red, green := color.New(color.FgRed).SprintFunc(), color.New(color.FgGreen).SprintFunc()
+	fmt.Fprintf(os.Stderr, "Updated %s:\n", what)
+	for _, c := range changes {
+		fmt.Fprintf(os.Stderr, "  %s: %s → %s\n", c.Field, red(models.FormatValue(c.Before)), green(models.FormatValue(c.After)))
+	}
+}
+
+// withChanges adds a "changes" list to an updated entity for JSON output,
+// leaving its own fields as they are. Other formats get the entity alone.
+func withChanges(entity interface{}, changes []models.FieldChange) interface{} {
+	if getFormat() != output.FormatJSON {
+		return entity
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return entity
+	}
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return entity
+	}
+	if changes == nil {
+		changes = []models.FieldChange{}
+	}
+	fields["changes"] = changes
+	return fields
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/services"
+	"go.yaml.in/yaml/v3"
+)
+
+// patchFileUsage documents --file and --merge on the update commands.
+const patchFileUsage = `Update fields from a YAML or JSON document instead of flags ("-" for stdin),
+using the API's field names, e.g.:
+
+  dailyBudgetAmount: {amount: "80"}
+  locInvoiceDetails: {orderNumber: PO-1234}
+
+Only the fields in the document are sent. Nested objects replace the current
+ones unless --merge is set, which fills their missing fields from the
+entity's current values.`
+
+var (
+	patchFile  string
+	patchMerge bool
+)
+
+// registerPatchFlags adds --file and --merge to an update command.
+func registerPatchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&patchFile, "file", "", `YAML or JSON document of fields to update ("-" for stdin)`)
+	cmd.Flags().BoolVar(&patchMerge, "merge", false, "Merge nested objects in --file with the current values")
+	cmd.Long = strings.TrimSpace(cmd.Long + "\n\n" + patchFileUsage)
+}
+
+// checkPatchFlags rejects --merge without --file, and --file with any of the
+// command's field flags.
+func checkPatchFlags(cmd *cobra.Command, fieldFlags ...string) error {
+	if !cmd.Flags().Changed("file") {
+		if patchMerge {
+			return fmt.Errorf("--merge requires --file")
+		}
+		return nil
+	}
+	for _, name := range fieldFlags {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--file can't be combined with --%s", name)
+		}
+	}
+	return nil
+}
+
+// readPatch reads the --file document. JSON is read as YAML.
+func readPatch(path string) (map[string]interface{}, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading patch: %w", err)
+	}
+	patch := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("parsing patch %s: %w", path, err)
+	}
+	if len(patch) == 0 {
+		return nil, fmt.Errorf("patch %s has no fields", path)
+	}
+	stringifyAmounts(patch)
+	return patch, nil
+}
+
+// stringifyAmounts turns numeric money amounts (amount: 80) into the strings
+// the API expects.
+func stringifyAmounts(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			switch val.(type) {
+			case int, int64, float64:
+				if k == "amount" {
+					v[k] = fmt.Sprint(val)
+				}
+			default:
+				stringifyAmounts(val)
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			stringifyAmounts(val)
+		}
+	}
+}
+
+// mergePatch fills the nested objects of patch with current's fields that
+// the patch doesn't set. Lists and scalars in the patch replace the current
+// values, and fields outside the patch are left out.
+func mergePatch(current interface{}, patch map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(current)
+	if err != nil {
+		return nil, fmt.Errorf("encoding current values: %w", err)
+	}
+	cur := map[string]interface{}{}
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, fmt.Errorf("decoding current values: %w", err)
+	}
+	merged := map[string]interface{}{}
+	for k, v := range patch {
+		merged[k] = mergeValue(cur[k], v)
+	}
+	return merged, nil
+}
+
+func mergeValue(current, patch interface{}) interface{} {
+	cm, ok1 := current.(map[string]interface{})
+	pm, ok2 := patch.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return patch
+	}
+	out := map[string]interface{}{}
+	for k, v := range cm {
+		out[k] = v
+	}
+	for k, v := range pm {
+		out[k] = mergeValue(cm[k], v)
+	}
+	return out
+}
+
+// decodePatch decodes a patch into an update payload, rejecting fields the
+// payload doesn't have.
+func decodePatch(patch map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("encoding patch: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("invalid patch: %w (updatable fields: %s)", err, strings.Join(jsonFields(out), ", "))
+	}
+	if err := models.CheckEnums(out); err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+	return nil
+}
+
+// jsonFields lists the JSON field names of the struct v points to.
+func jsonFields(v interface{}) []string {
+	t := reflect.TypeOf(v).Elem()
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// patchCampaign builds a campaign update from --file.
+func patchCampaign(client *api.Client, id int64) (*models.CampaignUpdate, error) {
+	patch, err := readPatch(patchFile)
+	if err != nil {
+		return nil, err
+	}
+	if patchMerge {
+		current, err := services.NewCampaignService(client).Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("getting campaign: %w", err)
+		}
+		if patch, err = mergePatch(current, patch); err != nil {
+			return nil, err
+		}
+	}
+	update := &models.CampaignUpdate{}
+	if err := decodePatch(patch, update); err != nil {
+		return nil, err
+	}
+
+	if update.Status != "" {
+		if update.Status, err = models.ParseStatus(string(update.Status)); err != nil {
+			return nil, err
+		}
+	}
+	if update.Name != "" {
+		if err := checkCampaignName(update.Name); err != nil {
+			return nil, err
+		}
+	}
+	if update.DailyBudgetAmount != nil {
+		if err := checkBudgetLimit(update.DailyBudgetAmount.Amount); err != nil {
+			return nil, err
+		}
+	}
+	return update, fillCurrency(client, update.BudgetAmount, update.DailyBudgetAmount)
+}
+
+// patchAdGroup builds an ad group update from --file.
+func patchAdGroup(client *api.Client, campaignID, id int64) (*models.AdGroupUpdate, error) {
+	patch, err := readPatch(patchFile)
+	if err != nil {
+		return nil, err
+	}
+	if patchMerge {
+		current, err := services.NewAdGroupService(client).Get(campaignID, id)
+		if err != nil {
+			return nil, fmt.Errorf("getting ad group: %w", err)
+		}
+		if patch, err = mergePatch(current, patch); err != nil {
+			return nil, err
+		}
+	}
+	update := &models.AdGroupUpdate{}
+	if err := decodePatch(patch, update); err != nil {
+		return nil, err
+	}
+
+	if update.Status != "" {
+		if update.Status, err = models.ParseStatus(string(update.Status)); err != nil {
+			return nil, err
+		}
+	}
+	if update.Name != "" {
+		if err := checkAdGroupName(update.Name); err != nil {
+			return nil, err
+		}
+	}
+	if update.DefaultBidAmount != nil {
+		if err := checkBidLimit(update.DefaultBidAmount.Amount); err != nil {
+			return nil, err
+		}
+	}
+	return update, fillCurrency(client, update.DefaultBidAmount, update.CpaGoal)
+}
+
+// fillCurrency sets the org's currency on amounts given without one.
+func fillCurrency(client *api.Client, amounts ...*models.Money) error {
+	for _, m := range amounts {
+		if m == nil || m.Currency != "" {
+			continue
+		}
+		currency, err := resolveOrgCurrency(client)
+		if err != nil {
+			return err
+		}
+		m.Currency = currency
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/output"
+	"github.com/trebuhs/asa-cli/internal/session"
+)
+
+var useCmd = &cobra.Command{
+	Use:   "use <org|campaign|adgroup> <id>",
+	Short: "Set the current org, campaign, or ad group for later commands",
+	Long: `Save a current org, campaign, or ad group for the profile. Later commands that
+take --org-id, --campaign-id, or --adgroup-id use it when the flag isn't given;
+explicit flags and --account always win. Setting a campaign clears the ad
+group. The context is shared with the interactive shell.
+
+Example:
+  asa-cli use org 12345
+  asa-cli use campaign 678
+  asa-cli keywords list --adgroup-id 91011`,
+	Args: cobra.ExactArgs(2),
+	RunE: runUse,
+}
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Show or clear the saved org, campaign, and ad group",
+	Args:  cobra.NoArgs,
+	RunE:  runContextShow,
+}
+
+var contextShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the saved context",
+	Args:  cobra.NoArgs,
+	RunE:  runContextShow,
+}
+
+var contextClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the saved context",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := session.Clear(profileName); err != nil {
+			return err
+		}
+		fmt.Println("Context cleared.")
+		return nil
+	},
+}
+
+func init() {
+	contextCmd.AddCommand(contextShowCmd, contextClearCmd)
+	rootCmd.AddCommand(useCmd, contextCmd)
+}
+
+func runUse(cmd *cobra.Command, args []string) error {
+	ctx, err := session.Load(profileName)
+	if err != nil {
+		return err
+	}
+	if err := setSessionContext(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+	if err := session.Save(profileName, ctx); err != nil {
+		return err
+	}
+	fmt.Printf("Now using %s %s.\n", args[0], args[1])
+	return nil
+}
+
+func runContextShow(cmd *cobra.Command, args []string) error {
+	ctx, err := session.Load(profileName)
+	if err != nil {
+		return err
+	}
+	if getFormat() == output.FormatJSON {
+		output.Print(output.FormatJSON, ctx, nil)
+		return nil
+	}
+	printSessionContext(ctx)
+	return nil
+}
+
+// setSessionContext sets one level of the context from a "use" command.
+func setSessionContext(ctx *session.Context, kind, value string) error {
+	id, err := strconv.ParseInt(value, 10, 64)
+	switch kind {
+	case "org":
+		if err != nil {
+			return fmt.Errorf("invalid org ID: %s", value)
+		}
+		ctx.OrgID = value
+	case "campaign":
+		if err != nil {
+			return fmt.Errorf("invalid campaign ID: %s", value)
+		}
+		ctx.CampaignID = id
+		ctx.AdGroupID = 0
+	case "adgroup":
+		if err != nil {
+			return fmt.Errorf("invalid ad group ID: %s", value)
+		}
+		ctx.AdGroupID = id
+	default:
+		return fmt.Errorf("unknown context type %q (use org, campaign or adgroup)", kind)
+	}
+	return nil
+}
+
+// applySavedContext fills --org-id, --campaign-id, and --adgroup-id from the
+// saved context when the command takes them and they weren't given. The ad
+// group only applies within the context's campaign.
+func applySavedContext(cmd *cobra.Command) error {
+	ctx, err := session.Load(profileName)
+	if err != nil || ctx.IsEmpty() {
+		return nil // don't block on a damaged session file
+	}
+	flags := cmd.Flags()
+	set := func(name, value string) error {
+		f := flags.Lookup(name)
+		if f == nil || f.Changed {
+			return nil
+		}
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid context value for --%s: %w", name, err)
+		}
+		// Required flags are validated by their changed state.
+		f.Changed = true
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Using --%s %s from context\n", name, value)
+		}
+		return nil
+	}
+
+	if ctx.OrgID != "" && accountName == "" {
+		if err := set("org-id", ctx.OrgID); err != nil {
+			return err
+		}
+	}
+	if ctx.CampaignID == 0 {
+		return nil
+	}
+	campaignID := strconv.FormatInt(ctx.CampaignID, 10)
+	if f := flags.Lookup("campaign-id"); f != nil && f.Changed && f.Value.String() != campaignID {
+		return nil
+	}
+	if err := set("campaign-id", campaignID); err != nil {
+		return err
+	}
+	if ctx.AdGroupID != 0 {
+		return set("adgroup-id", strconv.FormatInt(ctx.AdGroupID, 10))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/trebuhs/asa-cli/internal/weburl"
+)
+
+// linkFlagKinds are the ID flags that take a web UI link, by the kind of ID
+// they are given.
+var linkFlagKinds = map[string]string{
+	"campaign-id": weburl.KindCampaign,
+	"adgroup-id":  weburl.KindAdGroup,
+	"keyword-id":  weburl.KindKeyword,
+	"ad-id":       weburl.KindAd,
+}
+
+var linkKindNames = map[string]string{
+	"org":               "org",
+	weburl.KindCampaign: "campaign",
+	weburl.KindAdGroup:  "ad group",
+	weburl.KindKeyword:  "keyword",
+	weburl.KindAd:       "ad",
+}
+
+// commandKinds are the command groups whose <id> argument is of one kind.
+var commandKinds = map[string]string{
+	"campaigns": weburl.KindCampaign,
+	"adgroups":  weburl.KindAdGroup,
+	"keywords":  weburl.KindKeyword,
+	"ads":       weburl.KindAd,
+}
+
+// expandWebLinks replaces links to the Search Ads web UI, given for an ID
+// argument or ID flag, with the ID they point at. The org and parent IDs in
+// the link are added as --org-id, --campaign-id and --adgroup-id when the
+// command accepts them and they weren't given.
+func expandWebLinks(args []string) ([]string, error) {
+	target, _, err := rootCmd.Find(args)
+	if err != nil || target == rootCmd {
+		return args, nil
+	}
+
+	out := append([]string(nil), args...)
+	var parents weburl.Link
+	for i, a := range out {
+		var f *pflag.Flag
+		prefix, value := "", a
+		switch {
+		case strings.HasPrefix(a, "-"):
+			name, v, ok := strings.Cut(a, "=")
+			if !ok {
+				continue
+			}
+			f, prefix, value = lookupFlag(target, name), name+"=", v
+		case i > 0 && flagTakesValue(target, out[i-1]):
+			f = lookupFlag(target, out[i-1])
+		}
+		if !weburl.IsLink(value) {
+			continue
+		}
+		link, err := weburl.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		var kind string
+		var id int64
+		switch {
+		case f != nil && f.Name == "org-id":
+			kind, id = "org", link.OrgID
+		case f != nil:
+			if kind = linkFlagKinds[f.Name]; kind == "" {
+				return nil, fmt.Errorf("--%s doesn't take a link", f.Name)
+			}
+			id = link.ID(kind)
+		default:
+			kind = linkArgKind(target, out[:i], link)
+			id = link.ID(kind)
+		}
+		if id == 0 {
+			return nil, fmt.Errorf("link has no %s ID: %s", linkKindNames[kind], value)
+		}
+		out[i] = prefix + strconv.FormatInt(id, 10)
+
+		if err := mergeLinkOrg(&parents, link); err != nil {
+			return nil, err
+		}
+		switch kind {
+		case weburl.KindAdGroup:
+			parents.CampaignID = link.CampaignID
+		case weburl.KindKeyword, weburl.KindAd:
+			parents.CampaignID, parents.AdGroupID = link.CampaignID, link.AdGroupID
+		}
+	}
+	return addLinkParents(target, out, parents)
+}
+
+// linkArgKind works out the kind of ID a positional argument takes: the type
+// named before it (get adgroup <id>), the command group's (campaigns get
+// <id>), or else the most specific entity in the link (inspect <id>).
+func linkArgKind(target *cobra.Command, before []string, link weburl.Link) string {
+	if n := len(before); n > 0 && containsString(inspectTypes, strings.ToLower(before[n-1])) {
+		return strings.ToLower(before[n-1])
+	}
+	for c := target; c != nil; c = c.Parent() {
+		if kind, ok := commandKinds[c.Name()]; ok {
+			return kind
+		}
+	}
+	return link.Kind()
+}
+
+func mergeLinkOrg(parents *weburl.Link, link weburl.Link) error {
+	if link.OrgID == 0 {
+		return nil
+	}
+	if parents.OrgID != 0 && parents.OrgID != link.OrgID {
+		return fmt.Errorf("links are for different orgs (%d and %d)", parents.OrgID, link.OrgID)
+	}
+	parents.OrgID = link.OrgID
+	return nil
+}
+
+// addLinkParents adds the org and parent IDs read from links as flags. An
+// --org-id given for another org is an error, since the IDs in the link
+// would not be found there.
+func addLinkParents(target *cobra.Command, args []string, parents weburl.Link) ([]string, error) {
+	given := func(name string) (string, bool) {
+		for i, a := range args {
+			if a == "--"+name && i+1 < len(args) {
+				return args[i+1], true
+			}
+			if v, ok := strings.CutPrefix(a, "--"+name+"="); ok {
+				return v, true
+			}
+		}
+		return "", false
+	}
+	add := func(name string, id int64) {
+		if id == 0 || target.Flags().Lookup(name) == nil && target.InheritedFlags().Lookup(name) == nil {
+			return
+		}
+		if _, ok := given(name); ok {
+			return
+		}
+		args = append(args, "--"+name, strconv.FormatInt(id, 10))
+	}
+
+	if parents.OrgID != 0 {
+		if v, ok := given("org-id"); ok && v != strconv.FormatInt(parents.OrgID, 10) {
+			return nil, fmt.Errorf("link is for org %d, but --org-id is %s", parents.OrgID, v)
+		}
+	}
+	add("org-id", parents.OrgID)
+	add("campaign-id", parents.CampaignID)
+	add("adgroup-id", parents.AdGroupID)
+	return args, nil
+}
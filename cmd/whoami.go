@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/trebuhs/asa-cli/internal/aclcache"
+	"github.com/trebuhs/asa-cli/internal/config"
 	"github.com/trebuhs/asa-cli/internal/output"
 	"github.com/trebuhs/asa-cli/internal/services"
 )
@@ -31,6 +33,9 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("fetching ACLs: %w", err)
 	}
+	if cfg, err := config.Load(); err == nil {
+		_ = aclcache.Save(profileName, cfg.ClientID, acls)
+	}
 
 	if len(acls) == 0 {
 		fmt.Println("No organizations found.")
@@ -41,6 +46,8 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 		{Header: "ORG NAME", Field: "OrgName", Width: 30},
 		{Header: "ORG ID", Field: "OrgID", Width: 15},
 		{Header: "CURRENCY", Field: "Currency", Width: 10},
+		{Header: "TIME ZONE", Field: "TimeZone", Width: 20},
+		{Header: "PAYMENT", Field: "PaymentModel", Width: 8},
 		{Header: "ROLES", Field: "RoleNames", Width: 40},
 	})
 
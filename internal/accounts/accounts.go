@@ -0,0 +1,94 @@
+// Package accounts is a registry of client accounts for agencies managing
+// many organizations: each friendly name maps to a config profile and org.
+package accounts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+	"go.yaml.in/yaml/v3"
+)
+
+// Account is one registered client.
+type Account struct {
+	Name    string `yaml:"-" json:"name"`
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	OrgID   string `yaml:"org_id" json:"orgId"`
+}
+
+// Registry is the contents of accounts.yaml.
+type Registry struct {
+	Accounts map[string]Account `yaml:"accounts"`
+}
+
+// Path returns the registry file. It is shared by all profiles.
+func Path() string {
+	return filepath.Join(config.ConfigDir(), "accounts.yaml")
+}
+
+// Load reads the registry. A missing file yields an empty registry.
+func Load() (*Registry, error) {
+	r := &Registry{Accounts: map[string]Account{}}
+	data, err := os.ReadFile(Path())
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading accounts: %w", err)
+	}
+	if err := yaml.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("parsing accounts: %w", err)
+	}
+	if r.Accounts == nil {
+		r.Accounts = map[string]Account{}
+	}
+	return r, nil
+}
+
+// Get returns an account by name.
+func (r *Registry) Get(name string) (Account, error) {
+	a, ok := r.Accounts[strings.ToLower(name)]
+	if !ok {
+		return Account{}, fmt.Errorf("account %q not found in %s (see: asa-cli accounts list)", name, Path())
+	}
+	a.Name = strings.ToLower(name)
+	return a, nil
+}
+
+// List returns all accounts sorted by name.
+func (r *Registry) List() []Account {
+	out := make([]Account, 0, len(r.Accounts))
+	for name, a := range r.Accounts {
+		a.Name = name
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Set adds or replaces an account and saves the registry.
+func (r *Registry) Set(a Account) error {
+	r.Accounts[strings.ToLower(a.Name)] = a
+	return r.save()
+}
+
+// Remove deletes an account and saves the registry.
+func (r *Registry) Remove(name string) error {
+	delete(r.Accounts, strings.ToLower(name))
+	return r.save()
+}
+
+func (r *Registry) save() error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := config.WriteFileAtomic(Path(), data); err != nil {
+		return fmt.Errorf("writing accounts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package aclcache keeps the /acls response per profile for a while, so
+// commands that pick the org automatically don't fetch it on every run.
+package aclcache
+
+import (
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// DefaultTTL is how long a cached response is used when acl_cache_ttl is not
+// set.
+const DefaultTTL = 24 * time.Hour
+
+// Entry is a cached /acls response.
+type Entry struct {
+	ClientID  string           `json:"clientId"`
+	FetchedAt time.Time        `json:"fetchedAt"`
+	ACLs      []models.UserACL `json:"acls"`
+}
+
+// Path returns the cache file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("acls", profile)
+}
+
+// Load returns the cached ACLs for a profile if they are younger than ttl.
+// When clientID is set, a response cached for another API client is ignored.
+func Load(profile, clientID string, ttl time.Duration) ([]models.UserACL, bool) {
+	var e Entry
+	if config.LoadJSON(Path(profile), "ACL cache", &e) != nil || len(e.ACLs) == 0 {
+		return nil, false
+	}
+	if clientID != "" && e.ClientID != clientID {
+		return nil, false
+	}
+	if time.Since(e.FetchedAt) > ttl {
+		return nil, false
+	}
+	return e.ACLs, true
+}
+
+// Save caches a /acls response for a profile.
+func Save(profile, clientID string, acls []models.UserACL) error {
+	return config.SaveJSON(Path(profile), "ACL cache", Entry{ClientID: clientID, FetchedAt: time.Now().UTC(), ACLs: acls})
+}
@@ -0,0 +1,244 @@
+package aggregate
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// Metrics is a summable set of report metrics.
+type Metrics struct {
+	Impressions int64   `json:"impressions"`
+	Taps        int64   `json:"taps"`
+	Installs    int64   `json:"installs"`
+	Spend       float64 `json:"spend"`
+	Currency    string  `json:"currency,omitempty"`
+}
+
+// Add accumulates a report metrics row.
+func (m *Metrics) Add(row *models.SpendRow) {
+	if row == nil {
+		return
+	}
+	m.Impressions += row.Impressions
+	m.Taps += row.Taps
+	m.Installs += row.TotalInstalls
+	m.Spend += Amount(row.LocalSpend)
+	if m.Currency == "" {
+		m.Currency = row.LocalSpend.Currency
+	}
+}
+
+// Merge accumulates another Metrics value.
+func (m *Metrics) Merge(o Metrics) {
+	m.Impressions += o.Impressions
+	m.Taps += o.Taps
+	m.Installs += o.Installs
+	m.Spend += o.Spend
+	if m.Currency == "" {
+		m.Currency = o.Currency
+	}
+}
+
+// CPI is spend per install, or 0 with no installs.
+func (m Metrics) CPI() float64 { return ratio(m.Spend, float64(m.Installs)) }
+
+// CPT is spend per tap, or 0 with no taps.
+func (m Metrics) CPT() float64 { return ratio(m.Spend, float64(m.Taps)) }
+
+// CPM is spend per thousand impressions.
+func (m Metrics) CPM() float64 { return 1000 * ratio(m.Spend, float64(m.Impressions)) }
+
+// TTR is taps per impression.
+func (m Metrics) TTR() float64 { return ratio(float64(m.Taps), float64(m.Impressions)) }
+
+// ConversionRate is installs per tap.
+func (m Metrics) ConversionRate() float64 { return ratio(float64(m.Installs), float64(m.Taps)) }
+
+// Value returns a metric by name (see MetricNames).
+func (m Metrics) Value(name string) (float64, bool) {
+	d, ok := LookupMetric(name)
+	if !ok {
+		return 0, false
+	}
+	return d.Compute(m), true
+}
+
+// Entity is a report entity (campaign, ad group, keyword, ...) with totals.
+type Entity struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Metrics
+}
+
+// ByEntity totals report rows per entity, identified by the given metadata keys
+// (e.g. "campaignId" and "campaignName").
+func ByEntity(resp *models.ReportingDataResponse, idKey, nameKey string) []Entity {
+	if resp == nil {
+		return nil
+	}
+	index := map[int64]int{}
+	var out []Entity
+	for _, row := range resp.Row {
+		id := MetaInt64(row.Metadata, idKey)
+		i, ok := index[id]
+		if !ok {
+			i = len(out)
+			index[id] = i
+			out = append(out, Entity{ID: id, Name: MetaString(row.Metadata, nameKey)})
+		}
+		out[i].Metrics.Merge(RowTotals(row))
+	}
+	return out
+}
+
+// RowTotals returns a row's total metrics, summing granularity buckets if the
+// row has no total.
+func RowTotals(row models.ReportRow) Metrics {
+	var m Metrics
+	if row.Total != nil {
+		m.Add(row.Total)
+		return m
+	}
+	for _, g := range row.Granularity {
+		m.Add(g.Metrics)
+	}
+	return m
+}
+
+// Total sums metrics across entities.
+func Total(entities []Entity) Metrics {
+	var m Metrics
+	for _, e := range entities {
+		m.Merge(e.Metrics)
+	}
+	return m
+}
+
+// TopBySpend returns up to n entities with the highest spend.
+func TopBySpend(entities []Entity, n int) []Entity {
+	sorted := append([]Entity(nil), entities...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Spend > sorted[j].Spend })
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// Mover is an entity's spend change between two periods.
+type Mover struct {
+	ID        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Spend     float64 `json:"spend"`
+	PrevSpend float64 `json:"previousSpend"`
+	Delta     float64 `json:"delta"`
+	// DeltaPct is the relative change, or 0 when there was no prior spend.
+	DeltaPct float64 `json:"deltaPct"`
+}
+
+// Movers compares current and previous period entities and returns up to n
+// with the largest absolute spend change.
+func Movers(current, previous []Entity, n int) []Mover {
+	prev := map[int64]Entity{}
+	for _, e := range previous {
+		prev[e.ID] = e
+	}
+	seen := map[int64]bool{}
+	var movers []Mover
+	add := func(id int64, name string, cur, before float64) {
+		m := Mover{ID: id, Name: name, Spend: cur, PrevSpend: before, Delta: cur - before}
+		if before > 0 {
+			m.DeltaPct = m.Delta / before
+		}
+		movers = append(movers, m)
+	}
+	for _, e := range current {
+		seen[e.ID] = true
+		add(e.ID, e.Name, e.Spend, prev[e.ID].Spend)
+	}
+	for _, e := range previous {
+		if !seen[e.ID] {
+			add(e.ID, e.Name, 0, e.Spend)
+		}
+	}
+	sort.SliceStable(movers, func(i, j int) bool {
+		return math.Abs(movers[i].Delta) > math.Abs(movers[j].Delta)
+	})
+	if n > 0 && len(movers) > n {
+		movers = movers[:n]
+	}
+	return movers
+}
+
+// Amount parses a Money amount, returning 0 if it is empty or invalid.
+func Amount(m models.Money) float64 {
+	v, _ := strconv.ParseFloat(m.Amount, 64)
+	return v
+}
+
+// MetaInt64 reads a numeric metadata value.
+func MetaInt64(meta map[string]interface{}, key string) int64 {
+	switch v := meta[key].(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// MetaString reads a string metadata value.
+func MetaString(meta map[string]interface{}, key string) string {
+	if v, ok := meta[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// SearchTerm is a search term with totals across the rows it appeared in.
+type SearchTerm struct {
+	Term string `json:"term"`
+	Metrics
+}
+
+// BySearchTerm totals a search-term report per normalized (lowercased,
+// trimmed) term. Rows without a term (Apple's low-volume bucket) are skipped.
+func BySearchTerm(resp *models.ReportingDataResponse) []SearchTerm {
+	if resp == nil {
+		return nil
+	}
+	index := map[string]int{}
+	var out []SearchTerm
+	for _, row := range resp.Row {
+		term := NormalizeTerm(MetaString(row.Metadata, "searchTermText"))
+		if term == "" {
+			continue
+		}
+		i, ok := index[term]
+		if !ok {
+			i = len(out)
+			index[term] = i
+			out = append(out, SearchTerm{Term: term})
+		}
+		out[i].Metrics.Merge(RowTotals(row))
+	}
+	return out
+}
+
+// NormalizeTerm lowercases a term and collapses whitespace.
+func NormalizeTerm(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,89 @@
+package aggregate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetricKind says how a metric's values are shown.
+type MetricKind int
+
+const (
+	KindCount MetricKind = iota
+	KindMoney
+	KindRate
+)
+
+// Metric defines a report metric that tables, exports, and charts can show.
+type Metric struct {
+	Name   string
+	Header string
+	Kind   MetricKind
+	// Derived metrics are computed here from summed counts rather than read
+	// from the report, so they stay correct when rows are combined.
+	Derived bool
+	Compute func(Metrics) float64
+}
+
+// Registry lists every known metric, in display order.
+var Registry = []Metric{
+	{Name: "impressions", Header: "IMPRESSIONS", Kind: KindCount, Compute: func(m Metrics) float64 { return float64(m.Impressions) }},
+	{Name: "taps", Header: "TAPS", Kind: KindCount, Compute: func(m Metrics) float64 { return float64(m.Taps) }},
+	{Name: "installs", Header: "INSTALLS", Kind: KindCount, Compute: func(m Metrics) float64 { return float64(m.Installs) }},
+	{Name: "spend", Header: "SPEND", Kind: KindMoney, Compute: func(m Metrics) float64 { return m.Spend }},
+	{Name: "cpi", Header: "CPI", Kind: KindMoney, Derived: true, Compute: Metrics.CPI},
+	{Name: "cpt", Header: "CPT", Kind: KindMoney, Derived: true, Compute: Metrics.CPT},
+	{Name: "cpm", Header: "CPM", Kind: KindMoney, Derived: true, Compute: Metrics.CPM},
+	{Name: "ttr", Header: "TTR", Kind: KindRate, Derived: true, Compute: Metrics.TTR},
+	{Name: "cr", Header: "CR", Kind: KindRate, Derived: true, Compute: Metrics.ConversionRate},
+}
+
+// MetricNames lists the metric names accepted by Value and ParseMetrics.
+var MetricNames = metricNames()
+
+// DefaultMetrics is the metric list reports show unless told otherwise.
+const DefaultMetrics = "impressions,taps,installs,spend,cpi,cpt,ttr,cr"
+
+func metricNames() []string {
+	names := make([]string, len(Registry))
+	for i, d := range Registry {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// LookupMetric finds a metric by name, case-insensitively.
+func LookupMetric(name string) (Metric, bool) {
+	for _, d := range Registry {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+	}
+	return Metric{}, false
+}
+
+// ParseMetrics resolves metric names, e.g. from a --metrics flag.
+func ParseMetrics(names []string) ([]Metric, error) {
+	var out []Metric
+	for _, name := range names {
+		d, ok := LookupMetric(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown metric %q (expected %s)", name, strings.Join(MetricNames, ", "))
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// Format renders the metric's value in m for display: counts as integers,
+// money with two decimals, and rates as percentages.
+func (d Metric) Format(m Metrics) string {
+	v := d.Compute(m)
+	switch d.Kind {
+	case KindMoney:
+		return fmt.Sprintf("%.2f", v)
+	case KindRate:
+		return fmt.Sprintf("%.2f%%", v*100)
+	}
+	return fmt.Sprintf("%d", int64(v))
+}
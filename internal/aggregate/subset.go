@@ -0,0 +1,74 @@
+package aggregate
+
+import (
+	"strconv"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// Subset returns a copy of resp holding only the rows whose idKey metadata is
+// in ids. Grand totals, when reported, are recomputed from the kept rows.
+func Subset(resp *models.ReportingDataResponse, idKey string, ids map[int64]bool) *models.ReportingDataResponse {
+	if resp == nil {
+		return nil
+	}
+	out := *resp
+	out.Row = nil
+	for _, row := range resp.Row {
+		if ids[MetaInt64(row.Metadata, idKey)] {
+			out.Row = append(out.Row, row)
+		}
+	}
+	if resp.GrandTotals != nil {
+		var total models.SpendRow
+		for _, row := range out.Row {
+			if row.Total != nil {
+				sumSpendRow(&total, row.Total)
+				continue
+			}
+			for _, g := range row.Granularity {
+				sumSpendRow(&total, g.Metrics)
+			}
+		}
+		finishSpendRow(&total)
+		out.GrandTotals = &models.ReportRow{Total: &total}
+	}
+	return &out
+}
+
+// sumSpendRow adds the counts and spend of r to total.
+func sumSpendRow(total, r *models.SpendRow) {
+	if r == nil {
+		return
+	}
+	total.Impressions += r.Impressions
+	total.Taps += r.Taps
+	total.TotalInstalls += r.TotalInstalls
+	total.TapInstalls += r.TapInstalls
+	total.ViewInstalls += r.ViewInstalls
+	total.TotalNewDownloads += r.TotalNewDownloads
+	total.TapNewDownloads += r.TapNewDownloads
+	total.ViewNewDownloads += r.ViewNewDownloads
+	total.TotalRedownloads += r.TotalRedownloads
+	total.TapRedownloads += r.TapRedownloads
+	total.ViewRedownloads += r.ViewRedownloads
+	total.LocalSpend.Amount = strconv.FormatFloat(Amount(total.LocalSpend)+Amount(r.LocalSpend), 'f', 2, 64)
+	if total.LocalSpend.Currency == "" {
+		total.LocalSpend.Currency = r.LocalSpend.Currency
+	}
+}
+
+// finishSpendRow derives a summed row's rates and averages from its counts.
+func finishSpendRow(r *models.SpendRow) {
+	spend := Amount(r.LocalSpend)
+	money := func(v float64) models.Money {
+		return models.Money{Amount: strconv.FormatFloat(v, 'f', 2, 64), Currency: r.LocalSpend.Currency}
+	}
+	r.TTR = ratio(float64(r.Taps), float64(r.Impressions))
+	r.TotalInstallRate = ratio(float64(r.TotalInstalls), float64(r.Taps))
+	r.TapInstallRate = ratio(float64(r.TapInstalls), float64(r.Taps))
+	r.AvgCPT = money(ratio(spend, float64(r.Taps)))
+	r.AvgCPM = money(1000 * ratio(spend, float64(r.Impressions)))
+	r.TapInstallCPI = money(ratio(spend, float64(r.TapInstalls)))
+	r.TotalAvgCPI = money(ratio(spend, float64(r.TotalInstalls)))
+}
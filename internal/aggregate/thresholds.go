@@ -0,0 +1,47 @@
+package aggregate
+
+import "github.com/trebuhs/asa-cli/internal/models"
+
+// Thresholds drop report rows with too little volume or too high a cost.
+// Zero fields are not applied.
+type Thresholds struct {
+	MinSpend       float64
+	MinImpressions int64
+	// MaxCPI also drops rows that spent without any installs.
+	MaxCPI float64
+}
+
+// IsZero reports whether no threshold is set.
+func (t Thresholds) IsZero() bool {
+	return t == Thresholds{}
+}
+
+// Keep reports whether totals m pass every threshold.
+func (t Thresholds) Keep(m Metrics) bool {
+	if t.MinSpend > 0 && m.Spend < t.MinSpend {
+		return false
+	}
+	if t.MinImpressions > 0 && m.Impressions < t.MinImpressions {
+		return false
+	}
+	if t.MaxCPI > 0 && m.Spend > 0 && (m.Installs == 0 || m.CPI() > t.MaxCPI) {
+		return false
+	}
+	return true
+}
+
+// Filter returns a copy of resp holding only the rows whose totals pass, and
+// how many rows were dropped. Grand totals are left as reported.
+func (t Thresholds) Filter(resp *models.ReportingDataResponse) (*models.ReportingDataResponse, int) {
+	if resp == nil || t.IsZero() {
+		return resp, 0
+	}
+	out := *resp
+	out.Row = nil
+	for _, row := range resp.Row {
+		if t.Keep(RowTotals(row)) {
+			out.Row = append(out.Row, row)
+		}
+	}
+	return &out, len(resp.Row) - len(out.Row)
+}
@@ -0,0 +1,83 @@
+package aggregate
+
+import (
+	"sort"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// Node is one entity in a campaign → ad group → keyword rollup.
+type Node struct {
+	Level string `json:"level"`
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	// Share is the node's fraction of its parent's spend.
+	Share float64 `json:"shareOfParentSpend"`
+	Metrics
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Tree nests ad group and keyword report rows under their campaign. Keywords
+// are attached to ad groups by the adGroupId in their metadata; rows whose
+// ad group is missing from the ad group report get a placeholder parent so
+// no spend is lost. Children are sorted by spend, descending.
+func Tree(campaignID int64, campaigns, adgroups, keywords *models.ReportingDataResponse) *Node {
+	root := &Node{Level: "campaign", ID: campaignID}
+	for _, e := range ByEntity(campaigns, "campaignId", "campaignName") {
+		if e.ID == campaignID {
+			root.Name = e.Name
+			root.Metrics = e.Metrics
+		}
+	}
+
+	groups := map[int64]*Node{}
+	for _, e := range ByEntity(adgroups, "adGroupId", "adGroupName") {
+		n := &Node{Level: "adgroup", ID: e.ID, Name: e.Name, Metrics: e.Metrics}
+		groups[e.ID] = n
+		root.Children = append(root.Children, n)
+	}
+
+	if keywords != nil {
+		for _, row := range keywords.Row {
+			groupID := MetaInt64(row.Metadata, "adGroupId")
+			parent, ok := groups[groupID]
+			if !ok {
+				parent = &Node{Level: "adgroup", ID: groupID, Name: MetaString(row.Metadata, "adGroupName")}
+				groups[groupID] = parent
+				root.Children = append(root.Children, parent)
+			}
+			parent.Children = append(parent.Children, &Node{
+				Level:   "keyword",
+				ID:      MetaInt64(row.Metadata, "keywordId"),
+				Name:    MetaString(row.Metadata, "keyword"),
+				Metrics: RowTotals(row),
+			})
+		}
+	}
+
+	// Fall back to summing children where a parent level had no report data.
+	for _, g := range root.Children {
+		if g.Metrics == (Metrics{}) {
+			for _, k := range g.Children {
+				g.Metrics.Merge(k.Metrics)
+			}
+		}
+	}
+	if root.Metrics == (Metrics{}) {
+		for _, g := range root.Children {
+			root.Metrics.Merge(g.Metrics)
+		}
+	}
+
+	root.Share = 1
+	setShares(root)
+	return root
+}
+
+func setShares(n *Node) {
+	sort.SliceStable(n.Children, func(i, j int) bool { return n.Children[i].Spend > n.Children[j].Spend })
+	for _, c := range n.Children {
+		c.Share = ratio(c.Spend, n.Spend)
+		setShares(c)
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CallLog records the requests sent through its Transport, to summarize the
+// API calls behind a command's result.
+type CallLog struct {
+	mu     sync.Mutex
+	calls  []call
+	failed map[string]bool
+}
+
+type call struct {
+	endpoint   string
+	start, end time.Time
+	retry      bool
+}
+
+// CallSummary describes a run of requests.
+type CallSummary struct {
+	// Endpoint is the last request, e.g. "PUT /campaigns/123".
+	Endpoint string
+	Calls    int
+	Duration time.Duration
+	// Retries counts requests that repeat one that failed (a transport
+	// error, a 429, or a 5xx).
+	Retries int
+}
+
+// Transport returns an http.RoundTripper that records every request before
+// passing it to base.
+func (l *CallLog) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return callTransport{log: l, base: base}
+}
+
+// Len returns the number of requests recorded, to mark where a run starts.
+func (l *CallLog) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.calls)
+}
+
+// Summary describes the requests recorded since mark.
+func (l *CallLog) Summary(mark int) CallSummary {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var s CallSummary
+	if mark < 0 || mark >= len(l.calls) {
+		return s
+	}
+	first, last := l.calls[mark].start, l.calls[mark].end
+	for _, c := range l.calls[mark:] {
+		s.Calls++
+		s.Endpoint = c.endpoint
+		if c.retry {
+			s.Retries++
+		}
+		if c.start.Before(first) {
+			first = c.start
+		}
+		if c.end.After(last) {
+			last = c.end
+		}
+	}
+	s.Duration = last.Sub(first)
+	return s
+}
+
+// Reset forgets every request recorded.
+func (l *CallLog) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls, l.failed = nil, nil
+}
+
+type callTransport struct {
+	log  *CallLog
+	base http.RoundTripper
+}
+
+func (t callTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := requestKey(req)
+	l := t.log
+	l.mu.Lock()
+	retry := l.failed[key]
+	delete(l.failed, key)
+	l.mu.Unlock()
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	failed := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, call{endpoint: req.Method + " " + endpointPath(req.URL.Path), start: start, end: time.Now(), retry: retry})
+	if failed {
+		if l.failed == nil {
+			l.failed = map[string]bool{}
+		}
+		l.failed[key] = true
+	}
+	return resp, err
+}
+
+// requestKey identifies a request by method, URL, and body, so that a retry
+// can be told from the next page of a search.
+func requestKey(req *http.Request) string {
+	h := sha256.New()
+	io.WriteString(h, req.Method+" "+req.URL.String()+"\n")
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			io.Copy(h, body)
+			body.Close()
+		}
+	}
+	return string(h.Sum(nil))
+}
+
+// endpointPath drops the /api/<version> prefix from a request path.
+func endpointPath(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return path
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return rest[i:]
+	}
+	return path
+}
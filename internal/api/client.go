@@ -2,24 +2,32 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/trebuhs/asa-cli/internal/drift"
 	"github.com/trebuhs/asa-cli/internal/models"
 )
 
-const (
-	BaseURL        = "https://api.searchads.apple.com/api/v5"
-	defaultTimeout = 30 * time.Second
-)
+const defaultTimeout = 30 * time.Second
 
 type Client struct {
-	HTTP    *http.Client
+	HTTP *http.Client
+	// BaseURL is the API root. A {version} in it is replaced with Version.
 	BaseURL string
+	// Version is the API version, e.g. v5. Endpoints a version doesn't have
+	// fail with a VersionError before any request is sent.
+	Version string
 	Verbose bool
+	// Drift, when enabled, records response fields the models don't match.
+	Drift *drift.Recorder
+
+	ctx context.Context
 }
 
 func NewClient(httpClient *http.Client) *Client {
@@ -28,8 +36,32 @@ func NewClient(httpClient *http.Client) *Client {
 	}
 	return &Client{
 		HTTP:    httpClient,
-		BaseURL: BaseURL,
+		BaseURL: BaseURLTemplate,
+		Version: DefaultVersion,
+	}
+}
+
+// url expands the base URL for the client's version and appends path.
+func (c *Client) url(path string) string {
+	version := c.Version
+	if version == "" {
+		version = DefaultVersion
 	}
+	return strings.ReplaceAll(c.BaseURL, "{version}", version) + path
+}
+
+// WithContext returns a shallow copy of the client whose requests use ctx.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
 }
 
 func (c *Client) Get(path string, result interface{}) (*models.PageDetail, error) {
@@ -50,7 +82,10 @@ func (c *Client) Delete(path string) error {
 }
 
 func (c *Client) do(method, path string, body interface{}, result interface{}) (*models.PageDetail, error) {
-	url := c.BaseURL + path
+	if err := checkVersion(c.Version, path); err != nil {
+		return nil, err
+	}
+	url := c.url(path)
 
 	var bodyReader io.Reader
 	if body != nil {
@@ -64,19 +99,22 @@ func (c *Client) do(method, path string, body interface{}, result interface{}) (
 		}
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(c.context(), method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	requestID := NewRequestID()
+	req.Header.Set(RequestIDHeader, requestID)
 
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed (request id %s): %w", requestID, err)
 	}
 	defer resp.Body.Close()
+	_, appleID := RequestIDs(resp)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -84,6 +122,10 @@ func (c *Client) do(method, path string, body interface{}, result interface{}) (
 	}
 
 	if c.Verbose {
+		fmt.Printf("< Request ID: %s\n", requestID)
+		if appleID != "" {
+			fmt.Printf("< Apple request ID: %s\n", appleID)
+		}
 		fmt.Printf("< Body: %s\n", truncate(string(respBody), 2000))
 	}
 
@@ -93,7 +135,12 @@ func (c *Client) do(method, path string, body interface{}, result interface{}) (
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, parseError(resp.StatusCode, respBody)
+		apiErr := parseError(resp.StatusCode, respBody, result)
+		apiErr.RequestID, apiErr.AppleRequestID = requestID, appleID
+		if resp.StatusCode == http.StatusTooManyRequests {
+			apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+		return nil, apiErr
 	}
 
 	var apiResp models.APIResponse
@@ -103,7 +150,8 @@ func (c *Client) do(method, path string, body interface{}, result interface{}) (
 
 	if apiResp.Error != nil && len(apiResp.Error.Errors) > 0 {
 		e := apiResp.Error.Errors[0]
-		return nil, fmt.Errorf("API error [%s]: %s", e.MessageCode, e.Message)
+		decodePartial(apiResp.Data, result)
+		return nil, &Error{StatusCode: resp.StatusCode, MessageCode: e.MessageCode, Message: e.Message, Field: e.Field, Errors: apiResp.Error.Errors, RequestID: requestID, AppleRequestID: appleID}
 	}
 
 	if result != nil && apiResp.Data != nil {
@@ -111,17 +159,33 @@ func (c *Client) do(method, path string, body interface{}, result interface{}) (
 			return nil, fmt.Errorf("parsing response data: %w", err)
 		}
 	}
+	c.Drift.Check(method, path, "", respBody, &apiResp)
+	if result != nil {
+		c.Drift.Check(method, path, "data", apiResp.Data, result)
+	}
 
 	return apiResp.Pagination, nil
 }
 
-func parseError(statusCode int, body []byte) error {
+// parseError builds the error for a non-2xx response, decoding any items the
+// response still carries into result.
+func parseError(statusCode int, body []byte, result interface{}) *Error {
 	var apiResp models.APIResponse
 	if err := json.Unmarshal(body, &apiResp); err == nil && apiResp.Error != nil && len(apiResp.Error.Errors) > 0 {
 		e := apiResp.Error.Errors[0]
-		return fmt.Errorf("API error (HTTP %d) [%s]: %s", statusCode, e.MessageCode, e.Message)
+		decodePartial(apiResp.Data, result)
+		return &Error{StatusCode: statusCode, MessageCode: e.MessageCode, Message: e.Message, Field: e.Field, Errors: apiResp.Error.Errors}
+	}
+	return &Error{StatusCode: statusCode, Body: string(body)}
+}
+
+// decodePartial decodes the data of an error response into result. Bulk
+// endpoints return the items they accepted next to the errors for the rest;
+// data that doesn't decode is ignored.
+func decodePartial(data json.RawMessage, result interface{}) {
+	if result != nil && len(data) > 0 {
+		_ = json.Unmarshal(data, result)
 	}
-	return fmt.Errorf("API error (HTTP %d): %s", statusCode, truncate(string(body), 500))
 }
 
 func truncate(s string, max int) string {
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// Error is returned for non-2xx responses and for error payloads in API responses.
+type Error struct {
+	StatusCode  int
+	MessageCode string
+	Message     string
+	Field       string
+	Body        string
+	// Errors lists every error in the response. Bulk endpoints report one per
+	// rejected item, naming it in Field (e.g. "keywords[2].text").
+	Errors []models.APIError
+	// RequestID is the X-Request-Id sent with the request and AppleRequestID
+	// the identifier Apple returned, for correlating with support tickets.
+	RequestID      string
+	AppleRequestID string
+	// RetryAfter is how long a rate-limited (429) response asked clients to
+	// wait, or 0 if it didn't say.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	var msg string
+	if e.MessageCode != "" {
+		msg = fmt.Sprintf("API error (HTTP %d) [%s]: %s", e.StatusCode, e.MessageCode, e.Message)
+	} else {
+		msg = fmt.Sprintf("API error (HTTP %d): %s", e.StatusCode, truncate(e.Body, 500))
+	}
+	return msg + e.requestIDs()
+}
+
+// requestIDs formats the request identifiers as a suffix, e.g.
+// " (request id 1f2e…, Apple request id ABC…)".
+func (e *Error) requestIDs() string {
+	switch {
+	case e.RequestID != "" && e.AppleRequestID != "":
+		return fmt.Sprintf(" (request id %s, Apple request id %s)", e.RequestID, e.AppleRequestID)
+	case e.RequestID != "":
+		return fmt.Sprintf(" (request id %s)", e.RequestID)
+	case e.AppleRequestID != "":
+		return fmt.Sprintf(" (Apple request id %s)", e.AppleRequestID)
+	}
+	return ""
+}
+
+// StatusOf returns the HTTP status of an API error, or 0 if err is not one.
+func StatusOf(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an API 404.
+func IsNotFound(err error) bool {
+	return StatusOf(err) == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is an API 401 or 403.
+func IsUnauthorized(err error) bool {
+	s := StatusOf(err)
+	return s == http.StatusUnauthorized || s == http.StatusForbidden
+}
+
+// RetryAfterOf returns the wait a rate-limited API error asked for, or 0.
+func RetryAfterOf(err error) time.Duration {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// IsRateLimited reports whether err is an API 429.
+func IsRateLimited(err error) bool {
+	return StatusOf(err) == http.StatusTooManyRequests
+}
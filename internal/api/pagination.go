@@ -2,9 +2,11 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/progress"
 )
 
 const (
@@ -17,6 +19,9 @@ func PaginatedFetcher[T any](c *Client, path string, selector models.Selector) (
 	var allResults []T
 	offset := selector.Pagination.Offset
 
+	bar := progress.New("Fetching "+strings.TrimPrefix(path, "/"), 0)
+	defer bar.Done()
+
 	for {
 		selector.Pagination.Offset = offset
 		var page []T
@@ -26,6 +31,10 @@ func PaginatedFetcher[T any](c *Client, path string, selector models.Selector) (
 		}
 
 		allResults = append(allResults, page...)
+		if pagination != nil {
+			bar.SetTotal(pagination.TotalResults)
+		}
+		bar.Add(len(page))
 
 		if pagination == nil || len(allResults) >= pagination.TotalResults {
 			break
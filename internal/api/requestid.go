@@ -0,0 +1,37 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader carries the ID generated for every request.
+const RequestIDHeader = "X-Request-Id"
+
+// appleRequestIDHeaders are the response headers Apple may identify a request
+// by, in order of preference.
+var appleRequestIDHeaders = []string{"X-Apple-Request-Uuid", "X-Apple-Jingle-Correlation-Key", "X-Request-Id"}
+
+// NewRequestID returns a random 32-character hex request ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// RequestIDs returns the ID sent with resp's request and the ID Apple gave the
+// response, if any. Apple's ID is omitted when it only echoes ours.
+func RequestIDs(resp *http.Response) (sent, apple string) {
+	if resp.Request != nil {
+		sent = resp.Request.Header.Get(RequestIDHeader)
+	}
+	for _, h := range appleRequestIDHeaders {
+		if v := resp.Header.Get(h); v != "" && v != sent {
+			return sent, v
+		}
+	}
+	return sent, ""
+}
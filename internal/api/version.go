@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// API versions.
+const (
+	V4 = "v4"
+	V5 = "v5"
+
+	DefaultVersion = V5
+)
+
+// BaseURLTemplate is the API root; {version} is replaced with the client's
+// version.
+const BaseURLTemplate = "https://api.searchads.apple.com/api/{version}"
+
+// VersionURL returns the API root for version.
+func VersionURL(version string) string {
+	return strings.ReplaceAll(BaseURLTemplate, "{version}", version)
+}
+
+// ParseVersion normalizes an api_version setting ("v4", "5", ...). An empty
+// one is the default version.
+func ParseVersion(s string) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if v == "" {
+		return DefaultVersion, nil
+	}
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if v != V4 && v != V5 {
+		return "", fmt.Errorf("unsupported API version %q (expected v4 or v5)", s)
+	}
+	return v, nil
+}
+
+// v5Only are the endpoints Apple added in v5, as path segment patterns ("*"
+// matches any one segment), with the feature each serves. v4 orgs manage
+// creatives through creative sets instead of ads.
+var v5Only = []struct {
+	pattern string
+	feature string
+}{
+	{"/campaigns/*/adgroups/*/ads", "ads"},
+	{"/ads", "ads"},
+	{"/reports/campaigns/*/ads", "ad reports"},
+}
+
+// VersionError is returned, without a request being sent, for an endpoint the
+// client's API version doesn't have.
+type VersionError struct {
+	Feature string
+	Need    string
+	Have    string
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("%s need API %s; this profile uses %s (set api_version in config.yaml)", e.Feature, e.Need, e.Have)
+}
+
+// IsVersionError reports whether err is a VersionError.
+func IsVersionError(err error) bool {
+	var v *VersionError
+	return errors.As(err, &v)
+}
+
+// checkVersion returns a VersionError if path needs a later version than
+// version.
+func checkVersion(version, path string) error {
+	if version != V4 {
+		return nil
+	}
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for _, e := range v5Only {
+		if matchSegments(strings.Split(strings.Trim(e.pattern, "/"), "/"), segs) {
+			return &VersionError{Feature: e.feature, Need: V5, Have: version}
+		}
+	}
+	return nil
+}
+
+// matchSegments reports whether path starts with pattern's segments.
+func matchSegments(pattern, path []string) bool {
+	if len(path) < len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != path[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+// Package approvals stores proposed changes for a two-person rule: one
+// operator records a mutating command instead of running it, and another
+// reviews and applies it later.
+package approvals
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Proposal statuses.
+const (
+	StatusPending  = "pending"
+	StatusApplied  = "applied"
+	StatusFailed   = "failed"
+	StatusRejected = "rejected"
+)
+
+// Proposal is a recorded command awaiting review.
+type Proposal struct {
+	ID         string     `json:"id"`
+	Command    string     `json:"command"`
+	Args       []string   `json:"args"`
+	Profile    string     `json:"profile,omitempty"`
+	ProposedBy string     `json:"proposedBy"`
+	ProposedAt time.Time  `json:"proposedAt"`
+	Status     string     `json:"status"`
+	ReviewedBy string     `json:"reviewedBy,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+	Note       string     `json:"note,omitempty"`
+}
+
+// Dir is where proposals are kept. An empty dir means the default
+// approvals directory under the config directory.
+type Dir string
+
+// Open returns the proposal directory, dir or the default when empty.
+func Open(dir string) Dir {
+	if dir == "" {
+		return Dir(filepath.Join(config.ConfigDir(), "approvals"))
+	}
+	return Dir(dir)
+}
+
+// New records args as a pending proposal and returns it.
+func (d Dir) New(args []string, profile string) (*Proposal, error) {
+	p := &Proposal{
+		ID:         newID(),
+		Command:    strings.Join(args, " "),
+		Args:       args,
+		Profile:    profile,
+		ProposedBy: Operator(),
+		ProposedAt: time.Now().UTC(),
+		Status:     StatusPending,
+	}
+	if err := d.Save(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Get reads a proposal by ID.
+func (d Dir) Get(id string) (*Proposal, error) {
+	if strings.ContainsAny(id, `/\`) || id == "" {
+		return nil, fmt.Errorf("invalid proposal ID %q", id)
+	}
+	data, err := os.ReadFile(d.path(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("proposal %s not found in %s", id, string(d))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading proposal: %w", err)
+	}
+	var p Proposal
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing proposal %s: %w", id, err)
+	}
+	return &p, nil
+}
+
+// List returns every proposal, oldest first.
+func (d Dir) List() ([]Proposal, error) {
+	entries, err := os.ReadDir(string(d))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading approvals: %w", err)
+	}
+	var out []Proposal
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		p, err := d.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ProposedAt.Before(out[j].ProposedAt) })
+	return out, nil
+}
+
+// Save writes a proposal.
+func (d Dir) Save(p *Proposal) error {
+	return config.SaveJSON(d.path(p.ID), "proposal", p)
+}
+
+// Open reports whether the proposal can still be applied or rejected.
+func (p *Proposal) Open() bool {
+	return p.Status == StatusPending || p.Status == StatusFailed
+}
+
+// Review marks a proposal as decided by the current operator.
+func (p *Proposal) Review(status, note string) {
+	now := time.Now().UTC()
+	p.Status = status
+	p.ReviewedBy = Operator()
+	p.ReviewedAt = &now
+	p.Note = note
+}
+
+func (d Dir) path(id string) string {
+	return filepath.Join(string(d), id+".json")
+}
+
+// Operator names the person running the CLI: $ASA_OPERATOR, or else the
+// login name.
+func Operator() string {
+	if name := strings.TrimSpace(os.Getenv("ASA_OPERATOR")); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// newID returns a sortable, collision-resistant ID like 20240102-150405-a1b2.
+func newID() string {
+	b := make([]byte, 2)
+	rand.Read(b)
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
@@ -1,12 +1,14 @@
 package auth
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +17,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -26,25 +29,100 @@ const (
 	tokenAud    = "https://appleid.apple.com"
 	tokenScope  = "searchadsorg"
 	jwtLifetime = 180 * 24 * time.Hour // 180 days max
+
+	// tokenTimeout bounds a token exchange that has no deadline of its own.
+	tokenTimeout = 30 * time.Second
 )
 
+// tokenClient sends token exchanges.
+var tokenClient = &http.Client{Timeout: tokenTimeout}
+
 type TokenCache struct {
 	AccessToken string    `json:"access_token"`
 	TokenType   string    `json:"token_type"`
 	ExpiresAt   time.Time `json:"expires_at"`
 }
 
+// TokenError is returned when Apple rejects the OAuth token exchange.
+type TokenError struct {
+	StatusCode int
+	Code       string // OAuth error code, e.g. invalid_client
+	// ClockSkew is how far the local clock was ahead of Apple's (negative
+	// when behind), from the response's Date header.
+	ClockSkew time.Duration
+}
+
+func (e *TokenError) Error() string {
+	msg := fmt.Sprintf("token exchange failed (HTTP %d)", e.StatusCode)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if hint := e.Hint(); hint != "" {
+		msg += "\n" + hint
+	}
+	return msg
+}
+
+// Hint explains the likely cause of the error code and how to fix it.
+func (e *TokenError) Hint() string {
+	if e.Code == "invalid_client" || e.Code == "invalid_grant" {
+		if hint := SkewHint(e.ClockSkew); hint != "" {
+			return hint
+		}
+	}
+	switch e.Code {
+	case "invalid_client":
+		return "key_id does not match the public key uploaded in Search Ads, or client_id/team_id is wrong.\n" +
+			"If the key was rotated in the Search Ads UI, point private_key_path at the new private key and update key_id,\n" +
+			"or add the new key alongside the old one: asa-cli auth keys add --key-id <id> --private-key-path <file>\n" +
+			"Compare the local key with the uploaded one: asa-cli auth inspect-key"
+	case "invalid_grant":
+		return "The client secret was rejected; check that the system clock is correct and team_id matches the API user."
+	case "unauthorized_client":
+		return "The API user is not allowed to use the client credentials grant; check its role in the Search Ads UI."
+	case "invalid_scope":
+		return "The API user has no access to the Search Ads API scope; check its role in the Search Ads UI."
+	case "invalid_request":
+		return "The token request was malformed; check that client_id, team_id, and key_id have no stray whitespace."
+	}
+	return ""
+}
+
+// expiryBuffer is how long before expiry a token stops being used.
+const expiryBuffer = 5 * time.Minute
+
 type TokenProvider struct {
 	cfg   *config.Config
 	mu    sync.Mutex
 	token *TokenCache
+
+	// PrewarmWindow, if set, refreshes the token in the background once it is
+	// this close to expiry, instead of blocking the first request after it
+	// expires. It should exceed the 5 minute expiry buffer.
+	PrewarmWindow time.Duration
+	// refreshing is closed when the background refresh in flight ends.
+	refreshing chan struct{}
+
+	// OnFailover, if set, is called when a key is rejected with
+	// invalid_client and the next configured key is tried.
+	OnFailover func(rejected, next string)
+	// active is the index in cfg.KeyPairs() of the key that last worked,
+	// tried first on the next exchange.
+	active atomic.Int32
 }
 
 func NewTokenProvider(cfg *config.Config) *TokenProvider {
 	return &TokenProvider{cfg: cfg}
 }
 
+// GetToken returns a valid access token, exchanging a new one if needed.
 func (tp *TokenProvider) GetToken() (string, error) {
+	return tp.GetTokenContext(context.Background())
+}
+
+// GetTokenContext is GetToken with a context that bounds the token exchange,
+// such as the context of the API request that needs the token.
+func (tp *TokenProvider) GetTokenContext(ctx context.Context) (string, error) {
 	tp.mu.Lock()
 	defer tp.mu.Unlock()
 
@@ -53,13 +131,27 @@ func (tp *TokenProvider) GetToken() (string, error) {
 		tp.token = tp.loadCachedToken()
 	}
 
+	// Wait for a background refresh rather than start a second exchange
+	if !tp.usable() && tp.refreshing != nil {
+		wait := tp.refreshing
+		tp.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			tp.mu.Lock()
+			return "", ctx.Err()
+		}
+		tp.mu.Lock()
+	}
+
 	// Return cached token if still valid (with 5 min buffer)
-	if tp.token != nil && time.Now().Add(5*time.Minute).Before(tp.token.ExpiresAt) {
+	if tp.usable() {
+		tp.prewarmLocked()
 		return tp.token.AccessToken, nil
 	}
 
 	// Generate new token
-	token, err := tp.exchangeToken()
+	token, err := tp.exchangeToken(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -69,8 +161,116 @@ func (tp *TokenProvider) GetToken() (string, error) {
 	return token.AccessToken, nil
 }
 
-func (tp *TokenProvider) exchangeToken() (*TokenCache, error) {
-	clientSecret, err := tp.generateClientSecret()
+// Refresh exchanges a new token now, whatever the cached one's expiry.
+func (tp *TokenProvider) Refresh() (*TokenCache, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	token, err := tp.exchangeToken(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	tp.token = token
+	tp.saveCachedToken(token)
+	cached := *token
+	return &cached, nil
+}
+
+// Prewarm starts a background refresh if the cached token is missing,
+// expired, or within PrewarmWindow of expiry, so it is ready by the first
+// request.
+func (tp *TokenProvider) Prewarm() {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	if tp.token == nil {
+		tp.token = tp.loadCachedToken()
+	}
+	if !tp.usable() {
+		tp.startRefresh()
+		return
+	}
+	tp.prewarmLocked()
+}
+
+// usable reports whether the token is valid beyond the expiry buffer.
+func (tp *TokenProvider) usable() bool {
+	return tp.token != nil && time.Now().Add(expiryBuffer).Before(tp.token.ExpiresAt)
+}
+
+// prewarmLocked starts a background refresh when the token is within
+// PrewarmWindow of expiry. tp.mu must be held.
+func (tp *TokenProvider) prewarmLocked() {
+	if tp.PrewarmWindow > 0 && time.Now().Add(tp.PrewarmWindow).After(tp.token.ExpiresAt) {
+		tp.startRefresh()
+	}
+}
+
+// startRefresh exchanges a token in the background unless one is already
+// being exchanged. A failed refresh is dropped; the next GetToken after
+// expiry exchanges again and reports the error. tp.mu must be held.
+func (tp *TokenProvider) startRefresh() {
+	if tp.refreshing != nil {
+		return
+	}
+	done := make(chan struct{})
+	tp.refreshing = done
+	go func() {
+		defer close(done)
+		token, err := tp.exchangeToken(context.Background())
+		tp.mu.Lock()
+		defer tp.mu.Unlock()
+		if err == nil {
+			tp.token = token
+			tp.saveCachedToken(token)
+		}
+		tp.refreshing = nil
+	}()
+}
+
+// ActiveKeyID returns the ID of the key that last exchanged a token, or the
+// primary key's before any exchange.
+func (tp *TokenProvider) ActiveKeyID() string {
+	pairs := tp.cfg.KeyPairs()
+	if len(pairs) == 0 {
+		return ""
+	}
+	return pairs[int(tp.active.Load())%len(pairs)].KeyID
+}
+
+// exchangeToken exchanges a token with the key that last worked, failing
+// over to the profile's other keys in order while Apple answers
+// invalid_client.
+func (tp *TokenProvider) exchangeToken(ctx context.Context) (*TokenCache, error) {
+	pairs := tp.cfg.KeyPairs()
+	if len(pairs) == 0 {
+		pairs = []config.KeyPair{{}}
+	}
+	first := int(tp.active.Load()) % len(pairs)
+	var lastErr error
+	for n := 0; n < len(pairs); n++ {
+		i := (first + n) % len(pairs)
+		token, err := tp.exchangeWith(ctx, pairs[i], i == 0)
+		if err == nil {
+			tp.active.Store(int32(i))
+			return token, nil
+		}
+		var tokenErr *TokenError
+		if !errors.As(err, &tokenErr) || tokenErr.Code != "invalid_client" || len(pairs) == 1 {
+			return nil, err
+		}
+		lastErr = err
+		if n+1 < len(pairs) && tp.OnFailover != nil {
+			tp.OnFailover(pairs[i].KeyID, pairs[(i+1)%len(pairs)].KeyID)
+		}
+	}
+	return nil, lastErr
+}
+
+// exchangeWith exchanges a token signed with one key pair. The primary key
+// uses key material from a credential source when there is one.
+func (tp *TokenProvider) exchangeWith(ctx context.Context, pair config.KeyPair, primary bool) (*TokenCache, error) {
+	clientSecret, err := tp.generateClientSecret(pair, primary)
 	if err != nil {
 		return nil, fmt.Errorf("generating client secret: %w", err)
 	}
@@ -82,11 +282,19 @@ func (tp *TokenProvider) exchangeToken() (*TokenCache, error) {
 		"scope":         {tokenScope},
 	}
 
-	resp, err := http.PostForm(tokenURL, data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("token exchange request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	sent := time.Now()
+	resp, err := tokenClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("token exchange request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	received := time.Now()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -98,10 +306,10 @@ func (tp *TokenProvider) exchangeToken() (*TokenCache, error) {
 		var errResp struct {
 			Error string `json:"error"`
 		}
-		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("token exchange failed (HTTP %d): %s", resp.StatusCode, errResp.Error)
-		}
-		return nil, fmt.Errorf("token exchange failed (HTTP %d)", resp.StatusCode)
+		_ = json.Unmarshal(body, &errResp)
+		tokenErr := &TokenError{StatusCode: resp.StatusCode, Code: errResp.Error}
+		tokenErr.ClockSkew, _ = skewFromResponse(resp, sent, received)
+		return nil, tokenErr
 	}
 
 	var tokenResp struct {
@@ -120,8 +328,14 @@ func (tp *TokenProvider) exchangeToken() (*TokenCache, error) {
 	}, nil
 }
 
-func (tp *TokenProvider) generateClientSecret() (string, error) {
-	key, err := loadPrivateKey(tp.cfg.PrivateKeyPath)
+func (tp *TokenProvider) generateClientSecret(pair config.KeyPair, primary bool) (string, error) {
+	var key *ecdsa.PrivateKey
+	var err error
+	if primary && tp.cfg.PrivateKey != "" {
+		key, err = parsePrivateKey([]byte(tp.cfg.PrivateKey))
+	} else {
+		key, err = loadPrivateKey(pair.PrivateKeyPath)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -136,33 +350,45 @@ func (tp *TokenProvider) generateClientSecret() (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
-	token.Header["kid"] = tp.cfg.KeyID
+	token.Header["kid"] = pair.KeyID
 
 	return token.SignedString(key)
 }
 
+// LoadPrivateKey returns the configured private key, from a credential
+// source or from private_key_path.
+func LoadPrivateKey(cfg *config.Config) (*ecdsa.PrivateKey, error) {
+	if cfg.PrivateKey != "" {
+		return parsePrivateKey([]byte(cfg.PrivateKey))
+	}
+	return loadPrivateKey(cfg.PrivateKeyPath)
+}
+
 func loadPrivateKey(path string) (*ecdsa.PrivateKey, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading private key file: %w", err)
 	}
+	return parsePrivateKey(data)
+}
 
+func parsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
 	block, _ := pem.Decode(data)
 	if block == nil {
-		return nil, fmt.Errorf("no PEM block found in private key file")
+		return nil, fmt.Errorf("no PEM block found in private key")
 	}
 
 	// Try PKCS#8 first
 	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
 		if ecKey, ok := key.(*ecdsa.PrivateKey); ok {
-			return ecKey, nil
+			return checkCurve(ecKey)
 		}
 		return nil, fmt.Errorf("PKCS#8 key is not ECDSA")
 	}
 
 	// Try SEC1/EC format
 	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
-		return key, nil
+		return checkCurve(key)
 	}
 
 	return nil, fmt.Errorf("unable to parse private key (tried PKCS#8 and SEC1 formats)")
@@ -173,24 +399,15 @@ func (tp *TokenProvider) cachePath() string {
 }
 
 func (tp *TokenProvider) loadCachedToken() *TokenCache {
-	data, err := os.ReadFile(tp.cachePath())
-	if err != nil {
-		return nil
-	}
 	var cache TokenCache
-	if err := json.Unmarshal(data, &cache); err != nil {
+	if err := config.LoadJSON(tp.cachePath(), "token cache", &cache); err != nil || cache.AccessToken == "" {
 		return nil
 	}
 	return &cache
 }
 
 func (tp *TokenProvider) saveCachedToken(token *TokenCache) {
-	data, err := json.MarshalIndent(token, "", "  ")
-	if err != nil {
-		return
-	}
-	_ = os.MkdirAll(filepath.Dir(tp.cachePath()), 0700)
-	_ = os.WriteFile(tp.cachePath(), data, 0600)
+	_ = config.SaveJSON(tp.cachePath(), "token cache", token)
 }
 
 func (tp *TokenProvider) cacheKey() string {
@@ -204,6 +421,8 @@ func (tp *TokenProvider) cacheKey() string {
 	sb.WriteString(tp.cfg.OrgID)
 	sb.WriteString("|")
 	sb.WriteString(tp.cfg.PrivateKeyPath)
+	sb.WriteString("|")
+	sb.WriteString(tp.cfg.CredentialSource)
 	sum := sha256.Sum256([]byte(sb.String()))
 	return hex.EncodeToString(sum[:])
 }
@@ -219,16 +438,23 @@ func ValidateConfig(cfg *config.Config) error {
 	if cfg.KeyID == "" {
 		missing = append(missing, "key_id")
 	}
-	if cfg.PrivateKeyPath == "" {
+	if cfg.PrivateKeyPath == "" && cfg.PrivateKey == "" {
 		missing = append(missing, "private_key_path")
 	}
 	if len(missing) > 0 {
 		return fmt.Errorf("missing required config: %s\nRun 'asa-cli configure' to set up credentials", strings.Join(missing, ", "))
 	}
 
-	// Validate key file exists
-	if _, err := os.Stat(cfg.PrivateKeyPath); os.IsNotExist(err) {
-		return fmt.Errorf("private key file not found: %s", cfg.PrivateKeyPath)
+	// Validate key file exists, unless the key came from a credential source
+	if cfg.PrivateKey == "" {
+		if _, err := os.Stat(cfg.PrivateKeyPath); os.IsNotExist(err) {
+			return fmt.Errorf("private key file not found: %s", cfg.PrivateKeyPath)
+		}
+	}
+
+	// Catch unusable keys before Apple rejects the token exchange
+	if _, err := LoadPrivateKey(cfg); err != nil {
+		return err
 	}
 
 	return nil
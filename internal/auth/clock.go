@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MaxClockSkew is how far the local clock may drift from Apple's before it is
+// reported: client secrets are stamped with the local time, and one issued
+// in the future or long expired is rejected with invalid_client or
+// invalid_grant.
+const MaxClockSkew = time.Minute
+
+// skewFromResponse returns how far the local clock is ahead of the server
+// that sent resp (negative when behind), from its Date header, taking the
+// local time halfway through the request. ok is false without a usable
+// Date header.
+func skewFromResponse(resp *http.Response, sent, received time.Time) (skew time.Duration, ok bool) {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+	local := sent.Add(received.Sub(sent) / 2)
+	// Date has one second resolution
+	return local.Sub(date.Add(500 * time.Millisecond)).Round(time.Second), true
+}
+
+// ClockSkew asks appleid.apple.com for the time and returns how far the local
+// clock is ahead of it (negative when behind).
+func ClockSkew() (time.Duration, error) {
+	sent := time.Now()
+	resp, err := http.Head(tokenAud)
+	if err != nil {
+		return 0, fmt.Errorf("checking clock: %w", err)
+	}
+	resp.Body.Close()
+	skew, ok := skewFromResponse(resp, sent, time.Now())
+	if !ok {
+		return 0, fmt.Errorf("checking clock: %s sent no Date header", tokenAud)
+	}
+	return skew, nil
+}
+
+// Skewed reports whether skew is beyond MaxClockSkew.
+func Skewed(skew time.Duration) bool {
+	return skew <= -MaxClockSkew || skew >= MaxClockSkew
+}
+
+// DescribeSkew says how far and which way the local clock is off, e.g.
+// "local clock is 5m0s ahead of appleid.apple.com".
+func DescribeSkew(skew time.Duration) string {
+	dir := "ahead of"
+	if skew < 0 {
+		dir, skew = "behind", -skew
+	}
+	return fmt.Sprintf("local clock is %s %s appleid.apple.com", skew, dir)
+}
+
+// SkewHint explains a clock skew beyond MaxClockSkew, or returns "".
+func SkewHint(skew time.Duration) string {
+	if !Skewed(skew) {
+		return ""
+	}
+	return "The " + DescribeSkew(skew) + "; client secrets are stamped with the local time.\n" +
+		"Sync the clock (e.g. enable NTP, or restart the time service on a resumed VM) and retry."
+}
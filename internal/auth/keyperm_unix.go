@@ -0,0 +1,21 @@
+//go:build !windows
+
+package auth
+
+import (
+	"fmt"
+	"os"
+)
+
+// KeyPermissionWarning describes a private key file that other users can
+// read, or returns "" when its permissions are fine.
+func KeyPermissionWarning(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if mode := info.Mode().Perm(); mode&0o077 != 0 {
+		return fmt.Sprintf("private key %s is accessible by other users (mode %04o); run: chmod 600 %s", path, mode, path)
+	}
+	return ""
+}
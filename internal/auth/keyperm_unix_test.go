@@ -0,0 +1,46 @@
+//go:build !windows
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyPermissionWarning(t *testing.T) {
+	tests := []struct {
+		mode os.FileMode
+		warn bool
+	}{
+		{0o600, false},
+		{0o400, false},
+		{0o640, true},
+		{0o604, true},
+		{0o644, true},
+		{0o777, true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "key.pem")
+		if err := os.WriteFile(path, []byte("key"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, tt.mode); err != nil {
+			t.Fatal(err)
+		}
+		got := KeyPermissionWarning(path)
+		if (got != "") != tt.warn {
+			t.Errorf("mode %04o: KeyPermissionWarning = %q, want warning %v", tt.mode, got, tt.warn)
+		}
+		if tt.warn && !strings.Contains(got, "chmod 600 "+path) {
+			t.Errorf("mode %04o: warning %q doesn't say how to fix it", tt.mode, got)
+		}
+	}
+}
+
+func TestKeyPermissionWarningMissingFile(t *testing.T) {
+	if got := KeyPermissionWarning(filepath.Join(t.TempDir(), "missing.pem")); got != "" {
+		t.Errorf("KeyPermissionWarning(missing) = %q, want \"\"", got)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package auth
+
+// KeyPermissionWarning always returns "" on Windows: file access there is
+// governed by NTFS ACLs, which Unix permission bits do not reflect.
+func KeyPermissionWarning(path string) string {
+	return ""
+}
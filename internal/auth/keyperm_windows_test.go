@@ -0,0 +1,21 @@
+//go:build windows
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyPermissionWarning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, []byte("key"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{path, filepath.Join(t.TempDir(), "missing.pem")} {
+		if got := KeyPermissionWarning(p); got != "" {
+			t.Errorf("KeyPermissionWarning(%q) = %q, want \"\" on Windows", p, got)
+		}
+	}
+}
@@ -1,20 +1,39 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
+// ErrReadOnly is returned for requests a read-only transport refuses.
+var ErrReadOnly = errors.New("refused in read-only mode")
+
 // Transport is an http.RoundTripper that injects Authorization and X-AP-Context headers.
 type Transport struct {
 	Base     http.RoundTripper
 	Token    *TokenProvider
 	OrgID    string
 	Verbose  bool
+	// Observe, if set, is called with every response, e.g. to count API calls.
+	Observe func(*http.Response)
+	// Allow, if set, is called before every request; an error aborts it.
+	Allow func() error
+	// ReadOnly refuses every request that could change the account.
+	ReadOnly bool
 }
 
 func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
-	token, err := t.Token.GetToken()
+	if t.ReadOnly && !IsRead(req.Method, req.URL.Path) {
+		return nil, fmt.Errorf("%s %s %w", req.Method, req.URL.Path, ErrReadOnly)
+	}
+	if t.Allow != nil {
+		if err := t.Allow(); err != nil {
+			return nil, err
+		}
+	}
+	token, err := t.Token.GetTokenContext(req.Context())
 	if err != nil {
 		return nil, fmt.Errorf("auth: %w", err)
 	}
@@ -48,6 +67,9 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
+	if t.Observe != nil {
+		t.Observe(resp)
+	}
 
 	if t.Verbose {
 		fmt.Printf("< %s %s\n", resp.Status, resp.Proto)
@@ -55,3 +77,16 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	return resp, nil
 }
+
+// IsRead reports whether a request only reads. Besides GETs, the API takes
+// searches (.../find) and reports (/reports/..., /custom-reports) as POSTs;
+// those don't change campaigns and are allowed.
+func IsRead(method, path string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(path, "/find") || strings.Contains(path, "/reports/") || strings.HasSuffix(path, "/custom-reports")
+	}
+	return false
+}
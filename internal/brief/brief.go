@@ -0,0 +1,280 @@
+// Package brief renders a one-page client-facing PDF summary of an
+// organization's performance over a period.
+package brief
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+)
+
+// Campaign is one row of the brief's top campaigns table.
+type Campaign struct {
+	Name      string
+	Current   aggregate.Metrics
+	PrevSpend float64
+}
+
+// Day is one point of the daily spend chart.
+type Day struct {
+	Date  string
+	Spend float64
+}
+
+// Brief is the data shown in a brief.
+type Brief struct {
+	OrgName  string
+	Currency string
+	Period   string
+	Previous string
+
+	Totals     aggregate.Metrics
+	PrevTotals aggregate.Metrics
+	Campaigns  []Campaign
+	Daily      []Day
+}
+
+const (
+	pageWidth = 210.0
+	margin    = 15.0
+	inner     = pageWidth - 2*margin
+)
+
+// Write renders the brief as an A4 PDF. The built-in fonts cover Latin-1, so
+// characters outside it (e.g. CJK campaign names) are replaced.
+func Write(w io.Writer, b *Brief) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(inner, 9, tr(b.OrgName), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(inner, 6, tr(fmt.Sprintf("Apple Search Ads performance, %s (vs. %s)", b.Period, b.Previous)), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(4)
+
+	kpis(pdf, b)
+	pdf.Ln(6)
+
+	heading(pdf, "Daily spend")
+	dailyChart(pdf, b.Daily, b.Currency)
+	pdf.Ln(6)
+
+	heading(pdf, "Top campaigns")
+	campaignTable(pdf, tr, b)
+	pdf.Ln(6)
+
+	heading(pdf, "Spend by campaign")
+	campaignBars(pdf, tr, b.Campaigns)
+
+	return pdf.Output(w)
+}
+
+func heading(pdf *fpdf.Fpdf, text string) {
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(inner, 7, text, "", 1, "L", false, 0, "")
+	pdf.Ln(1)
+}
+
+// kpis draws a row of boxes with each headline metric and its change.
+func kpis(pdf *fpdf.Fpdf, b *Brief) {
+	cur, prev := b.Totals, b.PrevTotals
+	boxes := []struct {
+		label, value string
+		now, before  float64
+		lowerIsGood  bool
+	}{
+		{"Spend", fmt.Sprintf("%.2f %s", cur.Spend, b.Currency), cur.Spend, prev.Spend, false},
+		{"Installs", fmt.Sprintf("%d", cur.Installs), float64(cur.Installs), float64(prev.Installs), false},
+		{"CPI", fmt.Sprintf("%.2f %s", cur.CPI(), b.Currency), cur.CPI(), prev.CPI(), true},
+		{"Tap-through rate", fmt.Sprintf("%.2f%%", cur.TTR()*100), cur.TTR(), prev.TTR(), false},
+	}
+
+	gap := 4.0
+	width := (inner - gap*float64(len(boxes)-1)) / float64(len(boxes))
+	x, y := pdf.GetX(), pdf.GetY()
+	for i, box := range boxes {
+		bx := x + float64(i)*(width+gap)
+		pdf.SetFillColor(245, 245, 245)
+		pdf.Rect(bx, y, width, 22, "F")
+
+		pdf.SetXY(bx+3, y+2)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(width-6, 5, box.label, "", 0, "L", false, 0, "")
+
+		pdf.SetXY(bx+3, y+7)
+		pdf.SetFont("Helvetica", "B", 13)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(width-6, 7, box.value, "", 0, "L", false, 0, "")
+
+		pdf.SetXY(bx+3, y+15)
+		pdf.SetFont("Helvetica", "", 9)
+		delta, good := change(box.now, box.before, box.lowerIsGood)
+		switch {
+		case good > 0:
+			pdf.SetTextColor(30, 130, 60)
+		case good < 0:
+			pdf.SetTextColor(190, 40, 40)
+		}
+		pdf.CellFormat(width-6, 5, delta+" vs. previous", "", 0, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.SetXY(x, y+22)
+}
+
+// change formats the relative change from before to now, and reports whether
+// it is an improvement (1), a decline (-1), or neither (0).
+func change(now, before float64, lowerIsGood bool) (string, int) {
+	if before == 0 {
+		if now == 0 {
+			return "no change", 0
+		}
+		return "new", 0
+	}
+	pct := (now - before) / before * 100
+	good := 0
+	if math.Abs(pct) >= 0.05 {
+		good = 1
+		if (pct < 0) != lowerIsGood {
+			good = -1
+		}
+	}
+	return fmt.Sprintf("%+.1f%%", pct), good
+}
+
+func dailyChart(pdf *fpdf.Fpdf, days []Day, currency string) {
+	const height = 40.0
+	x, y := pdf.GetX(), pdf.GetY()
+	if len(days) == 0 {
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.CellFormat(inner, 6, "No spend in this period.", "", 1, "L", false, 0, "")
+		return
+	}
+
+	max := 0.0
+	for _, d := range days {
+		max = math.Max(max, d.Spend)
+	}
+	axis := 18.0
+	plot := inner - axis
+	slot := plot / float64(len(days))
+
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.SetXY(x, y)
+	pdf.CellFormat(axis-2, 4, fmt.Sprintf("%.0f %s", max, currency), "", 0, "R", false, 0, "")
+	pdf.SetXY(x, y+height-4)
+	pdf.CellFormat(axis-2, 4, "0", "", 0, "R", false, 0, "")
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.Line(x+axis, y+height, x+inner, y+height)
+
+	pdf.SetFillColor(66, 133, 244)
+	for i, d := range days {
+		h := 0.0
+		if max > 0 {
+			h = d.Spend / max * height
+		}
+		bx := x + axis + float64(i)*slot
+		pdf.Rect(bx+slot*0.15, y+height-h, slot*0.7, h, "F")
+	}
+
+	// Label the first and last days, plus a few in between for long ranges.
+	every := len(days)/7 + 1
+	for i, d := range days {
+		if i%every != 0 && i != len(days)-1 {
+			continue
+		}
+		label := d.Date
+		if len(label) == 10 {
+			label = label[5:]
+		}
+		pdf.SetXY(x+axis+float64(i)*slot, y+height+1)
+		pdf.CellFormat(slot, 4, label, "", 0, "C", false, 0, "")
+	}
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetXY(x, y+height+6)
+}
+
+func campaignTable(pdf *fpdf.Fpdf, tr func(string) string, b *Brief) {
+	widths := []float64{70, 28, 22, 22, 38}
+	headers := []string{"Campaign", "Spend", "Installs", "CPI", "Spend vs. previous"}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	for i, h := range headers {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 7, h, "B", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	if len(b.Campaigns) == 0 {
+		pdf.CellFormat(inner, 6, "No campaigns with spend in this period.", "", 1, "L", false, 0, "")
+		return
+	}
+	for _, c := range b.Campaigns {
+		delta, _ := change(c.Current.Spend, c.PrevSpend, false)
+		cells := []string{
+			truncate(pdf, tr(c.Name), widths[0]-2),
+			fmt.Sprintf("%.2f", c.Current.Spend),
+			fmt.Sprintf("%d", c.Current.Installs),
+			fmt.Sprintf("%.2f", c.Current.CPI()),
+			delta,
+		}
+		for i, cell := range cells {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[i], 6, cell, "B", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+func campaignBars(pdf *fpdf.Fpdf, tr func(string) string, campaigns []Campaign) {
+	if len(campaigns) == 0 {
+		return
+	}
+	max := 0.0
+	for _, c := range campaigns {
+		max = math.Max(max, c.Current.Spend)
+	}
+	label := 60.0
+	barWidth := inner - label - 20
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetFillColor(66, 133, 244)
+	for _, c := range campaigns {
+		x, y := pdf.GetX(), pdf.GetY()
+		pdf.CellFormat(label, 5, truncate(pdf, tr(c.Name), label-2), "", 0, "L", false, 0, "")
+		w := 0.0
+		if max > 0 {
+			w = c.Current.Spend / max * barWidth
+		}
+		pdf.Rect(x+label, y+1, w, 3, "F")
+		pdf.SetXY(x+label+w+1, y)
+		pdf.CellFormat(20, 5, fmt.Sprintf("%.2f", c.Current.Spend), "", 0, "L", false, 0, "")
+		pdf.SetXY(x, y+5.5)
+	}
+}
+
+// truncate shortens s with an ellipsis to fit width at the current font.
+func truncate(pdf *fpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	for len(s) > 0 && pdf.GetStringWidth(s+"...") > width {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
@@ -0,0 +1,178 @@
+// Package changeset stores staged edits that are applied together, with the
+// outcome of each edit and the commands that undo it.
+package changeset
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Change set statuses.
+const (
+	StatusOpen       = "open"
+	StatusApplied    = "applied"
+	StatusRolledBack = "rolled-back"
+	StatusFailed     = "failed"
+)
+
+// Item statuses.
+const (
+	ItemPending    = "pending"
+	ItemApplied    = "applied"
+	ItemFailed     = "failed"
+	ItemRolledBack = "rolled-back"
+	ItemSkipped    = "skipped"
+)
+
+// Item is one staged command.
+type Item struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Status  string   `json:"status"`
+	Error   string   `json:"error,omitempty"`
+	// Rollback holds the command lines that undo the item, once applied.
+	Rollback [][]string `json:"rollback,omitempty"`
+}
+
+// Changeset is a named list of staged commands.
+type Changeset struct {
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Status    string     `json:"status"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	Items     []Item     `json:"items"`
+}
+
+// Add stages a command.
+func (c *Changeset) Add(args []string) {
+	c.Items = append(c.Items, Item{Command: strings.Join(args, " "), Args: args, Status: ItemPending})
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Normalize lowercases and trims a change set name.
+func Normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// New returns an empty, open change set.
+func New(name string) (*Changeset, error) {
+	name = Normalize(name)
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid change set name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return &Changeset{Name: name, CreatedAt: time.Now().UTC(), Status: StatusOpen, Items: []Item{}}, nil
+}
+
+// Dir returns the change set directory for a profile.
+func Dir(profile string) string {
+	if profile == "" || profile == "default" {
+		return filepath.Join(config.ConfigDir(), "changesets")
+	}
+	return filepath.Join(config.ConfigDir(), "changesets_"+profile)
+}
+
+// Load reads a change set.
+func Load(profile, name string) (*Changeset, error) {
+	name = Normalize(name)
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid change set name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(Dir(profile), name+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("change set %q not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading change set: %w", err)
+	}
+	var c Changeset
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing change set %s: %w", name, err)
+	}
+	return &c, nil
+}
+
+// Exists reports whether a change set with this name is stored.
+func Exists(profile, name string) bool {
+	_, err := os.Stat(filepath.Join(Dir(profile), Normalize(name)+".json"))
+	return err == nil
+}
+
+// Save writes a change set.
+func Save(profile string, c *Changeset) error {
+	return config.SaveJSON(filepath.Join(Dir(profile), c.Name+".json"), "change set", c)
+}
+
+// Delete removes a stored change set.
+func Delete(profile, name string) error {
+	if err := os.Remove(filepath.Join(Dir(profile), Normalize(name)+".json")); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("change set %q not found", Normalize(name))
+		}
+		return fmt.Errorf("deleting change set: %w", err)
+	}
+	if Current(profile) == Normalize(name) {
+		SetCurrent(profile, "")
+	}
+	return nil
+}
+
+// List returns the stored change sets, newest first.
+func List(profile string) ([]Changeset, error) {
+	entries, err := os.ReadDir(Dir(profile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading change sets: %w", err)
+	}
+	var out []Changeset
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		c, err := Load(profile, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Current returns the name of the change set that add stages into, if any.
+func Current(profile string) string {
+	data, err := os.ReadFile(filepath.Join(Dir(profile), "CURRENT"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetCurrent makes name the change set that add stages into; "" clears it.
+func SetCurrent(profile, name string) error {
+	path := filepath.Join(Dir(profile), "CURRENT")
+	if name == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("clearing current change set: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(Dir(profile), 0700); err != nil {
+		return fmt.Errorf("cannot create change set directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("writing current change set: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Package chaos fails API requests at random, before they are sent, so
+// scripts can be tested against the errors Apple returns when it is under
+// load: rate limits, server errors, and dropped connections.
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+)
+
+// ErrConnectionReset is returned for requests failed as dropped connections.
+var ErrConnectionReset = errors.New("connection reset by peer (injected)")
+
+// Transport is an http.RoundTripper that answers a share of requests with a
+// failure instead of passing them to Base. Failed requests never reach the
+// network.
+type Transport struct {
+	Base http.RoundTripper
+	// FailRate is the share of requests (0 to 1) that fail with a 500, a 503,
+	// or a dropped connection.
+	FailRate float64
+	// RateLimitRate is the share of requests (0 to 1) answered with a 429
+	// asking to retry after a second.
+	RateLimitRate float64
+	// Float returns a number in [0, 1); nil uses math/rand.
+	Float func() float64
+}
+
+// Enabled reports whether t fails any requests.
+func (t *Transport) Enabled() bool {
+	return t.FailRate > 0 || t.RateLimitRate > 0
+}
+
+// Validate checks that both rates are between 0 and 1 and leave room for
+// each other.
+func (t *Transport) Validate() error {
+	if t.FailRate < 0 || t.FailRate > 1 {
+		return fmt.Errorf("--fail-rate must be between 0 and 1, got %g", t.FailRate)
+	}
+	if t.RateLimitRate < 0 || t.RateLimitRate > 1 {
+		return fmt.Errorf("--inject-429 must be between 0 and 1, got %g", t.RateLimitRate)
+	}
+	if t.FailRate+t.RateLimitRate > 1 {
+		return fmt.Errorf("--fail-rate and --inject-429 add up to more than 1")
+	}
+	return nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	float := t.Float
+	if float == nil {
+		float = rand.Float64
+	}
+
+	r := float()
+	switch {
+	case r < t.RateLimitRate:
+		closeBody(req)
+		resp := response(req, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "Too many requests. Please retry after some time.")
+		resp.Header.Set("Retry-After", "1")
+		return resp, nil
+	case r < t.RateLimitRate+t.FailRate:
+		closeBody(req)
+		// Spread failures evenly over the three kinds.
+		switch int((r - t.RateLimitRate) / t.FailRate * 3) {
+		case 0:
+			return response(req, http.StatusInternalServerError, "INTERNAL_ERROR", "An internal error occurred."), nil
+		case 1:
+			return response(req, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "The service is temporarily unavailable."), nil
+		default:
+			return nil, ErrConnectionReset
+		}
+	}
+	return base.RoundTrip(req)
+}
+
+// response builds an error response in the API's envelope. The message is
+// marked as injected so it can't be mistaken for a real failure.
+func response(req *http.Request, status int, code, message string) *http.Response {
+	body := fmt.Sprintf(`{"data":null,"pagination":null,"error":{"errors":[{"messageCode":%q,"message":%q,"field":""}]}}`,
+		code, message+" (injected)")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// closeBody releases the body of a request that is not sent, as a
+// RoundTripper must.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
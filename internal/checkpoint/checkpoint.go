@@ -0,0 +1,140 @@
+// Package checkpoint records progress of bulk operations so an interrupted
+// run can be resumed without repeating completed items.
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Checkpoint is the persisted state of one bulk operation. Completed items
+// are keyed by an idempotency key derived from the item's content, so the
+// same input always maps to the same key across runs. A Checkpoint is safe for
+// concurrent use.
+type Checkpoint struct {
+	Operation string                     `json:"operation"`
+	Key       string                     `json:"key"`
+	CreatedAt time.Time                  `json:"createdAt"`
+	UpdatedAt time.Time                  `json:"updatedAt"`
+	Done      map[string]json.RawMessage `json:"done"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// Key derives a stable short key from the given parts. Use it both for the
+// operation key (its inputs) and for per-item idempotency keys.
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Path returns the checkpoint file for an operation and key.
+func Path(operation, key string) string {
+	return filepath.Join(config.ConfigDir(), "checkpoints", operation+"-"+key+".json")
+}
+
+// Open loads or starts the checkpoint for an operation. If a checkpoint from
+// an earlier, unfinished run exists, resume must be set to continue it;
+// otherwise Open refuses so completed items are not repeated by accident.
+func Open(operation, key string, resume bool) (*Checkpoint, error) {
+	path := Path(operation, key)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		now := time.Now().UTC()
+		return &Checkpoint{Operation: operation, Key: key, CreatedAt: now, UpdatedAt: now, Done: map[string]json.RawMessage{}, path: path}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	if !resume {
+		return nil, fmt.Errorf("an earlier run of this operation did not finish (checkpoint %s); re-run with --resume to continue it, or delete the file to start over", path)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s: %w", path, err)
+	}
+	if cp.Done == nil {
+		cp.Done = map[string]json.RawMessage{}
+	}
+	cp.path = path
+	return &cp, nil
+}
+
+// IsDone reports whether an item has completed.
+func (c *Checkpoint) IsDone(item string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.Done[item]
+	return ok
+}
+
+// Result decodes the stored result of a completed item into v.
+func (c *Checkpoint) Result(item string, v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	raw, ok := c.Done[item]
+	if !ok {
+		return fmt.Errorf("no checkpoint result for %s", item)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// MarkDone records an item as completed with its result and saves the
+// checkpoint immediately.
+func (c *Checkpoint) MarkDone(item string, result interface{}) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding checkpoint result: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Done[item] = raw
+	c.UpdatedAt = time.Now().UTC()
+	return c.save()
+}
+
+// MarkDoneMany records several completed items, keyed by item, with a single
+// save.
+func (c *Checkpoint) MarkDoneMany(results map[string]interface{}) error {
+	if len(results) == 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for item, result := range results {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("encoding checkpoint result: %w", err)
+		}
+		c.Done[item] = raw
+	}
+	c.UpdatedAt = time.Now().UTC()
+	return c.save()
+}
+
+// Remove deletes the checkpoint once the operation has finished.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Path returns the checkpoint's file path.
+func (c *Checkpoint) Path() string { return c.path }
+
+// save writes the checkpoint atomically so a crash mid-write cannot corrupt it.
+func (c *Checkpoint) save() error {
+	return config.SaveJSON(c.path, "checkpoint", c)
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
 type Config struct {
@@ -16,6 +18,62 @@ type Config struct {
 	PrivateKeyPath string  `mapstructure:"private_key_path"`
 	MaxDailyBudget float64 `mapstructure:"max_daily_budget"`
 	MaxBid         float64 `mapstructure:"max_bid"`
+	MinBid         float64 `mapstructure:"min_bid"`
+
+	// Currency is the currency the limits above are written in. Commands
+	// warn when the org bills in another one.
+	Currency string `mapstructure:"currency"`
+
+	// QuotaLimit is the daily API call budget for the profile (0 for none);
+	// EnforceQuota refuses requests once it is used up instead of warning.
+	QuotaLimit   int64 `mapstructure:"quota_limit"`
+	EnforceQuota bool  `mapstructure:"enforce_quota"`
+
+	// RequireApproval makes mutating commands write a proposal for another
+	// operator to apply instead of running (see package approvals).
+	// ApprovalsDir overrides where proposals are kept, e.g. a shared folder.
+	RequireApproval bool   `mapstructure:"require_approval"`
+	ApprovalsDir    string `mapstructure:"approvals_dir"`
+
+	// ReadOnly refuses every API request that could change the account.
+	ReadOnly bool `mapstructure:"read_only"`
+
+	// APIVersion is the API version the org is served by, v5 (default) or v4
+	// for orgs still constrained to older endpoints.
+	APIVersion string `mapstructure:"api_version"`
+
+	// ACLCacheTTL is how long the /acls response is reused (default 24h).
+	ACLCacheTTL time.Duration `mapstructure:"acl_cache_ttl"`
+
+	// GoogleServiceAccountPath is the service account key used by gsheet:// exports.
+	GoogleServiceAccountPath string `mapstructure:"google_service_account_path"`
+
+	// CredentialSource names a secret manager entry holding the credentials,
+	// e.g. vault://secret/asa/prod (see package credentials).
+	CredentialSource string `mapstructure:"credential_source"`
+	// PrivateKey is PEM key material loaded from a credential source. When
+	// set it is used instead of PrivateKeyPath.
+	PrivateKey string `mapstructure:"-"`
+
+	// Keys are further key pairs for the same API user, tried in order when
+	// Apple rejects the primary one with invalid_client, e.g. while a key is
+	// being rotated.
+	Keys []KeyPair `mapstructure:"keys"`
+}
+
+// KeyPair is an API key ID and the private key file it was uploaded for.
+type KeyPair struct {
+	KeyID          string `mapstructure:"key_id" yaml:"key_id" json:"keyId"`
+	PrivateKeyPath string `mapstructure:"private_key_path" yaml:"private_key_path" json:"privateKeyPath"`
+}
+
+// KeyPairs returns the primary key pair, if set, followed by the extra ones.
+func (c *Config) KeyPairs() []KeyPair {
+	var pairs []KeyPair
+	if c.KeyID != "" || c.PrivateKeyPath != "" || c.PrivateKey != "" {
+		pairs = append(pairs, KeyPair{KeyID: c.KeyID, PrivateKeyPath: c.PrivateKeyPath})
+	}
+	return append(pairs, c.Keys...)
 }
 
 var (
@@ -27,6 +85,14 @@ func SetProfile(profile string) {
 	cfgProfile = profile
 }
 
+// SetDir points ConfigDir at dir, e.g. to keep a rehearsal away from the real
+// state, and returns the previous directory ("" for the default).
+func SetDir(dir string) string {
+	prev := configDir
+	configDir = dir
+	return prev
+}
+
 func ConfigDir() string {
 	if configDir != "" {
 		return configDir
@@ -36,11 +102,12 @@ func ConfigDir() string {
 		fmt.Fprintf(os.Stderr, "Error: cannot determine home directory: %v\n", err)
 		os.Exit(3)
 	}
-	configDir = filepath.Join(home, ".asa-cli")
+	configDir = defaultConfigDir(home)
 	return configDir
 }
 
-func Load() (*Config, error) {
+// readConfig reads config.yaml with environment variable bindings.
+func readConfig() (*viper.Viper, error) {
 	dir := ConfigDir()
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return nil, fmt.Errorf("cannot create config directory: %w", err)
@@ -59,6 +126,7 @@ func Load() (*Config, error) {
 	v.BindEnv("key_id")
 	v.BindEnv("org_id")
 	v.BindEnv("private_key_path")
+	v.BindEnv("credential_source")
 
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -66,6 +134,15 @@ func Load() (*Config, error) {
 		}
 	}
 
+	return v, nil
+}
+
+func Load() (*Config, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{}
 
 	if cfgProfile != "" && cfgProfile != "default" {
@@ -98,7 +175,147 @@ func Load() (*Config, error) {
 	if val := os.Getenv("ASA_PRIVATE_KEY_PATH"); val != "" {
 		cfg.PrivateKeyPath = val
 	}
+	if val := os.Getenv("ASA_CREDENTIAL_SOURCE"); val != "" {
+		cfg.CredentialSource = val
+	}
+
+	cfg.PrivateKeyPath = ExpandPath(cfg.PrivateKeyPath)
+	for i := range cfg.Keys {
+		cfg.Keys[i].PrivateKeyPath = ExpandPath(cfg.Keys[i].PrivateKeyPath)
+	}
+	cfg.GoogleServiceAccountPath = ExpandPath(cfg.GoogleServiceAccountPath)
+	cfg.ApprovalsDir = ExpandPath(cfg.ApprovalsDir)
+
+	return cfg, nil
+}
+
+// Defaults returns the `defaults:` section of config.yaml with the active
+// profile's own `defaults:` section layered on top. Nested maps scope values
+// to a command path, e.g. defaults.reports.granularity.
+func Defaults() (map[string]interface{}, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := defaultsSection(v, "defaults")
+	if err != nil {
+		return nil, err
+	}
+	if cfgProfile != "" && cfgProfile != "default" {
+		profileDefaults, err := defaultsSection(v, "profiles."+cfgProfile+".defaults")
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(defaults, profileDefaults)
+	}
+	return defaults, nil
+}
+
+// defaultsSection returns the map at key, rejecting anything but a map.
+func defaultsSection(v *viper.Viper, key string) (map[string]interface{}, error) {
+	raw := v.Get(key)
+	if raw == nil {
+		return map[string]interface{}{}, nil
+	}
+	if _, ok := raw.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("%s in config.yaml must be a map of flag names to values, not %T", key, raw)
+	}
+	return v.GetStringMap(key), nil
+}
+
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if existing, ok := dst[k].(map[string]interface{}); ok {
+				mergeMaps(existing, sub)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// NamingPolicy is the `naming:` section: a name pattern per entity kind and
+// the allowed values of pattern tokens.
+type NamingPolicy struct {
+	Campaign string              `mapstructure:"campaign"`
+	AdGroup  string              `mapstructure:"adgroup"`
+	Tokens   map[string][]string `mapstructure:"tokens"`
+}
+
+// Naming returns the naming policy. A profile's own `naming:` section
+// replaces the top-level one.
+func Naming() (*NamingPolicy, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	key := "naming"
+	if cfgProfile != "" && cfgProfile != "default" && v.IsSet("profiles."+cfgProfile+".naming") {
+		key = "profiles." + cfgProfile + ".naming"
+	}
+	policy := &NamingPolicy{}
+	if err := v.UnmarshalKey(key, policy); err != nil {
+		return nil, fmt.Errorf("error parsing naming policy: %w", err)
+	}
+	return policy, nil
+}
+
+// AuthConfig is the `auth:` section.
+type AuthConfig struct {
+	// Prewarm refreshes the access token in the background once it is within
+	// PrewarmWindow of expiry (default 10m).
+	Prewarm       bool          `mapstructure:"prewarm"`
+	PrewarmWindow time.Duration `mapstructure:"prewarm_window"`
+}
+
+// Auth returns the token settings. A profile's own `auth:` section replaces
+// the top-level one.
+func Auth() (*AuthConfig, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	key := "auth"
+	if cfgProfile != "" && cfgProfile != "default" && v.IsSet("profiles."+cfgProfile+"."+key) {
+		key = "profiles." + cfgProfile + "." + key
+	}
+	cfg := &AuthConfig{}
+	if err := v.UnmarshalKey(key, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing auth settings: %w", err)
+	}
+	return cfg, nil
+}
+
+// FormattingConfig is the `formatting:` section: how tables show numbers,
+// and the pager for long ones. Locale picks a preset; the number settings
+// override it.
+type FormattingConfig struct {
+	Locale             string         `mapstructure:"locale"`
+	ThousandsSeparator *string        `mapstructure:"thousands_separator"`
+	DecimalSeparator   string         `mapstructure:"decimal_separator"`
+	Decimals           map[string]int `mapstructure:"decimals"`
+	Currency           string         `mapstructure:"currency"`
+	// Pager is the command long tables are paged with; "false" turns
+	// paging off.
+	Pager string `mapstructure:"pager"`
+}
 
+// Formatting returns the number formatting settings. A profile's own
+// `formatting:` section replaces the top-level one.
+func Formatting() (*FormattingConfig, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	key := "formatting"
+	if cfgProfile != "" && cfgProfile != "default" && v.IsSet("profiles."+cfgProfile+"."+key) {
+		key = "profiles." + cfgProfile + "." + key
+	}
+	cfg := &FormattingConfig{}
+	if err := v.UnmarshalKey(key, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing formatting: %w", err)
+	}
 	return cfg, nil
 }
 
@@ -122,6 +339,19 @@ func (c *Config) CheckBid(amount float64) error {
 	return nil
 }
 
+// CheckMinBid validates a bid amount against the configured minimum.
+// Bids must always be positive; min_bid adds an account-specific floor.
+func (c *Config) CheckMinBid(amount float64) error {
+	if amount <= 0 {
+		return fmt.Errorf("bid %.2f must be greater than zero", amount)
+	}
+	if c.MinBid > 0 && amount < c.MinBid {
+		return fmt.Errorf("bid %.2f is below configured min_bid (%.2f). Update min_bid in %s/config.yaml if your storefront allows lower bids",
+			amount, c.MinBid, ConfigDir())
+	}
+	return nil
+}
+
 func Save(cfg *Config, profile string) error {
 	dir := ConfigDir()
 	if err := os.MkdirAll(dir, 0700); err != nil {
@@ -158,3 +388,280 @@ func Save(cfg *Config, profile string) error {
 	// Ensure restrictive permissions
 	return os.Chmod(configPath, 0600)
 }
+
+// Aliases returns the user-defined command aliases.
+func Aliases() (map[string]string, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	return v.GetStringMapString("aliases"), nil
+}
+
+// SetAlias stores a command alias in config.yaml.
+func SetAlias(name, expansion string) error {
+	return updateFile(func(doc map[string]interface{}) {
+		aliases, _ := doc["aliases"].(map[string]interface{})
+		if aliases == nil {
+			aliases = map[string]interface{}{}
+		}
+		aliases[name] = expansion
+		doc["aliases"] = aliases
+	})
+}
+
+// RemoveAlias deletes a command alias from config.yaml.
+func RemoveAlias(name string) error {
+	return updateFile(func(doc map[string]interface{}) {
+		if aliases, ok := doc["aliases"].(map[string]interface{}); ok {
+			delete(aliases, name)
+		}
+	})
+}
+
+// SaveKeyPairs stores the active profile's key pairs in config.yaml: the
+// first as key_id and private_key_path, the rest under keys.
+func SaveKeyPairs(pairs []KeyPair) error {
+	if len(pairs) == 0 {
+		return fmt.Errorf("a profile needs at least one key")
+	}
+	return updateFile(func(doc map[string]interface{}) {
+		section := doc
+		if cfgProfile != "" && cfgProfile != "default" {
+			profiles, _ := doc["profiles"].(map[string]interface{})
+			if profiles == nil {
+				profiles = map[string]interface{}{}
+			}
+			section, _ = profiles[cfgProfile].(map[string]interface{})
+			if section == nil {
+				section = map[string]interface{}{}
+			}
+			profiles[cfgProfile] = section
+			doc["profiles"] = profiles
+		}
+		section["key_id"] = pairs[0].KeyID
+		section["private_key_path"] = pairs[0].PrivateKeyPath
+		if len(pairs) > 1 {
+			section["keys"] = pairs[1:]
+		} else {
+			delete(section, "keys")
+		}
+	})
+}
+
+// ReportPreset is a saved report query, stored under `report_presets:`.
+type ReportPreset struct {
+	Level       string   `mapstructure:"level" yaml:"level" json:"level"`
+	Range       string   `mapstructure:"range" yaml:"range" json:"range"`
+	CampaignID  int64    `mapstructure:"campaign_id" yaml:"campaign_id,omitempty" json:"campaignId,omitempty"`
+	GroupBy     []string `mapstructure:"group_by" yaml:"group_by,omitempty" json:"groupBy,omitempty"`
+	Metrics     []string `mapstructure:"metrics" yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	Granularity string   `mapstructure:"granularity" yaml:"granularity,omitempty" json:"granularity,omitempty"`
+	Limit       int      `mapstructure:"limit" yaml:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ReportPresets returns the saved report presets by name.
+func ReportPresets() (map[string]ReportPreset, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	presets := map[string]ReportPreset{}
+	if err := v.UnmarshalKey("report_presets", &presets); err != nil {
+		return nil, fmt.Errorf("error parsing report presets: %w", err)
+	}
+	return presets, nil
+}
+
+// SaveReportPreset stores a report preset in config.yaml.
+func SaveReportPreset(name string, preset ReportPreset) error {
+	return updateFile(func(doc map[string]interface{}) {
+		presets, _ := doc["report_presets"].(map[string]interface{})
+		if presets == nil {
+			presets = map[string]interface{}{}
+		}
+		presets[name] = preset
+		doc["report_presets"] = presets
+	})
+}
+
+// RemoveReportPreset deletes a report preset from config.yaml.
+func RemoveReportPreset(name string) error {
+	return updateFile(func(doc map[string]interface{}) {
+		if presets, ok := doc["report_presets"].(map[string]interface{}); ok {
+			delete(presets, name)
+		}
+	})
+}
+
+// updateFile applies fn to the raw config.yaml document and writes it back,
+// preserving keys this package doesn't model.
+func updateFile(fn func(doc map[string]interface{})) error {
+	dir := ConfigDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+
+	doc := map[string]interface{}{}
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	if len(data) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("error parsing config: %w", err)
+		}
+	}
+
+	fn(doc)
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("error encoding config: %w", err)
+	}
+	if err := os.WriteFile(configPath, out, 0600); err != nil {
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	return os.Chmod(configPath, 0600)
+}
+
+// EmailConfig is the `notifications.email:` section: SMTP settings, default
+// message templates, and recipients per named job.
+type EmailConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	// PasswordEnv names the environment variable holding the SMTP password,
+	// so the secret stays out of config.yaml.
+	PasswordEnv string              `mapstructure:"password_env"`
+	From        string              `mapstructure:"from"`
+	Subject     string              `mapstructure:"subject"`
+	Body        string              `mapstructure:"body"`
+	Jobs        map[string]EmailJob `mapstructure:"jobs"`
+}
+
+// EmailJob is a named delivery: its recipients and optional template overrides.
+type EmailJob struct {
+	To      []string `mapstructure:"to"`
+	Cc      []string `mapstructure:"cc"`
+	Subject string   `mapstructure:"subject"`
+	Body    string   `mapstructure:"body"`
+}
+
+// Email returns the email notification settings. A profile's own
+// `notifications.email:` section replaces the top-level one.
+func Email() (*EmailConfig, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	key := "notifications.email"
+	if cfgProfile != "" && cfgProfile != "default" && v.IsSet("profiles."+cfgProfile+"."+key) {
+		key = "profiles." + cfgProfile + "." + key
+	}
+	cfg := &EmailConfig{}
+	if err := v.UnmarshalKey(key, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing email notifications: %w", err)
+	}
+	return cfg, nil
+}
+
+// SecretKeys are the per-user settings left out of shared config bundles:
+// each teammate supplies their own API user and key.
+var SecretKeys = []string{"client_id", "team_id", "key_id", "private_key_path", "keys", "google_service_account_path", "credential_source"}
+
+// Export returns the raw config.yaml document. With noSecrets, SecretKeys are
+// removed from the top level and from every profile.
+func Export(noSecrets bool) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	data, err := os.ReadFile(filepath.Join(ConfigDir(), "config.yaml"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing config: %w", err)
+	}
+	if noSecrets {
+		stripSecrets(doc)
+		if profiles, ok := doc["profiles"].(map[string]interface{}); ok {
+			for _, p := range profiles {
+				if p, ok := p.(map[string]interface{}); ok {
+					stripSecrets(p)
+				}
+			}
+		}
+	}
+	return doc, nil
+}
+
+func stripSecrets(m map[string]interface{}) {
+	for _, k := range SecretKeys {
+		delete(m, k)
+	}
+}
+
+// Import merges a config bundle into config.yaml. Bundle values win, except
+// that secrets already set locally are never replaced.
+func Import(bundle map[string]interface{}) error {
+	return updateFile(func(doc map[string]interface{}) {
+		keepSecrets(doc, bundle)
+		local, _ := doc["profiles"].(map[string]interface{})
+		shared, _ := bundle["profiles"].(map[string]interface{})
+		for name, p := range local {
+			lp, _ := p.(map[string]interface{})
+			sp, _ := shared[name].(map[string]interface{})
+			if lp != nil && sp != nil {
+				keepSecrets(lp, sp)
+			}
+		}
+		mergeMaps(doc, bundle)
+	})
+}
+
+// keepSecrets drops secrets from bundle that local already has a value for.
+func keepSecrets(local, bundle map[string]interface{}) {
+	for _, k := range SecretKeys {
+		if v, ok := local[k]; ok && v != nil && v != "" {
+			delete(bundle, k)
+		}
+	}
+}
+
+// TelemetryConfig is the `telemetry:` section. Remote reporting is off unless
+// Enabled is set; the local usage log behind `asa-cli stats` is on unless
+// DisableLocal is set.
+type TelemetryConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	Endpoint     string `mapstructure:"endpoint"`
+	DisableLocal bool   `mapstructure:"disable_local"`
+}
+
+// Telemetry returns the telemetry settings. They apply to all profiles.
+func Telemetry() (*TelemetryConfig, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &TelemetryConfig{}
+	if err := v.UnmarshalKey("telemetry", cfg); err != nil {
+		return nil, fmt.Errorf("error parsing telemetry: %w", err)
+	}
+	return cfg, nil
+}
+
+// SetTelemetry turns remote usage reporting on or off. An empty endpoint
+// keeps the configured one.
+func SetTelemetry(enabled bool, endpoint string) error {
+	return updateFile(func(doc map[string]interface{}) {
+		section, _ := doc["telemetry"].(map[string]interface{})
+		if section == nil {
+			section = map[string]interface{}{}
+		}
+		section["enabled"] = enabled
+		if endpoint != "" {
+			section["endpoint"] = endpoint
+		}
+		doc["telemetry"] = section
+	})
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// goos is runtime.GOOS, a variable so tests can take the Windows paths.
+var goos = runtime.GOOS
+
+// defaultConfigDir is ~/.asa-cli, or %APPDATA%\asa-cli on Windows unless an
+// existing ~/.asa-cli is found there.
+func defaultConfigDir(home string) string {
+	legacy := filepath.Join(home, ".asa-cli")
+	if goos != "windows" {
+		return legacy
+	}
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	if appData := os.Getenv("APPDATA"); appData != "" {
+		return filepath.Join(appData, "asa-cli")
+	}
+	return legacy
+}
+
+// ExpandPath expands a leading ~ to the home directory and converts the path
+// to the platform's separators. On Windows it also accepts ~\ and MSYS-style
+// drive paths such as /c/Users/me/key.pem.
+func ExpandPath(path string) string {
+	windows := goos == "windows"
+	if path == "~" || strings.HasPrefix(path, "~/") || windows && strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if windows {
+		path = msysToWindows(path)
+	}
+	if path == "" {
+		return path
+	}
+	return filepath.FromSlash(path)
+}
+
+// msysToWindows rewrites /c/rest (Git Bash, MSYS2) to C:/rest.
+func msysToWindows(path string) string {
+	if len(path) >= 2 && path[0] == '/' && isDriveLetter(path[1]) && (len(path) == 2 || path[2] == '/') {
+		rest := path[2:]
+		if rest == "" {
+			rest = "/"
+		}
+		return strings.ToUpper(path[1:2]) + ":" + rest
+	}
+	return path
+}
+
+func isDriveLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withGOOS runs the rest of the test as if on os.
+func withGOOS(t *testing.T, os string) {
+	t.Helper()
+	prev := goos
+	goos = os
+	t.Cleanup(func() { goos = prev })
+}
+
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return home
+}
+
+func TestExpandPath(t *testing.T) {
+	home := setHome(t)
+	tests := []struct {
+		goos string
+		path string
+		want string
+	}{
+		{"linux", "", ""},
+		{"linux", "~", home},
+		{"linux", "~/keys/key.pem", filepath.Join(home, "keys", "key.pem")},
+		{"linux", "~user/key.pem", filepath.FromSlash("~user/key.pem")},
+		{"linux", "/c/Users/me/key.pem", filepath.FromSlash("/c/Users/me/key.pem")},
+		{"linux", "keys/key.pem", filepath.FromSlash("keys/key.pem")},
+		{"windows", "~/keys/key.pem", filepath.Join(home, "keys", "key.pem")},
+		{"windows", `~\key.pem`, filepath.Join(home, `\key.pem`)},
+		{"windows", "/c/Users/me/key.pem", filepath.FromSlash("C:/Users/me/key.pem")},
+		{"windows", "/d", filepath.FromSlash("D:/")},
+		{"windows", "C:/Users/me/key.pem", filepath.FromSlash("C:/Users/me/key.pem")},
+		{"windows", "/cd/key.pem", filepath.FromSlash("/cd/key.pem")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos+" "+tt.path, func(t *testing.T) {
+			withGOOS(t, tt.goos)
+			if got := ExpandPath(tt.path); got != tt.want {
+				t.Errorf("ExpandPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMsysToWindows(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/c/Users/me", "C:/Users/me"},
+		{"/C/Users/me", "C:/Users/me"},
+		{"/z", "Z:/"},
+		{"/c/", "C:/"},
+		{"/cd/x", "/cd/x"},
+		{"/1/x", "/1/x"},
+		{"c/x", "c/x"},
+		{"C:/x", "C:/x"},
+		{"/", "/"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := msysToWindows(tt.path); got != tt.want {
+			t.Errorf("msysToWindows(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultConfigDir(t *testing.T) {
+	appData := t.TempDir()
+	tests := []struct {
+		name    string
+		goos    string
+		appData string
+		legacy  bool
+		want    func(home string) string
+	}{
+		{"unix", "linux", appData, false, func(home string) string { return filepath.Join(home, ".asa-cli") }},
+		{"windows uses APPDATA", "windows", appData, false, func(string) string { return filepath.Join(appData, "asa-cli") }},
+		{"windows keeps existing ~/.asa-cli", "windows", appData, true, func(home string) string { return filepath.Join(home, ".asa-cli") }},
+		{"windows without APPDATA", "windows", "", false, func(home string) string { return filepath.Join(home, ".asa-cli") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withGOOS(t, tt.goos)
+			t.Setenv("APPDATA", tt.appData)
+			home := t.TempDir()
+			if tt.legacy {
+				if err := os.Mkdir(filepath.Join(home, ".asa-cli"), 0700); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got, want := defaultConfigDir(home), tt.want(home); got != want {
+				t.Errorf("defaultConfigDir = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProfileFile returns a per-profile state file under the config directory:
+// <name>.json for the default profile and <name>_<profile>.json for others.
+func ProfileFile(name, profile string) string {
+	if profile == "" || profile == "default" {
+		return filepath.Join(ConfigDir(), name+".json")
+	}
+	return filepath.Join(ConfigDir(), name+"_"+profile+".json")
+}
+
+// LoadJSON decodes the JSON file at path into v. A missing file leaves v as
+// it is and is not an error. what names the file in errors.
+func LoadJSON(path, what string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", what, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing %s %s: %w", what, path, err)
+	}
+	return nil
+}
+
+// SaveJSON writes v to path as indented JSON with WriteFileAtomic. what
+// names the file in errors.
+func SaveJSON(path, what string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", what, err)
+	}
+	if err := WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("writing %s: %w", what, err)
+	}
+	return nil
+}
+
+// WriteFileAtomic writes data to path through a temporary file in the same
+// directory that is renamed over it, so a crash or a concurrent reader never
+// sees a partial file. The directory is created 0700 and the file is 0600.
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, 0600)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileFile(t *testing.T) {
+	setHome(t)
+	t.Setenv("APPDATA", "")
+	dir := ConfigDir()
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{"", filepath.Join(dir, "tags.json")},
+		{"default", filepath.Join(dir, "tags.json")},
+		{"prod", filepath.Join(dir, "tags_prod.json")},
+	}
+	for _, tt := range tests {
+		if got := ProfileFile("tags", tt.profile); got != tt.want {
+			t.Errorf("ProfileFile(tags, %q) = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
+
+func TestSaveLoadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "store.json")
+	type store struct {
+		Names []string `json:"names"`
+	}
+
+	var missing store
+	if err := LoadJSON(path, "store", &missing); err != nil || missing.Names != nil {
+		t.Fatalf("LoadJSON(missing) = %v, %v; want no error and no change", missing, err)
+	}
+
+	for _, names := range [][]string{{"a"}, {"b", "c"}} {
+		if err := SaveJSON(path, "store", store{Names: names}); err != nil {
+			t.Fatal(err)
+		}
+		var got store
+		if err := LoadJSON(path, "store", &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Names) != len(names) || got.Names[0] != names[0] {
+			t.Errorf("LoadJSON = %v, want %v", got.Names, names)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want only store.json (no temp files left)", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var bad store
+	if err := LoadJSON(path, "store", &bad); err == nil {
+		t.Error("LoadJSON(malformed) succeeded, want an error")
+	}
+}
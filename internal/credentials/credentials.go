@@ -0,0 +1,173 @@
+// Package credentials fetches API credentials from a secret manager at
+// runtime, so no key material has to live on disk.
+//
+// Supported sources (config credential_source or ASA_CREDENTIAL_SOURCE):
+//
+//	vault://<path>        HashiCorp Vault KV secret, via VAULT_ADDR and VAULT_TOKEN
+//	op://<vault>/<item>   1Password item, via the op CLI
+//
+// The secret must hold the fields client_id, team_id, key_id, and
+// private_key (PEM contents); org_id is optional.
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Resolve fills cfg's credentials from cfg.CredentialSource, if set. Values
+// from the secret replace those in config; org_id only fills a blank.
+func Resolve(cfg *config.Config) error {
+	if cfg.CredentialSource == "" {
+		return nil
+	}
+	scheme, path, ok := strings.Cut(cfg.CredentialSource, "://")
+	if !ok || path == "" {
+		return fmt.Errorf("invalid credential_source %q (expected vault://<path> or op://<vault>/<item>)", cfg.CredentialSource)
+	}
+
+	var (
+		fields map[string]string
+		err    error
+	)
+	switch scheme {
+	case "vault":
+		fields, err = fromVault(path)
+	case "op":
+		fields, err = fromOnePassword(path)
+	default:
+		return fmt.Errorf("unsupported credential source %q (expected vault or op)", scheme)
+	}
+	if err != nil {
+		return fmt.Errorf("reading credentials from %s: %w", cfg.CredentialSource, err)
+	}
+
+	var missing []string
+	for _, k := range []string{"client_id", "team_id", "key_id", "private_key"} {
+		if fields[k] == "" {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("credential source %s is missing fields: %s", cfg.CredentialSource, strings.Join(missing, ", "))
+	}
+
+	cfg.ClientID = fields["client_id"]
+	cfg.TeamID = fields["team_id"]
+	cfg.KeyID = fields["key_id"]
+	cfg.PrivateKey = fields["private_key"]
+	cfg.PrivateKeyPath = ""
+	if cfg.OrgID == "" {
+		cfg.OrgID = fields["org_id"]
+	}
+	return nil
+}
+
+// fromVault reads a KV secret. KV v2 mounts are tried first (inserting
+// "data/" after the mount), then the path as given for KV v1.
+func fromVault(path string) (map[string]string, error) {
+	addr := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+
+	mount, rest, _ := strings.Cut(strings.Trim(path, "/"), "/")
+	candidates := []string{mount + "/data/" + rest, strings.Trim(path, "/")}
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	for i, p := range candidates {
+		req, err := http.NewRequest("GET", addr+"/v1/"+p, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Vault-Token", token)
+		if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+			req.Header.Set("X-Vault-Namespace", ns)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusNotFound && i < len(candidates)-1 {
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("vault returned HTTP %d", resp.StatusCode)
+		}
+
+		var secret struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &secret); err != nil {
+			return nil, fmt.Errorf("parsing vault response: %w", err)
+		}
+		data := secret.Data
+		// KV v2 nests the secret under data.data.
+		if inner, ok := data["data"].(map[string]interface{}); ok && i == 0 {
+			data = inner
+		}
+		return stringFields(data), nil
+	}
+	return nil, fmt.Errorf("secret not found")
+}
+
+// fromOnePassword reads an item with the op CLI, matching fields by label.
+func fromOnePassword(path string) (map[string]string, error) {
+	vault, item, ok := strings.Cut(path, "/")
+	if !ok || vault == "" || item == "" {
+		return nil, fmt.Errorf("expected op://<vault>/<item>")
+	}
+	cmd := exec.Command("op", "item", "get", item, "--vault", vault, "--format", "json")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("op: %s", msg)
+		}
+		return nil, fmt.Errorf("running op: %w", err)
+	}
+
+	var parsed struct {
+		Fields []struct {
+			Label string `json:"label"`
+			Value string `json:"value"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing op output: %w", err)
+	}
+	fields := map[string]string{}
+	for _, f := range parsed.Fields {
+		fields[strings.ToLower(f.Label)] = f.Value
+	}
+	return fields, nil
+}
+
+func stringFields(data map[string]interface{}) map[string]string {
+	out := map[string]string{}
+	for k, v := range data {
+		switch v := v.(type) {
+		case string:
+			out[k] = v
+		case float64:
+			out[k] = fmt.Sprintf("%.0f", v)
+		}
+	}
+	return out
+}
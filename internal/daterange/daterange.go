@@ -0,0 +1,140 @@
+package daterange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateFormat is the date layout used by the reporting API.
+const DateFormat = "2006-01-02"
+
+// Range is an inclusive span of calendar days.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Help describes the accepted range expressions, for flag usage strings.
+const Help = "today, yesterday, last-Nd (e.g. last-7d), last-Nw, this-week, last-week, mtd, last-month, or YYYY-MM-DD:YYYY-MM-DD"
+
+// Parse resolves a range expression relative to now.
+//
+// Relative ranges ending in "d"/"w" count back from yesterday, since today's
+// data is still incomplete: last-7d on a Wednesday covers the previous
+// Wednesday through Tuesday.
+func Parse(expr string, now time.Time) (Range, error) {
+	today := day(now)
+	yesterday := today.AddDate(0, 0, -1)
+	expr = strings.ToLower(strings.TrimSpace(expr))
+
+	switch expr {
+	case "today":
+		return Range{Start: today, End: today}, nil
+	case "yesterday":
+		return Range{Start: yesterday, End: yesterday}, nil
+	case "this-week":
+		return Range{Start: weekStart(today), End: today}, nil
+	case "last-week":
+		start := weekStart(today).AddDate(0, 0, -7)
+		return Range{Start: start, End: start.AddDate(0, 0, 6)}, nil
+	case "mtd":
+		return Range{Start: time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location()), End: today}, nil
+	case "last-month":
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return Range{Start: first.AddDate(0, -1, 0), End: first.AddDate(0, 0, -1)}, nil
+	}
+
+	if strings.HasPrefix(expr, "last-") && len(expr) > 6 {
+		unit := expr[len(expr)-1]
+		n, err := strconv.Atoi(expr[5 : len(expr)-1])
+		if err != nil || n <= 0 {
+			return Range{}, fmt.Errorf("invalid range %q (expected %s)", expr, Help)
+		}
+		switch unit {
+		case 'd':
+			return Range{Start: yesterday.AddDate(0, 0, -(n - 1)), End: yesterday}, nil
+		case 'w':
+			return Range{Start: yesterday.AddDate(0, 0, -(7*n - 1)), End: yesterday}, nil
+		}
+	}
+
+	if parts := strings.SplitN(expr, ":", 2); len(parts) == 2 {
+		start, err := time.ParseInLocation(DateFormat, parts[0], now.Location())
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid start date %q: expected YYYY-MM-DD", parts[0])
+		}
+		end, err := time.ParseInLocation(DateFormat, parts[1], now.Location())
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid end date %q: expected YYYY-MM-DD", parts[1])
+		}
+		if end.Before(start) {
+			return Range{}, fmt.Errorf("range end %s is before start %s", parts[1], parts[0])
+		}
+		return Range{Start: start, End: end}, nil
+	}
+
+	return Range{}, fmt.Errorf("invalid range %q (expected %s)", expr, Help)
+}
+
+// StartDate returns the start as YYYY-MM-DD.
+func (r Range) StartDate() string { return r.Start.Format(DateFormat) }
+
+// EndDate returns the end as YYYY-MM-DD.
+func (r Range) EndDate() string { return r.End.Format(DateFormat) }
+
+// Days returns the number of days in the range, inclusive.
+func (r Range) Days() int {
+	return int(r.End.Sub(r.Start).Hours()/24) + 1
+}
+
+// Previous returns the range of equal length immediately before r.
+func (r Range) Previous() Range {
+	n := r.Days()
+	return Range{Start: r.Start.AddDate(0, 0, -n), End: r.Start.AddDate(0, 0, -1)}
+}
+
+func (r Range) String() string {
+	return r.StartDate() + " to " + r.EndDate()
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns the Monday on or before t.
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// ParseAge parses an age such as "90d", "2w", or a Go duration like "36h".
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		if v, err := strconv.Atoi(s[:n-1]); err == nil && v >= 0 {
+			days := v
+			if s[n-1] == 'w' {
+				days *= 7
+			}
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (expected e.g. 90d, 2w, or 36h)", s)
+	}
+	return d, nil
+}
+
+// ParseAPITime parses a timestamp from the campaign management API, e.g.
+// "2024-01-15T10:30:00.000". Timestamps without a zone are taken as UTC.
+func ParseAPITime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
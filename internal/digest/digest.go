@@ -0,0 +1,181 @@
+// Package digest renders a periodic performance digest, which combines the
+// summary, movers, budget, serving and search-term analyses, as Markdown or
+// HTML for posting to chat or email.
+package digest
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+)
+
+// Budget is an enabled campaign's spend against its daily budget.
+type Budget struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	DailyBudget float64 `json:"dailyBudget"`
+	Spend       float64 `json:"spend"`
+	// Utilization is Spend over DailyBudget times the days in the period.
+	Utilization float64 `json:"utilization"`
+}
+
+// ServingIssue is an enabled campaign that is not running.
+type ServingIssue struct {
+	ID            int64    `json:"id"`
+	Name          string   `json:"name"`
+	ServingStatus string   `json:"servingStatus"`
+	Reasons       []string `json:"reasons,omitempty"`
+}
+
+// Opportunity is a converting search term with no exact keyword.
+type Opportunity struct {
+	CampaignID   int64   `json:"campaignId"`
+	CampaignName string  `json:"campaignName"`
+	Term         string  `json:"term"`
+	Installs     int64   `json:"installs"`
+	CPI          float64 `json:"cpi"`
+	SuggestedBid float64 `json:"suggestedBid"`
+}
+
+// Digest is the data shown in a digest.
+type Digest struct {
+	OrgName  string `json:"orgName"`
+	Currency string `json:"currency"`
+	Period   string `json:"period"`
+	Previous string `json:"previous"`
+
+	Totals        aggregate.Metrics  `json:"totals"`
+	PrevTotals    aggregate.Metrics  `json:"previousTotals"`
+	TopCampaigns  []aggregate.Entity `json:"topCampaigns"`
+	Movers        []aggregate.Mover  `json:"movers"`
+	Budgets       []Budget           `json:"budgets"`
+	ServingIssues []ServingIssue     `json:"servingIssues"`
+	Opportunities []Opportunity      `json:"opportunities"`
+}
+
+// IsHTML reports whether path should be written as HTML rather than Markdown.
+func IsHTML(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".html") || strings.HasSuffix(lower, ".htm")
+}
+
+// change formats the relative change from prev to cur, e.g. "+12.3%".
+func change(cur, prev float64) string {
+	if prev == 0 {
+		if cur == 0 {
+			return "—"
+		}
+		return "new"
+	}
+	return fmt.Sprintf("%+.1f%%", (cur-prev)/prev*100)
+}
+
+// section is a titled table, or a note when it has no rows.
+type section struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+	Empty   string
+}
+
+func sections(d *Digest) []section {
+	cur := d.Currency
+	kpis := section{Title: "Headline", Headers: []string{"Metric", "Value", "Change"}}
+	kpis.Rows = [][]string{
+		{"Spend", fmt.Sprintf("%.2f %s", d.Totals.Spend, cur), change(d.Totals.Spend, d.PrevTotals.Spend)},
+		{"Installs", fmt.Sprintf("%d", d.Totals.Installs), change(float64(d.Totals.Installs), float64(d.PrevTotals.Installs))},
+		{"CPI", fmt.Sprintf("%.2f %s", d.Totals.CPI(), cur), change(d.Totals.CPI(), d.PrevTotals.CPI())},
+		{"Tap-through rate", fmt.Sprintf("%.2f%%", d.Totals.TTR()*100), change(d.Totals.TTR(), d.PrevTotals.TTR())},
+	}
+
+	top := section{Title: "Top campaigns by spend", Headers: []string{"Campaign", "Spend", "Installs", "CPI"}, Empty: "No spend in this period."}
+	for _, e := range d.TopCampaigns {
+		top.Rows = append(top.Rows, []string{e.Name, fmt.Sprintf("%.2f", e.Spend), fmt.Sprintf("%d", e.Installs), fmt.Sprintf("%.2f", e.CPI())})
+	}
+
+	movers := section{Title: "Biggest movers", Headers: []string{"Campaign", "Spend", "Previous", "Change"}, Empty: "No spend in either period."}
+	for _, m := range d.Movers {
+		movers.Rows = append(movers.Rows, []string{m.Name, fmt.Sprintf("%.2f", m.Spend), fmt.Sprintf("%.2f", m.PrevSpend), fmt.Sprintf("%+.2f (%s)", m.Delta, change(m.Spend, m.PrevSpend))})
+	}
+
+	budgets := section{Title: "Budget utilization", Headers: []string{"Campaign", "Daily budget", "Spend", "Utilization"}, Empty: "No enabled campaigns with a daily budget."}
+	for _, b := range d.Budgets {
+		budgets.Rows = append(budgets.Rows, []string{b.Name, fmt.Sprintf("%.2f", b.DailyBudget), fmt.Sprintf("%.2f", b.Spend), fmt.Sprintf("%.0f%%", b.Utilization*100)})
+	}
+
+	serving := section{Title: "Serving issues", Headers: []string{"Campaign", "Status", "Reasons"}, Empty: "None — all enabled campaigns are running."}
+	for _, s := range d.ServingIssues {
+		serving.Rows = append(serving.Rows, []string{s.Name, s.ServingStatus, strings.Join(s.Reasons, ", ")})
+	}
+
+	opps := section{Title: "New search-term opportunities", Headers: []string{"Term", "Campaign", "Installs", "CPI", "Suggested bid"}, Empty: "No untargeted converting search terms."}
+	for _, o := range d.Opportunities {
+		opps.Rows = append(opps.Rows, []string{o.Term, o.CampaignName, fmt.Sprintf("%d", o.Installs), fmt.Sprintf("%.2f", o.CPI), fmt.Sprintf("%.2f", o.SuggestedBid)})
+	}
+
+	return []section{kpis, top, movers, budgets, serving, opps}
+}
+
+// WriteMarkdown renders the digest as Markdown.
+func WriteMarkdown(w io.Writer, d *Digest) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — Apple Search Ads digest\n\n", d.OrgName)
+	fmt.Fprintf(&b, "%s, compared with %s.\n", d.Period, d.Previous)
+	for _, s := range sections(d) {
+		fmt.Fprintf(&b, "\n## %s\n\n", s.Title)
+		if len(s.Rows) == 0 {
+			fmt.Fprintf(&b, "%s\n", s.Empty)
+			continue
+		}
+		b.WriteString("| " + strings.Join(s.Headers, " | ") + " |\n")
+		b.WriteString("|" + strings.Repeat(" --- |", len(s.Headers)) + "\n")
+		for _, row := range s.Rows {
+			cells := make([]string, len(row))
+			for i, c := range row {
+				cells[i] = strings.ReplaceAll(c, "|", `\|`)
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Digest.OrgName}} — Apple Search Ads digest</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; color: #222; max-width: 760px; margin: 24px auto; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+h2 { font-size: 16px; margin-top: 28px; }
+p.period { color: #666; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #e5e5e5; }
+th { background: #f5f5f5; }
+</style>
+</head>
+<body>
+<h1>{{.Digest.OrgName}} — Apple Search Ads digest</h1>
+<p class="period">{{.Digest.Period}}, compared with {{.Digest.Previous}}.</p>
+{{range .Sections}}<h2>{{.Title}}</h2>
+{{if .Rows}}<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{else}}<p>{{.Empty}}</p>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// WriteHTML renders the digest as a standalone HTML page.
+func WriteHTML(w io.Writer, d *Digest) error {
+	return htmlTemplate.Execute(w, struct {
+		Digest   *Digest
+		Sections []section
+	}{d, sections(d)})
+}
@@ -0,0 +1,351 @@
+// Package drift compares API responses with the models they are decoded into,
+// to catch fields Apple adds, renames, or stops sending before they are lost
+// silently.
+package drift
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Finding kinds.
+const (
+	Unknown = "unknown" // in the response, not in the model
+	Missing = "missing" // in the model without omitempty, not in the response
+	Value   = "value"   // an enum value the model doesn't list
+)
+
+// Finding is one field that differs between a response and its model.
+type Finding struct {
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command,omitempty"`
+	Endpoint string    `json:"endpoint"`
+	Field    string    `json:"field"`
+	Kind     string    `json:"kind"`
+	Model    string    `json:"model"`
+}
+
+// Recorder collects findings for a run. It does nothing unless Enabled.
+type Recorder struct {
+	Enabled bool
+
+	mu       sync.Mutex
+	seen     map[string]bool
+	findings []Finding
+}
+
+// Check compares JSON with the value it was decoded into and records each
+// difference once per endpoint. prefix names where data sits in the response
+// body ("" for the body itself).
+func (r *Recorder) Check(method, path, prefix string, data []byte, v interface{}) {
+	if r == nil || !r.Enabled || v == nil {
+		return
+	}
+	var raw interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return
+	}
+	t := reflect.TypeOf(v)
+	endpoint := method + " " + Endpoint(path)
+	var found []Finding
+	walk(raw, t, prefix, func(field, kind string, model reflect.Type) {
+		found = append(found, Finding{Endpoint: endpoint, Field: field, Kind: kind, Model: model.String()})
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seen == nil {
+		r.seen = map[string]bool{}
+	}
+	for _, f := range found {
+		key := f.Endpoint + "|" + f.Field + "|" + f.Kind
+		if r.seen[key] {
+			continue
+		}
+		r.seen[key] = true
+		f.Time = time.Now().UTC()
+		r.findings = append(r.findings, f)
+	}
+}
+
+// Take returns the findings recorded so far and clears them.
+func (r *Recorder) Take() []Finding {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.findings
+	r.findings = nil
+	return out
+}
+
+var idSegment = regexp.MustCompile(`/\d+(/|$)`)
+
+// Endpoint strips the query and replaces numeric IDs in a request path, so
+// findings for /campaigns/1 and /campaigns/2 are reported once.
+func Endpoint(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	for idSegment.MatchString(path) {
+		path = idSegment.ReplaceAllString(path, "/{id}$1")
+	}
+	return path
+}
+
+// enum is implemented by enum types that list their values.
+type enum interface {
+	EnumValues() []string
+}
+
+var (
+	enumType        = reflect.TypeOf((*enum)(nil)).Elem()
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// walk reports the fields of raw that t has no place for, and the fields t
+// expects that raw lacks. Types with their own decoding, maps of interfaces,
+// and interfaces are not inspected.
+func walk(raw interface{}, t reflect.Type, path string, report func(field, kind string, model reflect.Type)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if s, ok := raw.(string); ok && t.Implements(enumType) {
+		if !knownValue(reflect.Zero(t).Interface().(enum), s) {
+			report(path+"="+s, Value, t)
+		}
+		return
+	}
+	if raw == nil || reflect.PointerTo(t).Implements(jsonUnmarshaler) || reflect.PointerTo(t).Implements(textUnmarshaler) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := structFields(t)
+		for key, val := range obj {
+			f, ok := lookupField(fields, key)
+			if !ok {
+				report(join(path, key), Unknown, t)
+				continue
+			}
+			walk(val, f.typ, join(path, f.name), report)
+		}
+		for _, f := range fields {
+			if f.omitEmpty {
+				continue
+			}
+			if _, ok := lookupKey(obj, f.name); !ok {
+				report(join(path, f.name), Missing, t)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		list, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range list {
+			walk(item, t.Elem(), path+"[]", report)
+		}
+	case reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, val := range obj {
+			walk(val, t.Elem(), path+".*", report)
+		}
+	}
+}
+
+func knownValue(e enum, s string) bool {
+	for _, v := range e.EnumValues() {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return s == ""
+}
+
+type field struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// structFields lists the JSON fields of a struct type, flattening embedded
+// structs the way encoding/json does.
+func structFields(t reflect.Type) []field {
+	var out []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				out = append(out, structFields(ft)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		out = append(out, field{name: name, typ: sf.Type, omitEmpty: strings.Contains(opts, "omitempty")})
+	}
+	return out
+}
+
+// lookupField finds a field by JSON key, exact match first and then
+// case-insensitively, as encoding/json does.
+func lookupField(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+func lookupKey(obj map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := obj[name]; ok {
+		return v, true
+	}
+	for k, v := range obj {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Path returns the local drift log.
+func Path() string {
+	return filepath.Join(config.ConfigDir(), "drift.jsonl")
+}
+
+// Append adds findings to the local drift log.
+func Append(findings []Finding) error {
+	if err := os.MkdirAll(config.ConfigDir(), 0700); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	f, err := os.OpenFile(Path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening drift log: %w", err)
+	}
+	defer f.Close()
+	for _, finding := range findings {
+		data, err := json.Marshal(finding)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads the drift log. Malformed lines are skipped.
+func Load() ([]Finding, error) {
+	f, err := os.Open(Path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening drift log: %w", err)
+	}
+	defer f.Close()
+	var out []Finding
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var finding Finding
+		if json.Unmarshal(scanner.Bytes(), &finding) != nil {
+			continue
+		}
+		out = append(out, finding)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading drift log: %w", err)
+	}
+	return out, nil
+}
+
+// Summary is one field across every logged run.
+type Summary struct {
+	Endpoint  string    `json:"endpoint"`
+	Field     string    `json:"field"`
+	Kind      string    `json:"kind"`
+	Model     string    `json:"model"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Summarize groups findings by endpoint, field, and kind, ordered by endpoint
+// and field.
+func Summarize(findings []Finding) []Summary {
+	index := map[string]int{}
+	var out []Summary
+	for _, f := range findings {
+		key := f.Endpoint + "|" + f.Field + "|" + f.Kind
+		i, ok := index[key]
+		if !ok {
+			i = len(out)
+			index[key] = i
+			out = append(out, Summary{Endpoint: f.Endpoint, Field: f.Field, Kind: f.Kind, Model: f.Model, FirstSeen: f.Time})
+		}
+		s := &out[i]
+		s.Count++
+		if f.Time.Before(s.FirstSeen) {
+			s.FirstSeen = f.Time
+		}
+		if f.Time.After(s.LastSeen) {
+			s.LastSeen = f.Time
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Endpoint != out[j].Endpoint {
+			return out[i].Endpoint < out[j].Endpoint
+		}
+		return out[i].Field < out[j].Field
+	})
+	return out
+}
@@ -0,0 +1,410 @@
+// Package executor runs bulk API work as a queue of batches on a small pool of
+// workers. It sizes batches from the amount of work, alternates read and
+// write batches, pauses every worker when the API rate limits, retries
+// transient failures with backoff, and reports throughput.
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/progress"
+)
+
+// Kind says whether a batch reads or writes. When both are queued, workers
+// take them in turn, so writes start while reads are still running.
+type Kind int
+
+const (
+	Read Kind = iota
+	Write
+)
+
+const (
+	defaultWorkers  = 4
+	defaultMaxBatch = 100
+	defaultRetries  = 5
+	defaultBackoff  = time.Second
+	maxBackoff      = time.Minute
+
+	// minSpread is the smallest batch worth splitting work into for the
+	// sake of parallelism; smaller jobs go out in one request.
+	minSpread = 10
+
+	// growAfter is how many successful requests in a row restore a worker
+	// taken away by a rate limit.
+	growAfter = 10
+)
+
+// Options configures an Executor. Zero values take the defaults.
+type Options struct {
+	// Label names the work on the progress bar.
+	Label string
+	// Workers is the most batches in flight at once (default 4). Each rate
+	// limit takes one away until requests succeed again.
+	Workers int
+	// MaxBatch is the most items per batch (default 100). Jobs of less than
+	// MaxBatch per worker are split evenly across the workers instead.
+	MaxBatch int
+	// Retries is how often a batch is retried after a transient failure
+	// (default 5). Write batches are only retried when they can't have been
+	// applied; see Retryable.
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each further
+	// one (default 1s). A 429's Retry-After takes precedence.
+	Backoff time.Duration
+	// StopOnError stops starting batches once one fails for good. Batches
+	// already running finish; the rest are counted as skipped.
+	StopOnError bool
+}
+
+// Stats summarizes a run.
+type Stats struct {
+	Items       int           `json:"items"`
+	Succeeded   int           `json:"succeeded"`
+	Failed      int           `json:"failed"`
+	Skipped     int           `json:"skipped"`
+	Batches     int           `json:"batches"`
+	Requests    int           `json:"requests"`
+	Retries     int           `json:"retries"`
+	RateLimited int           `json:"rateLimited"`
+	Elapsed     time.Duration `json:"elapsed"`
+}
+
+// Throughput is the items processed per second.
+func (s Stats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Succeeded+s.Failed) / s.Elapsed.Seconds()
+}
+
+func (s Stats) String() string {
+	out := fmt.Sprintf("%d item(s) in %d request(s) over %s (%.1f/s)",
+		s.Succeeded+s.Failed, s.Requests, s.Elapsed.Round(100*time.Millisecond), s.Throughput())
+	if s.Retries > 0 {
+		out += fmt.Sprintf("; %d retried, %d rate limited", s.Retries, s.RateLimited)
+	}
+	if s.Skipped > 0 {
+		out += fmt.Sprintf("; %d skipped", s.Skipped)
+	}
+	return out
+}
+
+// Executor is a queue of batches. Queue work with Batches, then call Wait;
+// batches may queue further work while the executor runs.
+type Executor struct {
+	opts  Options
+	sleep func(time.Duration)
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	queues     [2][]*task
+	turn       Kind
+	running    int
+	limit      int
+	streak     int
+	pauseUntil time.Time
+	stopped    bool
+	err        error
+	stats      Stats
+	bar        *progress.Bar
+}
+
+type task struct {
+	kind  Kind
+	size  int
+	run   func() error
+	done  func(err error)
+	split func() []*task // nil for single items
+}
+
+// New returns an executor with opts, filling in defaults.
+func New(opts Options) *Executor {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = defaultMaxBatch
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	} else if opts.Retries == 0 {
+		opts.Retries = defaultRetries
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+	e := &Executor{opts: opts, sleep: time.Sleep, limit: opts.Workers}
+	e.cond = sync.NewCond(&e.mu)
+	return e
+}
+
+// BatchSize is the batch size for n items: n spread evenly over the workers,
+// at most MaxBatch, and no smaller than needed to keep small jobs in a single
+// request.
+func (e *Executor) BatchSize(n int) int {
+	size := max((n+e.opts.Workers-1)/e.opts.Workers, minSpread)
+	return max(1, min(size, e.opts.MaxBatch))
+}
+
+// Batches queues items in batches of e.BatchSize(len(items)). run is called
+// for each batch, again on each retry; done, if not nil, is called once with
+// the batch's final error and the offset of its first item. A batch the API
+// rejects as too large (HTTP 413) is split in half and the halves queued
+// instead. Batches skipped after a StopOnError failure are not passed to done.
+func Batches[T any](e *Executor, kind Kind, items []T, run func(batch []T) error, done func(offset int, batch []T, err error)) {
+	size := e.BatchSize(len(items))
+	var tasks []*task
+	for off := 0; off < len(items); off += size {
+		tasks = append(tasks, batchTask(kind, items[off:min(off+size, len(items))], off, run, done))
+	}
+	e.add(tasks, true)
+}
+
+func batchTask[T any](kind Kind, items []T, offset int, run func([]T) error, done func(int, []T, error)) *task {
+	t := &task{
+		kind: kind,
+		size: len(items),
+		run:  func() error { return run(items) },
+		done: func(err error) {
+			if done != nil {
+				done(offset, items, err)
+			}
+		},
+	}
+	if len(items) > 1 {
+		t.split = func() []*task {
+			h := len(items) / 2
+			return []*task{
+				batchTask(kind, items[:h], offset, run, done),
+				batchTask(kind, items[h:], offset+h, run, done),
+			}
+		}
+	}
+	return t
+}
+
+// add queues tasks. New work counts toward the total; halves of a split
+// batch don't.
+func (e *Executor) add(tasks []*task, count bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, t := range tasks {
+		e.queues[t.kind] = append(e.queues[t.kind], t)
+		if count {
+			e.stats.Items += t.size
+		}
+	}
+	if e.bar != nil {
+		e.bar.SetTotal(e.stats.Items)
+	}
+	e.cond.Broadcast()
+}
+
+// Wait runs queued batches until none are left and returns the run's
+// statistics. With StopOnError, the error is the first batch failure.
+func (e *Executor) Wait() (Stats, error) {
+	start := time.Now()
+	e.mu.Lock()
+	e.bar = progress.New(e.opts.Label, e.stats.Items)
+	e.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < e.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.work()
+		}()
+	}
+	wg.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.bar.Done()
+	e.stats.Elapsed = time.Since(start)
+	return e.stats, e.err
+}
+
+func (e *Executor) work() {
+	for {
+		t := e.take()
+		if t == nil {
+			return
+		}
+		err := e.attempt(t)
+		if err != nil && api.StatusOf(err) == http.StatusRequestEntityTooLarge && t.split != nil {
+			e.add(t.split(), false)
+			e.finish(t, nil, true)
+			continue
+		}
+		// done runs before the task is finished, so work it queues is seen
+		// before the executor decides it's out of work.
+		t.done(err)
+		e.finish(t, err, false)
+	}
+}
+
+// take waits for a batch a worker may start, or returns nil when all work is
+// done.
+func (e *Executor) take() *task {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for {
+		if e.stopped {
+			for k := range e.queues {
+				for _, t := range e.queues[k] {
+					e.stats.Skipped += t.size
+				}
+				e.queues[k] = nil
+			}
+		}
+		if len(e.queues[Read])+len(e.queues[Write]) == 0 {
+			if e.running == 0 {
+				e.cond.Broadcast()
+				return nil
+			}
+			e.cond.Wait()
+			continue
+		}
+		if e.running >= e.limit {
+			e.cond.Wait()
+			continue
+		}
+		if wait := time.Until(e.pauseUntil); wait > 0 {
+			e.mu.Unlock()
+			e.sleep(wait)
+			e.mu.Lock()
+			continue
+		}
+
+		kind := e.turn
+		if len(e.queues[kind]) == 0 {
+			kind = 1 - kind
+		}
+		t := e.queues[kind][0]
+		e.queues[kind] = e.queues[kind][1:]
+		e.turn = 1 - kind
+		e.running++
+		return t
+	}
+}
+
+// attempt runs a batch, retrying transient failures. A rate limit pauses
+// every worker and takes one away until requests succeed again.
+func (e *Executor) attempt(t *task) error {
+	for i := 0; ; i++ {
+		e.mu.Lock()
+		wait := time.Until(e.pauseUntil)
+		e.mu.Unlock()
+		if wait > 0 {
+			e.sleep(wait)
+		}
+
+		err := t.run()
+
+		e.mu.Lock()
+		e.stats.Requests++
+		if err == nil {
+			e.streak++
+			if e.streak >= growAfter && e.limit < e.opts.Workers {
+				e.limit++
+				e.streak = 0
+				e.cond.Broadcast()
+			}
+			e.mu.Unlock()
+			return nil
+		}
+		if i >= e.opts.Retries || !Retryable(t.kind, err) {
+			e.mu.Unlock()
+			return err
+		}
+		wait = e.backoff(i, err)
+		e.stats.Retries++
+		if api.IsRateLimited(err) {
+			e.stats.RateLimited++
+			e.streak = 0
+			if e.limit > 1 {
+				e.limit--
+			}
+			if until := time.Now().Add(wait); until.After(e.pauseUntil) {
+				e.pauseUntil = until
+			}
+		}
+		e.mu.Unlock()
+		e.sleep(wait)
+	}
+}
+
+// backoff is the wait before retry i (from 0): the Retry-After of a 429 if
+// given, otherwise Backoff doubled per retry with up to 50% jitter.
+func (e *Executor) backoff(i int, err error) time.Duration {
+	if d := api.RetryAfterOf(err); d > 0 {
+		return d
+	}
+	d := min(e.opts.Backoff<<i, maxBackoff)
+	return d + time.Duration(rand.Int64N(int64(d)/2+1))
+}
+
+func (e *Executor) finish(t *task, err error, split bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.running--
+	if !split {
+		e.stats.Batches++
+		if err != nil {
+			e.stats.Failed += t.size
+			if e.opts.StopOnError && !e.stopped {
+				e.stopped = true
+				e.err = err
+			}
+		} else {
+			e.stats.Succeeded += t.size
+		}
+		e.bar.Add(t.size)
+	}
+	e.cond.Broadcast()
+}
+
+// Transient reports whether err is worth retrying: a rate limit, a server
+// error, or a network failure.
+func Transient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if status := api.StatusOf(err); status != 0 {
+		return status == http.StatusTooManyRequests || status >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Retryable reports whether a batch of kind should be retried after err.
+// Reads are retried on any transient failure. A write may already have been
+// applied when a server error, timeout, or dropped connection comes back, so
+// it is retried only on a rate limit or when the request was never sent.
+func Retryable(kind Kind, err error) bool {
+	if kind == Read {
+		return Transient(err)
+	}
+	return api.StatusOf(err) == http.StatusTooManyRequests || notSent(err)
+}
+
+// notSent reports whether err is a failure to connect: the host couldn't be
+// resolved or the connection couldn't be opened.
+func notSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
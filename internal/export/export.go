@@ -0,0 +1,50 @@
+// Package export writes tabular report data to external destinations
+// identified by a URI, e.g. gsheet://<spreadsheetId>/<tab> or
+// postgres://host/db?table=asa_keywords.
+package export
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Table is a header row plus data rows. Cells are strings, integers, or
+// floats so typed destinations can keep them numeric.
+type Table struct {
+	Columns []string
+	Rows    [][]interface{}
+	// Key lists the columns that identify a row, for destinations that upsert.
+	Key []string
+}
+
+// Options carries destination credentials resolved from config.
+type Options struct {
+	// GoogleCredentials is the path to a Google service account JSON key.
+	GoogleCredentials string
+}
+
+// Schemes lists the supported destination URI schemes.
+var Schemes = []string{"gsheet", "postgres"}
+
+// Write sends t to the destination named by target and returns a short
+// description of what was written.
+func Write(target string, t *Table, opts Options) (string, error) {
+	scheme, rest, ok := strings.Cut(target, "://")
+	if !ok {
+		return "", fmt.Errorf("invalid export target %q (expected scheme://..., one of: %s)", target, strings.Join(Schemes, ", "))
+	}
+	path, rawQuery, _ := strings.Cut(rest, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid export target query: %w", err)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "gsheet":
+		return writeSheet(path, query, t, opts)
+	case "postgres", "postgresql":
+		return writePostgres(target, query, t)
+	}
+	return "", fmt.Errorf("unsupported export scheme %q (expected one of: %s)", scheme, strings.Join(Schemes, ", "))
+}
@@ -0,0 +1,231 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	sheetsBaseURL = "https://sheets.googleapis.com/v4/spreadsheets/"
+	sheetsScope   = "https://www.googleapis.com/auth/spreadsheets"
+)
+
+// writeSheet writes t to a spreadsheet tab, given "<spreadsheetId>/<tab>".
+// mode=append (the default) adds rows below existing data, writing the header
+// only into an empty tab; mode=replace clears the tab first. Missing tabs are
+// created.
+func writeSheet(path string, query url.Values, t *Table, opts Options) (string, error) {
+	id, tab, _ := strings.Cut(path, "/")
+	if id == "" || tab == "" {
+		return "", fmt.Errorf("invalid gsheet target (expected gsheet://<spreadsheetId>/<tab>)")
+	}
+	mode := query.Get("mode")
+	if mode == "" {
+		mode = "append"
+	}
+	if mode != "append" && mode != "replace" {
+		return "", fmt.Errorf("invalid gsheet mode %q (expected append or replace)", mode)
+	}
+
+	credentials := opts.GoogleCredentials
+	if credentials == "" {
+		credentials = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if credentials == "" {
+		return "", fmt.Errorf("no Google service account configured: set google_service_account_path in config.yaml or GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	token, err := googleToken(credentials)
+	if err != nil {
+		return "", err
+	}
+	s := &sheets{id: id, token: token}
+
+	if err := s.ensureTab(tab); err != nil {
+		return "", err
+	}
+
+	// Quote the tab name for A1 notation, doubling embedded quotes.
+	rng := "'" + strings.ReplaceAll(tab, "'", "''") + "'"
+	values := t.Rows
+	switch mode {
+	case "replace":
+		if err := s.do("POST", "/values/"+url.PathEscape(rng)+":clear", struct{}{}, nil); err != nil {
+			return "", fmt.Errorf("clearing tab: %w", err)
+		}
+		values = append([][]interface{}{headerRow(t)}, values...)
+		body := map[string]interface{}{"values": values}
+		if err := s.do("PUT", "/values/"+url.PathEscape(rng+"!A1")+"?valueInputOption=RAW", body, nil); err != nil {
+			return "", fmt.Errorf("writing rows: %w", err)
+		}
+	case "append":
+		var first struct {
+			Values [][]interface{} `json:"values"`
+		}
+		if err := s.do("GET", "/values/"+url.PathEscape(rng+"!1:1"), nil, &first); err != nil {
+			return "", fmt.Errorf("reading tab: %w", err)
+		}
+		if len(first.Values) == 0 {
+			values = append([][]interface{}{headerRow(t)}, values...)
+		}
+		body := map[string]interface{}{"values": values}
+		if err := s.do("POST", "/values/"+url.PathEscape(rng+"!A1")+":append?valueInputOption=RAW&insertDataOption=INSERT_ROWS", body, nil); err != nil {
+			return "", fmt.Errorf("appending rows: %w", err)
+		}
+	}
+
+	verb := "Appended"
+	if mode == "replace" {
+		verb = "Wrote"
+	}
+	return fmt.Sprintf("%s %d rows to sheet tab '%s'", verb, len(t.Rows), tab), nil
+}
+
+func headerRow(t *Table) []interface{} {
+	row := make([]interface{}, len(t.Columns))
+	for i, c := range t.Columns {
+		row[i] = c
+	}
+	return row
+}
+
+type sheets struct {
+	id    string
+	token string
+}
+
+// ensureTab adds the tab to the spreadsheet if it does not exist.
+func (s *sheets) ensureTab(tab string) error {
+	var meta struct {
+		Sheets []struct {
+			Properties struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err := s.do("GET", "?fields=sheets.properties.title", nil, &meta); err != nil {
+		return fmt.Errorf("opening spreadsheet: %w", err)
+	}
+	for _, sh := range meta.Sheets {
+		if sh.Properties.Title == tab {
+			return nil
+		}
+	}
+	req := map[string]interface{}{
+		"requests": []interface{}{
+			map[string]interface{}{"addSheet": map[string]interface{}{"properties": map[string]string{"title": tab}}},
+		},
+	}
+	if err := s.do("POST", ":batchUpdate", req, nil); err != nil {
+		return fmt.Errorf("creating tab %q: %w", tab, err)
+	}
+	return nil
+}
+
+func (s *sheets) do(method, path string, body, out interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, sheetsBaseURL+url.PathEscape(s.id)+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("Sheets API (HTTP %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("Sheets API (HTTP %d)", resp.StatusCode)
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// googleToken exchanges a service account key for an OAuth access token
+// using the JWT bearer grant.
+func googleToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading service account key: %w", err)
+	}
+	var key struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return "", fmt.Errorf("parsing service account key: %w", err)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	signer, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("parsing service account private key: %w", err)
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   key.ClientEmail,
+		"scope": sheetsScope,
+		"aud":   key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(signer)
+	if err != nil {
+		return "", fmt.Errorf("signing service account assertion: %w", err)
+	}
+
+	resp, err := http.PostForm(key.TokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Google token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("parsing Google token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || tok.AccessToken == "" {
+		return "", fmt.Errorf("Google token exchange failed (HTTP %d): %s", resp.StatusCode, tok.Error)
+	}
+	return tok.AccessToken, nil
+}
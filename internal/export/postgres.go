@@ -0,0 +1,119 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// writePostgres upserts t into the table named by the target's table=
+// parameter, creating it if needed. The remaining URI is the connection
+// string. Rows are keyed by t.Key, so re-exporting a period updates it in place.
+func writePostgres(target string, query url.Values, t *Table) (string, error) {
+	table := query.Get("table")
+	if table == "" {
+		return "", fmt.Errorf("postgres export needs a table, e.g. postgres://host/db?table=asa_keywords")
+	}
+	if len(t.Key) == 0 {
+		return "", fmt.Errorf("postgres export needs key columns")
+	}
+	if len(t.Rows) == 0 {
+		// Column types are inferred from the data, so don't create a table yet.
+		return "No rows to export", nil
+	}
+	query.Del("table")
+	dsn, _, _ := strings.Cut(target, "?")
+	if len(query) > 0 {
+		dsn += "?" + query.Encode()
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return "", fmt.Errorf("connecting to postgres: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	ident := pgx.Identifier(strings.Split(table, ".")).Sanitize()
+	if _, err := conn.Exec(ctx, createTableSQL(ident, t)); err != nil {
+		return "", fmt.Errorf("creating table %s: %w", table, err)
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	upsert := upsertSQL(ident, t)
+	batch := &pgx.Batch{}
+	for _, row := range t.Rows {
+		batch.Queue(upsert, row...)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return "", fmt.Errorf("upserting rows: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("committing rows: %w", err)
+	}
+	return fmt.Sprintf("Upserted %d rows into %s", len(t.Rows), table), nil
+}
+
+func createTableSQL(ident string, t *Table) string {
+	defs := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		defs[i] = pgx.Identifier{c}.Sanitize() + " " + columnType(t, i)
+		if slices.Contains(t.Key, c) {
+			defs[i] += " NOT NULL"
+		}
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s, PRIMARY KEY (%s))",
+		ident, strings.Join(defs, ", "), quoteAll(t.Key))
+}
+
+func upsertSQL(ident string, t *Table) string {
+	params := make([]string, len(t.Columns))
+	var updates []string
+	for i, c := range t.Columns {
+		params[i] = fmt.Sprintf("$%d", i+1)
+		if !slices.Contains(t.Key, c) {
+			q := pgx.Identifier{c}.Sanitize()
+			updates = append(updates, q+" = EXCLUDED."+q)
+		}
+	}
+	action := "NOTHING"
+	if len(updates) > 0 {
+		action = "UPDATE SET " + strings.Join(updates, ", ")
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO %s",
+		ident, quoteAll(t.Columns), strings.Join(params, ", "), quoteAll(t.Key), action)
+}
+
+// columnType maps a column to a Postgres type from its name and the Go type
+// of its first value.
+func columnType(t *Table, i int) string {
+	if t.Columns[i] == "date" {
+		return "DATE"
+	}
+	if len(t.Rows) > 0 {
+		switch t.Rows[0][i].(type) {
+		case int, int64:
+			return "BIGINT"
+		case float64:
+			return "DOUBLE PRECISION"
+		}
+	}
+	return "TEXT"
+}
+
+func quoteAll(cols []string) string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		out[i] = pgx.Identifier{c}.Sanitize()
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,130 @@
+// Package forecast projects daily series such as spend and installs with a
+// simple day-of-week seasonal model. Its projections are estimates for
+// budget planning, not predictions from Apple.
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// MinHistory is the fewest days a model is fitted from: two of each weekday.
+const MinHistory = 14
+
+// Z80 is the normal quantile of an 80% confidence band.
+const Z80 = 1.2816
+
+// Model is a series fitted as a linear trend times a weekday factor.
+type Model struct {
+	// Level is the deseasonalized value on the last history day and Trend
+	// its change per day.
+	Level float64
+	Trend float64
+	// Weekday is each weekday's factor (Sunday first); they average 1.
+	Weekday [7]float64
+	// Sigma is the standard deviation of the history's residuals.
+	Sigma float64
+
+	days int
+	last time.Time
+}
+
+// Estimate is a projected value with its confidence band.
+type Estimate struct {
+	Date  time.Time
+	Value float64
+	Low   float64
+	High  float64
+}
+
+// Fit fits a model to daily values, values[i] being the value on start plus
+// i days. Weekdays that never had a value get a factor of 0.
+func Fit(values []float64, start time.Time) (*Model, error) {
+	n := len(values)
+	if n < MinHistory {
+		return nil, fmt.Errorf("need at least %d days of history, have %d", MinHistory, n)
+	}
+	m := &Model{days: n, last: start.AddDate(0, 0, n-1)}
+
+	var mean float64
+	var sums [7]float64
+	var counts [7]int
+	for i, v := range values {
+		w := start.AddDate(0, 0, i).Weekday()
+		sums[w] += v
+		counts[w]++
+		mean += v
+	}
+	mean /= float64(n)
+	if mean == 0 {
+		return m, nil
+	}
+	for w := range m.Weekday {
+		if counts[w] > 0 {
+			m.Weekday[w] = sums[w] / float64(counts[w]) / mean
+		}
+	}
+
+	// Least squares line through the deseasonalized values
+	var sx, sy, sxx, sxy, k float64
+	for i, v := range values {
+		f := m.Weekday[start.AddDate(0, 0, i).Weekday()]
+		if f == 0 {
+			continue
+		}
+		x, y := float64(i), v/f
+		sx, sy, sxx, sxy, k = sx+x, sy+y, sxx+x*x, sxy+x*y, k+1
+	}
+	intercept, slope := sy/k, 0.0
+	if d := k*sxx - sx*sx; d != 0 {
+		slope = (k*sxy - sx*sy) / d
+		intercept = (sy - slope*sx) / k
+	}
+	m.Trend = slope
+	m.Level = intercept + slope*float64(n-1)
+
+	var ss float64
+	for i, v := range values {
+		r := v - m.fitted(i-(n-1), start.AddDate(0, 0, i).Weekday())
+		ss += r * r
+	}
+	if n > 2 {
+		m.Sigma = math.Sqrt(ss / float64(n-2))
+	}
+	return m, nil
+}
+
+// fitted is the model's value h days after the last history day.
+func (m *Model) fitted(h int, w time.Weekday) float64 {
+	return math.Max(0, m.Level+m.Trend*float64(h)) * m.Weekday[w]
+}
+
+// Project estimates the days after the history, with bands z standard
+// deviations wide that widen with the distance from the history.
+func (m *Model) Project(days int, z float64) []Estimate {
+	out := make([]Estimate, days)
+	for h := 1; h <= days; h++ {
+		date := m.last.AddDate(0, 0, h)
+		v := m.fitted(h, date.Weekday())
+		spread := z * m.Sigma * math.Sqrt(1+float64(h)/float64(m.days))
+		out[h-1] = Estimate{Date: date, Value: v, Low: math.Max(0, v-spread), High: v + spread}
+	}
+	return out
+}
+
+// Sum totals estimates. The band combines the daily ones as independent
+// errors, so it is narrower than the sum of the daily bands.
+func Sum(estimates []Estimate) Estimate {
+	var total Estimate
+	var variance float64
+	for _, e := range estimates {
+		total.Value += e.Value
+		half := e.High - e.Value
+		variance += half * half
+	}
+	spread := math.Sqrt(variance)
+	total.Low = math.Max(0, total.Value-spread)
+	total.High = total.Value + spread
+	return total
+}
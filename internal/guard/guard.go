@@ -0,0 +1,68 @@
+// Package guard keeps the state of the daily spend guard per profile, so a
+// restarted guard knows which campaigns it already tripped on and which it
+// paused.
+package guard
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Trip records a campaign reaching its cap on a given day.
+type Trip struct {
+	CampaignID int64      `json:"campaignId"`
+	Date       string     `json:"date"` // YYYY-MM-DD
+	Spend      float64    `json:"spend"`
+	Cap        float64    `json:"cap"`
+	Action     string     `json:"action"`
+	TrippedAt  time.Time  `json:"trippedAt"`
+	PausedAt   *time.Time `json:"pausedAt,omitempty"`
+}
+
+// Store holds the latest trip per campaign.
+type Store struct {
+	Trips map[string]*Trip `json:"trips"`
+
+	path string
+}
+
+// Path returns the guard state file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("guard", profile)
+}
+
+// Load reads the guard state for a profile. A missing file yields an empty
+// store.
+func Load(profile string) (*Store, error) {
+	s := &Store{path: Path(profile)}
+	if err := config.LoadJSON(s.path, "guard state", s); err != nil {
+		return nil, err
+	}
+	if s.Trips == nil {
+		s.Trips = map[string]*Trip{}
+	}
+	return s, nil
+}
+
+// Get returns the trip recorded for a campaign, or nil.
+func (s *Store) Get(campaignID int64) *Trip {
+	return s.Trips[strconv.FormatInt(campaignID, 10)]
+}
+
+// Set records a trip, replacing any earlier one for the campaign.
+func (s *Store) Set(t *Trip) {
+	s.Trips[strconv.FormatInt(t.CampaignID, 10)] = t
+}
+
+// Clear forgets a campaign's trip.
+func (s *Store) Clear(campaignID int64) {
+	delete(s.Trips, strconv.FormatInt(campaignID, 10))
+}
+
+// Save writes the guard state. The file is replaced atomically, so a guard
+// stopped mid-write keeps its previous state.
+func (s *Store) Save() error {
+	return config.SaveJSON(s.path, "guard state", s)
+}
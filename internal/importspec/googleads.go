@@ -0,0 +1,118 @@
+package importspec
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// FromGoogleAds translates a Google Ads Editor export (CSV or its UTF-16
+// tab-separated default) or a Google Ads keyword download. Campaign rows
+// carry the daily budget, ad group rows the default bid (Max CPC), and
+// keyword rows the keywords, with the match type from the Criterion Type
+// column or the keyword's own syntax ([exact], "phrase", +broad). Apple has
+// no phrase match, so phrase keywords become broad. Negative criterion types
+// become negative keywords of the ad group, or of the campaign for
+// "Campaign Negative ..." types and rows without an ad group. Removed rows are
+// skipped.
+func FromGoogleAds(r io.Reader) (*Spec, Warnings, error) {
+	s, err := readSheet(r, "Campaign")
+	if err != nil {
+		return nil, nil, err
+	}
+	var b builder
+	var w Warnings
+	phrase := 0
+	for i, row := range s.rows {
+		line := s.first + i
+		name := s.get(row, "Campaign")
+		if name == "" {
+			continue
+		}
+		if _, removed := status(s.get(row, "Campaign Status", "Campaign state")); removed {
+			continue
+		}
+		c := b.campaign(name)
+		agName := s.get(row, "Ad Group", "Ad group")
+		text := s.get(row, "Keyword", "Search keyword")
+		rowStatus := s.get(row, "Status", "Keyword status", "Keyword state")
+
+		if agName == "" && text == "" {
+			if st, _ := status(s.get(row, "Campaign Status", "Campaign state", "Status")); st != "" {
+				c.Status = st
+			}
+			if v := amount(s.get(row, "Budget", "Campaign Daily Budget", "Daily budget")); v != "" {
+				c.DailyBudget = v
+			}
+			continue
+		}
+
+		var ag *AdGroup
+		if agName != "" {
+			agStatus := s.get(row, "Ad Group Status", "Ad group status", "Ad group state")
+			if text == "" && agStatus == "" {
+				agStatus = rowStatus
+			}
+			if _, removed := status(agStatus); removed {
+				continue
+			}
+			ag = b.adGroup(c, agName)
+			if st, _ := status(agStatus); st != "" {
+				ag.Status = st
+			}
+			if v := amount(s.get(row, "Default max. CPC", "Default Max CPC")); v != "" {
+				ag.DefaultBid = v
+			}
+			if text == "" {
+				if v := amount(s.get(row, "Max CPC", "Max. CPC")); v != "" {
+					ag.DefaultBid = v
+				}
+				continue
+			}
+		}
+
+		criterion := strings.ToLower(s.get(row, "Criterion Type", "Match type", "Keyword match type"))
+		negative := strings.Contains(criterion, "negative")
+		campaignNegative := strings.HasPrefix(criterion, "campaign negative")
+		if strings.HasPrefix(text, "-") {
+			negative, text = true, text[1:]
+		}
+		k := Keyword{MatchType: models.MatchBroad}
+		switch {
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			k.MatchType, text = models.MatchExact, text[1:len(text)-1]
+		case strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`) && len(text) > 1:
+			criterion, text = "phrase", text[1:len(text)-1]
+		case strings.Contains(criterion, "exact"):
+			k.MatchType = models.MatchExact
+		}
+		if strings.Contains(criterion, "phrase") {
+			phrase++
+		}
+		k.Text = strings.ReplaceAll(text, "+", "")
+
+		kwStatus, removed := keywordStatus(rowStatus)
+		if removed {
+			continue
+		}
+		switch {
+		case negative && (campaignNegative || ag == nil):
+			addKeyword(&c.Negatives, k, line, &w)
+		case negative:
+			addKeyword(&ag.Negatives, k, line, &w)
+		case ag == nil:
+			w.add(line, "skipped keyword %q: no ad group", k.Text)
+		default:
+			k.Bid = amount(s.get(row, "Max CPC", "Max. CPC"))
+			k.Status = kwStatus
+			addKeyword(&ag.Keywords, k, line, &w)
+		}
+	}
+	if phrase > 0 {
+		w = append(w, fmt.Sprintf("%d phrase match keyword(s) imported as broad match (Apple has no phrase match)", phrase))
+	}
+	spec, err := b.result()
+	return spec, w, err
+}
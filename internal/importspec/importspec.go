@@ -0,0 +1,183 @@
+// Package importspec reads and writes import specs, which describe campaigns
+// with their ad groups, keywords, and negative keywords as YAML, and
+// translates other tools' bulk sheets into them.
+package importspec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"go.yaml.in/yaml/v3"
+)
+
+// Spec is the contents of an import spec.
+type Spec struct {
+	Campaigns []Campaign `yaml:"campaigns"`
+}
+
+// Campaign is a campaign to create, or to update when one of the same name
+// exists. AppID and Countries are only needed to create it.
+type Campaign struct {
+	Name        string        `yaml:"name"`
+	AppID       int64         `yaml:"app_id,omitempty"`
+	Countries   []string      `yaml:"countries,omitempty"`
+	Status      models.Status `yaml:"status,omitempty"`
+	DailyBudget string        `yaml:"daily_budget,omitempty"`
+	AdGroups    []AdGroup     `yaml:"ad_groups,omitempty"`
+	Negatives   []Keyword     `yaml:"negative_keywords,omitempty"`
+}
+
+// AdGroup is an ad group, matched by name within its campaign.
+type AdGroup struct {
+	Name       string        `yaml:"name"`
+	Status     models.Status `yaml:"status,omitempty"`
+	DefaultBid string        `yaml:"default_bid,omitempty"`
+	Keywords   []Keyword     `yaml:"keywords,omitempty"`
+	Negatives  []Keyword     `yaml:"negative_keywords,omitempty"`
+}
+
+// Keyword is a targeting or negative keyword, matched by text and match
+// type. Bid and Status apply to targeting keywords only.
+type Keyword struct {
+	Text      string               `yaml:"text"`
+	MatchType models.MatchType     `yaml:"match_type,omitempty"`
+	Bid       string               `yaml:"bid,omitempty"`
+	Status    models.KeywordStatus `yaml:"status,omitempty"`
+}
+
+// Load reads and validates a spec file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading spec: %w", err)
+	}
+	var s Spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing spec %s: %w", path, err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("spec %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Write saves the spec as YAML.
+func (s *Spec) Write(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encoding spec: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing spec: %w", err)
+	}
+	return nil
+}
+
+// Validate normalizes enum values and keyword text, and checks that names
+// are present and not repeated.
+func (s *Spec) Validate() error {
+	if len(s.Campaigns) == 0 {
+		return fmt.Errorf("no campaigns")
+	}
+	campaigns := map[string]bool{}
+	for i := range s.Campaigns {
+		c := &s.Campaigns[i]
+		if c.Name == "" {
+			return fmt.Errorf("campaign %d has no name", i+1)
+		}
+		if campaigns[c.Name] {
+			return fmt.Errorf("campaign %q is listed twice", c.Name)
+		}
+		campaigns[c.Name] = true
+		var err error
+		if c.Status, err = models.ParseStatus(string(c.Status)); err != nil {
+			return fmt.Errorf("campaign %q: %w", c.Name, err)
+		}
+		for j := range c.Countries {
+			c.Countries[j] = strings.ToUpper(strings.TrimSpace(c.Countries[j]))
+		}
+		if err := normalizeKeywords(c.Negatives); err != nil {
+			return fmt.Errorf("campaign %q: %w", c.Name, err)
+		}
+
+		adGroups := map[string]bool{}
+		for j := range c.AdGroups {
+			ag := &c.AdGroups[j]
+			if ag.Name == "" {
+				return fmt.Errorf("campaign %q: ad group %d has no name", c.Name, j+1)
+			}
+			if adGroups[ag.Name] {
+				return fmt.Errorf("campaign %q: ad group %q is listed twice", c.Name, ag.Name)
+			}
+			adGroups[ag.Name] = true
+			if ag.Status, err = models.ParseStatus(string(ag.Status)); err != nil {
+				return fmt.Errorf("ad group %q: %w", ag.Name, err)
+			}
+			if err := normalizeKeywords(ag.Keywords); err != nil {
+				return fmt.Errorf("ad group %q: %w", ag.Name, err)
+			}
+			if err := normalizeKeywords(ag.Negatives); err != nil {
+				return fmt.Errorf("ad group %q: %w", ag.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeKeywords(keywords []Keyword) error {
+	for i := range keywords {
+		k := &keywords[i]
+		k.Text = kwplan.Normalize(k.Text)
+		if err := kwplan.Validate(k.Text); err != nil {
+			return fmt.Errorf("keyword %q: %w", k.Text, err)
+		}
+		var err error
+		if k.MatchType, err = models.ParseMatchType(string(k.MatchType)); err != nil {
+			return fmt.Errorf("keyword %q: %w", k.Text, err)
+		}
+		if k.MatchType == "" {
+			k.MatchType = models.MatchBroad
+		}
+		if k.Status, err = models.ParseKeywordStatus(string(k.Status)); err != nil {
+			return fmt.Errorf("keyword %q: %w", k.Text, err)
+		}
+	}
+	return nil
+}
+
+// builder assembles a spec from sheet rows, keeping campaigns and ad groups
+// in the order they first appear.
+type builder struct {
+	spec Spec
+}
+
+func (b *builder) campaign(name string) *Campaign {
+	for i := range b.spec.Campaigns {
+		if b.spec.Campaigns[i].Name == name {
+			return &b.spec.Campaigns[i]
+		}
+	}
+	b.spec.Campaigns = append(b.spec.Campaigns, Campaign{Name: name})
+	return &b.spec.Campaigns[len(b.spec.Campaigns)-1]
+}
+
+func (b *builder) adGroup(c *Campaign, name string) *AdGroup {
+	for i := range c.AdGroups {
+		if c.AdGroups[i].Name == name {
+			return &c.AdGroups[i]
+		}
+	}
+	c.AdGroups = append(c.AdGroups, AdGroup{Name: name})
+	return &c.AdGroups[len(c.AdGroups)-1]
+}
+
+// result validates the assembled spec.
+func (b *builder) result() (*Spec, error) {
+	if err := b.spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &b.spec, nil
+}
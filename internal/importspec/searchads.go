@@ -0,0 +1,106 @@
+package importspec
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// FromSearchAdsUI translates a keyword or bulk sheet downloaded from the
+// Search Ads web UI. Each row names its campaign and, optionally, its ad
+// group; rows with a keyword add it to the ad group, or with a negative
+// keyword (or a "Negative ..." match type) add a negative keyword to the ad
+// group or, without one, to the campaign. Campaign and ad group columns
+// (status, daily budget, countries, app ID, default bid) fill in the
+// entities themselves.
+func FromSearchAdsUI(r io.Reader) (*Spec, Warnings, error) {
+	s, err := readSheet(r, "Campaign Name", "Campaign")
+	if err != nil {
+		return nil, nil, err
+	}
+	var b builder
+	var w Warnings
+	for i, row := range s.rows {
+		line := s.first + i
+		name := s.get(row, "Campaign Name", "Campaign")
+		if name == "" {
+			continue
+		}
+		if _, removed := status(s.get(row, "Campaign Status")); removed {
+			w.add(line, "skipped row of removed campaign %q", name)
+			continue
+		}
+		c := b.campaign(name)
+		if st, _ := status(s.get(row, "Campaign Status")); st != "" {
+			c.Status = st
+		}
+		if v := amount(s.get(row, "Daily Budget", "Daily Cap", "Campaign Daily Budget")); v != "" {
+			c.DailyBudget = v
+		}
+		if v := s.get(row, "Countries or Regions", "Countries", "Country or Region", "Storefronts"); v != "" && len(c.Countries) == 0 {
+			c.Countries = splitList(v)
+		}
+		if v := s.get(row, "App ID", "Adam ID", "App Apple ID"); v != "" && c.AppID == 0 {
+			if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+				c.AppID = id
+			} else {
+				w.add(line, "ignored app ID %q: not a number", v)
+			}
+		}
+
+		var ag *AdGroup
+		if agName := s.get(row, "Ad Group Name", "Ad Group"); agName != "" {
+			if _, removed := status(s.get(row, "Ad Group Status")); removed {
+				w.add(line, "skipped row of removed ad group %q", agName)
+				continue
+			}
+			ag = b.adGroup(c, agName)
+			if st, _ := status(s.get(row, "Ad Group Status")); st != "" {
+				ag.Status = st
+			}
+			if v := amount(s.get(row, "Default Max CPT Bid", "Default CPT Bid", "Default Bid", "Ad Group Default Bid")); v != "" {
+				ag.DefaultBid = v
+			}
+		}
+
+		matchType := strings.ToLower(s.get(row, "Match Type"))
+		negative := strings.HasPrefix(matchType, "negative")
+		text := s.get(row, "Keyword", "Keyword Text")
+		if neg := s.get(row, "Negative Keyword", "Negative Keyword Text"); neg != "" {
+			text, negative = neg, true
+		}
+		if text == "" {
+			continue
+		}
+		k := Keyword{Text: text, MatchType: models.MatchBroad}
+		if strings.Contains(matchType, "exact") {
+			k.MatchType = models.MatchExact
+		}
+		kwStatus, removed := keywordStatus(s.get(row, "Keyword Status", "Status"))
+		if removed {
+			w.add(line, "skipped removed keyword %q", text)
+			continue
+		}
+		switch {
+		case negative && ag != nil:
+			addKeyword(&ag.Negatives, k, line, &w)
+		case negative:
+			addKeyword(&c.Negatives, k, line, &w)
+		case ag == nil:
+			w.add(line, "skipped keyword %q: no ad group", text)
+		default:
+			k.Bid = amount(s.get(row, "Max CPT Bid", "Max CPT", "Bid", "Keyword Bid", "CPT Bid"))
+			k.Status = kwStatus
+			addKeyword(&ag.Keywords, k, line, &w)
+		}
+	}
+	spec, err := b.result()
+	return spec, w, err
+}
+
+// splitList splits a comma-, semicolon-, or space-separated list.
+func splitList(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+}
@@ -0,0 +1,170 @@
+package importspec
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// Warnings lists what a translation left out or changed, one line each.
+type Warnings []string
+
+func (w *Warnings) add(line int, format string, args ...any) {
+	*w = append(*w, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+}
+
+// sheet is a bulk sheet with its columns looked up by normalized header.
+type sheet struct {
+	columns map[string]int
+	rows    [][]string
+	first   int // line number of rows[0]
+}
+
+// readSheet reads a comma- or tab-separated sheet in UTF-8 or UTF-16 (as
+// Google Ads Editor exports), skipping any title lines before the header.
+// The header is the first line holding one of the required columns.
+func readSheet(r io.Reader, required ...string) (*sheet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading sheet: %w", err)
+	}
+	data = decodeUTF16(data)
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte("\t")) > bytes.Count(firstLine, []byte(",")) {
+		cr.Comma = '\t'
+	}
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing sheet: %w", err)
+	}
+
+	for i, rec := range records {
+		columns := map[string]int{}
+		for j, h := range rec {
+			if key := headerKey(h); key != "" {
+				if _, ok := columns[key]; !ok {
+					columns[key] = j
+				}
+			}
+		}
+		for _, name := range required {
+			if _, ok := columns[headerKey(name)]; ok {
+				return &sheet{columns: columns, rows: records[i+1:], first: i + 2}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no header row with a %s column", strings.Join(required, " or "))
+}
+
+// decodeUTF16 converts UTF-16 text with a byte order mark to UTF-8.
+func decodeUTF16(data []byte) []byte {
+	if len(data) < 2 {
+		return data
+	}
+	var order func(b []byte) uint16
+	switch {
+	case data[0] == 0xFF && data[1] == 0xFE:
+		order = func(b []byte) uint16 { return uint16(b[0]) | uint16(b[1])<<8 }
+	case data[0] == 0xFE && data[1] == 0xFF:
+		order = func(b []byte) uint16 { return uint16(b[0])<<8 | uint16(b[1]) }
+	default:
+		return data
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 2; i+1 < len(data); i += 2 {
+		units = append(units, order(data[i:i+2]))
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+// headerKey lowercases a header and drops everything but letters and digits,
+// so "Ad Group Name", "ad_group_name", and "Ad group name" are the same.
+func headerKey(h string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(h) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// get returns a row's value in the first of names that the sheet has.
+func (s *sheet) get(row []string, names ...string) string {
+	for _, name := range names {
+		if j, ok := s.columns[headerKey(name)]; ok && j < len(row) {
+			return strings.TrimSpace(row[j])
+		}
+	}
+	return ""
+}
+
+// amount extracts a money amount from values like "1.50", "$1.50",
+// "USD 1.50", or "1,50".
+func amount(v string) string {
+	var b strings.Builder
+	for _, r := range v {
+		if unicode.IsDigit(r) || r == '.' || r == ',' {
+			b.WriteRune(r)
+		}
+	}
+	s := b.String()
+	if !strings.Contains(s, ".") && strings.Count(s, ",") == 1 && len(s)-strings.Index(s, ",") <= 3 {
+		return strings.Replace(s, ",", ".", 1)
+	}
+	return strings.ReplaceAll(s, ",", "")
+}
+
+// status maps a sheet's status to ENABLED or PAUSED. removed reports
+// statuses of deleted entities, whose rows are skipped.
+func status(v string) (s models.Status, removed bool) {
+	switch strings.ToLower(v) {
+	case "enabled", "active", "running", "on":
+		return models.StatusEnabled, false
+	case "paused", "on hold", "off":
+		return models.StatusPaused, false
+	case "removed", "deleted":
+		return "", true
+	}
+	return "", false
+}
+
+// keywordStatus is status for targeting keywords.
+func keywordStatus(v string) (models.KeywordStatus, bool) {
+	s, removed := status(v)
+	switch s {
+	case models.StatusEnabled:
+		return models.KeywordActive, removed
+	case models.StatusPaused:
+		return models.KeywordPaused, removed
+	}
+	return "", removed
+}
+
+// addKeyword appends a keyword unless its text is invalid or it is already
+// listed, warning about invalid text.
+func addKeyword(list *[]Keyword, k Keyword, line int, w *Warnings) {
+	k.Text = kwplan.Normalize(k.Text)
+	if err := kwplan.Validate(k.Text); err != nil {
+		w.add(line, "skipped keyword %q: %v", k.Text, err)
+		return
+	}
+	for _, existing := range *list {
+		if existing.Text == k.Text && existing.MatchType == k.MatchType {
+			return
+		}
+	}
+	*list = append(*list, k)
+}
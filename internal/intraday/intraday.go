@@ -0,0 +1,193 @@
+// Package intraday is a local warehouse of hourly campaign data. Completed
+// days are kept per profile so intraday curves (how much of a day's spend and
+// installs has usually arrived by each hour) can be built without refetching
+// Apple's hourly reports, and used to project today's end-of-day totals.
+package intraday
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Retention is how long stored days are kept; Apple serves hourly data for
+// the last 30 days.
+const Retention = 35 * 24 * time.Hour
+
+const dateFormat = "2006-01-02"
+
+// Day is one campaign's spend and installs per hour of a day.
+type Day struct {
+	Spend    [24]float64 `json:"spend"`
+	Installs [24]int64   `json:"installs"`
+}
+
+// Total returns the day's spend and installs.
+func (d *Day) Total() (float64, int64) {
+	var spend float64
+	var installs int64
+	for h := range d.Spend {
+		spend += d.Spend[h]
+		installs += d.Installs[h]
+	}
+	return spend, installs
+}
+
+// Store is a profile's stored days: campaign days by date. A date that is
+// present was fetched, even if no campaign spent on it.
+type Store struct {
+	Days map[string]map[int64]*Day `json:"days"`
+
+	path string
+}
+
+// Path returns the warehouse file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("intraday", profile)
+}
+
+// Load reads a profile's warehouse; a missing file is an empty one.
+func Load(profile string) (*Store, error) {
+	s := &Store{Days: map[string]map[int64]*Day{}, path: Path(profile)}
+	if err := config.LoadJSON(s.path, "intraday history", s); err != nil {
+		return nil, err
+	}
+	if s.Days == nil {
+		s.Days = map[string]map[int64]*Day{}
+	}
+	return s, nil
+}
+
+// Has reports whether a date has been stored.
+func (s *Store) Has(date time.Time) bool {
+	_, ok := s.Days[date.Format(dateFormat)]
+	return ok
+}
+
+// DayTotal returns a campaign's spend and installs on a stored date; ok is
+// false when the date hasn't been stored.
+func (s *Store) DayTotal(campaignID int64, date time.Time) (spend float64, installs int64, ok bool) {
+	campaigns, ok := s.Days[date.Format(dateFormat)]
+	if !ok {
+		return 0, 0, false
+	}
+	if d := campaigns[campaignID]; d != nil {
+		spend, installs = d.Total()
+	}
+	return spend, installs, true
+}
+
+// Put stores a completed date's campaign days.
+func (s *Store) Put(date time.Time, days map[int64]*Day) {
+	if days == nil {
+		days = map[int64]*Day{}
+	}
+	s.Days[date.Format(dateFormat)] = days
+}
+
+// Save drops days older than Retention and writes the warehouse.
+func (s *Store) Save(now time.Time) error {
+	cutoff := now.Add(-Retention).Format(dateFormat)
+	for date := range s.Days {
+		if date < cutoff {
+			delete(s.Days, date)
+		}
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encoding intraday history: %w", err)
+	}
+	if err := config.WriteFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("writing intraday history: %w", err)
+	}
+	return nil
+}
+
+// Curve is the average share of a day's total that has arrived by the end of
+// each hour; the last hour is 1.
+type Curve struct {
+	Spend    [24]float64 `json:"spend"`
+	Installs [24]float64 `json:"installs"`
+	// Days is how many stored days the curve was built from; 0 means it is
+	// the even pace of 1/24 per hour.
+	Days int `json:"days"`
+}
+
+// Even is the curve of a day that spends evenly.
+func Even() Curve {
+	var c Curve
+	for h := range c.Spend {
+		c.Spend[h] = float64(h+1) / 24
+		c.Installs[h] = c.Spend[h]
+	}
+	return c
+}
+
+// Curve builds a campaign's curve from the stored days in [from, to]. With
+// campaignID 0 it uses every campaign. Days without spend are skipped; with
+// none left it returns Even.
+func (s *Store) Curve(campaignID int64, from, to time.Time) Curve {
+	var spend [24]float64
+	var installs [24]float64
+	days := 0
+	for date, campaigns := range s.Days {
+		if date < from.Format(dateFormat) || date > to.Format(dateFormat) {
+			continue
+		}
+		var day Day
+		for id, d := range campaigns {
+			if campaignID != 0 && id != campaignID {
+				continue
+			}
+			for h := range day.Spend {
+				day.Spend[h] += d.Spend[h]
+				day.Installs[h] += d.Installs[h]
+			}
+		}
+		total, totalInstalls := day.Total()
+		if total <= 0 {
+			continue
+		}
+		days++
+		// Weight each day equally, so one big day doesn't set the shape.
+		for h := range spend {
+			spend[h] += day.Spend[h] / total
+			if totalInstalls > 0 {
+				installs[h] += float64(day.Installs[h]) / float64(totalInstalls)
+			} else {
+				installs[h] += day.Spend[h] / total
+			}
+		}
+	}
+	if days == 0 {
+		return Even()
+	}
+	c := Curve{Days: days}
+	var cs, ci float64
+	for h := range spend {
+		cs += spend[h] / float64(days)
+		ci += installs[h] / float64(days)
+		c.Spend[h], c.Installs[h] = cs, ci
+	}
+	c.Spend[23], c.Installs[23] = 1, 1
+	return c
+}
+
+// minShare is the smallest share of the day a projection divides by; earlier
+// in the day the even pace is used instead, since a near-zero share would
+// blow a few cents up into a huge projection.
+const minShare = 0.05
+
+// Project estimates a day's total from the amount through the end of hour,
+// given the share of the day that has usually arrived by then.
+func Project(soFar, share float64, hour int) float64 {
+	if share < minShare {
+		share = float64(hour+1) / 24
+	}
+	if share <= 0 {
+		return soFar
+	}
+	return soFar / share
+}
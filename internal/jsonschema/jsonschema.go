@@ -0,0 +1,152 @@
+// Package jsonschema generates JSON Schemas (draft 2020-12) from the API
+// model structs, so documents written for --file inputs can be validated and
+// autocompleted by editors and CI.
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect generated schemas declare.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema node.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Enum is implemented by string types that accept a fixed set of values.
+type Enum interface {
+	EnumValues() []string
+}
+
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// Generate returns the schema of v's type. Nested structs become $defs
+// referenced by type name. Fields without omitempty are required, and fields
+// a struct doesn't have are rejected, as the --file decoders do.
+func Generate(v interface{}, title, description string) *Schema {
+	g := &generator{defs: map[string]*Schema{}}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	root := g.object(t)
+	root.Schema = Draft
+	root.Title = title
+	root.Description = description
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+// Partial drops the required fields of s and its definitions, for documents
+// that set only the fields they change.
+func (s *Schema) Partial() *Schema {
+	s.Required = nil
+	for _, p := range s.Properties {
+		p.Partial()
+	}
+	for _, d := range s.Defs {
+		d.Partial()
+	}
+	if s.Items != nil {
+		s.Items.Partial()
+	}
+	if sub, ok := s.AdditionalProperties.(*Schema); ok {
+		sub.Partial()
+	}
+	return s
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		return g.schema(t.Elem())
+	}
+	if t.Implements(enumType) {
+		values := reflect.Zero(t).Interface().(Enum).EnumValues()
+		return &Schema{Type: "string", Enum: append([]string(nil), values...)}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			g.defs[t.Name()] = &Schema{}
+			*g.defs[t.Name()] = *g.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	}
+	// interface{} and anything else: any value
+	return &Schema{}
+}
+
+// object is the schema of a struct's JSON fields. Embedded structs without a
+// JSON name are flattened into it.
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	g.fields(t, s)
+	return s
+}
+
+func (g *generator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, s)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
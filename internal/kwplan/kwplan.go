@@ -0,0 +1,104 @@
+// Package kwplan normalizes, validates, and deduplicates keyword lists
+// before they are sent to the API.
+package kwplan
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength is the longest keyword text the API accepts, in characters.
+const MaxLength = 80
+
+// Statuses of a planned keyword.
+const (
+	StatusAdd       = "add"
+	StatusDuplicate = "duplicate"
+	StatusExisting  = "existing"
+	StatusInvalid   = "invalid"
+)
+
+// Candidate is a keyword to import.
+type Candidate struct {
+	Line      int
+	Text      string
+	MatchType string
+	Bid       string
+}
+
+// Planned is a candidate with its normalized text and import decision.
+type Planned struct {
+	Line      int    `json:"line"`
+	Text      string `json:"text"`
+	MatchType string `json:"matchType"`
+	Bid       string `json:"bid,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Normalize lowercases text and collapses runs of whitespace to one space.
+// Keyword matching is case-insensitive, so "Habit  Tracker" and
+// "habit tracker" are the same keyword.
+func Normalize(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// Validate checks normalized text against the length and character rules.
+// Letters, digits, and marks in any script are allowed, plus spaces and the
+// punctuation - ' & . ,
+func Validate(text string) error {
+	if text == "" {
+		return fmt.Errorf("empty text")
+	}
+	if n := utf8.RuneCountInString(text); n > MaxLength {
+		return fmt.Errorf("%d characters (max %d)", n, MaxLength)
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == ' ' || strings.ContainsRune("-'&.,", r) {
+			continue
+		}
+		return fmt.Errorf("character %q not allowed", r)
+	}
+	return nil
+}
+
+// Key identifies a keyword within an ad group: normalized text plus match type.
+func Key(text, matchType string) string {
+	return Normalize(text) + "|" + strings.ToUpper(matchType)
+}
+
+// Plan normalizes and validates candidates and marks repeats within the list
+// and keywords already live in the ad group (existing, as Key values).
+func Plan(candidates []Candidate, existing map[string]bool) []Planned {
+	seen := map[string]int{}
+	out := make([]Planned, 0, len(candidates))
+	for _, c := range candidates {
+		p := Planned{Line: c.Line, Text: Normalize(c.Text), MatchType: strings.ToUpper(c.MatchType), Bid: c.Bid, Status: StatusAdd}
+		key := Key(p.Text, p.MatchType)
+		switch {
+		case p.MatchType != "BROAD" && p.MatchType != "EXACT":
+			p.Status, p.Reason = StatusInvalid, fmt.Sprintf("match type %q (expected BROAD or EXACT)", c.MatchType)
+		case Validate(p.Text) != nil:
+			p.Status, p.Reason = StatusInvalid, Validate(p.Text).Error()
+		case existing[key]:
+			p.Status, p.Reason = StatusExisting, "already in ad group"
+		case seen[key] > 0:
+			p.Status, p.Reason = StatusDuplicate, fmt.Sprintf("same as line %d", seen[key])
+		default:
+			seen[key] = c.Line
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// Count tallies planned keywords by status.
+func Count(planned []Planned) map[string]int {
+	counts := map[string]int{}
+	for _, p := range planned {
+		counts[p.Status]++
+	}
+	return counts
+}
@@ -0,0 +1,139 @@
+// Package localize turns a base keyword list into per-storefront keyword
+// sets from a translation map, using the languages each storefront's users
+// search in.
+package localize
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+	"go.yaml.in/yaml/v3"
+)
+
+// Storefronts are the languages searched in each storefront, most common
+// first. A translation map can override them.
+var Storefronts = map[string][]string{
+	"AE": {"ar", "en"}, "AR": {"es", "en"}, "AT": {"de", "en"}, "AU": {"en"},
+	"BE": {"nl", "fr", "en"}, "BR": {"pt", "en"}, "CA": {"en", "fr"},
+	"CH": {"de", "fr", "it", "en"}, "CL": {"es", "en"}, "CN": {"zh-Hans", "en"},
+	"CO": {"es", "en"}, "CZ": {"cs", "en"}, "DE": {"de", "en"}, "DK": {"da", "en"},
+	"EC": {"es", "en"}, "EG": {"ar", "en"}, "ES": {"es", "en"}, "FI": {"fi", "en"},
+	"FR": {"fr", "en"}, "GB": {"en"}, "GR": {"el", "en"}, "HK": {"zh-Hant", "en"},
+	"HR": {"hr", "en"}, "HU": {"hu", "en"}, "ID": {"id", "en"}, "IE": {"en"},
+	"IL": {"he", "en"}, "IN": {"en", "hi"}, "IT": {"it", "en"}, "JP": {"ja", "en"},
+	"KR": {"ko", "en"}, "KW": {"ar", "en"}, "MX": {"es", "en"}, "MY": {"ms", "en"},
+	"NL": {"nl", "en"}, "NO": {"nb", "en"}, "NZ": {"en"}, "PE": {"es", "en"},
+	"PH": {"en"}, "PK": {"en"}, "PL": {"pl", "en"}, "PT": {"pt", "en"},
+	"QA": {"ar", "en"}, "RO": {"ro", "en"}, "SA": {"ar", "en"}, "SE": {"sv", "en"},
+	"SG": {"en", "zh-Hans"}, "SK": {"sk", "en"}, "TH": {"th", "en"},
+	"TR": {"tr", "en"}, "TW": {"zh-Hant", "en"}, "UA": {"uk", "en"},
+	"US": {"en", "es"}, "VN": {"vi", "en"}, "ZA": {"en"},
+}
+
+// Map is a translation map file.
+type Map struct {
+	// Storefronts overrides the built-in languages of storefronts.
+	Storefronts map[string][]string `yaml:"storefronts,omitempty"`
+	// Keywords maps each base keyword to its translations by language
+	// ("de", "zh-Hans") or by language in one storefront ("es-MX"), which
+	// replaces the language's translations there.
+	Keywords map[string]map[string]Texts `yaml:"keywords"`
+}
+
+// Texts is one or more translations; in YAML, a string or a list.
+type Texts []string
+
+func (t *Texts) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = Texts{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Load reads a translation map. Base keywords are normalized the way
+// keyword imports are, so they match the base file however it is cased.
+func Load(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading translation map: %w", err)
+	}
+	var m Map
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing translation map %s: %w", path, err)
+	}
+	keywords := make(map[string]map[string]Texts, len(m.Keywords))
+	for base, byLang := range m.Keywords {
+		keywords[kwplan.Normalize(base)] = byLang
+	}
+	m.Keywords = keywords
+	storefronts := make(map[string][]string, len(m.Storefronts))
+	for country, langs := range m.Storefronts {
+		storefronts[strings.ToUpper(country)] = langs
+	}
+	m.Storefronts = storefronts
+	return &m, nil
+}
+
+// Languages returns the languages searched in a storefront, or nil if it is
+// unknown.
+func (m *Map) Languages(country string) []string {
+	country = strings.ToUpper(country)
+	if langs, ok := m.Storefronts[country]; ok {
+		return langs
+	}
+	return Storefronts[country]
+}
+
+// Variant is a keyword to target in a storefront.
+type Variant struct {
+	Text     string
+	Language string
+}
+
+// Variants returns the keywords to target in a storefront for a base keyword
+// written in baseLanguage: the base itself where baseLanguage is searched,
+// and its translations into the storefront's other languages. Missing lists
+// the languages without a translation.
+func (m *Map) Variants(base, country, baseLanguage string) (variants []Variant, missing []string) {
+	country = strings.ToUpper(country)
+	byLang := m.Keywords[kwplan.Normalize(base)]
+	for _, lang := range m.Languages(country) {
+		if strings.EqualFold(lang, baseLanguage) {
+			variants = append(variants, Variant{Text: base, Language: lang})
+			continue
+		}
+		texts := lookup(byLang, lang+"-"+country)
+		if texts == nil {
+			texts = lookup(byLang, lang)
+		}
+		if len(texts) == 0 {
+			missing = append(missing, lang)
+			continue
+		}
+		for _, t := range texts {
+			variants = append(variants, Variant{Text: t, Language: lang})
+		}
+	}
+	return variants, missing
+}
+
+// lookup finds a language's translations, ignoring case.
+func lookup(byLang map[string]Texts, lang string) Texts {
+	if texts, ok := byLang[lang]; ok {
+		return texts
+	}
+	for l, texts := range byLang {
+		if strings.EqualFold(l, lang) {
+			return texts
+		}
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package modelgen
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Change kinds.
+const (
+	Added       = "added"        // field in the spec, new to the struct
+	Removed     = "removed"      // field in the struct, gone from the spec
+	TypeChanged = "type-changed" // the spec's type differs; the struct's is kept
+	NewType     = "new-type"     // struct added with --add
+	NoSchema    = "no-schema"    // struct's schema isn't in the spec; left as is
+)
+
+// Change is one difference between the spec and the generated structs.
+type Change struct {
+	Type   string `json:"type"`
+	Field  string `json:"field,omitempty"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Regenerate merges the spec into the generated structs, plus a new struct for
+// each entry of add (Go type name to schema name), and returns the new
+// structs and what changed.
+func Regenerate(spec *Spec, models *Models, add map[string]string) ([]Struct, []Change, error) {
+	structs := append([]Struct(nil), models.Generated...)
+	known := map[string]bool{}
+	for name := range models.Types {
+		known[name] = true
+	}
+	for _, s := range structs {
+		known[s.Name] = true
+	}
+	names := make([]string, 0, len(add))
+	for name := range add {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema := add[name]
+		if known[name] {
+			return nil, nil, fmt.Errorf("type %s already exists in %s", name, models.Dir)
+		}
+		known[name] = true
+		structs = append(structs, Struct{Name: name, Schema: schema, Doc: fmt.Sprintf("%s is an API object.\n", name)})
+	}
+	// Schema names of known types, for resolving $refs to Go types.
+	bySchema := map[string]string{}
+	for name := range known {
+		bySchema[name] = name
+	}
+	for _, s := range structs {
+		bySchema[s.Schema] = s.Name
+	}
+
+	var changes []Change
+	for i, s := range structs {
+		sc := spec.Schema(s.Schema)
+		if sc == nil {
+			changes = append(changes, Change{Type: s.Name, Kind: NoSchema, Detail: s.Schema})
+			continue
+		}
+		if _, isNew := add[s.Name]; isNew {
+			if d := strings.TrimSpace(sc.Description); d != "" {
+				s.Doc += "\n" + d + "\n"
+			}
+			changes = append(changes, Change{Type: s.Name, Kind: NewType, Detail: s.Schema})
+		}
+		merged, c := merge(spec, s, sc, bySchema, models.StringTypes)
+		structs[i] = merged
+		changes = append(changes, c...)
+	}
+	return structs, changes, nil
+}
+
+// merge brings one struct in line with its schema.
+func merge(spec *Spec, s Struct, sc *Schema, bySchema map[string]string, stringTypes map[string]bool) (Struct, []Change) {
+	props, required := spec.properties(sc)
+	inSpec := map[string]*Schema{}
+	for _, p := range props {
+		inSpec[p.Name] = p.Schema
+	}
+
+	var changes []Change
+	out := s
+	out.Fields = nil
+	have := map[string]bool{}
+	for _, f := range s.Fields {
+		name := f.JSONName()
+		if name == "" {
+			out.Fields = append(out.Fields, f)
+			continue
+		}
+		p, ok := inSpec[name]
+		if !ok {
+			changes = append(changes, Change{Type: s.Name, Field: name, Kind: Removed, Detail: f.Type})
+			continue
+		}
+		have[name] = true
+		if t := goType(spec, p, bySchema); !compatible(f.Type, t, stringTypes) {
+			changes = append(changes, Change{Type: s.Name, Field: name, Kind: TypeChanged, Detail: fmt.Sprintf("spec %s, kept %s", t, f.Type)})
+		}
+		out.Fields = append(out.Fields, f)
+	}
+	for _, p := range props {
+		if have[p.Name] {
+			continue
+		}
+		have[p.Name] = true
+		f := Field{Name: goName(p.Name), Type: goType(spec, p.Schema, bySchema), Comment: enumComment(spec, p.Schema)}
+		f.Tag = fmt.Sprintf(`json:"%s,omitempty"`, p.Name)
+		if required[p.Name] {
+			f.Tag = fmt.Sprintf(`json:"%s"`, p.Name)
+		}
+		out.Fields = append(out.Fields, f)
+		changes = append(changes, Change{Type: s.Name, Field: p.Name, Kind: Added, Detail: f.Type})
+	}
+	return out, changes
+}
+
+// compatible reports whether a struct's Go type matches the spec's, allowing
+// for pointers, which the models use to tell unset from zero, and for typed
+// enums in place of strings.
+func compatible(have, spec string, stringTypes map[string]bool) bool {
+	have, spec = strings.TrimPrefix(have, "*"), strings.TrimPrefix(spec, "*")
+	if have == spec {
+		return true
+	}
+	if h, ok := strings.CutPrefix(have, "[]"); ok {
+		if s, ok := strings.CutPrefix(spec, "[]"); ok {
+			return compatible(h, s, stringTypes)
+		}
+	}
+	switch spec {
+	case "string":
+		return stringTypes[have]
+	case "int64":
+		return have == "int" || have == "int32"
+	case "map[string]interface{}":
+		return have == "interface{}"
+	}
+	return false
+}
+
+// goType maps a schema to a Go type. Objects the models don't have become
+// maps.
+func goType(spec *Spec, sc *Schema, bySchema map[string]string) string {
+	if sc == nil {
+		return "interface{}"
+	}
+	if sc.Ref != "" {
+		target, name := spec.resolve(sc)
+		if target != nil && target.Type != "" && target.Type != "object" {
+			return goType(spec, target, bySchema)
+		}
+		if goName, ok := bySchema[name]; ok {
+			return "*" + goName
+		}
+		return "map[string]interface{}"
+	}
+	if len(sc.AllOf) == 1 {
+		return goType(spec, sc.AllOf[0], bySchema)
+	}
+	switch sc.Type {
+	case "string":
+		return "string"
+	case "integer":
+		if sc.Format == "int32" {
+			return "int"
+		}
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + strings.TrimPrefix(goType(spec, sc.Items, bySchema), "*")
+	case "object":
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// enumComment lists a string enum's values, as the hand-written models do.
+func enumComment(spec *Spec, sc *Schema) string {
+	sc, _ = spec.resolve(sc)
+	if sc == nil || len(sc.Enum) == 0 || len(sc.Enum) > 8 {
+		return ""
+	}
+	vals := make([]string, len(sc.Enum))
+	for i, v := range sc.Enum {
+		vals[i] = fmt.Sprint(v)
+	}
+	return strings.Join(vals, ", ")
+}
+
+// initialisms are written in capitals in Go names.
+var initialisms = map[string]bool{"id": true, "url": true, "uri": true, "api": true, "acl": true, "json": true, "loc": true}
+
+// goName turns a JSON name like "adamId" into a Go name like "AdamID".
+func goName(jsonName string) string {
+	var words []string
+	var cur []rune
+	for i, r := range jsonName {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(cur) > 0 {
+				words = append(words, string(cur))
+				cur = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && len(cur) > 0 {
+			words = append(words, string(cur))
+			cur = nil
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		words = append(words, string(cur))
+	}
+	var b strings.Builder
+	for _, w := range words {
+		lower := strings.ToLower(w)
+		if lower == "ids" {
+			b.WriteString("IDs")
+			continue
+		}
+		if initialisms[lower] {
+			b.WriteString(strings.ToUpper(lower))
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "F" + b.String()
+	}
+	return b.String()
+}
+
+// Render formats structs as the generated file.
+func Render(structs []Struct) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("// Code generated by asa-cli dev regen-models; DO NOT EDIT.\n")
+	b.WriteString("// Hand-written model types and helpers go in the package's other files.\n\n")
+	b.WriteString("package models\n")
+	for _, s := range structs {
+		b.WriteString("\n")
+		writeComment(&b, "", s.Doc)
+		if s.Doc != "" {
+			b.WriteString("//\n")
+		}
+		fmt.Fprintf(&b, "// Generated from the %s schema.\n", s.Schema)
+		fmt.Fprintf(&b, "type %s struct {\n", s.Name)
+		for _, f := range s.Fields {
+			writeComment(&b, "\t", f.Doc)
+			b.WriteString("\t")
+			if f.Name != "" {
+				b.WriteString(f.Name + " ")
+			}
+			b.WriteString(f.Type)
+			if f.Tag != "" {
+				b.WriteString(" `" + f.Tag + "`")
+			}
+			if c := strings.TrimSpace(f.Comment); c != "" {
+				b.WriteString(" // " + strings.ReplaceAll(c, "\n", " "))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n")
+	}
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated models: %w", err)
+	}
+	return src, nil
+}
+
+func writeComment(b *strings.Builder, indent, text string) {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			b.WriteString(indent + "//\n")
+			continue
+		}
+		b.WriteString(indent + "// " + line + "\n")
+	}
+}
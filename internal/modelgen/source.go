@@ -0,0 +1,137 @@
+package modelgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GeneratedFile is the file in the models directory that holds generated
+// structs.
+const GeneratedFile = "generated.go"
+
+// Struct is a generated struct.
+type Struct struct {
+	Name   string
+	Schema string
+	Doc    string
+	Fields []Field
+}
+
+// Field is a struct field.
+type Field struct {
+	Name    string
+	Type    string
+	Tag     string // without backquotes
+	Doc     string
+	Comment string
+}
+
+// JSONName returns the field's JSON name, or "" when it isn't encoded.
+func (f Field) JSONName() string {
+	name, _, _ := strings.Cut(reflect.StructTag(f.Tag).Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// schemaLine marks the schema a struct is generated from in its doc comment.
+var schemaLine = regexp.MustCompile(`(?m)^Generated from the (\S+) schema\.\n?`)
+
+// Models is the parsed models directory.
+type Models struct {
+	Dir string
+	// Generated are the structs in the generated file, in order.
+	Generated []Struct
+	// Types are the names of every type in the package.
+	Types map[string]bool
+	// StringTypes are the types defined as string, such as enums.
+	StringTypes map[string]bool
+}
+
+// LoadModels parses the Go files in dir.
+func LoadModels(dir string) (*Models, error) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	m := &Models{Dir: dir, Types: map[string]bool{}, StringTypes: map[string]bool{}}
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		generated := filepath.Base(path) == GeneratedFile
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				m.Types[ts.Name.Name] = true
+				if id, ok := ts.Type.(*ast.Ident); ok && id.Name == "string" {
+					m.StringTypes[ts.Name.Name] = true
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !generated || !ok {
+					continue
+				}
+				doc := gen.Doc
+				if ts.Doc != nil {
+					doc = ts.Doc
+				}
+				m.Generated = append(m.Generated, parseStruct(ts.Name.Name, doc, st))
+			}
+		}
+	}
+	return m, nil
+}
+
+func parseStruct(name string, doc *ast.CommentGroup, st *ast.StructType) Struct {
+	s := Struct{Name: name, Schema: name, Doc: doc.Text()}
+	if m := schemaLine.FindStringSubmatch(s.Doc); m != nil {
+		s.Schema = m[1]
+		s.Doc = strings.TrimRight(schemaLine.ReplaceAllString(s.Doc, ""), "\n")
+		if s.Doc != "" {
+			s.Doc += "\n"
+		}
+	}
+	for _, f := range st.Fields.List {
+		field := Field{Type: types.ExprString(f.Type), Doc: f.Doc.Text(), Comment: f.Comment.Text()}
+		if f.Tag != nil {
+			field.Tag, _ = strconv.Unquote(f.Tag.Value)
+		}
+		if len(f.Names) == 0 {
+			s.Fields = append(s.Fields, field)
+			continue
+		}
+		for _, n := range f.Names {
+			field.Name = n.Name
+			s.Fields = append(s.Fields, field)
+		}
+	}
+	return s
+}
+
+// Write renders structs into the generated file.
+func (m *Models) Write(structs []Struct) error {
+	src, err := Render(structs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.Dir, GeneratedFile), src, 0644)
+}
@@ -0,0 +1,130 @@
+// Package modelgen refreshes the API structs in internal/models from an
+// OpenAPI spec of the Apple Search Ads API.
+//
+// Only the structs in the generated file are touched. Regeneration merges the
+// spec into them: existing fields keep their Go name, type, tag and comments,
+// fields new to the spec are appended, and fields the spec no longer has are
+// dropped. Hand-written types and helpers live in the package's other files.
+package modelgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Spec is the part of an OpenAPI 3 (or Swagger 2) document the generator
+// reads.
+type Spec struct {
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+	Definitions map[string]*Schema `json:"definitions"`
+}
+
+// Schema is an OpenAPI schema object.
+type Schema struct {
+	Ref         string        `json:"$ref"`
+	Type        string        `json:"type"`
+	Format      string        `json:"format"`
+	Description string        `json:"description"`
+	Properties  Properties    `json:"properties"`
+	Required    []string      `json:"required"`
+	Items       *Schema       `json:"items"`
+	Enum        []interface{} `json:"enum"`
+	AllOf       []*Schema     `json:"allOf"`
+}
+
+// Property is a named schema property.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties keeps a schema's properties in spec order.
+type Properties []Property
+
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected property name %v", tok)
+		}
+		var s Schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+		*p = append(*p, Property{Name: name, Schema: &s})
+	}
+	return nil
+}
+
+// Load reads a JSON spec.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading spec: %w", err)
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parsing spec %s: %w", path, err)
+	}
+	if len(spec.schemas()) == 0 {
+		return nil, fmt.Errorf("spec %s has no schemas", path)
+	}
+	return &spec, nil
+}
+
+func (s *Spec) schemas() map[string]*Schema {
+	if len(s.Components.Schemas) > 0 {
+		return s.Components.Schemas
+	}
+	return s.Definitions
+}
+
+// Schema returns the named schema, or nil.
+func (s *Spec) Schema(name string) *Schema {
+	return s.schemas()[name]
+}
+
+// resolve follows a $ref and returns the schema and its name.
+func (s *Spec) resolve(sc *Schema) (*Schema, string) {
+	if sc == nil || sc.Ref == "" {
+		return sc, ""
+	}
+	name := sc.Ref[strings.LastIndex(sc.Ref, "/")+1:]
+	return s.Schema(name), name
+}
+
+// properties returns a schema's properties and required names, including
+// those of allOf parts.
+func (s *Spec) properties(sc *Schema) (Properties, map[string]bool) {
+	required := map[string]bool{}
+	var props Properties
+	var walk func(*Schema, int)
+	walk = func(sc *Schema, depth int) {
+		sc, _ = s.resolve(sc)
+		if sc == nil || depth > 10 {
+			return
+		}
+		for _, part := range sc.AllOf {
+			walk(part, depth+1)
+		}
+		props = append(props, sc.Properties...)
+		for _, r := range sc.Required {
+			required[r] = true
+		}
+	}
+	walk(sc, 0)
+	return props, required
+}
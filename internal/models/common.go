@@ -2,19 +2,6 @@ package models
 
 import "encoding/json"
 
-// Money represents a monetary amount.
-type Money struct {
-	Amount   string `json:"amount"`
-	Currency string `json:"currency"`
-}
-
-// PageDetail contains pagination metadata from API responses.
-type PageDetail struct {
-	TotalResults int `json:"totalResults"`
-	StartIndex   int `json:"startIndex"`
-	ItemsPerPage int `json:"itemsPerPage"`
-}
-
 // APIResponse is the standard wrapper for all API responses.
 type APIResponse struct {
 	Data       json.RawMessage `json:"data"`
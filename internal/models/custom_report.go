@@ -0,0 +1,23 @@
+package models
+
+// CustomReportRequest creates an impression share (custom) report.
+type CustomReportRequest struct {
+	Name        string      `json:"name"`
+	StartTime   string      `json:"startTime"`
+	EndTime     string      `json:"endTime"`
+	Granularity Granularity `json:"granularity,omitempty"` // DAILY or WEEKLY
+	Selector    *Selector   `json:"selector,omitempty"`
+}
+
+// ImpressionShareRow is a row of a downloaded impression share report.
+type ImpressionShareRow struct {
+	Date                string  `json:"date"`
+	AppName             string  `json:"appName,omitempty"`
+	AdamID              int64   `json:"adamId,omitempty"`
+	CountryOrRegion     string  `json:"countryOrRegion"`
+	SearchTerm          string  `json:"searchTerm"`
+	LowImpressionShare  float64 `json:"lowImpressionShare"`
+	HighImpressionShare float64 `json:"highImpressionShare"`
+	Rank                string  `json:"rank,omitempty"`
+	SearchPopularity    int     `json:"searchPopularity,omitempty"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldChange is a field an update changes, named by its JSON name.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// String renders the change as "field: before → after".
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Field, FormatValue(c.Before), FormatValue(c.After))
+}
+
+// Diff compares an update (e.g. *CampaignUpdate) with the entity it applies
+// to (e.g. *Campaign), matching fields by JSON name. Only the fields the
+// update sets are compared, by the same rules as the Unchanged methods:
+// money by amount, country lists in any order. The ID field is skipped.
+func Diff(current, update interface{}) []FieldChange {
+	cur, upd := indirect(reflect.ValueOf(current)), indirect(reflect.ValueOf(update))
+	if !cur.IsValid() || !upd.IsValid() || cur.Kind() != reflect.Struct || upd.Kind() != reflect.Struct {
+		return nil
+	}
+	byName := map[string]reflect.Value{}
+	for i := 0; i < cur.NumField(); i++ {
+		byName[jsonName(cur.Type().Field(i))] = cur.Field(i)
+	}
+
+	var changes []FieldChange
+	for i := 0; i < upd.NumField(); i++ {
+		name := jsonName(upd.Type().Field(i))
+		after := upd.Field(i)
+		if name == "" || name == "id" || after.IsZero() {
+			continue
+		}
+		var before interface{}
+		if v, ok := byName[name]; ok {
+			before = v.Interface()
+		}
+		if sameValue(after.Interface(), before) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Before: plain(before), After: plain(after.Interface())})
+	}
+	return changes
+}
+
+func sameValue(after, before interface{}) bool {
+	switch a := after.(type) {
+	case *Money:
+		b, _ := before.(*Money)
+		return sameMoney(a, b)
+	case []string:
+		b, _ := before.([]string)
+		return sameCodes(a, b)
+	case *bool:
+		b, ok := before.(bool)
+		return ok && *a == b
+	}
+	if reflect.TypeOf(after) == reflect.TypeOf(before) && reflect.ValueOf(after).Kind() == reflect.String {
+		return after == before
+	}
+	return sameJSON(after, before)
+}
+
+// plain dereferences pointers so changes encode as values.
+func plain(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		return rv.Elem().Interface()
+	}
+	return v
+}
+
+// FormatValue renders a changed value for display: money as "1.50 USD",
+// lists comma-separated, and nothing as "-".
+func FormatValue(v interface{}) string {
+	switch x := plain(v).(type) {
+	case nil:
+		return "-"
+	case Money:
+		return strings.TrimSpace(x.Amount + " " + x.Currency)
+	case []string:
+		if len(x) == 0 {
+			return "-"
+		}
+		return strings.Join(x, ",")
+	case string:
+		if x == "" {
+			return "-"
+		}
+		return x
+	}
+	if s := fmt.Sprint(plain(v)); s != "" {
+		return s
+	}
+	return "-"
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
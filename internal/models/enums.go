@@ -0,0 +1,278 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Status is the status of a campaign, ad group, or ad.
+type Status string
+
+const (
+	StatusEnabled Status = "ENABLED"
+	StatusPaused  Status = "PAUSED"
+)
+
+// KeywordStatus is the status of a targeting or negative keyword.
+type KeywordStatus string
+
+const (
+	KeywordActive KeywordStatus = "ACTIVE"
+	KeywordPaused KeywordStatus = "PAUSED"
+)
+
+// ServingStatus is whether a campaign, ad group, or ad is serving.
+type ServingStatus string
+
+const (
+	ServingRunning    ServingStatus = "RUNNING"
+	ServingNotRunning ServingStatus = "NOT_RUNNING"
+)
+
+// MatchType is a keyword's match type.
+type MatchType string
+
+const (
+	MatchBroad MatchType = "BROAD"
+	MatchExact MatchType = "EXACT"
+)
+
+// Granularity is the time bucket of a report.
+type Granularity string
+
+const (
+	GranularityHourly  Granularity = "HOURLY"
+	GranularityDaily   Granularity = "DAILY"
+	GranularityWeekly  Granularity = "WEEKLY"
+	GranularityMonthly Granularity = "MONTHLY"
+)
+
+// SupplySource is where a campaign's ads run.
+type SupplySource string
+
+const (
+	SupplySearchResults SupplySource = "APPSTORE_SEARCH_RESULTS"
+	SupplySearchTab     SupplySource = "APPSTORE_SEARCH_TAB"
+	SupplyTodayTab      SupplySource = "APPSTORE_TODAY_TAB"
+	SupplyProductPages  SupplySource = "APPSTORE_PRODUCT_PAGES_BROWSE"
+)
+
+// AdChannelType is a campaign's channel.
+type AdChannelType string
+
+const (
+	ChannelSearch  AdChannelType = "SEARCH"
+	ChannelDisplay AdChannelType = "DISPLAY"
+)
+
+// BillingEvent is what a campaign is charged for.
+type BillingEvent string
+
+const (
+	BillingTaps        BillingEvent = "TAPS"
+	BillingImpressions BillingEvent = "IMPRESSIONS"
+)
+
+// PricingModel is how an ad group bids.
+type PricingModel string
+
+const (
+	PricingCPC PricingModel = "CPC"
+	PricingCPM PricingModel = "CPM"
+)
+
+// PaymentModel is how a campaign is paid for.
+type PaymentModel string
+
+const (
+	PaymentPAYG PaymentModel = "PAYG"
+	PaymentLOC  PaymentModel = "LOC"
+)
+
+// enumValues are the values each enum accepts, in their canonical case.
+var enumValues = map[string][]string{
+	"status":         {"ENABLED", "PAUSED"},
+	"keyword status": {"ACTIVE", "PAUSED"},
+	"serving status": {"RUNNING", "NOT_RUNNING"},
+	"match type":     {"BROAD", "EXACT"},
+	"granularity":    {"HOURLY", "DAILY", "WEEKLY", "MONTHLY"},
+	"supply source":  {"APPSTORE_SEARCH_RESULTS", "APPSTORE_SEARCH_TAB", "APPSTORE_TODAY_TAB", "APPSTORE_PRODUCT_PAGES_BROWSE"},
+	"ad channel":     {"SEARCH", "DISPLAY"},
+	"billing event":  {"TAPS", "IMPRESSIONS"},
+	"pricing model":  {"CPC", "CPM"},
+	"payment model":  {"PAYG", "LOC"},
+}
+
+// parseEnum returns s in its canonical case, or an error naming the accepted
+// values. An empty s is accepted as unset.
+func parseEnum[T ~string](kind, s string) (T, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	if v == "" {
+		return "", nil
+	}
+	for _, allowed := range enumValues[kind] {
+		if v == allowed {
+			return T(v), nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s %q (expected %s)", kind, s, strings.Join(enumValues[kind], ", "))
+}
+
+// unmarshalEnum decodes a JSON string into an enum. Known values are put in
+// their canonical case; values Apple adds later are kept as they are, so a new
+// value doesn't break decoding (--strict-decode reports them as drift).
+func unmarshalEnum[T ~string](kind string, data []byte, v *T) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if parsed, err := parseEnum[T](kind, s); err == nil {
+		s = string(parsed)
+	}
+	*v = T(s)
+	return nil
+}
+
+// enum is implemented by the enum types.
+type enum interface {
+	EnumValues() []string
+}
+
+// IsKnownEnum reports whether s is one of the values of an enum type (in any
+// case); values of other types are always known.
+func IsKnownEnum(v interface{}, s string) bool {
+	e, ok := v.(enum)
+	if !ok || s == "" {
+		return true
+	}
+	for _, allowed := range e.EnumValues() {
+		if strings.EqualFold(s, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEnums rejects enum fields of v, a struct or a slice of structs, whose
+// values aren't known. Decoding accepts any value, so specs and request
+// bodies written by users are checked with it.
+func CheckEnums(v interface{}) error {
+	return checkEnums(reflect.ValueOf(v), "")
+}
+
+func checkEnums(v reflect.Value, path string) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		if !IsKnownEnum(v.Interface(), v.String()) {
+			return fmt.Errorf("invalid value %q for %s (expected %s)", v.String(), path, strings.Join(v.Interface().(enum).EnumValues(), ", "))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name == "" {
+				name = t.Field(i).Name
+			}
+			if t.Field(i).Anonymous {
+				name = path
+			} else if path != "" {
+				name = path + "." + name
+			}
+			if err := checkEnums(v.Field(i), name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkEnums(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EnumValues returns the values an enum kind accepts, e.g. for flag help.
+func EnumValues(kind string) []string {
+	return enumValues[kind]
+}
+
+// ParseStatus parses a campaign, ad group, or ad status, in any case.
+func ParseStatus(s string) (Status, error) { return parseEnum[Status]("status", s) }
+
+// ParseKeywordStatus parses a keyword status, in any case.
+func ParseKeywordStatus(s string) (KeywordStatus, error) {
+	return parseEnum[KeywordStatus]("keyword status", s)
+}
+
+// ParseServingStatus parses a serving status, in any case.
+func ParseServingStatus(s string) (ServingStatus, error) {
+	return parseEnum[ServingStatus]("serving status", s)
+}
+
+// ParseMatchType parses a match type, in any case.
+func ParseMatchType(s string) (MatchType, error) { return parseEnum[MatchType]("match type", s) }
+
+// ParseGranularity parses a report granularity, in any case.
+func ParseGranularity(s string) (Granularity, error) {
+	return parseEnum[Granularity]("granularity", s)
+}
+
+// ParseSupplySource parses a supply source, in any case.
+func ParseSupplySource(s string) (SupplySource, error) {
+	return parseEnum[SupplySource]("supply source", s)
+}
+
+// ParseAdChannelType parses an ad channel type, in any case.
+func ParseAdChannelType(s string) (AdChannelType, error) {
+	return parseEnum[AdChannelType]("ad channel", s)
+}
+
+// ParseBillingEvent parses a billing event, in any case.
+func ParseBillingEvent(s string) (BillingEvent, error) {
+	return parseEnum[BillingEvent]("billing event", s)
+}
+
+// ParsePricingModel parses a pricing model, in any case.
+func ParsePricingModel(s string) (PricingModel, error) {
+	return parseEnum[PricingModel]("pricing model", s)
+}
+
+// ParsePaymentModel parses a payment model, in any case.
+func ParsePaymentModel(s string) (PaymentModel, error) {
+	return parseEnum[PaymentModel]("payment model", s)
+}
+
+func (v *Status) UnmarshalJSON(b []byte) error        { return unmarshalEnum("status", b, v) }
+func (v *KeywordStatus) UnmarshalJSON(b []byte) error { return unmarshalEnum("keyword status", b, v) }
+func (v *ServingStatus) UnmarshalJSON(b []byte) error { return unmarshalEnum("serving status", b, v) }
+func (v *MatchType) UnmarshalJSON(b []byte) error     { return unmarshalEnum("match type", b, v) }
+func (v *Granularity) UnmarshalJSON(b []byte) error   { return unmarshalEnum("granularity", b, v) }
+func (v *SupplySource) UnmarshalJSON(b []byte) error  { return unmarshalEnum("supply source", b, v) }
+func (v *AdChannelType) UnmarshalJSON(b []byte) error { return unmarshalEnum("ad channel", b, v) }
+func (v *BillingEvent) UnmarshalJSON(b []byte) error  { return unmarshalEnum("billing event", b, v) }
+func (v *PricingModel) UnmarshalJSON(b []byte) error  { return unmarshalEnum("pricing model", b, v) }
+func (v *PaymentModel) UnmarshalJSON(b []byte) error  { return unmarshalEnum("payment model", b, v) }
+
+// EnumValues return the values each enum accepts, e.g. for JSON Schemas.
+func (Status) EnumValues() []string        { return enumValues["status"] }
+func (KeywordStatus) EnumValues() []string { return enumValues["keyword status"] }
+func (ServingStatus) EnumValues() []string { return enumValues["serving status"] }
+func (MatchType) EnumValues() []string     { return enumValues["match type"] }
+func (Granularity) EnumValues() []string   { return enumValues["granularity"] }
+func (SupplySource) EnumValues() []string  { return enumValues["supply source"] }
+func (AdChannelType) EnumValues() []string { return enumValues["ad channel"] }
+func (BillingEvent) EnumValues() []string  { return enumValues["billing event"] }
+func (PricingModel) EnumValues() []string  { return enumValues["pricing model"] }
+func (PaymentModel) EnumValues() []string  { return enumValues["payment model"] }
@@ -4,7 +4,7 @@ package models
 type ReportRequest struct {
 	StartTime        string   `json:"startTime"`
 	EndTime          string   `json:"endTime"`
-	Granularity      string   `json:"granularity,omitempty"` // HOURLY, DAILY, WEEKLY, MONTHLY
+	Granularity      Granularity   `json:"granularity,omitempty"` // HOURLY, DAILY, WEEKLY, MONTHLY
 	GroupBy          []string `json:"groupBy,omitempty"`     // countryOrRegion, deviceClass, ageRange, gender, adminArea, locality
 	Selector         *Selector `json:"selector,omitempty"`
 	ReturnGrandTotals bool    `json:"returnGrandTotals,omitempty"`
@@ -67,9 +67,12 @@ type InsightData struct {
 	BidRecommendation *BidRecommendation `json:"bidRecommendation,omitempty"`
 }
 
-// BidRecommendation for keyword bid suggestions.
+// BidRecommendation for keyword bid suggestions. Apple gives either a
+// suggested amount or a suggested range (bidMin to bidMax).
 type BidRecommendation struct {
 	SuggestedBidAmount *Money `json:"suggestedBidAmount,omitempty"`
+	BidMin             *Money `json:"bidMin,omitempty"`
+	BidMax             *Money `json:"bidMax,omitempty"`
 }
 
 // SearchTermReportRow is a row in the search terms report.
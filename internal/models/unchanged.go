@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Unchanged reports whether applying u to c would change nothing, so the
+// update can be skipped.
+func (u *CampaignUpdate) Unchanged(c *Campaign) bool {
+	return (u.Name == "" || u.Name == c.Name) &&
+		(u.Status == "" || u.Status == c.Status) &&
+		sameMoney(u.BudgetAmount, c.BudgetAmount) &&
+		sameMoney(u.DailyBudgetAmount, c.DailyBudgetAmount) &&
+		(u.CountriesOrRegions == nil || sameCodes(u.CountriesOrRegions, c.CountriesOrRegions)) &&
+		(u.LOCInvoiceDetails == nil || c.LOCInvoiceDetails != nil && *u.LOCInvoiceDetails == *c.LOCInvoiceDetails)
+}
+
+// Unchanged reports whether applying u to g would change nothing.
+// Targeting counts as unchanged only if it encodes to the same JSON.
+func (u *AdGroupUpdate) Unchanged(g *AdGroup) bool {
+	return (u.Name == "" || u.Name == g.Name) &&
+		(u.Status == "" || u.Status == g.Status) &&
+		sameMoney(u.DefaultBidAmount, g.DefaultBidAmount) &&
+		sameMoney(u.CpaGoal, g.CpaGoal) &&
+		(u.AutomatedKeywordsOptIn == nil || *u.AutomatedKeywordsOptIn == g.AutomatedKeywordsOptIn) &&
+		(u.StartTime == "" || u.StartTime == g.StartTime) &&
+		(u.EndTime == "" || u.EndTime == g.EndTime) &&
+		(u.TargetingDimensions == nil || sameJSON(u.TargetingDimensions, g.TargetingDimensions))
+}
+
+// Unchanged reports whether applying u to k would change nothing.
+func (u KeywordUpdate) Unchanged(k *Keyword) bool {
+	return (u.Status == "" || u.Status == k.Status) && sameMoney(u.BidAmount, k.BidAmount)
+}
+
+// sameMoney reports whether setting want leaves have as it is. A nil want
+// sets nothing; amounts are compared as numbers, so 1.5 equals 1.50.
+func sameMoney(want, have *Money) bool {
+	if want == nil {
+		return true
+	}
+	if have == nil || !strings.EqualFold(want.Currency, have.Currency) {
+		return false
+	}
+	a, err1 := strconv.ParseFloat(want.Amount, 64)
+	b, err2 := strconv.ParseFloat(have.Amount, 64)
+	return err1 == nil && err2 == nil && a == b
+}
+
+// sameCodes reports whether two lists of country codes hold the same codes
+// in any order.
+func sameCodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+func sameJSON(a, b interface{}) bool {
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(x) == string(y)
+}
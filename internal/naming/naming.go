@@ -0,0 +1,76 @@
+// Package naming validates entity names against configured naming policies.
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tokenRe = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Rule is a compiled naming pattern.
+//
+// A pattern containing {token} placeholders is a token pattern: literal text
+// must match exactly and each token matches one of its allowed values, or
+// any non-empty text if none are configured. For example, "{country}_{type}_{app}"
+// accepts "US_Brand_MyApp". Any other pattern is a regular expression.
+type Rule struct {
+	Pattern string
+	re      *regexp.Regexp
+	hints   []string
+}
+
+// Compile compiles a pattern. tokens lists allowed values per token name.
+func Compile(pattern string, tokens map[string][]string) (*Rule, error) {
+	r := &Rule{Pattern: pattern}
+	if !tokenRe.MatchString(pattern) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid naming pattern %q: %w", pattern, err)
+		}
+		r.re = re
+		return r, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, m := range tokenRe.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:m[0]]))
+		name := strings.ToLower(pattern[m[2]:m[3]])
+		if values := tokens[name]; len(values) > 0 {
+			quoted := make([]string, len(values))
+			for i, v := range values {
+				quoted[i] = regexp.QuoteMeta(v)
+			}
+			b.WriteString("(?:" + strings.Join(quoted, "|") + ")")
+			r.hints = append(r.hints, name+": "+strings.Join(values, "|"))
+		} else {
+			b.WriteString(".+?")
+		}
+		last = m[1]
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming pattern %q: %w", pattern, err)
+	}
+	r.re = re
+	return r, nil
+}
+
+// Check returns an error describing the policy if name does not match.
+func (r *Rule) Check(name string) error {
+	if r.re.MatchString(name) {
+		return nil
+	}
+	msg := fmt.Sprintf("name %q does not match naming policy %q", name, r.Pattern)
+	if len(r.hints) > 0 {
+		msg += " (" + strings.Join(r.hints, "; ") + ")"
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,204 @@
+// Package negeffect estimates what negative keywords block. Apple reports no
+// traffic for a blocked term, so the effect is read from history instead:
+// search terms that served before a negative was added and stopped serving
+// afterwards were most likely blocked by it.
+package negeffect
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+	"github.com/trebuhs/asa-cli/internal/daterange"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/ngram"
+)
+
+// Negative is a negative keyword and the time it was added. AdGroupID is 0
+// for campaign negatives.
+type Negative struct {
+	ID        int64            `json:"id"`
+	AdGroupID int64            `json:"adGroupId,omitempty"`
+	Text      string           `json:"text"`
+	MatchType models.MatchType `json:"matchType"`
+	Added     time.Time        `json:"added"`
+}
+
+// Term is a search term's daily metrics in one ad group, by date
+// (daterange.DateFormat).
+type Term struct {
+	Text      string
+	AdGroupID int64
+	Days      map[string]aggregate.Metrics
+}
+
+// Terms builds Terms from a daily search-term report.
+func Terms(resp *models.ReportingDataResponse) []Term {
+	if resp == nil {
+		return nil
+	}
+	type key struct {
+		text string
+		ag   int64
+	}
+	index := map[key]int{}
+	var out []Term
+	for _, row := range resp.Row {
+		k := key{aggregate.NormalizeTerm(aggregate.MetaString(row.Metadata, "searchTermText")), aggregate.MetaInt64(row.Metadata, "adGroupId")}
+		if k.text == "" {
+			continue
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(out)
+			index[k] = i
+			out = append(out, Term{Text: k.text, AdGroupID: k.ag, Days: map[string]aggregate.Metrics{}})
+		}
+		for _, g := range row.Granularity {
+			m := out[i].Days[g.Date]
+			m.Add(g.Metrics)
+			out[i].Days[g.Date] = m
+		}
+	}
+	return out
+}
+
+// Options tune when a negative is flagged as possibly over-broad.
+type Options struct {
+	// MinTerms flags broad negatives that stopped at least this many
+	// distinct terms besides their own text.
+	MinTerms int
+}
+
+// Effect is what a negative likely blocked. Before covers the matched terms'
+// days before it was added; Estimate projects the stopped terms' daily
+// average over the days after it.
+type Effect struct {
+	Negative
+	DaysBefore int `json:"daysBefore"`
+	DaysAfter  int `json:"daysAfter"`
+	// Matched are the terms the negative matches that served before it.
+	Matched int `json:"matched"`
+	// Stopped are the matched terms with no impressions after it.
+	Stopped      []string          `json:"stopped"`
+	StillServing []string          `json:"stillServing"`
+	Before       aggregate.Metrics `json:"before"`
+	Estimate     aggregate.Metrics `json:"estimate"`
+	OverBroad    bool              `json:"overBroad"`
+	Reason       string            `json:"reason,omitempty"`
+}
+
+// Analyze estimates the effect of each negative added inside rng, with at
+// least a day of history on either side. Negatives added outside it are
+// returned as skipped. Effects are sorted by estimated spend, highest first.
+func Analyze(negatives []Negative, terms []Term, rng daterange.Range, opts Options) (effects []Effect, skipped []Negative) {
+	for _, n := range negatives {
+		added := n.Added.In(rng.Start.Location())
+		day := time.Date(added.Year(), added.Month(), added.Day(), 0, 0, 0, 0, rng.Start.Location())
+		// The day it was added is mixed, so it counts on neither side.
+		if !day.After(rng.Start) || !day.Before(rng.End) {
+			skipped = append(skipped, n)
+			continue
+		}
+		before := daterange.Range{Start: rng.Start, End: day.AddDate(0, 0, -1)}
+		after := daterange.Range{Start: day.AddDate(0, 0, 1), End: rng.End}
+		effects = append(effects, analyze(n, day, before.Days(), after.Days(), terms, opts))
+	}
+	sort.SliceStable(effects, func(i, j int) bool { return effects[i].Estimate.Spend > effects[j].Estimate.Spend })
+	return effects, skipped
+}
+
+func analyze(n Negative, day time.Time, before, after int, terms []Term, opts Options) Effect {
+	e := Effect{Negative: n, DaysBefore: before, DaysAfter: after, Stopped: []string{}, StillServing: []string{}}
+	added := day.Format(daterange.DateFormat)
+	words := ngram.Tokenize(n.Text)
+
+	// Total each matched term over the ad groups the negative applies to.
+	type history struct{ pre, post aggregate.Metrics }
+	matched := map[string]*history{}
+	for _, t := range terms {
+		if n.AdGroupID != 0 && t.AdGroupID != n.AdGroupID {
+			continue
+		}
+		if !Matches(n.MatchType, words, t.Text) {
+			continue
+		}
+		h := matched[t.Text]
+		if h == nil {
+			h = &history{}
+			matched[t.Text] = h
+		}
+		for date, m := range t.Days {
+			switch {
+			case date < added:
+				h.pre.Merge(m)
+			case date > added:
+				h.post.Merge(m)
+			}
+		}
+	}
+
+	var lost aggregate.Metrics
+	own := strings.Join(words, " ")
+	others := 0
+	for text, h := range matched {
+		if h.pre.Impressions == 0 {
+			continue
+		}
+		e.Matched++
+		e.Before.Merge(h.pre)
+		if h.post.Impressions > 0 {
+			e.StillServing = append(e.StillServing, text)
+			continue
+		}
+		e.Stopped = append(e.Stopped, text)
+		lost.Merge(h.pre)
+		if text != own {
+			others++
+		}
+	}
+	sort.Strings(e.Stopped)
+	sort.Strings(e.StillServing)
+
+	scale := float64(after) / float64(before)
+	e.Estimate = aggregate.Metrics{
+		Impressions: int64(math.Round(float64(lost.Impressions) * scale)),
+		Taps:        int64(math.Round(float64(lost.Taps) * scale)),
+		Installs:    int64(math.Round(float64(lost.Installs) * scale)),
+		Spend:       lost.Spend * scale,
+		Currency:    lost.Currency,
+	}
+
+	switch {
+	case lost.Installs > 0:
+		e.OverBroad, e.Reason = true, "stopped terms that converted"
+	case n.MatchType == models.MatchBroad && opts.MinTerms > 0 && others >= opts.MinTerms:
+		e.OverBroad, e.Reason = true, "stopped many other terms"
+	}
+	return e
+}
+
+// Matches reports whether a negative with the given words blocks term, the
+// way Apple applies negatives: exact negatives block the term itself, broad
+// ones any term containing all of their words, in any order.
+func Matches(matchType models.MatchType, words []string, term string) bool {
+	tokens := ngram.Tokenize(term)
+	if len(words) == 0 {
+		return false
+	}
+	if matchType == models.MatchExact {
+		return strings.Join(tokens, " ") == strings.Join(words, " ")
+	}
+	have := map[string]bool{}
+	for _, t := range tokens {
+		have[t] = true
+	}
+	for _, w := range words {
+		if !have[w] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,242 @@
+// Package neglists keeps reusable negative keyword lists locally, per
+// profile, and records which campaigns each list was applied to. The record
+// lets a later apply remove terms dropped from the list, and lets status
+// report campaigns that drifted from it.
+package neglists
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/kwplan"
+)
+
+// List is a named set of negative keyword terms with one match type. With
+// Countries set, it applies only to campaigns serving one of them.
+type List struct {
+	Name      string    `json:"name"`
+	MatchType string    `json:"matchType"`
+	Countries []string  `json:"countries,omitempty"`
+	Terms     []string  `json:"terms"`
+	Updated   time.Time `json:"updated"`
+	// Applied is what the list last synced to each campaign, by campaign ID.
+	Applied map[int64]Application `json:"applied,omitempty"`
+}
+
+// Application is the list's terms as last synced to a campaign.
+type Application struct {
+	CampaignName string    `json:"campaignName,omitempty"`
+	Terms        []string  `json:"terms"`
+	AppliedAt    time.Time `json:"appliedAt"`
+}
+
+// Covers reports whether a campaign serving countries is in the list's
+// storefront scope.
+func (l List) Covers(countries []string) bool {
+	if len(l.Countries) == 0 {
+		return true
+	}
+	for _, c := range countries {
+		for _, want := range l.Countries {
+			if strings.EqualFold(c, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Plan is what syncing a list to a campaign changes.
+type Plan struct {
+	// Add are list terms the campaign lacks.
+	Add []string `json:"add"`
+	// Remove are terms the list synced before but no longer holds. Terms
+	// that another list applied to the campaign still needs are kept.
+	Remove []string `json:"remove"`
+	// Present are list terms the campaign already has.
+	Present []string `json:"present"`
+}
+
+// Plan compares the list with a campaign's live negative terms of the list's
+// match type. kept are terms other lists applied to the campaign hold.
+func (l List) Plan(campaignID int64, live, kept map[string]bool) Plan {
+	var p Plan
+	inList := map[string]bool{}
+	for _, t := range l.Terms {
+		inList[t] = true
+		if live[t] {
+			p.Present = append(p.Present, t)
+		} else {
+			p.Add = append(p.Add, t)
+		}
+	}
+	for _, t := range l.Applied[campaignID].Terms {
+		if !inList[t] && live[t] && !kept[t] {
+			p.Remove = append(p.Remove, t)
+		}
+	}
+	return p
+}
+
+// Drift is how a campaign differs from what the list last synced to it.
+type Drift struct {
+	// Missing are synced terms since removed from the campaign outside the list.
+	Missing []string `json:"missing,omitempty"`
+	// Pending are terms added to the list since it was last applied.
+	Pending []string `json:"pending,omitempty"`
+	// Stale are terms dropped from the list that the campaign still has.
+	Stale []string `json:"stale,omitempty"`
+}
+
+// InSync reports whether the campaign matches the list.
+func (d Drift) InSync() bool {
+	return len(d.Missing)+len(d.Pending)+len(d.Stale) == 0
+}
+
+// Drift compares a campaign's live negative terms with the list and its last
+// application to the campaign.
+func (l List) Drift(campaignID int64, live map[string]bool) Drift {
+	var d Drift
+	applied := map[string]bool{}
+	for _, t := range l.Applied[campaignID].Terms {
+		applied[t] = true
+	}
+	inList := map[string]bool{}
+	for _, t := range l.Terms {
+		inList[t] = true
+		switch {
+		case !applied[t]:
+			d.Pending = append(d.Pending, t)
+		case !live[t]:
+			d.Missing = append(d.Missing, t)
+		}
+	}
+	for _, t := range l.Applied[campaignID].Terms {
+		if !inList[t] && live[t] {
+			d.Stale = append(d.Stale, t)
+		}
+	}
+	return d
+}
+
+// ReadTerms reads one term per line. Blank lines and lines starting with #
+// are skipped; terms are normalized like keywords and repeats dropped. An
+// invalid term fails the whole read, naming its line.
+func ReadTerms(r io.Reader) ([]string, error) {
+	var terms []string
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+		term := kwplan.Normalize(raw)
+		if err := kwplan.Validate(term); err != nil {
+			return nil, fmt.Errorf("line %d: %q: %w", line, raw, err)
+		}
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading terms: %w", err)
+	}
+	return terms, nil
+}
+
+// Store holds the negative keyword lists of one profile, keyed by name.
+type Store struct {
+	Lists map[string]List `json:"lists"`
+
+	path string
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Path returns the list file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("neg_lists", profile)
+}
+
+// Load reads the lists of a profile. A missing file yields an empty store.
+func Load(profile string) (*Store, error) {
+	s := &Store{Lists: map[string]List{}, path: Path(profile)}
+	if err := config.LoadJSON(s.path, "negative keyword lists", s); err != nil {
+		return nil, err
+	}
+	if s.Lists == nil {
+		s.Lists = map[string]List{}
+	}
+	return s, nil
+}
+
+// Normalize lowercases and trims a list name.
+func Normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Get returns a list by name.
+func (s *Store) Get(name string) (List, error) {
+	l, ok := s.Lists[Normalize(name)]
+	if !ok {
+		return List{}, fmt.Errorf("negative keyword list %q not found", Normalize(name))
+	}
+	return l, nil
+}
+
+// Put adds or replaces a list and saves the store.
+func (s *Store) Put(l List) error {
+	l.Name = Normalize(l.Name)
+	if !validName.MatchString(l.Name) {
+		return fmt.Errorf("invalid list name %q: use letters, digits, '.', '_' or '-'", l.Name)
+	}
+	s.Lists[l.Name] = l
+	return s.save()
+}
+
+// Delete removes a list and saves the store.
+func (s *Store) Delete(name string) error {
+	name = Normalize(name)
+	if _, ok := s.Lists[name]; !ok {
+		return fmt.Errorf("negative keyword list %q not found", name)
+	}
+	delete(s.Lists, name)
+	return s.save()
+}
+
+// All returns the lists ordered by name.
+func (s *Store) All() []List {
+	out := make([]List, 0, len(s.Lists))
+	for _, l := range s.Lists {
+		out = append(out, l)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Kept returns the terms of the other lists applied to a campaign with
+// matchType, which syncing the list except must not remove.
+func (s *Store) Kept(except string, campaignID int64, matchType string) map[string]bool {
+	kept := map[string]bool{}
+	for name, l := range s.Lists {
+		if _, ok := l.Applied[campaignID]; !ok || name == except || !strings.EqualFold(l.MatchType, matchType) {
+			continue
+		}
+		for _, t := range l.Terms {
+			kept[t] = true
+		}
+	}
+	return kept
+}
+
+func (s *Store) save() error {
+	return config.SaveJSON(s.path, "negative keyword lists", s)
+}
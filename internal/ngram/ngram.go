@@ -0,0 +1,82 @@
+// Package ngram breaks search terms into word n-grams and totals report
+// metrics per n-gram, so tokens that waste spend across many terms show up
+// even when no single term is large enough to notice.
+package ngram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+)
+
+// MaxN is the longest n-gram Count builds.
+const MaxN = 3
+
+// stopwords are left out of 1-grams; they appear in too many terms to say
+// anything on their own.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "app": true, "apps": true, "by": true,
+	"for": true, "in": true, "of": true, "on": true, "the": true, "to": true,
+	"with": true,
+}
+
+// Gram is an n-gram with the totals of the search terms containing it.
+type Gram struct {
+	Text  string `json:"text"`
+	N     int    `json:"n"`
+	Terms int    `json:"terms"`
+	aggregate.Metrics
+}
+
+// Tokenize splits a search term into lowercase words. Apostrophes stay inside
+// words ("kid's"); other punctuation separates them.
+func Tokenize(term string) []string {
+	return strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\'' && r != '’'
+	})
+}
+
+// Grams returns the distinct n-grams of tokens, for n from 1 to maxN.
+func Grams(tokens []string, maxN int) []string {
+	seen := map[string]bool{}
+	var out []string
+	for n := 1; n <= maxN; n++ {
+		for i := 0; i+n <= len(tokens); i++ {
+			g := strings.Join(tokens[i:i+n], " ")
+			if seen[g] || (n == 1 && stopwords[g]) {
+				continue
+			}
+			seen[g] = true
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// Count totals terms per n-gram, for n from 1 to maxN. A term adds to each
+// distinct n-gram it contains once. Grams are sorted by spend, highest first.
+func Count(terms []aggregate.SearchTerm, maxN int) []Gram {
+	index := map[string]int{}
+	var out []Gram
+	for _, t := range terms {
+		for _, g := range Grams(Tokenize(t.Term), maxN) {
+			i, ok := index[g]
+			if !ok {
+				i = len(out)
+				index[g] = i
+				out = append(out, Gram{Text: g, N: strings.Count(g, " ") + 1})
+			}
+			out[i].Terms++
+			out[i].Metrics.Merge(t.Metrics)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Spend != out[j].Spend {
+			return out[i].Spend > out[j].Spend
+		}
+		return out[i].Text < out[j].Text
+	})
+	return out
+}
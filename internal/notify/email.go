@@ -0,0 +1,219 @@
+// Package notify delivers generated reports to people outside the terminal.
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+const (
+	defaultSubject = "{{.Job}}: {{.Files}} ({{.Date}})"
+	defaultBody    = "Attached: {{.Files}}\n\nGenerated by asa-cli on {{.Date}}.\n"
+)
+
+// TemplateData is available to subject and body templates.
+type TemplateData struct {
+	Job     string
+	Profile string
+	Date    string
+	Files   string
+}
+
+// Message is a rendered email with attachments.
+type Message struct {
+	From    string
+	To      []string
+	Cc      []string
+	Subject string
+	Body    string
+	Files   []string
+}
+
+// NewMessage renders the message for a configured job.
+func NewMessage(cfg *config.EmailConfig, job, profile string, files []string) (*Message, error) {
+	j, ok := cfg.Jobs[strings.ToLower(job)]
+	if !ok {
+		return nil, fmt.Errorf("email job %q not found under notifications.email.jobs", job)
+	}
+	if len(j.To) == 0 {
+		return nil, fmt.Errorf("email job %q has no recipients", job)
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("notifications.email.from is not set")
+	}
+
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = filepath.Base(f)
+	}
+	data := TemplateData{Job: job, Profile: profile, Date: time.Now().Format("2006-01-02"), Files: strings.Join(names, ", ")}
+
+	subject, err := render("subject", first(j.Subject, cfg.Subject, defaultSubject), data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := render("body", first(j.Body, cfg.Body, defaultBody), data)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{From: cfg.From, To: j.To, Cc: j.Cc, Subject: strings.TrimSpace(subject), Body: body, Files: files}, nil
+}
+
+func render(name, text string, data TemplateData) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing email %s template: %w", name, err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("rendering email %s template: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Bytes encodes the message as MIME multipart/mixed with base64 attachments.
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(m.To, ", "))
+	if len(m.Cc) > 0 {
+		fmt.Fprintf(&buf, "Cc: %s\r\n", strings.Join(m.Cc, ", "))
+	}
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", w.Boundary())
+
+	part, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, err
+	}
+	part.Write([]byte(strings.ReplaceAll(m.Body, "\n", "\r\n")))
+
+	for _, path := range m.Files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading attachment: %w", err)
+		}
+		name := filepath.Base(path)
+		ctype := mime.TypeByExtension(filepath.Ext(name))
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {ctype},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(data)
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Send delivers the message over SMTP. Port 465 uses implicit TLS; other ports
+// upgrade with STARTTLS when the server offers it.
+func Send(cfg *config.EmailConfig, m *Message) error {
+	if cfg.Host == "" {
+		return fmt.Errorf("notifications.email.host is not set")
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		password := ""
+		if cfg.PasswordEnv != "" {
+			password = os.Getenv(cfg.PasswordEnv)
+			if password == "" {
+				return fmt.Errorf("SMTP password variable %s is not set", cfg.PasswordEnv)
+			}
+		}
+		auth = smtp.PlainAuth("", cfg.Username, password, cfg.Host)
+	}
+
+	rcpts := append(append([]string{}, m.To...), m.Cc...)
+	if port != 465 {
+		return smtp.SendMail(addr, auth, m.From, rcpts, data)
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: cfg.Host})
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP auth: %w", err)
+		}
+	}
+	if err := c.Mail(m.From); err != nil {
+		return err
+	}
+	for _, r := range rcpts {
+		if err := c.Rcpt(r); err != nil {
+			return fmt.Errorf("recipient %s: %w", r, err)
+		}
+	}
+	wc, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := wc.Write(data); err != nil {
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,91 @@
+// Package optimize proposes account changes from recent performance.
+package optimize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/trebuhs/asa-cli/internal/aggregate"
+)
+
+// Budget allocation strategies.
+const (
+	StrategyInstalls = "proportional-to-installs"
+	StrategySpend    = "proportional-to-spend"
+	StrategyCPI      = "inverse-cpi"
+	StrategyEqual    = "equal"
+)
+
+// Strategies lists the accepted strategy names.
+var Strategies = []string{StrategyInstalls, StrategySpend, StrategyCPI, StrategyEqual}
+
+// Weight scores a campaign's metrics under a strategy. A higher weight earns
+// a larger share of the pool.
+func Weight(strategy string, m aggregate.Metrics) (float64, error) {
+	switch strategy {
+	case StrategyInstalls:
+		return float64(m.Installs), nil
+	case StrategySpend:
+		return m.Spend, nil
+	case StrategyCPI:
+		// Cheaper installs earn more budget; campaigns without installs get none.
+		if m.Installs == 0 || m.Spend == 0 {
+			return 0, nil
+		}
+		return 1 / m.CPI(), nil
+	case StrategyEqual:
+		return 1, nil
+	}
+	return 0, fmt.Errorf("unknown strategy %q", strategy)
+}
+
+// Allocate splits pool across weights. Every entry first gets floor (when the
+// pool allows), then the rest is shared in proportion to weight; if all
+// weights are zero it is shared equally. Amounts are rounded to cents and
+// sum exactly to pool.
+func Allocate(pool float64, weights []float64, floor float64) []float64 {
+	n := len(weights)
+	out := make([]float64, n)
+	if n == 0 || pool <= 0 {
+		return out
+	}
+	if floor*float64(n) > pool {
+		floor = pool / float64(n)
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += math.Max(w, 0)
+	}
+	rest := pool - floor*float64(n)
+	for i, w := range weights {
+		share := 1 / float64(n)
+		if total > 0 {
+			share = math.Max(w, 0) / total
+		}
+		out[i] = floor + rest*share
+	}
+
+	// Round to cents, handing the rounding remainder to the largest entries.
+	cents := int64(math.Round(pool * 100))
+	var sum int64
+	rounded := make([]int64, n)
+	for i, v := range out {
+		rounded[i] = int64(math.Floor(v * 100))
+		sum += rounded[i]
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return out[order[a]] > out[order[b]] })
+	for i := 0; sum < cents; i = (i + 1) % n {
+		rounded[order[i]]++
+		sum++
+	}
+	for i := range out {
+		out[i] = float64(rounded[i]) / 100
+	}
+	return out
+}
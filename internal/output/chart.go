@@ -0,0 +1,157 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// BarChart renders labeled horizontal bars, the longest spanning width cells.
+// Negative values are drawn as empty bars.
+func BarChart(labels []string, values []float64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	labelWidth, max := 0, 0.0
+	for i, v := range values {
+		if n := utf8.RuneCountInString(labels[i]); n > labelWidth {
+			labelWidth = n
+		}
+		max = math.Max(max, v)
+	}
+
+	g := currentGlyphs()
+	var b strings.Builder
+	for i, v := range values {
+		cells := 0.0
+		if max > 0 && v > 0 {
+			cells = v / max * float64(width)
+		}
+		full := int(cells)
+		bar := strings.Repeat(g.full, full)
+		if frac := cells - float64(full); frac >= 0.5 {
+			bar += g.half
+		}
+		pad := labelWidth - utf8.RuneCountInString(labels[i])
+		fmt.Fprintf(&b, "%s%s %s%s %s\n", labels[i], strings.Repeat(" ", pad), g.vertical, bar, formatChartValue(v))
+	}
+	return b.String()
+}
+
+// TargetBarChart renders bars like BarChart with each row's target marked on
+// the same scale, e.g. spend so far against an even pace.
+func TargetBarChart(labels []string, values, targets []float64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	labelWidth, max := 0, 0.0
+	for i, v := range values {
+		if n := utf8.RuneCountInString(labels[i]); n > labelWidth {
+			labelWidth = n
+		}
+		max = math.Max(max, math.Max(v, targets[i]))
+	}
+
+	g := currentGlyphs()
+	var b strings.Builder
+	for i, v := range values {
+		cells := make([]string, width+1)
+		bar := 0
+		if max > 0 && v > 0 {
+			bar = int(math.Round(v / max * float64(width)))
+		}
+		for c := range cells {
+			cells[c] = " "
+			if c < bar {
+				cells[c] = g.full
+			}
+		}
+		if max > 0 && targets[i] > 0 {
+			cells[int(math.Round(targets[i]/max*float64(width)))] = g.vertical
+		}
+		pad := labelWidth - utf8.RuneCountInString(labels[i])
+		fmt.Fprintf(&b, "%s%s %s%s %s\n", labels[i], strings.Repeat(" ", pad), g.vertical, strings.Join(cells, ""), formatChartValue(v))
+	}
+	return b.String()
+}
+
+// LineChart plots values over height rows with a y-axis, labeling the x-axis
+// with the first and last labels. Each value takes one column, or more when
+// there are few points so short series stay readable.
+func LineChart(labels []string, values []float64, height int) string {
+	if len(values) == 0 || height < 2 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	step := 1
+	if len(values) < 30 {
+		step = 60 / len(values)
+	}
+
+	level := func(v float64) int {
+		if max == min {
+			return height / 2
+		}
+		return int(math.Round((v - min) / (max - min) * float64(height-1)))
+	}
+
+	top, bottom := formatChartValue(max), formatChartValue(min)
+	axisWidth := len(top)
+	if len(bottom) > axisWidth {
+		axisWidth = len(bottom)
+	}
+
+	g := currentGlyphs()
+	var b strings.Builder
+	for row := height - 1; row >= 0; row-- {
+		axis := ""
+		switch row {
+		case height - 1:
+			axis = top
+		case 0:
+			axis = bottom
+		}
+		fmt.Fprintf(&b, "%*s %s", axisWidth, axis, g.axis)
+		for i, v := range values {
+			cell := " "
+			if level(v) == row {
+				cell = g.point
+			} else if i > 0 {
+				// Connect to the previous point with a vertical stroke.
+				lo, hi := level(values[i-1]), level(v)
+				if lo > hi {
+					lo, hi = hi, lo
+				}
+				if row > lo && row < hi {
+					cell = g.vertical
+				}
+			}
+			b.WriteString(cell + strings.Repeat(" ", step-1))
+		}
+		b.WriteString("\n")
+	}
+
+	span := len(values) * step
+	fmt.Fprintf(&b, "%*s %s%s\n", axisWidth, "", g.corner, strings.Repeat(g.baseline, span))
+	if len(labels) > 0 {
+		first, last := labels[0], labels[len(labels)-1]
+		gap := span - utf8.RuneCountInString(first) - utf8.RuneCountInString(last)
+		if gap < 1 {
+			gap = 1
+		}
+		fmt.Fprintf(&b, "%*s  %s%s%s\n", axisWidth, "", first, strings.Repeat(" ", gap), last)
+	}
+	return b.String()
+}
+
+func formatChartValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e9 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
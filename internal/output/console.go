@@ -0,0 +1,60 @@
+package output
+
+import "strings"
+
+// ASCII switches charts, sparklines, and tree rows to plain ASCII glyphs, for
+// consoles that cannot draw box and block characters.
+var ASCII bool
+
+// glyphs are the drawing characters, in Unicode and ASCII variants.
+type glyphs struct {
+	spark    []rune
+	full     string
+	half     string
+	point    string
+	vertical string
+	axis     string
+	corner   string
+	baseline string
+	indent   string
+	branch   string
+	to       string // between the ends of a range
+	dash     string
+}
+
+var (
+	unicodeGlyphs = glyphs{
+		spark: []rune("▁▂▃▄▅▆▇█"), full: "█", half: "▌",
+		point: "●", vertical: "│", axis: "┤", corner: "└", baseline: "─",
+		indent: "· ", branch: "└ ", to: "–", dash: "—",
+	}
+	asciiGlyphs = glyphs{
+		spark: []rune("_.-=+*#@"), full: "#", half: "+",
+		point: "*", vertical: "|", axis: "|", corner: "+", baseline: "-",
+		indent: ". ", branch: "`- ", to: "-", dash: "-",
+	}
+)
+
+func currentGlyphs() glyphs {
+	if ASCII {
+		return asciiGlyphs
+	}
+	return unicodeGlyphs
+}
+
+// TreePrefix marks a tree row at depth (1 for direct children) with visible
+// characters, since the table renderer trims leading spaces.
+func TreePrefix(depth int) string {
+	if depth <= 0 {
+		return ""
+	}
+	g := currentGlyphs()
+	return strings.Repeat(g.indent, depth-1) + g.branch
+}
+
+// PrepareConsole readies the terminal for output and reports whether it can
+// show ANSI colors and Unicode glyphs. On Windows it enables virtual terminal
+// processing and UTF-8 output; legacy consoles that refuse return false.
+func PrepareConsole() bool {
+	return prepareConsole()
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package output
+
+func prepareConsole() bool {
+	return true
+}
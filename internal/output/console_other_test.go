@@ -0,0 +1,11 @@
+//go:build !windows
+
+package output
+
+import "testing"
+
+func TestPrepareConsole(t *testing.T) {
+	if !PrepareConsole() {
+		t.Error("PrepareConsole() = false, want true outside Windows")
+	}
+}
@@ -0,0 +1,47 @@
+package output
+
+import "testing"
+
+func TestTreePrefix(t *testing.T) {
+	tests := []struct {
+		depth int
+		ascii bool
+		want  string
+	}{
+		{0, false, ""},
+		{-1, false, ""},
+		{1, false, "└ "},
+		{3, false, "· · └ "},
+		{0, true, ""},
+		{1, true, "`- "},
+		{3, true, ". . `- "},
+	}
+	defer func(prev bool) { ASCII = prev }(ASCII)
+	for _, tt := range tests {
+		ASCII = tt.ascii
+		if got := TreePrefix(tt.depth); got != tt.want {
+			t.Errorf("TreePrefix(%d) with ASCII=%v = %q, want %q", tt.depth, tt.ascii, got, tt.want)
+		}
+	}
+}
+
+func TestCurrentGlyphs(t *testing.T) {
+	defer func(prev bool) { ASCII = prev }(ASCII)
+	for _, ascii := range []bool{false, true} {
+		ASCII = ascii
+		g := currentGlyphs()
+		if len(g.spark) != 8 {
+			t.Errorf("ASCII=%v: %d spark levels, want 8", ascii, len(g.spark))
+		}
+		if !ascii {
+			continue
+		}
+		for _, s := range []string{string(g.spark), g.full, g.half, g.point, g.vertical, g.axis, g.corner, g.baseline, g.indent, g.branch, g.to, g.dash} {
+			for _, r := range s {
+				if r > 127 {
+					t.Errorf("ASCII glyph %q is not ASCII", s)
+				}
+			}
+		}
+	}
+}
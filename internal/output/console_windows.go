@@ -0,0 +1,26 @@
+//go:build windows
+
+package output
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+const cpUTF8 = 65001
+
+func prepareConsole() bool {
+	h := windows.Handle(os.Stdout.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(h, &mode); err != nil {
+		// Not a console (redirected, or a Cygwin/MSYS pty): leave it alone.
+		return true
+	}
+	if err := windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+		// Pre-Windows 10 conhost: no ANSI sequences, raster fonts.
+		return false
+	}
+	_ = windows.SetConsoleOutputCP(cpUTF8)
+	return true
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package output
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPrepareConsoleRedirected(t *testing.T) {
+	// Under go test, stdout is a pipe rather than a console, which
+	// PrepareConsole must leave alone and report as capable.
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	defer func(prev *os.File) { os.Stdout = prev }(os.Stdout)
+	os.Stdout = f
+	if !PrepareConsole() {
+		t.Error("PrepareConsole() = false for redirected output, want true")
+	}
+}
@@ -0,0 +1,219 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CSV money modes: separate amount and currency columns, one formatted cell
+// such as $12.50, or the bare amount.
+const (
+	CurrencySplit  = "split"
+	CurrencySymbol = "symbol"
+	CurrencyPlain  = "plain"
+)
+
+// CSVOptions controls how CSV output types values for spreadsheets.
+type CSVOptions struct {
+	// Currency is the money mode: CurrencySplit (the default),
+	// CurrencySymbol, or CurrencyPlain.
+	Currency string
+	// DateLayout, if set, rewrites DATE cells with this Go time layout.
+	DateLayout string
+}
+
+// CSV is the CSV output setup (see --csv-currency-mode and --date-format).
+var CSV CSVOptions
+
+// DateLayouts are the named --date-format presets.
+var DateLayouts = map[string]string{
+	"iso": "2006-01-02",
+	"us":  "01/02/2006",
+	"eu":  "02/01/2006",
+}
+
+// apiDateLayouts are the forms of the dates in report granularity rows.
+var apiDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05.000",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01",
+}
+
+// CSVFormatter prints a header row and one comma-separated row per item,
+// quoted as RFC 4180 requires. Numbers are never localized.
+type CSVFormatter struct{}
+
+func (f *CSVFormatter) Format(data interface{}, columns []Column) error {
+	val := asSlice(data)
+	money := make([]bool, len(columns))
+	elem := val.Type().Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Struct {
+		for i, col := range columns {
+			if sf, ok := elem.FieldByName(col.Field); ok {
+				money[i] = isMoneyType(sf.Type)
+			}
+		}
+	}
+
+	var headers []string
+	for i, col := range columns {
+		headers = append(headers, moneyHeaders(col.Header, money[i])...)
+	}
+	var rows [][]string
+	for i := 0; i < val.Len(); i++ {
+		item := val.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		var row []string
+		for j, col := range columns {
+			if money[j] {
+				amount, currency := moneyField(item, col.Field)
+				row = append(row, moneyCells(amount, currency)...)
+				continue
+			}
+			row = append(row, csvCell(col.Header, getFieldValue(item, col.Field)))
+		}
+		rows = append(rows, row)
+	}
+	return writeCSV(headers, rows)
+}
+
+// writeCSVGrid writes preformatted grid cells. Money columns are recognized
+// by a currency code in the header, e.g. "SPEND USD".
+func writeCSVGrid(headers []string, rows [][]string) error {
+	var outHeaders []string
+	currencies := make([]string, len(headers))
+	for i, h := range headers {
+		name, currency, ok := moneyHeader(h)
+		if !ok || CSV.Currency == CurrencyPlain {
+			outHeaders = append(outHeaders, h)
+			continue
+		}
+		currencies[i] = currency
+		outHeaders = append(outHeaders, moneyHeaders(name, true)...)
+	}
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		for j, v := range row {
+			if j < len(headers) && currencies[j] != "" {
+				out[i] = append(out[i], moneyCells(v, currencies[j])...)
+				continue
+			}
+			header := ""
+			if j < len(headers) {
+				header = headers[j]
+			}
+			out[i] = append(out[i], csvCell(header, v))
+		}
+	}
+	return writeCSV(outHeaders, out)
+}
+
+func writeCSV(headers []string, rows [][]string) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(headers); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	return nil
+}
+
+// moneyHeaders returns the column headers of a money column: two in split
+// mode.
+func moneyHeaders(header string, money bool) []string {
+	if money && CSV.Currency != CurrencySymbol && CSV.Currency != CurrencyPlain {
+		return []string{header, header + " CURRENCY"}
+	}
+	return []string{header}
+}
+
+// moneyCells returns the cells of an amount in the configured money mode.
+func moneyCells(amount, currency string) []string {
+	switch CSV.Currency {
+	case CurrencySymbol:
+		if amount == "" {
+			return []string{""}
+		}
+		return []string{NumberFormat{Currency: "before"}.Money("", amount, currency)}
+	case CurrencyPlain:
+		return []string{amount}
+	}
+	if amount == "" {
+		currency = ""
+	}
+	return []string{amount, currency}
+}
+
+// csvCell reformats DATE cells with CSV.DateLayout; other cells are written
+// as they are.
+func csvCell(header, v string) string {
+	if CSV.DateLayout == "" || !strings.EqualFold(header, "DATE") {
+		return v
+	}
+	for _, layout := range apiDateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.Format(CSV.DateLayout)
+		}
+	}
+	return v
+}
+
+// isMoneyType reports whether t is a Money-like struct (or pointer to one)
+// with string Amount and Currency fields.
+func isMoneyType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	amount, ok1 := t.FieldByName("Amount")
+	currency, ok2 := t.FieldByName("Currency")
+	return ok1 && ok2 && amount.Type.Kind() == reflect.String && currency.Type.Kind() == reflect.String
+}
+
+// moneyField returns the amount and currency of a Money field, or empty
+// strings when it's unset.
+func moneyField(item reflect.Value, field string) (string, string) {
+	if item.Kind() != reflect.Struct {
+		return "", ""
+	}
+	v := item.FieldByName(field)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", ""
+		}
+		v = v.Elem()
+	}
+	return v.FieldByName("Amount").String(), v.FieldByName("Currency").String()
+}
+
+// moneyHeader splits a grid header such as "SPEND USD" into its metric name
+// and currency code.
+func moneyHeader(header string) (name, currency string, ok bool) {
+	words := strings.Fields(header)
+	if len(words) != 2 {
+		return "", "", false
+	}
+	code := words[1]
+	if len(code) != 3 || code != strings.ToUpper(code) || strings.ContainsAny(code, "0123456789") {
+		return "", "", false
+	}
+	return words[0], code, true
+}
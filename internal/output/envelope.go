@@ -0,0 +1,51 @@
+package output
+
+import (
+	"reflect"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// Envelope is the JSON shape of every result when Enveloped is set, so that
+// consumers parse one shape whatever the command.
+type Envelope struct {
+	Data       interface{}        `json:"data"`
+	Pagination *models.PageDetail `json:"pagination"`
+	Request    RequestMeta        `json:"request"`
+	Warnings   []string           `json:"warnings"`
+}
+
+// RequestMeta summarizes the API requests behind a result. Endpoint is the
+// last request, e.g. "PUT /campaigns/123", and empty when none was sent.
+type RequestMeta struct {
+	Endpoint   string `json:"endpoint"`
+	Calls      int    `json:"calls"`
+	DurationMS int64  `json:"duration_ms"`
+	Retries    int    `json:"retries"`
+}
+
+// Enveloped wraps JSON output in an Envelope.
+var Enveloped bool
+
+// EnvelopeMeta, if set, supplies the request summary and warnings of an
+// envelope.
+var EnvelopeMeta func() (RequestMeta, []string)
+
+func envelope(data interface{}, page *models.PageDetail) Envelope {
+	e := Envelope{Data: emptyIfNil(data), Pagination: page, Warnings: []string{}}
+	if EnvelopeMeta != nil {
+		e.Request, e.Warnings = EnvelopeMeta()
+		if e.Warnings == nil {
+			e.Warnings = []string{}
+		}
+	}
+	return e
+}
+
+// emptyIfNil turns a nil slice into an empty one, so it encodes as [].
+func emptyIfNil(data interface{}) interface{} {
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
@@ -10,6 +10,9 @@ type Format string
 const (
 	FormatJSON  Format = "json"
 	FormatTable Format = "table"
+	FormatIDs   Format = "ids"
+	FormatTSV   Format = "tsv"
+	FormatCSV   Format = "csv"
 )
 
 type Formatter interface {
@@ -28,15 +31,101 @@ func NewFormatter(format Format) Formatter {
 		return &JSONFormatter{}
 	case FormatTable:
 		return &TableFormatter{}
+	case FormatIDs:
+		return &IDsFormatter{Null: NullDelimited}
+	case FormatTSV:
+		return &TSVFormatter{Null: NullDelimited}
+	case FormatCSV:
+		return &CSVFormatter{}
 	default:
 		return &TableFormatter{}
 	}
 }
 
+// Refine, if set, filters and sorts data before it is printed (see --where
+// and --client-sort).
+var Refine func(data interface{}) (interface{}, error)
+
+// refineErr is the first Refine failure since Err was last called.
+var refineErr error
+
+// Err returns the first error refining printed data since it was last
+// called, and clears it. Data that can't be refined isn't printed, so the
+// command should fail with this error.
+func Err() error {
+	err := refineErr
+	refineErr = nil
+	return err
+}
+
 func Print(format Format, data interface{}, columns []Column) {
-	f := NewFormatter(format)
+	data, ok := refine(data)
+	if !ok {
+		return
+	}
+	render(NewFormatter(format), data, columns)
+}
+
+// refine applies Refine to data. On failure it records the error for Err and
+// reports false.
+func refine(data interface{}) (interface{}, bool) {
+	if Refine == nil {
+		return data, true
+	}
+	refined, err := Refine(data)
+	if err != nil {
+		if refineErr == nil {
+			refineErr = fmt.Errorf("filtering output: %w", err)
+		}
+		return nil, false
+	}
+	return refined, true
+}
+
+func render(f Formatter, data interface{}, columns []Column) {
 	if err := f.Format(data, columns); err != nil {
 		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// PrintGrid prints rows of preformatted cells, for output that doesn't map
+// onto a struct per row (e.g. reports with a chosen set of metrics). JSON is
+// left to the caller; ids output prints the first column. Cells should hold
+// raw numbers, which tables localize (see Numbers).
+func PrintGrid(format Format, headers []string, rows [][]string) {
+	var err error
+	switch format {
+	case FormatTSV:
+		err = writeTSV(headers, rows, NullDelimited)
+	case FormatCSV:
+		err = writeCSVGrid(headers, rows)
+	case FormatIDs:
+		for _, row := range rows {
+			if len(row) == 0 || row[0] == "" {
+				continue
+			}
+			if NullDelimited {
+				fmt.Print(row[0] + "\x00")
+			} else {
+				fmt.Println(row[0])
+			}
+		}
+	default:
+		localized := make([][]string, len(rows))
+		for i, row := range rows {
+			localized[i] = make([]string, len(row))
+			for j, v := range row {
+				if j < len(headers) {
+					v = Numbers.cell(headers[j], v)
+				}
+				localized[i][j] = v
+			}
+		}
+		err = writeTable(headers, localized)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// IDsFormatter prints only the ID of each item, one per line (or
+// NUL-terminated with Null), for use in shell pipelines. Items without an ID
+// field fall back to the first column.
+type IDsFormatter struct {
+	Null bool
+}
+
+func (f *IDsFormatter) Format(data interface{}, columns []Column) error {
+	val := reflect.ValueOf(data)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Slice {
+		slice := reflect.MakeSlice(reflect.SliceOf(val.Type()), 1, 1)
+		slice.Index(0).Set(val)
+		val = slice
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		item := val.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+
+		field := "ID"
+		if item.Kind() == reflect.Struct && !item.FieldByName(field).IsValid() && len(columns) > 0 {
+			field = columns[0].Field
+		}
+		if id := getFieldValue(item, field); id != "" {
+			if f.Null {
+				fmt.Print(id + "\x00")
+			} else {
+				fmt.Println(id)
+			}
+		}
+	}
+	return nil
+}
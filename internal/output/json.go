@@ -6,9 +6,27 @@ import (
 	"os"
 )
 
+// JSONFormatter prints data as indented JSON, in an Envelope when Enveloped
+// is set.
 type JSONFormatter struct{}
 
 func (f *JSONFormatter) Format(data interface{}, columns []Column) error {
+	if Enveloped {
+		return writeJSON(envelope(data, nil))
+	}
+	return writeJSON(data)
+}
+
+// PrintDocument prints data as JSON without an envelope, for documents that
+// are saved as files, such as schemas.
+func PrintDocument(data interface{}) {
+	if err := writeJSON(data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func writeJSON(data interface{}) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	if err := enc.Encode(data); err != nil {
@@ -0,0 +1,218 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat is how tables show numbers. The zero value prints them as Go
+// does, with a "." decimal point and no grouping. JSON, TSV, CSV, and ID
+// output, and files written with --csv, always keep raw numbers.
+type NumberFormat struct {
+	Thousands string
+	Decimal   string
+	// Decimals fixes the decimal places by metric or field name (e.g. "spend",
+	// "ttr") or by kind: "money", "percent", or "number".
+	Decimals map[string]int
+	// Currency places a money value's currency: "code" (12.50 USD, the
+	// default), "before" ($12.50), or "after" (12,50 €).
+	Currency string
+}
+
+// Numbers is the number format of table output (see --locale).
+var Numbers NumberFormat
+
+// locales are the built-in --locale presets.
+var locales = map[string]NumberFormat{
+	"c":     {},
+	"en-us": {Thousands: ",", Decimal: ".", Currency: "before"},
+	"en-gb": {Thousands: ",", Decimal: ".", Currency: "before"},
+	"en-au": {Thousands: ",", Decimal: ".", Currency: "code"},
+	"en-ca": {Thousands: ",", Decimal: ".", Currency: "code"},
+	"de-de": {Thousands: ".", Decimal: ",", Currency: "after"},
+	"de-ch": {Thousands: "’", Decimal: ".", Currency: "code"},
+	"fr-fr": {Thousands: " ", Decimal: ",", Currency: "after"},
+	"es-es": {Thousands: ".", Decimal: ",", Currency: "after"},
+	"it-it": {Thousands: ".", Decimal: ",", Currency: "after"},
+	"nl-nl": {Thousands: ".", Decimal: ",", Currency: "before"},
+	"pt-br": {Thousands: ".", Decimal: ",", Currency: "before"},
+	"ja-jp": {Thousands: ",", Decimal: ".", Currency: "before"},
+}
+
+// languages maps a bare language to its preset.
+var languages = map[string]string{
+	"en": "en-us", "de": "de-de", "fr": "fr-fr", "es": "es-es",
+	"it": "it-it", "nl": "nl-nl", "pt": "pt-br", "ja": "ja-jp",
+}
+
+// currencySymbols are the symbols shown for "before" and "after" placement;
+// other currencies keep their code.
+var currencySymbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "CNY": "¥", "INR": "₹",
+	"KRW": "₩", "BRL": "R$", "AUD": "A$", "CAD": "CA$", "CHF": "CHF",
+}
+
+// Locale returns the preset for a locale such as "de-DE", "de_DE", or "de".
+func Locale(name string) (NumberFormat, error) {
+	key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"))
+	if i := strings.IndexByte(key, '.'); i >= 0 {
+		key = key[:i] // de_DE.UTF-8
+	}
+	if key == "posix" {
+		key = "c"
+	}
+	if full, ok := languages[key]; ok {
+		key = full
+	}
+	f, ok := locales[key]
+	if !ok {
+		return NumberFormat{}, fmt.Errorf("unknown locale %q (expected one of %s)", name, strings.Join(LocaleNames(), ", "))
+	}
+	return f, nil
+}
+
+// LocaleNames lists the built-in locales.
+func LocaleNames() []string {
+	var names []string
+	for k := range locales {
+		if k == "c" {
+			names = append(names, "C")
+			continue
+		}
+		names = append(names, k[:3]+strings.ToUpper(k[3:]))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// places returns the decimal places set for a metric or field name, falling
+// back to its kind; -1 keeps the value's own.
+func (f NumberFormat) places(name, kind string) int {
+	if n, ok := f.Decimals[strings.ToLower(name)]; ok {
+		return n
+	}
+	if n, ok := f.Decimals[kind]; ok {
+		return n
+	}
+	return -1
+}
+
+// Number formats a decimal string, rounding it to places unless places is
+// negative. A string that isn't a number is returned unchanged.
+func (f NumberFormat) Number(s string, places int) string {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	if places >= 0 {
+		s = strconv.FormatFloat(v, 'f', places, 64)
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+		if sign == "+" {
+			sign = ""
+		}
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if f.Thousands != "" && len(whole) > 3 {
+		var b strings.Builder
+		for i, d := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(f.Thousands)
+			}
+			b.WriteRune(d)
+		}
+		whole = b.String()
+	}
+	if !hasFrac {
+		return sign + whole
+	}
+	dec := f.Decimal
+	if dec == "" {
+		dec = "."
+	}
+	return sign + whole + dec + frac
+}
+
+// Money formats an amount with its currency placed as configured.
+func (f NumberFormat) Money(name, amount, currency string) string {
+	n := f.Number(amount, f.places(name, "money"))
+	symbol, ok := currencySymbols[currency]
+	switch {
+	case currency == "":
+		return n
+	case f.Currency == "before" && ok:
+		if strings.HasPrefix(n, "-") {
+			return "-" + symbol + n[1:]
+		}
+		return symbol + n
+	case f.Currency == "after" && ok:
+		return n + " " + symbol
+	}
+	return n + " " + currency
+}
+
+// cell formats a preformatted grid cell under header: numbers and
+// percentages are localized, except in identifier, name, and date columns.
+func (f NumberFormat) cell(header, s string) string {
+	h := strings.ToUpper(header)
+	if h == "ID" || h == "NAME" || h == "DATE" || strings.HasSuffix(h, " ID") || strings.HasSuffix(h, "_ID") {
+		return s
+	}
+	name, kind := strings.ToLower(strings.Fields(header + " _")[0]), "number"
+	if _, _, ok := moneyHeader(header); ok {
+		kind = "money" // e.g. "SPEND USD"
+	}
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		if _, err := strconv.ParseFloat(pct, 64); err == nil {
+			return f.Number(pct, f.places(name, "percent")) + "%"
+		}
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return s
+	}
+	return f.Number(s, f.places(name, kind))
+}
+
+// fieldValue formats a struct field for a table: floats, counts, and money
+// follow Numbers; identifiers and everything else are left to getFieldValue.
+func (f NumberFormat) fieldValue(item reflect.Value, field string) string {
+	if item.Kind() == reflect.Ptr {
+		if item.IsNil() {
+			return ""
+		}
+		item = item.Elem()
+	}
+	if item.Kind() != reflect.Struct {
+		return getFieldValue(item, field)
+	}
+	v := item.FieldByName(field)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return f.Number(strconv.FormatFloat(v.Float(), 'f', -1, 64), f.places(field, "number"))
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if isIdentifier(field) {
+			break
+		}
+		return f.Number(strconv.FormatInt(v.Int(), 10), -1)
+	case reflect.Struct:
+		amount, currency := v.FieldByName("Amount"), v.FieldByName("Currency")
+		if amount.IsValid() && currency.IsValid() && amount.Kind() == reflect.String && currency.Kind() == reflect.String {
+			return f.Money(field, amount.String(), currency.String())
+		}
+	}
+	return getFieldValue(item, field)
+}
+
+// isIdentifier reports whether a field holds an ID, which is never grouped.
+func isIdentifier(field string) bool {
+	return strings.HasSuffix(field, "ID") || strings.HasSuffix(field, "Id")
+}
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// pageEnvelope is the JSON shape of a paged list, matching the API's own.
+type pageEnvelope struct {
+	Data       interface{}        `json:"data"`
+	Pagination *models.PageDetail `json:"pagination"`
+}
+
+// PrintPage prints one page of a list. JSON output wraps the items as
+// {"data": [...], "pagination": {...}}; table output is followed by a hint on
+// stderr naming the --page that comes next. A nil page means data is the
+// whole result set.
+func PrintPage(format Format, data interface{}, page *models.PageDetail, pageSize int, columns []Column) {
+	shown := lenOf(data)
+	if page == nil {
+		page = &models.PageDetail{TotalResults: shown, ItemsPerPage: shown}
+	}
+	data, ok := refine(data)
+	if !ok {
+		return
+	}
+
+	switch format {
+	case FormatJSON:
+		if Enveloped {
+			PrintDocument(envelope(data, page))
+			return
+		}
+		PrintDocument(pageEnvelope{Data: emptyIfNil(data), Pagination: page})
+	case FormatTable:
+		render(NewFormatter(format), data, columns)
+		if hint := PageHint(page, shown, pageSize); hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
+	default:
+		render(NewFormatter(format), data, columns)
+	}
+}
+
+// PageHint describes which results a page holds, e.g.
+// "showing 51–100 of 1,243 — use --page 3". It is empty when the page holds
+// every result.
+func PageHint(page *models.PageDetail, shown, pageSize int) string {
+	if page == nil || shown == 0 || (page.StartIndex == 0 && shown >= page.TotalResults) {
+		return ""
+	}
+	g := currentGlyphs()
+	last := page.StartIndex + shown
+	hint := fmt.Sprintf("showing %s%s%s of %s", groupDigits(page.StartIndex+1), g.to, groupDigits(last), groupDigits(page.TotalResults))
+	if last < page.TotalResults && pageSize > 0 {
+		hint += fmt.Sprintf(" %s use --page %d", g.dash, last/pageSize+1)
+	}
+	return hint
+}
+
+func lenOf(data interface{}) int {
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return 1
+	}
+	return v.Len()
+}
+
+// groupDigits formats n with thousands separators.
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + groupDigits(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Pager is the command tables taller than the terminal are shown through,
+// e.g. "less", when stdout is a terminal. Empty prints them directly.
+var Pager string
+
+// showTable writes a rendered table, through Pager if it doesn't fit on the
+// screen. If the pager can't be started, the table is printed directly.
+func showTable(out []byte) error {
+	if Pager != "" && stdoutIsTerminal() {
+		if height := terminalHeight(); height > 0 && bytes.Count(out, []byte("\n")) >= height {
+			if err := runPager(Pager, out); err == nil {
+				return nil
+			}
+		}
+	}
+	_, err := os.Stdout.Write(out)
+	return err
+}
+
+// runPager shows out through command. It fails only if the pager couldn't be
+// started.
+func runPager(command string, out []byte) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return exec.ErrNotFound
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(out)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Like git: let less pass colors through and leave the table on screen
+	// when it quits, unless the user configured it.
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// The pager has the table; quitting it before the end isn't an error.
+	cmd.Wait()
+	return nil
+}
+
+// linesFallback is the terminal height from $LINES, for terminals the
+// platform can't be asked about.
+func linesFallback() int {
+	n, _ := strconv.Atoi(os.Getenv("LINES"))
+	return n
+}
+
+func stdoutIsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
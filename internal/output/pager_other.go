@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package output
+
+// terminalHeight is the terminal height from $LINES, or 0 if unknown.
+func terminalHeight() int {
+	return linesFallback()
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package output
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalHeight is the number of rows of the terminal on stdout, or 0 if
+// unknown.
+func terminalHeight() int {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Row == 0 {
+		return linesFallback()
+	}
+	return int(ws.Row)
+}
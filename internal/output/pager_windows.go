@@ -0,0 +1,19 @@
+//go:build windows
+
+package output
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// terminalHeight is the number of rows of the console window on stdout, or 0
+// if unknown.
+func terminalHeight() int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(os.Stdout.Fd()), &info); err != nil {
+		return linesFallback()
+	}
+	return int(info.Window.Bottom-info.Window.Top) + 1
+}
@@ -0,0 +1,27 @@
+package output
+
+// Sparkline renders values as a one-line bar chart scaled between their min and max.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	sparkTicks := currentGlyphs().spark
+	out := make([]rune, len(values))
+	for i, v := range values {
+		idx := 0
+		if max > min {
+			idx = int((v - min) / (max - min) * float64(len(sparkTicks)-1))
+		}
+		out[i] = sparkTicks[idx]
+	}
+	return string(out)
+}
@@ -1,8 +1,8 @@
 package output
 
 import (
+	"bytes"
 	"fmt"
-	"os"
 	"reflect"
 
 	"github.com/olekukonko/tablewriter"
@@ -11,50 +11,72 @@ import (
 type TableFormatter struct{}
 
 func (f *TableFormatter) Format(data interface{}, columns []Column) error {
-	val := reflect.ValueOf(data)
-
-	// Handle pointer
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
-
-	// If it's not a slice, wrap it
-	if val.Kind() != reflect.Slice {
-		slice := reflect.MakeSlice(reflect.SliceOf(val.Type()), 1, 1)
-		slice.Index(0).Set(val)
-		val = slice
-	}
+	headers, rows := cells(data, columns, true)
+	return writeTable(headers, rows)
+}
 
-	if val.Len() == 0 {
+func writeTable(headers []string, rows [][]string) error {
+	if len(rows) == 0 {
 		fmt.Println("No results found.")
 		return nil
 	}
+	var buf bytes.Buffer
+	table := tablewriter.NewTable(&buf)
+	table.Header(headers)
+	for _, row := range rows {
+		table.Append(row)
+	}
+	table.Render()
+	return showTable(buf.Bytes())
+}
 
-	table := tablewriter.NewTable(os.Stdout)
-
-	// Set headers
+// cells renders data as a header row plus one row of strings per item. With
+// localize, numbers follow Numbers instead of being printed raw.
+func cells(data interface{}, columns []Column, localize bool) ([]string, [][]string) {
 	headers := make([]string, len(columns))
 	for i, col := range columns {
 		headers[i] = col.Header
 	}
-	table.Header(headers)
 
-	// Fill rows
+	val := asSlice(data)
+	var rows [][]string
 	for i := 0; i < val.Len(); i++ {
 		item := val.Index(i)
 		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
 			item = item.Elem()
 		}
-
 		row := make([]string, len(columns))
 		for j, col := range columns {
-			row[j] = getFieldValue(item, col.Field)
+			if localize {
+				row[j] = Numbers.fieldValue(item, col.Field)
+			} else {
+				row[j] = getFieldValue(item, col.Field)
+			}
 		}
-		table.Append(row)
+		rows = append(rows, row)
 	}
+	return headers, rows
+}
 
-	table.Render()
-	return nil
+// asSlice returns data as a slice value, wrapping a single item.
+func asSlice(data interface{}) reflect.Value {
+	val := reflect.ValueOf(data)
+
+	// Handle pointer
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	// If it's not a slice, wrap it
+	if val.Kind() != reflect.Slice {
+		slice := reflect.MakeSlice(reflect.SliceOf(val.Type()), 1, 1)
+		slice.Index(0).Set(val)
+		val = slice
+	}
+	return val
 }
 
 func getFieldValue(v reflect.Value, field string) string {
@@ -0,0 +1,55 @@
+package output
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// NullDelimited makes TSV and ID output end every field with a NUL byte
+// instead of using tabs and newlines, for xargs -0 and similar tools.
+var NullDelimited bool
+
+// tsvEscaper keeps each TSV value on one line and in one field.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// TSVFormatter prints a header row and one tab-separated row per item.
+// Backslashes, tabs, and line breaks in values are escaped as \\, \t, \n,
+// and \r. With Null, the header is omitted and every field is written
+// unescaped and NUL-terminated, so each item is len(columns) fields.
+type TSVFormatter struct {
+	Null bool
+}
+
+func (f *TSVFormatter) Format(data interface{}, columns []Column) error {
+	headers, rows := cells(data, columns, false)
+	return writeTSV(headers, rows, f.Null)
+}
+
+func writeTSV(headers []string, rows [][]string, null bool) error {
+	w := bufio.NewWriter(os.Stdout)
+	if !null {
+		writeTSVRow(w, headers)
+	}
+	for _, row := range rows {
+		if null {
+			for _, v := range row {
+				w.WriteString(v)
+				w.WriteByte(0)
+			}
+			continue
+		}
+		writeTSVRow(w, row)
+	}
+	return w.Flush()
+}
+
+func writeTSVRow(w *bufio.Writer, row []string) {
+	for i, v := range row {
+		if i > 0 {
+			w.WriteByte('\t')
+		}
+		w.WriteString(tsvEscaper.Replace(v))
+	}
+	w.WriteByte('\n')
+}
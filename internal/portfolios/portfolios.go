@@ -0,0 +1,107 @@
+// Package portfolios keeps local, named groups of campaigns so reports and
+// budgets can be viewed per group. Apple Search Ads campaign groups are not
+// exposed in the API, so the groups live in the CLI's config directory.
+package portfolios
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Portfolio groups campaigns either by a fixed list of IDs or dynamically by
+// a local tag or campaign filter conditions, resolved each time it is used.
+type Portfolio struct {
+	Name        string   `json:"name"`
+	CampaignIDs []int64  `json:"campaignIds,omitempty"`
+	Tag         string   `json:"tag,omitempty"`
+	Filters     []string `json:"filters,omitempty"`
+}
+
+// Source describes how the portfolio's campaigns are chosen.
+func (p Portfolio) Source() string {
+	switch {
+	case p.Tag != "":
+		return "tag " + p.Tag
+	case len(p.Filters) > 0:
+		return "filter " + strings.Join(p.Filters, " ")
+	}
+	return fmt.Sprintf("%d campaign(s)", len(p.CampaignIDs))
+}
+
+// Store holds the portfolios of one profile, keyed by name.
+type Store struct {
+	Portfolios map[string]Portfolio `json:"portfolios"`
+
+	path string
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Path returns the portfolio file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("portfolios", profile)
+}
+
+// Load reads the portfolios of a profile. A missing file yields an empty store.
+func Load(profile string) (*Store, error) {
+	s := &Store{Portfolios: map[string]Portfolio{}, path: Path(profile)}
+	if err := config.LoadJSON(s.path, "portfolios", s); err != nil {
+		return nil, err
+	}
+	if s.Portfolios == nil {
+		s.Portfolios = map[string]Portfolio{}
+	}
+	return s, nil
+}
+
+// Normalize lowercases and trims a portfolio name.
+func Normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Get returns a portfolio by name.
+func (s *Store) Get(name string) (Portfolio, error) {
+	p, ok := s.Portfolios[Normalize(name)]
+	if !ok {
+		return Portfolio{}, fmt.Errorf("portfolio %q not found", Normalize(name))
+	}
+	return p, nil
+}
+
+// Put adds or replaces a portfolio and saves the store.
+func (s *Store) Put(p Portfolio) error {
+	p.Name = Normalize(p.Name)
+	if !validName.MatchString(p.Name) {
+		return fmt.Errorf("invalid portfolio name %q: use letters, digits, '.', '_' or '-'", p.Name)
+	}
+	s.Portfolios[p.Name] = p
+	return s.save()
+}
+
+// Delete removes a portfolio and saves the store.
+func (s *Store) Delete(name string) error {
+	name = Normalize(name)
+	if _, ok := s.Portfolios[name]; !ok {
+		return fmt.Errorf("portfolio %q not found", name)
+	}
+	delete(s.Portfolios, name)
+	return s.save()
+}
+
+// List returns the portfolios ordered by name.
+func (s *Store) List() []Portfolio {
+	out := make([]Portfolio, 0, len(s.Portfolios))
+	for _, p := range s.Portfolios {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (s *Store) save() error {
+	return config.SaveJSON(s.path, "portfolios", s)
+}
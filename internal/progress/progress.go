@@ -0,0 +1,151 @@
+// Package progress draws progress bars and spinners on stderr for long
+// operations such as report fan-outs, bulk updates, and --all pagination.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Enabled turns progress output on. It is off by default so library users
+// (pkg/asa, the REST server) never see it; the CLI enables it when stderr is a
+// terminal and --no-progress is not set.
+var Enabled bool
+
+// IsTerminal reports whether stderr is a terminal.
+func IsTerminal() bool {
+	fd := os.Stderr.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+const (
+	barWidth      = 30
+	redrawEvery   = 100 * time.Millisecond
+	spinnerFrames = `|/-\`
+)
+
+// Bar tracks progress toward a total. With an unknown total (0) it draws a
+// spinner with a running count instead. A Bar is safe for concurrent use and
+// all methods are no-ops when progress is disabled.
+type Bar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	label    string
+	total    int
+	done     int
+	start    time.Time
+	lastDraw time.Time
+	frame    int
+	width    int // longest line drawn, for clearing leftovers
+	active   bool
+}
+
+var (
+	shownMu sync.Mutex
+	shown   *Bar
+)
+
+// New starts a bar labeled label with the given total (0 if unknown). Only
+// one bar is drawn at a time: while a bar is showing (e.g. batch progress),
+// bars started by nested operations stay silent.
+func New(label string, total int) *Bar {
+	b := &Bar{w: os.Stderr, label: label, total: total, start: time.Now()}
+	shownMu.Lock()
+	if Enabled && shown == nil {
+		shown = b
+		b.active = true
+	}
+	shownMu.Unlock()
+	return b
+}
+
+// SetTotal updates the total once it becomes known.
+func (b *Bar) SetTotal(total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total = total
+	b.draw()
+}
+
+// Add advances the bar by n items.
+func (b *Bar) Add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done += n
+	b.draw()
+}
+
+// Tick redraws the bar without advancing it, to keep a spinner moving while
+// waiting.
+func (b *Bar) Tick() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.draw()
+}
+
+// Done clears the progress line.
+func (b *Bar) Done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.active {
+		return
+	}
+	fmt.Fprintf(b.w, "\r%s\r", strings.Repeat(" ", b.width))
+	b.active = false
+
+	shownMu.Lock()
+	if shown == b {
+		shown = nil
+	}
+	shownMu.Unlock()
+}
+
+func (b *Bar) draw() {
+	if !b.active {
+		return
+	}
+	now := time.Now()
+	if now.Sub(b.lastDraw) < redrawEvery && (b.total == 0 || b.done < b.total) {
+		return
+	}
+	b.lastDraw = now
+	b.frame++
+	line := b.line()
+	if len(line) > b.width {
+		b.width = len(line)
+	}
+	fmt.Fprintf(b.w, "\r%-*s", b.width, line)
+}
+
+func (b *Bar) line() string {
+	elapsed := time.Since(b.start).Round(time.Second)
+	if b.total <= 0 {
+		frame := spinnerFrames[b.frame%len(spinnerFrames)]
+		if b.done == 0 {
+			return fmt.Sprintf("%c %s (%s)", frame, b.label, elapsed)
+		}
+		return fmt.Sprintf("%c %s %d (%s)", frame, b.label, b.done, elapsed)
+	}
+
+	done := b.done
+	if done > b.total {
+		done = b.total
+	}
+	filled := barWidth * done / b.total
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+
+	eta := "--"
+	if done > 0 && done < b.total {
+		perItem := time.Since(b.start) / time.Duration(done)
+		eta = (perItem * time.Duration(b.total-done)).Round(time.Second).String()
+	} else if done == b.total {
+		eta = "0s"
+	}
+	return fmt.Sprintf("%s [%s] %d/%d ETA %s", b.label, bar, done, b.total, eta)
+}
@@ -0,0 +1,123 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortKey orders results by a dotted JSON path.
+type SortKey struct {
+	Path []string
+	Desc bool
+}
+
+// ParseSorts parses --client-sort values like "dailyBudgetAmount.amount:desc".
+func ParseSorts(specs []string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, spec := range specs {
+		field, dir, _ := strings.Cut(spec, ":")
+		if field == "" {
+			return nil, fmt.Errorf("invalid --client-sort %q: missing field", spec)
+		}
+		key := SortKey{Path: strings.Split(field, ".")}
+		switch strings.ToLower(dir) {
+		case "", "asc", "ascending":
+		case "desc", "descending":
+			key.Desc = true
+		default:
+			return nil, fmt.Errorf("invalid --client-sort %q: direction must be asc or desc", spec)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Apply filters a slice (or pointer to one) with where and orders it by
+// sorts, judging each item by its JSON form. The result has the same type as
+// data. Values that aren't slices are returned unchanged.
+func Apply(data interface{}, where *Expr, sorts []SortKey) (interface{}, error) {
+	if where == nil && len(sorts) == 0 {
+		return data, nil
+	}
+	val := reflect.ValueOf(data)
+	if val.Kind() == reflect.Ptr && !val.IsNil() && val.Elem().Kind() == reflect.Slice {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Slice {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(val.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var docs []interface{}
+	if err := json.Unmarshal(raw, &docs); err != nil || len(docs) != val.Len() {
+		return data, nil
+	}
+
+	var keep []int
+	for i, doc := range docs {
+		if where == nil || where.Match(doc) {
+			keep = append(keep, i)
+		}
+	}
+	if len(sorts) > 0 {
+		sort.SliceStable(keep, func(a, b int) bool {
+			return less(docs[keep[a]], docs[keep[b]], sorts)
+		})
+	}
+
+	out := reflect.MakeSlice(val.Type(), len(keep), len(keep))
+	for j, i := range keep {
+		out.Index(j).Set(val.Index(i))
+	}
+	return out.Interface(), nil
+}
+
+// less orders a before b by the first key on which they differ. Missing
+// values sort last in either direction.
+func less(a, b interface{}, keys []SortKey) bool {
+	for _, key := range keys {
+		av, aok := Lookup(a, key.Path)
+		bv, bok := Lookup(b, key.Path)
+		aok = aok && av != nil
+		bok = bok && bv != nil
+		switch {
+		case !aok && !bok:
+			continue
+		case !aok:
+			return false
+		case !bok:
+			return true
+		}
+		c := compareValues(scalarString(av), scalarString(bv))
+		if c == 0 {
+			continue
+		}
+		if key.Desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	return false
+}
+
+func compareValues(a, b string) int {
+	af, aerr := strconv.ParseFloat(a, 64)
+	bf, berr := strconv.ParseFloat(b, 64)
+	if aerr == nil && berr == nil {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -0,0 +1,344 @@
+// Package query filters and sorts decoded API results on the client, for
+// fields the API's selectors can't filter on.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed --where expression such as
+// `dailyBudgetAmount.amount > 100 and status = ENABLED`.
+//
+// Fields are dotted paths into an item's JSON form, matched case-insensitively.
+// Operators are = (or ==), !=, >, >=, <, <=, ~ (contains) and !~, combined with
+// and, or, not, and parentheses. Values compare as numbers when both sides are
+// numeric and as case-insensitive strings otherwise. A comparison against an
+// array field matches if any element does.
+type Expr struct {
+	src  string
+	root node
+}
+
+// String returns the expression as written.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Match reports whether doc, an item decoded from JSON, satisfies e.
+func (e *Expr) Match(doc interface{}) bool {
+	return e.root.eval(doc)
+}
+
+type node interface {
+	eval(doc interface{}) bool
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ inner node }
+
+type cmpNode struct {
+	path  []string
+	op    string
+	value string
+}
+
+func (n andNode) eval(doc interface{}) bool { return n.left.eval(doc) && n.right.eval(doc) }
+func (n orNode) eval(doc interface{}) bool  { return n.left.eval(doc) || n.right.eval(doc) }
+func (n notNode) eval(doc interface{}) bool { return !n.inner.eval(doc) }
+
+func (n cmpNode) eval(doc interface{}) bool {
+	v, ok := Lookup(doc, n.path)
+	if !ok || v == nil {
+		return n.op == "!=" || n.op == "!~"
+	}
+	if list, isList := v.([]interface{}); isList {
+		negated := n.op == "!=" || n.op == "!~"
+		for _, item := range list {
+			matched := compare(item, n.op, n.value)
+			if negated && !matched {
+				return false
+			}
+			if !negated && matched {
+				return true
+			}
+		}
+		return negated
+	}
+	return compare(v, n.op, n.value)
+}
+
+func compare(v interface{}, op, want string) bool {
+	got := scalarString(v)
+	switch op {
+	case "~":
+		return strings.Contains(strings.ToLower(got), strings.ToLower(want))
+	case "!~":
+		return !strings.Contains(strings.ToLower(got), strings.ToLower(want))
+	}
+
+	c := compareValues(got, want)
+	switch op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+// Lookup resolves a dotted path in a decoded JSON document. Keys match
+// case-insensitively.
+func Lookup(doc interface{}, path []string) (interface{}, bool) {
+	cur := doc
+	for _, key := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok := m[key]
+		if !ok {
+			for k, kv := range m {
+				if strings.EqualFold(k, key) {
+					v, ok = kv, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		cur = v
+	}
+	return cur, true
+}
+
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// Parse parses a --where expression.
+func Parse(src string) (*Expr, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --where %q: %w", src, err)
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid --where %q: %w", src, err)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// And combines expressions so that all must match. It returns nil for none.
+func And(exprs ...*Expr) *Expr {
+	var out *Expr
+	for _, e := range exprs {
+		if out == nil {
+			out = e
+			continue
+		}
+		out = &Expr{src: out.src + " and " + e.src, root: andNode{out.root, e.root}}
+	}
+	return out
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota // field, bare value, or keyword
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var operators = []string{"==", "!=", ">=", "<=", "!~", "&&", "||", "=", ">", "<", "~", "!"}
+
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "("})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")"})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, token{tokString, src[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					toks = append(toks, token{tokOp, op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			start := i
+			for i < len(src) && isWordChar(rune(src[i])) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected %q", string(c))
+			}
+			toks = append(toks, token{tokWord, src[start:i]})
+		}
+	}
+	return toks, nil
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._-+:/@", r) || r > unicode.MaxASCII
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+// keyword reports whether the next token is one of words (case-insensitive)
+// and consumes it.
+func (p *parser) keyword(words ...string) bool {
+	t, ok := p.peek()
+	if !ok || (t.kind != tokWord && t.kind != tokOp) {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and", "&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.keyword("not", "!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if t.kind == tokLParen {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokRParen {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	field, ok := p.peek()
+	if !ok || field.kind != tokWord {
+		return nil, fmt.Errorf("expected a field name")
+	}
+	p.pos++
+	op, ok := p.peek()
+	if !ok || op.kind != tokOp || op.text == "&&" || op.text == "||" || op.text == "!" {
+		return nil, fmt.Errorf("expected an operator after %q", field.text)
+	}
+	p.pos++
+	value, ok := p.peek()
+	if !ok || (value.kind != tokWord && value.kind != tokString) {
+		return nil, fmt.Errorf("expected a value after %q %s", field.text, op.text)
+	}
+	p.pos++
+
+	text := op.text
+	if text == "==" {
+		text = "="
+	}
+	return cmpNode{path: strings.Split(field.text, "."), op: text, value: value.text}, nil
+}
@@ -0,0 +1,82 @@
+// Package refs maps user-supplied external reference IDs to the Apple
+// Search Ads entities they created, so provisioning scripts can be re-run
+// without creating duplicates.
+package refs
+
+import (
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Entity kinds.
+const (
+	KindCampaign = "campaign"
+	KindAdGroup  = "adgroup"
+	KindKeyword  = "keyword"
+)
+
+// Ref links an external ID to an entity.
+type Ref struct {
+	Kind       string    `json:"kind"`
+	OrgID      string    `json:"orgId,omitempty"`
+	ExternalID string    `json:"externalId"`
+	ID         int64     `json:"id"`
+	CampaignID int64     `json:"campaignId,omitempty"`
+	AdGroupID  int64     `json:"adGroupId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Store is the per-profile mapping file.
+type Store struct {
+	Refs map[string]Ref `json:"refs"`
+
+	path string
+}
+
+// Path returns the mapping file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("external_ids", profile)
+}
+
+// Load reads the mapping for a profile. A missing file yields an empty store.
+func Load(profile string) (*Store, error) {
+	s := &Store{Refs: map[string]Ref{}, path: Path(profile)}
+	if err := config.LoadJSON(s.path, "external ID map", s); err != nil {
+		return nil, err
+	}
+	if s.Refs == nil {
+		s.Refs = map[string]Ref{}
+	}
+	return s, nil
+}
+
+func key(kind, orgID, externalID string) string {
+	return kind + "/" + orgID + "/" + externalID
+}
+
+// Lookup returns the entity recorded for an external ID.
+func (s *Store) Lookup(kind, orgID, externalID string) (Ref, bool) {
+	ref, ok := s.Refs[key(kind, orgID, externalID)]
+	return ref, ok
+}
+
+// Put records a mapping and saves the store.
+func (s *Store) Put(ref Ref) error {
+	if ref.CreatedAt.IsZero() {
+		ref.CreatedAt = time.Now().UTC()
+	}
+	s.Refs[key(ref.Kind, ref.OrgID, ref.ExternalID)] = ref
+	return s.save()
+}
+
+// Delete forgets a mapping (e.g. when the entity no longer exists) and saves
+// the store.
+func (s *Store) Delete(kind, orgID, externalID string) error {
+	delete(s.Refs, key(kind, orgID, externalID))
+	return s.save()
+}
+
+func (s *Store) save() error {
+	return config.SaveJSON(s.path, "external ID map", s)
+}
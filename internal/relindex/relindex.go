@@ -0,0 +1,204 @@
+// Package relindex keeps a local index of how a campaign's entities relate —
+// ad groups, targeting keywords, and negative keywords — so the impact of
+// changing one of them can be traced without refetching the campaign.
+package relindex
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// DefaultTTL is how long an indexed campaign is used before it is rebuilt.
+const DefaultTTL = time.Hour
+
+// Entity kinds.
+const (
+	KindCampaign         = "campaign"
+	KindAdGroup          = "adgroup"
+	KindKeyword          = "keyword"
+	KindCampaignNegative = "campaign-negative"
+	KindAdGroupNegative  = "adgroup-negative"
+)
+
+// Node is one indexed entity. Name is the text of keywords; Bid and Currency
+// are set for targeting keywords.
+type Node struct {
+	Kind      string `json:"kind"`
+	ID        int64  `json:"id"`
+	AdGroupID int64  `json:"adGroupId,omitempty"`
+	Name      string `json:"name"`
+	MatchType string `json:"matchType,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Bid       string `json:"bid,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+}
+
+// Campaign is the indexed relations of one campaign.
+type Campaign struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	IndexedAt time.Time `json:"indexedAt"`
+	AdGroups  []Node    `json:"adGroups"`
+	Keywords  []Node    `json:"keywords"`
+	Negatives []Node    `json:"negatives"`
+}
+
+// Index is a profile's indexed campaigns, by ID.
+type Index struct {
+	Campaigns map[int64]*Campaign `json:"campaigns"`
+
+	path string
+}
+
+// Path returns the index file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("relations", profile)
+}
+
+// Load reads a profile's index; a missing file is an empty index.
+func Load(profile string) (*Index, error) {
+	ix := &Index{Campaigns: map[int64]*Campaign{}, path: Path(profile)}
+	if err := config.LoadJSON(ix.path, "relation index", ix); err != nil {
+		return nil, err
+	}
+	if ix.Campaigns == nil {
+		ix.Campaigns = map[int64]*Campaign{}
+	}
+	return ix, nil
+}
+
+// Save writes the index.
+func (ix *Index) Save() error {
+	return config.SaveJSON(ix.path, "relation index", ix)
+}
+
+// Fresh returns a campaign indexed less than ttl ago.
+func (ix *Index) Fresh(campaignID int64, ttl time.Duration) (*Campaign, bool) {
+	c, ok := ix.Campaigns[campaignID]
+	if !ok || time.Since(c.IndexedAt) > ttl {
+		return nil, false
+	}
+	return c, true
+}
+
+// Put adds or replaces a campaign.
+func (ix *Index) Put(c *Campaign) {
+	ix.Campaigns[c.ID] = c
+}
+
+// FindKeyword returns the indexed campaign holding a targeting keyword.
+func (ix *Index) FindKeyword(id int64) (*Campaign, bool) {
+	ids := make([]int64, 0, len(ix.Campaigns))
+	for cid := range ix.Campaigns {
+		ids = append(ids, cid)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, cid := range ids {
+		if _, ok := ix.Campaigns[cid].Keyword(id); ok {
+			return ix.Campaigns[cid], true
+		}
+	}
+	return nil, false
+}
+
+// Keyword returns a targeting keyword of the campaign.
+func (c *Campaign) Keyword(id int64) (Node, bool) {
+	for _, k := range c.Keywords {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return Node{}, false
+}
+
+// AdGroup returns an ad group of the campaign.
+func (c *Campaign) AdGroup(id int64) (Node, bool) {
+	for _, ag := range c.AdGroups {
+		if ag.ID == id {
+			return ag, true
+		}
+	}
+	return Node{}, false
+}
+
+// InAdGroup returns the keywords and the ad group negatives of an ad group.
+func (c *Campaign) InAdGroup(adGroupID int64) (keywords, negatives []Node) {
+	for _, k := range c.Keywords {
+		if k.AdGroupID == adGroupID {
+			keywords = append(keywords, k)
+		}
+	}
+	for _, n := range c.Negatives {
+		if n.AdGroupID == adGroupID {
+			negatives = append(negatives, n)
+		}
+	}
+	return keywords, negatives
+}
+
+// CampaignNegatives returns the campaign-level negatives.
+func (c *Campaign) CampaignNegatives() []Node {
+	var out []Node
+	for _, n := range c.Negatives {
+		if n.Kind == KindCampaignNegative {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// Siblings returns the campaign's other keywords with the same text, in any
+// ad group or match type.
+func (c *Campaign) Siblings(kw Node) []Node {
+	var out []Node
+	term := Normalize(kw.Name)
+	for _, k := range c.Keywords {
+		if k.ID != kw.ID && Normalize(k.Name) == term {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Blocking returns the negatives that apply to the keyword's ad group and
+// match its text: exact negatives with the same text, and broad negatives
+// whose every word is in it.
+func (c *Campaign) Blocking(kw Node) []Node {
+	var out []Node
+	for _, n := range c.Negatives {
+		if n.Kind == KindAdGroupNegative && n.AdGroupID != kw.AdGroupID {
+			continue
+		}
+		if Matches(n, kw.Name) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// Matches reports whether a negative keyword matches text.
+func Matches(negative Node, text string) bool {
+	neg, term := Normalize(negative.Name), Normalize(text)
+	if strings.EqualFold(negative.MatchType, "EXACT") {
+		return neg == term
+	}
+	words := map[string]bool{}
+	for _, w := range strings.Fields(term) {
+		words[w] = true
+	}
+	for _, w := range strings.Fields(neg) {
+		if !words[w] {
+			return false
+		}
+	}
+	return neg != ""
+}
+
+// Normalize lowercases a keyword and collapses its whitespace.
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
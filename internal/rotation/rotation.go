@@ -0,0 +1,205 @@
+// Package rotation reads ad rotation schedules, which say which ads of an ad
+// group should be enabled on a given day, and keeps a log of the changes made
+// to follow them.
+package rotation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+	"go.yaml.in/yaml/v3"
+)
+
+const dateFormat = "2006-01-02"
+
+// Window enables Ads from From through To (inclusive dates, YYYY-MM-DD).
+type Window struct {
+	From string  `yaml:"from"`
+	To   string  `yaml:"to"`
+	Ads  []int64 `yaml:"ads"`
+}
+
+// Cycle enables each of Ads in turn for Weeks weeks at a time, starting on
+// Start.
+type Cycle struct {
+	Start string  `yaml:"start"`
+	Weeks int     `yaml:"weeks"`
+	Ads   []int64 `yaml:"ads"`
+}
+
+// Schedule is the contents of a rotation file. Windows take precedence over
+// the cycle, and Default applies when neither covers a day.
+type Schedule struct {
+	Windows []Window `yaml:"windows"`
+	Cycle   *Cycle   `yaml:"cycle"`
+	Default []int64  `yaml:"default"`
+}
+
+// Load reads and validates a rotation file.
+func Load(path string) (*Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading schedule: %w", err)
+	}
+	var s Schedule
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing schedule %s: %w", path, err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("schedule %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func (s *Schedule) validate() error {
+	if len(s.Windows) == 0 && s.Cycle == nil && len(s.Default) == 0 {
+		return fmt.Errorf("no windows, cycle, or default")
+	}
+	for i, w := range s.Windows {
+		from, err := time.Parse(dateFormat, w.From)
+		if err != nil {
+			return fmt.Errorf("window %d: invalid from date %q", i+1, w.From)
+		}
+		to, err := time.Parse(dateFormat, w.To)
+		if err != nil {
+			return fmt.Errorf("window %d: invalid to date %q", i+1, w.To)
+		}
+		if to.Before(from) {
+			return fmt.Errorf("window %d ends before it starts", i+1)
+		}
+		if len(w.Ads) == 0 {
+			return fmt.Errorf("window %d has no ads", i+1)
+		}
+	}
+	if c := s.Cycle; c != nil {
+		if _, err := time.Parse(dateFormat, c.Start); err != nil {
+			return fmt.Errorf("cycle: invalid start date %q", c.Start)
+		}
+		if c.Weeks < 0 {
+			return fmt.Errorf("cycle: weeks must be positive")
+		}
+		if len(c.Ads) == 0 {
+			return fmt.Errorf("cycle has no ads")
+		}
+	}
+	return nil
+}
+
+// Active returns the ads to enable on day and what selected them.
+func (s *Schedule) Active(day time.Time) (ads []int64, slot string) {
+	date := day.Format(dateFormat)
+	for i, w := range s.Windows {
+		if date >= w.From && date <= w.To {
+			return w.Ads, fmt.Sprintf("window %d (%s to %s)", i+1, w.From, w.To)
+		}
+	}
+	if c := s.Cycle; c != nil {
+		start, _ := time.Parse(dateFormat, c.Start)
+		d, _ := time.Parse(dateFormat, date)
+		if !d.Before(start) {
+			weeks := c.Weeks
+			if weeks == 0 {
+				weeks = 1
+			}
+			n := int(d.Sub(start).Hours()/24) / (7 * weeks)
+			i := n % len(c.Ads)
+			return c.Ads[i : i+1], fmt.Sprintf("cycle slot %d of %d", i+1, len(c.Ads))
+		}
+	}
+	return s.Default, "default"
+}
+
+// Managed returns every ad the schedule mentions. Ads outside it are left
+// alone.
+func (s *Schedule) Managed() []int64 {
+	var ids []int64
+	add := func(list []int64) {
+		for _, id := range list {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	for _, w := range s.Windows {
+		add(w.Ads)
+	}
+	if s.Cycle != nil {
+		add(s.Cycle.Ads)
+	}
+	add(s.Default)
+	return ids
+}
+
+// Change is one ad status change made by a rotation.
+type Change struct {
+	Time       time.Time `json:"time"`
+	Operator   string    `json:"operator"`
+	Profile    string    `json:"profile,omitempty"`
+	CampaignID int64     `json:"campaignId"`
+	AdGroupID  int64     `json:"adGroupId"`
+	AdID       int64     `json:"adId"`
+	AdName     string    `json:"adName"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Slot       string    `json:"slot"`
+	Schedule   string    `json:"schedule"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// LogPath returns the rotation log.
+func LogPath() string {
+	return filepath.Join(config.ConfigDir(), "rotation.jsonl")
+}
+
+// Append adds changes to the rotation log.
+func Append(changes []Change) error {
+	if err := os.MkdirAll(config.ConfigDir(), 0700); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	f, err := os.OpenFile(LogPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening rotation log: %w", err)
+	}
+	defer f.Close()
+	for _, c := range changes {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadLog reads the rotation log, oldest first. Malformed lines are skipped.
+func LoadLog() ([]Change, error) {
+	f, err := os.Open(LogPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening rotation log: %w", err)
+	}
+	defer f.Close()
+	var out []Change
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var c Change
+		if json.Unmarshal(scanner.Bytes(), &c) != nil {
+			continue
+		}
+		out = append(out, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading rotation log: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+// Server is a small authenticated REST facade over the services layer.
+type Server struct {
+	Client *api.Client
+	Config *config.Config
+	Token  string
+}
+
+// New creates a Server. Requests must carry "Authorization: Bearer <token>".
+func New(client *api.Client, cfg *config.Config, token string) *Server {
+	return &Server{Client: client, Config: cfg, Token: token}
+}
+
+// Handler returns the HTTP handler with all routes registered.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	mux.Handle("GET /v1/campaigns", s.auth(s.listCampaigns))
+	mux.Handle("GET /v1/campaigns/{campaignId}", s.auth(s.getCampaign))
+	mux.Handle("GET /v1/campaigns/{campaignId}/adgroups", s.auth(s.listAdGroups))
+	mux.Handle("GET /v1/campaigns/{campaignId}/adgroups/{adGroupId}/keywords", s.auth(s.listKeywords))
+	mux.Handle("PUT /v1/campaigns/{campaignId}/adgroups/{adGroupId}/keywords/bids", s.auth(s.updateKeywordBids))
+
+	mux.Handle("POST /v1/reports/campaigns", s.auth(s.campaignReport))
+	mux.Handle("POST /v1/reports/campaigns/{campaignId}/{level}", s.auth(s.subReport))
+	return mux
+}
+
+type envelope struct {
+	Data       interface{}        `json:"data,omitempty"`
+	Pagination *models.PageDetail `json:"pagination,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+func (s *Server) auth(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(s.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+		h(w, r)
+	})
+}
+
+func (s *Server) client(r *http.Request) *api.Client {
+	return s.Client.WithContext(r.Context())
+}
+
+func (s *Server) listCampaigns(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pageParams(r)
+	campaigns, page, err := services.NewCampaignService(s.client(r)).List(limit, offset)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, envelope{Data: campaigns, Pagination: page})
+}
+
+func (s *Server) getCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "campaignId")
+	if !ok {
+		return
+	}
+	campaign, err := services.NewCampaignService(s.client(r)).Get(id)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, envelope{Data: campaign})
+}
+
+func (s *Server) listAdGroups(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := pathID(w, r, "campaignId")
+	if !ok {
+		return
+	}
+	limit, offset := pageParams(r)
+	adgroups, page, err := services.NewAdGroupService(s.client(r)).List(campaignID, limit, offset)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, envelope{Data: adgroups, Pagination: page})
+}
+
+func (s *Server) listKeywords(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := pathID(w, r, "campaignId")
+	if !ok {
+		return
+	}
+	adGroupID, ok := pathID(w, r, "adGroupId")
+	if !ok {
+		return
+	}
+	limit, offset := pageParams(r)
+	keywords, page, err := services.NewKeywordService(s.client(r)).List(campaignID, adGroupID, limit, offset)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, envelope{Data: keywords, Pagination: page})
+}
+
+// updateKeywordBids applies a list of keyword updates, e.g.
+// [{"id": 1, "bidAmount": {"amount": "1.20", "currency": "USD"}}].
+// Bids are checked against the profile's max_bid.
+func (s *Server) updateKeywordBids(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := pathID(w, r, "campaignId")
+	if !ok {
+		return
+	}
+	adGroupID, ok := pathID(w, r, "adGroupId")
+	if !ok {
+		return
+	}
+
+	var updates []models.KeywordUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("parsing request body: %w", err))
+		return
+	}
+	if err := models.CheckEnums(updates); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(updates) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("no keyword updates provided"))
+		return
+	}
+	for _, u := range updates {
+		if u.BidAmount == nil {
+			continue
+		}
+		val, err := strconv.ParseFloat(u.BidAmount.Amount, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid bid amount for keyword %d: %s", u.ID, u.BidAmount.Amount))
+			return
+		}
+		if err := s.Config.CheckBid(val); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+
+	updated, err := services.NewKeywordService(s.client(r)).Update(campaignID, adGroupID, updates)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, envelope{Data: updated})
+}
+
+func (s *Server) campaignReport(w http.ResponseWriter, r *http.Request) {
+	req, ok := reportRequest(w, r)
+	if !ok {
+		return
+	}
+	resp, err := services.NewReportingService(s.client(r)).GetCampaignReport(req)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, envelope{Data: resp})
+}
+
+func (s *Server) subReport(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := pathID(w, r, "campaignId")
+	if !ok {
+		return
+	}
+	req, ok := reportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	svc := services.NewReportingService(s.client(r))
+	var resp *models.ReportingDataResponse
+	var err error
+	switch r.PathValue("level") {
+	case "adgroups":
+		resp, err = svc.GetAdGroupReport(campaignID, req)
+	case "keywords":
+		resp, err = svc.GetKeywordReport(campaignID, req)
+	case "searchterms":
+		resp, err = svc.GetSearchTermReport(campaignID, req)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown report level %q", r.PathValue("level")))
+		return
+	}
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, envelope{Data: resp})
+}
+
+func reportRequest(w http.ResponseWriter, r *http.Request) (*models.ReportRequest, bool) {
+	var req models.ReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("parsing report request: %w", err))
+		return nil, false
+	}
+	if err := models.CheckEnums(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if req.StartTime == "" || req.EndTime == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("startTime and endTime are required"))
+		return nil, false
+	}
+	if req.Selector == nil {
+		sel := models.NewSelector(1000, 0)
+		sel.OrderBy = []models.OrderByItem{{Field: "localSpend", SortOrder: "DESCENDING"}}
+		req.Selector = &sel
+	}
+	return &req, true
+}
+
+func pageParams(r *http.Request) (int, int) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %s", name, r.PathValue(name)))
+		return 0, false
+	}
+	return id, true
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	status := api.StatusOf(err)
+	if status == 0 {
+		status = http.StatusBadGateway
+	}
+	writeError(w, status, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, envelope{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -38,6 +38,19 @@ func (s *AdGroupService) FindAll(campaignID int64, selector models.Selector) ([]
 	return api.PaginatedFetcher[models.AdGroup](s.Client, fmt.Sprintf("/campaigns/%d/adgroups/find", campaignID), selector)
 }
 
+// FindInOrg finds ad groups across every campaign of the org.
+func (s *AdGroupService) FindInOrg(selector models.Selector) ([]models.AdGroup, *models.PageDetail, error) {
+	var adgroups []models.AdGroup
+	page, err := s.Client.Post("/adgroups/find", &selector, &adgroups)
+	return adgroups, page, err
+}
+
+// FindAllInOrg finds ad groups across every campaign of the org, fetching
+// every page.
+func (s *AdGroupService) FindAllInOrg(selector models.Selector) ([]models.AdGroup, error) {
+	return api.PaginatedFetcher[models.AdGroup](s.Client, "/adgroups/find", selector)
+}
+
 func (s *AdGroupService) Create(campaignID int64, adgroup *models.AdGroup) (*models.AdGroup, error) {
 	var created models.AdGroup
 	_, err := s.Client.Post(fmt.Sprintf("/campaigns/%d/adgroups", campaignID), adgroup, &created)
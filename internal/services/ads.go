@@ -0,0 +1,39 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+type AdService struct {
+	Client *api.Client
+}
+
+func NewAdService(client *api.Client) *AdService {
+	return &AdService{Client: client}
+}
+
+func (s *AdService) FindAll(campaignID, adGroupID int64, selector models.Selector) ([]models.Ad, error) {
+	return api.PaginatedFetcher[models.Ad](s.Client, fmt.Sprintf("/campaigns/%d/adgroups/%d/ads/find", campaignID, adGroupID), selector)
+}
+
+// FindInOrg finds ads across every campaign of the org.
+func (s *AdService) FindInOrg(selector models.Selector) ([]models.Ad, *models.PageDetail, error) {
+	var ads []models.Ad
+	page, err := s.Client.Post("/ads/find", &selector, &ads)
+	return ads, page, err
+}
+
+func (s *AdService) Create(campaignID, adGroupID int64, ad *models.Ad) (*models.Ad, error) {
+	var created models.Ad
+	_, err := s.Client.Post(fmt.Sprintf("/campaigns/%d/adgroups/%d/ads", campaignID, adGroupID), ad, &created)
+	return &created, err
+}
+
+func (s *AdService) Update(campaignID, adGroupID, adID int64, update *models.AdUpdate) (*models.Ad, error) {
+	var updated models.Ad
+	_, err := s.Client.Put(fmt.Sprintf("/campaigns/%d/adgroups/%d/ads/%d", campaignID, adGroupID, adID), update, &updated)
+	return &updated, err
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+)
+
+// BulkResult is the outcome of one item of a bulk request.
+type BulkResult[T any] struct {
+	// Index is the item's position in the request.
+	Index int
+	// Item is the item as the API returned it on success, or as submitted
+	// on failure.
+	Item  T
+	Error string
+}
+
+// OK reports whether the item was accepted.
+func (r BulkResult[T]) OK() bool {
+	return r.Error == ""
+}
+
+// Succeeded returns the accepted items.
+func Succeeded[T any](results []BulkResult[T]) []T {
+	var items []T
+	for _, r := range results {
+		if r.OK() {
+			items = append(items, r.Item)
+		}
+	}
+	return items
+}
+
+// FailedCount returns how many items were rejected.
+func FailedCount[T any](results []BulkResult[T]) int {
+	n := 0
+	for _, r := range results {
+		if !r.OK() {
+			n++
+		}
+	}
+	return n
+}
+
+// bulkIndex finds the item index in an error field like "keywords[2].text".
+var bulkIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+// bulkResults pairs the items of a bulk request with the API's answer. Errors
+// naming an item fail just that item, and the items the API returned are
+// matched in order to the rest. An error that names no item failed the whole
+// request and is returned as is.
+func bulkResults[T any](submitted, returned []T, err error) ([]BulkResult[T], error) {
+	results := make([]BulkResult[T], len(submitted))
+	for i, item := range submitted {
+		results[i] = BulkResult[T]{Index: i, Item: item}
+	}
+	if err != nil {
+		var apiErr *api.Error
+		if !errors.As(err, &apiErr) || len(apiErr.Errors) == 0 {
+			return nil, err
+		}
+		for _, e := range apiErr.Errors {
+			m := bulkIndex.FindStringSubmatch(e.Field)
+			if m == nil {
+				return nil, err
+			}
+			i, _ := strconv.Atoi(m[1])
+			if i >= len(results) {
+				return nil, err
+			}
+			results[i].Error = e.Message
+			if results[i].Error == "" {
+				results[i].Error = e.MessageCode
+			}
+			if results[i].Error == "" {
+				results[i].Error = "rejected"
+			}
+		}
+	}
+
+	if len(returned) == len(submitted) {
+		for i := range results {
+			if results[i].OK() {
+				results[i].Item = returned[i]
+			}
+		}
+		return results, nil
+	}
+	next := 0
+	for i := range results {
+		if results[i].OK() && next < len(returned) {
+			results[i].Item = returned[next]
+			next++
+		}
+	}
+	return results, nil
+}
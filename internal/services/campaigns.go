@@ -51,6 +51,19 @@ func (s *CampaignService) Update(id int64, update *models.CampaignUpdate) (*mode
 	return &updated, err
 }
 
+// UpdateCountries replaces a campaign's countries or regions. Apple rejects
+// the change while ad groups have geo targeting unless clearGeoTargeting is
+// set, which removes that targeting.
+func (s *CampaignService) UpdateCountries(id int64, countries []string, clearGeoTargeting bool) (*models.Campaign, error) {
+	var updated models.Campaign
+	req := &models.UpdateCampaignRequest{
+		Campaign:                                 &models.CampaignUpdate{CountriesOrRegions: countries},
+		ClearGeoTargetingOnCountryOrRegionChange: clearGeoTargeting,
+	}
+	_, err := s.Client.Put(fmt.Sprintf("/campaigns/%d", id), req, &updated)
+	return &updated, err
+}
+
 func (s *CampaignService) Delete(id int64) error {
 	return s.Client.Delete(fmt.Sprintf("/campaigns/%d", id))
 }
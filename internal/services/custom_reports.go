@@ -0,0 +1,129 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+	"github.com/trebuhs/asa-cli/internal/progress"
+)
+
+type CustomReportService struct {
+	Client *api.Client
+}
+
+func NewCustomReportService(client *api.Client) *CustomReportService {
+	return &CustomReportService{Client: client}
+}
+
+func (s *CustomReportService) Create(req *models.CustomReportRequest) (*models.CustomReport, error) {
+	var report models.CustomReport
+	_, err := s.Client.Post("/custom-reports", req, &report)
+	return &report, err
+}
+
+func (s *CustomReportService) Get(id int64) (*models.CustomReport, error) {
+	var report models.CustomReport
+	_, err := s.Client.Get(fmt.Sprintf("/custom-reports/%d", id), &report)
+	return &report, err
+}
+
+func (s *CustomReportService) List(limit, offset int) ([]models.CustomReport, *models.PageDetail, error) {
+	var reports []models.CustomReport
+	page, err := s.Client.Get(fmt.Sprintf("/custom-reports?limit=%d&offset=%d", limit, offset), &reports)
+	return reports, page, err
+}
+
+// WaitForCompletion polls a report until it is COMPLETED, FAILED, or the timeout elapses.
+func (s *CustomReportService) WaitForCompletion(id int64, interval, timeout time.Duration) (*models.CustomReport, error) {
+	deadline := time.Now().Add(timeout)
+	bar := progress.New(fmt.Sprintf("Waiting for report %d", id), 0)
+	defer bar.Done()
+	for {
+		bar.Tick()
+		report, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		switch report.State {
+		case "COMPLETED":
+			return report, nil
+		case "FAILED":
+			return nil, fmt.Errorf("custom report %d failed", id)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("custom report %d not ready after %v (state %s)", id, timeout, report.State)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Download fetches and parses a completed impression share report. The
+// download URI is pre-signed, so no API credentials are sent.
+func (s *CustomReportService) Download(report *models.CustomReport) ([]models.ImpressionShareRow, error) {
+	if report.DownloadURI == "" {
+		return nil, fmt.Errorf("custom report %d has no download URI", report.ID)
+	}
+	resp, err := http.Get(report.DownloadURI)
+	if err != nil {
+		return nil, fmt.Errorf("downloading custom report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading custom report: HTTP %d", resp.StatusCode)
+	}
+	return ParseImpressionShareCSV(resp.Body)
+}
+
+// ParseImpressionShareCSV parses the CSV format of impression share reports.
+func ParseImpressionShareCSV(r io.Reader) ([]models.ImpressionShareRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading report header: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.TrimSpace(h)] = i
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var rows []models.ImpressionShareRow
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading report rows: %w", err)
+		}
+		row := models.ImpressionShareRow{
+			Date:            get(rec, "date"),
+			AppName:         get(rec, "appName"),
+			CountryOrRegion: get(rec, "countryOrRegion"),
+			SearchTerm:      get(rec, "searchTerm"),
+			Rank:            get(rec, "rank"),
+		}
+		row.AdamID, _ = strconv.ParseInt(get(rec, "adamId"), 10, 64)
+		row.LowImpressionShare, _ = strconv.ParseFloat(get(rec, "lowImpressionShare"), 64)
+		row.HighImpressionShare, _ = strconv.ParseFloat(get(rec, "highImpressionShare"), 64)
+		row.SearchPopularity, _ = strconv.Atoi(get(rec, "searchPopularity"))
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
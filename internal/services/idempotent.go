@@ -0,0 +1,179 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// IsMissing reports whether err says the entity doesn't exist, typically
+// because an earlier attempt already deleted it.
+func IsMissing(err error) bool {
+	return api.IsNotFound(err)
+}
+
+// UpdateIfChanged applies update unless the campaign already matches it. It
+// returns the campaign as it is afterwards and the fields the update changed,
+// none if it wasn't sent.
+func (s *CampaignService) UpdateIfChanged(id int64, update *models.CampaignUpdate) (*models.Campaign, []models.FieldChange, error) {
+	current, err := s.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := models.Diff(current, update)
+	if len(changes) == 0 {
+		return current, nil, nil
+	}
+	updated, err := s.Update(id, update)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, changes, nil
+}
+
+// UpdateIfChanged applies update unless the ad group already matches it.
+func (s *AdGroupService) UpdateIfChanged(campaignID, adGroupID int64, update *models.AdGroupUpdate) (*models.AdGroup, []models.FieldChange, error) {
+	current, err := s.Get(campaignID, adGroupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := models.Diff(current, update)
+	if len(changes) == 0 {
+		return current, nil, nil
+	}
+	updated, err := s.Update(campaignID, adGroupID, update)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, changes, nil
+}
+
+// UpdateChanged applies the updates that change something and returns the
+// updated keywords along with the ones left alone because they already
+// matched, and the fields changed per keyword ID. Keywords it can't find are
+// sent as they are, for the API to judge.
+func (s *KeywordService) UpdateChanged(campaignID, adGroupID int64, updates []models.KeywordUpdate) (updated, unchanged []models.Keyword, changes map[int64][]models.FieldChange, err error) {
+	ids := make([]int64, len(updates))
+	for i, u := range updates {
+		ids[i] = u.ID
+	}
+	current, err := s.FindAll(campaignID, adGroupID, idSelector(ids))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	byID := make(map[int64]*models.Keyword, len(current))
+	for i := range current {
+		byID[current[i].ID] = &current[i]
+	}
+	var changed []models.KeywordUpdate
+	changes = map[int64][]models.FieldChange{}
+	for _, u := range updates {
+		k, ok := byID[u.ID]
+		if !ok || k.Deleted {
+			changed = append(changed, u)
+			continue
+		}
+		diff := models.Diff(k, u)
+		if len(diff) == 0 {
+			unchanged = append(unchanged, *k)
+			continue
+		}
+		changed = append(changed, u)
+		changes[u.ID] = diff
+	}
+	if len(changed) > 0 {
+		if updated, err = s.Update(campaignID, adGroupID, changed); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return updated, unchanged, changes, nil
+}
+
+// DeleteExisting deletes keywords like Delete, but succeeds for keywords that
+// are already deleted, returning their IDs, so a retried delete is safe.
+func (s *KeywordService) DeleteExisting(campaignID, adGroupID int64, ids []int64) ([]int64, error) {
+	return deleteExisting(ids, func(ids []int64) error {
+		return s.Delete(campaignID, adGroupID, ids)
+	}, func(ids []int64) (map[int64]bool, error) {
+		keywords, err := s.FindAll(campaignID, adGroupID, idSelector(ids))
+		live := map[int64]bool{}
+		for _, k := range keywords {
+			live[k.ID] = !k.Deleted
+		}
+		return live, err
+	})
+}
+
+// DeleteCampaignNegativeKeywordsExisting is DeleteExisting for campaign
+// negative keywords.
+func (s *KeywordService) DeleteCampaignNegativeKeywordsExisting(campaignID int64, ids []int64) ([]int64, error) {
+	return deleteExisting(ids, func(ids []int64) error {
+		return s.DeleteCampaignNegativeKeywords(campaignID, ids)
+	}, func(ids []int64) (map[int64]bool, error) {
+		keywords, err := s.FindAllCampaignNegativeKeywords(campaignID, idSelector(ids))
+		return liveNegatives(keywords), err
+	})
+}
+
+// DeleteAdGroupNegativeKeywordsExisting is DeleteExisting for ad group
+// negative keywords.
+func (s *KeywordService) DeleteAdGroupNegativeKeywordsExisting(campaignID, adGroupID int64, ids []int64) ([]int64, error) {
+	return deleteExisting(ids, func(ids []int64) error {
+		return s.DeleteAdGroupNegativeKeywords(campaignID, adGroupID, ids)
+	}, func(ids []int64) (map[int64]bool, error) {
+		keywords, err := s.FindAllAdGroupNegativeKeywords(campaignID, adGroupID, idSelector(ids))
+		return liveNegatives(keywords), err
+	})
+}
+
+// deleteExisting runs a bulk delete. If the API rejects it as a whole, it
+// looks the IDs up, and when some are gone, deletes just the rest. It returns
+// the IDs that were already gone.
+func deleteExisting(ids []int64, del func([]int64) error, lookup func([]int64) (map[int64]bool, error)) ([]int64, error) {
+	err := del(ids)
+	if err == nil || !IsMissing(err) && api.StatusOf(err) != http.StatusBadRequest {
+		return nil, err
+	}
+	live, lookupErr := lookup(ids)
+	if lookupErr != nil {
+		return nil, err
+	}
+	var rest, missing []int64
+	for _, id := range ids {
+		if live[id] {
+			rest = append(rest, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		if err := del(rest); err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+func liveNegatives(keywords []models.NegativeKeyword) map[int64]bool {
+	live := map[int64]bool{}
+	for _, k := range keywords {
+		live[k.ID] = !k.Deleted
+	}
+	return live
+}
+
+// idSelector selects the entities with the given IDs.
+func idSelector(ids []int64) models.Selector {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	selector := models.NewSelector(1000, 0)
+	selector.Conditions = []models.Condition{{Field: "id", Operator: "IN", Values: values}}
+	return selector
+}
@@ -15,6 +15,33 @@ func NewKeywordService(client *api.Client) *KeywordService {
 	return &KeywordService{Client: client}
 }
 
+// KeywordFilter narrows a keyword find on the server. Empty fields match
+// everything.
+type KeywordFilter struct {
+	Status       models.KeywordStatus
+	MatchType    models.MatchType
+	TextContains string
+}
+
+// Apply adds the filter's conditions to selector.
+func (f KeywordFilter) Apply(selector models.Selector) models.Selector {
+	if f.Status != "" {
+		selector.Conditions = append(selector.Conditions, models.Condition{Field: "status", Operator: "EQUALS", Values: []string{string(f.Status)}})
+	}
+	if f.MatchType != "" {
+		selector.Conditions = append(selector.Conditions, models.Condition{Field: "matchType", Operator: "EQUALS", Values: []string{string(f.MatchType)}})
+	}
+	if f.TextContains != "" {
+		selector.Conditions = append(selector.Conditions, models.Condition{Field: "text", Operator: "CONTAINS", Values: []string{f.TextContains}})
+	}
+	return selector
+}
+
+// IsZero reports whether the filter matches everything.
+func (f KeywordFilter) IsZero() bool {
+	return f == KeywordFilter{}
+}
+
 // --- Targeting Keywords ---
 
 func (s *KeywordService) List(campaignID, adGroupID int64, limit, offset int) ([]models.Keyword, *models.PageDetail, error) {
@@ -40,12 +67,30 @@ func (s *KeywordService) FindAll(campaignID, adGroupID int64, selector models.Se
 	return api.PaginatedFetcher[models.Keyword](s.Client, fmt.Sprintf("/campaigns/%d/adgroups/%d/targetingkeywords/find", campaignID, adGroupID), selector)
 }
 
+// FindInCampaign finds targeting keywords across all ad groups of a campaign.
+func (s *KeywordService) FindInCampaign(campaignID int64, selector models.Selector) ([]models.Keyword, *models.PageDetail, error) {
+	var keywords []models.Keyword
+	page, err := s.Client.Post(fmt.Sprintf("/campaigns/%d/adgroups/targetingkeywords/find", campaignID), &selector, &keywords)
+	return keywords, page, err
+}
+
+func (s *KeywordService) FindAllInCampaign(campaignID int64, selector models.Selector) ([]models.Keyword, error) {
+	return api.PaginatedFetcher[models.Keyword](s.Client, fmt.Sprintf("/campaigns/%d/adgroups/targetingkeywords/find", campaignID), selector)
+}
+
 func (s *KeywordService) Create(campaignID, adGroupID int64, keywords []models.Keyword) ([]models.Keyword, error) {
 	var created []models.Keyword
 	_, err := s.Client.Post(fmt.Sprintf("/campaigns/%d/adgroups/%d/targetingkeywords/bulk", campaignID, adGroupID), keywords, &created)
 	return created, err
 }
 
+// CreateEach creates keywords like Create but reports each keyword's outcome,
+// so keywords the API rejects don't hide the ones it created.
+func (s *KeywordService) CreateEach(campaignID, adGroupID int64, keywords []models.Keyword) ([]BulkResult[models.Keyword], error) {
+	created, err := s.Create(campaignID, adGroupID, keywords)
+	return bulkResults(keywords, created, err)
+}
+
 func (s *KeywordService) Update(campaignID, adGroupID int64, updates []models.KeywordUpdate) ([]models.Keyword, error) {
 	var updated []models.Keyword
 	_, err := s.Client.Put(fmt.Sprintf("/campaigns/%d/adgroups/%d/targetingkeywords/bulk", campaignID, adGroupID), updates, &updated)
@@ -79,12 +124,23 @@ func (s *KeywordService) FindCampaignNegativeKeywords(campaignID int64, selector
 	return keywords, page, err
 }
 
+func (s *KeywordService) FindAllCampaignNegativeKeywords(campaignID int64, selector models.Selector) ([]models.NegativeKeyword, error) {
+	return api.PaginatedFetcher[models.NegativeKeyword](s.Client, fmt.Sprintf("/campaigns/%d/negativekeywords/find", campaignID), selector)
+}
+
 func (s *KeywordService) CreateCampaignNegativeKeywords(campaignID int64, keywords []models.NegativeKeyword) ([]models.NegativeKeyword, error) {
 	var created []models.NegativeKeyword
 	_, err := s.Client.Post(fmt.Sprintf("/campaigns/%d/negativekeywords/bulk", campaignID), keywords, &created)
 	return created, err
 }
 
+// CreateCampaignNegativeKeywordsEach is CreateCampaignNegativeKeywords with
+// each keyword's outcome.
+func (s *KeywordService) CreateCampaignNegativeKeywordsEach(campaignID int64, keywords []models.NegativeKeyword) ([]BulkResult[models.NegativeKeyword], error) {
+	created, err := s.CreateCampaignNegativeKeywords(campaignID, keywords)
+	return bulkResults(keywords, created, err)
+}
+
 func (s *KeywordService) DeleteCampaignNegativeKeywords(campaignID int64, keywordIDs []int64) error {
 	path := fmt.Sprintf("/campaigns/%d/negativekeywords/delete/bulk", campaignID)
 	_, err := s.Client.Post(path, keywordIDs, nil)
@@ -112,12 +168,35 @@ func (s *KeywordService) FindAdGroupNegativeKeywords(campaignID, adGroupID int64
 	return keywords, page, err
 }
 
+func (s *KeywordService) FindAllAdGroupNegativeKeywords(campaignID, adGroupID int64, selector models.Selector) ([]models.NegativeKeyword, error) {
+	return api.PaginatedFetcher[models.NegativeKeyword](s.Client, fmt.Sprintf("/campaigns/%d/adgroups/%d/negativekeywords/find", campaignID, adGroupID), selector)
+}
+
+// FindAdGroupNegativeKeywordsInCampaign finds ad-group-level negative keywords
+// across all ad groups of a campaign.
+func (s *KeywordService) FindAdGroupNegativeKeywordsInCampaign(campaignID int64, selector models.Selector) ([]models.NegativeKeyword, *models.PageDetail, error) {
+	var keywords []models.NegativeKeyword
+	page, err := s.Client.Post(fmt.Sprintf("/campaigns/%d/adgroups/negativekeywords/find", campaignID), &selector, &keywords)
+	return keywords, page, err
+}
+
+func (s *KeywordService) FindAllAdGroupNegativeKeywordsInCampaign(campaignID int64, selector models.Selector) ([]models.NegativeKeyword, error) {
+	return api.PaginatedFetcher[models.NegativeKeyword](s.Client, fmt.Sprintf("/campaigns/%d/adgroups/negativekeywords/find", campaignID), selector)
+}
+
 func (s *KeywordService) CreateAdGroupNegativeKeywords(campaignID, adGroupID int64, keywords []models.NegativeKeyword) ([]models.NegativeKeyword, error) {
 	var created []models.NegativeKeyword
 	_, err := s.Client.Post(fmt.Sprintf("/campaigns/%d/adgroups/%d/negativekeywords/bulk", campaignID, adGroupID), keywords, &created)
 	return created, err
 }
 
+// CreateAdGroupNegativeKeywordsEach is CreateAdGroupNegativeKeywords with each
+// keyword's outcome.
+func (s *KeywordService) CreateAdGroupNegativeKeywordsEach(campaignID, adGroupID int64, keywords []models.NegativeKeyword) ([]BulkResult[models.NegativeKeyword], error) {
+	created, err := s.CreateAdGroupNegativeKeywords(campaignID, adGroupID, keywords)
+	return bulkResults(keywords, created, err)
+}
+
 func (s *KeywordService) DeleteAdGroupNegativeKeywords(campaignID, adGroupID int64, keywordIDs []int64) error {
 	path := fmt.Sprintf("/campaigns/%d/adgroups/%d/negativekeywords/delete/bulk", campaignID, adGroupID)
 	_, err := s.Client.Post(path, keywordIDs, nil)
@@ -32,11 +32,50 @@ func (s *ReportingService) GetSearchTermReport(campaignID int64, req *models.Rep
 	return s.getReport(fmt.Sprintf("/reports/campaigns/%d/searchterms", campaignID), req)
 }
 
+// GetAdGroupSearchTermReport reports the search terms of a single ad group.
+func (s *ReportingService) GetAdGroupSearchTermReport(campaignID, adGroupID int64, req *models.ReportRequest) (*models.ReportingDataResponse, error) {
+	return s.getReport(fmt.Sprintf("/reports/campaigns/%d/adgroups/%d/searchterms", campaignID, adGroupID), req)
+}
+
+// EachPage pulls a report of a campaign's ad groups, keywords, or search
+// terms (level "adgroups", "keywords", or "searchterms") one page of the
+// request's limit at a time, and calls fn with each page until every row has
+// been fetched. Only one page is held in memory at a time.
+func (s *ReportingService) EachPage(campaignID int64, level string, req *models.ReportRequest, fn func(page *models.ReportingDataResponse) error) error {
+	path := fmt.Sprintf("/reports/campaigns/%d/%s", campaignID, level)
+	paged := *req
+	selector := models.Selector{}
+	if req.Selector != nil {
+		selector = *req.Selector
+	}
+	paged.Selector = &selector
+
+	for offset := selector.Pagination.Offset; ; {
+		selector.Pagination.Offset = offset
+		page, detail, err := s.getReportPage(path, &paged)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		offset += len(page.Row)
+		if detail == nil || len(page.Row) == 0 || offset >= detail.TotalResults {
+			return nil
+		}
+	}
+}
+
 func (s *ReportingService) getReport(path string, req *models.ReportRequest) (*models.ReportingDataResponse, error) {
+	resp, _, err := s.getReportPage(path, req)
+	return resp, err
+}
+
+func (s *ReportingService) getReportPage(path string, req *models.ReportRequest) (*models.ReportingDataResponse, *models.PageDetail, error) {
 	var raw json.RawMessage
-	_, err := s.Client.Post(path, req, &raw)
+	page, err := s.Client.Post(path, req, &raw)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var resp models.ReportResponse
@@ -44,10 +83,10 @@ func (s *ReportingService) getReport(path string, req *models.ReportRequest) (*m
 		// Try direct unmarshal
 		var direct models.ReportingDataResponse
 		if err2 := json.Unmarshal(raw, &direct); err2 != nil {
-			return nil, fmt.Errorf("parsing report response: %w", err)
+			return nil, nil, fmt.Errorf("parsing report response: %w", err)
 		}
-		return &direct, nil
+		return &direct, page, nil
 	}
 
-	return &resp.ReportingDataResponse, nil
+	return &resp.ReportingDataResponse, page, nil
 }
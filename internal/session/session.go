@@ -0,0 +1,47 @@
+package session
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Context is the persisted "current" org/campaign/ad group selection.
+type Context struct {
+	OrgID      string `json:"orgId,omitempty"`
+	CampaignID int64  `json:"campaignId,omitempty"`
+	AdGroupID  int64  `json:"adGroupId,omitempty"`
+}
+
+// IsEmpty reports whether no context values are set.
+func (c *Context) IsEmpty() bool {
+	return c.OrgID == "" && c.CampaignID == 0 && c.AdGroupID == 0
+}
+
+// Path returns the session file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("session", profile)
+}
+
+// Load reads the session context for a profile. A missing file yields an empty context.
+func Load(profile string) (*Context, error) {
+	var ctx Context
+	if err := config.LoadJSON(Path(profile), "session context", &ctx); err != nil {
+		return nil, err
+	}
+	return &ctx, nil
+}
+
+// Save writes the session context for a profile.
+func Save(profile string, ctx *Context) error {
+	return config.SaveJSON(Path(profile), "session context", ctx)
+}
+
+// Clear removes the session context for a profile.
+func Clear(profile string) error {
+	if err := os.Remove(Path(profile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("clearing session context: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package simulate estimates the effect of changes from historical data.
+// Its results are heuristics, not forecasts from Apple.
+package simulate
+
+import (
+	"fmt"
+	"math"
+)
+
+// Baseline is a keyword's observed daily averages at its current bid.
+type Baseline struct {
+	Bid         float64
+	Impressions float64
+	Taps        float64
+	Spend       float64
+	// SuggestedBid is Apple's bid recommendation, or 0 if unknown.
+	SuggestedBid float64
+}
+
+// CPT is the observed average cost per tap.
+func (b Baseline) CPT() float64 {
+	if b.Taps == 0 {
+		return 0
+	}
+	return b.Spend / b.Taps
+}
+
+// TTR is the observed tap-through rate.
+func (b Baseline) TTR() float64 {
+	if b.Impressions == 0 {
+		return 0
+	}
+	return b.Taps / b.Impressions
+}
+
+// Outcome is an estimated daily result.
+type Outcome struct {
+	Label       string  `json:"label"`
+	Impressions float64 `json:"impressions"`
+	Taps        float64 `json:"taps"`
+	Spend       float64 `json:"spend"`
+	CPT         float64 `json:"cpt"`
+}
+
+// Elasticities are the low, mid, and high assumed responses of impression
+// volume to a relative bid change: impressions scale by (bid/current)^e.
+var Elasticities = [3]float64{0.4, 0.8, 1.2}
+
+// Bid estimates daily outcomes at a new bid for each of Elasticities.
+//
+// The model assumes the tap-through rate stays constant, impression volume
+// follows a power law in the bid ratio, and the average CPT moves with the
+// square root of the bid ratio (second-price auctions pass on only part of a
+// bid change) without exceeding the bid. Raising the bid past Apple's
+// suggested bid halves the elasticity for the part above it, since the
+// keyword already wins most auctions there.
+func Bid(base Baseline, bid float64) ([3]Outcome, error) {
+	var out [3]Outcome
+	if base.Bid <= 0 {
+		return out, fmt.Errorf("current bid is unknown")
+	}
+	if bid <= 0 {
+		return out, fmt.Errorf("bid must be positive")
+	}
+	if base.Impressions == 0 {
+		return out, fmt.Errorf("no impressions in the history window to model from")
+	}
+
+	cpt := base.CPT()
+	if cpt == 0 {
+		cpt = base.Bid * 0.7
+	}
+	newCPT := math.Min(cpt*math.Sqrt(bid/base.Bid), bid)
+
+	for i, e := range Elasticities {
+		ratio := volumeRatio(base, bid, e)
+		impressions := base.Impressions * ratio
+		taps := impressions * base.TTR()
+		out[i] = Outcome{
+			Label:       [3]string{"low", "mid", "high"}[i],
+			Impressions: impressions,
+			Taps:        taps,
+			Spend:       taps * newCPT,
+			CPT:         newCPT,
+		}
+	}
+	return out, nil
+}
+
+func volumeRatio(base Baseline, bid, e float64) float64 {
+	s := base.SuggestedBid
+	if s <= 0 || bid <= s || base.Bid >= s {
+		return math.Pow(bid/base.Bid, e)
+	}
+	// Full elasticity up to the suggested bid, half beyond it.
+	return math.Pow(s/base.Bid, e) * math.Pow(bid/s, e/2)
+}
@@ -0,0 +1,149 @@
+// Package tags is a local labeling layer over Apple Search Ads entities,
+// which have no labels of their own.
+package tags
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Entity kinds that can be tagged.
+const (
+	KindCampaign = "campaign"
+	KindAdGroup  = "adgroup"
+)
+
+// Entry is one tagged entity.
+type Entry struct {
+	Kind string   `json:"kind"`
+	ID   int64    `json:"id"`
+	Tags []string `json:"tags"`
+}
+
+// Store holds the tags of one profile, keyed by "kind/id".
+type Store struct {
+	Entities map[string][]string `json:"entities"`
+
+	path string
+}
+
+// Path returns the tag store file for a profile.
+func Path(profile string) string {
+	return config.ProfileFile("tags", profile)
+}
+
+// Load reads the tag store for a profile. A missing file yields an empty store.
+func Load(profile string) (*Store, error) {
+	s := &Store{Entities: map[string][]string{}, path: Path(profile)}
+	if err := config.LoadJSON(s.path, "tags", s); err != nil {
+		return nil, err
+	}
+	if s.Entities == nil {
+		s.Entities = map[string][]string{}
+	}
+	return s, nil
+}
+
+// ValidKind reports whether kind can be tagged.
+func ValidKind(kind string) bool {
+	return kind == KindCampaign || kind == KindAdGroup
+}
+
+// Normalize lowercases and trims a tag.
+func Normalize(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func key(kind string, id int64) string {
+	return kind + "/" + strconv.FormatInt(id, 10)
+}
+
+// Add tags an entity and saves the store.
+func (s *Store) Add(kind string, id int64, tags ...string) error {
+	k := key(kind, id)
+	for _, t := range tags {
+		t = Normalize(t)
+		if t != "" && !contains(s.Entities[k], t) {
+			s.Entities[k] = append(s.Entities[k], t)
+		}
+	}
+	sort.Strings(s.Entities[k])
+	return s.save()
+}
+
+// Remove untags an entity and saves the store.
+func (s *Store) Remove(kind string, id int64, tags ...string) error {
+	k := key(kind, id)
+	remove := make([]string, len(tags))
+	for i, t := range tags {
+		remove[i] = Normalize(t)
+	}
+	var kept []string
+	for _, t := range s.Entities[k] {
+		if !contains(remove, t) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == 0 {
+		delete(s.Entities, k)
+	} else {
+		s.Entities[k] = kept
+	}
+	return s.save()
+}
+
+// Tags returns an entity's tags.
+func (s *Store) Tags(kind string, id int64) []string {
+	return s.Entities[key(kind, id)]
+}
+
+// IDs returns the IDs of entities of a kind carrying tag, sorted.
+func (s *Store) IDs(kind, tag string) []int64 {
+	tag = Normalize(tag)
+	var ids []int64
+	for _, e := range s.Entries() {
+		if e.Kind == kind && contains(e.Tags, tag) {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+// Entries returns all tagged entities ordered by kind and ID.
+func (s *Store) Entries() []Entry {
+	var out []Entry
+	for k, tags := range s.Entities {
+		kind, idStr, ok := strings.Cut(k, "/")
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, Entry{Kind: kind, ID: id, Tags: tags})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func (s *Store) save() error {
+	return config.SaveJSON(s.path, "tags", s)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// quotaHistory is how many days of counts the quota store keeps.
+const quotaHistory = 31
+
+// warnRatio is the share of the limit at which Quota starts warning. From
+// there on it also re-reads the store before each request, so that calls by
+// parallel runs count towards the limit.
+const warnRatio = 0.8
+
+// flushEvery is how many calls Quota counts in memory before adding them to
+// the store; the rest are added when the command finishes (see SaveQuotas).
+const flushEvery = 50
+
+// DailyCalls maps profile to day (YYYY-MM-DD, local time) to API calls.
+type DailyCalls map[string]map[string]int64
+
+// QuotaPath returns the quota store.
+func QuotaPath() string {
+	return filepath.Join(config.ConfigDir(), "quota.json")
+}
+
+// LoadDailyCalls reads the quota store. A missing file yields no counts.
+func LoadDailyCalls() (DailyCalls, error) {
+	calls := DailyCalls{}
+	data, err := os.ReadFile(QuotaPath())
+	if os.IsNotExist(err) {
+		return calls, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading quota store: %w", err)
+	}
+	if err := json.Unmarshal(data, &calls); err != nil {
+		return nil, fmt.Errorf("parsing quota store: %w", err)
+	}
+	return calls, nil
+}
+
+// Day returns the store key for t.
+func Day(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// Profiles returns the profiles with recorded calls, sorted.
+func (d DailyCalls) Profiles() []string {
+	names := make([]string, 0, len(d))
+	for name := range d {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Quota tracks one profile's API calls for the day against a limit. Calls
+// are counted in memory and added to the store every flushEvery calls and
+// when the command finishes. Long-running sessions roll over to the next day.
+type Quota struct {
+	Profile string
+	Limit   int64 // 0 means no limit
+	Enforce bool
+
+	mu      sync.Mutex
+	day     string
+	used    int64 // today's calls in the store, as last read or written
+	pending int64 // calls not yet written to the store
+	saveErr error
+	calls   atomic.Int64
+	warned  atomic.Bool
+}
+
+// NewQuota starts tracking profile, counting from the calls already recorded
+// today.
+func NewQuota(profile string, limit int64, enforce bool) *Quota {
+	q := &Quota{Profile: profile, Limit: limit, Enforce: enforce}
+	q.refresh(Day(time.Now()))
+	return q
+}
+
+// refresh re-reads today's count from the store, starting a new day's count
+// when the day has changed. The caller holds q.mu or owns q.
+func (q *Quota) refresh(day string) {
+	if day != q.day {
+		q.day, q.used = day, 0
+		q.warned.Store(false)
+	}
+	if calls, err := LoadDailyCalls(); err == nil {
+		q.used = calls[q.Profile][q.day]
+	}
+}
+
+// Used returns today's API calls, including this run's.
+func (q *Quota) Used() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.used + q.pending
+}
+
+// Calls returns the API calls made by this run.
+func (q *Quota) Calls() int64 {
+	return q.calls.Load()
+}
+
+// Observe counts a response in the run totals (see Counts) and in today's
+// count, writing the count to the store every flushEvery calls. A failed
+// write is kept pending and retried; the first failure is reported on stderr.
+func (q *Quota) Observe(resp *http.Response) {
+	ObserveResponse(resp)
+	q.calls.Add(1)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollover()
+	q.pending++
+	if q.pending < flushEvery {
+		return
+	}
+	if err := q.flush(); err != nil && q.saveErr == nil {
+		q.saveErr = err
+		fmt.Fprintf(os.Stderr, "Warning: API calls for profile %q are not being counted: %v\n", q.Profile, err)
+	}
+}
+
+// rollover starts a new day's count when the day has changed, first adding
+// the pending calls to the day they were made. If that write fails they are
+// counted in the new day rather than lost. The caller holds q.mu.
+func (q *Quota) rollover() {
+	if day := Day(time.Now()); day != q.day {
+		q.flush()
+		q.refresh(day)
+	}
+}
+
+// flush adds the pending calls to the store. The caller holds q.mu.
+func (q *Quota) flush() error {
+	if q.pending == 0 {
+		return nil
+	}
+	var used int64
+	err := updateDailyCalls(func(calls DailyCalls) {
+		if calls[q.Profile] == nil {
+			calls[q.Profile] = map[string]int64{}
+		}
+		calls[q.Profile][q.day] += q.pending
+		used = calls[q.Profile][q.day]
+	})
+	if err != nil {
+		return err
+	}
+	q.used, q.pending = used, 0
+	return nil
+}
+
+// Allow is called before each request. It warns once on stderr when usage
+// passes 80% of the limit and, when enforcing, refuses requests at the limit.
+// Near the limit usage is re-read from the store, so calls by other runs
+// count too.
+func (q *Quota) Allow() error {
+	if q.Limit <= 0 {
+		return nil
+	}
+	q.mu.Lock()
+	q.rollover()
+	if float64(q.used+q.pending) >= warnRatio*float64(q.Limit) {
+		q.refresh(q.day)
+	}
+	used := q.used + q.pending
+	q.mu.Unlock()
+
+	if q.Enforce && used >= q.Limit {
+		return fmt.Errorf("daily API quota reached for profile %q: %d of %d calls (see: asa-cli stats quota)", q.Profile, used, q.Limit)
+	}
+	if float64(used) >= warnRatio*float64(q.Limit) && q.warned.CompareAndSwap(false, true) {
+		fmt.Fprintf(os.Stderr, "Warning: profile %q has used %d of its %d daily API calls\n", q.Profile, used, q.Limit)
+	}
+	return nil
+}
+
+// SaveQuotas writes any calls the quotas could not record when they were
+// made. It returns the first error.
+func SaveQuotas(quotas []*Quota) error {
+	var first error
+	for _, q := range quotas {
+		q.mu.Lock()
+		err := q.flush()
+		q.mu.Unlock()
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// updateDailyCalls applies fn to the quota store under a lock, drops days
+// older than the history window, and writes the store back atomically.
+func updateDailyCalls(fn func(DailyCalls)) error {
+	path := QuotaPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	calls, err := LoadDailyCalls()
+	if err != nil {
+		return err
+	}
+	fn(calls)
+
+	cutoff := Day(time.Now().AddDate(0, 0, -quotaHistory))
+	for _, days := range calls {
+		for day := range days {
+			if day < cutoff {
+				delete(days, day)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(calls, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := config.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("writing quota store: %w", err)
+	}
+	return nil
+}
+
+// Lock timings: how long to wait for another run, and when a lock file left
+// by a crashed run is taken over.
+const (
+	lockWait  = 5 * time.Second
+	lockStale = 30 * time.Second
+)
+
+// lockFile takes an exclusive lock by creating path, waiting for another
+// holder to release it. It returns the function that releases the lock.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("locking quota store: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("locking quota store: %s is held by another run", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
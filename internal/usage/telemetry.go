@@ -0,0 +1,86 @@
+package usage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// Report is the anonymous payload sent to a telemetry endpoint. It carries no
+// profile names, IDs, arguments, or account data.
+type Report struct {
+	InstallID   string `json:"installId"`
+	Command     string `json:"command"`
+	OS          string `json:"os"`
+	Arch        string `json:"arch"`
+	APICalls    int64  `json:"apiCalls"`
+	RateLimited int64  `json:"rateLimited"`
+	Failed      bool   `json:"failed"`
+	DurationMS  int64  `json:"durationMs"`
+}
+
+// NewReport strips an event down to its anonymous counts.
+func NewReport(e Event) Report {
+	return Report{
+		InstallID:   installID(),
+		Command:     e.Command,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		APICalls:    e.APICalls,
+		RateLimited: e.RateLimited,
+		Failed:      e.Failed,
+		DurationMS:  e.DurationMS,
+	}
+}
+
+// DoNotTrack reports whether the DO_NOT_TRACK convention disables telemetry.
+func DoNotTrack() bool {
+	v := os.Getenv("DO_NOT_TRACK")
+	return v != "" && v != "0" && !strings.EqualFold(v, "false")
+}
+
+// Send posts a report to endpoint, giving up quickly so it never holds up
+// the command.
+func Send(endpoint string, r Report) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("sending telemetry: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sending telemetry: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// installID is a random identifier for this installation, created on first
+// use. It lets the endpoint count installs without identifying anyone.
+func installID() string {
+	path := filepath.Join(config.ConfigDir(), "telemetry_id")
+	if data, err := os.ReadFile(path); err == nil && len(bytes.TrimSpace(data)) > 0 {
+		return string(bytes.TrimSpace(data))
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	id := hex.EncodeToString(b)
+	_ = os.MkdirAll(filepath.Dir(path), 0700)
+	_ = os.WriteFile(path, []byte(id+"\n"), 0600)
+	return id
+}
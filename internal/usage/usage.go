@@ -0,0 +1,196 @@
+// Package usage keeps a local log of command invocations and API calls, the
+// data behind `asa-cli stats`, and optionally reports anonymous counts to a
+// telemetry endpoint.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/config"
+)
+
+// maxLogSize is the size at which the log is rotated to usage.jsonl.1.
+const maxLogSize = 5 << 20
+
+// Event is one command invocation.
+type Event struct {
+	Time        time.Time `json:"time"`
+	Command     string    `json:"command"`
+	Profile     string    `json:"profile,omitempty"`
+	APICalls    int64     `json:"apiCalls"`
+	RateLimited int64     `json:"rateLimited,omitempty"`
+	Failed      bool      `json:"failed,omitempty"`
+	DurationMS  int64     `json:"durationMs"`
+	// FailedCalls are the run's failed API calls, with their request IDs.
+	FailedCalls []FailedCall `json:"failedCalls,omitempty"`
+}
+
+// FailedCall is an API call that got an error response.
+type FailedCall struct {
+	Status         int    `json:"status"`
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	RequestID      string `json:"requestId,omitempty"`
+	AppleRequestID string `json:"appleRequestId,omitempty"`
+}
+
+// maxFailedCalls caps the failed calls kept per run.
+const maxFailedCalls = 20
+
+var (
+	apiCalls, rateLimited atomic.Int64
+
+	failedMu    sync.Mutex
+	failedCalls []FailedCall
+)
+
+// ObserveResponse counts an API response and remembers it if it failed. It is
+// safe for concurrent use.
+func ObserveResponse(resp *http.Response) {
+	apiCalls.Add(1)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateLimited.Add(1)
+	}
+	if resp.StatusCode < 400 {
+		return
+	}
+	f := FailedCall{Status: resp.StatusCode}
+	if resp.Request != nil {
+		f.Method, f.Path = resp.Request.Method, resp.Request.URL.Path
+	}
+	f.RequestID, f.AppleRequestID = api.RequestIDs(resp)
+	failedMu.Lock()
+	if len(failedCalls) < maxFailedCalls {
+		failedCalls = append(failedCalls, f)
+	}
+	failedMu.Unlock()
+}
+
+// FailedCalls returns the failed API calls observed so far.
+func FailedCalls() []FailedCall {
+	failedMu.Lock()
+	defer failedMu.Unlock()
+	return append([]FailedCall(nil), failedCalls...)
+}
+
+// Counts returns the API calls and rate-limited responses observed so far.
+func Counts() (calls, limited int64) {
+	return apiCalls.Load(), rateLimited.Load()
+}
+
+// Reset clears the counts and failed calls, so that each of several runs in
+// one process is recorded on its own.
+func Reset() {
+	apiCalls.Store(0)
+	rateLimited.Store(0)
+	failedMu.Lock()
+	failedCalls = nil
+	failedMu.Unlock()
+}
+
+// Path returns the local usage log.
+func Path() string {
+	return filepath.Join(config.ConfigDir(), "usage.jsonl")
+}
+
+// Append adds an event to the local log.
+func Append(e Event) error {
+	path := Path()
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogSize {
+		_ = os.Rename(path, path+".1")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening usage log: %w", err)
+	}
+	defer f.Close()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load reads the events at or after since, including the rotated log.
+// Malformed lines are skipped.
+func Load(since time.Time) ([]Event, error) {
+	var events []Event
+	for _, path := range []string{Path() + ".1", Path()} {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("opening usage log: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e Event
+			if json.Unmarshal(scanner.Bytes(), &e) != nil || e.Time.Before(since) {
+				continue
+			}
+			events = append(events, e)
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading usage log: %w", err)
+		}
+	}
+	return events, nil
+}
+
+// CommandStats totals the events of one command.
+type CommandStats struct {
+	Command     string    `json:"command"`
+	Runs        int       `json:"runs"`
+	Failed      int       `json:"failed"`
+	APICalls    int64     `json:"apiCalls"`
+	RateLimited int64     `json:"rateLimited"`
+	LastRun     time.Time `json:"lastRun"`
+}
+
+// ByCommand totals events per command, most API calls first.
+func ByCommand(events []Event) []CommandStats {
+	index := map[string]int{}
+	var out []CommandStats
+	for _, e := range events {
+		i, ok := index[e.Command]
+		if !ok {
+			i = len(out)
+			index[e.Command] = i
+			out = append(out, CommandStats{Command: e.Command})
+		}
+		s := &out[i]
+		s.Runs++
+		if e.Failed {
+			s.Failed++
+		}
+		s.APICalls += e.APICalls
+		s.RateLimited += e.RateLimited
+		if e.Time.After(s.LastRun) {
+			s.LastRun = e.Time
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		if out[a].APICalls != out[b].APICalls {
+			return out[a].APICalls > out[b].APICalls
+		}
+		return out[a].Runs > out[b].Runs
+	})
+	return out
+}
@@ -0,0 +1,153 @@
+// Package weburl reads the org and entity IDs out of links to the Apple
+// Search Ads web UI, so a link copied from the browser can stand in for an ID.
+package weburl
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Entity kinds, from the campaign down.
+const (
+	KindCampaign = "campaign"
+	KindAdGroup  = "adgroup"
+	KindKeyword  = "keyword"
+	KindAd       = "ad"
+)
+
+// Link is what a web UI link points at. IDs it doesn't mention are 0.
+type Link struct {
+	OrgID      int64
+	CampaignID int64
+	AdGroupID  int64
+	KeywordID  int64
+	AdID       int64
+}
+
+// segments maps the path segments and query parameters the web UI puts
+// before an ID to the kind of the ID.
+var segments = map[string]string{
+	"campaign":   KindCampaign,
+	"campaigns":  KindCampaign,
+	"campaignid": KindCampaign,
+	"adgroup":    KindAdGroup,
+	"adgroups":   KindAdGroup,
+	"ad-group":   KindAdGroup,
+	"ad-groups":  KindAdGroup,
+	"adgroupid":  KindAdGroup,
+	"keyword":    KindKeyword,
+	"keywords":   KindKeyword,
+	"keywordid":  KindKeyword,
+	"ad":         KindAd,
+	"ads":        KindAd,
+	"adid":       KindAd,
+}
+
+// IsLink reports whether s looks like a link rather than an ID.
+func IsLink(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// Parse reads a web UI link such as
+// https://app.searchads.apple.com/cm/app/<org>/campaign/<id>/adgroup/<id>.
+// IDs are taken from the path, the fragment (for #/ routes), and query
+// parameters such as campaignId.
+func Parse(s string) (Link, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return Link{}, fmt.Errorf("invalid link: %s", s)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host != "apple.com" && !strings.HasSuffix(host, ".apple.com") {
+		return Link{}, fmt.Errorf("not a Search Ads link: %s", s)
+	}
+
+	var l Link
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	query := u.Query()
+	if frag := strings.TrimLeft(u.Fragment, "#!"); strings.HasPrefix(frag, "/") {
+		frag, rawQuery, _ := strings.Cut(frag, "?")
+		parts = append(parts, strings.Split(strings.Trim(frag, "/"), "/")...)
+		if q, err := url.ParseQuery(rawQuery); err == nil {
+			for name, values := range q {
+				query[name] = values
+			}
+		}
+	}
+	for i := 0; i+1 < len(parts); i++ {
+		id, err := strconv.ParseInt(parts[i+1], 10, 64)
+		if err != nil || id <= 0 {
+			continue
+		}
+		name := strings.ToLower(parts[i])
+		switch {
+		// The org comes right after /cm/app/ (Campaign Management) or /org/.
+		case name == "app" && i > 0 && strings.EqualFold(parts[i-1], "cm"), name == "org", name == "orgs":
+			l.OrgID = id
+		default:
+			l.set(segments[name], id)
+		}
+	}
+	for name, values := range query {
+		id, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil || id <= 0 {
+			continue
+		}
+		if name = strings.ToLower(name); name == "orgid" {
+			l.OrgID = id
+			continue
+		}
+		l.set(segments[name], id)
+	}
+
+	if l.Kind() == "" {
+		return Link{}, fmt.Errorf("no campaign, ad group, keyword, or ad ID in link: %s", s)
+	}
+	return l, nil
+}
+
+func (l *Link) set(kind string, id int64) {
+	switch kind {
+	case KindCampaign:
+		l.CampaignID = id
+	case KindAdGroup:
+		l.AdGroupID = id
+	case KindKeyword:
+		l.KeywordID = id
+	case KindAd:
+		l.AdID = id
+	}
+}
+
+// ID returns the ID of the given kind, or 0 if the link has none.
+func (l Link) ID(kind string) int64 {
+	switch kind {
+	case KindCampaign:
+		return l.CampaignID
+	case KindAdGroup:
+		return l.AdGroupID
+	case KindKeyword:
+		return l.KeywordID
+	case KindAd:
+		return l.AdID
+	}
+	return 0
+}
+
+// Kind returns the kind of the most specific entity the link points at, or
+// "" if it points at none.
+func (l Link) Kind() string {
+	switch {
+	case l.KeywordID != 0:
+		return KindKeyword
+	case l.AdID != 0:
+		return KindAd
+	case l.AdGroupID != 0:
+		return KindAdGroup
+	case l.CampaignID != 0:
+		return KindCampaign
+	}
+	return ""
+}
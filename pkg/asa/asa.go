@@ -0,0 +1,261 @@
+// Package asa is a Go client for the Apple Search Ads Campaign Management API v5.
+//
+// It exposes the same API client, authentication, models and services used by
+// the asa-cli binary, so Go programs can embed them directly:
+//
+//	client, err := asa.New(asa.Config{
+//		ClientID:       "SEARCHADS.xxxx",
+//		TeamID:         "SEARCHADS.xxxx",
+//		KeyID:          "xxxx",
+//		PrivateKeyPath: "/path/to/private-key.pem",
+//		OrgID:          "123456",
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	campaigns, _, err := client.ListCampaigns(ctx, 20, 0)
+//
+// All methods take a context.Context which bounds the underlying HTTP request.
+// Errors returned for API failures can be inspected with AsAPIError, IsNotFound,
+// IsUnauthorized and IsRateLimited.
+package asa
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/config"
+	"github.com/trebuhs/asa-cli/internal/services"
+)
+
+// Config holds the credentials and settings for a Client.
+type Config struct {
+	ClientID       string
+	TeamID         string
+	KeyID          string
+	PrivateKeyPath string
+
+	// OrgID is sent as the X-AP-Context header. It may be empty for ACLs().
+	OrgID string
+
+	// Timeout for each HTTP request. Defaults to 30 seconds.
+	Timeout time.Duration
+
+	// Transport is the base round tripper. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// ReadOnly makes the client refuse every request that could change the
+	// account. Searches and reports still work.
+	ReadOnly bool
+
+	// APIVersion is "v5" (the default) or "v4" for orgs still constrained to
+	// older endpoints. On v4, calls to v5-only endpoints such as ads fail
+	// without a request being sent.
+	APIVersion string
+}
+
+// Client is an authenticated Apple Search Ads API client.
+type Client struct {
+	api *api.Client
+}
+
+// New creates a Client. Access tokens are cached on disk under ~/.asa-cli
+// and shared with the asa-cli binary.
+func New(cfg Config) (*Client, error) {
+	c := &config.Config{
+		ClientID:       cfg.ClientID,
+		TeamID:         cfg.TeamID,
+		KeyID:          cfg.KeyID,
+		OrgID:          cfg.OrgID,
+		PrivateKeyPath: cfg.PrivateKeyPath,
+	}
+	if err := auth.ValidateConfig(c); err != nil {
+		return nil, err
+	}
+	version, err := api.ParseVersion(cfg.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	httpClient := &http.Client{
+		Transport: &auth.Transport{
+			Base:     cfg.Transport,
+			Token:    auth.NewTokenProvider(c),
+			OrgID:    cfg.OrgID,
+			ReadOnly: cfg.ReadOnly,
+		},
+		Timeout: timeout,
+	}
+	client := api.NewClient(httpClient)
+	client.Version = version
+	return &Client{api: client}, nil
+}
+
+func (c *Client) with(ctx context.Context) *api.Client {
+	return c.api.WithContext(ctx)
+}
+
+// ACLs returns the organizations and roles available to the API user.
+func (c *Client) ACLs(ctx context.Context) ([]UserACL, error) {
+	return services.NewACLService(c.with(ctx)).GetACLs()
+}
+
+// --- Campaigns ---
+
+func (c *Client) ListCampaigns(ctx context.Context, limit, offset int) ([]Campaign, *PageDetail, error) {
+	return services.NewCampaignService(c.with(ctx)).List(limit, offset)
+}
+
+func (c *Client) GetCampaign(ctx context.Context, id int64) (*Campaign, error) {
+	return services.NewCampaignService(c.with(ctx)).Get(id)
+}
+
+func (c *Client) FindCampaigns(ctx context.Context, selector Selector) ([]Campaign, *PageDetail, error) {
+	return services.NewCampaignService(c.with(ctx)).Find(selector)
+}
+
+// FindAllCampaigns follows pagination and returns every matching campaign.
+func (c *Client) FindAllCampaigns(ctx context.Context, selector Selector) ([]Campaign, error) {
+	return services.NewCampaignService(c.with(ctx)).FindAll(selector)
+}
+
+func (c *Client) CreateCampaign(ctx context.Context, campaign *Campaign) (*Campaign, error) {
+	return services.NewCampaignService(c.with(ctx)).Create(campaign)
+}
+
+func (c *Client) UpdateCampaign(ctx context.Context, id int64, update *CampaignUpdate) (*Campaign, error) {
+	return services.NewCampaignService(c.with(ctx)).Update(id, update)
+}
+
+func (c *Client) DeleteCampaign(ctx context.Context, id int64) error {
+	return services.NewCampaignService(c.with(ctx)).Delete(id)
+}
+
+// --- Ad Groups ---
+
+func (c *Client) ListAdGroups(ctx context.Context, campaignID int64, limit, offset int) ([]AdGroup, *PageDetail, error) {
+	return services.NewAdGroupService(c.with(ctx)).List(campaignID, limit, offset)
+}
+
+func (c *Client) GetAdGroup(ctx context.Context, campaignID, adGroupID int64) (*AdGroup, error) {
+	return services.NewAdGroupService(c.with(ctx)).Get(campaignID, adGroupID)
+}
+
+func (c *Client) FindAdGroups(ctx context.Context, campaignID int64, selector Selector) ([]AdGroup, *PageDetail, error) {
+	return services.NewAdGroupService(c.with(ctx)).Find(campaignID, selector)
+}
+
+// FindAllAdGroups follows pagination and returns every matching ad group.
+func (c *Client) FindAllAdGroups(ctx context.Context, campaignID int64, selector Selector) ([]AdGroup, error) {
+	return services.NewAdGroupService(c.with(ctx)).FindAll(campaignID, selector)
+}
+
+func (c *Client) CreateAdGroup(ctx context.Context, campaignID int64, adgroup *AdGroup) (*AdGroup, error) {
+	return services.NewAdGroupService(c.with(ctx)).Create(campaignID, adgroup)
+}
+
+func (c *Client) UpdateAdGroup(ctx context.Context, campaignID, adGroupID int64, update *AdGroupUpdate) (*AdGroup, error) {
+	return services.NewAdGroupService(c.with(ctx)).Update(campaignID, adGroupID, update)
+}
+
+func (c *Client) DeleteAdGroup(ctx context.Context, campaignID, adGroupID int64) error {
+	return services.NewAdGroupService(c.with(ctx)).Delete(campaignID, adGroupID)
+}
+
+// --- Targeting Keywords ---
+
+func (c *Client) ListKeywords(ctx context.Context, campaignID, adGroupID int64, limit, offset int) ([]Keyword, *PageDetail, error) {
+	return services.NewKeywordService(c.with(ctx)).List(campaignID, adGroupID, limit, offset)
+}
+
+func (c *Client) GetKeyword(ctx context.Context, campaignID, adGroupID, keywordID int64) (*Keyword, error) {
+	return services.NewKeywordService(c.with(ctx)).Get(campaignID, adGroupID, keywordID)
+}
+
+func (c *Client) FindKeywords(ctx context.Context, campaignID, adGroupID int64, selector Selector) ([]Keyword, *PageDetail, error) {
+	return services.NewKeywordService(c.with(ctx)).Find(campaignID, adGroupID, selector)
+}
+
+// FindAllKeywords follows pagination and returns every matching keyword.
+func (c *Client) FindAllKeywords(ctx context.Context, campaignID, adGroupID int64, selector Selector) ([]Keyword, error) {
+	return services.NewKeywordService(c.with(ctx)).FindAll(campaignID, adGroupID, selector)
+}
+
+func (c *Client) CreateKeywords(ctx context.Context, campaignID, adGroupID int64, keywords []Keyword) ([]Keyword, error) {
+	return services.NewKeywordService(c.with(ctx)).Create(campaignID, adGroupID, keywords)
+}
+
+func (c *Client) UpdateKeywords(ctx context.Context, campaignID, adGroupID int64, updates []KeywordUpdate) ([]Keyword, error) {
+	return services.NewKeywordService(c.with(ctx)).Update(campaignID, adGroupID, updates)
+}
+
+func (c *Client) DeleteKeywords(ctx context.Context, campaignID, adGroupID int64, keywordIDs []int64) error {
+	return services.NewKeywordService(c.with(ctx)).Delete(campaignID, adGroupID, keywordIDs)
+}
+
+// --- Negative Keywords ---
+
+func (c *Client) ListCampaignNegativeKeywords(ctx context.Context, campaignID int64, limit, offset int) ([]NegativeKeyword, *PageDetail, error) {
+	return services.NewKeywordService(c.with(ctx)).ListCampaignNegativeKeywords(campaignID, limit, offset)
+}
+
+func (c *Client) CreateCampaignNegativeKeywords(ctx context.Context, campaignID int64, keywords []NegativeKeyword) ([]NegativeKeyword, error) {
+	return services.NewKeywordService(c.with(ctx)).CreateCampaignNegativeKeywords(campaignID, keywords)
+}
+
+func (c *Client) DeleteCampaignNegativeKeywords(ctx context.Context, campaignID int64, keywordIDs []int64) error {
+	return services.NewKeywordService(c.with(ctx)).DeleteCampaignNegativeKeywords(campaignID, keywordIDs)
+}
+
+func (c *Client) ListAdGroupNegativeKeywords(ctx context.Context, campaignID, adGroupID int64, limit, offset int) ([]NegativeKeyword, *PageDetail, error) {
+	return services.NewKeywordService(c.with(ctx)).ListAdGroupNegativeKeywords(campaignID, adGroupID, limit, offset)
+}
+
+func (c *Client) CreateAdGroupNegativeKeywords(ctx context.Context, campaignID, adGroupID int64, keywords []NegativeKeyword) ([]NegativeKeyword, error) {
+	return services.NewKeywordService(c.with(ctx)).CreateAdGroupNegativeKeywords(campaignID, adGroupID, keywords)
+}
+
+func (c *Client) DeleteAdGroupNegativeKeywords(ctx context.Context, campaignID, adGroupID int64, keywordIDs []int64) error {
+	return services.NewKeywordService(c.with(ctx)).DeleteAdGroupNegativeKeywords(campaignID, adGroupID, keywordIDs)
+}
+
+// --- Reports ---
+
+func (c *Client) CampaignReport(ctx context.Context, req *ReportRequest) (*ReportingDataResponse, error) {
+	return services.NewReportingService(c.with(ctx)).GetCampaignReport(req)
+}
+
+func (c *Client) AdGroupReport(ctx context.Context, campaignID int64, req *ReportRequest) (*ReportingDataResponse, error) {
+	return services.NewReportingService(c.with(ctx)).GetAdGroupReport(campaignID, req)
+}
+
+func (c *Client) KeywordReport(ctx context.Context, campaignID int64, req *ReportRequest) (*ReportingDataResponse, error) {
+	return services.NewReportingService(c.with(ctx)).GetKeywordReport(campaignID, req)
+}
+
+func (c *Client) SearchTermReport(ctx context.Context, campaignID int64, req *ReportRequest) (*ReportingDataResponse, error) {
+	return services.NewReportingService(c.with(ctx)).GetSearchTermReport(campaignID, req)
+}
+
+// AdGroupSearchTermReport reports the search terms of a single ad group.
+func (c *Client) AdGroupSearchTermReport(ctx context.Context, campaignID, adGroupID int64, req *ReportRequest) (*ReportingDataResponse, error) {
+	return services.NewReportingService(c.with(ctx)).GetAdGroupSearchTermReport(campaignID, adGroupID, req)
+}
+
+// --- Search ---
+
+func (c *Client) SearchApps(ctx context.Context, query string, limit, offset int, ownedOnly bool) ([]AppInfo, *PageDetail, error) {
+	return services.NewAppService(c.with(ctx)).Search(query, limit, offset, ownedOnly)
+}
+
+func (c *Client) SearchGeo(ctx context.Context, query string, limit, offset int, entity, countryCode string) ([]GeoEntity, *PageDetail, error) {
+	return services.NewAppService(c.with(ctx)).SearchGeo(query, limit, offset, entity, countryCode)
+}
@@ -0,0 +1,133 @@
+package asa
+
+import (
+	"errors"
+
+	"github.com/trebuhs/asa-cli/internal/api"
+	"github.com/trebuhs/asa-cli/internal/auth"
+	"github.com/trebuhs/asa-cli/internal/models"
+)
+
+// Entity and request types.
+type (
+	Campaign              = models.Campaign
+	CampaignUpdate        = models.CampaignUpdate
+	AdGroup               = models.AdGroup
+	AdGroupUpdate         = models.AdGroupUpdate
+	TargetingDimensions   = models.TargetingDimensions
+	TargetingDimension    = models.TargetingDimension
+	Keyword               = models.Keyword
+	KeywordUpdate         = models.KeywordUpdate
+	NegativeKeyword       = models.NegativeKeyword
+	Money                 = models.Money
+	PageDetail            = models.PageDetail
+	Selector              = models.Selector
+	Condition             = models.Condition
+	OrderByItem           = models.OrderByItem
+	SelectorPagination    = models.SelectorPagination
+	ReportRequest         = models.ReportRequest
+	ReportingDataResponse = models.ReportingDataResponse
+	ReportRow             = models.ReportRow
+	SpendRow              = models.SpendRow
+	GranularityRow        = models.GranularityRow
+	UserACL               = models.UserACL
+	AppInfo               = models.AppInfo
+	GeoEntity             = models.GeoEntity
+	Ad                    = models.Ad
+	AdUpdate              = models.AdUpdate
+	Creative              = models.Creative
+)
+
+// Enum types. The Parse functions reject values the API doesn't accept;
+// decoding JSON keeps values added to the API since, as they are.
+type (
+	Status        = models.Status
+	KeywordStatus = models.KeywordStatus
+	ServingStatus = models.ServingStatus
+	MatchType     = models.MatchType
+	Granularity   = models.Granularity
+	SupplySource  = models.SupplySource
+	AdChannelType = models.AdChannelType
+	BillingEvent  = models.BillingEvent
+	PricingModel  = models.PricingModel
+	PaymentModel  = models.PaymentModel
+)
+
+// Enum values.
+const (
+	StatusEnabled       = models.StatusEnabled
+	StatusPaused        = models.StatusPaused
+	KeywordActive       = models.KeywordActive
+	KeywordPaused       = models.KeywordPaused
+	ServingRunning      = models.ServingRunning
+	ServingNotRunning   = models.ServingNotRunning
+	MatchBroad          = models.MatchBroad
+	MatchExact          = models.MatchExact
+	GranularityHourly   = models.GranularityHourly
+	GranularityDaily    = models.GranularityDaily
+	GranularityWeekly   = models.GranularityWeekly
+	GranularityMonthly  = models.GranularityMonthly
+	SupplySearchResults = models.SupplySearchResults
+	SupplySearchTab     = models.SupplySearchTab
+	SupplyTodayTab      = models.SupplyTodayTab
+	SupplyProductPages  = models.SupplyProductPages
+	ChannelSearch       = models.ChannelSearch
+	ChannelDisplay      = models.ChannelDisplay
+	BillingTaps         = models.BillingTaps
+	BillingImpressions  = models.BillingImpressions
+	PricingCPC          = models.PricingCPC
+	PricingCPM          = models.PricingCPM
+	PaymentPAYG         = models.PaymentPAYG
+	PaymentLOC          = models.PaymentLOC
+)
+
+// Enum parsers, accepting any case.
+var (
+	ParseStatus        = models.ParseStatus
+	ParseKeywordStatus = models.ParseKeywordStatus
+	ParseServingStatus = models.ParseServingStatus
+	ParseMatchType     = models.ParseMatchType
+	ParseGranularity   = models.ParseGranularity
+	ParseSupplySource  = models.ParseSupplySource
+	ParseAdChannelType = models.ParseAdChannelType
+	ParseBillingEvent  = models.ParseBillingEvent
+	ParsePricingModel  = models.ParsePricingModel
+	ParsePaymentModel  = models.ParsePaymentModel
+)
+
+// NewSelector creates a Selector with default pagination.
+func NewSelector(limit, offset int) Selector {
+	return models.NewSelector(limit, offset)
+}
+
+// APIError is returned for non-2xx API responses.
+type APIError = api.Error
+
+// TokenError is returned when Apple rejects the OAuth token exchange.
+type TokenError = auth.TokenError
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// IsNotFound reports whether err is an API 404.
+func IsNotFound(err error) bool { return api.IsNotFound(err) }
+
+// IsUnauthorized reports whether err is an API 401 or 403.
+func IsUnauthorized(err error) bool { return api.IsUnauthorized(err) }
+
+// IsRateLimited reports whether err is an API 429.
+func IsRateLimited(err error) bool { return api.IsRateLimited(err) }
+
+// IsReadOnly reports whether err is a request refused because of
+// Config.ReadOnly.
+func IsReadOnly(err error) bool { return errors.Is(err, auth.ErrReadOnly) }
+
+// VersionError is returned for an endpoint Config.APIVersion doesn't have.
+type VersionError = api.VersionError
+
+// IsVersionError reports whether err is a VersionError.
+func IsVersionError(err error) bool { return api.IsVersionError(err) }